@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/plugin"
@@ -29,6 +30,10 @@ type (
 	wsServer interface {
 		MountRoutes(chi.Router)
 		Send(kind string, payload interface{}, userIDs ...uint64) error
+		Publish(kind string, prepare func(auth.Identifiable) interface{}, channels ...string) error
+		Broadcast(ctx context.Context, kind string, payload interface{}) error
+		HandleBroadcast(kind string, fn func(ctx context.Context, payload []byte) error)
+		Watch(ctx context.Context) error
 	}
 
 	authServicer interface {
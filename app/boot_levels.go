@@ -19,10 +19,12 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/apigw"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
 	"github.com/cortezaproject/corteza-server/pkg/corredor"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/healthcheck"
 	"github.com/cortezaproject/corteza-server/pkg/http"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/mail"
@@ -95,6 +97,7 @@ func (app *CortezaApp) Setup() (err error) {
 	hcd.Add(scheduler.Healthcheck, "Scheduler")
 	hcd.Add(mail.Healthcheck, "Mail")
 	hcd.Add(corredor.Healthcheck, "Corredor")
+	hcd.Add(cluster.Healthcheck, "Cluster")
 
 	if err = sentry.Init(app.Opt.Sentry); err != nil {
 		return fmt.Errorf("could not initialize Sentry: %w", err)
@@ -321,7 +324,29 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 		return
 	}
 
-	app.WsServer = websocket.Server(app.Log, app.Opt.Websocket)
+	{
+		ws := websocket.Server(app.Log, app.Opt.Websocket)
+		if err = ws.Init(ctx, app.Store); err != nil {
+			return fmt.Errorf("failed to initialize websocket server: %w", err)
+		}
+
+		app.WsServer = ws
+	}
+
+	// Coordinates scheduled jobs between multiple nodes
+	cluster.Setup(app.Log, app.Store, app.Opt.Cluster)
+
+	// Broadcasts changes of cached data (RBAC rules, settings...) between nodes
+	{
+		opt := app.Opt.Invalidation
+		if opt.RedisURL == "" {
+			opt.RedisURL = app.Opt.Messagebus.RedisURL
+		}
+
+		if err = invalidation.Setup(ctx, app.Log, app.Store, cluster.Service().NodeID(), opt); err != nil {
+			return
+		}
+	}
 
 	ctx = actionlog.RequestOriginToContext(ctx, actionlog.RequestOrigin_APP_Init)
 	defer sentry.Recover()
@@ -362,6 +387,8 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 		Auth:      app.Opt.Auth,
 		RBAC:      app.Opt.RBAC,
 		Limit:     app.Opt.Limit,
+		Webhook:   app.Opt.Webhook,
+		Apigw:     app.Opt.Apigw,
 	})
 
 	if err != nil {
@@ -381,6 +408,7 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 		ActionLog: app.Opt.ActionLog,
 		Workflow:  app.Opt.Workflow,
 		Corredor:  app.Opt.Corredor,
+		Limit:     app.Opt.Limit,
 	})
 
 	if err != nil {
@@ -391,9 +419,10 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 	//
 	// Note: this is a legacy approach, all services from all 3 apps
 	// will most likely be merged in the future
-	err = cmpService.Initialize(ctx, app.Log, app.Store, cmpService.Config{
+	err = cmpService.Initialize(ctx, app.Log, app.Store, app.WsServer, cmpService.Config{
 		ActionLog: app.Opt.ActionLog,
 		Storage:   app.Opt.ObjStore,
+		Limit:     app.Opt.Limit,
 	})
 
 	if err != nil {
@@ -406,7 +435,7 @@ func (app *CortezaApp) InitServices(ctx context.Context) (err error) {
 	corredor.Service().SetRoleFinder(sysService.DefaultRole)
 
 	// Initialize API GW bits
-	apigw.Setup(options.Apigw(), app.Log, app.Store)
+	apigw.Setup(&app.Opt.Apigw, app.Log, app.Store)
 	if err = apigw.Service().Reload(ctx); err != nil {
 		return err
 	}
@@ -454,6 +483,20 @@ func (app *CortezaApp) Activate(ctx context.Context) (err error) {
 	ctx = actionlog.RequestOriginToContext(ctx, actionlog.RequestOrigin_APP_Activate)
 	defer sentry.Recover()
 
+	// Acquire node & leader leases before any of the scheduled jobs are started
+	cluster.Service().Watch(ctx)
+
+	if svc := invalidation.Service(); svc != nil {
+		if err = svc.Watch(ctx); err != nil {
+			return fmt.Errorf("failed to start cache invalidation watcher: %w", err)
+		}
+	}
+
+	// Receive messages for websocket subscribers from other nodes
+	if err = app.WsServer.Watch(ctx); err != nil {
+		return fmt.Errorf("failed to start websocket bus watcher: %w", err)
+	}
+
 	// Start scheduler
 	if app.Opt.Eventbus.SchedulerEnabled {
 		scheduler.Service().Start(ctx)
@@ -589,6 +632,11 @@ func updateAuthSettings(svc authServicer, current *types.AppSettings) {
 				Enabled:  current.Auth.MultiFactor.EmailOTP.Enabled,
 				Enforced: current.Auth.MultiFactor.EmailOTP.Enforced,
 			},
+			WebAuthn: authSettings.WebAuthn{
+				Enabled:      current.Auth.MultiFactor.WebAuthn.Enabled,
+				Enforced:     current.Auth.MultiFactor.WebAuthn.Enforced,
+				Passwordless: current.Auth.MultiFactor.WebAuthn.Passwordless,
+			},
 		},
 	}
 
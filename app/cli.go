@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	authCommands "github.com/cortezaproject/corteza-server/auth/commands"
+	composeCommands "github.com/cortezaproject/corteza-server/compose/commands"
 	federationCommands "github.com/cortezaproject/corteza-server/federation/commands"
 	"github.com/cortezaproject/corteza-server/pkg/cli"
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -99,12 +100,14 @@ func (app *CortezaApp) InitCLI() {
 		systemCommands.RBAC(ctx, storeInit),
 		systemCommands.Sink(ctx, app),
 		systemCommands.Settings(ctx, app),
+		systemCommands.Objstore(ctx, app),
 		systemCommands.Import(ctx, storeInit),
 		systemCommands.Export(ctx, storeInit),
 		serveCmd,
 		upgradeCmd,
 		provisionCmd,
 		authCommands.Command(ctx, app, storeInit),
+		composeCommands.Rollups(ctx, app),
 		federationCommands.Sync(ctx, app),
 		cli.EnvCommand(),
 		cli.VersionCommand(),
@@ -114,6 +114,11 @@ func (app *CortezaApp) mountHttpRoutes(r chi.Router) {
 			r.Handle("/docs*", http.StripPrefix(fullpathDocs, http.FileServer(docs.GetFS())))
 
 			var fullpathGateway = options.CleanBase(ho.BaseUrl, ho.ApiBaseUrl, "gateway")
+
+			// API Gateway handles preflight requests and authentication
+			// on its own so that routes can have their own CORS rules
+			// and accept tokens from other issuers
+			server.Passthrough(fullpathGateway)
 			r.Handle("/gateway*", http.StripPrefix(fullpathGateway, app.ApigwService))
 		})
 	}()
@@ -157,6 +162,7 @@ func (app *CortezaApp) mountHttpRoutes(r chi.Router) {
 			scim.Routes(r, scim.Config{
 				ExternalIdAsPrimary: app.Opt.SCIM.ExternalIdAsPrimary,
 				ExternalIdValidator: extIdValidation,
+				BaseURL:             path.Join(app.Opt.HTTPServer.BaseUrl, baseUrl),
 			})
 		})
 	}()
@@ -11,4 +11,96 @@ $(function () {
   })
 
   $('input.mfa-code-mask').mask('000 000')
+
+  // WebAuthn (security keys & passkeys)
+  //
+  // Options are prepared by the server and embedded in the form,
+  // response of the authenticator is posted back as JSON
+  $('form.webauthn-register, form.webauthn-verify, form.webauthn-login').each(function() {
+    let form = this
+    let $options = $('script.webauthn-options', form)
+
+    if (!window.PublicKeyCredential) {
+      $('.webauthn-unsupported', form).removeClass('d-none')
+      $('button[type=submit]', form).attr('disabled', true)
+      return
+    }
+
+    if ($options.length === 0) {
+      return
+    }
+
+    $(form).on('submit', function(e) {
+      e.preventDefault()
+
+      let register = $(form).hasClass('webauthn-register')
+      let options = JSON.parse($options.text()).publicKey
+
+      options.challenge = fromBase64URL(options.challenge)
+      if (options.user) {
+        options.user.id = fromBase64URL(options.user.id)
+      }
+
+      $.each(options.excludeCredentials || options.allowCredentials || [], function(_, c) {
+        c.id = fromBase64URL(c.id)
+      })
+
+      let ceremony = register
+        ? navigator.credentials.create({ publicKey: options })
+        : navigator.credentials.get({ publicKey: options })
+
+      ceremony
+        .then(function(cred) {
+          let body = {
+            id: cred.id,
+            rawId: toBase64URL(cred.rawId),
+            type: cred.type,
+            response: {
+              clientDataJSON: toBase64URL(cred.response.clientDataJSON),
+            },
+          }
+
+          if (register) {
+            body.response.attestationObject = toBase64URL(cred.response.attestationObject)
+            body = { label: $('input[name=label]', form).val(), credential: body }
+          } else {
+            body.response.authenticatorData = toBase64URL(cred.response.authenticatorData)
+            body.response.signature = toBase64URL(cred.response.signature)
+            if (cred.response.userHandle) {
+              body.response.userHandle = toBase64URL(cred.response.userHandle)
+            }
+          }
+
+          return fetch(form.action, {
+            method: 'POST',
+            credentials: 'same-origin',
+            headers: {
+              'Content-Type': 'application/json',
+              'X-CSRF-Token': $('input[type=hidden]', form).first().val() || '',
+            },
+            body: JSON.stringify(body),
+          })
+        })
+        .then(function(rsp) {
+          // server redirects to the next step (or back with an error)
+          window.location = rsp.redirected ? rsp.url : window.location.href
+        })
+        .catch(function() {
+          // ceremony was canceled or timed out
+          setTimeout(function() {
+            $('button, input[type=submit]', form).attr('disabled', false)
+          }, 100)
+        })
+    })
+  })
+
+  function fromBase64URL(s) {
+    s = s.replace(/-/g, '+').replace(/_/g, '/')
+    return Uint8Array.from(atob(s), function(c) { return c.charCodeAt(0) })
+  }
+
+  function toBase64URL(buf) {
+    let s = String.fromCharCode.apply(null, new Uint8Array(buf))
+    return btoa(s).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '')
+  }
 })
@@ -88,6 +88,29 @@
 	</div>
 	{{ end }}
 
+	{{ if .webAuthnOptions }}
+	<form
+		method="POST"
+		action="{{ links.LoginPasskey }}"
+		class="px-3 pb-3 webauthn-login"
+	>
+		{{ .csrfField }}
+		<div class="alert alert-danger webauthn-unsupported d-none" role="alert">
+			{{ tr "login.template.passkey.unsupported" }}
+		</div>
+
+		<script type="application/json" class="webauthn-options">{{ .webAuthnOptions }}</script>
+
+		<button
+			class="btn btn-light btn-block btn-lg text-dark"
+			type="submit"
+		>
+			<i class="bi bi-key mr-1"></i>
+			{{ tr "login.template.passkey.button" }}
+		</button>
+	</form>
+	{{ end }}
+
 	{{ if .settings.ExternalEnabled }}
 	<div class="pb-3">
 	{{ range .providers }}
@@ -0,0 +1,63 @@
+{{ template "inc_header.html.tpl"  set . "hideNav" true }}
+<div class="card-body p-0">
+	<h4 class="card-title p-3 border-bottom">{{ tr "mfa-webauthn.template.title" }}</h4>
+
+	{{ if .enforced }}
+	<p class="p-3 text-danger mb-0 font-weight-bold">
+		{{ tr "mfa-webauthn.template.enforced" }}
+	</p>
+	{{ end }}
+
+	<form
+		class="p-3 webauthn-register"
+		method="POST"
+		action="{{ links.MfaWebAuthnNewCredentials }}"
+	>
+		<p class="text-justify">
+			{{ tr "mfa-webauthn.template.instructions" }}
+		</p>
+
+		{{ if .form.error }}
+		<div class="alert alert-danger" role="alert">
+			{{ .form.error }}
+		</div>
+		{{ end }}
+
+		<div class="alert alert-danger webauthn-unsupported d-none" role="alert">
+			{{ tr "mfa-webauthn.template.unsupported" }}
+		</div>
+
+		{{ .csrfField }}
+		<div class="mb-3">
+			<label>
+				{{ tr "mfa-webauthn.template.form.label.label" }}
+			</label>
+			<input
+				type="text"
+				class="form-control"
+				name="label"
+				maxlength="64"
+				placeholder="{{ tr "mfa-webauthn.template.form.label.placeholder" }}"
+				autocomplete="off"
+				aria-label="{{ tr "mfa-webauthn.template.form.label.label" }}">
+		</div>
+
+		{{ if .webAuthnOptions }}
+		<script type="application/json" class="webauthn-options">{{ .webAuthnOptions }}</script>
+
+		<button
+			class="btn btn-primary btn-block btn-lg"
+			type="submit"
+		>
+			{{ tr "mfa-webauthn.template.form.button" }}
+		</button>
+		{{ end }}
+
+		{{ if not .enforced }}
+		<a href="{{ links.Security }}" class="btn btn-light btn-block text-dark">
+			{{ tr "mfa-webauthn.template.form.cancel" }}
+		</a>
+		{{ end }}
+	</form>
+</div>
+{{ template "inc_footer.html.tpl" . }}
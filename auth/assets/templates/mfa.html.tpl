@@ -102,5 +102,42 @@
 			<i class="bi bi-check-circle text-success h5 mr-1"></i> {{ tr "mfa.template.totp.confirmed" }}
 		</p>
 	{{ end }}
+
+	{{ if .webAuthnPending }}
+	<form
+		class="p-3 webauthn-verify"
+		method="POST"
+		action="{{ links.MfaWebAuthn }}"
+	>
+		<h5>{{ tr "mfa.template.webauthn.instructions" }}</h5>
+
+		{{ if .webAuthnError }}
+		<div class="alert alert-danger" role="alert">
+			{{ .webAuthnError }}
+		</div>
+		{{ end }}
+
+		<div class="alert alert-danger webauthn-unsupported d-none" role="alert">
+			{{ tr "mfa.template.webauthn.unsupported" }}
+		</div>
+
+		{{ .csrfField }}
+
+		{{ if .webAuthnOptions }}
+		<script type="application/json" class="webauthn-options">{{ .webAuthnOptions }}</script>
+
+		<button
+			class="btn btn-primary btn-block btn-lg"
+			type="submit"
+		>
+			{{ tr "mfa.template.webauthn.verify" }}
+		</button>
+		{{ end }}
+	</form>
+	{{ else if not .webAuthnDisabled }}
+		<p class="px-3 pt-3 pb-2 mb-0">
+			<i class="bi bi-check-circle text-success h5 mr-1"></i> {{ tr "mfa.template.webauthn.confirmed" }}
+		</p>
+	{{ end }}
 </div>
 {{ template "inc_footer.html.tpl" . }}
@@ -17,7 +17,7 @@
 	<div>
 		{{ .csrfField }}
 		<h5>{{ tr "security.template.mfa.title" }}</h5>
-		{{ if or .settings.MultiFactor.TOTP.Enabled .settings.MultiFactor.EmailOTP.Enabled }}
+		{{ if or .settings.MultiFactor.TOTP.Enabled .settings.MultiFactor.EmailOTP.Enabled .settings.MultiFactor.WebAuthn.Enabled }}
 			{{ if .settings.MultiFactor.TOTP.Enabled }}
 			<div class="py-4">
 				<h6>{{ tr "security.template.mfa.totp.title" }}</h6>
@@ -76,6 +76,55 @@
 				</div>
 			</div>
 			{{ end }}
+
+			{{ if .settings.MultiFactor.WebAuthn.Enabled }}
+			<div class="pt-4 pb-1">
+				<h6>{{ tr "security.template.mfa.webauthn.title" }}</h6>
+				<div class="row">
+					<div class="col-10 pt-2">
+					{{ if .webAuthnEnforced }}
+						<i class="bi bi-check-circle text-success h5 mr-1"></i>
+						{{ tr "security.template.mfa.webauthn.enforced" }}
+					{{ else }}
+						<i class="bi bi-exclamation-circle-fill text-danger h5 mr-1"></i>
+						{{ tr "security.template.mfa.webauthn.disabled" }}
+					{{ end }}
+					</div>
+					<div class="col-md-2 col-sm-12">
+						<button name="action" value="configureWebAuthn" class="btn btn-primary float-right">{{ tr "security.template.mfa.webauthn.add" }}</button>
+					</div>
+				</div>
+
+				{{ if .webAuthnCredentials }}
+				<ul class="list-group mt-3">
+				{{ range .webAuthnCredentials }}
+					<li class="list-group-item d-flex align-items-center">
+						<div class="flex-grow-1">
+							<i class="bi bi-key mr-1"></i>
+							{{ .Label }}
+							<small class="d-block text-muted">
+								{{ tr "security.template.mfa.webauthn.created" "date" (.CreatedAt.Format "2006-01-02") }}
+								{{ if .LastUsedAt }}
+								&middot; {{ tr "security.template.mfa.webauthn.last-used" "date" (.LastUsedAt.Format "2006-01-02") }}
+								{{ end }}
+							</small>
+						</div>
+						{{ if or (not $.settings.MultiFactor.WebAuthn.Enforced) (gt (len $.webAuthnCredentials) 1) }}
+						<button
+							name="action"
+							value="removeWebAuthn"
+							formaction="{{ links.Security }}?credentialsID={{ .ID }}"
+							class="btn btn-danger btn-sm"
+						>
+							{{ tr "security.template.mfa.webauthn.remove" }}
+						</button>
+						{{ end }}
+					</li>
+				{{ end }}
+				</ul>
+				{{ end }}
+			</div>
+			{{ end }}
 		{{ else }}
 			<div class="mb-1 font-italic" role="alert">
 				{{ tr "security.template.mfa.all-disabled" }}
@@ -67,9 +67,10 @@ func (h AuthHandlers) handleSuccessfulExternalAuth(w http.ResponseWriter, r *htt
 			h.Opt.SessionLifetime,
 		)
 
-		// auto-complete EmailOTP and TOTP when authenticating via external identity provider
+		// auto-complete EmailOTP, TOTP and WebAuthn when authenticating via external identity provider
 		req.AuthUser.CompleteEmailOTP()
 		req.AuthUser.CompleteTOTP()
+		req.AuthUser.CompleteWebAuthn()
 
 		req.AuthUser.Save(req.Session)
 
@@ -32,6 +32,7 @@ func (h *AuthHandlers) loginForm(req *request.AuthReq) error {
 	}
 
 	req.Data["form"] = kv
+	h.passkeyLoginOptions(req)
 	return nil
 }
 
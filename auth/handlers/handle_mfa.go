@@ -37,6 +37,23 @@ func (h AuthHandlers) mfaForm(req *request.AuthReq) (err error) {
 	req.Data["emailOtpPending"] = req.AuthUser.PendingEmailOTP()
 	req.Data["totpDisabled"] = req.AuthUser.DisabledTOTP()
 	req.Data["totpPending"] = req.AuthUser.PendingTOTP()
+	req.Data["webAuthnDisabled"] = req.AuthUser.DisabledWebAuthn()
+	req.Data["webAuthnPending"] = req.AuthUser.PendingWebAuthn()
+
+	if req.AuthUser.PendingWebAuthn() {
+		ca, ws, err := h.AuthService.BeginWebAuthnLogin(
+			auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		)
+
+		if err != nil {
+			req.Data["webAuthnError"] = err.Error()
+			return nil
+		}
+
+		request.SetWebAuthnSession(req.Session, ws)
+		req.Data["webAuthnOptions"] = ca
+	}
+
 	return nil
 }
 
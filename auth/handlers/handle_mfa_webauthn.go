@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// WebAuthn responses are sent as JSON body and can not be
+	// limited with the rest of the (form) POST fields
+	maxWebAuthnResponseLength = 2 << 15
+)
+
+// Handles WebAuthn credentials registration form
+//
+// Options for navigator.credentials.create() are generated and passed
+// to the template; ceremony state is kept in the session
+func (h AuthHandlers) mfaWebAuthnConfigForm(req *request.AuthReq) (err error) {
+	req.Template = TmplMfaWebAuthn
+	req.Data["enforced"] = h.Settings.MultiFactor.WebAuthn.Enforced
+
+	form := req.PopKV()
+	if form == nil {
+		form = map[string]string{}
+	}
+
+	cc, ws, err := h.AuthService.BeginWebAuthnRegistration(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+	)
+
+	if err != nil {
+		form["error"] = err.Error()
+	} else {
+		request.SetWebAuthnSession(req.Session, ws)
+		req.Data["webAuthnOptions"] = cc
+	}
+
+	req.Data["form"] = form
+	return nil
+}
+
+// Handles WebAuthn credentials registration
+//
+// Expects JSON with the credentials label and response of navigator.credentials.create()
+func (h AuthHandlers) mfaWebAuthnConfigProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().MfaWebAuthnNewCredentials
+	req.SetKV(nil)
+
+	var (
+		user    *types.User
+		ws      = request.GetWebAuthnSession(req.Session)
+		payload struct {
+			Label      string          `json:"label"`
+			Credential json.RawMessage `json:"credential"`
+		}
+	)
+
+	// ceremony state is valid for one attempt only
+	request.SetWebAuthnSession(req.Session, nil)
+
+	if err = readWebAuthnResponse(req, &payload); err != nil {
+		req.SetKV(map[string]string{"error": err.Error()})
+		return nil
+	}
+
+	user, err = h.AuthService.ConfigureWebAuthn(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		ws,
+		payload.Label,
+		payload.Credential,
+	)
+
+	if err != nil {
+		req.SetKV(map[string]string{"error": err.Error()})
+		return nil
+	}
+
+	t := translator(req, "auth")
+	req.NewAlerts = append(req.NewAlerts, request.Alert{
+		Type: "primary",
+		Text: t("mfa-webauthn.alerts.registered"),
+	})
+
+	// Make sure we update User's data in the session
+	req.AuthUser.User = user
+	req.AuthUser.CompleteWebAuthn()
+	req.AuthUser.Save(req.Session)
+
+	h.Log.Info("WebAuthn credentials registered")
+	req.RedirectTo = GetLinks().Security
+	return nil
+}
+
+// Handles verification of WebAuthn MFA
+//
+// Expects JSON with the response of navigator.credentials.get()
+//
+// Errors are pushed as alerts since the response is
+// not posted from the MFA form itself
+func (h AuthHandlers) mfaWebAuthnProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().Mfa
+
+	var (
+		ws       = request.GetWebAuthnSession(req.Session)
+		response json.RawMessage
+	)
+
+	// ceremony state is valid for one attempt only
+	request.SetWebAuthnSession(req.Session, nil)
+
+	if err = readWebAuthnResponse(req, &response); err != nil {
+		req.PushDangerAlert(err.Error())
+		return nil
+	}
+
+	err = h.AuthService.ValidateWebAuthn(
+		auth.SetIdentityToContext(req.Context(), req.AuthUser.User),
+		ws,
+		response,
+	)
+
+	if err != nil {
+		req.PushDangerAlert(err.Error())
+		return nil
+	}
+
+	t := translator(req, "auth")
+
+	req.PushAlert(t("mfa.webauthn.valid"))
+	req.AuthUser.CompleteWebAuthn()
+
+	// All required MFA's confirmed, proceed to profile
+	handleSuccessfulAuth(req)
+	return nil
+}
+
+// Handles sign-in with a passkey
+//
+// Expects JSON with the response of navigator.credentials.get()
+//
+// Errors are pushed as alerts since the response is
+// not posted from the login form itself
+func (h AuthHandlers) loginPasskeyProc(req *request.AuthReq) (err error) {
+	req.RedirectTo = GetLinks().Login
+
+	var (
+		user     *types.User
+		ws       = request.GetWebAuthnSession(req.Session)
+		response json.RawMessage
+	)
+
+	// ceremony state is valid for one attempt only
+	request.SetWebAuthnSession(req.Session, nil)
+
+	if err = readWebAuthnResponse(req, &response); err != nil {
+		req.PushDangerAlert(err.Error())
+		return nil
+	}
+
+	if user, err = h.AuthService.PasswordlessLogin(req.Context(), ws, response); err != nil {
+		req.PushDangerAlert(err.Error())
+		h.Log.Warn("handled error", zap.Error(err))
+		return nil
+	}
+
+	req.AuthUser = request.NewAuthUser(h.Settings, user, false, h.Opt.SessionLifetime)
+
+	// user was verified by the authenticator,
+	// passkey satisfies the WebAuthn MFA
+	req.AuthUser.CompleteWebAuthn()
+	req.AuthUser.Save(req.Session)
+
+	h.Log.Info(
+		"login with passkey successful",
+		zap.Any("mfa", req.AuthUser.MFAStatus),
+	)
+
+	t := translator(req, "auth")
+	req.PushAlert(t("login.alerts.logged-in"))
+
+	if req.AuthUser.PendingEmailOTP() {
+		if err = h.AuthService.SendEmailOTP(auth.SetIdentityToContext(req.Context(), req.AuthUser.User)); err != nil {
+			return errors.Internal("could not send OTP via email, contact your administrator").Wrap(err)
+		}
+	}
+
+	handleSuccessfulAuth(req)
+	return nil
+}
+
+// prepares options for the passwordless login (if enabled)
+func (h AuthHandlers) passkeyLoginOptions(req *request.AuthReq) {
+	if !h.Settings.MultiFactor.WebAuthn.Enabled || !h.Settings.MultiFactor.WebAuthn.Passwordless {
+		return
+	}
+
+	ca, ws, err := h.AuthService.BeginPasswordlessLogin(req.Context())
+	if err != nil {
+		h.Log.Warn("could not prepare passkey login", zap.Error(err))
+		return
+	}
+
+	request.SetWebAuthnSession(req.Session, ws)
+	req.Data["webAuthnOptions"] = ca
+}
+
+// reads JSON encoded WebAuthn response from the request body
+func readWebAuthnResponse(req *request.AuthReq, dst interface{}) error {
+	if req.Request.Body == nil {
+		return fmt.Errorf("missing WebAuthn response")
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Request.Body, maxWebAuthnResponseLength+1))
+	if err != nil {
+		return err
+	}
+
+	if len(body) > maxWebAuthnResponseLength {
+		return fmt.Errorf("WebAuthn response too large")
+	}
+
+	if err = json.Unmarshal(body, dst); err != nil {
+		return fmt.Errorf("invalid WebAuthn response")
+	}
+
+	return nil
+}
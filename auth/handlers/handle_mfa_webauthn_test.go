@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/auth/request"
+	"github.com/cortezaproject/corteza-server/auth/settings"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mfaWebAuthnProc(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{}
+
+		authService  authService
+		authHandlers *AuthHandlers
+		authReq      *request.AuthReq
+	)
+
+	tcc := []testingExpect{
+		{
+			name:   "successful verification",
+			alerts: []request.Alert{{Type: "primary", Text: "mfa.webauthn.valid"}},
+			link:   GetLinks().Mfa,
+			fn: func(_ *settings.Settings) {
+				req.Body = ioutil.NopCloser(strings.NewReader(`{"type":"public-key"}`))
+
+				authService = &authServiceMocked{
+					validateWebAuthn: func(ctx context.Context, ws *webauthn.Session, response []byte) (err error) {
+						return nil
+					},
+				}
+			},
+		},
+		{
+			name:   "invalid response",
+			alerts: []request.Alert{{Type: "danger", Text: "invalid WebAuthn response"}},
+			link:   GetLinks().Mfa,
+			fn: func(_ *settings.Settings) {
+				req.Body = ioutil.NopCloser(strings.NewReader(`not-json`))
+				authService = &authServiceMocked{}
+			},
+		},
+		{
+			name:   "invalid credentials",
+			alerts: []request.Alert{{Type: "danger", Text: "invalid security key or passkey"}},
+			link:   GetLinks().Mfa,
+			fn: func(_ *settings.Settings) {
+				req.Body = ioutil.NopCloser(strings.NewReader(`{"type":"public-key"}`))
+
+				authService = &authServiceMocked{
+					validateWebAuthn: func(ctx context.Context, ws *webauthn.Session, response []byte) (err error) {
+						return service.AuthErrInvalidWebAuthn()
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			rq := require.New(t)
+
+			user.Meta = &types.UserMeta{}
+
+			authSettings := &settings.Settings{}
+
+			tc.fn(authSettings)
+
+			authHandlers = prepareClientAuthHandlers(authService, authSettings)
+			authReq = prepareClientAuthReq(authHandlers, req, user)
+			request.SetWebAuthnSession(authReq.Session, &webauthn.Session{})
+
+			err := authHandlers.mfaWebAuthnProc(authReq)
+
+			rq.NoError(err)
+			rq.Equal(tc.alerts, authReq.NewAlerts)
+			rq.Equal(tc.link, authReq.RedirectTo)
+			rq.Nil(request.GetWebAuthnSession(authReq.Session))
+		})
+	}
+}
+
+func Test_mfaWebAuthnConfigProc(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{}
+
+		authService  authService
+		authHandlers *AuthHandlers
+		authReq      *request.AuthReq
+	)
+
+	tcc := []testingExpect{
+		{
+			name:   "credentials registered",
+			alerts: []request.Alert{{Type: "primary", Text: "mfa-webauthn.alerts.registered"}},
+			link:   GetLinks().Security,
+			fn: func(_ *settings.Settings) {
+				req.Body = ioutil.NopCloser(strings.NewReader(`{"label":"Security key","credential":{"type":"public-key"}}`))
+
+				authService = &authServiceMocked{
+					configureWebAuthn: func(ctx context.Context, ws *webauthn.Session, label string, response []byte) (*types.User, error) {
+						if label != "Security key" || string(response) != `{"type":"public-key"}` {
+							return nil, service.AuthErrInvalidWebAuthn()
+						}
+
+						u := makeMockUser()
+						u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+						return u, nil
+					},
+				}
+			},
+		},
+		{
+			name:    "registration failed",
+			payload: map[string]string{"error": "multi factor authentication with WebAuthn is disabled"},
+			link:    GetLinks().MfaWebAuthnNewCredentials,
+			fn: func(_ *settings.Settings) {
+				req.Body = ioutil.NopCloser(strings.NewReader(`{"label":"Security key","credential":{}}`))
+
+				authService = &authServiceMocked{
+					configureWebAuthn: func(ctx context.Context, ws *webauthn.Session, label string, response []byte) (*types.User, error) {
+						return nil, service.AuthErrDisabledMFAWithWebAuthn()
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			rq := require.New(t)
+
+			authSettings := &settings.Settings{}
+
+			tc.fn(authSettings)
+
+			authHandlers = prepareClientAuthHandlers(authService, authSettings)
+			authReq = prepareClientAuthReq(authHandlers, req, user)
+
+			err := authHandlers.mfaWebAuthnConfigProc(authReq)
+
+			rq.NoError(err)
+			rq.Equal(tc.alerts, authReq.NewAlerts)
+			rq.Equal(tc.link, authReq.RedirectTo)
+
+			if tc.payload != nil {
+				rq.Equal(tc.payload, authReq.GetKV())
+			} else {
+				rq.True(authReq.AuthUser.User.Meta.SecurityPolicy.MFA.EnforcedWebAuthn)
+			}
+		})
+	}
+}
+
+func Test_loginPasskeyProc(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{}
+
+		authService  authService
+		authHandlers *AuthHandlers
+		authReq      *request.AuthReq
+	)
+
+	tcc := []testingExpect{
+		{
+			name:   "successful login",
+			alerts: []request.Alert{{Type: "primary", Text: "login.alerts.logged-in"}},
+			link:   GetLinks().Profile,
+			fn: func(s *settings.Settings) {
+				s.MultiFactor.WebAuthn.Enabled = true
+				s.MultiFactor.WebAuthn.Passwordless = true
+
+				user.Meta = &types.UserMeta{}
+				user.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+
+				req.Body = ioutil.NopCloser(strings.NewReader(`{"type":"public-key"}`))
+
+				authService = &authServiceMocked{
+					passwordlessLogin: func(ctx context.Context, ws *webauthn.Session, response []byte) (*types.User, error) {
+						return user, nil
+					},
+				}
+			},
+		},
+		{
+			name:   "passwordless login disabled",
+			alerts: []request.Alert{{Type: "danger", Text: "sign-in with passkey is disabled"}},
+			link:   GetLinks().Login,
+			fn: func(_ *settings.Settings) {
+				req.Body = ioutil.NopCloser(strings.NewReader(`{"type":"public-key"}`))
+
+				authService = &authServiceMocked{
+					passwordlessLogin: func(ctx context.Context, ws *webauthn.Session, response []byte) (*types.User, error) {
+						return nil, service.AuthErrPasswordlessLoginDisabledByConfig()
+					},
+				}
+			},
+		},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			rq := require.New(t)
+
+			authSettings := &settings.Settings{}
+
+			tc.fn(authSettings)
+
+			authHandlers = prepareClientAuthHandlers(authService, authSettings)
+			authReq = prepareClientAuthReq(authHandlers, req, nil)
+
+			err := authHandlers.loginPasskeyProc(authReq)
+
+			rq.NoError(err)
+			rq.Equal(tc.alerts, authReq.NewAlerts)
+			rq.Equal(tc.link, authReq.RedirectTo)
+
+			if tc.link == GetLinks().Profile {
+				rq.NotNil(authReq.AuthUser)
+				rq.False(authReq.AuthUser.PendingMFA())
+			}
+		})
+	}
+}
+
+func Test_securityProcRemoveWebAuthn(t *testing.T) {
+	var (
+		user = makeMockUser()
+
+		req = &http.Request{
+			Form:     url.Values{},
+			PostForm: url.Values{},
+		}
+
+		authService  authService
+		authHandlers *AuthHandlers
+		authReq      *request.AuthReq
+
+		authSettings = &settings.Settings{}
+
+		removed uint64
+
+		rq = require.New(t)
+	)
+
+	req.Form.Set("action", "removeWebAuthn")
+	req.Form.Set("credentialsID", "42")
+
+	authService = &authServiceMocked{
+		removeWebAuthn: func(c context.Context, userID, credentialsID uint64) (*types.User, error) {
+			removed = credentialsID
+			return user, nil
+		},
+	}
+
+	authHandlers = prepareClientAuthHandlers(authService, authSettings)
+	authReq = prepareClientAuthReq(authHandlers, req, user)
+
+	err := authHandlers.securityProc(authReq)
+
+	rq.NoError(err)
+	rq.Equal(uint64(42), removed)
+	rq.Equal(GetLinks().Security, authReq.RedirectTo)
+	rq.Equal([]request.Alert{{Type: "primary", Text: "security.webauthn-removed"}}, authReq.NewAlerts)
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"strconv"
+
 	"github.com/cortezaproject/corteza-server/auth/request"
 	"go.uber.org/zap"
 )
@@ -13,6 +15,16 @@ func (h *AuthHandlers) securityForm(req *request.AuthReq) error {
 
 	req.Data["emailOtpEnforced"] = umsp.EnforcedEmailOTP
 	req.Data["totpEnforced"] = umsp.EnforcedTOTP
+	req.Data["webAuthnEnforced"] = umsp.EnforcedWebAuthn
+
+	if h.Settings.MultiFactor.WebAuthn.Enabled {
+		cc, err := h.AuthService.WebAuthnCredentials(req.Context(), req.AuthUser.User.ID)
+		if err != nil {
+			return err
+		}
+
+		req.Data["webAuthnCredentials"] = cc
+	}
 
 	return nil
 }
@@ -30,6 +42,27 @@ func (h *AuthHandlers) securityProc(req *request.AuthReq) error {
 	case "disableTOTP":
 		req.RedirectTo = GetLinks().MfaTotpDisable
 
+	case "configureWebAuthn":
+		req.RedirectTo = GetLinks().MfaWebAuthnNewCredentials
+
+	case "removeWebAuthn":
+		credentialsID, _ := strconv.ParseUint(req.Request.Form.Get("credentialsID"), 10, 64)
+		if user, err := h.AuthService.RemoveWebAuthn(req.Context(), req.AuthUser.User.ID, credentialsID); err != nil {
+			req.PushDangerAlert(err.Error())
+		} else {
+			t := translator(req, "auth")
+			req.NewAlerts = append(req.NewAlerts, request.Alert{
+				Type: "primary",
+				Text: t("security.webauthn-removed"),
+			})
+
+			// Make sure we update User's data in the session
+			req.AuthUser.User = user
+			req.AuthUser.Save(req.Session)
+
+			h.Log.Info("WebAuthn credentials removed", zap.Uint64("credentialsID", credentialsID))
+		}
+
 	case "disableEmailOTP", "enableEmailOTP":
 		enable := action == "enableEmailOTP"
 		if user, err := h.AuthService.ConfigureEmailOTP(req.Context(), req.AuthUser.User.ID, enable); err != nil {
@@ -16,6 +16,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/go-oauth2/oauth2/v4"
 	"github.com/go-oauth2/oauth2/v4/server"
@@ -48,6 +49,15 @@ type (
 		SendEmailOTP(ctx context.Context) (err error)
 		ConfigureEmailOTP(ctx context.Context, userID uint64, enable bool) (u *types.User, err error)
 		ValidateEmailOTP(ctx context.Context, code string) (err error)
+
+		WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error)
+		BeginWebAuthnRegistration(ctx context.Context) (*webauthn.CredentialCreation, *webauthn.Session, error)
+		ConfigureWebAuthn(ctx context.Context, ws *webauthn.Session, label string, response []byte) (u *types.User, err error)
+		RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error)
+		BeginWebAuthnLogin(ctx context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error)
+		ValidateWebAuthn(ctx context.Context, ws *webauthn.Session, response []byte) (err error)
+		BeginPasswordlessLogin(ctx context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error)
+		PasswordlessLogin(ctx context.Context, ws *webauthn.Session, response []byte) (u *types.User, err error)
 	}
 
 	userService interface {
@@ -134,6 +144,7 @@ const (
 	TmplMfa                      = "mfa.html.tpl"
 	TmplMfaTotp                  = "mfa-totp.html.tpl"
 	TmplMfaTotpDisable           = "mfa-totp-disable.html.tpl"
+	TmplMfaWebAuthn              = "mfa-webauthn.html.tpl"
 	TmplInternalError            = "error-internal.html.tpl"
 
 	// 1k of data per POST field is all we allow
@@ -387,6 +398,10 @@ func authOnly(fn handlerFn) handlerFn {
 			// authenticated but need to configure MFA
 			req.RedirectTo = GetLinks().MfaTotpNewSecret
 
+		case req.AuthUser.UnconfiguredWebAuthn():
+			// authenticated but need to register WebAuthn credentials
+			req.RedirectTo = GetLinks().MfaWebAuthnNewCredentials
+
 		case req.AuthUser.PendingMFA():
 			// authenticated but MFA pending
 			req.RedirectTo = GetLinks().Mfa
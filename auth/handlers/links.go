@@ -12,6 +12,7 @@ type (
 		ConfirmEmail,
 		PendingEmailConfirmation,
 		Login,
+		LoginPasskey,
 
 		Security,
 		ChangePassword,
@@ -37,6 +38,9 @@ type (
 		MfaTotpQRImage,
 		MfaTotpDisable,
 
+		MfaWebAuthn,
+		MfaWebAuthnNewCredentials,
+
 		External,
 
 		SamlInit,
@@ -64,6 +68,7 @@ func GetLinks() Links {
 		ConfirmEmail:             b + "auth/confirm-email",
 		PendingEmailConfirmation: b + "auth/pending-email-confirmation",
 		Login:                    b + "auth/login",
+		LoginPasskey:             b + "auth/login/passkey",
 		Security:                 b + "auth/security",
 		ChangePassword:           b + "auth/change-password",
 		CreatePassword:           b + "auth/create-password",
@@ -86,6 +91,9 @@ func GetLinks() Links {
 		MfaTotpQRImage:   b + "auth/mfa/totp/qr.png",
 		MfaTotpDisable:   b + "auth/mfa/totp/disable",
 
+		MfaWebAuthn:               b + "auth/mfa/webauthn",
+		MfaWebAuthnNewCredentials: b + "auth/mfa/webauthn/setup",
+
 		External: b + "auth/external",
 
 		SamlInit:     b + "auth/external/saml/init",
@@ -10,6 +10,7 @@ import (
 	"github.com/cortezaproject/corteza-server/auth/settings"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -96,6 +97,14 @@ type (
 		sendEmailOTP                      func(context.Context) (err error)
 		configureEmailOTP                 func(context.Context, uint64, bool) (u *types.User, err error)
 		validateEmailOTP                  func(context.Context, string) (err error)
+		webAuthnCredentials               func(context.Context, uint64) (types.CredentialsSet, error)
+		beginWebAuthnRegistration         func(context.Context) (*webauthn.CredentialCreation, *webauthn.Session, error)
+		configureWebAuthn                 func(context.Context, *webauthn.Session, string, []byte) (u *types.User, err error)
+		removeWebAuthn                    func(context.Context, uint64, uint64) (u *types.User, err error)
+		beginWebAuthnLogin                func(context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error)
+		validateWebAuthn                  func(context.Context, *webauthn.Session, []byte) (err error)
+		beginPasswordlessLogin            func(context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error)
+		passwordlessLogin                 func(context.Context, *webauthn.Session, []byte) (u *types.User, err error)
 	}
 )
 
@@ -185,6 +194,38 @@ func (s authServiceMocked) ValidateEmailOTP(ctx context.Context, code string) (e
 	return s.validateEmailOTP(ctx, code)
 }
 
+func (s authServiceMocked) WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error) {
+	return s.webAuthnCredentials(ctx, userID)
+}
+
+func (s authServiceMocked) BeginWebAuthnRegistration(ctx context.Context) (*webauthn.CredentialCreation, *webauthn.Session, error) {
+	return s.beginWebAuthnRegistration(ctx)
+}
+
+func (s authServiceMocked) ConfigureWebAuthn(ctx context.Context, ws *webauthn.Session, label string, response []byte) (u *types.User, err error) {
+	return s.configureWebAuthn(ctx, ws, label, response)
+}
+
+func (s authServiceMocked) RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error) {
+	return s.removeWebAuthn(ctx, userID, credentialsID)
+}
+
+func (s authServiceMocked) BeginWebAuthnLogin(ctx context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error) {
+	return s.beginWebAuthnLogin(ctx)
+}
+
+func (s authServiceMocked) ValidateWebAuthn(ctx context.Context, ws *webauthn.Session, response []byte) (err error) {
+	return s.validateWebAuthn(ctx, ws, response)
+}
+
+func (s authServiceMocked) BeginPasswordlessLogin(ctx context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error) {
+	return s.beginPasswordlessLogin(ctx)
+}
+
+func (s authServiceMocked) PasswordlessLogin(ctx context.Context, ws *webauthn.Session, response []byte) (u *types.User, err error) {
+	return s.passwordlessLogin(ctx, ws, response)
+}
+
 func (s authServiceMocked) LoadRoleMemberships(ctx context.Context, u *types.User) error {
 	// no-op for now
 	return nil
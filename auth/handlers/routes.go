@@ -68,6 +68,7 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 
 			r.Get(tbp(l.Login), h.handle(anonyOnly(h.loginForm)))
 			r.Post(tbp(l.Login), h.handle(h.onlyIfLocalEnabled(anonyOnly(h.loginProc))))
+			r.Post(tbp(l.LoginPasskey), h.handle(anonyOnly(h.loginPasskeyProc)))
 
 			r.Get(tbp(l.Mfa), h.handle(h.mfaForm))
 			r.Post(tbp(l.Mfa), h.handle(h.mfaProc))
@@ -91,6 +92,10 @@ func (h *AuthHandlers) MountHttpRoutes(r chi.Router) {
 			r.Get(tbp(l.MfaTotpDisable), h.handle(authOnly(h.mfaTotpDisableForm)))
 			r.Post(tbp(l.MfaTotpDisable), h.handle(authOnly(h.mfaTotpDisableProc)))
 
+			r.Get(tbp(l.MfaWebAuthnNewCredentials), h.handle(partAuthOnly(h.mfaWebAuthnConfigForm)))
+			r.Post(tbp(l.MfaWebAuthnNewCredentials), h.handle(partAuthOnly(h.mfaWebAuthnConfigProc)))
+			r.Post(tbp(l.MfaWebAuthn), h.handle(partAuthOnly(h.mfaWebAuthnProc)))
+
 		})
 
 		r.Group(func(r chi.Router) {
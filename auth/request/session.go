@@ -1,9 +1,12 @@
 package request
 
 import (
+	"encoding/gob"
+	"net/url"
+
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/gorilla/sessions"
-	"net/url"
 )
 
 const (
@@ -14,8 +17,13 @@ const (
 	keyOAuth2AuthParams       = "oauth2AuthParams"
 	keyOAuth2Client           = "oauth2ClientID"
 	keyOAuth2ClientAuthorized = "oauth2ClientAuthorized"
+	keyWebAuthnSession        = "webAuthnSession"
 )
 
+func init() {
+	gob.Register(&webauthn.Session{})
+}
+
 // GetUser is wrapper to get value from session
 func GetAuthUser(ses *sessions.Session) *authUser {
 	val, has := ses.Values[keyAuthUser]
@@ -101,3 +109,25 @@ func SetOauth2ClientAuthorized(ses *sessions.Session, val bool) {
 		delete(ses.Values, keyOAuth2ClientAuthorized)
 	}
 }
+
+// GetWebAuthnSession is wrapper to get value from session
+func GetWebAuthnSession(ses *sessions.Session) *webauthn.Session {
+	val, has := ses.Values[keyWebAuthnSession]
+	if !has {
+		return nil
+	}
+
+	return val.(*webauthn.Session)
+}
+
+// SetWebAuthnSession is a session value setting wrapper for WebAuthnSession
+//
+// State of the WebAuthn ceremony is kept in the session
+// between generating the options and verifying the response
+func SetWebAuthnSession(ses *sessions.Session, val *webauthn.Session) {
+	if val != nil {
+		ses.Values[keyWebAuthnSession] = val
+	} else {
+		delete(ses.Values, keyWebAuthnSession)
+	}
+}
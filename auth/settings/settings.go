@@ -49,6 +49,7 @@ type (
 	MultiFactor struct {
 		EmailOTP EmailOTP
 		TOTP     TOTP
+		WebAuthn WebAuthn
 	}
 
 	EmailOTP struct {
@@ -70,6 +71,17 @@ type (
 		Issuer string
 	}
 
+	WebAuthn struct {
+		// Can users use WebAuthn MFA?
+		Enabled bool
+
+		// Is WebAuthn MFA enforced?
+		Enforced bool
+
+		// Can users sign-in with a passkey instead of the password?
+		Passwordless bool
+	}
+
 	Provider struct {
 		Handle      string
 		Label       string
@@ -12,8 +12,6 @@ import (
 )
 
 type (
-	secureStoragerTodo struct{}
-
 	oauth2Handler struct {
 		reg oauth2HandlerRegistry
 	}
@@ -66,7 +64,7 @@ func (h oauth2Handler) authenticate(ctx context.Context, args *oauth2Authenticat
 		TokenUrl: u,
 	}
 
-	servicer, err := auth.NewOauth2(params, http.DefaultClient, secureStoragerTodo{})
+	servicer, err := auth.NewOauth2(params, http.DefaultClient, nil)
 
 	if err != nil {
 		err = fmt.Errorf("could not init servicer: %s", err)
@@ -25,6 +25,7 @@ type (
 		ActionLog options.ActionLogOpt
 		Workflow  options.WorkflowOpt
 		Corredor  options.CorredorOpt
+		Limit     options.LimitOpt
 	}
 
 	userService interface {
@@ -92,10 +93,11 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 	DefaultAccessControl = AccessControl()
 
 	DefaultSession = Session(DefaultLogger.Named("session"), c.Workflow, ws)
-	DefaultWorkflow = Workflow(DefaultLogger.Named("workflow"), c.Corredor, c.Workflow)
+	DefaultWorkflow = Workflow(DefaultLogger.Named("workflow"), c.Corredor, c.Workflow, WorkflowLimits{LimitWorkflows: c.Limit.AutomationWorkflows})
 	DefaultTrigger = Trigger(DefaultLogger.Named("trigger"), c.Workflow)
 
 	DefaultWorkflow.triggers = DefaultTrigger
+	DefaultSession.graph = DefaultWorkflow.graph
 
 	Registry().AddTypes(
 		&expr.Any{},
@@ -135,6 +137,12 @@ func Activate(ctx context.Context) (err error) {
 		return
 	}
 
+	if err = DefaultSession.resumeAll(ctx, DefaultWorkflow.graph); err != nil {
+		return
+	}
+
+	DefaultSession.watchOrphaned(ctx, DefaultWorkflow.graph)
+
 	return
 }
 
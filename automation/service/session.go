@@ -2,12 +2,14 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/automation/types"
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -32,6 +34,10 @@ type (
 		pool         map[uint64]*types.Session
 		spawnQueue   chan *spawn
 		promptSender promptSender
+
+		// graph of the executable workflow, used when
+		// session is restored from the store
+		graph func(workflowID uint64) *wfexec.Graph
 	}
 
 	spawn struct {
@@ -60,6 +66,9 @@ const (
 	// We use the size of the stacktrace and for every F (see the value of the constant)
 	// we flush the session info to the store.
 	sessionStateFlushFrequency = 1000
+
+	// how often sessions of cluster nodes that are no longer running are adopted
+	sessionAdoptInterval = time.Minute
 )
 
 func Session(log *zap.Logger, opt options.WorkflowOpt, ps promptSender) *session {
@@ -124,19 +133,173 @@ func (svc *session) LookupByID(ctx context.Context, sessionID uint64) (res *type
 	return res, svc.recordAction(ctx, sap, SessionActionLookup, err)
 }
 
-func (svc *session) resumeAll(ctx context.Context) error {
-	// In theory we could resume active/pending/prompt sessions from persistent store
-	// so that they can survive server termination
+// resumeAll restores all prompted and delayed sessions from the store
+//
+// Sessions that can not be restored (missing state, workflow was removed
+// or changed) are marked as failed.
+//
+// Sessions that are run by other (running) cluster nodes are skipped.
+// Sessions of nodes that are no longer running are adopted by the cluster leader.
+func (svc *session) resumeAll(ctx context.Context, graph func(workflowID uint64) *wfexec.Graph) error {
+	return svc.resume(ctx, graph, func(ses *types.Session) (bool, error) {
+		if ses.State == nil || ses.State.Node == "" || ses.State.Node == cluster.NodeID() {
+			return true, nil
+		}
+
+		return svc.orphaned(ctx, ses)
+	})
+}
+
+// watchOrphaned periodically adopts sessions of cluster nodes that are no longer running
+func (svc *session) watchOrphaned(ctx context.Context, graph func(workflowID uint64) *wfexec.Graph) {
+	ticker := time.NewTicker(sessionAdoptInterval)
+
+	go func() {
+		defer sentry.Recover()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := svc.resume(ctx, graph, func(ses *types.Session) (bool, error) {
+					return svc.orphaned(ctx, ses)
+				})
+
+				if err != nil {
+					svc.log.Error("failed to adopt orphaned sessions", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// orphaned returns true for sessions run by cluster nodes that are no longer running
+//
+// Only the cluster leader adopts orphaned sessions so that
+// the same session is never restored on multiple nodes
+func (svc *session) orphaned(ctx context.Context, ses *types.Session) (bool, error) {
+	if ses.State == nil || ses.State.Node == "" || ses.State.Node == cluster.NodeID() {
+		return false, nil
+	}
+
+	if !cluster.Holds(cluster.LeaderLease) {
+		return false, nil
+	}
+
+	alive, err := cluster.NodeAlive(ctx, ses.State.Node)
+	return !alive, err
+}
+
+// resume restores all suspended sessions that are not in the pool and pass the check
+func (svc *session) resume(ctx context.Context, graph func(workflowID uint64) *wfexec.Graph, check func(*types.Session) (bool, error)) error {
+	set, _, err := store.SearchAutomationSessions(ctx, svc.store, types.SessionFilter{
+		Status: []uint{uint(types.SessionPrompted), uint(types.SessionSuspended)},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, ses := range set {
+		svc.mux.RLock()
+		_, pooled := svc.pool[ses.ID]
+		svc.mux.RUnlock()
+
+		if pooled {
+			continue
+		}
+
+		if ok, err := check(ses); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		log := svc.log.With(
+			zap.Uint64("sessionID", ses.ID),
+			zap.Uint64("workflowID", ses.WorkflowID),
+		)
+
+		if err = svc.restore(ctx, ses, graph(ses.WorkflowID)); err == nil {
+			log.Debug("session restored")
+
+			if nodeID := cluster.NodeID(); ses.State.Node != nodeID {
+				// take over the session so that it is not
+				// restored by other nodes
+				ses.State.Node = nodeID
+				if err = svc.store.UpsertAutomationSession(ctx, ses); err != nil {
+					log.Error("failed to update session", zap.Error(err))
+				}
+			}
+
+			continue
+		}
+
+		log.Warn("failed to restore session", zap.Error(err))
+
+		ses.State = nil
+		ses.SuspendedAt = nil
+		ses.CompletedAt = now()
+		ses.Error = err.Error()
+		ses.Status = types.SessionFailed
+
+		if err = svc.store.UpsertAutomationSession(ctx, ses); err != nil {
+			log.Error("failed to update session", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// restore rehydrates session from the stored state and adds it to the pool
+func (svc *session) restore(ctx context.Context, ses *types.Session, g *wfexec.Graph) error {
+	if ses.State != nil && ses.State.Error != "" {
+		return fmt.Errorf("session state not stored: %s", ses.State.Error)
+	}
+
+	if ses.State == nil || ses.State.Session == nil {
+		return fmt.Errorf("session state not stored")
+	}
+
+	if g == nil {
+		return fmt.Errorf("workflow %d is not executable", ses.WorkflowID)
+	}
+
+	if err := ses.State.Session.ResolveTypes(Registry().Type); err != nil {
+		return err
+	}
+
+	var (
+		runner  = ses.State.RunnerIdentity()
+		invoker = ses.State.InvokerIdentity()
+	)
+
+	wfs, err := wfexec.RestoreSession(
+		svc.execCtx(runner, invoker),
+		g,
+		ses.State.Session,
+		svc.execOpts(ctx, ses.WorkflowID, runner)...,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	ses.Restore(wfs, runner, invoker)
+
+	svc.mux.Lock()
+	svc.pool[ses.ID] = ses
+	svc.mux.Unlock()
 
-	// @todo resume active sessions from storage
-	//       load all active sessions from store and load them into the pool
-	//
 	return nil
 }
 
 func (svc *session) suspendAll(ctx context.Context) error {
-	// In theory we could suspend active/pending/prompt sessions to persistent store
-	// so that they can survive server termination
+	// Prompted and delayed sessions are flushed to persistent store
+	// (with their state) by the status handler and restored on start
+	// by resumeAll()
 
 	// @todo suspend active sessions to storage:
 	//       stop watcher queue
@@ -246,11 +409,9 @@ func (svc *session) Resume(sessionID, stateID uint64, i auth.Identifiable, input
 		ctx = auth.SetIdentityToContext(context.Background(), i)
 	)
 
-	svc.mux.RLock()
-	defer svc.mux.RUnlock()
-	ses := svc.pool[sessionID]
-	if ses == nil {
-		return errors.NotFound("session not found")
+	ses, err := svc.resumable(ctx, sessionID)
+	if err != nil {
+		return err
 	}
 
 	resPrompt, err := ses.Resume(ctx, stateID, input)
@@ -265,6 +426,66 @@ func (svc *session) Resume(sessionID, stateID uint64, i auth.Identifiable, input
 	return nil
 }
 
+// resumable returns session that can be resumed on this node
+//
+// Prompted session is not in the pool when it was suspended on another node
+// (request was routed to a different node than the one running the session).
+// Session is then restored from the store and taken over by this node.
+// Pooled copy of a session that was taken over by another node is stopped and
+// replaced with the one from the store.
+func (svc *session) resumable(ctx context.Context, sessionID uint64) (*types.Session, error) {
+	svc.mux.RLock()
+	ses := svc.pool[sessionID]
+	svc.mux.RUnlock()
+
+	stored, err := loadSession(ctx, svc.store, sessionID)
+	if err != nil {
+		if ses != nil && SessionErrNotFound().Is(err) {
+			// session not flushed to the store yet
+			return ses, nil
+		}
+
+		return nil, err
+	}
+
+	if ses != nil {
+		if stored.State == nil || stored.State.Node == "" || stored.State.Node == cluster.NodeID() {
+			return ses, nil
+		}
+
+		svc.log.Debug("session taken over by another node",
+			zap.Uint64("sessionID", sessionID),
+			zap.String("node", stored.State.Node),
+		)
+
+		ses.Stop()
+
+		svc.mux.Lock()
+		delete(svc.pool, sessionID)
+		svc.mux.Unlock()
+	}
+
+	if stored.Status != types.SessionPrompted {
+		return nil, errors.NotFound("session not found")
+	}
+
+	var g *wfexec.Graph
+	if svc.graph != nil {
+		g = svc.graph(stored.WorkflowID)
+	}
+
+	if err = svc.restore(ctx, stored, g); err != nil {
+		return nil, err
+	}
+
+	stored.State.Node = cluster.NodeID()
+	if err = svc.store.UpsertAutomationSession(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
 // spawns a new session
 //
 // We need initial context for the session because we want to catch all cancellations or timeouts from there
@@ -284,7 +505,7 @@ func (svc *session) spawn(g *wfexec.Graph, workflowID uint64, trace bool, callSt
 	svc.spawnQueue <- s
 
 	// blocks until session is set
-	ses = types.NewSession(<-s.session)
+	ses = types.NewSession(<-s.session, runner, invoker)
 
 	svc.mux.Lock()
 	svc.pool[ses.ID] = ses
@@ -307,37 +528,12 @@ func (svc *session) Watch(ctx context.Context) {
 			case <-ctx.Done():
 				return
 			case s := <-svc.spawnQueue:
-				var execCtx = context.Background()
-
-				opts := []wfexec.SessionOpt{
-					wfexec.SetWorkflowID(s.workflowID),
+				opts := append(
+					svc.execOpts(ctx, s.workflowID, s.runner),
 					wfexec.SetCallStack(s.callStack...),
-					wfexec.SetHandler(svc.stateChangeHandler(ctx)),
-				}
-
-				if svc.opt.ExecDebug {
-					log := svc.log.
-						Named("exec").
-						With(zap.Uint64("workflowID", s.workflowID)).
-						With(zap.Uint64("runnerID", s.runner.Identity())).
-						With(zap.Uint64s("runnerRoles", s.runner.Roles()))
-
-					opts = append(
-						opts,
-						wfexec.SetLogger(log),
-						wfexec.SetDumpStacktraceOnPanic(true),
-					)
-				}
+				)
 
-				// Encode runner into execution context
-				// runner is used as identity and for access control
-				execCtx = auth.SetIdentityToContext(execCtx, s.runner)
-
-				// Encode invoker into execution context
-				// invoker is used
-				execCtx = context.WithValue(execCtx, workflowInvokerCtxKey{}, s.invoker)
-
-				s.session <- wfexec.NewSession(execCtx, s.graph, opts...)
+				s.session <- wfexec.NewSession(svc.execCtx(s.runner, s.invoker), s.graph, opts...)
 				// case time for a pool cleanup
 				// @todo cleanup pool when sessions are complete
 
@@ -356,6 +552,45 @@ func (svc *session) Watch(ctx context.Context) {
 	svc.log.Debug("watcher initialized")
 }
 
+// execCtx creates context for workflow session execution
+func (svc *session) execCtx(runner, invoker auth.Identifiable) context.Context {
+	var execCtx = context.Background()
+
+	// Encode runner into execution context
+	// runner is used as identity and for access control
+	execCtx = auth.SetIdentityToContext(execCtx, runner)
+
+	// Encode invoker into execution context
+	// invoker is used
+	execCtx = context.WithValue(execCtx, workflowInvokerCtxKey{}, invoker)
+
+	return execCtx
+}
+
+// execOpts prepares options for new or restored workflow session
+func (svc *session) execOpts(ctx context.Context, workflowID uint64, runner auth.Identifiable) []wfexec.SessionOpt {
+	opts := []wfexec.SessionOpt{
+		wfexec.SetWorkflowID(workflowID),
+		wfexec.SetHandler(svc.stateChangeHandler(ctx)),
+	}
+
+	if svc.opt.ExecDebug {
+		log := svc.log.
+			Named("exec").
+			With(zap.Uint64("workflowID", workflowID)).
+			With(zap.Uint64("runnerID", runner.Identity())).
+			With(zap.Uint64s("runnerRoles", runner.Roles()))
+
+		opts = append(
+			opts,
+			wfexec.SetLogger(log),
+			wfexec.SetDumpStacktraceOnPanic(true),
+		)
+	}
+
+	return opts
+}
+
 // garbage collection for stale sessions
 func (svc *session) gc() {
 	svc.mux.Lock()
@@ -464,11 +699,13 @@ func (svc *session) stateChangeHandler(ctx context.Context) wfexec.StateChangeHa
 			ses.Status = types.SessionSuspended
 
 		case wfexec.SessionCompleted:
+			ses.State = nil
 			ses.SuspendedAt = nil
 			ses.CompletedAt = now()
 			ses.Status = types.SessionCompleted
 
 		case wfexec.SessionFailed:
+			ses.State = nil
 			ses.SuspendedAt = nil
 			ses.CompletedAt = now()
 			ses.Error = state.Error()
@@ -483,6 +720,14 @@ func (svc *session) stateChangeHandler(ctx context.Context) wfexec.StateChangeHa
 			return
 		}
 
+		if i == wfexec.SessionPrompted || i == wfexec.SessionDelayed {
+			// keep the state of the suspended session so
+			// that it can be restored after restart
+			if err := ses.Suspend(cluster.NodeID()); err != nil {
+				log.Warn("failed to make session snapshot, session will not survive restart", zap.Error(err))
+			}
+		}
+
 		ses.CopyRuntimeStacktrace()
 
 		if err := svc.store.UpsertAutomationSession(ctx, ses); err != nil {
@@ -42,3 +42,17 @@ func TestSession_Start(t *testing.T) {
 	req.EqualError(err, "cannot start workflow on a step with parents")
 
 }
+
+func TestSession_RestoreWithoutSnapshot(t *testing.T) {
+	var (
+		req = require.New(t)
+		svc = &session{}
+		ctx = context.Background()
+	)
+
+	err := svc.restore(ctx, &types.Session{}, wfexec.NewGraph())
+	req.EqualError(err, "session state not stored")
+
+	err = svc.restore(ctx, &types.Session{State: &types.SessionState{Error: wfexec.ErrSuspendedInLoop.Error()}}, wfexec.NewGraph())
+	req.EqualError(err, "session state not stored: "+wfexec.ErrSuspendedInLoop.Error())
+}
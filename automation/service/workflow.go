@@ -31,7 +31,8 @@ type (
 		triggers  *trigger
 		session   *session
 
-		opt options.WorkflowOpt
+		opt    options.WorkflowOpt
+		limits WorkflowLimits
 
 		log *zap.Logger
 
@@ -49,6 +50,10 @@ type (
 		parser expr.Parsable
 	}
 
+	WorkflowLimits struct {
+		LimitWorkflows int
+	}
+
 	wfCacheItem struct {
 		wf *types.Workflow
 
@@ -95,10 +100,11 @@ const (
 	workflowDefChanged    workflowChanges = 4
 )
 
-func Workflow(log *zap.Logger, corredorOpt options.CorredorOpt, opt options.WorkflowOpt) *workflow {
+func Workflow(log *zap.Logger, corredorOpt options.CorredorOpt, opt options.WorkflowOpt, limits WorkflowLimits) *workflow {
 	return &workflow{
 		log:         log,
 		opt:         opt,
+		limits:      limits,
 		actionlog:   DefaultActionlog,
 		store:       DefaultStore,
 		ac:          DefaultAccessControl,
@@ -212,6 +218,10 @@ func (svc *workflow) Create(ctx context.Context, new *types.Workflow) (wf *types
 			return err
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return err
+		}
+
 		wf = &types.Workflow{
 			ID:           nextID(),
 			Handle:       new.Handle,
@@ -484,10 +494,34 @@ func (svc workflow) handleUndelete(ctx context.Context, res *types.Workflow) (wo
 		return workflowUnchanged, nil
 	}
 
+	if err := svc.checkLimits(ctx); err != nil {
+		return workflowUnchanged, err
+	}
+
 	res.DeletedAt = nil
 	return workflowChanged, nil
 }
 
+func (svc workflow) checkLimits(ctx context.Context) error {
+	if svc.limits.LimitWorkflows == 0 {
+		return nil
+	}
+
+	if c, err := countValidWorkflows(ctx, svc.store); err != nil {
+		return err
+	} else if c >= uint(svc.limits.LimitWorkflows) {
+		return WorkflowErrMaxWorkflowLimitReached()
+	}
+
+	return nil
+}
+
+// countValidWorkflows counts workflows that are not deleted;
+// disabled workflows are included
+func countValidWorkflows(ctx context.Context, s store.AutomationWorkflows) (c uint, err error) {
+	return store.CountAutomationWorkflows(ctx, s, types.WorkflowFilter{Disabled: filter.StateInclusive})
+}
+
 func (svc *workflow) Load(ctx context.Context) error {
 	var (
 		set, _, err = store.SearchAutomationWorkflows(ctx, svc.store, types.WorkflowFilter{
@@ -529,6 +563,18 @@ func (svc *workflow) updateCache(wf *types.Workflow, runAs intAuth.Identifiable,
 	return
 }
 
+// graph returns cached graph for the executable workflow
+func (svc *workflow) graph(workflowID uint64) *wfexec.Graph {
+	defer svc.mux.RUnlock()
+	svc.mux.RLock()
+
+	if c := svc.cache[workflowID]; c != nil {
+		return c.g
+	}
+
+	return nil
+}
+
 func (svc *workflow) Exec(ctx context.Context, workflowID uint64, p types.WorkflowExecParams) (*expr.Vars, types.Stacktrace, error) {
 	var (
 		wap     = &workflowActionProps{}
@@ -1015,6 +1015,40 @@ func WorkflowErrMaximumCallStackSizeExceeded(mm ...*workflowActionProps) *errors
 	return e
 }
 
+// WorkflowErrMaxWorkflowLimitReached returns "automation:workflow.maxWorkflowLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func WorkflowErrMaxWorkflowLimitReached(mm ...*workflowActionProps) *errors.Error {
+	var p = &workflowActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your workflow limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxWorkflowLimitReached"),
+		errors.Meta("resource", "automation:workflow"),
+
+		errors.Meta(workflowPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "automation"),
+		errors.Meta(locale.ErrorMetaKey{}, "workflow.errors.maxWorkflowLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
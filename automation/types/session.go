@@ -41,8 +41,17 @@ type (
 		CompletedAt *time.Time `json:"completedAt,omitempty"`
 		Error       string     `json:"error,omitempty"`
 
+		// Serialized state of the suspended session
+		//
+		// Set when session is prompted or delayed so that it
+		// can be restored after restart
+		State *SessionState `json:"-"`
+
 		session *wfexec.Session
 
+		// identities that are restored with the session
+		runner, invoker auth.Identifiable
+
 		// For keeping runtime stacktrace,
 		// even if we do not want to store it on every update
 		//
@@ -94,6 +103,25 @@ type (
 
 	Stacktrace []*wfexec.Frame
 
+	// SessionState holds everything that is needed to restore suspended session
+	SessionState struct {
+		Runner  SessionIdentity         `json:"runner"`
+		Invoker SessionIdentity         `json:"invoker"`
+		Session *wfexec.SessionSnapshot `json:"session"`
+
+		// Node that runs the suspended session
+		Node string `json:"node,omitempty"`
+
+		// Reason why snapshot of the session could not be made;
+		// session without snapshot can not be restored
+		Error string `json:"error,omitempty"`
+	}
+
+	SessionIdentity struct {
+		ID    uint64   `json:"id,string"`
+		Roles []uint64 `json:"roles,omitempty"`
+	}
+
 	SessionStatus uint
 )
 
@@ -105,10 +133,12 @@ const (
 	SessionCompleted
 )
 
-func NewSession(s *wfexec.Session) *Session {
+func NewSession(s *wfexec.Session, runner, invoker auth.Identifiable) *Session {
 	return &Session{
 		ID:      s.ID(),
 		session: s,
+		runner:  runner,
+		invoker: invoker,
 	}
 }
 
@@ -124,6 +154,49 @@ func (s *Session) PendingPrompts(ownerId uint64) []*wfexec.PendingPrompt {
 	return s.session.UserPendingPrompts(ownerId)
 }
 
+// Restore binds session, loaded from the store, with the restored wfexec session
+func (s *Session) Restore(ses *wfexec.Session, runner, invoker auth.Identifiable) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.session = ses
+	s.runner = runner
+	s.invoker = invoker
+}
+
+// Stop stops the session without changing its status
+//
+// Used when session was taken over by another node
+func (s *Session) Stop() {
+	s.session.Stop()
+}
+
+// Suspend makes a snapshot of the session state
+//
+// nodeID identifies the cluster node that keeps running the session
+//
+// When snapshot can not be made (e.g. session is suspended inside a loop)
+// state is kept without it, with the reason why the session can not be restored
+func (s *Session) Suspend(nodeID string) error {
+	snap, err := s.session.Snapshot()
+
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	s.State = &SessionState{
+		Runner:  makeSessionIdentity(s.runner),
+		Invoker: makeSessionIdentity(s.invoker),
+		Session: snap,
+		Node:    nodeID,
+	}
+
+	if err != nil {
+		s.State.Error = err.Error()
+	}
+
+	return err
+}
+
 func (s *Session) GC() bool {
 	s.l.RLock()
 	defer s.l.RUnlock()
@@ -181,6 +254,47 @@ func (s *Session) CopyRuntimeStacktrace() {
 	}
 }
 
+// RunnerIdentity returns identity restored from the session state
+func (ss SessionState) RunnerIdentity() auth.Identifiable {
+	return auth.Authenticated(ss.Runner.ID, ss.Runner.Roles...)
+}
+
+// InvokerIdentity returns identity restored from the session state
+func (ss SessionState) InvokerIdentity() auth.Identifiable {
+	return auth.Authenticated(ss.Invoker.ID, ss.Invoker.Roles...)
+}
+
+func (ss *SessionState) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*ss = SessionState{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, ss); err != nil {
+			return fmt.Errorf("cannot scan '%v' into SessionState: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (ss *SessionState) Value() (driver.Value, error) {
+	if ss == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(ss)
+}
+
+func makeSessionIdentity(i auth.Identifiable) SessionIdentity {
+	if i == nil {
+		return SessionIdentity{}
+	}
+
+	return SessionIdentity{ID: i.Identity(), Roles: i.Roles()}
+}
+
 func (set *Stacktrace) Scan(value interface{}) error {
 	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
 	switch value.(type) {
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func Rollups(ctx context.Context, app serviceInitializer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rollups",
+		Aliases: []string{"rollup"},
+		Short:   "Rollup field management",
+	}
+
+	cmd.AddCommand(rollupsRecompute(ctx, app))
+
+	return cmd
+}
+
+func rollupsRecompute(ctx context.Context, app serviceInitializer) *cobra.Command {
+	return &cobra.Command{
+		Use:     "recompute [namespace-ID-or-slug] [module-ID-or-handle]",
+		Short:   "Recompute rollup fields on all records of the module",
+		Long:    "Recomputes values of all rollup fields on all records of the module; use after rollup field definitions are changed",
+		Args:    cobra.ExactArgs(2),
+		PreRunE: commandPreRunInitService(app),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx = auth.SetIdentityToContext(ctx, auth.ServiceUser())
+
+			ns, err := service.DefaultNamespace.FindByAny(ctx, args[0])
+			cli.HandleError(err)
+
+			m, err := service.DefaultModule.FindByAny(ctx, ns.ID, args[1])
+			cli.HandleError(err)
+
+			n, err := service.DefaultRecord.RecomputeRollups(ctx, ns.ID, m.ID)
+			cli.HandleError(err)
+
+			cmd.Printf("Recomputed rollup fields on %d record(s) of module [%d] %q\n", n, m.ID, m.Handle)
+		},
+	}
+}
@@ -0,0 +1,184 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	systemTypes "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	// loader batches lookups of related records and users
+	//
+	// Resolvers queue IDs and return thunks; GraphQL executor
+	// resolves thunks after all fields on the same level are
+	// visited so all queued IDs can be loaded with a single query.
+	//
+	// Loaded resources are cached for the duration of the request.
+	// Resources that were not found (or are not accessible) are
+	// cached as nil
+	loader struct {
+		ctx context.Context
+		svc *service
+
+		records        map[uint64]map[uint64]*types.Record
+		pendingRecords map[uint64][]uint64
+
+		users        map[uint64]*systemTypes.User
+		pendingUsers []uint64
+	}
+)
+
+const (
+	// max number of IDs loaded in one query
+	loaderBatchSize = 100
+)
+
+func newLoader(ctx context.Context, svc *service) *loader {
+	return &loader{
+		ctx:            ctx,
+		svc:            svc,
+		records:        make(map[uint64]map[uint64]*types.Record),
+		pendingRecords: make(map[uint64][]uint64),
+		users:          make(map[uint64]*systemTypes.User),
+	}
+}
+
+// prime adds already loaded records to the cache
+func (l *loader) prime(rr ...*types.Record) {
+	for _, r := range rr {
+		if l.records[r.ModuleID] == nil {
+			l.records[r.ModuleID] = make(map[uint64]*types.Record)
+		}
+
+		l.records[r.ModuleID][r.ID] = r
+	}
+}
+
+// recordsThunk queues records for loading and returns function that returns them
+func (l *loader) recordsThunk(moduleID uint64, IDs []uint64) func() (types.RecordSet, error) {
+	if l.records[moduleID] == nil {
+		l.records[moduleID] = make(map[uint64]*types.Record)
+	}
+
+	for _, ID := range IDs {
+		if _, has := l.records[moduleID][ID]; has {
+			// loaded or pending
+			continue
+		}
+
+		l.records[moduleID][ID] = nil
+		l.pendingRecords[moduleID] = append(l.pendingRecords[moduleID], ID)
+	}
+
+	return func() (out types.RecordSet, err error) {
+		if err = l.loadRecords(moduleID); err != nil {
+			return
+		}
+
+		out = make(types.RecordSet, 0, len(IDs))
+		for _, ID := range IDs {
+			if r := l.records[moduleID][ID]; r != nil {
+				out = append(out, r)
+			}
+		}
+
+		return
+	}
+}
+
+// usersThunk queues users for loading and returns function that returns them
+func (l *loader) usersThunk(IDs []uint64) func() (systemTypes.UserSet, error) {
+	for _, ID := range IDs {
+		if _, has := l.users[ID]; has {
+			continue
+		}
+
+		l.users[ID] = nil
+		l.pendingUsers = append(l.pendingUsers, ID)
+	}
+
+	return func() (out systemTypes.UserSet, err error) {
+		if err = l.loadUsers(); err != nil {
+			return
+		}
+
+		out = make(systemTypes.UserSet, 0, len(IDs))
+		for _, ID := range IDs {
+			if u := l.users[ID]; u != nil {
+				out = append(out, u)
+			}
+		}
+
+		return
+	}
+}
+
+// loadRecords loads all pending records of a module
+//
+// Records are loaded through record service (and not directly from the store)
+// so that record and field-level access control is applied
+func (l *loader) loadRecords(moduleID uint64) error {
+	var (
+		IDs = l.pendingRecords[moduleID]
+	)
+
+	delete(l.pendingRecords, moduleID)
+
+	for _, chunk := range chunks(IDs) {
+		cnd := make([]string, len(chunk))
+		for i, ID := range chunk {
+			cnd[i] = fmt.Sprintf("recordID = %d", ID)
+		}
+
+		rr, _, err := l.svc.record.Find(l.ctx, types.RecordFilter{
+			ModuleID: moduleID,
+			Query:    strings.Join(cnd, " OR "),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		l.prime(rr...)
+	}
+
+	return nil
+}
+
+// loadUsers loads all pending users
+func (l *loader) loadUsers() error {
+	var (
+		IDs = l.pendingUsers
+	)
+
+	l.pendingUsers = nil
+
+	for _, chunk := range chunks(IDs) {
+		uu, _, err := l.svc.user.Find(l.ctx, systemTypes.UserFilter{UserID: chunk, AllKinds: true})
+		if err != nil {
+			return err
+		}
+
+		for _, u := range uu {
+			l.users[u.ID] = u
+		}
+	}
+
+	return nil
+}
+
+func chunks(IDs []uint64) (out [][]uint64) {
+	for len(IDs) > loaderBatchSize {
+		out = append(out, IDs[:loaderBatchSize])
+		IDs = IDs[loaderBatchSize:]
+	}
+
+	if len(IDs) > 0 {
+		out = append(out, IDs)
+	}
+
+	return
+}
@@ -0,0 +1,751 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	systemTypes "github.com/cortezaproject/corteza-server/system/types"
+	gql "github.com/graphql-go/graphql"
+)
+
+type (
+	schemaBuilder struct {
+		namespaceID uint64
+
+		// modules in the order they are added to the schema
+		modules []*moduleDef
+
+		// modules indexed by ID, used to resolve relations
+		byID map[uint64]*moduleDef
+
+		user   *gql.Object
+		module *gql.Object
+	}
+
+	moduleDef struct {
+		module *types.Module
+
+		// lower camel case name used for queries
+		name string
+
+		// upper camel case name used for mutations
+		pascal string
+
+		// name of the record type
+		typeName string
+
+		// maps GraphQL field identifiers to module field names
+		fields map[string]*types.ModuleField
+
+		object *gql.Object
+		page   *gql.Object
+		input  *gql.InputObject
+	}
+)
+
+func newSchemaBuilder(namespaceID uint64, mm types.ModuleSet) *schemaBuilder {
+	var (
+		b = &schemaBuilder{
+			namespaceID: namespaceID,
+			byID:        make(map[uint64]*moduleDef),
+		}
+
+		// query names already used
+		used = map[string]bool{"modules": true}
+	)
+
+	// order modules by ID so that name collisions
+	// are always resolved in the same way
+	mm = append(types.ModuleSet{}, mm...)
+	sort.Slice(mm, func(i, j int) bool { return mm[i].ID < mm[j].ID })
+
+	for _, m := range mm {
+		def := &moduleDef{module: m, fields: make(map[string]*types.ModuleField)}
+		def.name, def.pascal = moduleNames(m)
+		def.typeName = def.pascal + "Record"
+
+		if used[def.name] || used[def.name+"List"] {
+			// skip modules with handles that
+			// collide with already added modules
+			continue
+		}
+
+		used[def.name] = true
+		used[def.name+"List"] = true
+
+		for _, f := range m.Fields {
+			def.fields[identifier(f.Name)] = f
+		}
+
+		b.modules = append(b.modules, def)
+		b.byID[m.ID] = def
+	}
+
+	return b
+}
+
+// build creates GraphQL schema
+//
+// Each module gets its own type with the system record fields
+// and one field per module field. Module types reference each other
+// (via Record fields) so fields are defined lazily
+func (b *schemaBuilder) build() (gql.Schema, error) {
+	var (
+		query    = gql.Fields{}
+		mutation = gql.Fields{}
+	)
+
+	b.user = gql.NewObject(gql.ObjectConfig{
+		Name: "User",
+		Fields: gql.Fields{
+			"userID": &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: resolveUserProp(func(u *systemTypes.User) interface{} { return strconv.FormatUint(u.ID, 10) })},
+			"handle": &gql.Field{Type: gql.String, Resolve: resolveUserProp(func(u *systemTypes.User) interface{} { return u.Handle })},
+			"name":   &gql.Field{Type: gql.String, Resolve: resolveUserProp(func(u *systemTypes.User) interface{} { return u.Name })},
+			"email":  &gql.Field{Type: gql.String, Resolve: resolveUserProp(func(u *systemTypes.User) interface{} { return u.Email })},
+		},
+	})
+
+	b.module = gql.NewObject(gql.ObjectConfig{
+		Name:        "Module",
+		Description: "Module that is available in the schema",
+		Fields: gql.Fields{
+			"moduleID": &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: resolveModuleProp(func(d *moduleDef) interface{} { return strconv.FormatUint(d.module.ID, 10) })},
+			"handle":   &gql.Field{Type: gql.String, Resolve: resolveModuleProp(func(d *moduleDef) interface{} { return d.module.Handle })},
+			"name":     &gql.Field{Type: gql.String, Resolve: resolveModuleProp(func(d *moduleDef) interface{} { return d.module.Name })},
+			"type":     &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: resolveModuleProp(func(d *moduleDef) interface{} { return d.typeName })},
+		},
+	})
+
+	query["modules"] = &gql.Field{
+		Type:        gql.NewNonNull(gql.NewList(gql.NewNonNull(b.module))),
+		Description: "List of modules available in the schema",
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return b.modules, nil
+		},
+	}
+
+	for _, def := range b.modules {
+		b.defineTypes(def)
+	}
+
+	for _, def := range b.modules {
+		b.defineQueries(def, query)
+		b.defineMutations(def, mutation)
+	}
+
+	cfg := gql.SchemaConfig{
+		Query: gql.NewObject(gql.ObjectConfig{Name: "Query", Fields: query}),
+	}
+
+	if len(mutation) > 0 {
+		cfg.Mutation = gql.NewObject(gql.ObjectConfig{Name: "Mutation", Fields: mutation})
+	}
+
+	return gql.NewSchema(cfg)
+}
+
+func (b *schemaBuilder) defineTypes(def *moduleDef) {
+	def.object = gql.NewObject(gql.ObjectConfig{
+		Name:        def.typeName,
+		Description: fmt.Sprintf("Records of module %q", def.module.Name),
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			ff := gql.Fields{
+				"recordID":  &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: resolveRecordProp(func(r *types.Record) interface{} { return strconv.FormatUint(r.ID, 10) })},
+				"moduleID":  &gql.Field{Type: gql.NewNonNull(gql.ID), Resolve: resolveRecordProp(func(r *types.Record) interface{} { return strconv.FormatUint(r.ModuleID, 10) })},
+				"createdAt": &gql.Field{Type: gql.String, Resolve: resolveRecordProp(func(r *types.Record) interface{} { return formatTime(&r.CreatedAt) })},
+				"updatedAt": &gql.Field{Type: gql.String, Resolve: resolveRecordProp(func(r *types.Record) interface{} { return formatTime(r.UpdatedAt) })},
+				"ownedBy":   &gql.Field{Type: b.user, Resolve: resolveUserRef(func(r *types.Record) uint64 { return r.OwnedBy })},
+				"createdBy": &gql.Field{Type: b.user, Resolve: resolveUserRef(func(r *types.Record) uint64 { return r.CreatedBy })},
+				"updatedBy": &gql.Field{Type: b.user, Resolve: resolveUserRef(func(r *types.Record) uint64 { return r.UpdatedBy })},
+			}
+
+			for name, f := range def.fields {
+				if _, has := ff[name]; has {
+					continue
+				}
+
+				ff[name] = b.valueField(f)
+			}
+
+			return ff
+		}),
+	})
+
+	def.page = gql.NewObject(gql.ObjectConfig{
+		Name: def.typeName + "Page",
+		Fields: gql.Fields{
+			"records": &gql.Field{
+				Type: gql.NewNonNull(gql.NewList(gql.NewNonNull(def.object))),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return p.Source.(*recordPage).set, nil
+				},
+			},
+			"total": &gql.Field{
+				Type: gql.Int,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return int(p.Source.(*recordPage).filter.Total), nil
+				},
+			},
+			"nextPage": &gql.Field{
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return encodeCursor(p.Source.(*recordPage).filter.NextPage), nil
+				},
+			},
+			"prevPage": &gql.Field{
+				Type: gql.String,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return encodeCursor(p.Source.(*recordPage).filter.PrevPage), nil
+				},
+			},
+		},
+	})
+
+	inputFields := gql.InputObjectConfigFieldMap{}
+	for name, f := range def.fields {
+		var typ gql.Input = scalarType(f)
+		if f.Multi {
+			typ = gql.NewList(typ)
+		}
+
+		inputFields[name] = &gql.InputObjectFieldConfig{Type: typ}
+	}
+
+	if len(inputFields) > 0 {
+		def.input = gql.NewInputObject(gql.InputObjectConfig{
+			Name:   def.typeName + "Input",
+			Fields: inputFields,
+		})
+	}
+}
+
+// valueField creates GraphQL field for module field
+//
+// Record and User fields are resolved into related records and users,
+// all other kinds are resolved as scalars
+func (b *schemaBuilder) valueField(f *types.ModuleField) *gql.Field {
+	var (
+		out = &gql.Field{Description: f.Label}
+	)
+
+	switch {
+	case f.Kind == "Record" && b.byID[f.Options.UInt64("moduleID")] != nil:
+		out.Type = b.byID[f.Options.UInt64("moduleID")].object
+		out.Resolve = resolveRecordValueRef(f, f.Options.UInt64("moduleID"))
+
+	case f.Kind == "User":
+		out.Type = b.user
+		out.Resolve = resolveUserValueRef(f)
+
+	default:
+		out.Type = scalarType(f)
+		out.Resolve = resolveScalarValue(f)
+	}
+
+	if f.Multi {
+		out.Type = gql.NewList(out.Type)
+	}
+
+	return out
+}
+
+func (b *schemaBuilder) defineQueries(def *moduleDef, query gql.Fields) {
+	query[def.name] = &gql.Field{
+		Type:        def.object,
+		Description: fmt.Sprintf("Find record of module %q by ID", def.module.Name),
+		Args: gql.FieldConfigArgument{
+			"recordID": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+		},
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			recordID, err := argID(p.Args, "recordID")
+			if err != nil {
+				return nil, err
+			}
+
+			// single record lookup uses the loader as well
+			// so that multiple lookups are batched
+			load := loaderFromContext(p.Context).recordsThunk(def.module.ID, []uint64{recordID})
+			return func() (interface{}, error) {
+				rr, err := load()
+				if err != nil {
+					return nil, err
+				}
+
+				return first(false, len(rr), rr), nil
+			}, nil
+		},
+	}
+
+	query[def.name+"List"] = &gql.Field{
+		Type:        gql.NewNonNull(def.page),
+		Description: fmt.Sprintf("Search records of module %q", def.module.Name),
+		Args: gql.FieldConfigArgument{
+			"query":      &gql.ArgumentConfig{Type: gql.String, Description: "Record filter, same as on REST API"},
+			"sort":       &gql.ArgumentConfig{Type: gql.String},
+			"limit":      &gql.ArgumentConfig{Type: gql.Int},
+			"pageCursor": &gql.ArgumentConfig{Type: gql.String},
+			"incTotal":   &gql.ArgumentConfig{Type: gql.Boolean},
+		},
+		Resolve: func(p gql.ResolveParams) (_ interface{}, err error) {
+			var (
+				l = loaderFromContext(p.Context)
+				f = types.RecordFilter{
+					NamespaceID: b.namespaceID,
+					ModuleID:    def.module.ID,
+				}
+
+				limit, _  = p.Args["limit"].(int)
+				cursor, _ = p.Args["pageCursor"].(string)
+				srt, _    = p.Args["sort"].(string)
+			)
+
+			f.Query, _ = p.Args["query"].(string)
+			if limit < 0 {
+				limit = 0
+			}
+
+			if f.Paging, err = filter.NewPaging(uint(limit), cursor); err != nil {
+				return nil, err
+			}
+
+			f.IncTotal, _ = p.Args["incTotal"].(bool)
+
+			if f.Sorting, err = filter.NewSorting(srt); err != nil {
+				return nil, err
+			}
+
+			set, f, err := l.svc.record.Find(p.Context, f)
+			if err != nil {
+				return nil, err
+			}
+
+			l.prime(set...)
+			return &recordPage{set: set, filter: f}, nil
+		},
+	}
+}
+
+func (b *schemaBuilder) defineMutations(def *moduleDef, mutation gql.Fields) {
+	var (
+		valuesArg = gql.FieldConfigArgument{}
+	)
+
+	if def.input != nil {
+		valuesArg["values"] = &gql.ArgumentConfig{Type: gql.NewNonNull(def.input)}
+	}
+
+	mutation["create"+def.pascal] = &gql.Field{
+		Type:        def.object,
+		Description: fmt.Sprintf("Create record of module %q", def.module.Name),
+		Args:        valuesArg,
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			var (
+				l   = loaderFromContext(p.Context)
+				rec = &types.Record{
+					NamespaceID: b.namespaceID,
+					ModuleID:    def.module.ID,
+					Values:      def.inputValues(p.Args["values"], nil),
+				}
+			)
+
+			rec, err := l.svc.record.Create(p.Context, rec)
+			if err != nil {
+				return nil, err
+			}
+
+			l.prime(rec)
+			return rec, nil
+		},
+	}
+
+	updateArgs := gql.FieldConfigArgument{
+		"recordID": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+	}
+
+	for k, v := range valuesArg {
+		updateArgs[k] = v
+	}
+
+	mutation["update"+def.pascal] = &gql.Field{
+		Type:        def.object,
+		Description: fmt.Sprintf("Update record of module %q; fields that are not provided are left unchanged", def.module.Name),
+		Args:        updateArgs,
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			var (
+				l = loaderFromContext(p.Context)
+			)
+
+			recordID, err := argID(p.Args, "recordID")
+			if err != nil {
+				return nil, err
+			}
+
+			old, err := l.svc.record.FindByID(p.Context, b.namespaceID, def.module.ID, recordID)
+			if err != nil {
+				return nil, err
+			}
+
+			rec, err := l.svc.record.Update(p.Context, &types.Record{
+				ID:          old.ID,
+				NamespaceID: old.NamespaceID,
+				ModuleID:    old.ModuleID,
+				OwnedBy:     old.OwnedBy,
+				Labels:      old.Labels,
+				UpdatedAt:   old.UpdatedAt,
+				Values:      def.inputValues(p.Args["values"], old.Values),
+			})
+
+			if err != nil {
+				return nil, err
+			}
+
+			l.prime(rec)
+			return rec, nil
+		},
+	}
+
+	mutation["delete"+def.pascal] = &gql.Field{
+		Type:        gql.Boolean,
+		Description: fmt.Sprintf("Delete record of module %q", def.module.Name),
+		Args: gql.FieldConfigArgument{
+			"recordID": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)},
+		},
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			recordID, err := argID(p.Args, "recordID")
+			if err != nil {
+				return nil, err
+			}
+
+			if err = loaderFromContext(p.Context).svc.record.DeleteByID(p.Context, b.namespaceID, def.module.ID, recordID); err != nil {
+				return nil, err
+			}
+
+			return true, nil
+		},
+	}
+}
+
+// inputValues converts GraphQL input into record values
+//
+// Values of fields that are not part of the input are copied from the existing values
+func (def *moduleDef) inputValues(input interface{}, existing types.RecordValueSet) (out types.RecordValueSet) {
+	var (
+		in, _ = input.(map[string]interface{})
+		set   = make(map[string]bool)
+	)
+
+	out = types.RecordValueSet{}
+
+	for name, raw := range in {
+		f := def.fields[name]
+		if f == nil {
+			continue
+		}
+
+		set[f.Name] = true
+
+		vv, is := raw.([]interface{})
+		if !is {
+			vv = []interface{}{raw}
+		}
+
+		place := uint(0)
+		for _, v := range vv {
+			if v == nil {
+				continue
+			}
+
+			out = append(out, &types.RecordValue{Name: f.Name, Value: inputValue(v), Place: place})
+			place++
+		}
+	}
+
+	for _, v := range existing {
+		if !set[v.Name] {
+			out = append(out, v)
+		}
+	}
+
+	return
+}
+
+type (
+	recordPage struct {
+		set    types.RecordSet
+		filter types.RecordFilter
+	}
+)
+
+// scalarType returns GraphQL type for values of the field
+func scalarType(f *types.ModuleField) *gql.Scalar {
+	switch f.Kind {
+	case "Bool":
+		return gql.Boolean
+	case "Number":
+		return gql.Float
+	case "Record", "User", "File":
+		return gql.ID
+	default:
+		return gql.String
+	}
+}
+
+// scalarValue converts raw record value into value of the field's GraphQL type
+func scalarValue(f *types.ModuleField, v string) interface{} {
+	switch f.Kind {
+	case "Bool":
+		return v == "1" || v == "true"
+	case "Number":
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+
+		return n
+	default:
+		return v
+	}
+}
+
+// inputValue converts GraphQL input value into raw record value
+func inputValue(v interface{}) string {
+	switch c := v.(type) {
+	case bool:
+		if c {
+			return "1"
+		}
+
+		return "0"
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(c)
+	case string:
+		return c
+	default:
+		return fmt.Sprintf("%v", c)
+	}
+}
+
+// fieldValues returns record values of the field ordered by place
+func fieldValues(r *types.Record, f *types.ModuleField) types.RecordValueSet {
+	vv := r.Values.FilterByName(f.Name)
+	sort.Sort(vv)
+	return vv
+}
+
+// refs returns IDs from record values
+func refs(vv types.RecordValueSet) (IDs []uint64) {
+	for _, v := range vv {
+		if ID, err := strconv.ParseUint(v.Value, 10, 64); err == nil && ID > 0 {
+			IDs = append(IDs, ID)
+		}
+	}
+
+	return
+}
+
+func resolveScalarValue(f *types.ModuleField) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		r, ok := p.Source.(*types.Record)
+		if !ok {
+			return nil, nil
+		}
+
+		vv := fieldValues(r, f)
+		if f.Multi {
+			out := make([]interface{}, len(vv))
+			for i, v := range vv {
+				out[i] = scalarValue(f, v.Value)
+			}
+
+			return out, nil
+		}
+
+		if len(vv) == 0 {
+			return nil, nil
+		}
+
+		return scalarValue(f, vv[0].Value), nil
+	}
+}
+
+func resolveRecordValueRef(f *types.ModuleField, moduleID uint64) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		r, ok := p.Source.(*types.Record)
+		if !ok {
+			return nil, nil
+		}
+
+		load := loaderFromContext(p.Context).recordsThunk(moduleID, refs(fieldValues(r, f)))
+		return func() (interface{}, error) {
+			rr, err := load()
+			if err != nil {
+				return nil, err
+			}
+
+			return first(f.Multi, len(rr), rr), nil
+		}, nil
+	}
+}
+
+func resolveUserValueRef(f *types.ModuleField) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		r, ok := p.Source.(*types.Record)
+		if !ok {
+			return nil, nil
+		}
+
+		return userThunk(p, f.Multi, refs(fieldValues(r, f))), nil
+	}
+}
+
+func resolveUserRef(fn func(*types.Record) uint64) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		r, ok := p.Source.(*types.Record)
+		if !ok || fn(r) == 0 {
+			return nil, nil
+		}
+
+		return userThunk(p, false, []uint64{fn(r)}), nil
+	}
+}
+
+func userThunk(p gql.ResolveParams, multi bool, IDs []uint64) func() (interface{}, error) {
+	load := loaderFromContext(p.Context).usersThunk(IDs)
+	return func() (interface{}, error) {
+		uu, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		return first(multi, len(uu), uu), nil
+	}
+}
+
+// first returns the whole set for multi-value fields
+// and first element (or nil) for single-value fields
+func first(multi bool, l int, set interface{}) interface{} {
+	switch {
+	case multi:
+		return set
+	case l == 0:
+		return nil
+	}
+
+	switch s := set.(type) {
+	case types.RecordSet:
+		return s[0]
+	case systemTypes.UserSet:
+		return s[0]
+	}
+
+	return nil
+}
+
+func resolveRecordProp(fn func(*types.Record) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		if r, ok := p.Source.(*types.Record); ok {
+			return fn(r), nil
+		}
+
+		return nil, nil
+	}
+}
+
+func resolveUserProp(fn func(*systemTypes.User) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		if u, ok := p.Source.(*systemTypes.User); ok {
+			return fn(u), nil
+		}
+
+		return nil, nil
+	}
+}
+
+func resolveModuleProp(fn func(*moduleDef) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		if d, ok := p.Source.(*moduleDef); ok {
+			return fn(d), nil
+		}
+
+		return nil, nil
+	}
+}
+
+func argID(args map[string]interface{}, name string) (uint64, error) {
+	raw, _ := args[name].(string)
+	ID, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || ID == 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+
+	return ID, nil
+}
+
+func formatTime(t *time.Time) interface{} {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func encodeCursor(c *filter.PagingCursor) interface{} {
+	if c == nil {
+		return nil
+	}
+
+	return strings.Trim(c.Encode(), `"`)
+}
+
+// moduleNames returns lower and upper camel case names for the module
+//
+// Names are derived from the module handle; modules without handle use their ID
+func moduleNames(m *types.Module) (name, pascal string) {
+	if m.Handle == "" {
+		return fmt.Sprintf("module%d", m.ID), fmt.Sprintf("Module%d", m.ID)
+	}
+
+	parts := strings.FieldsFunc(m.Handle, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i := range parts {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+
+	pascal = identifier(strings.Join(parts, ""))
+	name = strings.ToLower(pascal[:1]) + pascal[1:]
+	return
+}
+
+// identifier replaces all characters that are not allowed in GraphQL names
+func identifier(s string) string {
+	var (
+		b strings.Builder
+	)
+
+	for i, r := range s {
+		switch {
+		case r == '_', r < unicode.MaxASCII && unicode.IsLetter(r):
+			b.WriteRune(r)
+		case r < unicode.MaxASCII && unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteRune('_')
+			}
+
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	return b.String()
+}
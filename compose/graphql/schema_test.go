@@ -0,0 +1,130 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	systemTypes "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	mockRecordService struct {
+		recordService
+		set   types.RecordSet
+		finds int
+	}
+
+	mockModuleService struct {
+		set types.ModuleSet
+	}
+
+	mockUserService struct {
+		userService
+	}
+)
+
+func (svc *mockRecordService) Find(_ context.Context, f types.RecordFilter) (out types.RecordSet, _ types.RecordFilter, _ error) {
+	svc.finds++
+	for _, r := range svc.set {
+		if r.ModuleID == f.ModuleID {
+			out = append(out, r)
+		}
+	}
+
+	return out, f, nil
+}
+
+func (svc mockModuleService) Find(_ context.Context, f types.ModuleFilter) (types.ModuleSet, types.ModuleFilter, error) {
+	return svc.set, f, nil
+}
+
+func (mockUserService) Find(_ context.Context, f systemTypes.UserFilter) (systemTypes.UserSet, systemTypes.UserFilter, error) {
+	return nil, f, nil
+}
+
+func TestModuleNames(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	tcc := []struct {
+		handle string
+		name   string
+		pascal string
+	}{
+		{"account", "account", "Account"},
+		{"order_line", "orderLine", "OrderLine"},
+		{"Lead.Source-2", "leadSource2", "LeadSource2"},
+		{"", "module42", "Module42"},
+	}
+
+	for _, tc := range tcc {
+		name, pascal := moduleNames(&types.Module{ID: 42, Handle: tc.handle})
+		req.Equal(tc.name, name)
+		req.Equal(tc.pascal, pascal)
+	}
+}
+
+func TestSchema_Collisions(t *testing.T) {
+	var (
+		req = require.New(t)
+		b   = newSchemaBuilder(1, types.ModuleSet{
+			{ID: 3, Handle: "account_list"},
+			{ID: 1, Handle: "account"},
+			{ID: 2, Handle: "Account"},
+			{ID: 4, Handle: "modules"},
+		})
+	)
+
+	req.Len(b.modules, 1)
+	req.Equal(uint64(1), b.modules[0].module.ID)
+
+	_, err := b.build()
+	req.NoError(err)
+}
+
+func TestSchema_RelationsBatched(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+
+		author = &types.Module{ID: 1, Handle: "author", Fields: types.ModuleFieldSet{
+			{Name: "name", Kind: "String"},
+		}}
+
+		book = &types.Module{ID: 2, Handle: "book", Fields: types.ModuleFieldSet{
+			{Name: "title", Kind: "String"},
+			{Name: "authors", Kind: "Record", Multi: true, Options: types.ModuleFieldOptions{"moduleID": "1"}},
+		}}
+
+		rs = &mockRecordService{set: types.RecordSet{
+			{ID: 10, ModuleID: 1, Values: types.RecordValueSet{{Name: "name", Value: "Jane"}}},
+			{ID: 11, ModuleID: 1, Values: types.RecordValueSet{{Name: "name", Value: "John"}}},
+			{ID: 20, ModuleID: 2, Values: types.RecordValueSet{
+				{Name: "title", Value: "First"},
+				{Name: "authors", Value: "10", Place: 0},
+				{Name: "authors", Value: "11", Place: 1},
+			}},
+			{ID: 21, ModuleID: 2, Values: types.RecordValueSet{
+				{Name: "title", Value: "Second"},
+				{Name: "authors", Value: "11"},
+			}},
+		}}
+
+		svc = NewService(rs, mockModuleService{set: types.ModuleSet{author, book}}, mockUserService{})
+	)
+
+	res, err := svc.Exec(ctx, 1, `{ bookList { records { title authors { name } } } }`, "", nil)
+	req.NoError(err)
+	req.Empty(res.Errors)
+
+	// one for the list, one for all related authors
+	req.Equal(2, rs.finds)
+	req.Equal(
+		`map[bookList:map[records:[map[authors:[map[name:Jane] map[name:John]] title:First] map[authors:[map[name:John]] title:Second]]]]`,
+		fmt.Sprintf("%v", res.Data),
+	)
+}
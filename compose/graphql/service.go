@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	systemTypes "github.com/cortezaproject/corteza-server/system/types"
+	gql "github.com/graphql-go/graphql"
+)
+
+type (
+	recordService interface {
+		FindByID(ctx context.Context, namespaceID, moduleID, recordID uint64) (*types.Record, error)
+		Find(ctx context.Context, filter types.RecordFilter) (types.RecordSet, types.RecordFilter, error)
+		Create(ctx context.Context, record *types.Record) (*types.Record, error)
+		Update(ctx context.Context, record *types.Record) (*types.Record, error)
+		DeleteByID(ctx context.Context, namespaceID, moduleID uint64, recordID ...uint64) error
+	}
+
+	moduleService interface {
+		Find(ctx context.Context, filter types.ModuleFilter) (types.ModuleSet, types.ModuleFilter, error)
+	}
+
+	userService interface {
+		Find(ctx context.Context, filter systemTypes.UserFilter) (systemTypes.UserSet, systemTypes.UserFilter, error)
+	}
+
+	service struct {
+		record recordService
+		module moduleService
+		user   userService
+	}
+
+	loaderCtxKey struct{}
+)
+
+// NewService initializes GraphQL service
+//
+// All reads and writes are done through the given services so that
+// access control (including field-level permissions) is applied
+// exactly the same way as on the REST API
+func NewService(record recordService, module moduleService, user userService) *service {
+	return &service{
+		record: record,
+		module: module,
+		user:   user,
+	}
+}
+
+// Schema builds GraphQL schema from all modules in the namespace
+// that are accessible to the current user
+func (svc *service) Schema(ctx context.Context, namespaceID uint64) (gql.Schema, error) {
+	mm, _, err := svc.module.Find(ctx, types.ModuleFilter{NamespaceID: namespaceID})
+	if err != nil {
+		return gql.Schema{}, err
+	}
+
+	return newSchemaBuilder(namespaceID, mm).build()
+}
+
+// Exec executes GraphQL query (or mutation) against the namespace schema
+//
+// Errors returned by the resolvers are part of the result, only errors
+// that prevent the schema from being built are returned directly
+func (svc *service) Exec(ctx context.Context, namespaceID uint64, query, operationName string, variables map[string]interface{}) (*gql.Result, error) {
+	schema, err := svc.Schema(ctx, namespaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return gql.Do(gql.Params{
+		Schema:         schema,
+		RequestString:  query,
+		OperationName:  operationName,
+		VariableValues: variables,
+		Context:        context.WithValue(ctx, loaderCtxKey{}, newLoader(ctx, svc)),
+	}), nil
+}
+
+// loaderFromContext returns per-request loader
+func loaderFromContext(ctx context.Context) *loader {
+	l, _ := ctx.Value(loaderCtxKey{}).(*loader)
+	return l
+}
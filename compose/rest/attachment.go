@@ -146,6 +146,10 @@ func (ctrl Attachment) serve(ctx context.Context, namespaceID, attachmentID uint
 			w.Header().Add("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
 		}
 
+		if c, ok := fh.(io.Closer); ok {
+			defer c.Close()
+		}
+
 		http.ServeContent(w, req, name, att.CreatedAt, fh)
 	}, nil
 }
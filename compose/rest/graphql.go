@@ -0,0 +1,45 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cortezaproject/corteza-server/compose/graphql"
+	"github.com/cortezaproject/corteza-server/compose/rest/request"
+	"github.com/cortezaproject/corteza-server/compose/service"
+	systemService "github.com/cortezaproject/corteza-server/system/service"
+	gql "github.com/graphql-go/graphql"
+)
+
+type (
+	GraphQL struct {
+		svc graphQLService
+	}
+
+	graphQLService interface {
+		Exec(ctx context.Context, namespaceID uint64, query, operationName string, variables map[string]interface{}) (*gql.Result, error)
+	}
+)
+
+func (GraphQL) New() *GraphQL {
+	return &GraphQL{
+		svc: graphql.NewService(service.DefaultRecord, service.DefaultModule, systemService.DefaultUser),
+	}
+}
+
+// Query executes GraphQL query
+//
+// Result is sent as-is (without the usual response wrapper)
+// so that it can be consumed by the standard GraphQL clients
+func (ctrl *GraphQL) Query(ctx context.Context, r *request.GraphqlQuery) (interface{}, error) {
+	res, err := ctrl.svc.Exec(ctx, r.NamespaceID, r.Query, r.OperationName, r.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	}, nil
+}
@@ -25,6 +25,8 @@ type (
 		ImportRun(context.Context, *request.RecordImportRun) (interface{}, error)
 		ImportProgress(context.Context, *request.RecordImportProgress) (interface{}, error)
 		Export(context.Context, *request.RecordExport) (interface{}, error)
+		StreamExport(context.Context, *request.RecordStreamExport) (interface{}, error)
+		StreamImport(context.Context, *request.RecordStreamImport) (interface{}, error)
 		Exec(context.Context, *request.RecordExec) (interface{}, error)
 		Create(context.Context, *request.RecordCreate) (interface{}, error)
 		Read(context.Context, *request.RecordRead) (interface{}, error)
@@ -32,6 +34,13 @@ type (
 		BulkDelete(context.Context, *request.RecordBulkDelete) (interface{}, error)
 		Delete(context.Context, *request.RecordDelete) (interface{}, error)
 		Upload(context.Context, *request.RecordUpload) (interface{}, error)
+		Revisions(context.Context, *request.RecordRevisions) (interface{}, error)
+		RestoreRevision(context.Context, *request.RecordRestoreRevision) (interface{}, error)
+		Trash(context.Context, *request.RecordTrash) (interface{}, error)
+		BulkUndelete(context.Context, *request.RecordBulkUndelete) (interface{}, error)
+		BulkPurge(context.Context, *request.RecordBulkPurge) (interface{}, error)
+		Undelete(context.Context, *request.RecordUndelete) (interface{}, error)
+		Purge(context.Context, *request.RecordPurge) (interface{}, error)
 		TriggerScript(context.Context, *request.RecordTriggerScript) (interface{}, error)
 		TriggerScriptOnList(context.Context, *request.RecordTriggerScriptOnList) (interface{}, error)
 	}
@@ -44,6 +53,8 @@ type (
 		ImportRun           func(http.ResponseWriter, *http.Request)
 		ImportProgress      func(http.ResponseWriter, *http.Request)
 		Export              func(http.ResponseWriter, *http.Request)
+		StreamExport        func(http.ResponseWriter, *http.Request)
+		StreamImport        func(http.ResponseWriter, *http.Request)
 		Exec                func(http.ResponseWriter, *http.Request)
 		Create              func(http.ResponseWriter, *http.Request)
 		Read                func(http.ResponseWriter, *http.Request)
@@ -51,6 +62,13 @@ type (
 		BulkDelete          func(http.ResponseWriter, *http.Request)
 		Delete              func(http.ResponseWriter, *http.Request)
 		Upload              func(http.ResponseWriter, *http.Request)
+		Revisions           func(http.ResponseWriter, *http.Request)
+		RestoreRevision     func(http.ResponseWriter, *http.Request)
+		Trash               func(http.ResponseWriter, *http.Request)
+		BulkUndelete        func(http.ResponseWriter, *http.Request)
+		BulkPurge           func(http.ResponseWriter, *http.Request)
+		Undelete            func(http.ResponseWriter, *http.Request)
+		Purge               func(http.ResponseWriter, *http.Request)
 		TriggerScript       func(http.ResponseWriter, *http.Request)
 		TriggerScriptOnList func(http.ResponseWriter, *http.Request)
 	}
@@ -154,6 +172,38 @@ func NewRecord(h RecordAPI) *Record {
 
 			api.Send(w, r, value)
 		},
+		StreamExport: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordStreamExport()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.StreamExport(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		StreamImport: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordStreamImport()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.StreamImport(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		Exec: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewRecordExec()
@@ -266,6 +316,118 @@ func NewRecord(h RecordAPI) *Record {
 
 			api.Send(w, r, value)
 		},
+		Revisions: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRevisions()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Revisions(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		RestoreRevision: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordRestoreRevision()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.RestoreRevision(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Trash: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordTrash()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Trash(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		BulkUndelete: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordBulkUndelete()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.BulkUndelete(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		BulkPurge: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordBulkPurge()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.BulkPurge(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Undelete: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordUndelete()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Undelete(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		Purge: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewRecordPurge()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Purge(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
 		TriggerScript: func(w http.ResponseWriter, r *http.Request) {
 			defer r.Body.Close()
 			params := request.NewRecordTriggerScript()
@@ -310,6 +472,8 @@ func (h Record) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http
 		r.Patch("/namespace/{namespaceID}/module/{moduleID}/record/import/{sessionID}", h.ImportRun)
 		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/import/{sessionID}", h.ImportProgress)
 		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/export{filename}.{ext}", h.Export)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/stream/export", h.StreamExport)
+		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/stream/import", h.StreamImport)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/exec/{procedure}", h.Exec)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/", h.Create)
 		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}", h.Read)
@@ -317,6 +481,13 @@ func (h Record) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http
 		r.Delete("/namespace/{namespaceID}/module/{moduleID}/record/", h.BulkDelete)
 		r.Delete("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}", h.Delete)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/attachment", h.Upload)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions", h.Revisions)
+		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/revisions/{revision}/restore", h.RestoreRevision)
+		r.Get("/namespace/{namespaceID}/module/{moduleID}/record/trash", h.Trash)
+		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/trash/undelete", h.BulkUndelete)
+		r.Delete("/namespace/{namespaceID}/module/{moduleID}/record/trash", h.BulkPurge)
+		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/undelete", h.Undelete)
+		r.Delete("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/purge", h.Purge)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/{recordID}/trigger", h.TriggerScript)
 		r.Post("/namespace/{namespaceID}/module/{moduleID}/record/trigger", h.TriggerScriptOnList)
 	})
@@ -1,6 +1,8 @@
 package rest
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -19,6 +21,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/envoy/csv"
 	ejson "github.com/cortezaproject/corteza-server/pkg/envoy/json"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/spreadsheet"
 	estore "github.com/cortezaproject/corteza-server/pkg/envoy/store"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/payload"
@@ -26,6 +29,14 @@ import (
 	systemService "github.com/cortezaproject/corteza-server/system/service"
 )
 
+const (
+	recordStreamExportBatchSize    = 500
+	recordStreamExportMaxBatchSize = 1000
+	recordStreamImportBatchSize    = 100
+	recordStreamImportMaxBatchSize = 500
+	recordStreamImportMaxLineSize  = 16 << 20
+)
+
 type (
 	recordPayload struct {
 		*types.Record
@@ -53,6 +64,25 @@ type (
 		userFinder    systemService.UserService
 	}
 
+	// recordStreamImportResult is written to the response for each of the imported lines
+	recordStreamImportResult struct {
+		Line        uint                       `json:"line"`
+		RecordID    uint64                     `json:"recordID,string,omitempty"`
+		Error       string                     `json:"error,omitempty"`
+		ValueErrors *types.RecordValueErrorSet `json:"valueErrors,omitempty"`
+	}
+
+	// recordStreamImportSummary is written to the response when the import is done
+	recordStreamImportSummary struct {
+		Summary struct {
+			Processed uint `json:"processed"`
+			Created   uint `json:"created"`
+			Updated   uint `json:"updated"`
+			Failed    uint `json:"failed"`
+			Aborted   bool `json:"aborted"`
+		} `json:"summary"`
+	}
+
 	recordAccessController interface {
 		CanGrant(context.Context) bool
 
@@ -258,6 +288,83 @@ func (ctrl *Record) BulkDelete(ctx context.Context, r *request.RecordBulkDelete)
 	)
 }
 
+func (ctrl *Record) Revisions(ctx context.Context, r *request.RecordRevisions) (interface{}, error) {
+	return ctrl.record.Revisions(ctx, r.NamespaceID, r.ModuleID, r.RecordID)
+}
+
+func (ctrl *Record) RestoreRevision(ctx context.Context, r *request.RecordRestoreRevision) (interface{}, error) {
+	var (
+		m   *types.Module
+		err error
+	)
+
+	if m, err = ctrl.module.FindByID(ctx, r.NamespaceID, r.ModuleID); err != nil {
+		return nil, err
+	}
+
+	record, err := ctrl.record.RestoreRevision(ctx, r.NamespaceID, r.ModuleID, r.RecordID, r.Revision)
+	if rve := types.IsRecordValueErrorSet(err); rve != nil {
+		return ctrl.handleValidationError(rve), nil
+	}
+
+	return ctrl.makePayload(ctx, m, record, err)
+}
+
+func (ctrl *Record) Trash(ctx context.Context, r *request.RecordTrash) (interface{}, error) {
+	var (
+		m   *types.Module
+		err error
+
+		f = types.RecordFilter{
+			NamespaceID: r.NamespaceID,
+			ModuleID:    r.ModuleID,
+			Query:       r.Query,
+		}
+	)
+
+	if m, err = ctrl.module.FindByID(ctx, r.NamespaceID, r.ModuleID); err != nil {
+		return nil, err
+	}
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	f.IncTotal = r.IncTotal
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	rr, filter, err := ctrl.record.Trash(ctx, f)
+
+	return ctrl.makeFilterPayload(ctx, m, rr, &filter, err)
+}
+
+func (ctrl *Record) BulkUndelete(ctx context.Context, r *request.RecordBulkUndelete) (interface{}, error) {
+	return api.OK(), ctrl.record.UndeleteByID(ctx,
+		r.NamespaceID,
+		r.ModuleID,
+		payload.ParseUint64s(r.RecordIDs)...,
+	)
+}
+
+func (ctrl *Record) BulkPurge(ctx context.Context, r *request.RecordBulkPurge) (interface{}, error) {
+	return api.OK(), ctrl.record.PurgeByID(ctx,
+		r.NamespaceID,
+		r.ModuleID,
+		payload.ParseUint64s(r.RecordIDs)...,
+	)
+}
+
+func (ctrl *Record) Undelete(ctx context.Context, r *request.RecordUndelete) (interface{}, error) {
+	return api.OK(), ctrl.record.UndeleteByID(ctx, r.NamespaceID, r.ModuleID, r.RecordID)
+}
+
+func (ctrl *Record) Purge(ctx context.Context, r *request.RecordPurge) (interface{}, error) {
+	return api.OK(), ctrl.record.PurgeByID(ctx, r.NamespaceID, r.ModuleID, r.RecordID)
+}
+
 func (ctrl *Record) Upload(ctx context.Context, r *request.RecordUpload) (interface{}, error) {
 	file, err := r.Upload.Open()
 	if err != nil {
@@ -438,6 +545,31 @@ func (ctrl *Record) Export(ctx context.Context, r *request.RecordExport) (interf
 		return nil, err
 	}
 
+	if len(r.Modules) > 0 {
+		switch strings.ToLower(r.Ext) {
+		case spreadsheet.FormatXLSX, spreadsheet.FormatODS:
+		default:
+			return nil, fmt.Errorf("multi-module export not supported by format (%s)", r.Ext)
+		}
+
+		// Records of additional modules are exported into their own sheets
+		for _, moduleID := range r.Modules {
+			if moduleID == r.ModuleID {
+				continue
+			}
+
+			if _, err = ctrl.module.FindByID(ctx, r.NamespaceID, moduleID); err != nil {
+				return nil, err
+			}
+
+			f.ComposeRecord(&types.RecordFilter{
+				Query:       r.Filter,
+				NamespaceID: r.NamespaceID,
+				ModuleID:    moduleID,
+			})
+		}
+	}
+
 	if len(r.Fields) == 1 {
 		r.Fields = strings.Split(r.Fields[0], ",")
 	}
@@ -475,6 +607,15 @@ func (ctrl *Record) Export(ctx context.Context, r *request.RecordExport) (interf
 				Timezone: r.Timezone,
 			})
 
+		case spreadsheet.FormatXLSX, spreadsheet.FormatODS:
+			ext := strings.ToLower(r.Ext)
+			contentType = spreadsheet.MimeType(ext)
+			encoder = spreadsheet.NewBulkRecordEncoder(&spreadsheet.EncoderConfig{
+				Format:   ext,
+				Fields:   fx,
+				Timezone: r.Timezone,
+			})
+
 		default:
 			http.Error(w, "unsupported format ("+r.Ext+")", http.StatusBadRequest)
 			return
@@ -507,6 +648,248 @@ func (ctrl *Record) Export(ctx context.Context, r *request.RecordExport) (interf
 	}, err
 }
 
+// StreamExport writes records that match the filter as newline delimited JSON
+//
+// Records are fetched from the store page by page (using paging cursors)
+// and written to the response as soon as they are fetched.
+func (ctrl *Record) StreamExport(ctx context.Context, r *request.RecordStreamExport) (interface{}, error) {
+	var (
+		err error
+
+		f = types.RecordFilter{
+			Query:       r.Filter,
+			NamespaceID: r.NamespaceID,
+			ModuleID:    r.ModuleID,
+		}
+	)
+
+	// Access control
+	if _, err = ctrl.module.FindByID(ctx, r.NamespaceID, r.ModuleID); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	switch f.Limit = r.BatchSize; {
+	case f.Limit == 0:
+		f.Limit = recordStreamExportBatchSize
+	case f.Limit > recordStreamExportMaxBatchSize:
+		f.Limit = recordStreamExportMaxBatchSize
+	}
+
+	if len(r.Fields) == 1 {
+		r.Fields = strings.Split(r.Fields[0], ",")
+	}
+
+	fx := make(map[string]bool)
+	for _, name := range r.Fields {
+		fx[name] = true
+	}
+
+	// First page is fetched before the response is written
+	// so that the errors (access control, invalid filter) can be properly reported
+	rr, f, err := ctrl.record.Find(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		var (
+			enc = json.NewEncoder(w)
+		)
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		err = func() error {
+			for {
+				for _, rec := range rr {
+					if len(fx) > 0 {
+						vv := make(types.RecordValueSet, 0, len(rec.Values))
+						for _, v := range rec.Values {
+							if fx[v.Name] {
+								vv = append(vv, v)
+							}
+						}
+						rec.Values = vv
+					}
+
+					if err = enc.Encode(rec); err != nil {
+						return err
+					}
+				}
+
+				if fl, ok := w.(http.Flusher); ok {
+					fl.Flush()
+				}
+
+				if f.NextPage == nil {
+					return nil
+				}
+
+				f.PageCursor, f.NextPage = f.NextPage, nil
+				if rr, f, err = ctrl.record.Find(ctx, f); err != nil {
+					return err
+				}
+			}
+		}()
+
+		if err != nil {
+			// headers are already sent; error is reported as the last line
+			_ = enc.Encode(map[string]string{"error": err.Error()})
+		}
+
+		_ = ctrl.record.RecordExport(ctx, f)
+	}, nil
+}
+
+// StreamImport imports records from the newline delimited JSON request body
+//
+// Each line holds one record (same structure as the one used by the record API).
+// Records are applied in transactional batches and result of each line is written
+// to the response (as newline delimited JSON) once its batch is applied.
+// Summary of the import is written as the last line.
+func (ctrl *Record) StreamImport(ctx context.Context, r *request.RecordStreamImport) (interface{}, error) {
+	var (
+		err error
+
+		batchSize = r.BatchSize
+		onError   = strings.ToUpper(r.OnError)
+	)
+
+	// Access control
+	if _, err = ctrl.module.FindByID(ctx, r.NamespaceID, r.ModuleID); err != nil {
+		return nil, err
+	}
+
+	switch onError {
+	case "":
+		onError = service.IMPORT_ON_ERROR_FAIL
+	case service.IMPORT_ON_ERROR_FAIL, service.IMPORT_ON_ERROR_SKIP:
+		// ok
+	default:
+		return nil, fmt.Errorf("unsupported onError value (%s)", r.OnError)
+	}
+
+	switch {
+	case batchSize == 0:
+		batchSize = recordStreamImportBatchSize
+	case batchSize > recordStreamImportMaxBatchSize:
+		// whole batch is held in memory and applied in a single transaction
+		batchSize = recordStreamImportMaxBatchSize
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		var (
+			skip = onError == service.IMPORT_ON_ERROR_SKIP
+			enc  = json.NewEncoder(w)
+			sc   = bufio.NewScanner(req.Body)
+			sum  = &recordStreamImportSummary{}
+
+			line  uint
+			lines []uint
+			batch types.RecordSet
+		)
+
+		sc.Buffer(make([]byte, 0, 64*1024), recordStreamImportMaxLineSize)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+
+		fail := func(l uint, err error) {
+			sum.Summary.Failed++
+			_ = enc.Encode(&recordStreamImportResult{
+				Line:        l,
+				Error:       err.Error(),
+				ValueErrors: types.IsRecordValueErrorSet(err),
+			})
+		}
+
+		// applies the batch and reports results of all of its lines
+		//
+		// returns false when import should stop
+		apply := func() bool {
+			out, ee, err := ctrl.record.ImportBatch(ctx, batch, skip)
+			for i, rec := range batch {
+				sum.Summary.Processed++
+
+				switch {
+				case ee[i] != nil:
+					fail(lines[i], ee[i])
+
+				case err != nil:
+					fail(lines[i], fmt.Errorf("batch rolled back"))
+
+				default:
+					if rec.ID > 0 {
+						sum.Summary.Updated++
+					} else {
+						sum.Summary.Created++
+					}
+
+					_ = enc.Encode(&recordStreamImportResult{Line: lines[i], RecordID: out[i].ID})
+				}
+			}
+
+			if fl, ok := w.(http.Flusher); ok {
+				fl.Flush()
+			}
+
+			batch, lines = batch[:0], lines[:0]
+			return err == nil
+		}
+
+		for sc.Scan() {
+			line++
+
+			raw := bytes.TrimSpace(sc.Bytes())
+			if len(raw) == 0 {
+				continue
+			}
+
+			rec := &types.Record{}
+			if err = json.Unmarshal(raw, rec); err != nil {
+				if !skip {
+					// lines preceding the invalid one are applied
+					// before the import is aborted so that each gets its result
+					if len(batch) > 0 {
+						apply()
+					}
+
+					sum.Summary.Processed++
+					fail(line, fmt.Errorf("invalid record: %w", err))
+					sum.Summary.Aborted = true
+					break
+				}
+
+				sum.Summary.Processed++
+				fail(line, fmt.Errorf("invalid record: %w", err))
+				continue
+			}
+
+			rec.NamespaceID, rec.ModuleID = r.NamespaceID, r.ModuleID
+			batch = append(batch, rec)
+			lines = append(lines, line)
+
+			if uint(len(batch)) >= batchSize && !apply() {
+				sum.Summary.Aborted = true
+				break
+			}
+		}
+
+		if !sum.Summary.Aborted && len(batch) > 0 {
+			sum.Summary.Aborted = !apply()
+		}
+
+		// lines read before the body failed to scan are still applied
+		if err = sc.Err(); err != nil {
+			sum.Summary.Aborted = true
+			fail(line+1, err)
+		}
+
+		_ = enc.Encode(sum)
+	}, nil
+}
+
 func (ctrl Record) Exec(ctx context.Context, r *request.RecordExec) (interface{}, error) {
 	aa := request.ProcedureArgs(r.Args)
 
@@ -0,0 +1,190 @@
+package request
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	"github.com/go-chi/chi"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// dummy vars to prevent
+// unused imports complain
+var (
+	_ = chi.URLParam
+	_ = multipart.ErrMessageTooLarge
+	_ = payload.ParseUint64s
+	_ = strings.ToLower
+	_ = io.EOF
+	_ = fmt.Errorf
+	_ = json.NewEncoder
+)
+
+type (
+	// Internal API interface
+	GraphqlQuery struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// Query POST parameter
+		//
+		// GraphQL query document
+		Query string
+
+		// OperationName POST parameter
+		//
+		// Name of the operation to execute
+		OperationName string
+
+		// Variables POST parameter
+		//
+		// Query variables
+		Variables map[string]interface{}
+	}
+)
+
+// NewGraphqlQuery request
+func NewGraphqlQuery() *GraphqlQuery {
+	return &GraphqlQuery{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r GraphqlQuery) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID":   r.NamespaceID,
+		"query":         r.Query,
+		"operationName": r.OperationName,
+		"variables":     r.Variables,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r GraphqlQuery) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r GraphqlQuery) GetQuery() string {
+	return r.Query
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r GraphqlQuery) GetOperationName() string {
+	return r.OperationName
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r GraphqlQuery) GetVariables() map[string]interface{} {
+	return r.Variables
+}
+
+// Fill processes request and fills internal variables
+func (r *GraphqlQuery) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+			if val, ok := req.MultipartForm.Value["query"]; ok && len(val) > 0 {
+				r.Query, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["operationName"]; ok && len(val) > 0 {
+				r.OperationName, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["variables[]"]; ok {
+				r.Variables, err = parseMapStringInterface(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["variables"]; ok {
+				r.Variables, err = parseMapStringInterface(val)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if val, ok := req.Form["query"]; ok && len(val) > 0 {
+			r.Query, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["operationName"]; ok && len(val) > 0 {
+			r.OperationName, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["variables[]"]; ok {
+			r.Variables, err = parseMapStringInterface(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["variables"]; ok {
+			r.Variables, err = parseMapStringInterface(val)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
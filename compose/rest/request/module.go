@@ -106,6 +106,11 @@ type (
 		// Module meta data
 		Meta sqlxTypes.JSONText
 
+		// Config POST parameter
+		//
+		// Module config
+		Config types.ModuleConfig
+
 		// Labels POST parameter
 		//
 		// Module labels
@@ -155,6 +160,11 @@ type (
 		// Module meta data
 		Meta sqlxTypes.JSONText
 
+		// Config POST parameter
+		//
+		// Module config
+		Config types.ModuleConfig
+
 		// UpdatedAt POST parameter
 		//
 		// Last update (or creation) date
@@ -373,6 +383,7 @@ func (r ModuleCreate) Auditable() map[string]interface{} {
 		"handle":      r.Handle,
 		"fields":      r.Fields,
 		"meta":        r.Meta,
+		"config":      r.Config,
 		"labels":      r.Labels,
 	}
 }
@@ -402,6 +413,11 @@ func (r ModuleCreate) GetMeta() sqlxTypes.JSONText {
 	return r.Meta
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r ModuleCreate) GetConfig() types.ModuleConfig {
+	return r.Config
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r ModuleCreate) GetLabels() map[string]string {
 	return r.Labels
@@ -449,6 +465,18 @@ func (r *ModuleCreate) Fill(req *http.Request) (err error) {
 				}
 			}
 
+			if val, ok := req.MultipartForm.Value["config[]"]; ok {
+				r.Config, err = types.ParseModuleConfig(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["config"]; ok {
+				r.Config, err = types.ParseModuleConfig(val)
+				if err != nil {
+					return err
+				}
+			}
+
 			if val, ok := req.MultipartForm.Value["labels[]"]; ok {
 				r.Labels, err = label.ParseStrings(val)
 				if err != nil {
@@ -498,6 +526,18 @@ func (r *ModuleCreate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["config[]"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["config"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["labels[]"]; ok {
 			r.Labels, err = label.ParseStrings(val)
 			if err != nil {
@@ -587,6 +627,7 @@ func (r ModuleUpdate) Auditable() map[string]interface{} {
 		"handle":      r.Handle,
 		"fields":      r.Fields,
 		"meta":        r.Meta,
+		"config":      r.Config,
 		"updatedAt":   r.UpdatedAt,
 		"labels":      r.Labels,
 	}
@@ -622,6 +663,11 @@ func (r ModuleUpdate) GetMeta() sqlxTypes.JSONText {
 	return r.Meta
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r ModuleUpdate) GetConfig() types.ModuleConfig {
+	return r.Config
+}
+
 // Auditable returns all auditable/loggable parameters
 func (r ModuleUpdate) GetUpdatedAt() *time.Time {
 	return r.UpdatedAt
@@ -674,6 +720,18 @@ func (r *ModuleUpdate) Fill(req *http.Request) (err error) {
 				}
 			}
 
+			if val, ok := req.MultipartForm.Value["config[]"]; ok {
+				r.Config, err = types.ParseModuleConfig(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["config"]; ok {
+				r.Config, err = types.ParseModuleConfig(val)
+				if err != nil {
+					return err
+				}
+			}
+
 			if val, ok := req.MultipartForm.Value["updatedAt"]; ok && len(val) > 0 {
 				r.UpdatedAt, err = payload.ParseISODatePtrWithErr(val[0])
 				if err != nil {
@@ -730,6 +788,18 @@ func (r *ModuleUpdate) Fill(req *http.Request) (err error) {
 			}
 		}
 
+		if val, ok := req.Form["config[]"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["config"]; ok {
+			r.Config, err = types.ParseModuleConfig(val)
+			if err != nil {
+				return err
+			}
+		}
+
 		if val, ok := req.Form["updatedAt"]; ok && len(val) > 0 {
 			r.UpdatedAt, err = payload.ParseISODatePtrWithErr(val[0])
 			if err != nil {
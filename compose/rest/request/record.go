@@ -210,6 +210,65 @@ type (
 		//
 		// Convert times to this timezone
 		Timezone string
+
+		// Modules GET parameter
+		//
+		// Additional modules to export; supported by spreadsheet formats (xlsx, ods) where each module is exported into its own sheet
+		Modules []uint64
+	}
+
+	RecordStreamExport struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// Filter GET parameter
+		//
+		// Filtering condition
+		Filter string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+
+		// Fields GET parameter
+		//
+		// Fields to export; all fields are exported when omitted
+		Fields []string
+
+		// BatchSize GET parameter
+		//
+		// Number of records fetched from the store at once (default 500, max 1000)
+		BatchSize uint
+	}
+
+	RecordStreamImport struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// BatchSize GET parameter
+		//
+		// Number of records applied in a single transaction (default 100, max 500)
+		BatchSize uint
+
+		// OnError GET parameter
+		//
+		// What happens when a line fails; FAIL (default) stops the import, SKIP omits the line from its batch
+		OnError string
 	}
 
 	RecordExec struct {
@@ -376,6 +435,150 @@ type (
 		Upload *multipart.FileHeader
 	}
 
+	RecordRevisions struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+	}
+
+	RecordRestoreRevision struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+
+		// Revision PATH parameter
+		//
+		// Revision number
+		Revision uint
+	}
+
+	RecordTrash struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// Query GET parameter
+		//
+		// Record filtering query
+		Query string
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// IncTotal GET parameter
+		//
+		// Include total records counter
+		IncTotal bool
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	RecordBulkUndelete struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordIDs POST parameter
+		//
+		// IDs of records to undelete
+		RecordIDs []string
+	}
+
+	RecordBulkPurge struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordIDs POST parameter
+		//
+		// IDs of records to purge
+		RecordIDs []string
+	}
+
+	RecordUndelete struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+	}
+
+	RecordPurge struct {
+		// NamespaceID PATH parameter
+		//
+		// Namespace ID
+		NamespaceID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+	}
+
 	RecordTriggerScript struct {
 		// NamespaceID PATH parameter
 		//
@@ -958,6 +1161,7 @@ func (r RecordExport) Auditable() map[string]interface{} {
 		"filter":      r.Filter,
 		"fields":      r.Fields,
 		"timezone":    r.Timezone,
+		"modules":     r.Modules,
 	}
 }
 
@@ -996,6 +1200,11 @@ func (r RecordExport) GetTimezone() string {
 	return r.Timezone
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r RecordExport) GetModules() []uint64 {
+	return r.Modules
+}
+
 // Fill processes request and fills internal variables
 func (r *RecordExport) Fill(req *http.Request) (err error) {
 
@@ -1026,6 +1235,17 @@ func (r *RecordExport) Fill(req *http.Request) (err error) {
 				return err
 			}
 		}
+		if val, ok := tmp["modules[]"]; ok {
+			r.Modules, err = payload.ParseUint64s(val), nil
+			if err != nil {
+				return err
+			}
+		} else if val, ok := tmp["modules"]; ok {
+			r.Modules, err = payload.ParseUint64s(val), nil
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	{
@@ -1061,69 +1281,252 @@ func (r *RecordExport) Fill(req *http.Request) (err error) {
 	return err
 }
 
-// NewRecordExec request
-func NewRecordExec() *RecordExec {
-	return &RecordExec{}
+// NewRecordStreamExport request
+func NewRecordStreamExport() *RecordStreamExport {
+	return &RecordStreamExport{}
 }
 
 // Auditable returns all auditable/loggable parameters
-func (r RecordExec) Auditable() map[string]interface{} {
+func (r RecordStreamExport) Auditable() map[string]interface{} {
 	return map[string]interface{}{
 		"namespaceID": r.NamespaceID,
 		"moduleID":    r.ModuleID,
-		"procedure":   r.Procedure,
-		"args":        r.Args,
+		"filter":      r.Filter,
+		"sort":        r.Sort,
+		"fields":      r.Fields,
+		"batchSize":   r.BatchSize,
 	}
 }
 
 // Auditable returns all auditable/loggable parameters
-func (r RecordExec) GetNamespaceID() uint64 {
+func (r RecordStreamExport) GetNamespaceID() uint64 {
 	return r.NamespaceID
 }
 
 // Auditable returns all auditable/loggable parameters
-func (r RecordExec) GetModuleID() uint64 {
+func (r RecordStreamExport) GetModuleID() uint64 {
 	return r.ModuleID
 }
 
 // Auditable returns all auditable/loggable parameters
-func (r RecordExec) GetProcedure() string {
-	return r.Procedure
+func (r RecordStreamExport) GetFilter() string {
+	return r.Filter
 }
 
 // Auditable returns all auditable/loggable parameters
-func (r RecordExec) GetArgs() []ProcedureArg {
-	return r.Args
+func (r RecordStreamExport) GetSort() string {
+	return r.Sort
 }
 
-// Fill processes request and fills internal variables
-func (r *RecordExec) Fill(req *http.Request) (err error) {
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamExport) GetFields() []string {
+	return r.Fields
+}
 
-	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
-		err = json.NewDecoder(req.Body).Decode(r)
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamExport) GetBatchSize() uint {
+	return r.BatchSize
+}
 
-		switch {
-		case err == io.EOF:
-			err = nil
-		case err != nil:
-			return fmt.Errorf("error parsing http request body: %w", err)
-		}
-	}
+// Fill processes request and fills internal variables
+func (r *RecordStreamExport) Fill(req *http.Request) (err error) {
 
 	{
-		// Caching 32MB to memory, the rest to disk
-		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
-			return err
-		} else if err == nil {
-			// Multipart params
+		// GET params
+		tmp := req.URL.Query()
 
+		if val, ok := tmp["filter"]; ok && len(val) > 0 {
+			r.Filter, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["fields[]"]; ok {
+			r.Fields, err = val, nil
+			if err != nil {
+				return err
+			}
+		} else if val, ok := tmp["fields"]; ok {
+			r.Fields, err = val, nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["batchSize"]; ok && len(val) > 0 {
+			r.BatchSize, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
 		}
 	}
 
 	{
-		if err = req.ParseForm(); err != nil {
-			return err
-		}
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordStreamImport request
+func NewRecordStreamImport() *RecordStreamImport {
+	return &RecordStreamImport{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamImport) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"batchSize":   r.BatchSize,
+		"onError":     r.OnError,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamImport) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamImport) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamImport) GetBatchSize() uint {
+	return r.BatchSize
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordStreamImport) GetOnError() string {
+	return r.OnError
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordStreamImport) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["batchSize"]; ok && len(val) > 0 {
+			r.BatchSize, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["onError"]; ok && len(val) > 0 {
+			r.OnError, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordExec request
+func NewRecordExec() *RecordExec {
+	return &RecordExec{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordExec) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"procedure":   r.Procedure,
+		"args":        r.Args,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordExec) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordExec) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordExec) GetProcedure() string {
+	return r.Procedure
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordExec) GetArgs() []ProcedureArg {
+	return r.Args
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordExec) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
 
 		// POST params
 
@@ -1790,6 +2193,545 @@ func (r *RecordUpload) Fill(req *http.Request) (err error) {
 	return err
 }
 
+// NewRecordRevisions request
+func NewRecordRevisions() *RecordRevisions {
+	return &RecordRevisions{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordID":    r.RecordID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRevisions) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordRevisions) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordRestoreRevision request
+func NewRecordRestoreRevision() *RecordRestoreRevision {
+	return &RecordRestoreRevision{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordID":    r.RecordID,
+		"revision":    r.Revision,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordRestoreRevision) GetRevision() uint {
+	return r.Revision
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordRestoreRevision) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "revision")
+		r.Revision, err = payload.ParseUint(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordTrash request
+func NewRecordTrash() *RecordTrash {
+	return &RecordTrash{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"query":       r.Query,
+		"limit":       r.Limit,
+		"incTotal":    r.IncTotal,
+		"pageCursor":  r.PageCursor,
+		"sort":        r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetQuery() string {
+	return r.Query
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetIncTotal() bool {
+	return r.IncTotal
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordTrash) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordTrash) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["query"]; ok && len(val) > 0 {
+			r.Query, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["incTotal"]; ok && len(val) > 0 {
+			r.IncTotal, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordBulkUndelete request
+func NewRecordBulkUndelete() *RecordBulkUndelete {
+	return &RecordBulkUndelete{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkUndelete) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordIDs":   r.RecordIDs,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkUndelete) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkUndelete) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkUndelete) GetRecordIDs() []string {
+	return r.RecordIDs
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordBulkUndelete) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		//if val, ok := req.Form["recordIDs[]"]; ok && len(val) > 0  {
+		//    r.RecordIDs, err = val, nil
+		//    if err != nil {
+		//        return err
+		//    }
+		//}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordBulkPurge request
+func NewRecordBulkPurge() *RecordBulkPurge {
+	return &RecordBulkPurge{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkPurge) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordIDs":   r.RecordIDs,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkPurge) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkPurge) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordBulkPurge) GetRecordIDs() []string {
+	return r.RecordIDs
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordBulkPurge) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		//if val, ok := req.Form["recordIDs[]"]; ok && len(val) > 0  {
+		//    r.RecordIDs, err = val, nil
+		//    if err != nil {
+		//        return err
+		//    }
+		//}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordUndelete request
+func NewRecordUndelete() *RecordUndelete {
+	return &RecordUndelete{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordUndelete) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordID":    r.RecordID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordUndelete) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordUndelete) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordUndelete) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordUndelete) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewRecordPurge request
+func NewRecordPurge() *RecordPurge {
+	return &RecordPurge{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordPurge) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"namespaceID": r.NamespaceID,
+		"moduleID":    r.ModuleID,
+		"recordID":    r.RecordID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordPurge) GetNamespaceID() uint64 {
+	return r.NamespaceID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordPurge) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r RecordPurge) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Fill processes request and fills internal variables
+func (r *RecordPurge) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "namespaceID")
+		r.NamespaceID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
 // NewRecordTriggerScript request
 func NewRecordTriggerScript() *RecordTriggerScript {
 	return &RecordTriggerScript{}
@@ -34,6 +34,7 @@ func MountRoutes(r chi.Router) {
 		handlers.NewAutomation(automation).MountRoutes(r)
 		handlers.NewModule(module).MountRoutes(r)
 		handlers.NewRecord(record).MountRoutes(r)
+		handlers.NewGraphql(GraphQL{}.New()).MountRoutes(r)
 		handlers.NewChart(chart).MountRoutes(r)
 		handlers.NewNotification(notification).MountRoutes(r)
 	})
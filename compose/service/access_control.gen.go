@@ -113,6 +113,16 @@ func (svc accessControl) List() (out []map[string]string) {
 			"any":  types.ModuleRbacResource(0, 0),
 			"op":   "records.search",
 		},
+		{
+			"type": types.ModuleResourceType,
+			"any":  types.ModuleRbacResource(0, 0),
+			"op":   "record.undelete",
+		},
+		{
+			"type": types.ModuleResourceType,
+			"any":  types.ModuleRbacResource(0, 0),
+			"op":   "record.purge",
+		},
 		{
 			"type": types.NamespaceResourceType,
 			"any":  types.NamespaceRbacResource(0),
@@ -366,6 +376,20 @@ func (svc accessControl) CanSearchRecordsOnModule(ctx context.Context, r *types.
 	return svc.can(ctx, "records.search", r)
 }
 
+// CanUndeleteRecordOnModule checks if current user can list and undelete deleted records
+//
+// This function is auto-generated
+func (svc accessControl) CanUndeleteRecordOnModule(ctx context.Context, r *types.Module) bool {
+	return svc.can(ctx, "record.undelete", r)
+}
+
+// CanPurgeRecordOnModule checks if current user can list and permanently delete (purge) deleted records
+//
+// This function is auto-generated
+func (svc accessControl) CanPurgeRecordOnModule(ctx context.Context, r *types.Module) bool {
+	return svc.can(ctx, "record.purge", r)
+}
+
 // CanReadNamespace checks if current user can read namespace
 //
 // This function is auto-generated
@@ -562,11 +586,13 @@ func rbacResourceOperations(r string) map[string]bool {
 		}
 	case types.ModuleResourceType:
 		return map[string]bool{
-			"read":           true,
-			"update":         true,
-			"delete":         true,
-			"record.create":  true,
-			"records.search": true,
+			"read":            true,
+			"update":          true,
+			"delete":          true,
+			"record.create":   true,
+			"records.search":  true,
+			"record.undelete": true,
+			"record.purge":    true,
 		}
 	case types.NamespaceResourceType:
 		return map[string]bool{
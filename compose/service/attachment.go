@@ -233,7 +233,7 @@ func (svc attachment) OpenOriginal(att *types.Attachment) (io.ReadSeeker, error)
 		return nil, nil
 	}
 
-	return svc.objects.Open(att.Url)
+	return objstore.OpenSeeker(svc.objects, att.Url)
 }
 
 func (svc attachment) OpenPreview(att *types.Attachment) (io.ReadSeeker, error) {
@@ -241,7 +241,7 @@ func (svc attachment) OpenPreview(att *types.Attachment) (io.ReadSeeker, error)
 		return nil, nil
 	}
 
-	return svc.objects.Open(att.PreviewUrl)
+	return objstore.OpenSeeker(svc.objects, att.PreviewUrl)
 }
 
 func (svc attachment) CreatePageAttachment(ctx context.Context, namespaceID uint64, name string, size int64, fh io.ReadSeeker, pageID uint64) (att *types.Attachment, err error) {
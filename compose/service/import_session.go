@@ -13,6 +13,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/envoy/csv"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/json"
 	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/spreadsheet"
 )
 
 type (
@@ -66,9 +67,10 @@ func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, con
 		UpdatedAt: time.Now(),
 	}
 
-	// Decoders; We only need to do csv & yaml here
+	// Decoders; We only need to do csv, json & spreadsheets here
 	cd := csv.Decoder()
 	jd := json.Decoder()
+	sd := spreadsheet.Decoder()
 
 	// This will really be at most 1
 	var err error
@@ -83,6 +85,17 @@ func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, con
 			return cd.Decode(ctx, f, do)
 		}
 
+		f.Seek(0, 0)
+		if sd.CanDecodeExt(name) || sd.CanDecodeMime(contentType) || sd.CanDecodeFile(f) {
+			f.Seek(0, 0)
+			rr, err := sd.Decode(ctx, f, do)
+			if len(rr) > 1 {
+				// Only the first sheet is imported
+				rr = rr[:1]
+			}
+			return rr, err
+		}
+
 		f.Seek(0, 0)
 		if jd.CanDecodeFile(f) {
 			f.Seek(0, 0)
@@ -96,6 +109,10 @@ func (svc *importSession) Create(ctx context.Context, f io.ReadSeeker, name, con
 		return nil, err
 	}
 
+	if len(sh.Resources) == 0 {
+		return nil, fmt.Errorf("compose.service.RecordImportFormatNotSupported")
+	}
+
 	// Get some metadata
 	n, ok := (sh.Resources[0]).(*resource.ResourceDataset)
 	if !ok {
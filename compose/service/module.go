@@ -15,6 +15,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/label"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/slice"
@@ -28,6 +29,12 @@ type (
 		eventbus  eventDispatcher
 		store     store.Storer
 		locale    ResourceTranslationsManagerService
+
+		opt ModuleOptions
+	}
+
+	ModuleOptions struct {
+		LimitModules int
 	}
 
 	moduleAccessController interface {
@@ -62,6 +69,9 @@ const (
 	moduleChanged       moduleChanges = 1
 	moduleLabelsChanged moduleChanges = 2
 	moduleFieldsChanged moduleChanges = 4
+
+	// ModuleInvalidationKind is used to notify other nodes about changed modules
+	ModuleInvalidationKind = "compose:module"
 )
 
 var (
@@ -77,8 +87,10 @@ var (
 	})
 )
 
-func Module() *module {
+func Module(opt ModuleOptions) *module {
 	return &module{
+		opt: opt,
+
 		ac:        DefaultAccessControl,
 		eventbus:  eventbus.Service(),
 		actionlog: DefaultActionlog,
@@ -267,6 +279,10 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 			return err
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return err
+		}
+
 		new.ID = nextID()
 		new.CreatedAt = *now()
 		new.UpdatedAt = nil
@@ -293,6 +309,14 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 
 		aProps.setModule(new)
 
+		if err = validateModuleRollups(ctx, s, new); err != nil {
+			return err
+		}
+
+		if err = validateModuleUniqueConstraints(new); err != nil {
+			return err
+		}
+
 		if err = store.CreateComposeModule(ctx, s, new); err != nil {
 			return err
 		}
@@ -318,6 +342,10 @@ func (svc module) Create(ctx context.Context, new *types.Module) (*types.Module,
 		return nil
 	})
 
+	if err == nil {
+		invalidation.Notify(ctx, ModuleInvalidationKind)
+	}
+
 	return new, svc.recordAction(ctx, aProps, ModuleActionCreate, err)
 }
 
@@ -372,6 +400,12 @@ func (svc module) updater(ctx context.Context, namespaceID, moduleID uint64, act
 			return err
 		}
 
+		if changes&(moduleChanged|moduleFieldsChanged) > 0 {
+			if err = validateModuleUniqueConstraints(m); err != nil {
+				return err
+			}
+		}
+
 		if changes&moduleChanged > 0 {
 			if err = store.UpdateComposeModule(ctx, svc.store, m); err != nil {
 				return err
@@ -390,11 +424,27 @@ func (svc module) updater(ctx context.Context, namespaceID, moduleID uint64, act
 
 			hasRecords = len(set) > 0
 
+			if err = validateModuleRollups(ctx, s, m); err != nil {
+				return err
+			}
+
 			if err = updateModuleFields(ctx, s, m, old, hasRecords); err != nil {
 				return err
 			}
 		}
 
+		if changes&(moduleChanged|moduleFieldsChanged) > 0 && m.DeletedAt == nil {
+			// rebuild unique constraint index when constraints or constrained fields change
+			if !reflect.DeepEqual(old.Config.UniqueConstraints, m.Config.UniqueConstraints) ||
+				(changes&moduleFieldsChanged > 0 && len(m.Config.UniqueConstraints) > 0) {
+				if err = store.ReindexComposeRecordUniqueValues(ctx, s, m); errors.IsDuplicateData(err) {
+					return ModuleErrUniqueConstraintViolated()
+				} else if err != nil {
+					return err
+				}
+			}
+		}
+
 		// i18n
 		tt := m.EncodeTranslations()
 		for _, f := range m.Fields {
@@ -420,6 +470,10 @@ func (svc module) updater(ctx context.Context, namespaceID, moduleID uint64, act
 		return err
 	})
 
+	if err == nil {
+		invalidation.Notify(ctx, ModuleInvalidationKind)
+	}
+
 	return m, svc.recordAction(ctx, aProps, action, err)
 }
 
@@ -530,6 +584,11 @@ func (svc module) handleUpdate(ctx context.Context, upd *types.Module) moduleUpd
 
 		}
 
+		if !reflect.DeepEqual(res.Config, upd.Config) {
+			changes |= moduleChanged
+			res.Config = upd.Config
+		}
+
 		// @todo make field-change detection more optimal
 		if !reflect.DeepEqual(res.Fields, upd.Fields) {
 			changes |= moduleFieldsChanged
@@ -589,10 +648,66 @@ func (svc module) handleUndelete(ctx context.Context, ns *types.Namespace, m *ty
 		return moduleUnchanged, nil
 	}
 
+	if err := svc.checkLimits(ctx); err != nil {
+		return moduleUnchanged, err
+	}
+
 	m.DeletedAt = nil
 	return moduleChanged, nil
 }
 
+func (svc module) checkLimits(ctx context.Context) error {
+	if svc.opt.LimitModules == 0 {
+		return nil
+	}
+
+	if c, err := countValidModules(ctx, svc.store); err != nil {
+		return err
+	} else if c >= uint(svc.opt.LimitModules) {
+		return ModuleErrMaxModuleLimitReached()
+	}
+
+	return nil
+}
+
+// countValidModules counts modules that are not deleted, on all namespaces
+func countValidModules(ctx context.Context, s store.ComposeModules) (c uint, err error) {
+	return store.CountComposeModules(ctx, s, types.ModuleFilter{})
+}
+
+// validateModuleUniqueConstraints checks unique constraint configuration
+//
+// Constraints must have unique (and valid) names and can only
+// reference existing single-value fields
+func validateModuleUniqueConstraints(m *types.Module) error {
+	var (
+		names = make(map[string]bool)
+	)
+
+	for _, c := range m.Config.UniqueConstraints {
+		if c == nil || c.Name == "" || !handle.IsValid(c.Name) || names[c.Name] || len(c.Fields) == 0 {
+			return ModuleErrInvalidUniqueConstraint()
+		}
+
+		names[c.Name] = true
+
+		var (
+			fields = make(map[string]bool)
+		)
+
+		for _, name := range c.Fields {
+			f := m.Fields.FindByName(name)
+			if f == nil || f.Multi || f.IsRollup() || fields[name] {
+				return ModuleErrInvalidUniqueConstraint()
+			}
+
+			fields[name] = true
+		}
+	}
+
+	return nil
+}
+
 // updates module fields
 // expecting to receive all module fields, as it deletes the rest
 // also, sort order of the fields is also important as this fn stores and updates field's place as send
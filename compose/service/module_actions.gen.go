@@ -678,6 +678,108 @@ func ModuleErrFieldNameReserved(mm ...*moduleActionProps) *errors.Error {
 	return e
 }
 
+// ModuleErrInvalidRollup returns "compose:module.invalidRollup" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrInvalidRollup(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid rollup field configuration", nil),
+
+		errors.Meta("type", "invalidRollup"),
+		errors.Meta("resource", "compose:module"),
+
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "module.errors.invalidRollup"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ModuleErrInvalidUniqueConstraint returns "compose:module.invalidUniqueConstraint" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrInvalidUniqueConstraint(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid unique constraint configuration", nil),
+
+		errors.Meta("type", "invalidUniqueConstraint"),
+		errors.Meta("resource", "compose:module"),
+
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "module.errors.invalidUniqueConstraint"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ModuleErrUniqueConstraintViolated returns "compose:module.uniqueConstraintViolated" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrUniqueConstraintViolated(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("existing records violate unique constraint", nil),
+
+		errors.Meta("type", "uniqueConstraintViolated"),
+		errors.Meta("resource", "compose:module"),
+
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "module.errors.uniqueConstraintViolated"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // ModuleErrStaleData returns "compose:module.staleData" as *errors.Error
 //
 //
@@ -1034,6 +1136,40 @@ func ModuleErrNotAllowedToUndelete(mm ...*moduleActionProps) *errors.Error {
 	return e
 }
 
+// ModuleErrMaxModuleLimitReached returns "compose:module.maxModuleLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ModuleErrMaxModuleLimitReached(mm ...*moduleActionProps) *errors.Error {
+	var p = &moduleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your module limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxModuleLimitReached"),
+		errors.Meta("resource", "compose:module"),
+
+		errors.Meta(modulePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "module.errors.maxModuleLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
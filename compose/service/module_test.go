@@ -210,4 +210,36 @@ func TestModules(t *testing.T) {
 			req.Empty(findAndReturnLabel(res.ID))
 		})
 	})
+
+	t.Run("limits", func(t *testing.T) {
+		req := require.New(t)
+		svc := module{
+			store:    s,
+			ac:       &accessControl{rbac: &rbac.ServiceAllowAll{}},
+			eventbus: eventbus.New(),
+			opt:      ModuleOptions{LimitModules: 2},
+		}
+
+		req.NoError(s.TruncateComposeModules(ctx))
+
+		m1, err := svc.Create(ctx, &types.Module{Name: "limited 1", NamespaceID: namespaceID})
+		req.NoError(err)
+
+		_, err = svc.Create(ctx, &types.Module{Name: "limited 2", NamespaceID: namespaceID})
+		req.NoError(err)
+
+		_, err = svc.Create(ctx, &types.Module{Name: "limited 3", NamespaceID: namespaceID})
+		req.True(ModuleErrMaxModuleLimitReached().Is(err))
+
+		// deleted modules are not counted
+		req.NoError(svc.DeleteByID(ctx, namespaceID, m1.ID))
+
+		m3, err := svc.Create(ctx, &types.Module{Name: "limited 3", NamespaceID: namespaceID})
+		req.NoError(err)
+		req.NotNil(m3)
+
+		// and can not be restored when limit is reached
+		err = svc.UndeleteByID(ctx, namespaceID, m1.ID)
+		req.True(ModuleErrMaxModuleLimitReached().Is(err))
+	})
 }
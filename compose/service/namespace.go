@@ -24,6 +24,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/label"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
@@ -42,6 +43,12 @@ type (
 		eventbus eventDispatcher
 		store    store.Storer
 		locale   ResourceTranslationsManagerService
+
+		opt NamespaceOptions
+	}
+
+	NamespaceOptions struct {
+		LimitNamespaces int
 	}
 
 	namespaceImportSession struct {
@@ -98,8 +105,10 @@ var (
 	namespaceSessionStore = make(map[uint64]namespaceImportSession)
 )
 
-func Namespace() *namespace {
+func Namespace(opt NamespaceOptions) *namespace {
 	return &namespace{
+		opt: opt,
+
 		ac:      DefaultAccessControl,
 		modAc:   DefaultAccessControl,
 		pageAc:  DefaultAccessControl,
@@ -245,6 +254,10 @@ func (svc namespace) Create(ctx context.Context, new *types.Namespace) (*types.N
 			return err
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return err
+		}
+
 		new.ID = nextID()
 		new.CreatedAt = *now()
 		new.UpdatedAt = nil
@@ -303,6 +316,10 @@ func (svc namespace) Clone(ctx context.Context, namespaceID uint64, dup *types.N
 			return err
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return err
+		}
+
 		// get namespace resources
 		nn, err := decoder()
 		if err != nil {
@@ -551,6 +568,10 @@ func (svc namespace) ImportRun(ctx context.Context, sessionID uint64, dup *types
 			return err
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return err
+		}
+
 		if dup.Slug == "" || !handle.IsValid(dup.Slug) {
 			return NamespaceErrInvalidHandle()
 		}
@@ -607,6 +628,7 @@ func (svc namespace) ImportRun(ctx context.Context, sessionID uint64, dup *types
 
 		// Reload RBAC rules (in case import brought in something new)
 		rbac.Global().Reload(ctx)
+		invalidation.Notify(ctx, rbac.InvalidationKind)
 
 		// Reload workflow-triggers (in case import brought in something new)
 		if err = automationService.DefaultWorkflow.Load(ctx); err != nil {
@@ -808,10 +830,32 @@ func (svc namespace) handleUndelete(ctx context.Context, ns *types.Namespace) (n
 		return namespaceUnchanged, nil
 	}
 
+	if err := svc.checkLimits(ctx); err != nil {
+		return namespaceUnchanged, err
+	}
+
 	ns.DeletedAt = nil
 	return namespaceChanged, nil
 }
 
+func (svc namespace) checkLimits(ctx context.Context) error {
+	if svc.opt.LimitNamespaces == 0 {
+		return nil
+	}
+
+	if c, err := countValidNamespaces(ctx, svc.store); err != nil {
+		return err
+	} else if c >= uint(svc.opt.LimitNamespaces) {
+		return NamespaceErrMaxNamespaceLimitReached()
+	}
+
+	return nil
+}
+
+func countValidNamespaces(ctx context.Context, s store.ComposeNamespaces) (c uint, err error) {
+	return store.CountComposeNamespaces(ctx, s, types.NamespaceFilter{})
+}
+
 func (svc namespace) canExport(ctx context.Context, namespace *types.Namespace) error {
 	// Preload all of the relevant stuff for access control
 	// - modules
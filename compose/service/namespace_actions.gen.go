@@ -1073,6 +1073,40 @@ func NamespaceErrNotAllowedToUndelete(mm ...*namespaceActionProps) *errors.Error
 	return e
 }
 
+// NamespaceErrMaxNamespaceLimitReached returns "compose:namespace.maxNamespaceLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func NamespaceErrMaxNamespaceLimitReached(mm ...*namespaceActionProps) *errors.Error {
+	var p = &namespaceActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your namespace limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxNamespaceLimitReached"),
+		errors.Meta("resource", "compose:namespace"),
+
+		errors.Meta(namespacePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "namespace.errors.maxNamespaceLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -7,6 +7,8 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/service/event"
@@ -19,6 +21,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/label"
+	"github.com/cortezaproject/corteza-server/pkg/objstore"
 	"github.com/cortezaproject/corteza-server/pkg/report"
 	"github.com/cortezaproject/corteza-server/store"
 )
@@ -27,6 +30,9 @@ const (
 	IMPORT_ON_ERROR_SKIP         = "SKIP"
 	IMPORT_ON_ERROR_FAIL         = "FAIL"
 	IMPORT_ERROR_MAX_INDEX_COUNT = 500000
+
+	// how long is the record count used for limit checks cached
+	recordCountTTL = time.Minute
 )
 
 type (
@@ -43,6 +49,30 @@ type (
 		validator recordValuesValidator
 
 		optEmitEvents bool
+
+		// publishes record changes to websocket subscribers
+		publisher websocketPublisher
+
+		opt RecordOptions
+
+		// cached record count used when checking limits
+		counter *recordCounter
+
+		// cached rollup fields, reset when modules change
+		rollups *rollupTargetCache
+
+		// files of purged record attachments are removed from here
+		objects objstore.Store
+	}
+
+	RecordOptions struct {
+		LimitRecords int
+	}
+
+	recordCounter struct {
+		l         sync.Mutex
+		count     uint
+		countedAt time.Time
 	}
 
 	recordValuesFormatter interface {
@@ -66,6 +96,13 @@ type (
 		CanUpdateRecordValue(context.Context, *types.ModuleField) bool
 	}
 
+	// afterEventCollector holds back after-events
+	// until the changes are committed
+	afterEventCollector struct {
+		eventDispatcher
+		events []eventbus.Event
+	}
+
 	recordAccessController interface {
 		CanCreateRecordOnModule(context.Context, *types.Module) bool
 		CanSearchRecordsOnModule(context.Context, *types.Module) bool
@@ -74,6 +111,8 @@ type (
 		CanReadRecord(context.Context, *types.Record) bool
 		CanUpdateRecord(context.Context, *types.Record) bool
 		CanDeleteRecord(context.Context, *types.Record) bool
+		CanUndeleteRecordOnModule(context.Context, *types.Module) bool
+		CanPurgeRecordOnModule(context.Context, *types.Module) bool
 
 		recordValueAccessController
 	}
@@ -91,13 +130,25 @@ type (
 		Create(ctx context.Context, record *types.Record) (*types.Record, error)
 		Update(ctx context.Context, record *types.Record) (*types.Record, error)
 		Bulk(ctx context.Context, oo ...*types.RecordBulkOperation) (types.RecordSet, error)
+		ImportBatch(ctx context.Context, rr types.RecordSet, skipFailed bool) (types.RecordSet, []error, error)
 
 		Validate(ctx context.Context, rec *types.Record) error
 
 		DeleteByID(ctx context.Context, namespaceID, moduleID uint64, recordID ...uint64) error
 
+		Trash(ctx context.Context, filter types.RecordFilter) (set types.RecordSet, f types.RecordFilter, err error)
+		UndeleteByID(ctx context.Context, namespaceID, moduleID uint64, recordID ...uint64) error
+		PurgeByID(ctx context.Context, namespaceID, moduleID uint64, recordID ...uint64) error
+		PurgeExpired(ctx context.Context) error
+		WatchTrash(ctx context.Context)
+
+		Revisions(ctx context.Context, namespaceID, moduleID, recordID uint64) (types.RecordRevisionSet, error)
+		RestoreRevision(ctx context.Context, namespaceID, moduleID, recordID uint64, revision uint) (*types.Record, error)
+
 		Organize(ctx context.Context, namespaceID, moduleID, recordID uint64, sortingField, sortingValue, sortingFilter, valueField, value string) error
 
+		RecomputeRollups(ctx context.Context, namespaceID, moduleID uint64) (uint, error)
+
 		Iterator(ctx context.Context, f types.RecordFilter, fn eventbus.HandlerFn, action string) (err error)
 
 		TriggerScript(ctx context.Context, namespaceID, moduleID, recordID uint64, rvs types.RecordValueSet, script string) (*types.Module, *types.Record, error)
@@ -146,8 +197,14 @@ type (
 	ErrorIndex  map[string]int
 )
 
-func Record() RecordService {
+func Record(opt RecordOptions, ws websocketPublisher) RecordService {
 	svc := &record{
+		opt:       opt,
+		publisher: ws,
+		counter:   &recordCounter{},
+		rollups:   &rollupTargetCache{},
+		objects:   DefaultObjectStore,
+
 		actionlog:     DefaultActionlog,
 		ac:            DefaultAccessControl,
 		eventbus:      eventbus.Service(),
@@ -465,6 +522,131 @@ func (svc record) Bulk(ctx context.Context, oo ...*types.RecordBulkOperation) (r
 	}
 }
 
+// ImportBatch creates or updates the given set of records in a single transaction
+//
+// Records with ID are updated, others are created.
+//
+// Errors of individual records are returned in a slice, aligned with the given records.
+// When skipFailed is set, changes of the failed record are rolled back (to the savepoint
+// made before it) and the rest of the batch is applied; otherwise the whole batch is
+// rolled back and the error is returned.
+//
+// After-create and after-update events are dispatched only when the batch is committed.
+func (svc record) ImportBatch(ctx context.Context, rr types.RecordSet, skipFailed bool) (out types.RecordSet, ee []error, err error) {
+	var (
+		// records created in the batch;
+		// reservations are released on rollback
+		created uint
+
+		// events collected while applying the batch that is
+		// rolled back are discarded
+		after = &afterEventCollector{eventDispatcher: svc.eventbus}
+	)
+
+	ee = make([]error, len(rr))
+	out = make(types.RecordSet, len(rr))
+
+	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+		// all changes must be done through the transaction
+		txSvc := svc
+		txSvc.store = s
+
+		// changes are published and after-events dispatched
+		// after the transaction is committed
+		txSvc.publisher = nil
+		txSvc.eventbus = after
+
+		for i, r := range rr {
+			var (
+				// events of the failed record are discarded
+				collected = len(after.events)
+			)
+
+			// each record is applied in its own savepoint so that
+			// the failed one can be rolled back on its own
+			err = store.Tx(ctx, s, func(ctx context.Context, s store.Storer) (err error) {
+				spSvc := txSvc
+				spSvc.store = s
+
+				// records are modified while being created or updated;
+				// given records are left intact
+				r = r.Clone()
+
+				if r.ID > 0 {
+					out[i], err = spSvc.update(ctx, r)
+				} else if out[i], err = spSvc.create(ctx, r); err == nil {
+					created++
+				}
+
+				return err
+			})
+
+			if err != nil {
+				ee[i], out[i] = err, nil
+				after.events = after.events[:collected]
+
+				if !skipFailed {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		svc.releaseLimits(created)
+		out = nil
+	} else {
+		after.flush(ctx)
+	}
+
+	for i, r := range out {
+		if r == nil {
+			continue
+		}
+
+		if rr[i].ID > 0 {
+			svc.publishChange(ctx, recordChangeUpdate, r.GetModule(), r)
+		} else {
+			svc.publishChange(ctx, recordChangeCreate, r.GetModule(), r)
+		}
+	}
+
+	return out, ee, svc.recordAction(ctx, &recordActionProps{}, RecordActionImport, err)
+}
+
+// WaitFor collects after-events and passes all others to the eventbus
+func (c *afterEventCollector) WaitFor(ctx context.Context, ev eventbus.Event) error {
+	if strings.HasPrefix(ev.EventType(), "after") {
+		c.events = append(c.events, ev)
+		return nil
+	}
+
+	return c.eventDispatcher.WaitFor(ctx, ev)
+}
+
+// Dispatch collects after-events and passes all others to the eventbus
+func (c *afterEventCollector) Dispatch(ctx context.Context, ev eventbus.Event) {
+	if strings.HasPrefix(ev.EventType(), "after") {
+		c.events = append(c.events, ev)
+		return
+	}
+
+	c.eventDispatcher.Dispatch(ctx, ev)
+}
+
+// flush dispatches all collected events
+//
+// As with the regular create and update, after-event errors are ignored
+func (c *afterEventCollector) flush(ctx context.Context) {
+	for _, ev := range c.events {
+		_ = c.eventDispatcher.WaitFor(ctx, ev)
+	}
+
+	c.events = nil
+}
+
 // Raw create function that is responsible for value validation, event dispatching
 // and creation.
 func (svc record) create(ctx context.Context, new *types.Record) (rec *types.Record, err error) {
@@ -488,6 +670,20 @@ func (svc record) create(ctx context.Context, new *types.Record) (rec *types.Rec
 		return nil, RecordErrNotAllowedToCreate()
 	}
 
+	if err = svc.checkLimits(ctx); err != nil {
+		return
+	}
+
+	var (
+		stored bool
+	)
+
+	defer func() {
+		if err != nil && !stored {
+			svc.releaseLimits(1)
+		}
+	}()
+
 	if err = RecordValueSanitization(m, new.Values); err != nil {
 		return
 	}
@@ -519,13 +715,30 @@ func (svc record) create(ctx context.Context, new *types.Record) (rec *types.Rec
 	}
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-		return store.CreateComposeRecord(ctx, s, m, new)
+		if err = svc.applyRollups(ctx, s, m, new); err != nil {
+			return err
+		}
+
+		if err = store.CreateComposeRecord(ctx, s, m, new); err != nil {
+			return err
+		}
+
+		if err = svc.updateRollups(ctx, s, m, new); err != nil {
+			return err
+		}
+
+		return svc.storeRevision(ctx, s, m, new, types.RecordRevisionOperationCreate, types.RecordRevisionChanges(m.Fields, nil, new.Values))
 	})
 
-	if err != nil {
+	if rve = types.IsRecordValueErrorSet(err); rve != nil {
+		// unique constraints are checked by the store
+		return nil, RecordErrValueInput().Wrap(rve)
+	} else if err != nil {
 		return nil, err
 	}
 
+	stored = true
+
 	if err = label.Create(ctx, svc.store, new); err != nil {
 		return
 	}
@@ -541,9 +754,73 @@ func (svc record) create(ctx context.Context, new *types.Record) (rec *types.Rec
 		_ = svc.eventbus.WaitFor(ctx, event.RecordAfterCreateImmutable(new, nil, m, ns, nil, nil))
 	}
 
+	svc.publishChange(ctx, recordChangeCreate, m, rec)
 	return
 }
 
+// checkLimits verifies that another record can be created
+//
+// Counting all records is expensive so the count is cached
+// for a short period and bumped on each check that passes;
+// callers release the reservation when the record is not stored.
+func (svc record) checkLimits(ctx context.Context) error {
+	if svc.opt.LimitRecords == 0 {
+		return nil
+	}
+
+	c := svc.counter
+	if c == nil {
+		c = &recordCounter{}
+	}
+
+	return c.reserve(ctx, svc.store, uint(svc.opt.LimitRecords))
+}
+
+// releaseLimits gives back reservations of records that were not stored
+func (svc record) releaseLimits(n uint) {
+	if svc.opt.LimitRecords == 0 || svc.counter == nil {
+		return
+	}
+
+	svc.counter.release(n)
+}
+
+// reserve counts one more record unless limit is reached
+//
+// Count is reloaded from the store when older than recordCountTTL;
+// meanwhile, records created on other nodes or removed are not accounted for.
+func (c *recordCounter) reserve(ctx context.Context, s store.ComposeRecords, limit uint) (err error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if c.countedAt.IsZero() || time.Since(c.countedAt) > recordCountTTL {
+		if c.count, err = store.CountComposeRecords(ctx, s); err != nil {
+			return err
+		}
+
+		c.countedAt = time.Now()
+	}
+
+	if c.count >= limit {
+		return RecordErrMaxRecordLimitReached()
+	}
+
+	c.count++
+	return nil
+}
+
+// release uncounts records that were reserved but not stored
+func (c *recordCounter) release(n uint) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	if n > c.count {
+		c.count = 0
+	} else {
+		c.count -= n
+	}
+}
+
 // RecordValueSanitization does basic field and format validation
 //
 // Received values must fit the data model: on unknown fields
@@ -701,6 +978,12 @@ func RecordValueDefaults(m *types.Module, vv types.RecordValueSet) (out types.Re
 // Raw update function that is responsible for value validation, event dispatching
 // and update.
 func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Record, err error) {
+	return svc.updateWithRevision(ctx, upd, types.RecordRevisionOperationUpdate)
+}
+
+// updateWithRevision updates the record and stores the revision (when enabled)
+// with the given operation
+func (svc record) updateWithRevision(ctx context.Context, upd *types.Record, op types.RecordRevisionOperation) (rec *types.Record, err error) {
 	var (
 		aProps    = &recordActionProps{changed: upd}
 		invokerID = auth.GetIdentityFromContext(ctx).Identity()
@@ -774,10 +1057,27 @@ func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Rec
 			}
 		}
 
-		return store.UpdateComposeRecord(ctx, s, m, upd)
+		if err = svc.applyRollups(ctx, s, m, upd); err != nil {
+			return err
+		}
+
+		if err = store.UpdateComposeRecord(ctx, s, m, upd); err != nil {
+			return err
+		}
+
+		// related record might have been changed
+		// so both old and new need to be updated
+		if err = svc.updateRollups(ctx, s, m, old, upd); err != nil {
+			return err
+		}
+
+		return svc.storeRevision(ctx, s, m, upd, op, types.RecordRevisionChanges(m.Fields, old.Values, upd.Values))
 	})
 
-	if err != nil {
+	if rve = types.IsRecordValueErrorSet(err); rve != nil {
+		// unique constraints are checked by the store
+		return nil, RecordErrValueInput().Wrap(rve)
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -797,6 +1097,8 @@ func (svc record) update(ctx context.Context, upd *types.Record) (rec *types.Rec
 		upd.Values = svc.formatter.Run(m, upd.Values)
 		_ = svc.eventbus.WaitFor(ctx, event.RecordAfterUpdateImmutable(upd, old, m, ns, nil, nil))
 	}
+
+	svc.publishChange(ctx, recordChangeUpdate, m, rec)
 	return
 }
 
@@ -965,7 +1267,15 @@ func (svc record) delete(ctx context.Context, namespaceID, moduleID, recordID ui
 	del.DeletedBy = invokerID
 
 	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-		return store.UpdateComposeRecord(ctx, s, m, del)
+		if err = store.UpdateComposeRecord(ctx, s, m, del); err != nil {
+			return err
+		}
+
+		if err = svc.updateRollups(ctx, s, m, del); err != nil {
+			return err
+		}
+
+		return svc.storeRevision(ctx, s, m, del, types.RecordRevisionOperationDelete, nil)
 	})
 
 	if err != nil {
@@ -979,6 +1289,7 @@ func (svc record) delete(ctx context.Context, namespaceID, moduleID, recordID ui
 		_ = svc.eventbus.WaitFor(ctx, event.RecordAfterDeleteImmutable(nil, del, m, ns, nil, nil))
 	}
 
+	svc.publishChange(ctx, recordChangeDelete, m, del)
 	return del, nil
 }
 
@@ -1144,6 +1455,10 @@ func (svc record) Organize(ctx context.Context, namespaceID, moduleID, recordID
 				return err
 			}
 
+			if err = svc.storePartialRevision(ctx, s, m, r, recordValues...); err != nil {
+				return err
+			}
+
 			if reorderingRecords {
 				var (
 					set              types.RecordSet
@@ -1190,6 +1505,12 @@ func (svc record) Organize(ctx context.Context, namespaceID, moduleID, recordID
 				if err = store.PartialComposeRecordValueUpdate(ctx, s, m, vv...); err != nil {
 					return err
 				}
+
+				for i, r := range set {
+					if err = svc.storePartialRevision(ctx, s, m, r, vv[i]); err != nil {
+						return err
+					}
+				}
 			}
 
 			return nil
@@ -1317,6 +1638,9 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 			}
 
 			err = func() error {
+				// values before the script changes them
+				old := rec.Clone()
+
 				if err = fn(ctx, event.RecordOnIteration(rec, nil, m, ns, nil, nil)); err != nil {
 					if errors.Is(err, corredor.ScriptExecAborted) {
 						// When script was softly aborted (return false),
@@ -1339,7 +1663,11 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					}
 
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-						return store.CreateComposeRecord(ctx, s, m, rec)
+						if err := store.CreateComposeRecord(ctx, s, m, rec); err != nil {
+							return err
+						}
+
+						return svc.storeRevision(ctx, s, m, rec, types.RecordRevisionOperationCreate, types.RecordRevisionChanges(m.Fields, nil, rec.Values))
 					})
 				case "update":
 					recordableAction = RecordActionIteratorUpdate
@@ -1350,7 +1678,11 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					}
 
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
-						return store.UpdateComposeRecord(ctx, s, m, rec)
+						if err := store.UpdateComposeRecord(ctx, s, m, rec); err != nil {
+							return err
+						}
+
+						return svc.storeRevision(ctx, s, m, rec, types.RecordRevisionOperationUpdate, types.RecordRevisionChanges(m.Fields, old.Values, rec.Values))
 					})
 				case "delete":
 					recordableAction = RecordActionIteratorDelete
@@ -1358,7 +1690,11 @@ func (svc record) Iterator(ctx context.Context, f types.RecordFilter, fn eventbu
 					return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
 						rec.DeletedAt = now()
 						rec.DeletedBy = invokerID
-						return store.UpdateComposeRecord(ctx, s, m, rec)
+						if err := store.UpdateComposeRecord(ctx, s, m, rec); err != nil {
+							return err
+						}
+
+						return svc.storeRevision(ctx, s, m, rec, types.RecordRevisionOperationDelete, nil)
 					})
 				}
 
@@ -30,6 +30,7 @@ type (
 		field         string
 		value         string
 		valueErrors   *types.RecordValueErrorSet
+		revision      *types.RecordRevision
 	}
 
 	recordAction struct {
@@ -156,6 +157,17 @@ func (p *recordActionProps) setValueErrors(valueErrors *types.RecordValueErrorSe
 	return p
 }
 
+// setRevision updates recordActionProps's revision
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *recordActionProps) setRevision(revision *types.RecordRevision) *recordActionProps {
+	p.revision = revision
+	return p
+}
+
 // Serialize converts recordActionProps to actionlog.Meta
 //
 // This function is auto-generated.
@@ -202,6 +214,10 @@ func (p recordActionProps) Serialize() actionlog.Meta {
 	if p.valueErrors != nil {
 		m.Set("valueErrors.set", p.valueErrors.Set, true)
 	}
+	if p.revision != nil {
+		m.Set("revision.revision", p.revision.Revision, true)
+		m.Set("revision.ID", p.revision.ID, true)
+	}
 
 	return m
 }
@@ -337,6 +353,20 @@ func (p recordActionProps) Format(in string, err error) string {
 		)
 		pairs = append(pairs, "{{valueErrors.set}}", fns(p.valueErrors.Set))
 	}
+
+	if p.revision != nil {
+		// replacement for "{{revision}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{revision}}",
+			fns(
+				p.revision.Revision,
+				p.revision.ID,
+			),
+		)
+		pairs = append(pairs, "{{revision.revision}}", fns(p.revision.Revision))
+		pairs = append(pairs, "{{revision.ID}}", fns(p.revision.ID))
+	}
 	return strings.NewReplacer(pairs...).Replace(in)
 }
 
@@ -532,6 +562,46 @@ func RecordActionUndelete(props ...*recordActionProps) *recordAction {
 	return a
 }
 
+// RecordActionTrash returns "compose:record.trash" action
+//
+// This function is auto-generated.
+//
+func RecordActionTrash(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "trash",
+		log:       "searched for deleted records",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// RecordActionPurge returns "compose:record.purge" action
+//
+// This function is auto-generated.
+//
+func RecordActionPurge(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "purge",
+		log:       "purged {{record}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // RecordActionImport returns "compose:record.import" action
 //
 // This function is auto-generated.
@@ -592,6 +662,46 @@ func RecordActionOrganize(props ...*recordActionProps) *recordAction {
 	return a
 }
 
+// RecordActionRevisions returns "compose:record.revisions" action
+//
+// This function is auto-generated.
+//
+func RecordActionRevisions(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "revisions",
+		log:       "searched for revisions of {{record}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// RecordActionRestoreRevision returns "compose:record.restoreRevision" action
+//
+// This function is auto-generated.
+//
+func RecordActionRestoreRevision(props ...*recordActionProps) *recordAction {
+	a := &recordAction{
+		timestamp: time.Now(),
+		resource:  "compose:record",
+		action:    "restoreRevision",
+		log:       "restored {{record}} to revision {{revision}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // RecordActionIteratorInvoked returns "compose:record.iteratorInvoked" action
 //
 // This function is auto-generated.
@@ -936,6 +1046,74 @@ func RecordErrInvalidModuleID(mm ...*recordActionProps) *errors.Error {
 	return e
 }
 
+// RecordErrRevisionNotFound returns "compose:record.revisionNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrRevisionNotFound(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("record revision not found", nil),
+
+		errors.Meta("type", "revisionNotFound"),
+		errors.Meta("resource", "compose:record"),
+
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.revisionNotFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// RecordErrNotDeleted returns "compose:record.notDeleted" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrNotDeleted(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("record is not deleted", nil),
+
+		errors.Meta("type", "notDeleted"),
+		errors.Meta("resource", "compose:record"),
+
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.notDeleted"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // RecordErrStaleData returns "compose:record.staleData" as *errors.Error
 //
 //
@@ -1294,6 +1472,78 @@ func RecordErrNotAllowedToUndelete(mm ...*recordActionProps) *errors.Error {
 	return e
 }
 
+// RecordErrNotAllowedToSearchTrash returns "compose:record.notAllowedToSearchTrash" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrNotAllowedToSearchTrash(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to search or list deleted records", nil),
+
+		errors.Meta("type", "notAllowedToSearchTrash"),
+		errors.Meta("resource", "compose:record"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(recordLogMetaKey{}, "failed to search or list deleted records; insufficient permissions"),
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.notAllowedToSearchTrash"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// RecordErrNotAllowedToPurge returns "compose:record.notAllowedToPurge" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrNotAllowedToPurge(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to purge this record", nil),
+
+		errors.Meta("type", "notAllowedToPurge"),
+		errors.Meta("resource", "compose:record"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(recordLogMetaKey{}, "failed to purge {{record}}; insufficient permissions"),
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.notAllowedToPurge"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // RecordErrNotAllowedToChangeFieldValue returns "compose:record.notAllowedToChangeFieldValue" as *errors.Error
 //
 //
@@ -1536,6 +1786,40 @@ func RecordErrValueInput(mm ...*recordActionProps) *errors.Error {
 	return e
 }
 
+// RecordErrMaxRecordLimitReached returns "compose:record.maxRecordLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RecordErrMaxRecordLimitReached(mm ...*recordActionProps) *errors.Error {
+	var p = &recordActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your record limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxRecordLimitReached"),
+		errors.Meta("resource", "compose:record"),
+
+		errors.Meta(recordPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "compose"),
+		errors.Meta(locale.ErrorMetaKey{}, "record.errors.maxRecordLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"go.uber.org/zap"
+)
+
+type (
+	websocketPublisher interface {
+		Publish(kind string, prepare func(auth.Identifiable) interface{}, channels ...string) error
+		Broadcast(ctx context.Context, kind string, payload interface{}) error
+		HandleBroadcast(kind string, fn func(ctx context.Context, payload []byte) error)
+	}
+
+	// recordChange is sent to websocket subscribers when record is created, updated or deleted
+	recordChange struct {
+		Action      string    `json:"action"`
+		NamespaceID uint64    `json:"namespaceID,string"`
+		ModuleID    uint64    `json:"moduleID,string"`
+		RecordID    uint64    `json:"recordID,string"`
+		UserID      uint64    `json:"userID,string"`
+		Timestamp   time.Time `json:"timestamp"`
+
+		// record with values that subscriber is allowed to read;
+		// omitted for deleted records
+		Record *types.Record `json:"record,omitempty"`
+	}
+
+	// recordChangeBroadcast is sent to all nodes that deliver
+	// the change to their websocket subscribers
+	recordChangeBroadcast struct {
+		Change recordChange  `json:"change"`
+		Record *types.Record `json:"record"`
+	}
+)
+
+const (
+	recordChangeMessageType = "composeRecordChange"
+
+	recordChangeCreate = "create"
+	recordChangeUpdate = "update"
+	recordChangeDelete = "delete"
+)
+
+// RecordChangeChannels returns websocket channels where changes of the record are published
+//
+// Clients can subscribe to all changes in a namespace or a module or to changes of a single record.
+func RecordChangeChannels(r *types.Record) []string {
+	return []string{
+		"compose:namespace:" + strconv.FormatUint(r.NamespaceID, 10),
+		"compose:module:" + strconv.FormatUint(r.ModuleID, 10),
+		"compose:record:" + strconv.FormatUint(r.ID, 10),
+	}
+}
+
+// publishChange broadcasts record change to all nodes
+//
+// Each node delivers the change to its websocket subscribers (see deliverChange).
+func (svc record) publishChange(ctx context.Context, action string, m *types.Module, r *types.Record) {
+	if svc.publisher == nil || r == nil {
+		return
+	}
+
+	msg := recordChangeBroadcast{
+		Change: recordChange{
+			Action:      action,
+			NamespaceID: r.NamespaceID,
+			ModuleID:    r.ModuleID,
+			RecordID:    r.ID,
+			UserID:      auth.GetIdentityFromContext(ctx).Identity(),
+			Timestamp:   *now(),
+		},
+		Record: r,
+	}
+
+	if err := svc.publisher.Broadcast(ctx, recordChangeMessageType, msg); err != nil {
+		DefaultLogger.Warn("could not publish record change", zap.Error(err), zap.Uint64("recordID", r.ID))
+	}
+}
+
+// deliverChange sends broadcast record change to all websocket subscribers
+// on this node that are allowed to read the record
+//
+// Record values are filtered with the subscriber's field read permissions.
+func (svc record) deliverChange(ctx context.Context, payload []byte) error {
+	var (
+		msg = recordChangeBroadcast{}
+
+		m    *types.Module
+		mErr error
+		once sync.Once
+
+		// subscribers are checked without the context of the request
+		// that made the change
+		bgCtx = context.Background()
+	)
+
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return err
+	}
+
+	if msg.Record == nil {
+		return nil
+	}
+
+	prepare := func(identity auth.Identifiable) interface{} {
+		if identity == nil {
+			return nil
+		}
+
+		// module is loaded on this node and only when there are subscribers
+		once.Do(func() { m, mErr = loadModule(ctx, svc.store, msg.Record.ModuleID) })
+		if mErr != nil {
+			return nil
+		}
+
+		var (
+			ictx = auth.SetIdentityToContext(bgCtx, identity)
+			rec  = msg.Record.Clone()
+			chg  = msg.Change
+		)
+
+		rec.SetModule(m)
+		if !svc.ac.CanReadRecord(ictx, rec) {
+			return nil
+		}
+
+		if chg.Action != recordChangeDelete {
+			ComposeRecordFilterAC(ictx, svc.ac, m, rec)
+			chg.Record = rec
+		}
+
+		return chg
+	}
+
+	if err := svc.publisher.Publish(recordChangeMessageType, prepare, RecordChangeChannels(msg.Record)...); err != nil {
+		return err
+	}
+
+	return mErr
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// Revisions returns all stored revisions of a record
+//
+// Changes on fields that current user is not allowed to read are removed
+func (svc record) Revisions(ctx context.Context, namespaceID, moduleID, recordID uint64) (rr types.RecordRevisionSet, err error) {
+	var (
+		ns     *types.Namespace
+		m      *types.Module
+		r      *types.Record
+		aProps = &recordActionProps{record: &types.Record{ID: recordID, ModuleID: moduleID, NamespaceID: namespaceID}}
+	)
+
+	err = func() error {
+		if ns, m, r, err = loadRecordCombo(ctx, svc.store, namespaceID, moduleID, recordID); err != nil {
+			return err
+		}
+
+		aProps.setNamespace(ns)
+		aProps.setModule(m)
+		aProps.setRecord(r)
+
+		if !svc.ac.CanReadRecord(ctx, r) {
+			return RecordErrNotAllowedToRead()
+		}
+
+		if rr, err = loadRecordRevisions(ctx, svc.store, recordID); err != nil {
+			return err
+		}
+
+		ComposeRecordRevisionFilterAC(ctx, svc.ac, m, rr...)
+		return nil
+	}()
+
+	return rr, svc.recordAction(ctx, aProps, RecordActionRevisions, err)
+}
+
+// RestoreRevision restores record values to the state they were in right after the given revision
+//
+// Restored values are passed through the regular update procedure
+// (validation, before/after update events, access control) and stored as a new revision
+func (svc record) RestoreRevision(ctx context.Context, namespaceID, moduleID, recordID uint64, revision uint) (rec *types.Record, err error) {
+	var (
+		r      *types.Record
+		rr     types.RecordRevisionSet
+		aProps = &recordActionProps{record: &types.Record{ID: recordID, ModuleID: moduleID, NamespaceID: namespaceID}}
+	)
+
+	err = func() error {
+		if _, _, r, err = loadRecordCombo(ctx, svc.store, namespaceID, moduleID, recordID); err != nil {
+			return err
+		}
+
+		aProps.setRecord(r)
+
+		if r.DeletedAt != nil {
+			return RecordErrNotFound()
+		}
+
+		if rr, err = loadRecordRevisions(ctx, svc.store, recordID); err != nil {
+			return err
+		}
+
+		var rev *types.RecordRevision
+		for i := range rr {
+			if rr[i].Revision == revision {
+				rev = rr[i]
+				break
+			}
+		}
+
+		if rev == nil {
+			return RecordErrRevisionNotFound()
+		}
+
+		aProps.setRevision(rev)
+
+		upd := &types.Record{
+			ID:          r.ID,
+			ModuleID:    r.ModuleID,
+			NamespaceID: r.NamespaceID,
+			OwnedBy:     r.OwnedBy,
+			Values:      rr.RevertTo(r.Values, revision),
+		}
+
+		rec, err = svc.updateWithRevision(ctx, upd, types.RecordRevisionOperationRestore)
+		return err
+	}()
+
+	return rec, svc.recordAction(ctx, aProps, RecordActionRestoreRevision, err)
+}
+
+// storeRevision stores revision of the record when revisions are enabled on the module
+//
+// Updates and restores that did not change any value are not stored
+func (svc record) storeRevision(ctx context.Context, s store.Storer, m *types.Module, r *types.Record, op types.RecordRevisionOperation, cc types.RecordRevisionChangeSet) (err error) {
+	if !m.Config.RecordRevisions.Enabled {
+		return nil
+	}
+
+	switch op {
+	case types.RecordRevisionOperationUpdate, types.RecordRevisionOperationRestore:
+		if len(cc) == 0 {
+			return nil
+		}
+	}
+
+	if cc == nil {
+		cc = types.RecordRevisionChangeSet{}
+	}
+
+	rev := &types.RecordRevision{
+		ID:          nextID(),
+		RecordID:    r.ID,
+		ModuleID:    m.ID,
+		NamespaceID: m.NamespaceID,
+		Operation:   op,
+		Changes:     cc,
+		UserID:      auth.GetIdentityFromContext(ctx).Identity(),
+		CreatedAt:   *now(),
+	}
+
+	if rev.Revision, err = lastRecordRevision(ctx, s, r.ID); err != nil {
+		return
+	}
+
+	// revisions are numbered sequentially (per record)
+	// unique index on record & revision prevents duplicates
+	rev.Revision++
+
+	return store.CreateComposeRecordRevision(ctx, s, rev)
+}
+
+// storePartialRevision stores revision of values that were updated
+// without updating the whole record (see PartialComposeRecordValueUpdate)
+func (svc record) storePartialRevision(ctx context.Context, s store.Storer, m *types.Module, r *types.Record, vv ...*types.RecordValue) error {
+	if !m.Config.RecordRevisions.Enabled {
+		return nil
+	}
+
+	upd := r.Values.Clone()
+	for _, v := range vv {
+		upd = upd.Set(v.Clone())
+	}
+
+	return svc.storeRevision(ctx, s, m, r, types.RecordRevisionOperationUpdate, types.RecordRevisionChanges(m.Fields, r.Values, upd))
+}
+
+// ComposeRecordRevisionFilterAC removes changes of fields that can not be read
+func ComposeRecordRevisionFilterAC(ctx context.Context, ac recordValueAccessController, m *types.Module, rr ...*types.RecordRevision) {
+	var (
+		readableFields = make([]string, 0, len(m.Fields))
+	)
+
+	for _, f := range m.Fields {
+		if ac.CanReadRecordValue(ctx, f) {
+			readableFields = append(readableFields, f.Name)
+		}
+	}
+
+	for _, r := range rr {
+		r.Changes = r.Changes.FilterByName(readableFields...)
+	}
+}
+
+// loadRecordRevisions loads all revisions of a record ordered by revision number
+func loadRecordRevisions(ctx context.Context, s store.Storer, recordID uint64) (rr types.RecordRevisionSet, err error) {
+	f := types.RecordRevisionFilter{RecordID: recordID}
+	if err = f.Sort.Set("revision"); err != nil {
+		return
+	}
+
+	rr, _, err = store.SearchComposeRecordRevisions(ctx, s, f)
+	return
+}
+
+// lastRecordRevision returns number of the latest stored record revision (0 if there are none)
+func lastRecordRevision(ctx context.Context, s store.Storer, recordID uint64) (uint, error) {
+	f := types.RecordRevisionFilter{RecordID: recordID, Paging: filter.Paging{Limit: 1}}
+	if err := f.Sort.Set("revision DESC"); err != nil {
+		return 0, err
+	}
+
+	rr, _, err := store.SearchComposeRecordRevisions(ctx, s, f)
+	if err != nil || len(rr) == 0 {
+		return 0, err
+	}
+
+	return rr[0].Revision, nil
+}
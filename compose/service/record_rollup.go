@@ -0,0 +1,438 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+type (
+	// rollupTarget is a rollup field with the module it belongs to
+	// and the module it aggregates over
+	rollupTarget struct {
+		module  *types.Module
+		field   *types.ModuleField
+		related *types.Module
+	}
+
+	// rollupKey identifies rollup field on a record
+	rollupKey struct {
+		recordID uint64
+		fieldID  uint64
+	}
+
+	// rollupTargetCache keeps rollup targets of each namespace
+	// indexed by the module they aggregate over
+	//
+	// Cache is reset when modules change (on this or on other nodes);
+	// namespaces also expire after rollupTargetsTTL so that modules
+	// changed directly in the store (imports) are picked up.
+	rollupTargetCache struct {
+		l  sync.RWMutex
+		nn map[uint64]*rollupNamespace
+
+		// bumped on each reset so that targets loaded
+		// before the reset are not cached
+		gen uint
+	}
+
+	rollupNamespace struct {
+		loadedAt time.Time
+		targets  map[uint64][]*rollupTarget
+	}
+)
+
+const (
+	// number of records loaded at once when rollups are calculated or recomputed
+	rollupBatchSize = 500
+
+	rollupTargetsTTL = time.Minute
+)
+
+// validateModuleRollups checks rollup configuration of all module fields
+//
+// Related module must exist in the same namespace and the relation field must be
+// a Record field; aggregated field (all but count) must be a Number field
+func validateModuleRollups(ctx context.Context, s store.Storer, m *types.Module) error {
+	for _, f := range m.Fields {
+		if !f.IsRollup() {
+			continue
+		}
+
+		var (
+			r   = f.Expressions.Rollup
+			rel = m
+			err error
+		)
+
+		if !r.IsValidAggregate() || f.Multi {
+			return ModuleErrInvalidRollup()
+		}
+
+		if r.ModuleID != m.ID {
+			if rel, err = loadModule(ctx, s, r.ModuleID); err != nil || rel.NamespaceID != m.NamespaceID {
+				return ModuleErrInvalidRollup()
+			}
+		}
+
+		if rf := rel.Fields.FindByName(r.RelationField); rf == nil || rf.Kind != "Record" {
+			return ModuleErrInvalidRollup()
+		}
+
+		if r.Aggregate != types.RollupCount {
+			if vf := rel.Fields.FindByName(r.ValueField); vf == nil || !vf.IsNumeric() {
+				return ModuleErrInvalidRollup()
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadRollupTargets returns all rollup fields of the namespace
+// indexed by the module they aggregate over
+func loadRollupTargets(ctx context.Context, s store.Storer, namespaceID uint64) (tt map[uint64][]*rollupTarget, err error) {
+	mm, _, err := store.SearchComposeModules(ctx, s, types.ModuleFilter{NamespaceID: namespaceID})
+	if err != nil {
+		return
+	}
+
+	if err = loadModuleFields(ctx, s, mm...); err != nil {
+		return
+	}
+
+	tt = make(map[uint64][]*rollupTarget)
+	for _, pm := range mm {
+		for _, f := range pm.Fields {
+			if f.IsRollup() {
+				moduleID := f.Expressions.Rollup.ModuleID
+				tt[moduleID] = append(tt[moduleID], &rollupTarget{module: pm, field: f})
+			}
+		}
+	}
+
+	return
+}
+
+// rollupTargets returns all rollup fields (in the same namespace)
+// that aggregate over records of the given module
+func (svc record) rollupTargets(ctx context.Context, s store.Storer, m *types.Module) ([]*rollupTarget, error) {
+	var (
+		c  = svc.rollups
+		tt map[uint64][]*rollupTarget
+	)
+
+	if c == nil {
+		c = &rollupTargetCache{}
+	}
+
+	c.l.RLock()
+	rn, gen := c.nn[m.NamespaceID], c.gen
+	c.l.RUnlock()
+
+	if rn != nil && time.Since(rn.loadedAt) < rollupTargetsTTL {
+		tt = rn.targets
+	} else {
+		// loaded outside of the lock; targets are
+		// read through the (transaction) store
+		loaded, err := loadRollupTargets(ctx, s, m.NamespaceID)
+		if err != nil {
+			return nil, err
+		}
+
+		c.l.Lock()
+		if gen == c.gen {
+			if c.nn == nil {
+				c.nn = make(map[uint64]*rollupNamespace)
+			}
+
+			c.nn[m.NamespaceID] = &rollupNamespace{loadedAt: time.Now(), targets: loaded}
+		}
+		c.l.Unlock()
+
+		tt = loaded
+	}
+
+	out := make([]*rollupTarget, len(tt[m.ID]))
+	for i, t := range tt[m.ID] {
+		out[i] = &rollupTarget{module: t.module, field: t.field, related: m}
+	}
+
+	return out, nil
+}
+
+// reset drops all cached rollup targets
+func (c *rollupTargetCache) reset() {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	c.nn = nil
+	c.gen++
+}
+
+// calculateRollup aggregates related records of the parent record
+//
+// Related records are loaded page by page
+func calculateRollup(ctx context.Context, s store.Storer, rel *types.Module, r *types.ModuleFieldRollup, parentID uint64) (string, bool, error) {
+	q := fmt.Sprintf("%s = %d", r.RelationField, parentID)
+	if r.Filter != "" {
+		q = fmt.Sprintf("(%s) AND (%s)", q, r.Filter)
+	}
+
+	var (
+		acc = r.Accumulator()
+
+		rr  types.RecordSet
+		err error
+		f   = types.RecordFilter{
+			ModuleID:    rel.ID,
+			NamespaceID: rel.NamespaceID,
+			Query:       q,
+			Paging:      filter.Paging{Limit: rollupBatchSize},
+		}
+	)
+
+	for {
+		if rr, f, err = store.SearchComposeRecords(ctx, s, rel, f); err != nil {
+			return "", false, err
+		}
+
+		acc.Add(rr)
+
+		if f.NextPage == nil {
+			break
+		}
+
+		f.PageCursor = f.NextPage
+		f.NextPage = nil
+	}
+
+	value, ok := acc.Result()
+	return value, ok, nil
+}
+
+// applyRollups sets values of all rollup fields on the record
+//
+// Any values sent for the rollup fields are replaced
+func (svc record) applyRollups(ctx context.Context, s store.Storer, m *types.Module, rec *types.Record) error {
+	for _, f := range m.Fields {
+		if !f.IsRollup() {
+			continue
+		}
+
+		var (
+			r   = f.Expressions.Rollup
+			rel = m
+			err error
+		)
+
+		if r.ModuleID != m.ID {
+			if rel, err = loadModule(ctx, s, r.ModuleID); err != nil {
+				return err
+			}
+		}
+
+		value, ok, err := calculateRollup(ctx, s, rel, r, rec.ID)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			rec.Values = rec.Values.Replace(f.Name, value)
+		} else {
+			rec.Values = rec.Values.Replace(f.Name)
+		}
+	}
+
+	return nil
+}
+
+// updateRollups recalculates rollup fields on all records that are
+// referenced by the given (created, updated or deleted) records
+//
+// Changes are propagated further when the updated rollup field is
+// aggregated by another rollup (rollup of a rollup)
+func (svc record) updateRollups(ctx context.Context, s store.Storer, m *types.Module, rr ...*types.Record) error {
+	return svc.propagateRollups(ctx, s, m, make(map[rollupKey]bool), rr...)
+}
+
+func (svc record) propagateRollups(ctx context.Context, s store.Storer, m *types.Module, visited map[rollupKey]bool, rr ...*types.Record) error {
+	tt, err := svc.rollupTargets(ctx, s, m)
+	if err != nil || len(tt) == 0 {
+		return err
+	}
+
+	type (
+		parentKey struct {
+			moduleID uint64
+			recordID uint64
+		}
+	)
+
+	var (
+		// rollup fields to update, grouped by the referenced (parent) record
+		// so that each record is updated once
+		parents []parentKey
+		targets = make(map[parentKey][]*rollupTarget)
+		modules = make(map[uint64]*types.Module)
+	)
+
+	for _, t := range tt {
+		for _, rec := range rr {
+			if rec == nil {
+				continue
+			}
+
+			for _, v := range rec.Values.FilterByName(t.field.Expressions.Rollup.RelationField) {
+				parentID, _ := strconv.ParseUint(v.Value, 10, 64)
+				if parentID == 0 || visited[rollupKey{parentID, t.field.ID}] {
+					continue
+				}
+
+				// each rollup field of each record is updated once; this also
+				// breaks the propagation when records reference each other in a cycle
+				visited[rollupKey{parentID, t.field.ID}] = true
+
+				pk := parentKey{t.module.ID, parentID}
+				if targets[pk] == nil {
+					parents = append(parents, pk)
+				}
+
+				targets[pk] = append(targets[pk], t)
+				modules[t.module.ID] = t.module
+			}
+		}
+	}
+
+	for _, pk := range parents {
+		if err = svc.updateRollup(ctx, s, modules[pk.moduleID], pk.recordID, targets[pk], visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateRollup recalculates and stores values of rollup fields on one record
+//
+// Record is updated (with revision) only when any of the values change
+func (svc record) updateRollup(ctx context.Context, s store.Storer, m *types.Module, parentID uint64, tt []*rollupTarget, visited map[rollupKey]bool) error {
+	parent, err := store.LookupComposeRecordByID(ctx, s, m, parentID)
+	if errors.IsNotFound(err) || (err == nil && (parent.ModuleID != m.ID || parent.DeletedAt != nil)) {
+		// related record points to a record that does not exist
+		// (or is deleted), nothing to update
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var (
+		old = parent.Clone()
+		cc  types.RecordRevisionChangeSet
+	)
+
+	for _, t := range tt {
+		value, ok, err := calculateRollup(ctx, s, t.related, t.field.Expressions.Rollup, parentID)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			parent.Values = parent.Values.Replace(t.field.Name, value)
+		} else {
+			parent.Values = parent.Values.Replace(t.field.Name)
+		}
+	}
+
+	if cc = types.RecordRevisionChanges(m.Fields, old.Values, parent.Values); len(cc) == 0 {
+		return nil
+	}
+
+	parent.UpdatedAt = now()
+	parent.UpdatedBy = auth.GetIdentityFromContext(ctx).Identity()
+
+	if err = store.UpdateComposeRecord(ctx, s, m, parent); err != nil {
+		return err
+	}
+
+	if err = svc.storeRevision(ctx, s, m, parent, types.RecordRevisionOperationUpdate, cc); err != nil {
+		return err
+	}
+
+	return svc.propagateRollups(ctx, s, m, visited, old, parent)
+}
+
+// RecomputeRollups recalculates all rollup fields on all records of the module
+//
+// Intended to be used after rollup definitions change; access control
+// is not checked. Returns number of updated records
+func (svc record) RecomputeRollups(ctx context.Context, namespaceID, moduleID uint64) (n uint, err error) {
+	var (
+		m   *types.Module
+		rel = make(map[uint64]*types.Module)
+		tt  []*rollupTarget
+
+		rr types.RecordSet
+		f  = types.RecordFilter{
+			ModuleID:    moduleID,
+			NamespaceID: namespaceID,
+			Paging:      filter.Paging{Limit: rollupBatchSize},
+		}
+	)
+
+	if _, m, err = loadModuleWithNamespace(ctx, svc.store, namespaceID, moduleID); err != nil {
+		return
+	}
+
+	for _, fld := range m.Fields {
+		if !fld.IsRollup() {
+			continue
+		}
+
+		r := fld.Expressions.Rollup
+		if rel[r.ModuleID] == nil {
+			if rel[r.ModuleID], err = loadModule(ctx, svc.store, r.ModuleID); err != nil {
+				return
+			}
+		}
+
+		tt = append(tt, &rollupTarget{module: m, field: fld, related: rel[r.ModuleID]})
+	}
+
+	if len(tt) == 0 {
+		return
+	}
+
+	for {
+		if rr, f, err = store.SearchComposeRecords(ctx, svc.store, m, f); err != nil {
+			return
+		}
+
+		for _, rec := range rr {
+			err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+				return svc.updateRollup(ctx, s, m, rec.ID, tt, make(map[rollupKey]bool))
+			})
+
+			if err != nil {
+				return
+			}
+
+			n++
+		}
+
+		if f.NextPage == nil {
+			return
+		}
+
+		f.PageCursor = f.NextPage
+		f.NextPage = nil
+	}
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/service/values"
 	"github.com/cortezaproject/corteza-server/compose/types"
@@ -856,3 +858,219 @@ func TestRecord_contextualRolesAccessControl(t *testing.T) {
 	hits, _, err = svc.Find(ctx, f)
 	req.Len(hits, 9)
 }
+
+// testRecordChangePublisher passes broadcast messages
+// (encoded, as they would be sent to other nodes) to the handler
+type testRecordChangePublisher struct {
+	kind     string
+	prepare  func(auth.Identifiable) interface{}
+	channels []string
+
+	handler func(context.Context, []byte) error
+}
+
+func (p *testRecordChangePublisher) Publish(kind string, prepare func(auth.Identifiable) interface{}, channels ...string) error {
+	p.kind, p.prepare, p.channels = kind, prepare, channels
+	return nil
+}
+
+func (p *testRecordChangePublisher) Broadcast(ctx context.Context, kind string, payload interface{}) error {
+	pb, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return p.handler(ctx, pb)
+}
+
+func (p *testRecordChangePublisher) HandleBroadcast(kind string, fn func(context.Context, []byte) error) {
+	p.handler = fn
+}
+
+func TestRecord_publishChange(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+
+		s, err = sqlite3.ConnectInMemoryWithDebug(ctx)
+
+		rbacService = rbac.NewService(zap.NewNop(), nil)
+		pub         = &testRecordChangePublisher{}
+
+		svc = &record{
+			ac:        &accessControl{rbac: rbacService},
+			publisher: pub,
+			store:     s,
+		}
+
+		ns          = &types.Namespace{ID: nextID()}
+		mod         = &types.Module{ID: nextID(), NamespaceID: ns.ID, CreatedAt: *now()}
+		publicField = &types.ModuleField{ID: nextID(), NamespaceID: ns.ID, ModuleID: mod.ID, Name: "public", Kind: "String", Place: 1, CreatedAt: *now()}
+		secretField = &types.ModuleField{ID: nextID(), NamespaceID: ns.ID, ModuleID: mod.ID, Name: "secret", Kind: "String", Place: 2, CreatedAt: *now()}
+
+		readerRoleID   = nextID()
+		outsiderRoleID = nextID()
+
+		rec = &types.Record{
+			ID:          nextID(),
+			ModuleID:    mod.ID,
+			NamespaceID: ns.ID,
+			Values: types.RecordValueSet{
+				&types.RecordValue{Name: "public", Value: "abc"},
+				&types.RecordValue{Name: "secret", Value: "xyz"},
+			},
+		}
+	)
+
+	req.NoError(err)
+	req.NoError(store.Upgrade(ctx, zap.NewNop(), s))
+	req.NoError(store.TruncateComposeModules(ctx, s))
+	req.NoError(store.TruncateComposeModuleFields(ctx, s))
+
+	mod.Fields = types.ModuleFieldSet{publicField, secretField}
+	req.NoError(store.CreateComposeModule(ctx, s, mod))
+	req.NoError(store.CreateComposeModuleField(ctx, s, mod.Fields...))
+
+	pub.HandleBroadcast(recordChangeMessageType, svc.deliverChange)
+
+	rbacService.UpdateRoles(
+		rbac.CommonRole.Make(readerRoleID, "reader"),
+		rbac.CommonRole.Make(outsiderRoleID, "outsider"),
+	)
+
+	rbacService.Grant(ctx,
+		rbac.AllowRule(readerRoleID, types.RecordRbacResource(0, 0, 0), "read"),
+		rbac.AllowRule(readerRoleID, publicField.RbacResource(), "record.value.read"),
+		rbac.DenyRule(readerRoleID, secretField.RbacResource(), "record.value.read"),
+		rbac.DenyRule(outsiderRoleID, types.RecordRbacResource(0, 0, 0), "read"),
+	)
+
+	svc.publishChange(ctx, recordChangeUpdate, mod, rec)
+
+	req.Equal(recordChangeMessageType, pub.kind)
+	req.Equal(RecordChangeChannels(rec), pub.channels)
+
+	req.Nil(pub.prepare(auth.Authenticated(nextID(), outsiderRoleID)))
+
+	chg, ok := pub.prepare(auth.Authenticated(nextID(), readerRoleID)).(recordChange)
+	req.True(ok)
+	req.Equal(recordChangeUpdate, chg.Action)
+	req.Equal(rec.ID, chg.RecordID)
+	req.NotNil(chg.Record)
+	req.NotNil(chg.Record.Values.Get("public", 0))
+	req.Nil(chg.Record.Values.Get("secret", 0))
+
+	// published record is not modified
+	req.NotNil(rec.Values.Get("secret", 0))
+
+	svc.publishChange(ctx, recordChangeDelete, mod, rec)
+	chg, ok = pub.prepare(auth.Authenticated(nextID(), readerRoleID)).(recordChange)
+	req.True(ok)
+	req.Nil(chg.Record)
+}
+
+func TestRecord_checkLimits(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx    = context.Background()
+		s, err = sqlite3.ConnectInMemoryWithDebug(ctx)
+	)
+
+	req.NoError(err)
+	req.NoError(store.Upgrade(ctx, zap.NewNop(), s))
+	req.NoError(store.TruncateComposeRecords(ctx, s, nil))
+
+	var (
+		mod = &types.Module{ID: nextID(), NamespaceID: nextID()}
+		rec = &types.Record{ID: nextID(), ModuleID: mod.ID, NamespaceID: mod.NamespaceID, CreatedAt: *now()}
+
+		svc = &record{
+			store:   s,
+			opt:     RecordOptions{LimitRecords: 2},
+			counter: &recordCounter{},
+		}
+	)
+
+	req.NoError(store.CreateComposeRecord(ctx, s, mod, rec))
+
+	// count is loaded from the store and cached
+	req.NoError(svc.checkLimits(ctx))
+	req.True(RecordErrMaxRecordLimitReached().Is(svc.checkLimits(ctx)))
+
+	// reservation of record that was not stored is released
+	svc.releaseLimits(1)
+	req.NoError(svc.checkLimits(ctx))
+	req.Error(svc.checkLimits(ctx))
+
+	// removed records are noticed once cached count expires
+	req.NoError(store.DeleteComposeRecord(ctx, s, mod, rec))
+	req.Error(svc.checkLimits(ctx))
+
+	svc.counter.countedAt = svc.counter.countedAt.Add(-recordCountTTL - time.Second)
+	req.NoError(svc.checkLimits(ctx))
+}
+
+func TestRecord_rollupTargets(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx    = context.Background()
+		s, err = sqlite3.ConnectInMemoryWithDebug(ctx)
+	)
+
+	req.NoError(err)
+	req.NoError(store.Upgrade(ctx, zap.NewNop(), s))
+
+	var (
+		nsID = nextID()
+		line = &types.Module{ID: nextID(), NamespaceID: nsID, Handle: "line", CreatedAt: *now()}
+
+		svc = &record{rollups: &rollupTargetCache{}}
+
+		makeRollup = func(handle string) *types.Module {
+			m := &types.Module{ID: nextID(), NamespaceID: nsID, Handle: handle, CreatedAt: *now()}
+			m.Fields = types.ModuleFieldSet{&types.ModuleField{
+				ID:       nextID(),
+				ModuleID: m.ID,
+				Name:     "total",
+				Kind:     "Number",
+				Expressions: types.ModuleFieldExpr{
+					Rollup: &types.ModuleFieldRollup{ModuleID: line.ID, RelationField: "parent", Aggregate: types.RollupCount},
+				},
+				CreatedAt: *now(),
+			}}
+
+			req.NoError(store.CreateComposeModule(ctx, s, m))
+			req.NoError(store.CreateComposeModuleField(ctx, s, m.Fields...))
+			return m
+		}
+	)
+
+	req.NoError(store.CreateComposeModule(ctx, s, line))
+	invoice := makeRollup("invoice")
+
+	tt, err := svc.rollupTargets(ctx, s, line)
+	req.NoError(err)
+	req.Len(tt, 1)
+	req.Equal(invoice.ID, tt[0].module.ID)
+	req.Equal(line, tt[0].related)
+
+	// no rollups over the module
+	tt, err = svc.rollupTargets(ctx, s, invoice)
+	req.NoError(err)
+	req.Empty(tt)
+
+	// targets are cached until reset
+	makeRollup("order")
+
+	tt, err = svc.rollupTargets(ctx, s, line)
+	req.NoError(err)
+	req.Len(tt, 1)
+
+	svc.rollups.reset()
+
+	tt, err = svc.rollupTargets(ctx, s, line)
+	req.NoError(err)
+	req.Len(tt, 2)
+}
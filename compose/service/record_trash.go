@@ -0,0 +1,449 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/service/event"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"github.com/cortezaproject/corteza-server/store"
+	"go.uber.org/zap"
+)
+
+type (
+	// trashOperation is applied on each of the records in bulk undelete or purge
+	trashOperation func(ctx context.Context, ns *types.Namespace, m *types.Module, recordID uint64) (*types.Record, error)
+)
+
+// Trash returns deleted records of a module
+//
+// Only users that can undelete or purge records on the module can see them
+func (svc record) Trash(ctx context.Context, filter types.RecordFilter) (set types.RecordSet, f types.RecordFilter, err error) {
+	var (
+		m      *types.Module
+		aProps = &recordActionProps{filter: &filter}
+	)
+
+	err = func() error {
+		if m, err = loadModule(ctx, svc.store, filter.ModuleID); err != nil {
+			return err
+		}
+
+		aProps.setModule(m)
+
+		if !svc.ac.CanSearchRecordsOnModule(ctx, m) {
+			return RecordErrNotAllowedToSearch()
+		}
+
+		if !svc.ac.CanUndeleteRecordOnModule(ctx, m) && !svc.ac.CanPurgeRecordOnModule(ctx, m) {
+			return RecordErrNotAllowedToSearchTrash()
+		}
+
+		filter.Deleted = trashFilterState
+		filter.Check = ComposeRecordFilterChecker(ctx, svc.ac, m)
+
+		if set, f, err = store.SearchComposeRecords(ctx, svc.store, m, filter); err != nil {
+			return err
+		}
+
+		_ = set.Walk(func(r *types.Record) error {
+			r.SetModule(m)
+			r.Values = svc.sanitizer.RunXSS(m, r.Values)
+			return nil
+		})
+
+		ComposeRecordFilterAC(ctx, svc.ac, m, set...)
+
+		return nil
+	}()
+
+	return set, f, svc.recordAction(ctx, aProps, RecordActionTrash, err)
+}
+
+// UndeleteByID restores one or more deleted records (all from the same module and namespace)
+func (svc record) UndeleteByID(ctx context.Context, namespaceID, moduleID uint64, recordIDs ...uint64) error {
+	return svc.trashBulk(ctx, namespaceID, moduleID, RecordActionUndelete, svc.undelete, recordIDs...)
+}
+
+// PurgeByID permanently removes one or more deleted records (all from the same module and namespace)
+//
+// Records that are not deleted can not be purged
+func (svc record) PurgeByID(ctx context.Context, namespaceID, moduleID uint64, recordIDs ...uint64) error {
+	return svc.trashBulk(ctx, namespaceID, moduleID, RecordActionPurge, svc.purge, recordIDs...)
+}
+
+// PurgeExpired permanently removes deleted records that were kept in the trash
+// longer than the retention period configured on their namespace
+//
+// Purge is done with service user identity and without access control checks
+func (svc record) PurgeExpired(ctx context.Context) error {
+	ctx = auth.SetIdentityToContext(ctx, auth.ServiceUser())
+
+	nn, _, err := store.SearchComposeNamespaces(ctx, svc.store, types.NamespaceFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range nn {
+		if ns.Meta.RecordTrashRetentionDays == 0 {
+			continue
+		}
+
+		if err = svc.purgeExpiredOnNamespace(ctx, ns, now().AddDate(0, 0, -int(ns.Meta.RecordTrashRetentionDays))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchTrash periodically purges expired records from the trash
+func (svc record) WatchTrash(ctx context.Context) {
+	var (
+		log     = DefaultLogger.Named("record-trash")
+		tTicker = time.NewTicker(trashPurgeInterval)
+	)
+
+	// only one node in the cluster purges the trash
+	cluster.Register(ctx, trashPurgeLease)
+
+	go func() {
+		defer sentry.Recover()
+		defer tTicker.Stop()
+		defer log.Info("stopped")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tTicker.C:
+				if !cluster.Holds(trashPurgeLease) {
+					continue
+				}
+
+				if err := svc.PurgeExpired(ctx); err != nil {
+					log.Error("failed to purge expired records", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (svc record) purgeExpiredOnNamespace(ctx context.Context, ns *types.Namespace, cutoff time.Time) error {
+	mm, _, err := store.SearchComposeModules(ctx, svc.store, types.ModuleFilter{NamespaceID: ns.ID})
+	if err != nil {
+		return err
+	}
+
+	for _, m := range mm {
+		f := types.RecordFilter{
+			NamespaceID: ns.ID,
+			ModuleID:    m.ID,
+			Deleted:     trashFilterState,
+			Check: func(r *types.Record) (bool, error) {
+				return r.DeletedAt != nil && r.DeletedAt.Before(cutoff), nil
+			},
+		}
+
+		rr, _, err := store.SearchComposeRecords(ctx, svc.store, m, f)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range rr {
+			aProps := &recordActionProps{}
+			aProps.setNamespace(ns)
+			aProps.setModule(m)
+			aProps.setRecord(r)
+
+			err = svc.recordAction(ctx, aProps, RecordActionPurge, svc.purgeRecord(ctx, m, r))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// trashBulk applies trash operation on each of the given records
+//
+// Errors are recorded per record; in case of a bulk operation
+// failed record does not stop the operation
+func (svc record) trashBulk(ctx context.Context, namespaceID, moduleID uint64, action func(...*recordActionProps) *recordAction, op trashOperation, recordIDs ...uint64) (err error) {
+	var (
+		aProps = &recordActionProps{
+			namespace: &types.Namespace{ID: namespaceID},
+			module:    &types.Module{ID: moduleID},
+		}
+
+		isBulk = len(recordIDs) > 1
+
+		ns *types.Namespace
+		m  *types.Module
+		r  *types.Record
+	)
+
+	err = func() error {
+		if namespaceID == 0 {
+			return RecordErrInvalidNamespaceID()
+		}
+		if moduleID == 0 {
+			return RecordErrInvalidModuleID()
+		}
+
+		ns, m, err = loadModuleWithNamespace(ctx, svc.store, namespaceID, moduleID)
+		if err != nil {
+			return err
+		}
+
+		aProps.setNamespace(ns)
+		aProps.setModule(m)
+
+		return nil
+	}()
+
+	if err != nil {
+		return svc.recordAction(ctx, aProps, action, err)
+	}
+
+	for _, recordID := range recordIDs {
+		err := func() (err error) {
+			r, err = op(ctx, ns, m, recordID)
+			aProps.setRecord(r)
+
+			return svc.recordAction(ctx, aProps, action, err)
+		}()
+
+		if err != nil && !isBulk {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (svc record) undelete(ctx context.Context, ns *types.Namespace, m *types.Module, recordID uint64) (r *types.Record, err error) {
+	if recordID == 0 {
+		return nil, RecordErrInvalidID()
+	}
+
+	if !svc.ac.CanUndeleteRecordOnModule(ctx, m) {
+		return nil, RecordErrNotAllowedToUndelete()
+	}
+
+	if _, _, r, err = loadRecordCombo(ctx, svc.store, ns.ID, m.ID, recordID); err != nil {
+		return nil, err
+	}
+
+	if r.DeletedAt == nil {
+		// record not deleted
+		return r, nil
+	}
+
+	if err = svc.checkLimits(ctx); err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			svc.releaseLimits(1)
+		}
+	}()
+
+	var (
+		old = r.Clone()
+		rve *types.RecordValueErrorSet
+	)
+
+	// ensure module ref is set before running through records workflows and scripts
+	r.SetModule(m)
+	old.SetModule(m)
+
+	if svc.optEmitEvents {
+		if err = svc.eventbus.WaitFor(ctx, event.RecordBeforeUndelete(r, old, m, ns, nil, nil)); err != nil {
+			return nil, err
+		}
+	}
+
+	r.DeletedAt = nil
+	r.DeletedBy = 0
+	r.UpdatedAt = now()
+	r.UpdatedBy = auth.GetIdentityFromContext(ctx).Identity()
+
+	// values were marked as deleted together with the record
+	for _, v := range r.Values {
+		v.DeletedAt = nil
+	}
+
+	// module could have changed (required, unique fields) or
+	// another record could have taken unique values while this one was deleted
+	if rve = svc.validator.Run(ctx, svc.store, m, r); !rve.IsValid() {
+		return nil, RecordErrValueInput().Wrap(rve)
+	}
+
+	err = store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) error {
+		// related records could change while this one was deleted
+		if err = svc.applyRollups(ctx, s, m, r); err != nil {
+			return err
+		}
+
+		if err = store.UpdateComposeRecord(ctx, s, m, r); err != nil {
+			return err
+		}
+
+		if err = svc.updateRollups(ctx, s, m, r); err != nil {
+			return err
+		}
+
+		return svc.storeRevision(ctx, s, m, r, types.RecordRevisionOperationUndelete, nil)
+	})
+
+	if rve = types.IsRecordValueErrorSet(err); rve != nil {
+		// unique constraints are checked by the store
+		return nil, RecordErrValueInput().Wrap(rve)
+	} else if err != nil {
+		return nil, err
+	}
+
+	r.SetModule(m)
+
+	if svc.optEmitEvents {
+		_ = svc.eventbus.WaitFor(ctx, event.RecordAfterUndeleteImmutable(r, old, m, ns, nil, nil))
+	}
+
+	// undeleted record re-appears in the lists
+	svc.publishChange(ctx, recordChangeCreate, m, r)
+	return r, nil
+}
+
+func (svc record) purge(ctx context.Context, ns *types.Namespace, m *types.Module, recordID uint64) (r *types.Record, err error) {
+	if recordID == 0 {
+		return nil, RecordErrInvalidID()
+	}
+
+	if !svc.ac.CanPurgeRecordOnModule(ctx, m) {
+		return nil, RecordErrNotAllowedToPurge()
+	}
+
+	if _, _, r, err = loadRecordCombo(ctx, svc.store, ns.ID, m.ID, recordID); err != nil {
+		return nil, err
+	}
+
+	if r.DeletedAt == nil {
+		return r, RecordErrNotDeleted()
+	}
+
+	return r, svc.purgeRecord(ctx, m, r)
+}
+
+// purgeRecord removes record with all its values, revisions and labels
+//
+// Attachments of the record that are not used by any other
+// record are removed together with their files.
+func (svc record) purgeRecord(ctx context.Context, m *types.Module, r *types.Record) error {
+	var aa types.AttachmentSet
+
+	err := store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+		var rr types.RecordRevisionSet
+
+		if err = store.DeleteComposeRecord(ctx, s, m, r); err != nil {
+			return
+		}
+
+		if rr, err = loadRecordRevisions(ctx, s, r.ID); err != nil {
+			return
+		}
+
+		if err = store.DeleteComposeRecordRevision(ctx, s, rr...); err != nil {
+			return
+		}
+
+		if aa, err = svc.purgeAttachments(ctx, s, m, r); err != nil {
+			return
+		}
+
+		return store.DeleteExtraLabels(ctx, s, r.LabelResourceKind(), r.ID)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, att := range aa {
+		for _, url := range []string{att.Url, att.PreviewUrl} {
+			if url == "" || svc.objects == nil {
+				continue
+			}
+
+			if err = svc.objects.Remove(url); err != nil {
+				DefaultLogger.Warn("could not remove attachment file",
+					zap.Uint64("attachmentID", att.ID),
+					zap.String("url", url),
+					zap.Error(err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// purgeAttachments removes attachments referenced from file fields of the purged record
+//
+// Attachments that are still referenced by other records of the module
+// (including the ones in the trash) are kept.
+func (svc record) purgeAttachments(ctx context.Context, s store.Storer, m *types.Module, r *types.Record) (aa types.AttachmentSet, err error) {
+	for _, v := range r.Values {
+		f := m.Fields.FindByName(v.Name)
+		if f == nil || f.Kind != "File" || v.Ref == 0 {
+			continue
+		}
+
+		ref := v.Ref
+		used, _, err := store.SearchComposeAttachments(ctx, s, types.AttachmentFilter{
+			Kind:      types.RecordAttachment,
+			ModuleID:  m.ID,
+			FieldName: f.Name,
+			Check: func(att *types.Attachment) (bool, error) {
+				return att.ID == ref, nil
+			},
+		})
+
+		if err != nil {
+			return nil, err
+		} else if len(used) > 0 {
+			continue
+		}
+
+		att, err := store.LookupComposeAttachmentByID(ctx, s, ref)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if err = store.DeleteComposeAttachment(ctx, s, att); err != nil {
+			return nil, err
+		}
+
+		aa = append(aa, att)
+	}
+
+	return aa, nil
+}
+
+const (
+	// trash holds deleted records only
+	trashFilterState = filter.StateExclusive
+
+	// how often expired records are purged from the trash
+	trashPurgeInterval = time.Hour
+
+	// cluster lease that guards the trash purge
+	trashPurgeLease = "compose.record.trash-purge"
+)
@@ -15,9 +15,11 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/healthcheck"
 	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/objstore"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/cas"
 	"github.com/cortezaproject/corteza-server/pkg/objstore/minio"
 	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -30,6 +32,7 @@ type (
 	Config struct {
 		ActionLog options.ActionLogOpt
 		Storage   options.ObjectStoreOpt
+		Limit     options.LimitOpt
 	}
 
 	eventDispatcher interface {
@@ -76,7 +79,7 @@ var (
 )
 
 // Initialize compose-only services
-func Initialize(_ context.Context, log *zap.Logger, s store.Storer, c Config) (err error) {
+func Initialize(_ context.Context, log *zap.Logger, s store.Storer, ws websocketPublisher, c Config) (err error) {
 	var (
 		hcd = healthcheck.Defaults()
 	)
@@ -108,6 +111,10 @@ func Initialize(_ context.Context, log *zap.Logger, s store.Storer, c Config) (e
 			bucket string
 		)
 		const svcPath = "compose"
+
+		// prefix for content addressed blobs
+		blobPrefix := ""
+
 		if opt.MinioEndpoint != "" {
 			bucket = minio.GetBucket(opt.MinioBucket, svcPath)
 
@@ -127,6 +134,7 @@ func Initialize(_ context.Context, log *zap.Logger, s store.Storer, c Config) (e
 				zap.Error(err))
 		} else {
 			path := opt.Path + "/" + svcPath
+			blobPrefix = path
 			DefaultObjectStore, err = plain.New(path)
 			log.Info("initializing store",
 				zap.String("path", path),
@@ -134,6 +142,12 @@ func Initialize(_ context.Context, log *zap.Logger, s store.Storer, c Config) (e
 
 		}
 
+		if err == nil && opt.Dedup {
+			DefaultObjectStore, err = cas.New(DefaultObjectStore, DefaultStore, svcPath, blobPrefix)
+			log.Info("initializing content addressed storage",
+				zap.Error(err))
+		}
+
 		hcd.Add(objstore.Healthcheck(DefaultObjectStore), "ObjectStore/Compose")
 
 		if err != nil {
@@ -141,11 +155,15 @@ func Initialize(_ context.Context, log *zap.Logger, s store.Storer, c Config) (e
 		}
 	}
 
-	DefaultNamespace = Namespace()
-	DefaultModule = Module()
+	DefaultNamespace = Namespace(NamespaceOptions{LimitNamespaces: c.Limit.ComposeNamespaces})
+	DefaultModule = Module(ModuleOptions{LimitModules: c.Limit.ComposeModules})
 
 	DefaultImportSession = ImportSession()
-	DefaultRecord = Record()
+	DefaultRecord = Record(RecordOptions{LimitRecords: c.Limit.ComposeRecords}, ws)
+	if ws != nil {
+		// changes made on any of the nodes are delivered to subscribers on this node
+		ws.HandleBroadcast(recordChangeMessageType, DefaultRecord.(*record).deliverChange)
+	}
 	DefaultPage = Page()
 	DefaultChart = Chart()
 	DefaultNotification = Notification()
@@ -188,6 +206,11 @@ func Initialize(_ context.Context, log *zap.Logger, s store.Storer, c Config) (e
 	// @todo additional datasource providers; generate?
 	systemService.DefaultReport.RegisterReporter("composeRecords", DefaultRecord)
 
+	// Webhook payloads are checked against the webhook owner's permissions
+	systemService.DefaultWebhook.RegisterArgsGuard("compose:namespace", webhookNamespaceGuard(DefaultAccessControl))
+	systemService.DefaultWebhook.RegisterArgsGuard("compose:module", webhookModuleGuard(DefaultAccessControl))
+	systemService.DefaultWebhook.RegisterArgsGuard("compose:record", webhookRecordGuard(DefaultAccessControl))
+
 	return nil
 }
 
@@ -196,7 +219,26 @@ func Activate(ctx context.Context) (err error) {
 }
 
 func Watchers(ctx context.Context) {
-	//
+	DefaultRecord.WatchTrash(ctx)
+
+	rollups := DefaultRecord.(*record).rollups
+
+	// rollup fields are cached; drop them when modules are changed
+	eventbus.Service().Register(
+		func(ctx context.Context, ev eventbus.Event) error {
+			rollups.reset()
+			return nil
+		},
+		eventbus.For("compose:module"),
+		eventbus.On("afterCreate", "afterUpdate", "afterDelete"),
+	)
+
+	// module translations are cached with the rest of resource translations;
+	// reload them when modules are changed on other nodes
+	invalidation.Register(ModuleInvalidationKind, func(ctx context.Context) error {
+		rollups.reset()
+		return locale.Global().ReloadResourceTranslations(ctx)
+	})
 }
 
 func RegisterIteratorProviders() {
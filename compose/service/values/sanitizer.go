@@ -74,8 +74,8 @@ func (s sanitizer) Run(m *types.Module, vv types.RecordValueSet) (out types.Reco
 			continue
 		}
 
-		if f.Expressions.ValueExpr != "" {
-			// do not do any sanitization if field has value expression!
+		if f.Expressions.ValueExpr != "" || f.IsRollup() {
+			// do not do any sanitization if field has value expression or is a rollup!
 			continue
 		}
 
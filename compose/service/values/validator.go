@@ -146,8 +146,8 @@ fields:
 			continue
 		}
 
-		if f.Expressions.ValueExpr != "" {
-			// do not do any validation if field has value expression!
+		if f.Expressions.ValueExpr != "" || f.IsRollup() {
+			// do not do any validation if field has value expression or is a rollup!
 			continue
 		}
 
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	systemService "github.com/cortezaproject/corteza-server/system/service"
+)
+
+type (
+	webhookAccessController interface {
+		CanReadNamespace(context.Context, *types.Namespace) bool
+		CanReadModule(context.Context, *types.Module) bool
+		CanReadRecord(context.Context, *types.Record) bool
+
+		recordValueAccessController
+	}
+)
+
+// webhookNamespaceGuard lets through only events of namespaces that webhook owner can read
+func webhookNamespaceGuard(ac webhookAccessController) systemService.WebhookArgsGuard {
+	return func(ctx context.Context, ev eventbus.Event, _ map[string][]byte) (bool, error) {
+		if e, is := ev.(interface{ Namespace() *types.Namespace }); is && e.Namespace() != nil {
+			return ac.CanReadNamespace(ctx, e.Namespace()), nil
+		}
+
+		return false, nil
+	}
+}
+
+// webhookModuleGuard lets through only events of modules that webhook owner can read
+func webhookModuleGuard(ac webhookAccessController) systemService.WebhookArgsGuard {
+	return func(ctx context.Context, ev eventbus.Event, _ map[string][]byte) (bool, error) {
+		if e, is := ev.(interface{ Module() *types.Module }); is && e.Module() != nil {
+			return ac.CanReadModule(ctx, e.Module()), nil
+		}
+
+		return false, nil
+	}
+}
+
+// webhookRecordGuard lets through only events of records that webhook owner can read
+//
+// Values of the record (and the old record) are trimmed
+// to fields webhook owner can read.
+func webhookRecordGuard(ac webhookAccessController) systemService.WebhookArgsGuard {
+	return func(ctx context.Context, ev eventbus.Event, args map[string][]byte) (bool, error) {
+		e, is := ev.(interface {
+			Record() *types.Record
+			OldRecord() *types.Record
+			Module() *types.Module
+		})
+
+		if !is || e.Module() == nil || !ac.CanReadModule(ctx, e.Module()) {
+			return false, nil
+		}
+
+		var (
+			m  = e.Module()
+			rr = map[string]*types.Record{
+				"record":    e.Record(),
+				"oldRecord": e.OldRecord(),
+			}
+		)
+
+		for arg, r := range rr {
+			if r == nil {
+				continue
+			}
+
+			// record from the event is shared with other handlers
+			r = r.Clone()
+			r.SetModule(m)
+
+			if !ac.CanReadRecord(ctx, r) {
+				return false, nil
+			}
+
+			ComposeRecordFilterAC(ctx, ac, m, r)
+
+			enc, err := json.Marshal(r)
+			if err != nil {
+				return false, err
+			}
+
+			args[arg] = enc
+		}
+
+		return true, nil
+	}
+}
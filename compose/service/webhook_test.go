@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service/event"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	webhookTestAccessController struct {
+		readRecord bool
+		fields     map[string]bool
+	}
+)
+
+func (ac webhookTestAccessController) CanReadNamespace(context.Context, *types.Namespace) bool {
+	return true
+}
+
+func (ac webhookTestAccessController) CanReadModule(context.Context, *types.Module) bool {
+	return true
+}
+
+func (ac webhookTestAccessController) CanReadRecord(context.Context, *types.Record) bool {
+	return ac.readRecord
+}
+
+func (ac webhookTestAccessController) CanReadRecordValue(_ context.Context, f *types.ModuleField) bool {
+	return ac.fields[f.Name]
+}
+
+func (ac webhookTestAccessController) CanUpdateRecordValue(context.Context, *types.ModuleField) bool {
+	return false
+}
+
+func TestWebhookRecordGuard(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		m = &types.Module{
+			ID: 1,
+			Fields: types.ModuleFieldSet{
+				&types.ModuleField{Name: "public"},
+				&types.ModuleField{Name: "secret"},
+			},
+		}
+
+		r = &types.Record{
+			ID:       2,
+			ModuleID: m.ID,
+			Values: types.RecordValueSet{
+				{Name: "public", Value: "p"},
+				{Name: "secret", Value: "s"},
+			},
+		}
+
+		ev = event.RecordAfterUpdate(r, r, m, nil, nil, nil)
+	)
+
+	t.Run("record not readable", func(t *testing.T) {
+		req := require.New(t)
+		ok, err := webhookRecordGuard(webhookTestAccessController{})(ctx, ev, map[string][]byte{})
+		req.NoError(err)
+		req.False(ok)
+	})
+
+	t.Run("values trimmed", func(t *testing.T) {
+		var (
+			req  = require.New(t)
+			args = map[string][]byte{}
+			ac   = webhookTestAccessController{readRecord: true, fields: map[string]bool{"public": true}}
+			out  = &types.Record{}
+		)
+
+		ok, err := webhookRecordGuard(ac)(ctx, ev, args)
+		req.NoError(err)
+		req.True(ok)
+
+		for _, arg := range []string{"record", "oldRecord"} {
+			req.NoError(json.Unmarshal(args[arg], out))
+			req.Len(out.Values, 1)
+			req.Equal("public", out.Values[0].Name)
+		}
+
+		// record in the event must stay intact
+		req.Len(r.Values, 2)
+	})
+}
@@ -1,11 +1,16 @@
 package types
 
 import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/locale"
 	"github.com/jmoiron/sqlx/types"
+	"github.com/pkg/errors"
 )
 
 type (
@@ -13,6 +18,7 @@ type (
 		ID     uint64         `json:"moduleID,string"`
 		Handle string         `json:"handle"`
 		Meta   types.JSONText `json:"meta"`
+		Config ModuleConfig   `json:"config"`
 		Fields ModuleFieldSet `json:"fields"`
 
 		Labels map[string]string `json:"labels,omitempty"`
@@ -29,6 +35,28 @@ type (
 		Name string `json:"name"`
 	}
 
+	ModuleConfig struct {
+		RecordRevisions ModuleConfigRecordRevisions `json:"recordRevisions"`
+
+		// Unique constraints over one or more module fields
+		UniqueConstraints []*ModuleConfigUniqueConstraint `json:"uniqueConstraints,omitempty"`
+	}
+
+	ModuleConfigRecordRevisions struct {
+		// Enables storing of record revisions (changed values)
+		// on create, update and delete
+		Enabled bool `json:"enabled"`
+	}
+
+	// ModuleConfigUniqueConstraint ensures there are no two (non-deleted) records
+	// with the same combination of values in the listed fields
+	//
+	// Records with an empty value in any of the fields are not checked
+	ModuleConfigUniqueConstraint struct {
+		Name   string   `json:"name"`
+		Fields []string `json:"fields"`
+	}
+
 	ModuleFilter struct {
 		ModuleID    []uint64 `json:"moduleID"`
 		NamespaceID uint64   `json:"namespaceID,string"`
@@ -79,3 +107,43 @@ func (set ModuleSet) FindByHandle(handle string) *Module {
 
 	return nil
 }
+
+// Key returns a hash of the record values covered by the constraint
+//
+// Returns false when record does not have values for all constraint fields
+func (c ModuleConfigUniqueConstraint) Key(vv RecordValueSet) (string, bool) {
+	var (
+		key = make([]string, len(c.Fields))
+	)
+
+	for i, name := range c.Fields {
+		v := vv.Get(name, 0)
+		if v == nil || v.IsDeleted() || v.Value == "" {
+			return "", false
+		}
+
+		key[i] = v.Value
+	}
+
+	enc, _ := json.Marshal(key)
+	return fmt.Sprintf("%x", sha256.Sum256(enc)), true
+}
+
+func (mc *ModuleConfig) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*mc = ModuleConfig{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, mc); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into ModuleConfig", string(b))
+		}
+	}
+
+	return nil
+}
+
+func (mc ModuleConfig) Value() (driver.Value, error) {
+	return json.Marshal(mc)
+}
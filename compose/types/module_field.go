@@ -430,6 +430,11 @@ func (f ModuleField) IsDateTime() bool {
 	return f.Kind == "DateTime"
 }
 
+// IsRollup tells us if value of this field is aggregated over related records
+func (f ModuleField) IsRollup() bool {
+	return f.Expressions.Rollup != nil
+}
+
 // IsRef tells us if value of this field be a reference to something
 // (another record, file , user)?
 func (f ModuleField) IsRef() bool {
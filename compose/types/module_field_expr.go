@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 type (
@@ -17,6 +18,8 @@ type (
 
 		Formatters               []string `json:"formatters,omitempty"`
 		DisableDefaultFormatters bool     `json:"disableDefaultFormatters,omitempty"`
+
+		Rollup *ModuleFieldRollup `json:"rollup,omitempty"`
 	}
 
 	ModuleFieldValidator struct {
@@ -24,6 +27,50 @@ type (
 		Test        string `json:"test,omitempty"`
 		Error       string `json:"error,omitempty"`
 	}
+
+	// ModuleFieldRollup configures field with a value that is
+	// aggregated over related records (e.g. sum of invoice lines)
+	//
+	// Value of the field is maintained by the record service when
+	// related records are created, updated or deleted
+	ModuleFieldRollup struct {
+		// Module with the related records
+		ModuleID uint64 `json:"moduleID,string"`
+
+		// Record field on the related module that points to the record
+		RelationField string `json:"relationField"`
+
+		// Field on the related module with values to aggregate, not used for count
+		ValueField string `json:"valueField,omitempty"`
+
+		// One of count, sum, min, max, avg
+		Aggregate string `json:"aggregate"`
+
+		// Additional filter for the related records
+		Filter string `json:"filter,omitempty"`
+	}
+
+	// RollupAccumulator aggregates values of related records
+	// so they do not need to be loaded all at once
+	RollupAccumulator struct {
+		rollup ModuleFieldRollup
+
+		// number of records, values and their sum
+		records int
+		cnt     int
+		sum     float64
+
+		// first, min or max value
+		out float64
+	}
+)
+
+const (
+	RollupCount = "count"
+	RollupSum   = "sum"
+	RollupMin   = "min"
+	RollupMax   = "max"
+	RollupAvg   = "avg"
 )
 
 func (opt *ModuleFieldExpr) Scan(value interface{}) error {
@@ -44,3 +91,89 @@ func (opt *ModuleFieldExpr) Scan(value interface{}) error {
 func (opt ModuleFieldExpr) Value() (driver.Value, error) {
 	return json.Marshal(opt)
 }
+
+// IsValidAggregate checks if aggregate function is supported
+func (r ModuleFieldRollup) IsValidAggregate() bool {
+	switch r.Aggregate {
+	case RollupCount, RollupSum, RollupMin, RollupMax, RollupAvg:
+		return true
+	}
+
+	return false
+}
+
+// Calculate aggregates values of the related records
+//
+// Non-numeric values are ignored. Returns false when
+// there is no value (min, max and avg of an empty set)
+func (r ModuleFieldRollup) Calculate(rr RecordSet) (string, bool) {
+	a := r.Accumulator()
+	a.Add(rr)
+	return a.Result()
+}
+
+// Accumulator returns accumulator that aggregates
+// related records added in batches
+func (r ModuleFieldRollup) Accumulator() *RollupAccumulator {
+	return &RollupAccumulator{rollup: r}
+}
+
+// Add aggregates values of the given records
+func (a *RollupAccumulator) Add(rr RecordSet) {
+	a.records += len(rr)
+
+	if a.rollup.Aggregate == RollupCount {
+		return
+	}
+
+	for _, rec := range rr {
+		for _, v := range rec.Values.FilterByName(a.rollup.ValueField) {
+			if v.IsDeleted() {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				continue
+			}
+
+			switch {
+			case a.cnt == 0:
+				a.out = f
+			case a.rollup.Aggregate == RollupMin && f < a.out:
+				a.out = f
+			case a.rollup.Aggregate == RollupMax && f > a.out:
+				a.out = f
+			}
+
+			a.sum += f
+			a.cnt++
+		}
+	}
+}
+
+// Result returns aggregated value of all added records
+//
+// Returns false when there is no value (min, max and avg of an empty set)
+func (a *RollupAccumulator) Result() (string, bool) {
+	var (
+		out = a.out
+	)
+
+	switch a.rollup.Aggregate {
+	case RollupCount:
+		return strconv.Itoa(a.records), true
+	case RollupSum:
+		out = a.sum
+	case RollupAvg:
+		if a.cnt > 0 {
+			out = a.sum / float64(a.cnt)
+		}
+	}
+
+	if a.cnt == 0 && a.rollup.Aggregate != RollupSum {
+		return "", false
+	}
+
+	return strconv.FormatFloat(out, 'f', -1, 64), true
+}
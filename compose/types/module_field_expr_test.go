@@ -0,0 +1,80 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleFieldRollup_Calculate(t *testing.T) {
+	var (
+		rr = RecordSet{
+			{Values: RecordValueSet{{Name: "amount", Value: "10"}}},
+			{Values: RecordValueSet{{Name: "amount", Value: "2.5"}}},
+			{Values: RecordValueSet{{Name: "amount", Value: "abc"}}},
+			{Values: RecordValueSet{}},
+		}
+	)
+
+	tests := []struct {
+		aggregate string
+		set       RecordSet
+		value     string
+		ok        bool
+	}{
+		{RollupCount, rr, "4", true},
+		{RollupSum, rr, "12.5", true},
+		{RollupMin, rr, "2.5", true},
+		{RollupMax, rr, "10", true},
+		{RollupAvg, rr, "6.25", true},
+		{RollupCount, nil, "0", true},
+		{RollupSum, nil, "0", true},
+		{RollupMin, nil, "", false},
+		{RollupAvg, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.aggregate, func(t *testing.T) {
+			value, ok := ModuleFieldRollup{Aggregate: tt.aggregate, ValueField: "amount"}.Calculate(tt.set)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.value, value)
+		})
+	}
+}
+
+func TestModuleFieldRollup_Accumulator(t *testing.T) {
+	var (
+		first = RecordSet{
+			{Values: RecordValueSet{{Name: "amount", Value: "10"}}},
+			{Values: RecordValueSet{{Name: "amount", Value: "4"}}},
+		}
+
+		second = RecordSet{
+			{Values: RecordValueSet{{Name: "amount", Value: "1"}}},
+			{Values: RecordValueSet{}},
+		}
+	)
+
+	tests := []struct {
+		aggregate string
+		value     string
+	}{
+		{RollupCount, "4"},
+		{RollupSum, "15"},
+		{RollupMin, "1"},
+		{RollupMax, "10"},
+		{RollupAvg, "5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.aggregate, func(t *testing.T) {
+			a := ModuleFieldRollup{Aggregate: tt.aggregate, ValueField: "amount"}.Accumulator()
+			a.Add(first)
+			a.Add(second)
+
+			value, ok := a.Result()
+			require.True(t, ok)
+			require.Equal(t, tt.value, value)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleConfigUniqueConstraint_Key(t *testing.T) {
+	var (
+		req = require.New(t)
+		c   = ModuleConfigUniqueConstraint{Name: "c", Fields: []string{"a", "b"}}
+	)
+
+	k1, ok := c.Key(RecordValueSet{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}})
+	req.True(ok)
+
+	k2, ok := c.Key(RecordValueSet{{Name: "b", Value: "2"}, {Name: "a", Value: "1"}, {Name: "x", Value: "3"}})
+	req.True(ok)
+	req.Equal(k1, k2)
+
+	// empty values are not checked
+	k3, ok := c.Key(RecordValueSet{{Name: "a", Value: "12"}, {Name: "b", Value: ""}})
+	req.False(ok)
+	req.Empty(k3)
+
+	// values must not be simply concatenated
+	k3, _ = c.Key(RecordValueSet{{Name: "a", Value: "1\",\"2"}, {Name: "b", Value: "2"}})
+	req.NotEqual(k1, k3)
+
+	_, ok = c.Key(RecordValueSet{{Name: "a", Value: "1"}})
+	req.False(ok)
+}
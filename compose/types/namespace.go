@@ -69,6 +69,10 @@ type (
 		//          struct field is kept for the convenience for now since it allows us
 		//          easy encoding/decoding of the outgoing/incoming values
 		Description string `json:"description,omitempty"`
+
+		// Number of days deleted records are kept in the trash;
+		// expired records are periodically purged (0 keeps them forever)
+		RecordTrashRetentionDays uint `json:"recordTrashRetentionDays,omitempty"`
 	}
 )
 
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/pkg/errors"
+)
+
+type (
+	// RecordRevision holds one change made on a record
+	//
+	// Revisions are only stored for records of modules
+	// with enabled record revisions (see ModuleConfig)
+	RecordRevision struct {
+		ID          uint64 `json:"revisionID,string"`
+		RecordID    uint64 `json:"recordID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+
+		// Sequential number of the revision, starting with 1
+		Revision  uint                    `json:"revision"`
+		Operation RecordRevisionOperation `json:"operation"`
+		Changes   RecordRevisionChangeSet `json:"changes"`
+
+		UserID    uint64    `json:"userID,string"`
+		CreatedAt time.Time `json:"timestamp"`
+	}
+
+	RecordRevisionOperation string
+
+	// RecordRevisionChange holds all old and new values of a single field
+	//
+	// Values are kept as a list to cover multi-value fields
+	RecordRevisionChange struct {
+		Name string   `json:"name"`
+		Old  []string `json:"old"`
+		New  []string `json:"new"`
+	}
+
+	RecordRevisionChangeSet []*RecordRevisionChange
+
+	RecordRevisionFilter struct {
+		RecordID    uint64 `json:"recordID,string"`
+		ModuleID    uint64 `json:"moduleID,string"`
+		NamespaceID uint64 `json:"namespaceID,string"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*RecordRevision) (bool, error) `json:"-"`
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+const (
+	RecordRevisionOperationCreate   RecordRevisionOperation = "create"
+	RecordRevisionOperationUpdate   RecordRevisionOperation = "update"
+	RecordRevisionOperationDelete   RecordRevisionOperation = "delete"
+	RecordRevisionOperationRestore  RecordRevisionOperation = "restore"
+	RecordRevisionOperationUndelete RecordRevisionOperation = "undelete"
+)
+
+// RecordRevisionChanges compares old and new value set and returns changes for all modified fields
+//
+// Fields are compared in the order they are defined on a module;
+// deleted values (with DeletedAt set) are ignored.
+func RecordRevisionChanges(fields ModuleFieldSet, old, new RecordValueSet) (cc RecordRevisionChangeSet) {
+	var (
+		oldClean = old.GetClean()
+		newClean = new.GetClean()
+	)
+
+	cc = RecordRevisionChangeSet{}
+
+	for _, f := range fields {
+		var (
+			ov = revisionValues(oldClean.FilterByName(f.Name))
+			nv = revisionValues(newClean.FilterByName(f.Name))
+		)
+
+		if equalStrings(ov, nv) {
+			continue
+		}
+
+		cc = append(cc, &RecordRevisionChange{Name: f.Name, Old: ov, New: nv})
+	}
+
+	return
+}
+
+// FilterByName returns changes on the given fields only
+func (set RecordRevisionChangeSet) FilterByName(names ...string) (out RecordRevisionChangeSet) {
+	out = RecordRevisionChangeSet{}
+	for _, c := range set {
+		for _, n := range names {
+			if c.Name == n {
+				out = append(out, c)
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func (set *RecordRevisionChangeSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*set = RecordRevisionChangeSet{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, set); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into RecordRevisionChangeSet", string(b))
+		}
+	}
+
+	return nil
+}
+
+func (set RecordRevisionChangeSet) Value() (driver.Value, error) {
+	return json.Marshal(set)
+}
+
+// RevertTo reverts values to the state right after the given revision was made
+//
+// Changes from all revisions that are newer are undone (in reverse order)
+// and the resulting value set is returned. Values in the given set are expected to be
+// current record values.
+func (set RecordRevisionSet) RevertTo(vv RecordValueSet, revision uint) (out RecordValueSet) {
+	out = vv.GetClean()
+	sort.Sort(set)
+
+	// walk from the newest to the oldest revision
+	for i := len(set) - 1; i >= 0; i-- {
+		if set[i].Revision <= revision {
+			continue
+		}
+
+		for _, c := range set[i].Changes {
+			out = out.Replace(c.Name, c.Old...)
+		}
+	}
+
+	return
+}
+
+func (set RecordRevisionSet) Len() int           { return len(set) }
+func (set RecordRevisionSet) Swap(i, j int)      { set[i], set[j] = set[j], set[i] }
+func (set RecordRevisionSet) Less(i, j int) bool { return set[i].Revision < set[j].Revision }
+
+// revisionValues returns values ordered by their place
+func revisionValues(vv RecordValueSet) (out []string) {
+	out = make([]string, 0, len(vv))
+	sort.Sort(vv)
+	for _, v := range vv {
+		out = append(out, v.Value)
+	}
+
+	return
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
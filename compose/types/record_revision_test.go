@@ -0,0 +1,69 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordRevisionChanges(t *testing.T) {
+	var (
+		req = require.New(t)
+		mfs = ModuleFieldSet{
+			&ModuleField{Name: "amount"},
+			&ModuleField{Name: "tags", Multi: true},
+			&ModuleField{Name: "note"},
+		}
+
+		old = RecordValueSet{
+			{Name: "amount", Value: "10"},
+			{Name: "tags", Value: "b", Place: 1},
+			{Name: "tags", Value: "a", Place: 0},
+			{Name: "note", Value: "same"},
+		}
+
+		new = RecordValueSet{
+			{Name: "amount", Value: "20"},
+			{Name: "tags", Value: "a", Place: 0},
+			{Name: "note", Value: "same"},
+		}
+	)
+
+	cc := RecordRevisionChanges(mfs, old, new)
+	req.Len(cc, 2)
+	req.Equal(&RecordRevisionChange{Name: "amount", Old: []string{"10"}, New: []string{"20"}}, cc[0])
+	req.Equal(&RecordRevisionChange{Name: "tags", Old: []string{"a", "b"}, New: []string{"a"}}, cc[1])
+
+	req.Len(RecordRevisionChanges(mfs, old, old), 0)
+}
+
+func TestRecordRevisionSet_RevertTo(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		rr = RecordRevisionSet{
+			{Revision: 3, Changes: RecordRevisionChangeSet{{Name: "amount", Old: []string{"20"}, New: []string{"30"}}}},
+			{Revision: 1, Changes: RecordRevisionChangeSet{{Name: "amount", Old: []string{}, New: []string{"10"}}}},
+			{Revision: 2, Changes: RecordRevisionChangeSet{
+				{Name: "amount", Old: []string{"10"}, New: []string{"20"}},
+				{Name: "note", Old: []string{}, New: []string{"added"}},
+			}},
+		}
+
+		current = RecordValueSet{
+			{Name: "amount", Value: "30"},
+			{Name: "note", Value: "added"},
+		}
+	)
+
+	vv := rr.RevertTo(current, 3)
+	req.Equal("30", vv.Get("amount", 0).Value)
+
+	vv = rr.RevertTo(current, 2)
+	req.Equal("20", vv.Get("amount", 0).Value)
+	req.Equal("added", vv.Get("note", 0).Value)
+
+	vv = rr.RevertTo(current, 1)
+	req.Equal("10", vv.Get("amount", 0).Value)
+	req.False(vv.Has("note", 0))
+}
@@ -45,6 +45,11 @@ type (
 	// This type is auto-generated.
 	RecordSet []*Record
 
+	// RecordRevisionSet slice of RecordRevision
+	//
+	// This type is auto-generated.
+	RecordRevisionSet []*RecordRevision
+
 	// RecordValueSet slice of RecordValue
 	//
 	// This type is auto-generated.
@@ -443,6 +448,62 @@ func (set RecordSet) IDs() (IDs []uint64) {
 	return
 }
 
+// Walk iterates through every slice item and calls w(RecordRevision) err
+//
+// This function is auto-generated.
+func (set RecordRevisionSet) Walk(w func(*RecordRevision) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(RecordRevision) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set RecordRevisionSet) Filter(f func(*RecordRevision) (bool, error)) (out RecordRevisionSet, err error) {
+	var ok bool
+	out = RecordRevisionSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set RecordRevisionSet) FindByID(ID uint64) *RecordRevision {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set RecordRevisionSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(RecordValue) err
 //
 // This function is auto-generated.
@@ -644,6 +644,96 @@ func TestRecordSetIDs(t *testing.T) {
 	}
 }
 
+func TestRecordRevisionSetWalk(t *testing.T) {
+	var (
+		value = make(RecordRevisionSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*RecordRevision) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*RecordRevision) error { return fmt.Errorf("walk error") }))
+}
+
+func TestRecordRevisionSetFilter(t *testing.T) {
+	var (
+		value = make(RecordRevisionSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*RecordRevision) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*RecordRevision) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*RecordRevision) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestRecordRevisionSetIDs(t *testing.T) {
+	var (
+		value = make(RecordRevisionSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(RecordRevision)
+	value[1] = new(RecordRevision)
+	value[2] = new(RecordRevision)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
 func TestRecordValueSetWalk(t *testing.T) {
 	var (
 		value = make(RecordValueSet, 3)
@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/federation/rest/request"
+	"github.com/cortezaproject/corteza-server/federation/service"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	conflictServicer interface {
+		Find(ctx context.Context, filter types.ConflictFilter) (types.ConflictSet, types.ConflictFilter, error)
+		FindByID(ctx context.Context, conflictID uint64) (*types.Conflict, error)
+		Resolve(ctx context.Context, conflictID uint64, resolution string) (*types.Conflict, error)
+	}
+
+	Conflict struct {
+		svcConflict conflictServicer
+	}
+
+	conflictSetPayload struct {
+		Filter types.ConflictFilter `json:"filter"`
+		Set    types.ConflictSet    `json:"set"`
+	}
+)
+
+func (Conflict) New() *Conflict {
+	return &Conflict{
+		svcConflict: service.DefaultConflict,
+	}
+}
+
+func (ctrl Conflict) Search(ctx context.Context, r *request.ConflictSearch) (interface{}, error) {
+	var (
+		err error
+		f   = types.ConflictFilter{
+			NodeID:          r.NodeID,
+			ModuleID:        r.ModuleID,
+			ComposeRecordID: r.RecordID,
+			Status:          r.Status,
+		}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, f, err := ctrl.svcConflict.Find(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conflictSetPayload{Filter: f, Set: set}, nil
+}
+
+func (ctrl Conflict) Read(ctx context.Context, r *request.ConflictRead) (interface{}, error) {
+	return ctrl.svcConflict.FindByID(ctx, r.ConflictID)
+}
+
+func (ctrl Conflict) Resolve(ctx context.Context, r *request.ConflictResolve) (interface{}, error) {
+	return ctrl.svcConflict.Resolve(ctx, r.ConflictID, r.Resolution)
+}
@@ -22,13 +22,21 @@ type (
 		ReadExposedAll(context.Context, *request.SyncDataReadExposedAll) (interface{}, error)
 		ReadExposedInternal(context.Context, *request.SyncDataReadExposedInternal) (interface{}, error)
 		ReadExposedSocial(context.Context, *request.SyncDataReadExposedSocial) (interface{}, error)
+		ReadExposedRecord(context.Context, *request.SyncDataReadExposedRecord) (interface{}, error)
+		ReadExposedAttachment(context.Context, *request.SyncDataReadExposedAttachment) (interface{}, error)
+		ReceivePushed(context.Context, *request.SyncDataReceivePushed) (interface{}, error)
+		ReceiveChanges(context.Context, *request.SyncDataReceiveChanges) (interface{}, error)
 	}
 
 	// HTTP API interface
 	SyncData struct {
-		ReadExposedAll      func(http.ResponseWriter, *http.Request)
-		ReadExposedInternal func(http.ResponseWriter, *http.Request)
-		ReadExposedSocial   func(http.ResponseWriter, *http.Request)
+		ReadExposedAll        func(http.ResponseWriter, *http.Request)
+		ReadExposedInternal   func(http.ResponseWriter, *http.Request)
+		ReadExposedSocial     func(http.ResponseWriter, *http.Request)
+		ReadExposedRecord     func(http.ResponseWriter, *http.Request)
+		ReadExposedAttachment func(http.ResponseWriter, *http.Request)
+		ReceivePushed         func(http.ResponseWriter, *http.Request)
+		ReceiveChanges        func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -80,6 +88,70 @@ func NewSyncData(h SyncDataAPI) *SyncData {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		ReadExposedRecord: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewSyncDataReadExposedRecord()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.ReadExposedRecord(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		ReadExposedAttachment: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewSyncDataReadExposedAttachment()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.ReadExposedAttachment(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		ReceivePushed: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewSyncDataReceivePushed()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.ReceivePushed(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			api.Send(w, r, value)
+		},
+		ReceiveChanges: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewSyncDataReceiveChanges()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.ReceiveChanges(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -91,5 +163,9 @@ func (h SyncData) MountRoutes(r chi.Router, middlewares ...func(http.Handler) ht
 		r.Get("/nodes/{nodeID}/modules/exposed/records/", h.ReadExposedAll)
 		r.Get("/nodes/{nodeID}/modules/{moduleID}/records/", h.ReadExposedInternal)
 		r.Get("/nodes/{nodeID}/modules/{moduleID}/records/activity-stream/", h.ReadExposedSocial)
+		r.Get("/nodes/{nodeID}/modules/{moduleID}/records/{recordID}", h.ReadExposedRecord)
+		r.Get("/nodes/{nodeID}/modules/{moduleID}/records/{recordID}/attachments/{attachmentID}", h.ReadExposedAttachment)
+		r.Post("/nodes/{nodeID}/modules/{moduleID}/records/push", h.ReceivePushed)
+		r.Post("/nodes/{nodeID}/modules/{moduleID}/records/changes", h.ReceiveChanges)
 	})
 }
@@ -41,6 +41,8 @@ func (ctrl ManageStructure) CreateExposed(ctx context.Context, r *request.Manage
 			Name:               r.Name,
 			Handle:             r.Handle,
 			Fields:             r.Fields,
+			Bidirectional:      r.Bidirectional,
+			ConflictPolicy:     r.ConflictPolicy,
 		}
 	)
 
@@ -63,6 +65,8 @@ func (ctrl ManageStructure) UpdateExposed(ctx context.Context, r *request.Manage
 			Name:               r.Name,
 			Handle:             r.Handle,
 			Fields:             r.Fields,
+			Bidirectional:      r.Bidirectional,
+			ConflictPolicy:     r.ConflictPolicy,
 		}
 	)
 
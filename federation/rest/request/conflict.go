@@ -0,0 +1,316 @@
+package request
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	"github.com/go-chi/chi"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// dummy vars to prevent
+// unused imports complain
+var (
+	_ = chi.URLParam
+	_ = multipart.ErrMessageTooLarge
+	_ = payload.ParseUint64s
+	_ = strings.ToLower
+	_ = io.EOF
+	_ = fmt.Errorf
+	_ = json.NewEncoder
+)
+
+type (
+	// Internal API interface
+	ConflictSearch struct {
+		// NodeID GET parameter
+		//
+		// Filter by node ID
+		NodeID uint64 `json:",string"`
+
+		// ModuleID GET parameter
+		//
+		// Filter by exposed module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID GET parameter
+		//
+		// Filter by compose record ID
+		RecordID uint64 `json:",string"`
+
+		// Status GET parameter
+		//
+		// Filter by status (pending, resolved)
+		Status string
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	ConflictRead struct {
+		// ConflictID PATH parameter
+		//
+		// Conflict ID
+		ConflictID uint64 `json:",string"`
+	}
+
+	ConflictResolve struct {
+		// ConflictID PATH parameter
+		//
+		// Conflict ID
+		ConflictID uint64 `json:",string"`
+
+		// Resolution POST parameter
+		//
+		// Keep local (origin) or remote (node) version of the record
+		Resolution string
+	}
+)
+
+// NewConflictSearch request
+func NewConflictSearch() *ConflictSearch {
+	return &ConflictSearch{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"nodeID":     r.NodeID,
+		"moduleID":   r.ModuleID,
+		"recordID":   r.RecordID,
+		"status":     r.Status,
+		"limit":      r.Limit,
+		"pageCursor": r.PageCursor,
+		"sort":       r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetNodeID() uint64 {
+	return r.NodeID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetStatus() string {
+	return r.Status
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictSearch) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *ConflictSearch) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["nodeID"]; ok && len(val) > 0 {
+			r.NodeID, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["moduleID"]; ok && len(val) > 0 {
+			r.ModuleID, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["recordID"]; ok && len(val) > 0 {
+			r.RecordID, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["status"]; ok && len(val) > 0 {
+			r.Status, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// NewConflictRead request
+func NewConflictRead() *ConflictRead {
+	return &ConflictRead{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictRead) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"conflictID": r.ConflictID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictRead) GetConflictID() uint64 {
+	return r.ConflictID
+}
+
+// Fill processes request and fills internal variables
+func (r *ConflictRead) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "conflictID")
+		r.ConflictID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewConflictResolve request
+func NewConflictResolve() *ConflictResolve {
+	return &ConflictResolve{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictResolve) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"conflictID": r.ConflictID,
+		"resolution": r.Resolution,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictResolve) GetConflictID() uint64 {
+	return r.ConflictID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ConflictResolve) GetResolution() string {
+	return r.Resolution
+}
+
+// Fill processes request and fills internal variables
+func (r *ConflictResolve) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+			if val, ok := req.MultipartForm.Value["resolution"]; ok && len(val) > 0 {
+				r.Resolution, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if val, ok := req.Form["resolution"]; ok && len(val) > 0 {
+			r.Resolution, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "conflictID")
+		r.ConflictID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
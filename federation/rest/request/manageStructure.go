@@ -76,6 +76,16 @@ type (
 		//
 		// Exposed module fields
 		Fields types.ModuleFieldSet
+
+		// Bidirectional POST parameter
+		//
+		// Accept record changes from the paired node
+		Bidirectional bool
+
+		// ConflictPolicy POST parameter
+		//
+		// Conflict resolution policy (lastWriterWins, originWins, manual)
+		ConflictPolicy string
 	}
 
 	ManageStructureUpdateExposed struct {
@@ -113,6 +123,16 @@ type (
 		//
 		// Exposed module fields
 		Fields types.ModuleFieldSet
+
+		// Bidirectional POST parameter
+		//
+		// Accept record changes from the paired node
+		Bidirectional bool
+
+		// ConflictPolicy POST parameter
+		//
+		// Conflict resolution policy (lastWriterWins, originWins, manual)
+		ConflictPolicy string
 	}
 
 	ManageStructureRemoveExposed struct {
@@ -267,6 +287,8 @@ func (r ManageStructureCreateExposed) Auditable() map[string]interface{} {
 		"name":               r.Name,
 		"handle":             r.Handle,
 		"fields":             r.Fields,
+		"bidirectional":      r.Bidirectional,
+		"conflictPolicy":     r.ConflictPolicy,
 	}
 }
 
@@ -300,6 +322,16 @@ func (r ManageStructureCreateExposed) GetFields() types.ModuleFieldSet {
 	return r.Fields
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r ManageStructureCreateExposed) GetBidirectional() bool {
+	return r.Bidirectional
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ManageStructureCreateExposed) GetConflictPolicy() string {
+	return r.ConflictPolicy
+}
+
 // Fill processes request and fills internal variables
 func (r *ManageStructureCreateExposed) Fill(req *http.Request) (err error) {
 
@@ -349,6 +381,19 @@ func (r *ManageStructureCreateExposed) Fill(req *http.Request) (err error) {
 				}
 			}
 
+			if val, ok := req.MultipartForm.Value["bidirectional"]; ok && len(val) > 0 {
+				r.Bidirectional, err = payload.ParseBool(val[0]), nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["conflictPolicy"]; ok && len(val) > 0 {
+				r.ConflictPolicy, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -393,6 +438,20 @@ func (r *ManageStructureCreateExposed) Fill(req *http.Request) (err error) {
 		//        return err
 		//    }
 		//}
+
+		if val, ok := req.Form["bidirectional"]; ok && len(val) > 0 {
+			r.Bidirectional, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["conflictPolicy"]; ok && len(val) > 0 {
+			r.ConflictPolicy, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	{
@@ -425,6 +484,8 @@ func (r ManageStructureUpdateExposed) Auditable() map[string]interface{} {
 		"name":               r.Name,
 		"handle":             r.Handle,
 		"fields":             r.Fields,
+		"bidirectional":      r.Bidirectional,
+		"conflictPolicy":     r.ConflictPolicy,
 	}
 }
 
@@ -463,6 +524,16 @@ func (r ManageStructureUpdateExposed) GetFields() types.ModuleFieldSet {
 	return r.Fields
 }
 
+// Auditable returns all auditable/loggable parameters
+func (r ManageStructureUpdateExposed) GetBidirectional() bool {
+	return r.Bidirectional
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ManageStructureUpdateExposed) GetConflictPolicy() string {
+	return r.ConflictPolicy
+}
+
 // Fill processes request and fills internal variables
 func (r *ManageStructureUpdateExposed) Fill(req *http.Request) (err error) {
 
@@ -512,6 +583,19 @@ func (r *ManageStructureUpdateExposed) Fill(req *http.Request) (err error) {
 				}
 			}
 
+			if val, ok := req.MultipartForm.Value["bidirectional"]; ok && len(val) > 0 {
+				r.Bidirectional, err = payload.ParseBool(val[0]), nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["conflictPolicy"]; ok && len(val) > 0 {
+				r.ConflictPolicy, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -556,6 +640,20 @@ func (r *ManageStructureUpdateExposed) Fill(req *http.Request) (err error) {
 		//        return err
 		//    }
 		//}
+
+		if val, ok := req.Form["bidirectional"]; ok && len(val) > 0 {
+			r.Bidirectional, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["conflictPolicy"]; ok && len(val) > 0 {
+			r.ConflictPolicy, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	{
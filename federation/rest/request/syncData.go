@@ -11,6 +11,7 @@ package request
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/cortezaproject/corteza-server/federation/types"
 	"github.com/cortezaproject/corteza-server/pkg/payload"
 	"github.com/go-chi/chi"
 	"io"
@@ -138,6 +139,79 @@ type (
 		// Sort items
 		Sort string
 	}
+
+	SyncDataReadExposedRecord struct {
+		// NodeID PATH parameter
+		//
+		// Node ID
+		NodeID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+	}
+
+	SyncDataReadExposedAttachment struct {
+		// NodeID PATH parameter
+		//
+		// Node ID
+		NodeID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// RecordID PATH parameter
+		//
+		// Record ID
+		RecordID uint64 `json:",string"`
+
+		// AttachmentID PATH parameter
+		//
+		// Attachment ID
+		AttachmentID uint64 `json:",string"`
+	}
+
+	SyncDataReceivePushed struct {
+		// NodeID PATH parameter
+		//
+		// Node ID
+		NodeID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// Changes POST parameter
+		//
+		// Changed records
+		Changes types.RecordChangeSet
+	}
+
+	SyncDataReceiveChanges struct {
+		// NodeID PATH parameter
+		//
+		// Node ID
+		NodeID uint64 `json:",string"`
+
+		// ModuleID PATH parameter
+		//
+		// Module ID
+		ModuleID uint64 `json:",string"`
+
+		// Changes POST parameter
+		//
+		// Changed records
+		Changes types.RecordChangeSet
+	}
 )
 
 // NewSyncDataReadExposedAll request
@@ -466,3 +540,311 @@ func (r *SyncDataReadExposedSocial) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewSyncDataReadExposedRecord request
+func NewSyncDataReadExposedRecord() *SyncDataReadExposedRecord {
+	return &SyncDataReadExposedRecord{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedRecord) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"nodeID":   r.NodeID,
+		"moduleID": r.ModuleID,
+		"recordID": r.RecordID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedRecord) GetNodeID() uint64 {
+	return r.NodeID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedRecord) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedRecord) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Fill processes request and fills internal variables
+func (r *SyncDataReadExposedRecord) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "nodeID")
+		r.NodeID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewSyncDataReadExposedAttachment request
+func NewSyncDataReadExposedAttachment() *SyncDataReadExposedAttachment {
+	return &SyncDataReadExposedAttachment{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedAttachment) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"nodeID":       r.NodeID,
+		"moduleID":     r.ModuleID,
+		"recordID":     r.RecordID,
+		"attachmentID": r.AttachmentID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedAttachment) GetNodeID() uint64 {
+	return r.NodeID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedAttachment) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedAttachment) GetRecordID() uint64 {
+	return r.RecordID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReadExposedAttachment) GetAttachmentID() uint64 {
+	return r.AttachmentID
+}
+
+// Fill processes request and fills internal variables
+func (r *SyncDataReadExposedAttachment) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "nodeID")
+		r.NodeID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "recordID")
+		r.RecordID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "attachmentID")
+		r.AttachmentID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewSyncDataReceivePushed request
+func NewSyncDataReceivePushed() *SyncDataReceivePushed {
+	return &SyncDataReceivePushed{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceivePushed) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"nodeID":   r.NodeID,
+		"moduleID": r.ModuleID,
+		"changes":  r.Changes,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceivePushed) GetNodeID() uint64 {
+	return r.NodeID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceivePushed) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceivePushed) GetChanges() types.RecordChangeSet {
+	return r.Changes
+}
+
+// Fill processes request and fills internal variables
+func (r *SyncDataReceivePushed) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		//if val, ok := req.Form["changes[]"]; ok && len(val) > 0  {
+		//    r.Changes, err = types.RecordChangeSet(val), nil
+		//    if err != nil {
+		//        return err
+		//    }
+		//}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "nodeID")
+		r.NodeID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewSyncDataReceiveChanges request
+func NewSyncDataReceiveChanges() *SyncDataReceiveChanges {
+	return &SyncDataReceiveChanges{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceiveChanges) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"nodeID":   r.NodeID,
+		"moduleID": r.ModuleID,
+		"changes":  r.Changes,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceiveChanges) GetNodeID() uint64 {
+	return r.NodeID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceiveChanges) GetModuleID() uint64 {
+	return r.ModuleID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r SyncDataReceiveChanges) GetChanges() types.RecordChangeSet {
+	return r.Changes
+}
+
+// Fill processes request and fills internal variables
+func (r *SyncDataReceiveChanges) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		//if val, ok := req.Form["changes[]"]; ok && len(val) > 0  {
+		//    r.Changes, err = types.RecordChangeSet(val), nil
+		//    if err != nil {
+		//        return err
+		//    }
+		//}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "nodeID")
+		r.NodeID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "moduleID")
+		r.ModuleID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
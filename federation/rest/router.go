@@ -22,5 +22,7 @@ func MountRoutes(r chi.Router) {
 
 		handlers.NewSyncData((SyncData{}.New())).MountRoutes(r)
 		handlers.NewSyncStructure((SyncStructure{}.New())).MountRoutes(r)
+
+		handlers.NewConflict(Conflict{}.New()).MountRoutes(r)
 	})
 }
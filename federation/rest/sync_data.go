@@ -2,8 +2,13 @@ package rest
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +17,7 @@ import (
 	"github.com/cortezaproject/corteza-server/federation/rest/request"
 	"github.com/cortezaproject/corteza-server/federation/service"
 	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/api"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/federation"
@@ -94,7 +100,7 @@ func (ctrl SyncData) ReadExposedAll(ctx context.Context, r *request.SyncDataRead
 		}
 
 		// todo - handle error properly
-		if list, _, err := (cs.Record()).Find(ctx, rf); err != nil || len(list) == 0 {
+		if list, _, err := (cs.Record(cs.RecordOptions{}, nil)).Find(ctx, rf); err != nil || len(list) == 0 {
 			continue
 		}
 
@@ -169,6 +175,111 @@ func (ctrl SyncData) ReadExposedSocial(ctx context.Context, r *request.SyncDataR
 	}, nil
 }
 
+// ReadExposedRecord fetches a single record of the exposed module in an internal format
+//
+// Nodes use it to fetch the records that are referenced from the synced records
+func (ctrl SyncData) ReadExposedRecord(ctx context.Context, r *request.SyncDataReadExposedRecord) (interface{}, error) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		node, em, rec, err := ctrl.readExposedRecord(ctx, r.NodeID, r.ModuleID, r.RecordID)
+
+		if err != nil {
+			errors.ServeHTTP(w, req, err, false)
+			return
+		}
+
+		payload := federation.ListDataPayload{
+			NodeID:   node.ID,
+			ModuleID: em.ID,
+			Filter:   &ct.RecordFilter{ModuleID: em.ComposeModuleID},
+			Set:      &ct.RecordSet{rec},
+		}
+
+		fEncoder := federation.NewEncoder(w, service.DefaultOptions)
+
+		err = fEncoder.Encode(payload, federation.CortezaInternalData)
+
+		if err != nil {
+			errors.ServeHTTP(w, req, err, false)
+			return
+		}
+	}, nil
+}
+
+// ReadExposedAttachment serves the attachment, referenced from
+// the exposed file field of the record
+//
+// Checksum of the content is sent in the header so the node
+// can validate the transferred file.
+func (ctrl SyncData) ReadExposedAttachment(ctx context.Context, r *request.SyncDataReadExposedAttachment) (interface{}, error) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var (
+			att *ct.Attachment
+			fh  io.ReadSeeker
+		)
+
+		_, em, rec, err := ctrl.readExposedRecord(ctx, r.NodeID, r.ModuleID, r.RecordID)
+
+		if err != nil {
+			errors.ServeHTTP(w, req, err, false)
+			return
+		}
+
+		if !hasExposedAttachment(em, rec, r.AttachmentID) {
+			errors.ServeHTTP(w, req, errors.NotFound("attachment not found"), false)
+			return
+		}
+
+		if att, err = cs.DefaultAttachment.FindByID(ctx, em.ComposeNamespaceID, r.AttachmentID); err != nil {
+			errors.ServeHTTP(w, req, err, false)
+			return
+		}
+
+		if fh, err = cs.DefaultAttachment.OpenOriginal(att); err != nil {
+			errors.ServeHTTP(w, req, err, false)
+			return
+		} else if fh == nil {
+			errors.ServeHTTP(w, req, errors.NotFound("attachment not found"), false)
+			return
+		}
+
+		if c, ok := fh.(io.Closer); ok {
+			defer c.Close()
+		}
+
+		checksum := sha256.New()
+		if _, err = io.Copy(checksum, fh); err == nil {
+			_, err = fh.Seek(0, io.SeekStart)
+		}
+
+		if err != nil {
+			errors.ServeHTTP(w, req, err, false)
+			return
+		}
+
+		name := url.QueryEscape(att.Name)
+
+		w.Header().Add("Content-Disposition", "attachment; filename="+name)
+		w.Header().Set(service.FederationChecksumHeader, hex.EncodeToString(checksum.Sum(nil)))
+
+		http.ServeContent(w, req, name, att.CreatedAt, fh)
+	}, nil
+}
+
+// ReceivePushed processes record changes pushed from the origin node
+func (ctrl SyncData) ReceivePushed(ctx context.Context, r *request.SyncDataReceivePushed) (interface{}, error) {
+	if _, err := service.DefaultSync.ReceivePushed(ctx, r.NodeID, r.ModuleID, r.Changes); err != nil {
+		return nil, err
+	}
+
+	return api.OK(), nil
+}
+
+// ReceiveChanges applies record changes made on the node
+// that shares the module bidirectionally
+func (ctrl SyncData) ReceiveChanges(ctx context.Context, r *request.SyncDataReceiveChanges) (interface{}, error) {
+	return service.DefaultConflict.ReceiveChanges(ctx, r.NodeID, r.ModuleID, r.Changes)
+}
+
 // readExposed fetches all the data - records (with paging) for an exposed module in an internal format
 func (ctrl SyncData) readExposed(ctx context.Context, r *request.SyncDataReadExposedInternal) (interface{}, error) {
 	var (
@@ -221,7 +332,7 @@ func (ctrl SyncData) readExposed(ctx context.Context, r *request.SyncDataReadExp
 		return nil, err
 	}
 
-	list, f, err := (cs.Record()).Find(ctx, f)
+	list, f, err := (cs.Record(cs.RecordOptions{}, nil)).Find(ctx, f)
 
 	if err != nil {
 		return nil, err
@@ -242,6 +353,38 @@ func (ctrl SyncData) readExposed(ctx context.Context, r *request.SyncDataReadExp
 	}, nil
 }
 
+// readExposedRecord fetches the record of the exposed module, shared with the node
+func (ctrl SyncData) readExposedRecord(ctx context.Context, nodeID, moduleID, recordID uint64) (*types.Node, *types.ExposedModule, *ct.Record, error) {
+	var (
+		err  error
+		em   *types.ExposedModule
+		rec  *ct.Record
+		node *types.Node
+	)
+
+	if node, err = service.DefaultNode.FindBySharedNodeID(ctx, nodeID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if em, err = service.DefaultExposedModule.FindByID(ctx, nodeID, moduleID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if em.NodeID != node.ID || em.DeletedAt != nil {
+		return nil, nil, nil, service.ExposedModuleErrNotFound()
+	}
+
+	if rec, err = (cs.Record(cs.RecordOptions{}, nil)).FindByID(ctx, em.ComposeNamespaceID, em.ComposeModuleID, recordID); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err = filterExposedFields(em)(rec); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return node, em, rec, nil
+}
+
 func buildLastSyncQuery(ts uint64) string {
 	if ts == 0 {
 		return ""
@@ -290,3 +433,21 @@ func filterExposedFields(em *types.ExposedModule) func(r *ct.Record) error {
 		return err
 	}
 }
+
+// hasExposedAttachment checks if the attachment is referenced
+// from one of the exposed file fields of the record
+func hasExposedAttachment(em *types.ExposedModule, rec *ct.Record, attachmentID uint64) bool {
+	for _, f := range em.Fields {
+		if f.Kind != "File" {
+			continue
+		}
+
+		for _, rv := range rec.Values.FilterByName(f.Name) {
+			if rv.Value == strconv.FormatUint(attachmentID, 10) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
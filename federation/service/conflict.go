@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	cs "github.com/cortezaproject/corteza-server/compose/service"
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+type (
+	conflict struct {
+		ac        conflictAccessController
+		record    conflictRecordService
+		store     store.Storer
+		actionlog actionlog.Recorder
+	}
+
+	conflictAccessController interface {
+		CanManageExposedModule(ctx context.Context, r *types.ExposedModule) bool
+		CanManageNode(ctx context.Context, r *types.Node) bool
+	}
+
+	conflictRecordService interface {
+		FindByID(ctx context.Context, namespaceID, moduleID, recordID uint64) (*ct.Record, error)
+		Update(ctx context.Context, record *ct.Record) (*ct.Record, error)
+		DeleteByID(ctx context.Context, namespaceID, moduleID uint64, recordID ...uint64) error
+	}
+
+	ConflictService interface {
+		Find(ctx context.Context, filter types.ConflictFilter) (types.ConflictSet, types.ConflictFilter, error)
+		FindByID(ctx context.Context, conflictID uint64) (*types.Conflict, error)
+		Resolve(ctx context.Context, conflictID uint64, resolution string) (*types.Conflict, error)
+		ReceiveChanges(ctx context.Context, sharedNodeID, moduleID uint64, changes types.RecordChangeSet) ([]*types.RecordChangeResult, error)
+	}
+
+	sourceNodeCtxKey struct{}
+)
+
+func Conflict() *conflict {
+	return &conflict{
+		ac:        DefaultAccessControl,
+		record:    cs.DefaultRecord,
+		store:     DefaultStore,
+		actionlog: DefaultActionlog,
+	}
+}
+
+// withSourceNode marks changes made with the context as received from the given node
+//
+// Push worker uses this to avoid sending changes back to the node they came from
+func withSourceNode(ctx context.Context, nodeID uint64) context.Context {
+	return context.WithValue(ctx, sourceNodeCtxKey{}, nodeID)
+}
+
+// sourceNode returns ID of the node the changes were received from
+func sourceNode(ctx context.Context) uint64 {
+	if nodeID, ok := ctx.Value(sourceNodeCtxKey{}).(uint64); ok {
+		return nodeID
+	}
+
+	return 0
+}
+
+func (svc conflict) Find(ctx context.Context, filter types.ConflictFilter) (set types.ConflictSet, f types.ConflictFilter, err error) {
+	var (
+		aProps = &conflictActionProps{filter: &filter}
+
+		// cache access checks per exposed module
+		allowed = make(map[uint64]bool)
+	)
+
+	err = func() error {
+		filter.Check = func(c *types.Conflict) (bool, error) {
+			if _, checked := allowed[c.ModuleID]; !checked {
+				em, err := store.LookupFederationExposedModuleByID(ctx, svc.store, c.ModuleID)
+				if err != nil && !errors.IsNotFound(err) {
+					return false, err
+				}
+
+				allowed[c.ModuleID] = em != nil && svc.ac.CanManageExposedModule(ctx, em)
+			}
+
+			return allowed[c.ModuleID], nil
+		}
+
+		if set, f, err = store.SearchFederationConflicts(ctx, svc.store, filter); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return set, f, svc.recordAction(ctx, aProps, ConflictActionSearch, err)
+}
+
+func (svc conflict) FindByID(ctx context.Context, conflictID uint64) (c *types.Conflict, err error) {
+	var (
+		aProps = &conflictActionProps{conflict: &types.Conflict{ID: conflictID}}
+	)
+
+	err = func() error {
+		if c, _, err = svc.lookup(ctx, conflictID); err != nil {
+			return err
+		}
+
+		aProps.setConflict(c)
+		return nil
+	}()
+
+	return c, svc.recordAction(ctx, aProps, ConflictActionLookup, err)
+}
+
+// Resolve resolves pending conflict by keeping the local (origin) or remote version of the record
+//
+// When remote version is kept, remote values are applied to the local record.
+// When local version is kept, local record is re-saved so that the paired nodes
+// receive it with the next sync.
+func (svc conflict) Resolve(ctx context.Context, conflictID uint64, resolution string) (c *types.Conflict, err error) {
+	var (
+		aProps = &conflictActionProps{conflict: &types.Conflict{ID: conflictID}}
+	)
+
+	err = func() (err error) {
+		var (
+			em  *types.ExposedModule
+			rec *ct.Record
+		)
+
+		if c, em, err = svc.lookup(ctx, conflictID); err != nil {
+			return err
+		}
+
+		aProps.setConflict(c)
+
+		if !c.IsPending() {
+			return ConflictErrAlreadyResolved(aProps)
+		}
+
+		if resolution != types.ConflictResolutionLocal && resolution != types.ConflictResolutionRemote {
+			return ConflictErrInvalidResolution(aProps)
+		}
+
+		if rec, err = svc.record.FindByID(ctx, c.ComposeNamespaceID, c.ComposeModuleID, c.ComposeRecordID); err != nil {
+			return err
+		}
+
+		switch {
+		case resolution == types.ConflictResolutionLocal:
+			_, err = svc.record.Update(ctx, rec)
+		case c.RemoteDeleted:
+			err = svc.record.DeleteByID(withSourceNode(ctx, c.NodeID), rec.NamespaceID, rec.ModuleID, rec.ID)
+		default:
+			rec.Values = mergeExposedValues(em, rec.Values, ct.RecordValueSet(c.RemoteValues))
+			_, err = svc.record.Update(withSourceNode(ctx, c.NodeID), rec)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		c.Status = types.ConflictStatusResolved
+		c.Resolution = resolution
+		c.ResolvedAt = now()
+		c.ResolvedBy = auth.GetIdentityFromContext(ctx).Identity()
+
+		return store.UpdateFederationConflict(ctx, svc.store, c)
+	}()
+
+	return c, svc.recordAction(ctx, aProps, ConflictActionResolve, err)
+}
+
+// ReceiveChanges applies record changes made on the paired node to records of
+// bidirectional exposed module
+//
+// Changes are applied as the federation system user. Conflicting changes (record
+// was changed on both nodes since the last sync) are handled with module's conflict policy.
+func (svc conflict) ReceiveChanges(ctx context.Context, sharedNodeID, moduleID uint64, changes types.RecordChangeSet) (rr []*types.RecordChangeResult, err error) {
+	var (
+		aProps = &conflictActionProps{}
+	)
+
+	err = func() (err error) {
+		var (
+			node *types.Node
+			em   *types.ExposedModule
+		)
+
+		if node, err = store.LookupFederationNodeBySharedNodeID(ctx, svc.store, sharedNodeID); err != nil {
+			return ConflictErrNodeNotFound()
+		}
+
+		aProps.setNode(node)
+
+		if !svc.ac.CanManageNode(ctx, node) {
+			return NodeErrNotAllowedToManage()
+		}
+
+		if em, err = store.LookupFederationExposedModuleByID(ctx, svc.store, moduleID); err != nil || em.NodeID != node.ID {
+			return ConflictErrModuleNotFound()
+		}
+
+		aProps.setModule(em)
+
+		if !em.Bidirectional {
+			return ConflictErrModuleNotBidirectional(aProps)
+		}
+
+		// changes are applied as federation user and
+		// marked as received from the node
+		ctx = withSourceNode(auth.SetIdentityToContext(ctx, auth.FederationUser()), node.ID)
+
+		rr = make([]*types.RecordChangeResult, 0, len(changes))
+		for _, change := range changes {
+			r, err := svc.receiveChange(ctx, node, em, change)
+			if err != nil {
+				return err
+			}
+
+			rr = append(rr, r)
+		}
+
+		return nil
+	}()
+
+	return rr, svc.recordAction(ctx, aProps, ConflictActionReceiveChanges, err)
+}
+
+func (svc conflict) receiveChange(ctx context.Context, node *types.Node, em *types.ExposedModule, change *types.RecordChange) (r *types.RecordChangeResult, err error) {
+	var (
+		rec *ct.Record
+	)
+
+	r = &types.RecordChangeResult{RecordID: change.RecordID, Status: types.RecordChangeStatusRejected}
+
+	if rec, err = svc.record.FindByID(ctx, em.ComposeNamespaceID, em.ComposeModuleID, change.RecordID); errors.IsNotFound(err) {
+		// record does not exist (anymore) on the origin
+		return r, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	apply, conflicted := reconcile(em.ConflictPolicy, rec, change)
+
+	if conflicted {
+		if err = svc.recordConflict(ctx, node, em, rec, change, apply); err != nil {
+			return nil, err
+		}
+
+		r.Status = types.RecordChangeStatusConflict
+	}
+
+	if !apply {
+		r.UpdatedAt = lastUpdate(rec)
+		return r, nil
+	}
+
+	if change.Deleted {
+		if err = svc.record.DeleteByID(ctx, rec.NamespaceID, rec.ModuleID, rec.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		rec.Values = mergeExposedValues(em, rec.Values, change.Values)
+
+		if rec, err = svc.record.Update(ctx, rec); err != nil {
+			return nil, err
+		}
+	}
+
+	r.Status = types.RecordChangeStatusApplied
+	r.UpdatedAt = lastUpdate(rec)
+	return r, nil
+}
+
+// recordConflict stores the conflict
+//
+// Conflicts handled by the policy are stored as resolved
+// and can be reviewed later; manual conflicts are pending.
+func (svc conflict) recordConflict(ctx context.Context, node *types.Node, em *types.ExposedModule, rec *ct.Record, change *types.RecordChange, applied bool) error {
+	var (
+		remoteUpdatedAt = change.UpdatedAt
+
+		c = &types.Conflict{
+			ID:                 nextID(),
+			NodeID:             node.ID,
+			ModuleID:           em.ID,
+			ComposeNamespaceID: rec.NamespaceID,
+			ComposeModuleID:    rec.ModuleID,
+			ComposeRecordID:    rec.ID,
+			Policy:             em.ConflictPolicy,
+			Status:             types.ConflictStatusPending,
+			LocalValues:        types.ConflictValueSet(exposedValues(em, rec.Values)),
+			LocalUpdatedAt:     lastUpdate(rec),
+			RemoteValues:       types.ConflictValueSet(exposedValues(em, change.Values)),
+			RemoteDeleted:      change.Deleted,
+			RemoteUpdatedAt:    &remoteUpdatedAt,
+			CreatedAt:          *now(),
+		}
+
+		aProps = &conflictActionProps{conflict: c}
+	)
+
+	if c.Policy == "" {
+		c.Policy = types.ConflictPolicyLastWriterWins
+	}
+
+	if c.Policy != types.ConflictPolicyManual {
+		c.Status = types.ConflictStatusResolved
+		c.ResolvedAt = now()
+		c.Resolution = types.ConflictResolutionLocal
+
+		if applied {
+			c.Resolution = types.ConflictResolutionRemote
+		}
+	}
+
+	return svc.recordAction(ctx, aProps, ConflictActionCreate, store.CreateFederationConflict(ctx, svc.store, c))
+}
+
+// lookup loads the conflict and the exposed module it belongs to and checks access
+func (svc conflict) lookup(ctx context.Context, conflictID uint64) (c *types.Conflict, em *types.ExposedModule, err error) {
+	if conflictID == 0 {
+		return nil, nil, ConflictErrInvalidID()
+	}
+
+	if c, err = store.LookupFederationConflictByID(ctx, svc.store, conflictID); errors.IsNotFound(err) {
+		return nil, nil, ConflictErrNotFound()
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if em, err = store.LookupFederationExposedModuleByID(ctx, svc.store, c.ModuleID); errors.IsNotFound(err) {
+		return nil, nil, ConflictErrModuleNotFound()
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if !svc.ac.CanManageExposedModule(ctx, em) {
+		return nil, nil, ConflictErrNotAllowedToManage(&conflictActionProps{conflict: c})
+	}
+
+	return c, em, nil
+}
+
+// reconcile decides if the change received from the paired node should be applied
+// to the local record and if there is a conflict between the two
+//
+// Change is in conflict with the local record when the record was updated
+// after the last version the paired node knows about.
+func reconcile(policy string, local *ct.Record, change *types.RecordChange) (apply, conflicted bool) {
+	var (
+		localUpdatedAt = lastUpdate(local)
+	)
+
+	switch {
+	case change.BaseUpdatedAt == nil:
+		// paired node does not know which version it changed;
+		// record that was never updated can not be in conflict
+		conflicted = local.UpdatedAt != nil
+	default:
+		conflicted = localUpdatedAt.After(*change.BaseUpdatedAt)
+	}
+
+	if !conflicted {
+		return true, false
+	}
+
+	switch policy {
+	case types.ConflictPolicyOriginWins, types.ConflictPolicyManual:
+		return false, true
+	default:
+		return change.UpdatedAt.After(*localUpdatedAt), true
+	}
+}
+
+// lastUpdate returns time of the last change of the record
+func lastUpdate(r *ct.Record) *time.Time {
+	switch {
+	case r.DeletedAt != nil:
+		return r.DeletedAt
+	case r.UpdatedAt != nil:
+		return r.UpdatedAt
+	default:
+		return &r.CreatedAt
+	}
+}
+
+// exposedValues returns values of exposed fields
+func exposedValues(em *types.ExposedModule, vv ct.RecordValueSet) ct.RecordValueSet {
+	out, _ := vv.Filter(func(v *ct.RecordValue) (bool, error) {
+		return em.Fields.HasField(v.Name)
+	})
+
+	return out
+}
+
+// mergeExposedValues replaces values of exposed fields with the remote values
+//
+// Values of fields that are not exposed are kept as they are.
+func mergeExposedValues(em *types.ExposedModule, local, remote ct.RecordValueSet) ct.RecordValueSet {
+	out, _ := local.Filter(func(v *ct.RecordValue) (bool, error) {
+		has, _ := em.Fields.HasField(v.Name)
+		return !has, nil
+	})
+
+	return append(out, exposedValues(em, remote)...)
+}
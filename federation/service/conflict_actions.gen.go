@@ -0,0 +1,723 @@
+package service
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// federation/service/conflict_actions.yaml
+
+import (
+	"context"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/locale"
+	"strings"
+	"time"
+)
+
+type (
+	conflictActionProps struct {
+		conflict *types.Conflict
+		filter   *types.ConflictFilter
+		node     *types.Node
+		module   *types.ExposedModule
+	}
+
+	conflictAction struct {
+		timestamp time.Time
+		resource  string
+		action    string
+		log       string
+		severity  actionlog.Severity
+
+		// prefix for error when action fails
+		errorMessage string
+
+		props *conflictActionProps
+	}
+
+	conflictLogMetaKey   struct{}
+	conflictPropsMetaKey struct{}
+)
+
+var (
+	// just a placeholder to cover template cases w/o fmt package use
+	_ = fmt.Println
+)
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Props methods
+// setConflict updates conflictActionProps's conflict
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *conflictActionProps) setConflict(conflict *types.Conflict) *conflictActionProps {
+	p.conflict = conflict
+	return p
+}
+
+// setFilter updates conflictActionProps's filter
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *conflictActionProps) setFilter(filter *types.ConflictFilter) *conflictActionProps {
+	p.filter = filter
+	return p
+}
+
+// setNode updates conflictActionProps's node
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *conflictActionProps) setNode(node *types.Node) *conflictActionProps {
+	p.node = node
+	return p
+}
+
+// setModule updates conflictActionProps's module
+//
+// Allows method chaining
+//
+// This function is auto-generated.
+//
+func (p *conflictActionProps) setModule(module *types.ExposedModule) *conflictActionProps {
+	p.module = module
+	return p
+}
+
+// Serialize converts conflictActionProps to actionlog.Meta
+//
+// This function is auto-generated.
+//
+func (p conflictActionProps) Serialize() actionlog.Meta {
+	var (
+		m = make(actionlog.Meta)
+	)
+
+	if p.conflict != nil {
+		m.Set("conflict.ID", p.conflict.ID, true)
+		m.Set("conflict.NodeID", p.conflict.NodeID, true)
+		m.Set("conflict.ModuleID", p.conflict.ModuleID, true)
+		m.Set("conflict.ComposeRecordID", p.conflict.ComposeRecordID, true)
+		m.Set("conflict.Policy", p.conflict.Policy, true)
+		m.Set("conflict.Status", p.conflict.Status, true)
+		m.Set("conflict.Resolution", p.conflict.Resolution, true)
+	}
+	if p.filter != nil {
+		m.Set("filter.NodeID", p.filter.NodeID, true)
+		m.Set("filter.ModuleID", p.filter.ModuleID, true)
+		m.Set("filter.ComposeRecordID", p.filter.ComposeRecordID, true)
+		m.Set("filter.Status", p.filter.Status, true)
+	}
+	if p.node != nil {
+		m.Set("node.ID", p.node.ID, true)
+		m.Set("node.Name", p.node.Name, true)
+	}
+	if p.module != nil {
+		m.Set("module.ID", p.module.ID, true)
+		m.Set("module.Name", p.module.Name, true)
+	}
+
+	return m
+}
+
+// tr translates string and replaces meta value placeholder with values
+//
+// This function is auto-generated.
+//
+func (p conflictActionProps) Format(in string, err error) string {
+	var (
+		pairs = []string{"{{err}}"}
+		// first non-empty string
+		fns = func(ii ...interface{}) string {
+			for _, i := range ii {
+				if s := fmt.Sprintf("%v", i); len(s) > 0 {
+					return s
+				}
+			}
+
+			return ""
+		}
+	)
+
+	if err != nil {
+		pairs = append(pairs, err.Error())
+	} else {
+		pairs = append(pairs, "nil")
+	}
+
+	if p.conflict != nil {
+		// replacement for "{{conflict}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{conflict}}",
+			fns(
+				p.conflict.ID,
+				p.conflict.NodeID,
+				p.conflict.ModuleID,
+				p.conflict.ComposeRecordID,
+				p.conflict.Policy,
+				p.conflict.Status,
+				p.conflict.Resolution,
+			),
+		)
+		pairs = append(pairs, "{{conflict.ID}}", fns(p.conflict.ID))
+		pairs = append(pairs, "{{conflict.NodeID}}", fns(p.conflict.NodeID))
+		pairs = append(pairs, "{{conflict.ModuleID}}", fns(p.conflict.ModuleID))
+		pairs = append(pairs, "{{conflict.ComposeRecordID}}", fns(p.conflict.ComposeRecordID))
+		pairs = append(pairs, "{{conflict.Policy}}", fns(p.conflict.Policy))
+		pairs = append(pairs, "{{conflict.Status}}", fns(p.conflict.Status))
+		pairs = append(pairs, "{{conflict.Resolution}}", fns(p.conflict.Resolution))
+	}
+
+	if p.filter != nil {
+		// replacement for "{{filter}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{filter}}",
+			fns(
+				p.filter.NodeID,
+				p.filter.ModuleID,
+				p.filter.ComposeRecordID,
+				p.filter.Status,
+			),
+		)
+		pairs = append(pairs, "{{filter.NodeID}}", fns(p.filter.NodeID))
+		pairs = append(pairs, "{{filter.ModuleID}}", fns(p.filter.ModuleID))
+		pairs = append(pairs, "{{filter.ComposeRecordID}}", fns(p.filter.ComposeRecordID))
+		pairs = append(pairs, "{{filter.Status}}", fns(p.filter.Status))
+	}
+
+	if p.node != nil {
+		// replacement for "{{node}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{node}}",
+			fns(
+				p.node.ID,
+				p.node.Name,
+			),
+		)
+		pairs = append(pairs, "{{node.ID}}", fns(p.node.ID))
+		pairs = append(pairs, "{{node.Name}}", fns(p.node.Name))
+	}
+
+	if p.module != nil {
+		// replacement for "{{module}}" (in order how fields are defined)
+		pairs = append(
+			pairs,
+			"{{module}}",
+			fns(
+				p.module.ID,
+				p.module.Name,
+			),
+		)
+		pairs = append(pairs, "{{module.ID}}", fns(p.module.ID))
+		pairs = append(pairs, "{{module.Name}}", fns(p.module.Name))
+	}
+	return strings.NewReplacer(pairs...).Replace(in)
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action methods
+
+// String returns loggable description as string
+//
+// This function is auto-generated.
+//
+func (a *conflictAction) String() string {
+	var props = &conflictActionProps{}
+
+	if a.props != nil {
+		props = a.props
+	}
+
+	return props.Format(a.log, nil)
+}
+
+func (e *conflictAction) ToAction() *actionlog.Action {
+	return &actionlog.Action{
+		Resource:    e.resource,
+		Action:      e.action,
+		Severity:    e.severity,
+		Description: e.String(),
+		Meta:        e.props.Serialize(),
+	}
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Action constructors
+
+// ConflictActionSearch returns "federation:conflict.search" action
+//
+// This function is auto-generated.
+//
+func ConflictActionSearch(props ...*conflictActionProps) *conflictAction {
+	a := &conflictAction{
+		timestamp: time.Now(),
+		resource:  "federation:conflict",
+		action:    "search",
+		log:       "searched for conflicts",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ConflictActionLookup returns "federation:conflict.lookup" action
+//
+// This function is auto-generated.
+//
+func ConflictActionLookup(props ...*conflictActionProps) *conflictAction {
+	a := &conflictAction{
+		timestamp: time.Now(),
+		resource:  "federation:conflict",
+		action:    "lookup",
+		log:       "looked-up for a {{conflict}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ConflictActionCreate returns "federation:conflict.create" action
+//
+// This function is auto-generated.
+//
+func ConflictActionCreate(props ...*conflictActionProps) *conflictAction {
+	a := &conflictAction{
+		timestamp: time.Now(),
+		resource:  "federation:conflict",
+		action:    "create",
+		log:       "recorded conflict on {{conflict}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ConflictActionResolve returns "federation:conflict.resolve" action
+//
+// This function is auto-generated.
+//
+func ConflictActionResolve(props ...*conflictActionProps) *conflictAction {
+	a := &conflictAction{
+		timestamp: time.Now(),
+		resource:  "federation:conflict",
+		action:    "resolve",
+		log:       "resolved {{conflict}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// ConflictActionReceiveChanges returns "federation:conflict.receiveChanges" action
+//
+// This function is auto-generated.
+//
+func ConflictActionReceiveChanges(props ...*conflictActionProps) *conflictAction {
+	a := &conflictAction{
+		timestamp: time.Now(),
+		resource:  "federation:conflict",
+		action:    "receiveChanges",
+		log:       "received record changes from {{node}}",
+		severity:  actionlog.Info,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+// Error constructors
+
+// ConflictErrGeneric returns "federation:conflict.generic" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrGeneric(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("failed to complete request due to internal error", nil),
+
+		errors.Meta("type", "generic"),
+		errors.Meta("resource", "federation:conflict"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(conflictLogMetaKey{}, "{err}"),
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.generic"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrNotFound returns "federation:conflict.notFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrNotFound(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("conflict does not exist", nil),
+
+		errors.Meta("type", "notFound"),
+		errors.Meta("resource", "federation:conflict"),
+
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.notFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrInvalidID returns "federation:conflict.invalidID" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrInvalidID(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid ID", nil),
+
+		errors.Meta("type", "invalidID"),
+		errors.Meta("resource", "federation:conflict"),
+
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.invalidID"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrNodeNotFound returns "federation:conflict.nodeNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrNodeNotFound(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("node does not exist", nil),
+
+		errors.Meta("type", "nodeNotFound"),
+		errors.Meta("resource", "federation:conflict"),
+
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.nodeNotFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrModuleNotFound returns "federation:conflict.moduleNotFound" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrModuleNotFound(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("exposed module does not exist", nil),
+
+		errors.Meta("type", "moduleNotFound"),
+		errors.Meta("resource", "federation:conflict"),
+
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.moduleNotFound"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrModuleNotBidirectional returns "federation:conflict.moduleNotBidirectional" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrModuleNotBidirectional(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("exposed module does not accept record changes", nil),
+
+		errors.Meta("type", "moduleNotBidirectional"),
+		errors.Meta("resource", "federation:conflict"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(conflictLogMetaKey{}, "could not apply record changes on {{module}}; module is not bidirectional"),
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.moduleNotBidirectional"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrAlreadyResolved returns "federation:conflict.alreadyResolved" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrAlreadyResolved(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("conflict is already resolved", nil),
+
+		errors.Meta("type", "alreadyResolved"),
+		errors.Meta("resource", "federation:conflict"),
+
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.alreadyResolved"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrInvalidResolution returns "federation:conflict.invalidResolution" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrInvalidResolution(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid resolution", nil),
+
+		errors.Meta("type", "invalidResolution"),
+		errors.Meta("resource", "federation:conflict"),
+
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.invalidResolution"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// ConflictErrNotAllowedToManage returns "federation:conflict.notAllowedToManage" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ConflictErrNotAllowedToManage(mm ...*conflictActionProps) *errors.Error {
+	var p = &conflictActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to manage conflicts of this module", nil),
+
+		errors.Meta("type", "notAllowedToManage"),
+		errors.Meta("resource", "federation:conflict"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(conflictLogMetaKey{}, "could not manage {{conflict}}; insufficient permissions"),
+		errors.Meta(conflictPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "conflict.errors.notAllowedToManage"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// *********************************************************************************************************************
+// *********************************************************************************************************************
+
+// recordAction is a service helper function wraps function that can return error
+//
+// It will wrap unrecognized/internal errors with generic errors.
+//
+// This function is auto-generated.
+//
+func (svc conflict) recordAction(ctx context.Context, props *conflictActionProps, actionFn func(...*conflictActionProps) *conflictAction, err error) error {
+	if svc.actionlog == nil || actionFn == nil {
+		// action log disabled or no action fn passed, return error as-is
+		return err
+	} else if err == nil {
+		// action completed w/o error, record it
+		svc.actionlog.Record(ctx, actionFn(props).ToAction())
+		return nil
+	}
+
+	a := actionFn(props).ToAction()
+
+	// Extracting error information and recording it as action
+	a.Error = err.Error()
+
+	switch c := err.(type) {
+	case *errors.Error:
+		m := c.Meta()
+
+		a.Error = err.Error()
+		a.Severity = actionlog.Severity(m.AsInt("severity"))
+		a.Description = props.Format(m.AsString(conflictLogMetaKey{}), err)
+
+		if p, has := m[conflictPropsMetaKey{}]; has {
+			a.Meta = p.(*conflictActionProps).Serialize()
+		}
+
+		svc.actionlog.Record(ctx, a)
+	default:
+		svc.actionlog.Record(ctx, a)
+	}
+
+	// Original error is passed on
+	return err
+}
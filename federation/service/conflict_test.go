@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflict_reconcile(t *testing.T) {
+	var (
+		t0 = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+		t1 = t0.Add(time.Minute)
+		t2 = t0.Add(2 * time.Minute)
+
+		// record on the origin, updated at t1
+		updated = &ct.Record{CreatedAt: t0, UpdatedAt: &t1}
+
+		// record on the origin, never updated
+		created = &ct.Record{CreatedAt: t0}
+
+		change = func(updatedAt time.Time, base *time.Time) *types.RecordChange {
+			return &types.RecordChange{UpdatedAt: updatedAt, BaseUpdatedAt: base}
+		}
+	)
+
+	tcc := []struct {
+		name       string
+		policy     string
+		local      *ct.Record
+		change     *types.RecordChange
+		apply      bool
+		conflicted bool
+	}{
+		{"no conflict, based on the latest version", types.ConflictPolicyManual, updated, change(t2, &t1), true, false},
+		{"no conflict, record was never updated", types.ConflictPolicyManual, created, change(t2, nil), true, false},
+		{"conflict, unknown base version", types.ConflictPolicyOriginWins, updated, change(t2, nil), false, true},
+		{"last writer wins, remote is newer", types.ConflictPolicyLastWriterWins, updated, change(t2, &t0), true, true},
+		{"last writer wins, local is newer", types.ConflictPolicyLastWriterWins, updated, change(t0, &t0), false, true},
+		{"default policy is last writer wins", "", updated, change(t2, &t0), true, true},
+		{"origin wins", types.ConflictPolicyOriginWins, updated, change(t2, &t0), false, true},
+		{"manual", types.ConflictPolicyManual, updated, change(t2, &t0), false, true},
+	}
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			apply, conflicted := reconcile(tc.policy, tc.local, tc.change)
+			require.Equal(t, tc.apply, apply)
+			require.Equal(t, tc.conflicted, conflicted)
+		})
+	}
+}
+
+func TestConflict_mergeExposedValues(t *testing.T) {
+	var (
+		req = require.New(t)
+		em  = &types.ExposedModule{Fields: types.ModuleFieldSet{{Name: "Name"}, {Name: "Phone"}}}
+
+		local = ct.RecordValueSet{
+			&ct.RecordValue{Name: "Name", Value: "local"},
+			&ct.RecordValue{Name: "Phone", Value: "000"},
+			&ct.RecordValue{Name: "Internal", Value: "secret"},
+		}
+
+		remote = ct.RecordValueSet{
+			&ct.RecordValue{Name: "Name", Value: "remote"},
+			&ct.RecordValue{Name: "Internal", Value: "overwritten"},
+		}
+	)
+
+	req.Equal(ct.RecordValueSet{
+		&ct.RecordValue{Name: "Internal", Value: "secret"},
+		&ct.RecordValue{Name: "Name", Value: "remote"},
+	}, mergeExposedValues(em, local, remote))
+}
@@ -111,6 +111,10 @@ func (svc exposedModule) Update(ctx context.Context, updated *types.ExposedModul
 			return ExposedModuleErrNotAllowedToManage()
 		}
 
+		if !types.ValidConflictPolicy(updated.ConflictPolicy) {
+			return ExposedModuleErrInvalidConflictPolicy()
+		}
+
 		if _, err := svc.namespace.FindByID(ctx, updated.ComposeNamespaceID); err != nil {
 			return ExposedModuleErrComposeNamespaceNotFound()
 		}
@@ -252,6 +256,10 @@ func (svc exposedModule) Create(ctx context.Context, new *types.ExposedModule) (
 			return ExposedModuleErrNotAllowedToCreate()
 		}
 
+		if !types.ValidConflictPolicy(new.ConflictPolicy) {
+			return ExposedModuleErrInvalidConflictPolicy()
+		}
+
 		if _, err := svc.namespace.FindByID(ctx, new.ComposeNamespaceID); err != nil {
 			return ExposedModuleErrComposeNamespaceNotFound()
 		}
@@ -727,6 +727,40 @@ func ExposedModuleErrComposeNamespaceNotFound(mm ...*exposedModuleActionProps) *
 	return e
 }
 
+// ExposedModuleErrInvalidConflictPolicy returns "federation:exposed_module.invalidConflictPolicy" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ExposedModuleErrInvalidConflictPolicy(mm ...*exposedModuleActionProps) *errors.Error {
+	var p = &exposedModuleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid conflict policy", nil),
+
+		errors.Meta("type", "invalidConflictPolicy"),
+		errors.Meta("resource", "federation:exposed_module"),
+
+		errors.Meta(exposedModulePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "federation"),
+		errors.Meta(locale.ErrorMetaKey{}, "exposedModule.errors.invalidConflictPolicy"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // ExposedModuleErrRequestParametersInvalid returns "federation:exposed_module.requestParametersInvalid" as *errors.Error
 //
 //
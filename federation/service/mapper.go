@@ -39,6 +39,26 @@ func (m *Mapper) Merge(in *ct.RecordValueSet, out *ct.RecordValueSet, mappings *
 	return
 }
 
+// Reverse maps the values of the destination module
+// back to the fields of the originating module
+func (m *Mapper) Reverse(in ct.RecordValueSet, mappings types.ModuleFieldMappingSet) (out ct.RecordValueSet) {
+	var match *types.ModuleFieldMapping
+
+	for _, destVal := range in {
+		if match, _ = mappings.FindByName(destVal.Name, types.ModuleFieldMappingSetFindTypeDestination); match == nil {
+			continue
+		}
+
+		out = append(out, &ct.RecordValue{
+			Name:  match.Origin.Name,
+			Value: destVal.Value,
+			Place: destVal.Place,
+		})
+	}
+
+	return
+}
+
 // Prepare creates a set of Records to be used later
 // when the fields will be mapped via Merge()
 func (m *Mapper) Prepare(mappings types.ModuleFieldMappingSet) (out ct.RecordValueSet) {
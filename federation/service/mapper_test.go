@@ -95,3 +95,20 @@ func TestMapper_prepare(t *testing.T) {
 		})
 	}
 }
+
+func TestMapper_reverse(t *testing.T) {
+	var (
+		req    = require.New(t)
+		mapper = &Mapper{}
+		mm     = types.ModuleFieldMappingSet{}
+	)
+
+	req.NoError(json.Unmarshal([]byte(`[{"origin":{"kind":"String","name":"Description"},"destination":{"kind":"String","name":"Name"}},{"origin":{"kind":"Url","name":"Facebook"},"destination":{"kind":"Url","name":"Fb"}}]`), &mm))
+
+	out := mapper.Reverse(
+		ct.RecordValueSet{&ct.RecordValue{Name: "Name", Value: "foo"}, &ct.RecordValue{Name: "Fb", Value: "https://fb.com/user_1"}, &ct.RecordValue{Name: "Local", Value: "bar"}},
+		mm,
+	)
+
+	req.Equal(ct.RecordValueSet{&ct.RecordValue{Name: "Description", Value: "foo"}, &ct.RecordValue{Name: "Facebook", Value: "https://fb.com/user_1"}}, out)
+}
@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"sync"
 
 	cs "github.com/cortezaproject/corteza-server/compose/service"
 	ct "github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/federation/types"
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/store"
 )
 
@@ -19,6 +21,20 @@ type (
 		smodule   SharedModuleService
 		namespace cs.NamespaceService
 		actionlog actionlog.Recorder
+
+		mapped *mappedModules
+	}
+
+	// mappedModules caches IDs of compose modules that are mapped to shared modules
+	//
+	// Set is loaded on first use and dropped when mappings change
+	mappedModules struct {
+		l   sync.RWMutex
+		set map[uint64]bool
+
+		// bumped on each reset so that set loaded
+		// before the reset is not cached
+		gen uint
 	}
 
 	moduleMappingAccessController interface {
@@ -30,11 +46,18 @@ type (
 		FindByID(ctx context.Context, federationModuleID uint64) (*types.ModuleMapping, error)
 		Create(ctx context.Context, new *types.ModuleMapping) (*types.ModuleMapping, error)
 		Update(ctx context.Context, updated *types.ModuleMapping) (*types.ModuleMapping, error)
+		IsMapped(ctx context.Context, composeModuleID uint64) (bool, error)
+		Reset()
 	}
 
 	moduleMappingUpdateHandler func(ctx context.Context, c *types.ModuleMapping) (bool, bool, error)
 )
 
+const (
+	// ModuleMappingInvalidationKind is used to notify other nodes about changed module mappings
+	ModuleMappingInvalidationKind = "federation:module-mapping"
+)
+
 func ModuleMapping() *moduleMapping {
 	return &moduleMapping{
 		ac:        DefaultAccessControl,
@@ -44,6 +67,7 @@ func ModuleMapping() *moduleMapping {
 		smodule:   DefaultSharedModule,
 		module:    cs.DefaultModule,
 		namespace: cs.DefaultNamespace,
+		mapped:    &mappedModules{},
 	}
 }
 
@@ -155,6 +179,10 @@ func (svc moduleMapping) Create(ctx context.Context, new *types.ModuleMapping) (
 		return nil
 	})
 
+	if err == nil {
+		svc.changed(ctx)
+	}
+
 	return new, svc.recordAction(ctx, aProps, ModuleMappingActionCreate, err)
 }
 
@@ -199,9 +227,57 @@ func (svc moduleMapping) Update(ctx context.Context, updated *types.ModuleMappin
 		return nil
 	})
 
+	if err == nil {
+		svc.changed(ctx)
+	}
+
 	return updated, svc.recordAction(ctx, aProps, ModuleMappingActionUpdate, err)
 }
 
+// IsMapped checks if compose module is mapped to any of the shared modules
+func (svc moduleMapping) IsMapped(ctx context.Context, composeModuleID uint64) (bool, error) {
+	svc.mapped.l.RLock()
+	set, gen := svc.mapped.set, svc.mapped.gen
+	svc.mapped.l.RUnlock()
+
+	if set != nil {
+		return set[composeModuleID], nil
+	}
+
+	mm, _, err := store.SearchFederationModuleMappings(ctx, svc.store, types.ModuleMappingFilter{})
+	if err != nil {
+		return false, err
+	}
+
+	set = make(map[uint64]bool, len(mm))
+	for _, m := range mm {
+		set[m.ComposeModuleID] = true
+	}
+
+	svc.mapped.l.Lock()
+	if gen == svc.mapped.gen {
+		svc.mapped.set = set
+	}
+	svc.mapped.l.Unlock()
+
+	return set[composeModuleID], nil
+}
+
+// Reset drops cached module mappings
+func (svc moduleMapping) Reset() {
+	svc.mapped.l.Lock()
+	defer svc.mapped.l.Unlock()
+
+	svc.mapped.set = nil
+	svc.mapped.gen++
+}
+
+// changed resets cached mappings on this and other nodes
+func (svc moduleMapping) changed(ctx context.Context) {
+	svc.Reset()
+	invalidation.Notify(ctx, ModuleMappingInvalidationKind)
+}
+
 func (svc moduleMapping) uniqueCheck(ctx context.Context, m *types.ModuleMapping) (err error) {
 	f := types.ModuleMappingFilter{
 		FederationModuleID: m.FederationModuleID,
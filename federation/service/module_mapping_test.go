@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/sqlite3"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestModuleMapping_IsMapped(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx    = context.Background()
+		s, err = sqlite3.ConnectInMemoryWithDebug(ctx)
+	)
+
+	req.NoError(err)
+	req.NoError(store.Upgrade(ctx, zap.NewNop(), s))
+	req.NoError(store.TruncateFederationModuleMappings(ctx, s))
+
+	var (
+		svc = &moduleMapping{store: s, mapped: &mappedModules{}}
+
+		mapping = func(composeModuleID uint64) *types.ModuleMapping {
+			return &types.ModuleMapping{
+				NodeID:             nextID(),
+				FederationModuleID: nextID(),
+				ComposeModuleID:    composeModuleID,
+				ComposeNamespaceID: nextID(),
+			}
+		}
+	)
+
+	req.NoError(store.CreateFederationModuleMapping(ctx, s, mapping(1)))
+
+	mapped, err := svc.IsMapped(ctx, 1)
+	req.NoError(err)
+	req.True(mapped)
+
+	mapped, err = svc.IsMapped(ctx, 2)
+	req.NoError(err)
+	req.False(mapped)
+
+	// mappings are cached until reset
+	req.NoError(store.CreateFederationModuleMapping(ctx, s, mapping(2)))
+
+	mapped, err = svc.IsMapped(ctx, 2)
+	req.NoError(err)
+	req.False(mapped)
+
+	svc.Reset()
+
+	mapped, err = svc.IsMapped(ctx, 2)
+	req.NoError(err)
+	req.True(mapped)
+}
@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	ct "github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/federation/types"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/decoder"
 	st "github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
 )
 
 type (
@@ -22,6 +24,17 @@ type (
 		SyncService         *Sync
 		Node                *types.Node
 		User                *st.User
+
+		// fetch referenced records that are not synced yet
+		FollowReferences bool
+
+		// destination compose module, loaded when references are resolved
+		module *ct.Module
+
+		// depth of the followed references
+		depth int
+
+		log *zap.Logger
 	}
 
 	dataProcesserResponse struct {
@@ -29,6 +42,11 @@ type (
 	}
 )
 
+const (
+	// label with the time of the last change of the record on the origin node
+	federationLabelExtUpdated = "federation_extupdated"
+)
+
 // Process gets the payload from syncer and
 // uses the decode package to decode the whole set, depending on
 // the filtering that was used (limit)
@@ -42,6 +60,14 @@ func (dp *dataProcesser) Process(ctx context.Context, payload []byte) (Processer
 		}, err
 	}
 
+	return dp.processRecords(ctx, o)
+}
+
+// processRecords creates, updates or deletes local records
+// from the exposed records of the origin node
+func (dp *dataProcesser) processRecords(ctx context.Context, o []*decoder.ExposedRecord) (ProcesserResponse, error) {
+	processed := 0
+
 	if len(o) == 0 {
 		return dataProcesserResponse{
 			Processed: processed,
@@ -54,19 +80,33 @@ func (dp *dataProcesser) Process(ctx context.Context, payload []byte) (Processer
 		var (
 			rec *ct.Record
 			err error
+
+			log = dp.logger().With(zap.Uint64("extRecordID", er.ID))
 		)
 
 		dp.SyncService.mapper.Merge(&er.Values, dp.ModuleMappingValues, dp.ModuleMappings)
 
+		if er.DeletedAt == nil {
+			// resolved before the lookup, records that reference
+			// each other could be created in the meantime
+			if err = dp.resolveRecordReferences(ctx, *dp.ModuleMappingValues); err != nil {
+				log.Warn("could not resolve record references, skipping", zap.Error(err))
+				continue
+			}
+		}
+
 		if er.DeletedAt != nil {
 			// find the record
 			if rec, err = dp.findRecordByFederationID(ctx, er.ID, dp.ComposeModuleID, dp.ComposeNamespaceID); err != nil {
+				log.Warn("could not find record, skipping", zap.Error(err))
 				continue
 			}
 
 			// Handle edge cases where the data doesn't exist anymore
 			if rec != nil {
-				dp.SyncService.DeleteRecord(ctx, rec)
+				if err = dp.SyncService.DeleteRecord(ctx, rec); err != nil {
+					log.Warn("could not delete record", zap.Error(err))
+				}
 			}
 			processed++
 
@@ -76,6 +116,7 @@ func (dp *dataProcesser) Process(ctx context.Context, payload []byte) (Processer
 		if er.UpdatedAt != nil {
 			if rec, err = dp.findRecordByFederationID(ctx, er.ID, dp.ComposeModuleID, dp.ComposeNamespaceID); err != nil {
 				// could not find existing record
+				log.Warn("could not find record, skipping", zap.Error(err))
 				continue
 			}
 
@@ -97,6 +138,15 @@ func (dp *dataProcesser) Process(ctx context.Context, payload []byte) (Processer
 			AddFederationLabel(rec, "federation_extrecord", fmt.Sprintf("%d", er.ID))
 		}
 
+		if err = dp.resolveAttachments(ctx, rec, er.ID); err != nil {
+			log.Warn("could not transfer record attachments, skipping", zap.Error(err))
+			continue
+		}
+
+		// remember the version of the origin record,
+		// changes of bidirectionally shared records are checked against it
+		AddFederationLabel(rec, federationLabelExtUpdated, exposedRecordUpdatedAt(er).Format(time.RFC3339Nano))
+
 		if rec.ID != 0 {
 			_, err = dp.SyncService.UpdateRecord(ctx, rec)
 		} else {
@@ -104,6 +154,7 @@ func (dp *dataProcesser) Process(ctx context.Context, payload []byte) (Processer
 		}
 
 		if err != nil {
+			log.Warn("could not store record, skipping", zap.Error(err))
 			continue
 		}
 
@@ -115,6 +166,23 @@ func (dp *dataProcesser) Process(ctx context.Context, payload []byte) (Processer
 	}, nil
 }
 
+func (dp *dataProcesser) logger() *zap.Logger {
+	if dp.log == nil {
+		return zap.NewNop()
+	}
+
+	return dp.log
+}
+
+// exposedRecordUpdatedAt returns time of the last change of the exposed record
+func exposedRecordUpdatedAt(er *decoder.ExposedRecord) time.Time {
+	if er.UpdatedAt != nil {
+		return *er.UpdatedAt
+	}
+
+	return er.CreatedAt
+}
+
 // findRecordByFederationID finds any already existing records via
 // federation label
 func (dp *dataProcesser) findRecordByFederationID(ctx context.Context, recordID, moduleID, namespaceID uint64) (r *ct.Record, err error) {
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/decoder"
+	ss "github.com/cortezaproject/corteza-server/system/service"
+	"go.uber.org/zap"
+)
+
+const (
+	// FederationChecksumHeader holds the SHA-256 checksum of the transferred attachment
+	FederationChecksumHeader = "X-Federation-Checksum"
+
+	// prefix of the labels that link the origin attachments
+	// with their copies on this node
+	federationLabelExtAttachment = "federation_extattachment_"
+
+	// how deep the references of the fetched records are followed
+	referenceFollowDepth = 3
+
+	megabyte = 1_000_000
+
+	// max size of the transferred attachment when
+	// neither field nor settings limit it
+	attachmentFallbackMaxSize = 100 * megabyte
+)
+
+// resolveRecordReferences replaces the IDs of the records, referenced
+// on the origin node, with the IDs of their federated copies
+//
+// References that can not be resolved are removed.
+func (dp *dataProcesser) resolveRecordReferences(ctx context.Context, vv ct.RecordValueSet) error {
+	var (
+		fields = mappedFieldsOfKind(*dp.ModuleMappings, "Record")
+	)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	m, err := dp.composeModule(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, rv := range vv {
+		if !fields[rv.Name] || rv.Value == "" {
+			continue
+		}
+
+		var (
+			f        = m.Fields.FindByName(rv.Name)
+			extID, _ = strconv.ParseUint(rv.Value, 10, 64)
+		)
+
+		rv.Value = ""
+
+		if f == nil || f.Options.UInt64("moduleID") == 0 || extID == 0 {
+			continue
+		}
+
+		if rec := dp.findReferencedRecord(ctx, extID, f.Options.UInt64("moduleID")); rec != nil {
+			rv.Value = strconv.FormatUint(rec.ID, 10)
+		}
+	}
+
+	return nil
+}
+
+// findReferencedRecord finds the federated copy of the referenced record and
+// fetches it from the origin node when it is not synced yet (if enabled)
+func (dp *dataProcesser) findReferencedRecord(ctx context.Context, extID, composeModuleID uint64) *ct.Record {
+	rec, _ := dp.findRecordByFederationID(ctx, extID, composeModuleID, dp.ComposeNamespaceID)
+
+	if rec != nil || !dp.FollowReferences || dp.depth >= referenceFollowDepth {
+		return rec
+	}
+
+	if err := dp.followReference(ctx, extID, composeModuleID); err != nil {
+		dp.logger().Warn("could not fetch referenced record",
+			zap.Uint64("extRecordID", extID),
+			zap.Uint64("composeModuleID", composeModuleID),
+			zap.Error(err))
+
+		return nil
+	}
+
+	rec, _ = dp.findRecordByFederationID(ctx, extID, composeModuleID, dp.ComposeNamespaceID)
+	return rec
+}
+
+// followReference fetches the referenced record from the module that is
+// shared by the same node and mapped to the referenced compose module
+func (dp *dataProcesser) followReference(ctx context.Context, extID, composeModuleID uint64) error {
+	mm, sm, err := dp.SyncService.FindNodeModuleMapping(ctx, dp.Node.ID, composeModuleID)
+	if err != nil {
+		return err
+	}
+
+	values, err := dp.SyncService.PrepareModuleMappings(ctx, mm)
+	if err != nil {
+		return err
+	}
+
+	href := fmt.Sprintf("%s/nodes/%d/modules/%d/records/%d", dp.Node.BaseURL, dp.Node.SharedNodeID, sm.ExternalFederationModuleID, extID)
+
+	// use the authToken from node pairing
+	body, err := dp.SyncService.FetchUrl(context.WithValue(ctx, FederationUserToken, dp.Node.AuthToken), href)
+	if err != nil {
+		return err
+	}
+
+	if c, ok := body.(io.Closer); ok {
+		defer c.Close()
+	}
+
+	payload, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	o, err := decoder.DecodeFederationRecordSync(payload)
+	if err != nil {
+		return err
+	}
+
+	ref := &dataProcesser{
+		ID:                  sm.ExternalFederationModuleID,
+		ComposeModuleID:     mm.ComposeModuleID,
+		ComposeNamespaceID:  mm.ComposeNamespaceID,
+		NodeBaseURL:         dp.NodeBaseURL,
+		ModuleMappings:      &mm.FieldMapping,
+		ModuleMappingValues: &values,
+		SyncService:         dp.SyncService,
+		Node:                dp.Node,
+		User:                dp.User,
+		FollowReferences:    dp.FollowReferences,
+		depth:               dp.depth + 1,
+		log:                 dp.log,
+	}
+
+	_, err = ref.processRecords(ctx, o)
+	return err
+}
+
+// resolveAttachments transfers the attachments, referenced from the file fields,
+// from the origin node and replaces their IDs with the IDs of the local copies
+//
+// Attachments that were already transferred are reused, record labels
+// link the origin attachments with their local copies.
+func (dp *dataProcesser) resolveAttachments(ctx context.Context, rec *ct.Record, extRecordID uint64) error {
+	var (
+		fields      = mappedFieldsOfKind(*dp.ModuleMappings, "File")
+		labels      = rec.GetLabels()
+		transferred = make(map[string]string)
+	)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	m, err := dp.composeModule(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, rv := range rec.Values {
+		if !fields[rv.Name] || rv.Value == "" {
+			continue
+		}
+
+		key := federationLabelExtAttachment + rv.Value
+
+		if localID := labels[key]; localID != "" && dp.hasAttachment(ctx, rec.NamespaceID, localID) {
+			rv.Value = localID
+			transferred[key] = localID
+			continue
+		}
+
+		href := fmt.Sprintf("%s/nodes/%d/modules/%d/records/%d/attachments/%s", dp.Node.BaseURL, dp.Node.SharedNodeID, dp.ID, extRecordID, rv.Value)
+
+		// use the authToken from node pairing
+		name, content, err := dp.SyncService.FetchAttachment(context.WithValue(ctx, FederationUserToken, dp.Node.AuthToken), href, attachmentMaxSize(m.Fields.FindByName(rv.Name)))
+		if err != nil {
+			return err
+		}
+
+		att, err := dp.SyncService.CreateAttachment(ctx, rec, rv.Name, name, content)
+		if err != nil {
+			return err
+		}
+
+		rv.Value = strconv.FormatUint(att.ID, 10)
+		transferred[key] = rv.Value
+	}
+
+	// forget the attachments that are no longer used
+	for key := range labels {
+		if strings.HasPrefix(key, federationLabelExtAttachment) && transferred[key] == "" {
+			delete(rec.Labels, key)
+		}
+	}
+
+	for key, localID := range transferred {
+		AddFederationLabel(rec, key, localID)
+	}
+
+	return nil
+}
+
+// attachmentMaxSize returns max size (in bytes) of the attachment in the file field
+//
+// Same limits as for uploaded attachments apply: field option or the settings.
+func attachmentMaxSize(f *ct.ModuleField) (maxSize int64) {
+	if ss.CurrentSettings != nil {
+		maxSize = int64(ss.CurrentSettings.Compose.Record.Attachments.MaxSize) * megabyte
+	}
+
+	if f != nil {
+		if aux := f.Options.Int64("maxSize"); aux > 0 {
+			maxSize = aux * megabyte
+		}
+	}
+
+	if maxSize <= 0 {
+		maxSize = attachmentFallbackMaxSize
+	}
+
+	return
+}
+
+func (dp *dataProcesser) hasAttachment(ctx context.Context, namespaceID uint64, attachmentID string) bool {
+	ID, _ := strconv.ParseUint(attachmentID, 10, 64)
+	if ID == 0 {
+		return false
+	}
+
+	att, err := dp.SyncService.FindAttachment(ctx, namespaceID, ID)
+	return err == nil && att != nil
+}
+
+// composeModule loads the destination compose module with its fields
+func (dp *dataProcesser) composeModule(ctx context.Context) (*ct.Module, error) {
+	if dp.module != nil {
+		return dp.module, nil
+	}
+
+	m, err := dp.SyncService.FindComposeModule(ctx, dp.ComposeNamespaceID, dp.ComposeModuleID)
+	if err != nil {
+		return nil, err
+	}
+
+	dp.module = m
+	return m, nil
+}
+
+// mappedFieldsOfKind returns names of the destination fields that are
+// mapped from the origin fields of the same kind
+func mappedFieldsOfKind(mappings types.ModuleFieldMappingSet, kind string) map[string]bool {
+	out := make(map[string]bool)
+
+	for _, m := range mappings {
+		if m.Origin.Kind == kind && m.Destination.Kind == kind {
+			out[m.Destination.Name] = true
+		}
+	}
+
+	return out
+}
+
+// validChecksum compares the SHA-256 checksum of the content with the expected hex encoded one
+func validChecksum(content []byte, expected string) bool {
+	sum := sha256.Sum256(content)
+	return expected != "" && strings.EqualFold(hex.EncodeToString(sum[:]), expected)
+}
+
+// attachmentName extracts the filename from the content disposition header
+func attachmentName(header http.Header) string {
+	_, params, err := mime.ParseMediaType(header.Get("Content-Disposition"))
+	if err != nil {
+		return ""
+	}
+
+	name, err := url.QueryUnescape(params["filename"])
+	if err != nil {
+		return params["filename"]
+	}
+
+	return name
+}
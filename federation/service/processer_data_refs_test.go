@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	cs "github.com/cortezaproject/corteza-server/compose/service"
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testRecordServiceFindByLabel struct {
+		cs.RecordService
+	}
+)
+
+func TestProcesserData_resolveRecordReferences(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+
+		dp = &dataProcesser{
+			ModuleMappings: &types.ModuleFieldMappingSet{
+				{Origin: types.ModuleField{Kind: "Record", Name: "Account"}, Destination: types.ModuleField{Kind: "Record", Name: "Acc"}},
+				{Origin: types.ModuleField{Kind: "Record", Name: "Owner"}, Destination: types.ModuleField{Kind: "Record", Name: "Own"}},
+				{Origin: types.ModuleField{Kind: "String", Name: "Name"}, Destination: types.ModuleField{Kind: "String", Name: "Name"}},
+			},
+			SyncService: NewSync(&Syncer{}, &Mapper{}, &testSharedModuleService{}, &testRecordServiceFindByLabel{}, &testUserService{}, &testRoleService{}),
+			Node:        &types.Node{},
+			module: &ct.Module{Fields: ct.ModuleFieldSet{
+				{Name: "Acc", Kind: "Record", Options: ct.ModuleFieldOptions{"moduleID": "5"}},
+				{Name: "Own", Kind: "Record", Options: ct.ModuleFieldOptions{"moduleID": "5"}},
+				{Name: "Name", Kind: "String"},
+			}},
+		}
+
+		vv = ct.RecordValueSet{
+			&ct.RecordValue{Name: "Acc", Value: "11"},
+			&ct.RecordValue{Name: "Own", Value: "12"},
+			&ct.RecordValue{Name: "Name", Value: "13"},
+		}
+	)
+
+	req.NoError(dp.resolveRecordReferences(ctx, vv))
+
+	// federated copy of the referenced record
+	req.Equal("1011", vv[0].Value)
+
+	// referenced record is not synced, reference is removed
+	req.Equal("", vv[1].Value)
+
+	// not a reference
+	req.Equal("13", vv[2].Value)
+}
+
+func TestSync_fetchAttachment(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		req     = require.New(t)
+		content = []byte("attachment content")
+		sum     = sha256.Sum256(content)
+
+		checksum string
+
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Content-Disposition", "attachment; filename=my+file.txt")
+			w.Header().Set(FederationChecksumHeader, checksum)
+			w.Write(content)
+		}))
+
+		s = NewSync(NewSyncer(), &Mapper{}, &testSharedModuleService{}, &testRecordServicePersistSuccess{}, &testUserService{}, &testRoleService{})
+	)
+
+	defer srv.Close()
+
+	checksum = hex.EncodeToString(sum[:])
+	name, out, err := s.FetchAttachment(ctx, srv.URL, int64(len(content)))
+	req.NoError(err)
+	req.Equal("my file.txt", name)
+	req.Equal(content, out)
+
+	_, _, err = s.FetchAttachment(ctx, srv.URL, int64(len(content)-1))
+	req.EqualError(err, "attachment exceeds max size of 17 bytes")
+
+	checksum = hex.EncodeToString(sum[1:])
+	_, _, err = s.FetchAttachment(ctx, srv.URL, megabyte)
+	req.EqualError(err, "attachment checksum mismatch")
+
+	checksum = ""
+	_, _, err = s.FetchAttachment(ctx, srv.URL, megabyte)
+	req.Error(err)
+}
+
+func TestAttachmentMaxSize(t *testing.T) {
+	var (
+		req = require.New(t)
+		f   = &ct.ModuleField{Options: ct.ModuleFieldOptions{"maxSize": 2}}
+	)
+
+	req.Equal(int64(2*megabyte), attachmentMaxSize(f))
+	req.Equal(int64(attachmentFallbackMaxSize), attachmentMaxSize(&ct.ModuleField{}))
+	req.Equal(int64(attachmentFallbackMaxSize), attachmentMaxSize(nil))
+}
+
+// finds federated copies of the records with external ID 11
+func (s testRecordServiceFindByLabel) Find(_ context.Context, filter ct.RecordFilter) (ct.RecordSet, ct.RecordFilter, error) {
+	if filter.ModuleID == 5 && filter.Labels["federation_extrecord"] == "11" {
+		return ct.RecordSet{&ct.Record{ID: 1011}}, filter, nil
+	}
+
+	return ct.RecordSet{}, filter, nil
+}
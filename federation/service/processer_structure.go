@@ -47,6 +47,7 @@ func (dp *structureProcesser) Process(ctx context.Context, payload []byte) (Proc
 			Fields:                     em.Fields,
 			Handle:                     em.Handle,
 			Name:                       em.Name,
+			Bidirectional:              em.Bidirectional,
 		}
 
 		existing, err := dp.SyncService.LookupSharedModule(ctx, new)
@@ -97,6 +98,7 @@ func (dp *structureProcesser) Process(ctx context.Context, payload []byte) (Proc
 			existing.Name = new.Name
 			existing.Handle = new.Handle
 			existing.Fields = new.Fields
+			existing.Bidirectional = new.Bidirectional
 
 			_, err = dp.SyncService.UpdateSharedModule(ctx, existing)
 
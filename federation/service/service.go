@@ -10,6 +10,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/label"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/store"
@@ -46,6 +47,9 @@ var (
 	DefaultExposedModule ExposedModuleService
 	DefaultSharedModule  SharedModuleService
 	DefaultModuleMapping ModuleMappingService
+	DefaultConflict      ConflictService
+
+	DefaultSync *Sync
 
 	// wrapper around time.Now() that will aid service testing
 	now = func() *time.Time {
@@ -91,14 +95,9 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, c Config)
 	DefaultExposedModule = ExposedModule()
 	DefaultSharedModule = SharedModule()
 	DefaultModuleMapping = ModuleMapping()
+	DefaultConflict = Conflict()
 
-	return
-}
-
-func Watchers(ctx context.Context) {
-	DefaultLogger.Info("Starting federation - warning, this is still an experimental feature")
-
-	syncService := NewSync(
+	DefaultSync = NewSync(
 		&Syncer{},
 		&Mapper{},
 		DefaultSharedModule,
@@ -106,8 +105,21 @@ func Watchers(ctx context.Context) {
 		ss.DefaultUser,
 		ss.DefaultRole)
 
-	syncStructure := WorkerStructure(syncService, DefaultLogger)
-	syncData := WorkerData(syncService, DefaultLogger)
+	return
+}
+
+func Watchers(ctx context.Context) {
+	DefaultLogger.Info("Starting federation - warning, this is still an experimental feature")
+
+	syncStructure := WorkerStructure(DefaultSync, DefaultLogger)
+	syncData := WorkerData(DefaultSync, DefaultLogger)
+	syncPush := WorkerPush(DefaultSync, DefaultLogger, DefaultOptions.DataPush)
+
+	// module mappings are cached; reload them when mappings are changed on other nodes
+	invalidation.Register(ModuleMappingInvalidationKind, func(ctx context.Context) error {
+		DefaultModuleMapping.Reset()
+		return nil
+	})
 
 	go syncStructure.Watch(
 		ctx,
@@ -118,6 +130,10 @@ func Watchers(ctx context.Context) {
 		ctx,
 		DefaultOptions.DataMonitorInterval,
 		DefaultOptions.DataPageSize)
+
+	go syncPush.Watch(
+		ctx,
+		DefaultOptions.DataPushInterval)
 }
 
 func AddFederationLabel(entity label.LabeledResource, key string, value string) {
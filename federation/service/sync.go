@@ -1,16 +1,20 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"time"
 
 	cs "github.com/cortezaproject/corteza-server/compose/service"
 	ct "github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/federation/types"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/decoder"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	ss "github.com/cortezaproject/corteza-server/system/service"
 	st "github.com/cortezaproject/corteza-server/system/types"
@@ -78,6 +82,79 @@ func (s *Sync) FetchUrl(ctx context.Context, url string) (io.Reader, error) {
 	return s.syncer.Fetch(ctx, url)
 }
 
+// PushUrl passes the payload to be sent to the syncer
+func (s *Sync) PushUrl(ctx context.Context, url string, payload interface{}, out interface{}) error {
+	return s.syncer.Send(ctx, url, payload, out)
+}
+
+// ReceivePushed processes record changes that were pushed from the origin node
+// in the same way as the records fetched with the periodic data sync
+func (s *Sync) ReceivePushed(ctx context.Context, sharedNodeID, moduleID uint64, changes types.RecordChangeSet) (int, error) {
+	var (
+		n   *types.Node
+		sm  *types.SharedModule
+		mm  *types.ModuleMapping
+		err error
+	)
+
+	if n, err = DefaultNode.FindBySharedNodeID(ctx, sharedNodeID); err != nil {
+		return 0, err
+	}
+
+	sm, err = s.LookupSharedModule(ctx, &types.SharedModule{NodeID: n.ID, ExternalFederationModuleID: moduleID})
+	if err != nil {
+		return 0, err
+	} else if sm == nil {
+		return 0, SharedModuleErrNotFound()
+	}
+
+	if mm, _ = s.GetModuleMappings(ctx, sm.ID); mm == nil {
+		// module is not mapped (yet), nothing to do
+		return 0, nil
+	}
+
+	mappingValues, err := s.PrepareModuleMappings(ctx, mm)
+	if err != nil {
+		return 0, err
+	}
+
+	processer := &dataProcesser{
+		ID:                  sm.ExternalFederationModuleID,
+		ComposeModuleID:     mm.ComposeModuleID,
+		ComposeNamespaceID:  mm.ComposeNamespaceID,
+		NodeBaseURL:         n.BaseURL,
+		ModuleMappings:      &mm.FieldMapping,
+		ModuleMappingValues: &mappingValues,
+		SyncService:         s,
+		Node:                n,
+		FollowReferences:    DefaultOptions.DataFollowReferences,
+		log:                 DefaultLogger,
+	}
+
+	rr := make([]*decoder.ExposedRecord, len(changes))
+	for i, c := range changes {
+		updatedAt := c.UpdatedAt
+
+		rr[i] = &decoder.ExposedRecord{
+			ID:        c.RecordID,
+			Values:    c.Values,
+			CreatedAt: c.UpdatedAt,
+			UpdatedAt: &updatedAt,
+		}
+
+		if c.Deleted {
+			rr[i].DeletedAt = &updatedAt
+		}
+	}
+
+	rsp, err := processer.processRecords(ctx, rr)
+	if err != nil {
+		return 0, err
+	}
+
+	return rsp.(dataProcesserResponse).Processed, nil
+}
+
 // CreateRecord wraps the compose Record service Create
 func (s *Sync) CreateRecord(ctx context.Context, rec *ct.Record) (*ct.Record, error) {
 	return s.composeRecordService.Create(ctx, rec)
@@ -99,6 +176,49 @@ func (s *Sync) FindRecords(ctx context.Context, filter ct.RecordFilter) (set ct.
 	return
 }
 
+// FindComposeModule wraps the compose Module service FindByID
+func (s *Sync) FindComposeModule(ctx context.Context, namespaceID, moduleID uint64) (*ct.Module, error) {
+	return cs.DefaultModule.FindByID(ctx, namespaceID, moduleID)
+}
+
+// FindAttachment wraps the compose Attachment service FindByID
+func (s *Sync) FindAttachment(ctx context.Context, namespaceID, attachmentID uint64) (*ct.Attachment, error) {
+	return cs.DefaultAttachment.FindByID(ctx, namespaceID, attachmentID)
+}
+
+// CreateAttachment stores the content as an attachment of the record field
+func (s *Sync) CreateAttachment(ctx context.Context, rec *ct.Record, fieldName, name string, content []byte) (*ct.Attachment, error) {
+	return cs.DefaultAttachment.CreateRecordAttachment(ctx, rec.NamespaceID, name, int64(len(content)), bytes.NewReader(content), rec.ModuleID, rec.ID, fieldName)
+}
+
+// FetchAttachment downloads the attachment from the url
+// and validates the checksum of the content
+//
+// Content larger than maxSize (in bytes) is not read
+func (s *Sync) FetchAttachment(ctx context.Context, url string, maxSize int64) (name string, content []byte, err error) {
+	body, header, err := s.syncer.Download(ctx, url)
+	if err != nil {
+		return "", nil, err
+	}
+
+	defer body.Close()
+
+	// read one byte over the limit to tell if content is too large
+	if content, err = ioutil.ReadAll(io.LimitReader(body, maxSize+1)); err != nil {
+		return "", nil, err
+	}
+
+	if int64(len(content)) > maxSize {
+		return "", nil, fmt.Errorf("attachment exceeds max size of %d bytes", maxSize)
+	}
+
+	if !validChecksum(content, header.Get(FederationChecksumHeader)) {
+		return "", nil, errors.New("attachment checksum mismatch")
+	}
+
+	return attachmentName(header), content, nil
+}
+
 // LookupSharedModule find the shared module if exists
 func (s *Sync) LookupSharedModule(ctx context.Context, new *types.SharedModule) (*types.SharedModule, error) {
 	var sm *types.SharedModule
@@ -160,6 +280,26 @@ func (s *Sync) GetModuleMappings(ctx context.Context, moduleID uint64) (out *typ
 	return
 }
 
+// FindNodeModuleMapping finds the mapping of the module, shared by the node,
+// to the compose module
+func (s *Sync) FindNodeModuleMapping(ctx context.Context, nodeID, composeModuleID uint64) (*types.ModuleMapping, *types.SharedModule, error) {
+	set, _, err := DefaultModuleMapping.Find(ctx, types.ModuleMappingFilter{ComposeModuleID: composeModuleID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, mm := range set {
+		sm, err := DefaultSharedModule.FindByID(ctx, nodeID, mm.FederationModuleID)
+		if err != nil || sm.NodeID != nodeID || sm.DeletedAt != nil {
+			continue
+		}
+
+		return mm, sm, nil
+	}
+
+	return nil, nil, ModuleMappingErrNotFound()
+}
+
 func (s *Sync) PrepareModuleMappings(ctx context.Context, mappings *types.ModuleMapping) (ct.RecordValueSet, error) {
 	return s.mapper.Prepare((*mappings).FieldMapping), nil
 }
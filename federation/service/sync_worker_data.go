@@ -8,6 +8,7 @@ import (
 
 	"github.com/cortezaproject/corteza-server/federation/types"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +30,10 @@ func WorkerData(sync *Sync, logger *zap.Logger) *syncWorkerData {
 	}
 }
 
+const (
+	syncDataLease = "federation.sync.data"
+)
+
 func (w *syncWorkerData) queueUrl(url *types.SyncerURI, urls chan Url, meta Processer) {
 	t := Url{
 		Url:  *url,
@@ -136,11 +141,14 @@ func (w *syncWorkerData) PrepareForNodes(ctx context.Context, urls chan Url) {
 				ID:                  sm.ExternalFederationModuleID,
 				ComposeModuleID:     mappings.ComposeModuleID,
 				ComposeNamespaceID:  mappings.ComposeNamespaceID,
+				NodeBaseURL:         n.BaseURL,
 				ModuleMappings:      &mappings.FieldMapping,
 				ModuleMappingValues: &mappingValues,
 				SyncService:         w.syncService,
 				User:                u,
 				Node:                n,
+				FollowReferences:    DefaultOptions.DataFollowReferences,
+				log:                 w.logger,
 			}
 
 			go w.queueUrl(&url, urls, processer)
@@ -167,7 +175,12 @@ func (w *syncWorkerData) Watch(ctx context.Context, delay time.Duration, limit i
 
 	ticker := time.NewTicker(delay)
 
-	w.PrepareForNodes(ctx, urls)
+	// only one node in the cluster syncs the data
+	cluster.Register(ctx, syncDataLease)
+
+	if cluster.Holds(syncDataLease) {
+		w.PrepareForNodes(ctx, urls)
+	}
 
 	for {
 		select {
@@ -176,6 +189,10 @@ func (w *syncWorkerData) Watch(ctx context.Context, delay time.Duration, limit i
 			w.logger.Info("stopping sync", zap.Int("processed", countProcess))
 			return
 		case <-ticker.C:
+			if !cluster.Holds(syncDataLease) {
+				continue
+			}
+
 			// do the whole process again
 			w.PrepareForNodes(ctx, urls)
 		case url := <-urls:
@@ -0,0 +1,563 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/label"
+	"github.com/cortezaproject/corteza-server/store"
+	"go.uber.org/zap"
+)
+
+type (
+	// syncWorkerPush sends record changes to the paired nodes as they happen
+	//
+	// Changes of exposed modules are pushed to the nodes that share them (when enabled),
+	// changes of federated records in bidirectional shared modules are sent back to the origin node.
+	//
+	// Changes sent back to the origin are stored as pending changes and
+	// removed only after the origin node receives them; periodic data sync
+	// only pulls changes so nothing else would pick them up.
+	syncWorkerPush struct {
+		syncService *Sync
+		store       store.Storer
+		logger      *zap.Logger
+
+		// only changes of mapped modules are stored as pending
+		mappings interface {
+			IsMapped(ctx context.Context, composeModuleID uint64) (bool, error)
+		}
+
+		// push changes of exposed modules to the paired nodes
+		downstream bool
+
+		changes chan *pushChange
+	}
+
+	pushChange struct {
+		record  *ct.Record
+		deleted bool
+
+		// node the change was received from
+		sourceNodeID uint64
+
+		// change was made by the sync itself
+		synced bool
+	}
+
+	pushPayload struct {
+		Changes types.RecordChangeSet `json:"changes"`
+	}
+
+	pushChangesResponse struct {
+		Response []*types.RecordChangeResult `json:"response"`
+	}
+
+	recordEvent interface {
+		Record() *ct.Record
+		OldRecord() *ct.Record
+	}
+)
+
+const (
+	// changes of exposed modules that do not fit into the queue
+	// are picked up by the periodic data sync of the paired nodes
+	pushQueueSize = 1000
+
+	// max number of pending changes sent in one go
+	pushPendingBatchSize = 500
+
+	// cluster lease that guards pushing of pending changes
+	pushPendingLease = "federation.push.pending"
+
+	// delay before the first retry of the failed pending change;
+	// it doubles with each failed attempt up to pushPendingMaxBackoff
+	pushPendingBackoff    = time.Second * 30
+	pushPendingMaxBackoff = time.Hour * 6
+
+	// pending changes that failed this many times are no longer pushed;
+	// they are kept (with the last error) for inspection
+	pushPendingMaxAttempts = 10
+)
+
+func WorkerPush(sync *Sync, logger *zap.Logger, downstream bool) *syncWorkerPush {
+	return &syncWorkerPush{
+		syncService: sync,
+		store:       DefaultStore,
+		logger:      logger,
+		mappings:    DefaultModuleMapping,
+		downstream:  downstream,
+		changes:     make(chan *pushChange, pushQueueSize),
+	}
+}
+
+// Watch collects record changes and pushes them in batches
+//
+// Each instance in the cluster pushes changes of exposed modules made on that instance,
+// pending changes of federated records are pushed by one instance only.
+func (w *syncWorkerPush) Watch(ctx context.Context, delay time.Duration) {
+	var (
+		batch []*pushChange
+	)
+
+	ptr := eventbus.Service().Register(
+		w.handle,
+		eventbus.For("compose:record"),
+		eventbus.On("afterCreate", "afterUpdate", "afterDelete"),
+	)
+
+	defer eventbus.Service().Unregister(ptr)
+
+	ctx = auth.SetIdentityToContext(ctx, auth.FederationUser())
+
+	cluster.Register(ctx, pushPendingLease)
+
+	if delay <= 0 {
+		delay = time.Second
+	}
+
+	ticker := time.NewTicker(delay)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info("stopping push", zap.Int("pending", len(batch)))
+			return
+		case c := <-w.changes:
+			batch = append(batch, c)
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(ctx, batch)
+				batch = nil
+			}
+
+			if cluster.Holds(pushPendingLease) {
+				w.pushPending(ctx)
+			}
+		}
+	}
+}
+
+// handle queues the record change
+func (w *syncWorkerPush) handle(ctx context.Context, ev eventbus.Event) error {
+	re, ok := ev.(recordEvent)
+	if !ok {
+		return nil
+	}
+
+	c := &pushChange{
+		record:       re.Record(),
+		deleted:      ev.EventType() == "afterDelete",
+		sourceNodeID: sourceNode(ctx),
+	}
+
+	if c.deleted {
+		c.record = re.OldRecord()
+	}
+
+	if c.record == nil {
+		return nil
+	}
+
+	c.synced = c.sourceNodeID == 0 && isFederationUser(auth.GetIdentityFromContext(ctx).Identity())
+
+	if !c.synced && c.sourceNodeID == 0 {
+		if err := w.storePending(ctx, c.record); err != nil {
+			w.logger.Error("could not store pending record change",
+				zap.Uint64("recordID", c.record.ID),
+				zap.Error(err))
+		}
+	}
+
+	if !w.downstream {
+		return nil
+	}
+
+	c.record = c.record.Clone()
+
+	select {
+	case w.changes <- c:
+	default:
+		w.logger.Warn("push queue is full, paired nodes will pick up the record change with their periodic sync",
+			zap.Uint64("recordID", c.record.ID))
+	}
+
+	return nil
+}
+
+// storePending stores the change of the record in the module
+// that is mapped to a shared module so it can be sent to the origin node
+//
+// The latest version of the record is loaded when the change is pushed.
+func (w *syncWorkerPush) storePending(ctx context.Context, r *ct.Record) error {
+	mapped, err := w.mappings.IsMapped(ctx, r.ModuleID)
+	if err != nil || !mapped {
+		return err
+	}
+
+	return store.CreateFederationPendingChange(ctx, w.store, &types.PendingChange{
+		ID:              nextID(),
+		ComposeModuleID: r.ModuleID,
+		ComposeRecordID: r.ID,
+		CreatedAt:       *now(),
+	})
+}
+
+func (w *syncWorkerPush) flush(ctx context.Context, cc []*pushChange) {
+	var (
+		modules  []uint64
+		byModule = make(map[uint64][]*pushChange)
+	)
+
+	for _, c := range cc {
+		if _, has := byModule[c.record.ModuleID]; !has {
+			modules = append(modules, c.record.ModuleID)
+		}
+
+		byModule[c.record.ModuleID] = append(byModule[c.record.ModuleID], c)
+	}
+
+	for _, moduleID := range modules {
+		w.pushExposed(ctx, moduleID, byModule[moduleID])
+	}
+}
+
+// pushExposed sends changes of the exposed module records to the nodes that share the module
+func (w *syncWorkerPush) pushExposed(ctx context.Context, composeModuleID uint64, cc []*pushChange) {
+	set, _, err := store.SearchFederationExposedModules(ctx, w.store, types.ExposedModuleFilter{ComposeModuleID: composeModuleID})
+	if err != nil {
+		w.logger.Info("could not get exposed modules", zap.Uint64("composeModuleID", composeModuleID), zap.Error(err))
+		return
+	}
+
+	for _, em := range set {
+		if em.DeletedAt != nil {
+			continue
+		}
+
+		n := w.pairedNode(ctx, em.NodeID)
+		if n == nil {
+			continue
+		}
+
+		changes := exposedChanges(em, n.ID, cc)
+		if len(changes) == 0 {
+			continue
+		}
+
+		url := fmt.Sprintf("%s/nodes/%d/modules/%d/records/push", n.BaseURL, n.SharedNodeID, em.ID)
+		_ = w.send(ctx, n, url, changes, nil)
+	}
+}
+
+// pushPending sends pending changes of federated records in
+// bidirectional shared modules back to the origin node
+//
+// Pending changes are removed once the origin node receives them,
+// failed attempts are recorded and retried with exponential backoff
+// until pushPendingMaxAttempts is reached.
+//
+// Changes with fewer attempts are pushed first so that changes failing
+// over and over again do not hold back the new ones.
+func (w *syncWorkerPush) pushPending(ctx context.Context) {
+	var (
+		at = *now()
+	)
+
+	f := types.PendingChangeFilter{
+		MaxAttempts: pushPendingMaxAttempts,
+		Check: func(p *types.PendingChange) (bool, error) {
+			return pendingDue(p, at), nil
+		},
+		Sorting: filter.Sorting{
+			Sort: filter.SortExprSet{
+				&filter.SortExpr{Column: "attempts"},
+				&filter.SortExpr{Column: "id"},
+			},
+		},
+	}
+
+	f.Limit = pushPendingBatchSize
+
+	set, _, err := store.SearchFederationPendingChanges(ctx, w.store, f)
+	if err != nil {
+		w.logger.Error("could not get pending record changes", zap.Error(err))
+		return
+	}
+
+	var (
+		modules  []uint64
+		byModule = make(map[uint64]types.PendingChangeSet)
+	)
+
+	for _, p := range set {
+		if _, has := byModule[p.ComposeModuleID]; !has {
+			modules = append(modules, p.ComposeModuleID)
+		}
+
+		byModule[p.ComposeModuleID] = append(byModule[p.ComposeModuleID], p)
+	}
+
+	for _, moduleID := range modules {
+		w.pushShared(ctx, moduleID, byModule[moduleID])
+	}
+}
+
+// pushShared sends pending changes of the module's records to
+// the origin nodes of all bidirectional shared modules it is mapped to
+func (w *syncWorkerPush) pushShared(ctx context.Context, composeModuleID uint64, pp types.PendingChangeSet) {
+	var (
+		cc     []*pushChange
+		failed error
+	)
+
+	m, err := store.LookupComposeModuleByID(ctx, w.store, composeModuleID)
+	if err == nil {
+		m.Fields, _, err = store.SearchComposeModuleFields(ctx, w.store, ct.ModuleFieldFilter{ModuleID: []uint64{m.ID}})
+	}
+
+	if err != nil {
+		// module is gone, nothing to push
+		w.removePending(ctx, pp...)
+		return
+	}
+
+	set, _, err := store.SearchFederationModuleMappings(ctx, w.store, types.ModuleMappingFilter{ComposeModuleID: composeModuleID})
+	if err != nil {
+		w.logger.Info("could not get module mappings", zap.Uint64("composeModuleID", composeModuleID), zap.Error(err))
+		return
+	}
+
+	loaded := make(map[uint64]bool)
+	for _, p := range pp {
+		// record can have more than one pending change
+		if loaded[p.ComposeRecordID] {
+			continue
+		}
+
+		loaded[p.ComposeRecordID] = true
+
+		r, err := store.LookupComposeRecordByID(ctx, w.store, m, p.ComposeRecordID)
+		if err != nil {
+			continue
+		}
+
+		if err = label.Load(ctx, w.store, r); err != nil {
+			continue
+		}
+
+		cc = append(cc, &pushChange{record: r, deleted: r.DeletedAt != nil})
+	}
+
+	for _, mm := range set {
+		sm, err := store.LookupFederationSharedModuleByID(ctx, w.store, mm.FederationModuleID)
+		if err != nil || !sm.Bidirectional {
+			continue
+		}
+
+		n := w.pairedNode(ctx, sm.NodeID)
+		if n == nil {
+			continue
+		}
+
+		var (
+			changes = types.RecordChangeSet{}
+			records = make(map[uint64]*ct.Record)
+		)
+
+		for _, c := range cc {
+			change := sharedChange(n, c)
+			if change == nil {
+				continue
+			}
+
+			change.Values = w.syncService.mapper.Reverse(change.Values, mm.FieldMapping)
+			records[change.RecordID] = c.record
+			changes = append(changes, change)
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		var (
+			url = fmt.Sprintf("%s/nodes/%d/modules/%d/records/changes", n.BaseURL, n.SharedNodeID, sm.ExternalFederationModuleID)
+			rsp = &pushChangesResponse{}
+		)
+
+		if err = w.send(ctx, n, url, changes, rsp); err != nil {
+			failed = err
+			continue
+		}
+
+		// records that were applied on the origin are now
+		// based on the latest version of the origin record
+		for _, r := range rsp.Response {
+			rec := records[r.RecordID]
+			if rec == nil || rec.DeletedAt != nil || r.Status != types.RecordChangeStatusApplied || r.UpdatedAt == nil {
+				continue
+			}
+
+			AddFederationLabel(rec, federationLabelExtUpdated, r.UpdatedAt.Format(time.RFC3339Nano))
+			if err = label.Update(ctx, w.store, rec); err != nil {
+				w.logger.Info("could not update record labels", zap.Uint64("recordID", rec.ID), zap.Error(err))
+			}
+		}
+	}
+
+	if failed == nil {
+		w.removePending(ctx, pp...)
+		return
+	}
+
+	for _, p := range pp {
+		p.Attempts++
+		p.Error = failed.Error()
+		p.LastAttemptAt = now()
+
+		if p.Attempts >= pushPendingMaxAttempts {
+			w.logger.Warn("giving up on pending record change",
+				zap.Uint64("recordID", p.ComposeRecordID),
+				zap.Uint("attempts", p.Attempts),
+				zap.String("error", p.Error))
+		}
+	}
+
+	if err = store.UpdateFederationPendingChange(ctx, w.store, pp...); err != nil {
+		w.logger.Error("could not update pending record changes", zap.Error(err))
+	}
+}
+
+// pendingDue checks if backoff period of the failed pending change has passed
+func pendingDue(p *types.PendingChange, at time.Time) bool {
+	if p.Attempts == 0 || p.LastAttemptAt == nil {
+		return true
+	}
+
+	delay := pushPendingBackoff
+	for i := uint(1); i < p.Attempts && delay < pushPendingMaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > pushPendingMaxBackoff {
+		delay = pushPendingMaxBackoff
+	}
+
+	return !at.Before(p.LastAttemptAt.Add(delay))
+}
+
+func (w *syncWorkerPush) removePending(ctx context.Context, pp ...*types.PendingChange) {
+	if err := store.DeleteFederationPendingChange(ctx, w.store, pp...); err != nil {
+		w.logger.Error("could not remove pending record changes", zap.Error(err))
+	}
+}
+
+func (w *syncWorkerPush) pairedNode(ctx context.Context, nodeID uint64) *types.Node {
+	n, err := store.LookupFederationNodeByID(ctx, w.store, nodeID)
+	if err != nil || n.Status != types.NodeStatusPaired {
+		return nil
+	}
+
+	return n
+}
+
+func (w *syncWorkerPush) send(ctx context.Context, n *types.Node, url string, changes types.RecordChangeSet, out interface{}) error {
+	// use the authToken from node pairing
+	ctx = context.WithValue(ctx, FederationUserToken, n.AuthToken)
+
+	if err := w.syncService.PushUrl(ctx, url, pushPayload{Changes: changes}, out); err != nil {
+		w.logger.Error("could not push record changes",
+			zap.Error(err),
+			zap.String("url", url),
+			zap.Uint64("nodeID", n.ID),
+			zap.String("host", n.BaseURL))
+
+		return err
+	}
+
+	w.logger.Info("pushed record changes",
+		zap.Int("changes", len(changes)),
+		zap.Uint64("nodeID", n.ID))
+
+	return nil
+}
+
+// isFederationUser checks if the user is the federation system user
+func isFederationUser(userID uint64) bool {
+	u := auth.FederationUser()
+	return u != nil && u.ID == userID
+}
+
+// exposedChanges converts record changes to the changes that are pushed to the node
+//
+// Only exposed values are sent; changes made by the sync and changes received
+// from the node itself are skipped.
+func exposedChanges(em *types.ExposedModule, nodeID uint64, cc []*pushChange) (out types.RecordChangeSet) {
+	var (
+		index = make(map[uint64]int)
+	)
+
+	for _, c := range cc {
+		if c.synced || c.sourceNodeID == nodeID || isFederationUser(c.record.CreatedBy) {
+			continue
+		}
+
+		change := &types.RecordChange{
+			RecordID:  c.record.ID,
+			Values:    exposedValues(em, c.record.Values),
+			Deleted:   c.deleted,
+			UpdatedAt: *lastUpdate(c.record),
+		}
+
+		// only the last change of the record is pushed
+		if i, has := index[change.RecordID]; has {
+			out[i] = change
+			continue
+		}
+
+		index[change.RecordID] = len(out)
+		out = append(out, change)
+	}
+
+	return
+}
+
+// sharedChange converts change of the federated record to the change sent to the origin node
+//
+// It returns nil for records that were not received from the node.
+func sharedChange(n *types.Node, c *pushChange) *types.RecordChange {
+	var (
+		labels   = c.record.GetLabels()
+		extID, _ = strconv.ParseUint(labels["federation_extrecord"], 10, 64)
+	)
+
+	if extID == 0 {
+		return nil
+	}
+
+	if origin := labels["federation"]; origin != "" && origin != n.BaseURL {
+		return nil
+	}
+
+	change := &types.RecordChange{
+		RecordID:  extID,
+		Values:    c.record.Values,
+		Deleted:   c.deleted,
+		UpdatedAt: *lastUpdate(c.record),
+	}
+
+	if base, err := time.Parse(time.RFC3339Nano, labels[federationLabelExtUpdated]); err == nil {
+		change.BaseUpdatedAt = &base
+	}
+
+	return change
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPush_exposedChanges(t *testing.T) {
+	var (
+		req = require.New(t)
+		t0  = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+		t1  = t0.Add(time.Minute)
+
+		em = &types.ExposedModule{Fields: types.ModuleFieldSet{{Name: "Name"}}}
+
+		values = func(v string) ct.RecordValueSet {
+			return ct.RecordValueSet{&ct.RecordValue{Name: "Name", Value: v}, &ct.RecordValue{Name: "Internal", Value: "secret"}}
+		}
+
+		cc = []*pushChange{
+			{record: &ct.Record{ID: 1, CreatedAt: t0, Values: values("first")}},
+			{record: &ct.Record{ID: 2, CreatedAt: t0, Values: values("synced")}, synced: true},
+			{record: &ct.Record{ID: 3, CreatedAt: t0, Values: values("from node")}, sourceNodeID: 42},
+			{record: &ct.Record{ID: 1, CreatedAt: t0, UpdatedAt: &t1, Values: values("second")}},
+			{record: &ct.Record{ID: 4, CreatedAt: t0, DeletedAt: &t1}, deleted: true},
+		}
+	)
+
+	out := exposedChanges(em, 42, cc)
+	req.Len(out, 2)
+
+	req.Equal(uint64(1), out[0].RecordID)
+	req.Equal(t1, out[0].UpdatedAt)
+	req.Equal(ct.RecordValueSet{&ct.RecordValue{Name: "Name", Value: "second"}}, out[0].Values)
+
+	req.Equal(uint64(4), out[1].RecordID)
+	req.True(out[1].Deleted)
+
+	// changes received from other nodes are pushed
+	req.Len(exposedChanges(em, 1, cc), 3)
+}
+
+func TestWorkerPush_sharedChange(t *testing.T) {
+	var (
+		req = require.New(t)
+		t0  = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+		t1  = t0.Add(time.Minute)
+
+		n = &types.Node{ID: 1, BaseURL: "https://origin.tld"}
+
+		change = func(labels map[string]string) *pushChange {
+			return &pushChange{record: &ct.Record{ID: 10, CreatedAt: t0, UpdatedAt: &t1, Labels: labels}}
+		}
+	)
+
+	req.Nil(sharedChange(n, change(nil)), "local record")
+	req.Nil(sharedChange(n, change(map[string]string{
+		"federation":           "https://other.tld",
+		"federation_extrecord": "20",
+	})), "record from other node")
+
+	c := sharedChange(n, change(map[string]string{
+		"federation":              "https://origin.tld",
+		"federation_extrecord":    "20",
+		federationLabelExtUpdated: t0.Format(time.RFC3339Nano),
+	}))
+
+	req.NotNil(c)
+	req.Equal(uint64(20), c.RecordID)
+	req.Equal(t1, c.UpdatedAt)
+	req.NotNil(c.BaseUpdatedAt)
+	req.True(t0.Equal(*c.BaseUpdatedAt))
+}
+
+func TestWorkerPush_pendingDue(t *testing.T) {
+	var (
+		req = require.New(t)
+		t0  = time.Date(2021, 1, 1, 10, 0, 0, 0, time.UTC)
+
+		pending = func(attempts uint) *types.PendingChange {
+			return &types.PendingChange{Attempts: attempts, LastAttemptAt: &t0}
+		}
+	)
+
+	req.True(pendingDue(&types.PendingChange{}, t0), "never attempted")
+
+	req.False(pendingDue(pending(1), t0.Add(pushPendingBackoff-time.Second)))
+	req.True(pendingDue(pending(1), t0.Add(pushPendingBackoff)))
+
+	// backoff doubles with each attempt
+	req.False(pendingDue(pending(3), t0.Add(pushPendingBackoff*3)))
+	req.True(pendingDue(pending(3), t0.Add(pushPendingBackoff*4)))
+
+	// up to the max backoff
+	req.False(pendingDue(pending(20), t0.Add(pushPendingMaxBackoff-time.Second)))
+	req.True(pendingDue(pending(20), t0.Add(pushPendingMaxBackoff)))
+}
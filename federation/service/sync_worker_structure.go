@@ -8,6 +8,7 @@ import (
 
 	"github.com/cortezaproject/corteza-server/federation/types"
 	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
 	"go.uber.org/zap"
 )
 
@@ -29,6 +30,10 @@ func WorkerStructure(sync *Sync, logger *zap.Logger) *syncWorkerStructure {
 	}
 }
 
+const (
+	syncStructureLease = "federation.sync.structure"
+)
+
 func (w *syncWorkerStructure) queueUrl(url *types.SyncerURI, urls chan Url, meta Processer) {
 	t := Url{
 		Url:  *url,
@@ -110,7 +115,12 @@ func (w *syncWorkerStructure) Watch(ctx context.Context, delay time.Duration, li
 
 	ticker := time.NewTicker(delay)
 
-	w.PrepareForNodes(ctx, urls)
+	// only one node in the cluster syncs the structure
+	cluster.Register(ctx, syncStructureLease)
+
+	if cluster.Holds(syncStructureLease) {
+		w.PrepareForNodes(ctx, urls)
+	}
 
 	for {
 		select {
@@ -119,6 +129,10 @@ func (w *syncWorkerStructure) Watch(ctx context.Context, delay time.Duration, li
 			w.logger.Info("stopping sync", zap.Int("processed", countProcess))
 			return
 		case <-ticker.C:
+			if !cluster.Holds(syncStructureLease) {
+				continue
+			}
+
 			// do the whole process again
 			w.PrepareForNodes(ctx, urls)
 		case url := <-urls:
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -68,6 +69,66 @@ func (h *Syncer) Fetch(ctx context.Context, url string) (io.Reader, error) {
 	return resp.Body, nil
 }
 
+// Download fetches the file from the url, caller is responsible for closing the body
+func (h *Syncer) Download(ctx context.Context, url string) (io.ReadCloser, http.Header, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if authToken := ctx.Value(FederationUserToken); authToken != nil {
+		req.Header.Add("Authorization", `Bearer `+authToken.(string))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, nil, errors.New(fmt.Sprintf("invalid return status: %d", resp.StatusCode))
+	}
+
+	return resp.Body, resp.Header, nil
+}
+
+// Send posts JSON payload to the url and decodes the response into out (when not nil)
+func (h *Syncer) Send(ctx context.Context, url string, payload interface{}, out interface{}) error {
+	body := &bytes.Buffer{}
+	if err := json.NewEncoder(body).Encode(payload); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if authToken := ctx.Value(FederationUserToken); authToken != nil {
+		req.Header.Add("Authorization", `Bearer `+authToken.(string))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return errors.New(fmt.Sprintf("invalid return status: %d", resp.StatusCode))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
 func (h *Syncer) Process(ctx context.Context, payload []byte, out chan Url, url types.SyncerURI, processer Processer) (ProcesserResponse, error) {
 	aux, err := h.ParseHeader(ctx, payload)
 
@@ -0,0 +1,109 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+const (
+	// ConflictPolicyLastWriterWins keeps the change with the most recent update time
+	ConflictPolicyLastWriterWins = "lastWriterWins"
+
+	// ConflictPolicyOriginWins keeps the record on the origin node and drops remote changes
+	ConflictPolicyOriginWins = "originWins"
+
+	// ConflictPolicyManual keeps the record as it is and flags the conflict for manual resolution
+	ConflictPolicyManual = "manual"
+
+	ConflictStatusPending  = "pending"
+	ConflictStatusResolved = "resolved"
+
+	ConflictResolutionLocal  = "local"
+	ConflictResolutionRemote = "remote"
+)
+
+type (
+	// Conflict is recorded on the origin node when a record of a bidirectionally
+	// shared module is changed on a remote node and on the origin at the same time
+	Conflict struct {
+		ID                 uint64 `json:"conflictID,string"`
+		NodeID             uint64 `json:"nodeID,string"`
+		ModuleID           uint64 `json:"moduleID,string"`
+		ComposeNamespaceID uint64 `json:"composeNamespaceID,string"`
+		ComposeModuleID    uint64 `json:"composeModuleID,string"`
+		ComposeRecordID    uint64 `json:"composeRecordID,string"`
+
+		Policy     string `json:"policy"`
+		Status     string `json:"status"`
+		Resolution string `json:"resolution,omitempty"`
+
+		LocalValues    ConflictValueSet `json:"localValues"`
+		LocalUpdatedAt *time.Time       `json:"localUpdatedAt,omitempty"`
+
+		RemoteValues    ConflictValueSet `json:"remoteValues"`
+		RemoteDeleted   bool             `json:"remoteDeleted"`
+		RemoteUpdatedAt *time.Time       `json:"remoteUpdatedAt,omitempty"`
+
+		CreatedAt  time.Time  `json:"createdAt,omitempty"`
+		ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+		ResolvedBy uint64     `json:"resolvedBy,string,omitempty"`
+	}
+
+	ConflictFilter struct {
+		NodeID          uint64 `json:"nodeID,string"`
+		ModuleID        uint64 `json:"moduleID,string"`
+		ComposeRecordID uint64 `json:"composeRecordID,string"`
+		Status          string `json:"status"`
+
+		Check func(*Conflict) (bool, error) `json:"-"`
+
+		filter.Sorting
+		filter.Paging
+	}
+
+	ConflictValueSet ct.RecordValueSet
+)
+
+// IsPending returns true when conflict still needs to be resolved
+func (c Conflict) IsPending() bool {
+	return c.Status == ConflictStatusPending
+}
+
+// ValidConflictPolicy checks if policy is one of the supported conflict policies
+//
+// Empty policy is valid and is handled as last-writer-wins
+func ValidConflictPolicy(policy string) bool {
+	switch policy {
+	case "", ConflictPolicyLastWriterWins, ConflictPolicyOriginWins, ConflictPolicyManual:
+		return true
+	}
+
+	return false
+}
+
+func (set ConflictValueSet) Value() (driver.Value, error) {
+	return json.Marshal(set)
+}
+
+func (set *ConflictValueSet) Scan(value interface{}) error {
+	switch value.(type) {
+	case nil:
+		*set = ConflictValueSet{}
+	case []uint8:
+		if err := json.Unmarshal(value.([]byte), set); err != nil {
+			return errors.New(fmt.Sprintf("cannot scan '%v' into ConflictValueSet", value))
+		}
+	case string:
+		if err := json.Unmarshal([]byte(value.(string)), set); err != nil {
+			return errors.New(fmt.Sprintf("cannot scan '%v' into ConflictValueSet", value))
+		}
+	}
+
+	return nil
+}
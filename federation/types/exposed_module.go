@@ -16,6 +16,11 @@ type (
 		Name               string         `json:"name"`
 		Fields             ModuleFieldSet `json:"fields"`
 
+		// Bidirectional modules accept record changes from the paired nodes;
+		// conflicting changes are handled with the conflict policy
+		Bidirectional  bool   `json:"bidirectional,omitempty"`
+		ConflictPolicy string `json:"conflictPolicy,omitempty"`
+
 		CreatedAt time.Time  `json:"createdAt,omitempty"`
 		CreatedBy uint64     `json:"createdBy,string" `
 		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// PendingChange is a local change of the federated record that
+	// still needs to be sent back to the origin node
+	//
+	// Pending changes are stored when the record is changed and removed
+	// once the origin node receives the change; failed pushes are retried.
+	PendingChange struct {
+		ID              uint64 `json:"pendingChangeID,string"`
+		ComposeModuleID uint64 `json:"composeModuleID,string"`
+		ComposeRecordID uint64 `json:"composeRecordID,string"`
+
+		Attempts uint   `json:"attempts"`
+		Error    string `json:"error,omitempty"`
+
+		CreatedAt     time.Time  `json:"createdAt,omitempty"`
+		LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+	}
+
+	PendingChangeFilter struct {
+		ComposeModuleID uint64 `json:"composeModuleID,string"`
+		ComposeRecordID uint64 `json:"composeRecordID,string"`
+
+		// Only changes with fewer failed attempts
+		MaxAttempts uint `json:"maxAttempts,omitempty"`
+
+		Check func(*PendingChange) (bool, error) `json:"-"`
+
+		filter.Sorting
+		filter.Paging
+	}
+)
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"time"
+
+	ct "github.com/cortezaproject/corteza-server/compose/types"
+)
+
+const (
+	RecordChangeStatusApplied  = "applied"
+	RecordChangeStatusRejected = "rejected"
+	RecordChangeStatusConflict = "conflict"
+)
+
+type (
+	// RecordChange is sent from a remote node to the origin node
+	// when a record of a bidirectionally shared module is changed on the remote node
+	RecordChange struct {
+		// ID of the record on the origin node
+		RecordID uint64 `json:"recordID,string"`
+
+		// Values, mapped to the names of the exposed fields
+		Values  ct.RecordValueSet `json:"values,omitempty"`
+		Deleted bool              `json:"deleted,omitempty"`
+
+		// Time of the change on the remote node
+		UpdatedAt time.Time `json:"updatedAt"`
+
+		// Time of the last update on the origin node that the remote node knows about
+		BaseUpdatedAt *time.Time `json:"baseUpdatedAt,omitempty"`
+	}
+
+	RecordChangeSet []*RecordChange
+
+	// RecordChangeResult is returned from the origin node for each of the received changes
+	RecordChangeResult struct {
+		RecordID uint64 `json:"recordID,string"`
+		Status   string `json:"status"`
+
+		// Time of the last update of the record on the origin node
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	}
+)
@@ -14,6 +14,7 @@ type (
 		Name                       string         `json:"name"`
 		ExternalFederationModuleID uint64         `json:"externalFederationModuleID,string"`
 		Fields                     ModuleFieldSet `json:"fields"`
+		Bidirectional              bool           `json:"bidirectional"`
 
 		CreatedAt time.Time  `json:"createdAt,omitempty"`
 		CreatedBy uint64     `json:"createdBy,string" `
@@ -10,6 +10,11 @@ package types
 
 type (
 
+	// ConflictSet slice of Conflict
+	//
+	// This type is auto-generated.
+	ConflictSet []*Conflict
+
 	// ExposedModuleSet slice of ExposedModule
 	//
 	// This type is auto-generated.
@@ -30,12 +35,73 @@ type (
 	// This type is auto-generated.
 	NodeSyncSet []*NodeSync
 
+	// PendingChangeSet slice of PendingChange
+	//
+	// This type is auto-generated.
+	PendingChangeSet []*PendingChange
+
 	// SharedModuleSet slice of SharedModule
 	//
 	// This type is auto-generated.
 	SharedModuleSet []*SharedModule
 )
 
+// Walk iterates through every slice item and calls w(Conflict) err
+//
+// This function is auto-generated.
+func (set ConflictSet) Walk(w func(*Conflict) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(Conflict) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set ConflictSet) Filter(f func(*Conflict) (bool, error)) (out ConflictSet, err error) {
+	var ok bool
+	out = ConflictSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set ConflictSet) FindByID(ID uint64) *Conflict {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set ConflictSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(ExposedModule) err
 //
 // This function is auto-generated.
@@ -208,6 +274,62 @@ func (set NodeSyncSet) Filter(f func(*NodeSync) (bool, error)) (out NodeSyncSet,
 	return
 }
 
+// Walk iterates through every slice item and calls w(PendingChange) err
+//
+// This function is auto-generated.
+func (set PendingChangeSet) Walk(w func(*PendingChange) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(PendingChange) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set PendingChangeSet) Filter(f func(*PendingChange) (bool, error)) (out PendingChangeSet, err error) {
+	var ok bool
+	out = PendingChangeSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set PendingChangeSet) FindByID(ID uint64) *PendingChange {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set PendingChangeSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(SharedModule) err
 //
 // This function is auto-generated.
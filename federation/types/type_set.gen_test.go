@@ -14,6 +14,96 @@ import (
 	"testing"
 )
 
+func TestConflictSetWalk(t *testing.T) {
+	var (
+		value = make(ConflictSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*Conflict) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*Conflict) error { return fmt.Errorf("walk error") }))
+}
+
+func TestConflictSetFilter(t *testing.T) {
+	var (
+		value = make(ConflictSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*Conflict) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*Conflict) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*Conflict) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestConflictSetIDs(t *testing.T) {
+	var (
+		value = make(ConflictSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(Conflict)
+	value[1] = new(Conflict)
+	value[2] = new(Conflict)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
 func TestExposedModuleSetWalk(t *testing.T) {
 	var (
 		value = make(ExposedModuleSet, 3)
@@ -306,6 +396,96 @@ func TestNodeSyncSetFilter(t *testing.T) {
 	}
 }
 
+func TestPendingChangeSetWalk(t *testing.T) {
+	var (
+		value = make(PendingChangeSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*PendingChange) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*PendingChange) error { return fmt.Errorf("walk error") }))
+}
+
+func TestPendingChangeSetFilter(t *testing.T) {
+	var (
+		value = make(PendingChangeSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*PendingChange) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*PendingChange) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*PendingChange) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestPendingChangeSetIDs(t *testing.T) {
+	var (
+		value = make(PendingChangeSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(PendingChange)
+	value[1] = new(PendingChange)
+	value[2] = new(PendingChange)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
 func TestSharedModuleSetWalk(t *testing.T) {
 	var (
 		value = make(SharedModuleSet, 3)
@@ -6,13 +6,31 @@ import (
 	"github.com/go-chi/cors"
 )
 
-// Sets up default CORS rules to use as a middleware
-func handleCORS(next http.Handler) http.Handler {
+// DefaultCors returns default CORS rules
+func DefaultCors() *cors.Cors {
 	return cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-ID"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
-	}).Handler(next)
+	})
+}
+
+// Sets up default CORS rules to use as a middleware
+//
+// Preflight requests on passthrough paths are left to the next handler
+func handleCORS(next http.Handler) http.Handler {
+	var (
+		h = DefaultCors().Handler(next)
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions && isPassthrough(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
 }
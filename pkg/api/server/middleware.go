@@ -1,6 +1,7 @@
 package server
 
 import (
+	"net"
 	"net/http"
 	"os"
 	"runtime/debug"
@@ -13,11 +14,15 @@ import (
 	"go.uber.org/zap"
 )
 
-func BaseMiddleware(isProduction bool, log *zap.Logger) []func(http.Handler) http.Handler {
+// BaseMiddleware returns middleware used on all routes
+//
+// Forwarding headers (X-Forwarded-For, X-Real-IP) are honoured only
+// on requests coming from one of the trusted proxies.
+func BaseMiddleware(isProduction bool, log *zap.Logger, trustedProxies ...*net.IPNet) []func(http.Handler) http.Handler {
 	return []func(http.Handler) http.Handler{
 		handleCORS,
 		locale.DetectLanguage(locale.Global()),
-		middleware.RealIP,
+		realIP(trustedProxies),
 		api.RemoteAddrToContext,
 		middleware.RequestID,
 		api.DebugToContext(isProduction),
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+var (
+	// path prefixes of handlers that take care
+	// of CORS preflight requests and authentication on their own
+	passthrough    = map[string]bool{}
+	passthroughMux sync.RWMutex
+)
+
+// Passthrough registers path prefix of a handler that answers CORS
+// preflight requests and authenticates requests on its own (see API Gateway)
+//
+// Default CORS preflight handling and JWT verification
+// are skipped for all requests under that prefix
+func Passthrough(prefix string) {
+	passthroughMux.Lock()
+	defer passthroughMux.Unlock()
+
+	passthrough[prefix] = true
+}
+
+func isPassthrough(path string) bool {
+	passthroughMux.RLock()
+	defer passthroughMux.RUnlock()
+
+	for prefix := range passthrough {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipOnPassthrough wraps middleware so that
+// it is not used for passthrough requests
+func skipOnPassthrough(mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		var (
+			h = mw(next)
+		)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isPassthrough(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	require.NoError(t, err)
+
+	var cc = []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		xri        string
+		out        string
+	}{
+		{"no headers", "203.0.113.1:1234", "", "", "203.0.113.1:1234"},
+		{"spoofed forwarded-for", "203.0.113.1:1234", "198.51.100.1", "", "203.0.113.1:1234"},
+		{"spoofed real-ip", "203.0.113.1:1234", "", "198.51.100.1", "203.0.113.1:1234"},
+		{"trusted proxy", "10.1.1.1:1234", "198.51.100.1", "", "198.51.100.1"},
+		{"trusted proxy by address", "192.168.1.1:1234", "198.51.100.1", "", "198.51.100.1"},
+		{"trusted proxy real-ip", "10.1.1.1:1234", "", "198.51.100.1", "198.51.100.1"},
+		{"chain of trusted proxies", "10.1.1.1:1234", "198.51.100.1, 10.2.2.2", "", "198.51.100.1"},
+		{"client prepends spoofed address", "10.1.1.1:1234", "192.0.2.1, 198.51.100.1", "", "198.51.100.1"},
+		{"malformed header", "10.1.1.1:1234", "not-an-ip", "", "10.1.1.1:1234"},
+	}
+
+	for _, c := range cc {
+		t.Run(c.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				out string
+				r   = httptest.NewRequest(http.MethodGet, "/", nil)
+			)
+
+			r.RemoteAddr = c.remoteAddr
+			if c.xff != "" {
+				r.Header.Set("X-Forwarded-For", c.xff)
+			}
+
+			if c.xri != "" {
+				r.Header.Set("X-Real-IP", c.xri)
+			}
+
+			realIP(trusted)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				out = r.RemoteAddr
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			req.Equal(c.out, out)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	req := require.New(t)
+
+	nn, err := ParseTrustedProxies("")
+	req.NoError(err)
+	req.Empty(nn)
+
+	nn, err = ParseTrustedProxies("127.0.0.1/8,::1/128, 172.16.0.1")
+	req.NoError(err)
+	req.Len(nn, 3)
+
+	_, err = ParseTrustedProxies("172.16.0.0/33")
+	req.Error(err)
+
+	_, err = ParseTrustedProxies("proxy.local")
+	req.Error(err)
+}
+
+func TestParseTrustedProxies_default(t *testing.T) {
+	req := require.New(t)
+
+	nn, err := ParseTrustedProxies(options.HTTPServer().TrustedProxies)
+	req.NoError(err)
+
+	// proxies on docker & kubernetes networks
+	req.True(isTrustedProxy(nn, net.ParseIP("172.18.0.2")))
+	req.True(isTrustedProxy(nn, net.ParseIP("10.42.0.17")))
+	req.True(isTrustedProxy(nn, net.ParseIP("::1")))
+	req.False(isTrustedProxy(nn, net.ParseIP("203.0.113.1")))
+}
@@ -50,6 +50,12 @@ func (s server) Serve(ctx context.Context) {
 		zap.String("address", s.httpOpt.Addr),
 	)
 
+	trustedProxies, err := ParseTrustedProxies(s.httpOpt.TrustedProxies)
+	if err != nil {
+		s.log.Error("cannot start server", zap.Error(err))
+		return
+	}
+
 	listener, err := net.Listen("tcp", s.httpOpt.Addr)
 	if err != nil {
 		s.log.Error("cannot start server", zap.Error(err))
@@ -73,7 +79,7 @@ func (s server) Serve(ctx context.Context) {
 		r.Use(handlePanic)
 
 		// Base middleware, CORS, RealIP, RequestID, context-logger
-		r.Use(BaseMiddleware(s.environmentOpt.IsProduction(), s.log)...)
+		r.Use(BaseMiddleware(s.environmentOpt.IsProduction(), s.log, trustedProxies...)...)
 
 		// Logging request if enabled
 		if s.httpOpt.LogRequest {
@@ -86,8 +92,8 @@ func (s server) Serve(ctx context.Context) {
 		}
 
 		r.Use(
-			auth.DefaultJwtHandler.HttpVerifier(),
-			auth.DefaultJwtHandler.HttpAuthenticator(),
+			skipOnPassthrough(auth.DefaultJwtHandler.HttpVerifier()),
+			skipOnPassthrough(auth.DefaultJwtHandler.HttpAuthenticator()),
 		)
 
 		for _, mountRoutes := range s.endpoints {
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	// Memory is an in-memory LRU cache of route responses
+	//
+	// Least recently used entries are evicted when
+	// the number of entries reaches the limit
+	Memory struct {
+		mux sync.Mutex
+		max int
+		ll  *list.List
+		ee  map[string]*list.Element
+		now func() time.Time
+	}
+)
+
+const (
+	// used when size is not set
+	defaultMemorySize = 1000
+)
+
+func NewMemory(size int) *Memory {
+	if size <= 0 {
+		size = defaultMemorySize
+	}
+
+	return &Memory{
+		max: size,
+		ll:  list.New(),
+		ee:  make(map[string]*list.Element),
+		now: time.Now,
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) (*st.ApigwCacheEntry, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	el, has := m.ee[key]
+	if !has {
+		return nil, nil
+	}
+
+	e := el.Value.(*st.ApigwCacheEntry)
+	if e.Expired(m.now()) {
+		m.remove(el)
+		return nil, nil
+	}
+
+	m.ll.MoveToFront(el)
+	return e, nil
+}
+
+func (m *Memory) Set(_ context.Context, e *st.ApigwCacheEntry) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if el, has := m.ee[e.Key]; has {
+		el.Value = e
+		m.ll.MoveToFront(el)
+		return nil
+	}
+
+	m.ee[e.Key] = m.ll.PushFront(e)
+
+	for m.ll.Len() > m.max {
+		m.remove(m.ll.Back())
+	}
+
+	return nil
+}
+
+func (m *Memory) Purge(_ context.Context, routeID uint64) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	for _, el := range m.ee {
+		if el.Value.(*st.ApigwCacheEntry).RouteID == routeID {
+			m.remove(el)
+		}
+	}
+
+	return nil
+}
+
+// Len returns number of cached entries
+func (m *Memory) Len() int {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	return m.ll.Len()
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.ll.Remove(el)
+	delete(m.ee, el.Value.(*st.ApigwCacheEntry).Key)
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	st "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory_Evict(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		m   = NewMemory(2)
+		exp = time.Now().Add(time.Hour)
+	)
+
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "a", ExpiresAt: exp}))
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "b", ExpiresAt: exp}))
+
+	// a is now the most recently used
+	e, err := m.Get(ctx, "a")
+	req.NoError(err)
+	req.NotNil(e)
+
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "c", ExpiresAt: exp}))
+	req.Equal(2, m.Len())
+
+	e, _ = m.Get(ctx, "b")
+	req.Nil(e)
+
+	e, _ = m.Get(ctx, "a")
+	req.NotNil(e)
+}
+
+func TestMemory_Expired(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		m   = NewMemory(0)
+		now = time.Now()
+	)
+
+	m.now = func() time.Time { return now }
+
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "a", ExpiresAt: now.Add(time.Minute)}))
+
+	e, _ := m.Get(ctx, "a")
+	req.NotNil(e)
+
+	now = now.Add(time.Minute)
+
+	e, _ = m.Get(ctx, "a")
+	req.Nil(e)
+	req.Equal(0, m.Len())
+}
+
+func TestMemory_Purge(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		m   = NewMemory(0)
+		exp = time.Now().Add(time.Hour)
+	)
+
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "1:a", RouteID: 1, ExpiresAt: exp}))
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "1:b", RouteID: 1, ExpiresAt: exp}))
+	req.NoError(m.Set(ctx, &st.ApigwCacheEntry{Key: "2:a", RouteID: 2, ExpiresAt: exp}))
+
+	req.NoError(m.Purge(ctx, 1))
+	req.Equal(1, m.Len())
+
+	e, _ := m.Get(ctx, "2:a")
+	req.NotNil(e)
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/store"
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	storer interface {
+		LookupApigwCacheEntryByKey(ctx context.Context, key string) (*st.ApigwCacheEntry, error)
+		UpsertApigwCacheEntry(ctx context.Context, rr ...*st.ApigwCacheEntry) error
+		DeleteApigwCacheEntryByKey(ctx context.Context, key string) error
+		DeleteApigwCacheEntriesByRouteID(ctx context.Context, routeID uint64) error
+		DeleteExpiredApigwCacheEntries(ctx context.Context) error
+	}
+
+	// Store keeps cached route responses in the database
+	//
+	// Cache is shared between all instances of the server
+	// and survives restarts
+	Store struct {
+		mux     sync.Mutex
+		store   storer
+		cleaned time.Time
+		now     func() time.Time
+	}
+)
+
+const (
+	// responses with larger bodies are not stored
+	// (size of binary column on all supported databases)
+	storeMaxBodySize = 1 << 16
+
+	// expired entries are removed from the store
+	// when setting new entries but not more often than this
+	storeCleanupInterval = time.Minute
+)
+
+func NewStore(s storer) *Store {
+	return &Store{store: s, now: time.Now}
+}
+
+func (s *Store) Get(ctx context.Context, key string) (*st.ApigwCacheEntry, error) {
+	e, err := s.store.LookupApigwCacheEntryByKey(ctx, key)
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if e.Expired(s.now()) {
+		return nil, s.store.DeleteApigwCacheEntryByKey(ctx, key)
+	}
+
+	return e, nil
+}
+
+func (s *Store) Set(ctx context.Context, e *st.ApigwCacheEntry) error {
+	if len(e.Body) > storeMaxBodySize {
+		return nil
+	}
+
+	if err := s.cleanup(ctx); err != nil {
+		return err
+	}
+
+	return s.store.UpsertApigwCacheEntry(ctx, e)
+}
+
+func (s *Store) Purge(ctx context.Context, routeID uint64) error {
+	return s.store.DeleteApigwCacheEntriesByRouteID(ctx, routeID)
+}
+
+// cleanup removes expired entries from the store
+func (s *Store) cleanup(ctx context.Context) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if now := s.now(); now.Sub(s.cleaned) > storeCleanupInterval {
+		s.cleaned = now
+		return s.store.DeleteExpiredApigwCacheEntries(ctx)
+	}
+
+	return nil
+}
@@ -0,0 +1,346 @@
+package filter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	responseCache struct {
+		types.FilterMeta
+
+		caches map[string]types.ResponseCache
+		cache  types.ResponseCache
+		ttl    time.Duration
+
+		params struct {
+			TTL     string   `json:"ttl"`
+			Storage string   `json:"storage"`
+			Query   []string `json:"query"`
+			Headers []string `json:"headers"`
+		}
+	}
+
+	// bufferedResponse collects the response
+	// so it can be cached before it is sent
+	bufferedResponse struct {
+		header  http.Header
+		status  int
+		written bool
+		body    bytes.Buffer
+	}
+)
+
+const (
+	// larger responses are not cached
+	responseCacheMaxBodySize = 1 << 20
+)
+
+func NewResponseCache(caches map[string]types.ResponseCache) (v *responseCache) {
+	v = &responseCache{caches: caches}
+
+	v.Name = "responseCache"
+	v.Label = "Response cache"
+	v.Kind = types.PostFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "ttl",
+			Example: "5m",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "storage",
+			Example: types.ResponseCacheMemory,
+			Options: map[string]interface{}{
+				"values": []string{types.ResponseCacheMemory, types.ResponseCacheStore},
+			},
+		},
+		{
+			Type:    "list",
+			Label:   "query",
+			Example: `["page", "limit"]`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "list",
+			Label:   "headers",
+			Example: `["Accept-Language"]`,
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h responseCache) New() types.Handler {
+	return NewResponseCache(h.caches)
+}
+
+func (h responseCache) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h responseCache) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *responseCache) Merge(params []byte) (types.Handler, error) {
+	var (
+		has bool
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&h.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if h.ttl, err = time.ParseDuration(h.params.TTL); err != nil || h.ttl <= 0 {
+		return nil, fmt.Errorf("could not validate response cache parameters: invalid ttl %q", h.params.TTL)
+	}
+
+	if h.params.Storage == "" {
+		h.params.Storage = types.ResponseCacheMemory
+	}
+
+	if h.cache, has = h.caches[h.params.Storage]; !has {
+		return nil, fmt.Errorf("could not validate response cache parameters: invalid storage %q", h.params.Storage)
+	}
+
+	return h, nil
+}
+
+// Handler does nothing; responses are cached by
+// wrapping processers and postfilters (see WrapRoute)
+func (h responseCache) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		return nil
+	}
+}
+
+// WrapRoute serves GET and HEAD requests from cache
+//
+// On cache miss, response of the rest of the route is collected
+// and cached when successful. Cached responses are validated
+// with ETag (If-None-Match request header).
+//
+// Responses are cached per authenticated user. Requests with
+// credentials (Authorization header) that were not authenticated
+// by one of the route's prefilters are never served from or stored
+// in the cache (RFC 7234, section 3.2).
+//
+// Cache errors are not fatal, request is processed as if
+// there was no cache.
+func (h responseCache) WrapRoute(routeID uint64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		var (
+			ctx      = r.Context()
+			identity = requestIdentity(r)
+		)
+
+		if identity == 0 && r.Header.Get("Authorization") != "" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		key := h.key(routeID, identity, r)
+
+		if !hasCacheDirective(r.Header, "no-cache") {
+			if e, err := h.cache.Get(ctx, key); err == nil && e != nil {
+				serveCached(rw, r, e, "HIT")
+				return
+			}
+		}
+
+		buf := &bufferedResponse{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		if !buf.cacheable() {
+			buf.flush(rw)
+			return
+		}
+
+		var (
+			now = time.Now()
+			e   = &st.ApigwCacheEntry{
+				Key:       key,
+				RouteID:   routeID,
+				Status:    buf.status,
+				Header:    st.ApigwCacheEntryHeader(buf.header),
+				Body:      buf.body.Bytes(),
+				ETag:      buf.header.Get("ETag"),
+				CreatedAt: now,
+				ExpiresAt: now.Add(h.ttl),
+			}
+		)
+
+		if e.ETag == "" {
+			sum := sha256.Sum256(e.Body)
+			e.ETag = `"` + hex.EncodeToString(sum[:16]) + `"`
+		}
+
+		_ = h.cache.Set(ctx, e)
+		serveCached(rw, r, e, "MISS")
+	})
+}
+
+// key returns cache key for the request
+//
+// Key is prefixed with route ID so that cached
+// responses can be purged by route
+func (h responseCache) key(routeID, identity uint64, r *http.Request) string {
+	var (
+		hash = sha256.New()
+		q    = r.URL.Query()
+	)
+
+	fmt.Fprintf(hash, "%s\n%s\n", r.Method, r.URL.Path)
+
+	for _, p := range h.params.Query {
+		fmt.Fprintf(hash, "q:%s=%s\n", p, strings.Join(q[p], ","))
+	}
+
+	for _, hdr := range h.params.Headers {
+		fmt.Fprintf(hash, "h:%s=%s\n", strings.ToLower(hdr), strings.Join(r.Header.Values(hdr), ","))
+	}
+
+	if identity > 0 {
+		fmt.Fprintf(hash, "i:%d\n", identity)
+	}
+
+	return fmt.Sprintf("%d:%x", routeID, hash.Sum(nil))
+}
+
+// requestIdentity returns ID of the user that was authenticated
+// by one of the route's prefilters or 0 for anonymous requests
+//
+// Routes run as service user unless prefilter authenticates the request
+func requestIdentity(r *http.Request) uint64 {
+	i := auth.GetIdentityFromContext(r.Context())
+	if su := auth.ServiceUser(); !i.Valid() || (su != nil && su.ID == i.Identity()) {
+		return 0
+	}
+
+	return i.Identity()
+}
+
+// serveCached writes cached response or 304
+// when client already has the same version
+func serveCached(rw http.ResponseWriter, r *http.Request, e *st.ApigwCacheEntry, status string) {
+	hh := rw.Header()
+	for k, vv := range e.Header {
+		hh[k] = vv
+	}
+
+	hh.Set("ETag", e.ETag)
+	hh.Set("X-Cache", status)
+
+	if status == "HIT" {
+		hh.Set("Age", strconv.Itoa(int(time.Since(e.CreatedAt).Seconds())))
+	}
+
+	if etagMatch(r.Header.Get("If-None-Match"), e.ETag) {
+		hh.Del("Content-Length")
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.WriteHeader(e.Status)
+
+	if r.Method != http.MethodHead {
+		_, _ = rw.Write(e.Body)
+	}
+}
+
+// etagMatch checks If-None-Match header against the entity tag
+// with weak comparison (RFC 7232, section 3.2)
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasCacheDirective(hh http.Header, directive string) bool {
+	for _, d := range strings.Split(hh.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.written = true
+	return b.body.Write(p)
+}
+
+// WriteHeader sets status of the response;
+// as with http.ResponseWriter, only the first call counts
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.written {
+		return
+	}
+
+	b.written = true
+	b.status = status
+}
+
+// cacheable checks if the response can be cached
+//
+// Only successful responses that are not private
+// and do not set cookies are cached
+func (b *bufferedResponse) cacheable() bool {
+	switch {
+	case b.status != http.StatusOK,
+		b.body.Len() > responseCacheMaxBodySize,
+		b.header.Get("Set-Cookie") != "",
+		hasCacheDirective(b.header, "no-store"),
+		hasCacheDirective(b.header, "no-cache"),
+		hasCacheDirective(b.header, "private"):
+		return false
+	}
+
+	return true
+}
+
+// flush writes collected response
+func (b *bufferedResponse) flush(rw http.ResponseWriter) {
+	hh := rw.Header()
+	for k, vv := range b.header {
+		hh[k] = vv
+	}
+
+	rw.WriteHeader(b.status)
+	_, _ = rw.Write(b.body.Bytes())
+}
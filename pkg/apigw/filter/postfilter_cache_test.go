@@ -0,0 +1,213 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/cache"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_responseCacheMerge(t *testing.T) {
+	var (
+		caches = map[string]types.ResponseCache{types.ResponseCacheMemory: cache.NewMemory(0)}
+
+		tcc = []tf{
+			{
+				name: "defaults",
+				expr: `{"ttl":"5m"}`,
+			},
+			{
+				name: "query and headers",
+				expr: `{"ttl":"5m","storage":"memory","query":["page"],"headers":["Accept-Language"]}`,
+			},
+			{
+				name: "missing ttl",
+				expr: `{}`,
+				err:  `could not validate response cache parameters: invalid ttl ""`,
+			},
+			{
+				name: "invalid storage",
+				expr: `{"ttl":"5m","storage":"foo"}`,
+				err:  `could not validate response cache parameters: invalid storage "foo"`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewResponseCache(caches), tc))
+	}
+}
+
+func Test_responseCacheWrap(t *testing.T) {
+	var (
+		prepare = func(t *testing.T, params string, next http.HandlerFunc) http.Handler {
+			h, err := NewResponseCache(map[string]types.ResponseCache{
+				types.ResponseCacheMemory: cache.NewMemory(0),
+			}).Merge([]byte(params))
+
+			require.NoError(t, err)
+			return h.(types.RouteWrapper).WrapRoute(1, next)
+		}
+
+		serve = func(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+			return rr
+		}
+	)
+
+	t.Run("hit and not modified", func(t *testing.T) {
+		var (
+			req   = require.New(t)
+			calls = 0
+
+			h = prepare(t, `{"ttl":"1m"}`, func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+				rw.Header().Set("Content-Type", "text/plain")
+				rw.Write([]byte("body"))
+			})
+		)
+
+		rr := serve(h, httptest.NewRequest(http.MethodGet, "/foo", nil))
+		req.Equal("MISS", rr.Header().Get("X-Cache"))
+		req.Equal("body", rr.Body.String())
+
+		etag := rr.Header().Get("ETag")
+		req.NotEmpty(etag)
+
+		rr = serve(h, httptest.NewRequest(http.MethodGet, "/foo", nil))
+		req.Equal("HIT", rr.Header().Get("X-Cache"))
+		req.Equal("text/plain", rr.Header().Get("Content-Type"))
+		req.Equal("body", rr.Body.String())
+
+		r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+		r.Header.Set("If-None-Match", "W/"+etag)
+		rr = serve(h, r)
+		req.Equal(http.StatusNotModified, rr.Code)
+		req.Empty(rr.Body.String())
+
+		req.Equal(1, calls)
+	})
+
+	t.Run("key", func(t *testing.T) {
+		var (
+			req   = require.New(t)
+			calls = 0
+
+			h = prepare(t, `{"ttl":"1m","query":["page"],"headers":["Accept-Language"]}`, func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+			})
+
+			get = func(url, lang string) {
+				r := httptest.NewRequest(http.MethodGet, url, nil)
+				r.Header.Set("Accept-Language", lang)
+				serve(h, r)
+			}
+		)
+
+		get("/foo?page=1", "en")
+		get("/foo?page=1&other=1", "en")
+		req.Equal(1, calls)
+
+		get("/foo?page=2", "en")
+		req.Equal(2, calls)
+
+		get("/foo?page=1", "de")
+		req.Equal(3, calls)
+
+		get("/bar?page=1", "en")
+		req.Equal(4, calls)
+	})
+
+	t.Run("authenticated requests", func(t *testing.T) {
+		var (
+			req   = require.New(t)
+			calls = 0
+
+			h = prepare(t, `{"ttl":"1m"}`, func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+				rw.Write([]byte(fmt.Sprintf("user %d", auth.GetIdentityFromContext(r.Context()).Identity())))
+			})
+
+			get = func(token string, userID uint64) *httptest.ResponseRecorder {
+				r := httptest.NewRequest(http.MethodGet, "/foo", nil)
+				r.Header.Set("Authorization", "Bearer "+token)
+
+				if userID > 0 {
+					// identity set by the JWT prefilter
+					r = r.WithContext(auth.SetIdentityToContext(r.Context(), auth.Authenticated(userID)))
+				}
+
+				return serve(h, r)
+			}
+		)
+
+		req.Equal("user 1", get("token-1", 1).Body.String())
+		req.Equal("user 2", get("token-2", 2).Body.String())
+		req.Equal(2, calls)
+
+		rr := get("token-1", 1)
+		req.Equal("HIT", rr.Header().Get("X-Cache"))
+		req.Equal("user 1", rr.Body.String())
+
+		rr = get("token-2", 2)
+		req.Equal("HIT", rr.Header().Get("X-Cache"))
+		req.Equal("user 2", rr.Body.String())
+		req.Equal(2, calls)
+
+		// credentials that no prefilter resolved are not cached
+		get("token-3", 0)
+		rr = get("token-3", 0)
+		req.Empty(rr.Header().Get("X-Cache"))
+		req.Equal(4, calls)
+	})
+
+	t.Run("not cacheable", func(t *testing.T) {
+		var (
+			req   = require.New(t)
+			calls = 0
+		)
+
+		for _, next := range []http.HandlerFunc{
+			func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+				rw.WriteHeader(http.StatusNotFound)
+			},
+			func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+				rw.Header().Set("Cache-Control", "private")
+			},
+			func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+				rw.Header().Set("Set-Cookie", "foo=bar")
+			},
+		} {
+			h := prepare(t, `{"ttl":"1m"}`, next)
+			serve(h, httptest.NewRequest(http.MethodGet, "/foo", nil))
+			rr := serve(h, httptest.NewRequest(http.MethodGet, "/foo", nil))
+			req.Empty(rr.Header().Get("X-Cache"))
+		}
+
+		req.Equal(6, calls)
+	})
+
+	t.Run("unsafe methods", func(t *testing.T) {
+		var (
+			req   = require.New(t)
+			calls = 0
+
+			h = prepare(t, `{"ttl":"1m"}`, func(rw http.ResponseWriter, r *http.Request) {
+				calls++
+			})
+		)
+
+		serve(h, httptest.NewRequest(http.MethodPost, "/foo", nil))
+		serve(h, httptest.NewRequest(http.MethodPost, "/foo", nil))
+		req.Equal(2, calls)
+	})
+}
@@ -0,0 +1,435 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/dgrijalva/jwt-go"
+	"gopkg.in/square/go-jose.v2"
+)
+
+type (
+	authTokens struct {
+		types.FilterMeta
+
+		c      *http.Client
+		tokens TokenAuthenticator
+		ir     types.IdentityResolver
+		keys   *jwks
+
+		params struct {
+			Type     string `json:"type"`
+			JwksURL  string `json:"jwksUrl"`
+			Issuer   string `json:"issuer"`
+			Audience string `json:"audience"`
+			Claim    string `json:"claim"`
+			Lookup   string `json:"lookup"`
+			Scope    string `json:"scope"`
+		}
+	}
+
+	// TokenAuthenticator validates Corteza access tokens
+	TokenAuthenticator interface {
+		Authenticate(token string) (jwt.MapClaims, error)
+	}
+
+	// jwks fetches and caches keys used
+	// for validating signatures of external tokens
+	jwks struct {
+		mux     sync.Mutex
+		c       *http.Client
+		url     string
+		set     *jose.JSONWebKeySet
+		fetched time.Time
+		now     func() time.Time
+	}
+
+	// authError is written as a response with
+	// WWW-Authenticate header as defined in RFC 6750
+	authError struct {
+		status int
+		code   string
+		msg    string
+	}
+)
+
+const (
+	authTypeCorteza = "corteza"
+	authTypeJwks    = "jwks"
+
+	// keys are re-fetched after this period
+	jwksTTL = time.Hour
+
+	// unknown key IDs trigger re-fetch (key rotation)
+	// but not more often than this
+	jwksMinRefresh = time.Minute
+)
+
+var (
+	jwksAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+)
+
+func NewAuth(c *http.Client, tokens TokenAuthenticator, ir types.IdentityResolver) (v *authTokens) {
+	v = &authTokens{c: c, tokens: tokens, ir: ir}
+
+	v.Name = "auth"
+	v.Label = "Authentication (JWT)"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "text",
+			Label:   "type",
+			Example: authTypeCorteza,
+			Options: map[string]interface{}{
+				"values": []string{authTypeCorteza, authTypeJwks},
+			},
+		},
+		{
+			Type:    "text",
+			Label:   "jwksUrl",
+			Example: "https://issuer.example.com/.well-known/jwks.json",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "issuer",
+			Example: "https://issuer.example.com/",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "audience",
+			Example: "my-api",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "claim",
+			Example: "email",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "lookup",
+			Example: types.IdentityLookupEmail,
+			Options: map[string]interface{}{
+				"values": []string{types.IdentityLookupEmail, types.IdentityLookupHandle, types.IdentityLookupID},
+			},
+		},
+		{
+			Type:    "text",
+			Label:   "scope",
+			Example: "api",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h authTokens) New() types.Handler {
+	return NewAuth(h.c, h.tokens, h.ir)
+}
+
+func (h authTokens) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h authTokens) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *authTokens) Merge(params []byte) (types.Handler, error) {
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&h.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch h.params.Type {
+	case "", authTypeCorteza:
+		h.params.Type = authTypeCorteza
+
+	case authTypeJwks:
+		if h.params.JwksURL == "" {
+			return nil, fmt.Errorf("could not validate auth parameters: JWKS URL missing")
+		}
+
+		if h.params.Claim == "" {
+			h.params.Claim = "email"
+		}
+
+		switch h.params.Lookup {
+		case "":
+			h.params.Lookup = types.IdentityLookupEmail
+		case types.IdentityLookupEmail, types.IdentityLookupHandle, types.IdentityLookupID:
+		default:
+			return nil, fmt.Errorf("could not validate auth parameters: invalid lookup %q", h.params.Lookup)
+		}
+
+		h.keys = &jwks{c: h.c, url: h.params.JwksURL, now: time.Now}
+
+	default:
+		return nil, fmt.Errorf("could not validate auth parameters: invalid type %q", h.params.Type)
+	}
+
+	return h, nil
+}
+
+// Handler authenticates the request with the bearer token
+//
+// Resolved identity is stored in the scope; filters that follow
+// (workflow processer) run as that user
+func (h authTokens) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		var (
+			ctx = r.Context()
+
+			i   auth.Identifiable
+			err error
+		)
+
+		if i, err = h.authenticate(ctx, r); err != nil {
+			ae, is := err.(*authError)
+			if !is {
+				ae = &authError{status: http.StatusUnauthorized, code: "invalid_token", msg: err.Error()}
+			}
+
+			return ae.respond(rw)
+		}
+
+		agctx.ScopeFromContext(ctx).Set("identity", i)
+		return nil
+	}
+}
+
+func (h authTokens) authenticate(ctx context.Context, r *http.Request) (auth.Identifiable, error) {
+	var (
+		claims jwt.MapClaims
+		i      auth.Identifiable
+		err    error
+	)
+
+	tkn := bearerToken(r)
+	if tkn == "" {
+		return nil, &authError{status: http.StatusUnauthorized, msg: "access token missing"}
+	}
+
+	switch h.params.Type {
+	case authTypeJwks:
+		claims, err = h.parseExternal(tkn)
+	default:
+		claims, err = h.parseCorteza(tkn)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasScope(claims, h.params.Scope) {
+		return nil, &authError{status: http.StatusForbidden, code: "insufficient_scope", msg: "access token scope insufficient"}
+	}
+
+	if h.params.Type == authTypeCorteza {
+		if i = auth.ClaimsToIdentity(claims); i == nil {
+			return nil, fmt.Errorf("access token subject invalid")
+		}
+
+		return i, nil
+	}
+
+	ref, _ := claims[h.params.Claim].(string)
+	if ref == "" {
+		return nil, fmt.Errorf("access token claim %q missing", h.params.Claim)
+	}
+
+	if i, err = h.ir.ResolveIdentity(ctx, h.params.Lookup, ref); err != nil {
+		return nil, &authError{status: http.StatusForbidden, msg: err.Error()}
+	}
+
+	return i, nil
+}
+
+func (h authTokens) parseCorteza(tkn string) (jwt.MapClaims, error) {
+	var (
+		tokens = h.tokens
+	)
+
+	if tokens == nil {
+		// use current default JWT handler; it can be
+		// (re)configured after the filters are registered
+		tokens = auth.DefaultJwtHandler
+	}
+
+	claims, err := tokens.Authenticate(tkn)
+	if err != nil {
+		return nil, fmt.Errorf("access token invalid: %v", err)
+	}
+
+	return claims, nil
+}
+
+func (h authTokens) parseExternal(tkn string) (jwt.MapClaims, error) {
+	var (
+		claims = jwt.MapClaims{}
+		parser = &jwt.Parser{ValidMethods: jwksAlgorithms}
+	)
+
+	_, err := parser.ParseWithClaims(tkn, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return h.keys.key(kid)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("access token invalid: %v", err)
+	}
+
+	if h.params.Issuer != "" && !claims.VerifyIssuer(h.params.Issuer, true) {
+		return nil, fmt.Errorf("access token issuer invalid")
+	}
+
+	if h.params.Audience != "" && !hasAudience(claims, h.params.Audience) {
+		return nil, fmt.Errorf("access token audience invalid")
+	}
+
+	return claims, nil
+}
+
+// key returns public key with the given ID
+//
+// Keys are (re)fetched when expired or when key ID is not known
+func (k *jwks) key(kid string) (interface{}, error) {
+	k.mux.Lock()
+	defer k.mux.Unlock()
+
+	var (
+		now     = k.now()
+		expired = k.set == nil || now.Sub(k.fetched) > jwksTTL
+	)
+
+	if expired || (len(k.set.Key(kid)) == 0 && now.Sub(k.fetched) > jwksMinRefresh) {
+		if err := k.fetch(now); err != nil && k.set == nil {
+			return nil, err
+		}
+	}
+
+	for _, jwk := range k.set.Keys {
+		if kid != "" && jwk.KeyID != kid {
+			continue
+		}
+
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		return jwk.Key, nil
+	}
+
+	return nil, fmt.Errorf("signing key %q not found", kid)
+}
+
+func (k *jwks) fetch(now time.Time) error {
+	rsp, err := k.c.Get(k.url)
+	if err != nil {
+		return fmt.Errorf("could not fetch signing keys: %v", err)
+	}
+
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not fetch signing keys: unexpected status %d", rsp.StatusCode)
+	}
+
+	set := &jose.JSONWebKeySet{}
+	if err = json.NewDecoder(rsp.Body).Decode(set); err != nil {
+		return fmt.Errorf("could not decode signing keys: %v", err)
+	}
+
+	k.set = set
+	k.fetched = now
+	return nil
+}
+
+func (e *authError) Error() string {
+	return e.msg
+}
+
+// respond writes error response and stops further processing
+func (e *authError) respond(rw http.ResponseWriter) error {
+	challenge := `Bearer realm="corteza"`
+	if e.code != "" {
+		challenge += fmt.Sprintf(`, error="%s"`, e.code)
+	}
+
+	rw.Header().Set("WWW-Authenticate", challenge)
+	return respondWithError(rw, e.status, e.msg)
+}
+
+func bearerToken(r *http.Request) string {
+	hdr := r.Header.Get("Authorization")
+	if len(hdr) > 7 && strings.EqualFold(hdr[:7], "bearer ") {
+		return strings.TrimSpace(hdr[7:])
+	}
+
+	return ""
+}
+
+// hasScope checks if all required scopes are granted
+//
+// Scopes are read from scope or scp claims,
+// as a space separated string or as a list
+func hasScope(claims jwt.MapClaims, required string) bool {
+	var (
+		granted = make(map[string]bool)
+	)
+
+	for _, c := range []string{"scope", "scp"} {
+		switch s := claims[c].(type) {
+		case string:
+			for _, s := range strings.Fields(s) {
+				granted[s] = true
+			}
+
+		case []interface{}:
+			for _, s := range s {
+				if s, ok := s.(string); ok {
+					granted[s] = true
+				}
+			}
+		}
+	}
+
+	for _, s := range strings.Fields(required) {
+		if !granted[s] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasAudience checks aud claim that can be a string or a list
+func hasAudience(claims jwt.MapClaims, aud string) bool {
+	switch a := claims["aud"].(type) {
+	case string:
+		return a == aud
+	case []interface{}:
+		for _, a := range a {
+			if a == aud {
+				return true
+			}
+		}
+	}
+
+	return false
+}
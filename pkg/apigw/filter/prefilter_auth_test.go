@@ -0,0 +1,287 @@
+package filter
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	agctx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func Test_authMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "defaults to corteza tokens",
+				expr: `{}`,
+			},
+			{
+				name: "jwks",
+				expr: `{"type":"jwks","jwksUrl":"https://issuer.example.com/jwks.json","lookup":"handle"}`,
+			},
+			{
+				name: "jwks without URL",
+				expr: `{"type":"jwks"}`,
+				err:  "could not validate auth parameters: JWKS URL missing",
+			},
+			{
+				name: "jwks with invalid lookup",
+				expr: `{"type":"jwks","jwksUrl":"https://issuer.example.com/jwks.json","lookup":"foo"}`,
+				err:  `could not validate auth parameters: invalid lookup "foo"`,
+			},
+			{
+				name: "invalid type",
+				expr: `{"type":"foo"}`,
+				err:  `could not validate auth parameters: invalid type "foo"`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewAuth(http.DefaultClient, nil, nil), tc))
+	}
+}
+
+func Test_authHandleCorteza(t *testing.T) {
+	var (
+		tokens, _ = auth.JWT("secret", time.Hour)
+
+		handle = func(t *testing.T, params, token string) (*httptest.ResponseRecorder, *types.Scp, error) {
+			h, err := NewAuth(http.DefaultClient, tokens, nil).Merge([]byte(params))
+			require.NoError(t, err)
+
+			return authHandle(h, token)
+		}
+	)
+
+	t.Run("valid token", func(t *testing.T) {
+		var (
+			req = require.New(t)
+		)
+
+		rc, scope, err := handle(t, `{}`, tokens.Encode(auth.Authenticated(42, 1)))
+
+		req.NoError(err)
+		req.Equal(http.StatusOK, rc.Code)
+		req.NotNil(scope.Identity())
+		req.Equal(uint64(42), scope.Identity().Identity())
+		req.Contains(scope.Identity().Roles(), uint64(1))
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		var (
+			req = require.New(t)
+		)
+
+		rc, scope, err := handle(t, `{}`, "")
+
+		req.Error(err)
+		req.Equal(http.StatusUnauthorized, rc.Code)
+		req.Equal(`Bearer realm="corteza"`, rc.Header().Get("WWW-Authenticate"))
+		req.Nil(scope.Identity())
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		var (
+			req        = require.New(t)
+			other, _   = auth.JWT("other-secret", time.Hour)
+			rc, _, err = handle(t, `{}`, other.Encode(auth.Authenticated(42)))
+		)
+
+		req.Error(err)
+		req.Equal(http.StatusUnauthorized, rc.Code)
+		req.Equal(`Bearer realm="corteza", error="invalid_token"`, rc.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("insufficient scope", func(t *testing.T) {
+		var (
+			req        = require.New(t)
+			rc, _, err = handle(t, `{"scope":"api"}`, tokens.Encode(auth.Authenticated(42), "profile"))
+		)
+
+		req.Error(err)
+		req.Equal(http.StatusForbidden, rc.Code)
+		req.Equal(`Bearer realm="corteza", error="insufficient_scope"`, rc.Header().Get("WWW-Authenticate"))
+	})
+}
+
+func Test_authHandleJwks(t *testing.T) {
+	var (
+		key, _  = rsa.GenerateKey(rand.Reader, 2048)
+		fetches int
+
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: "k1", Algorithm: "RS256", Use: "sig"},
+			}})
+		}))
+
+		resolver = types.MockIdentityResolver{
+			"user@example.com": auth.Authenticated(42),
+		}
+
+		sign = func(kid string, claims jwt.MapClaims) string {
+			if _, has := claims["exp"]; !has {
+				claims["exp"] = time.Now().Add(time.Hour).Unix()
+			}
+
+			tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			tkn.Header["kid"] = kid
+			s, err := tkn.SignedString(key)
+			require.NoError(t, err)
+			return s
+		}
+
+		h = func(t *testing.T) types.Handler {
+			h, err := NewAuth(srv.Client(), nil, resolver).Merge([]byte(`{
+				"type": "jwks",
+				"jwksUrl": "` + srv.URL + `",
+				"issuer": "https://issuer.example.com/",
+				"audience": "api",
+				"scope": "read"
+			}`))
+
+			require.NoError(t, err)
+			return h
+		}
+	)
+
+	defer srv.Close()
+
+	t.Run("valid token", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			tkn = sign("k1", jwt.MapClaims{
+				"iss":   "https://issuer.example.com/",
+				"aud":   []string{"api", "other"},
+				"email": "user@example.com",
+				"scope": "read write",
+			})
+		)
+
+		rc, scope, err := authHandle(h(t), tkn)
+
+		req.NoError(err)
+		req.Equal(http.StatusOK, rc.Code)
+		req.Equal(uint64(42), scope.Identity().Identity())
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			tkn = sign("k1", jwt.MapClaims{
+				"iss":   "https://issuer.example.com/",
+				"aud":   "api",
+				"email": "other@example.com",
+				"scope": "read",
+			})
+		)
+
+		rc, scope, err := authHandle(h(t), tkn)
+
+		req.Error(err)
+		req.Equal(http.StatusForbidden, rc.Code)
+		req.Nil(scope.Identity())
+	})
+
+	t.Run("invalid issuer", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			tkn = sign("k1", jwt.MapClaims{
+				"iss":   "https://other.example.com/",
+				"aud":   "api",
+				"email": "user@example.com",
+				"scope": "read",
+			})
+		)
+
+		rc, _, err := authHandle(h(t), tkn)
+
+		req.Error(err)
+		req.Equal(http.StatusUnauthorized, rc.Code)
+		req.Contains(rc.Body.String(), "access token issuer invalid")
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			tkn = sign("k1", jwt.MapClaims{
+				"exp":   time.Now().Add(-time.Minute).Unix(),
+				"iss":   "https://issuer.example.com/",
+				"aud":   "api",
+				"email": "user@example.com",
+				"scope": "read",
+			})
+		)
+
+		rc, _, err := authHandle(h(t), tkn)
+
+		req.Error(err)
+		req.Equal(http.StatusUnauthorized, rc.Code)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			tkn = sign("k2", jwt.MapClaims{
+				"iss":   "https://issuer.example.com/",
+				"aud":   "api",
+				"email": "user@example.com",
+				"scope": "read",
+			})
+		)
+
+		rc, _, err := authHandle(h(t), tkn)
+
+		req.Error(err)
+		req.Equal(http.StatusUnauthorized, rc.Code)
+		req.Contains(rc.Body.String(), `signing key \"k2\" not found`)
+	})
+
+	t.Run("keys are cached", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			hh  = h(t)
+			tkn = sign("k1", jwt.MapClaims{
+				"iss":   "https://issuer.example.com/",
+				"aud":   "api",
+				"email": "user@example.com",
+				"scope": "read",
+			})
+		)
+
+		fetches = 0
+
+		for i := 0; i < 3; i++ {
+			_, _, err := authHandle(hh, tkn)
+			req.NoError(err)
+		}
+
+		req.Equal(1, fetches)
+	})
+}
+
+func authHandle(h types.Handler, token string) (*httptest.ResponseRecorder, *types.Scp, error) {
+	var (
+		rc    = httptest.NewRecorder()
+		r     = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+		scope = &types.Scp{}
+	)
+
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	r = r.WithContext(agctx.ScopeToContext(r.Context(), scope))
+	return rc, scope, h.Handler()(rc, r)
+}
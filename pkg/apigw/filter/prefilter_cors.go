@@ -0,0 +1,154 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/go-chi/cors"
+)
+
+type (
+	corsPolicy struct {
+		types.FilterMeta
+		cors   *cors.Cors
+		params struct {
+			AllowedOrigins   []string `json:"allowedOrigins"`
+			AllowedMethods   []string `json:"allowedMethods"`
+			AllowedHeaders   []string `json:"allowedHeaders"`
+			ExposedHeaders   []string `json:"exposedHeaders"`
+			AllowCredentials bool     `json:"allowCredentials"`
+			MaxAge           int      `json:"maxAge"`
+		}
+	}
+)
+
+func NewCors() (v *corsPolicy) {
+	v = &corsPolicy{}
+
+	v.Name = "cors"
+	v.Label = "CORS"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "list",
+			Label:   "allowedOrigins",
+			Example: `["https://example.com", "https://*.example.com"]`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "list",
+			Label:   "allowedMethods",
+			Example: `["GET", "POST"]`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "list",
+			Label:   "allowedHeaders",
+			Example: `["Accept", "Authorization", "Content-Type"]`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "list",
+			Label:   "exposedHeaders",
+			Example: `["X-Request-Id"]`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "bool",
+			Label:   "allowCredentials",
+			Example: "false",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "number",
+			Label:   "maxAge",
+			Example: "300",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h corsPolicy) New() types.Handler {
+	return NewCors()
+}
+
+func (h corsPolicy) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h corsPolicy) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *corsPolicy) Merge(params []byte) (types.Handler, error) {
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&h.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h.params.AllowedOrigins) == 0 {
+		return nil, fmt.Errorf("could not validate CORS parameters: allowed origins missing")
+	}
+
+	if h.params.MaxAge < 0 {
+		return nil, fmt.Errorf("could not validate CORS parameters: invalid max age")
+	}
+
+	h.cors = cors.New(cors.Options{
+		AllowedOrigins:   h.params.AllowedOrigins,
+		AllowedMethods:   h.params.AllowedMethods,
+		AllowedHeaders:   h.params.AllowedHeaders,
+		ExposedHeaders:   h.params.ExposedHeaders,
+		AllowCredentials: h.params.AllowCredentials,
+		MaxAge:           h.params.MaxAge,
+	})
+
+	return h, nil
+}
+
+// Handler answers preflight requests and stops further processing
+//
+// On actual requests, headers set by the default CORS rules are
+// replaced with the ones from the filter
+func (h corsPolicy) Handler() types.HandlerFunc {
+	var (
+		apply = h.cors.Handler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	)
+
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		resetCorsHeaders(rw.Header())
+		apply.ServeHTTP(rw, r)
+
+		if !isPreflight(r) {
+			return nil
+		}
+
+		rw.WriteHeader(http.StatusNoContent)
+		return types.ErrStopProcessing
+	}
+}
+
+// isPreflight checks if the request is a CORS preflight request
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+func resetCorsHeaders(hh http.Header) {
+	for k := range hh {
+		if strings.HasPrefix(k, "Access-Control-") {
+			hh.Del(k)
+		}
+	}
+
+	hh.Del("Vary")
+}
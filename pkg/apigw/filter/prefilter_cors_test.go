@@ -0,0 +1,125 @@
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_corsMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "allowed origins",
+				expr: `{"allowedOrigins":["https://example.com"],"maxAge":300}`,
+			},
+			{
+				name: "missing origins",
+				expr: `{"allowedMethods":["GET"]}`,
+				err:  "could not validate CORS parameters: allowed origins missing",
+			},
+			{
+				name: "invalid max age",
+				expr: `{"allowedOrigins":["*"],"maxAge":-1}`,
+				err:  "could not validate CORS parameters: invalid max age",
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewCors(), tc))
+	}
+}
+
+func Test_corsHandle(t *testing.T) {
+	const (
+		params = `{
+			"allowedOrigins": ["https://*.example.com"],
+			"allowedMethods": ["GET", "POST"],
+			"allowedHeaders": ["Content-Type"],
+			"exposedHeaders": ["X-Request-Id"],
+			"maxAge": 600
+		}`
+	)
+
+	var (
+		handle = func(t *testing.T, r *http.Request) (*httptest.ResponseRecorder, error) {
+			h, err := NewCors().Merge([]byte(params))
+			require.NoError(t, err)
+
+			rc := httptest.NewRecorder()
+
+			// set by the default CORS rules
+			rc.Header().Set("Access-Control-Allow-Origin", "https://other.com")
+
+			return rc, h.Handler()(rc, r)
+		}
+	)
+
+	t.Run("preflight allowed origin", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			r   = httptest.NewRequest(http.MethodOptions, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Origin", "https://app.example.com")
+		r.Header.Set("Access-Control-Request-Method", "POST")
+		r.Header.Set("Access-Control-Request-Headers", "Content-Type")
+
+		rc, err := handle(t, r)
+
+		req.True(err == types.ErrStopProcessing)
+		req.Equal(http.StatusNoContent, rc.Code)
+		req.Equal("https://app.example.com", rc.Header().Get("Access-Control-Allow-Origin"))
+		req.Equal("POST", rc.Header().Get("Access-Control-Allow-Methods"))
+		req.Equal("Content-Type", rc.Header().Get("Access-Control-Allow-Headers"))
+		req.Equal("600", rc.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("preflight disallowed method", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			r   = httptest.NewRequest(http.MethodOptions, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Origin", "https://app.example.com")
+		r.Header.Set("Access-Control-Request-Method", "DELETE")
+
+		rc, err := handle(t, r)
+
+		req.True(err == types.ErrStopProcessing)
+		req.Empty(rc.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("actual request allowed origin", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			r   = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Origin", "https://app.example.com")
+
+		rc, err := handle(t, r)
+
+		req.NoError(err)
+		req.Equal("https://app.example.com", rc.Header().Get("Access-Control-Allow-Origin"))
+		req.Equal("X-Request-Id", rc.Header().Get("Access-Control-Expose-Headers"))
+	})
+
+	t.Run("actual request disallowed origin", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			r   = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+		)
+
+		r.Header.Set("Origin", "https://other.com")
+
+		rc, err := handle(t, r)
+
+		req.NoError(err)
+		req.Empty(rc.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
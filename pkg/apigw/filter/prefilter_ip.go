@@ -0,0 +1,180 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+)
+
+type (
+	ipFilter struct {
+		types.FilterMeta
+		allow  []*net.IPNet
+		deny   []*net.IPNet
+		params struct {
+			Allow []string `json:"allow"`
+			Deny  []string `json:"deny"`
+		}
+	}
+)
+
+func NewIpFilter() (v *ipFilter) {
+	v = &ipFilter{}
+
+	v.Name = "ipFilter"
+	v.Label = "IP allow/deny list"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "list",
+			Label:   "allow",
+			Example: `["10.0.0.0/8", "192.168.1.10"]`,
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "list",
+			Label:   "deny",
+			Example: `["10.0.12.0/24"]`,
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h ipFilter) New() types.Handler {
+	return NewIpFilter()
+}
+
+func (h ipFilter) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h ipFilter) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *ipFilter) Merge(params []byte) (types.Handler, error) {
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&h.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(h.params.Allow) == 0 && len(h.params.Deny) == 0 {
+		return nil, fmt.Errorf("could not validate IP filter parameters: allow or deny list required")
+	}
+
+	if h.allow, err = parseNetworks(h.params.Allow); err != nil {
+		return nil, fmt.Errorf("could not validate IP filter parameters: %s", err)
+	}
+
+	if h.deny, err = parseNetworks(h.params.Deny); err != nil {
+		return nil, fmt.Errorf("could not validate IP filter parameters: %s", err)
+	}
+
+	return h, nil
+}
+
+// Handler rejects requests from denied addresses
+// and addresses not on the allow list (when set)
+//
+// Deny list takes precedence over allow list
+func (h ipFilter) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		ip := clientIP(r)
+
+		if ip == nil {
+			return respondWithError(rw, http.StatusForbidden, "could not determine client address")
+		}
+
+		if containsIP(h.deny, ip) {
+			return respondWithError(rw, http.StatusForbidden, "client address not allowed")
+		}
+
+		if len(h.allow) > 0 && !containsIP(h.allow, ip) {
+			return respondWithError(rw, http.StatusForbidden, "client address not allowed")
+		}
+
+		return nil
+	}
+}
+
+// parseNetworks parses list of CIDRs or single IP addresses
+func parseNetworks(ss []string) (nn []*net.IPNet, err error) {
+	for _, s := range ss {
+		var (
+			n *net.IPNet
+		)
+
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid address %q", s)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			nn = append(nn, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		if _, n, err = net.ParseCIDR(s); err != nil {
+			return nil, fmt.Errorf("invalid network %q", s)
+		}
+
+		nn = append(nn, n)
+	}
+
+	return
+}
+
+func containsIP(nn []*net.IPNet, ip net.IP) bool {
+	for _, n := range nn {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clientIP returns address of the client
+//
+// Remote address is already resolved from X-Forwarded-For and X-Real-IP
+// headers by the base middleware; headers are honoured only on requests
+// from trusted proxies (see HTTP_TRUSTED_PROXIES)
+func clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(strings.TrimSpace(host))
+}
+
+// respondWithError writes error response and
+// stops further processing of the request
+func respondWithError(rw http.ResponseWriter, status int, msg string) error {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	_ = json.NewEncoder(rw).Encode(map[string]interface{}{
+		"error": map[string]string{"message": msg},
+	})
+
+	return types.ErrStopProcessing
+}
@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ipFilterMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "allow list",
+				expr: `{"allow":["10.0.0.0/8", "192.168.1.10", "::1"]}`,
+			},
+			{
+				name: "empty lists",
+				expr: `{"allow":[],"deny":[]}`,
+				err:  "could not validate IP filter parameters: allow or deny list required",
+			},
+			{
+				name: "invalid network",
+				expr: `{"deny":["10.0.0.0/33"]}`,
+				err:  `could not validate IP filter parameters: invalid network "10.0.0.0/33"`,
+			},
+			{
+				name: "invalid address",
+				expr: `{"allow":["foo"]}`,
+				err:  `could not validate IP filter parameters: invalid address "foo"`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewIpFilter(), tc))
+	}
+}
+
+func Test_ipFilterHandle(t *testing.T) {
+	type (
+		tfIP struct {
+			name   string
+			params string
+			remote string
+			status int
+		}
+	)
+
+	var (
+		tcc = []tfIP{
+			{
+				name:   "allowed network",
+				params: `{"allow":["10.0.0.0/8"]}`,
+				remote: "10.1.2.3:4321",
+				status: http.StatusOK,
+			},
+			{
+				name:   "not on allow list",
+				params: `{"allow":["10.0.0.0/8"]}`,
+				remote: "192.168.1.1:4321",
+				status: http.StatusForbidden,
+			},
+			{
+				name:   "denied network",
+				params: `{"deny":["10.0.12.0/24"]}`,
+				remote: "10.0.12.1:4321",
+				status: http.StatusForbidden,
+			},
+			{
+				name:   "not on deny list",
+				params: `{"deny":["10.0.12.0/24"]}`,
+				remote: "10.0.13.1:4321",
+				status: http.StatusOK,
+			},
+			{
+				name:   "deny list takes precedence",
+				params: `{"allow":["10.0.0.0/8"],"deny":["10.0.12.1"]}`,
+				remote: "10.0.12.1:4321",
+				status: http.StatusForbidden,
+			},
+			{
+				name:   "address without port",
+				params: `{"allow":["192.168.1.10"]}`,
+				remote: "192.168.1.10",
+				status: http.StatusOK,
+			},
+			{
+				name:   "ipv6 address",
+				params: `{"allow":["::1"]}`,
+				remote: "[::1]:4321",
+				status: http.StatusOK,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				rc  = httptest.NewRecorder()
+				r   = httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+			)
+
+			r.RemoteAddr = tc.remote
+
+			h, err := NewIpFilter().Merge([]byte(tc.params))
+			req.NoError(err)
+
+			err = h.Handler()(rc, r)
+
+			req.Equal(tc.status, rc.Code)
+			if tc.status != http.StatusOK {
+				req.Error(err)
+				req.Contains(rc.Body.String(), "client address not allowed")
+			} else {
+				req.NoError(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+)
+
+type (
+	rateLimit struct {
+		types.FilterMeta
+		buckets *tokenBuckets
+		params  struct {
+			Rate   float64 `json:"rate"`
+			Period string  `json:"period"`
+			Burst  int     `json:"burst"`
+			Key    string  `json:"key"`
+			Header string  `json:"header"`
+		}
+	}
+
+	// tokenBuckets holds one token bucket per key
+	//
+	// Buckets are refilled lazily, on every take
+	tokenBuckets struct {
+		mux   sync.Mutex
+		rate  float64 // tokens per second
+		burst float64
+		bb    map[string]*tokenBucket
+		now   func() time.Time
+	}
+
+	tokenBucket struct {
+		tokens float64
+		last   time.Time
+	}
+)
+
+const (
+	rateLimitKeyIP       = "ip"
+	rateLimitKeyHeader   = "header"
+	rateLimitKeyIdentity = "identity"
+
+	// buckets are pruned when there are more than this many
+	rateLimitPruneSize = 10000
+)
+
+func NewRateLimit() (v *rateLimit) {
+	v = &rateLimit{}
+
+	v.Name = "rateLimit"
+	v.Label = "Rate limit"
+	v.Kind = types.PreFilter
+
+	v.Args = []*types.FilterMetaArg{
+		{
+			Type:    "number",
+			Label:   "rate",
+			Example: "100",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "period",
+			Example: "1m",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "number",
+			Label:   "burst",
+			Example: "10",
+			Options: map[string]interface{}{},
+		},
+		{
+			Type:    "text",
+			Label:   "key",
+			Example: rateLimitKeyIP,
+			Options: map[string]interface{}{
+				"values": []string{rateLimitKeyIP, rateLimitKeyHeader, rateLimitKeyIdentity},
+			},
+		},
+		{
+			Type:    "text",
+			Label:   "header",
+			Example: "X-Api-Key",
+			Options: map[string]interface{}{},
+		},
+	}
+
+	return
+}
+
+func (h rateLimit) New() types.Handler {
+	return NewRateLimit()
+}
+
+func (h rateLimit) String() string {
+	return fmt.Sprintf("apigw filter %s (%s)", h.Name, h.Label)
+}
+
+func (h rateLimit) Meta() types.FilterMeta {
+	return h.FilterMeta
+}
+
+func (h *rateLimit) Merge(params []byte) (types.Handler, error) {
+	var (
+		period = time.Second
+	)
+
+	err := json.NewDecoder(bytes.NewBuffer(params)).Decode(&h.params)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if h.params.Rate <= 0 {
+		return nil, fmt.Errorf("could not validate rate limit parameters: rate must be positive")
+	}
+
+	if h.params.Period != "" {
+		if period, err = time.ParseDuration(h.params.Period); err != nil || period <= 0 {
+			return nil, fmt.Errorf("could not validate rate limit parameters: invalid period %q", h.params.Period)
+		}
+	}
+
+	if h.params.Burst < 0 {
+		return nil, fmt.Errorf("could not validate rate limit parameters: burst must not be negative")
+	}
+
+	if h.params.Burst == 0 {
+		// when not set, allow whole rate in one go
+		h.params.Burst = int(math.Ceil(h.params.Rate))
+	}
+
+	switch h.params.Key {
+	case "":
+		h.params.Key = rateLimitKeyIP
+	case rateLimitKeyIP, rateLimitKeyIdentity:
+	case rateLimitKeyHeader:
+		if h.params.Header == "" {
+			return nil, fmt.Errorf("could not validate rate limit parameters: header name missing")
+		}
+	default:
+		return nil, fmt.Errorf("could not validate rate limit parameters: invalid key %q", h.params.Key)
+	}
+
+	h.buckets = newTokenBuckets(h.params.Rate/period.Seconds(), h.params.Burst)
+
+	return h, nil
+}
+
+// Handler rejects requests with 429 when bucket for the
+// request's key is empty
+func (h rateLimit) Handler() types.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) error {
+		ok, remaining, retry := h.buckets.take(h.key(r))
+
+		rw.Header().Set("X-RateLimit-Limit", strconv.Itoa(h.params.Burst))
+		rw.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		if ok {
+			return nil
+		}
+
+		rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+		return respondWithError(rw, http.StatusTooManyRequests, "rate limit exceeded")
+	}
+}
+
+// key returns rate limiting key for the request
+//
+// Identity key falls back to the client address for
+// requests without an authenticated user
+func (h rateLimit) key(r *http.Request) string {
+	switch h.params.Key {
+	case rateLimitKeyHeader:
+		return "header:" + strings.TrimSpace(r.Header.Get(h.params.Header))
+
+	case rateLimitKeyIdentity:
+		i := auth.GetIdentityFromContext(r.Context())
+		if su := auth.ServiceUser(); i.Valid() && (su == nil || su.ID != i.Identity()) {
+			return "identity:" + strconv.FormatUint(i.Identity(), 10)
+		}
+	}
+
+	if ip := clientIP(r); ip != nil {
+		return "ip:" + ip.String()
+	}
+
+	return "ip:" + r.RemoteAddr
+}
+
+func newTokenBuckets(rate float64, burst int) *tokenBuckets {
+	return &tokenBuckets{
+		rate:  rate,
+		burst: float64(burst),
+		bb:    make(map[string]*tokenBucket),
+		now:   time.Now,
+	}
+}
+
+// take removes one token from the key's bucket
+//
+// Returns number of remaining tokens and, when
+// bucket is empty, time until the next token is available
+func (tb *tokenBuckets) take(key string) (ok bool, remaining int, retry time.Duration) {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+
+	var (
+		now = tb.now()
+	)
+
+	b, has := tb.bb[key]
+	if !has {
+		if len(tb.bb) >= rateLimitPruneSize {
+			tb.prune(now)
+		}
+
+		b = &tokenBucket{tokens: tb.burst, last: now}
+		tb.bb[key] = b
+	}
+
+	b.tokens = math.Min(tb.burst, b.tokens+now.Sub(b.last).Seconds()*tb.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, 0, time.Duration((1 - b.tokens) / tb.rate * float64(time.Second))
+	}
+
+	b.tokens--
+	return true, int(b.tokens), 0
+}
+
+// prune removes buckets that are full again;
+// they are no different from a new bucket
+func (tb *tokenBuckets) prune(now time.Time) {
+	for k, b := range tb.bb {
+		if b.tokens+now.Sub(b.last).Seconds()*tb.rate >= tb.burst {
+			delete(tb.bb, k)
+		}
+	}
+}
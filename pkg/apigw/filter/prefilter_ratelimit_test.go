@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rateLimitMerge(t *testing.T) {
+	var (
+		tcc = []tf{
+			{
+				name: "defaults",
+				expr: `{"rate":10}`,
+			},
+			{
+				name: "header key",
+				expr: `{"rate":10,"period":"1m","burst":5,"key":"header","header":"X-Api-Key"}`,
+			},
+			{
+				name: "missing rate",
+				expr: `{"burst":5}`,
+				err:  "could not validate rate limit parameters: rate must be positive",
+			},
+			{
+				name: "invalid period",
+				expr: `{"rate":10,"period":"foo"}`,
+				err:  `could not validate rate limit parameters: invalid period "foo"`,
+			},
+			{
+				name: "missing header name",
+				expr: `{"rate":10,"key":"header"}`,
+				err:  "could not validate rate limit parameters: header name missing",
+			},
+			{
+				name: "invalid key",
+				expr: `{"rate":10,"key":"foo"}`,
+				err:  `could not validate rate limit parameters: invalid key "foo"`,
+			},
+		}
+	)
+
+	for _, tc := range tcc {
+		t.Run(tc.name, testMerge(NewRateLimit(), tc))
+	}
+}
+
+func Test_rateLimitHandle(t *testing.T) {
+	var (
+		prepare = func(t *testing.T, params string) (*rateLimit, *time.Time) {
+			h, err := NewRateLimit().Merge([]byte(params))
+			require.NoError(t, err)
+
+			var (
+				rl  = h.(*rateLimit)
+				now = time.Now()
+			)
+
+			rl.buckets.now = func() time.Time { return now }
+			return rl, &now
+		}
+
+		do = func(h *rateLimit, r *http.Request) *httptest.ResponseRecorder {
+			rc := httptest.NewRecorder()
+			_ = h.Handler()(rc, r)
+			return rc
+		}
+
+		request = func(remote string) *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+			r.RemoteAddr = remote
+			return r
+		}
+	)
+
+	t.Run("burst and refill", func(t *testing.T) {
+		var (
+			req    = require.New(t)
+			h, now = prepare(t, `{"rate":1,"period":"1s","burst":2}`)
+		)
+
+		req.Equal(http.StatusOK, do(h, request("10.0.0.1:1234")).Code)
+
+		rc := do(h, request("10.0.0.1:1234"))
+		req.Equal(http.StatusOK, rc.Code)
+		req.Equal("0", rc.Header().Get("X-RateLimit-Remaining"))
+
+		rc = do(h, request("10.0.0.1:1234"))
+		req.Equal(http.StatusTooManyRequests, rc.Code)
+		req.Equal("1", rc.Header().Get("Retry-After"))
+
+		// other clients have their own bucket
+		req.Equal(http.StatusOK, do(h, request("10.0.0.2:1234")).Code)
+
+		*now = now.Add(time.Second)
+		req.Equal(http.StatusOK, do(h, request("10.0.0.1:1234")).Code)
+		req.Equal(http.StatusTooManyRequests, do(h, request("10.0.0.1:1234")).Code)
+	})
+
+	t.Run("keyed by header", func(t *testing.T) {
+		var (
+			req  = require.New(t)
+			h, _ = prepare(t, `{"rate":1,"key":"header","header":"X-Api-Key"}`)
+
+			withKey = func(key string) *http.Request {
+				r := request("10.0.0.1:1234")
+				r.Header.Set("X-Api-Key", key)
+				return r
+			}
+		)
+
+		req.Equal(http.StatusOK, do(h, withKey("a")).Code)
+		req.Equal(http.StatusTooManyRequests, do(h, withKey("a")).Code)
+		req.Equal(http.StatusOK, do(h, withKey("b")).Code)
+	})
+
+	t.Run("keyed by identity", func(t *testing.T) {
+		var (
+			req  = require.New(t)
+			h, _ = prepare(t, `{"rate":1,"key":"identity"}`)
+
+			as = func(userID uint64) *http.Request {
+				r := request("10.0.0.1:1234")
+				return r.WithContext(auth.SetIdentityToContext(r.Context(), auth.Authenticated(userID)))
+			}
+		)
+
+		req.Equal(http.StatusOK, do(h, as(1)).Code)
+		req.Equal(http.StatusTooManyRequests, do(h, as(1)).Code)
+		req.Equal(http.StatusOK, do(h, as(2)).Code)
+
+		// anonymous requests fall back to client address
+		req.Equal(http.StatusOK, do(h, request("10.0.0.1:1234")).Code)
+		req.Equal(http.StatusTooManyRequests, do(h, request("10.0.0.1:1234")).Code)
+	})
+}
+
+func Test_tokenBucketsPrune(t *testing.T) {
+	var (
+		req = require.New(t)
+		tb  = newTokenBuckets(1, 1)
+		now = time.Now()
+	)
+
+	tb.now = func() time.Time { return now }
+
+	tb.take("a")
+	tb.take("b")
+	req.Len(tb.bb, 2)
+
+	now = now.Add(time.Second)
+	tb.prune(now)
+	req.Len(tb.bb, 0)
+}
@@ -1,6 +1,7 @@
 package proxy
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -78,6 +79,12 @@ type (
 )
 
 func NewProxyAuthServicer(c *http.Client, p ProxyAuthParams, s types.SecureStorager) (ProxyAuthServicer, error) {
+	var err error
+
+	if p.Params, err = resolveSecrets(context.Background(), p.Params, s); err != nil {
+		return nil, err
+	}
+
 	switch p.Type {
 	case proxyAuthTypeHeader:
 		return newProxyAuthHeader(p)
@@ -94,6 +101,42 @@ func NewProxyAuthServicer(c *http.Client, p ProxyAuthParams, s types.SecureStora
 	}
 }
 
+// resolveSecrets replaces secret references in auth params with secret values
+//
+// Secret is referenced by its handle with {"secret": "handle"} in place of the value
+// so that credentials are not stored in plain filter params
+func resolveSecrets(ctx context.Context, params map[string]interface{}, s types.SecureStorager) (out map[string]interface{}, err error) {
+	if len(params) == 0 {
+		return params, nil
+	}
+
+	out = make(map[string]interface{}, len(params))
+
+	for k, v := range params {
+		out[k] = v
+
+		ref, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		handle, ok := ref["secret"].(string)
+		if !ok {
+			continue
+		}
+
+		if s == nil {
+			return nil, fmt.Errorf("could not resolve secret for param %s: secure storage not available", k)
+		}
+
+		if out[k], err = s.Secret(ctx, handle); err != nil {
+			return nil, fmt.Errorf("could not resolve secret for param %s: %w", k, err)
+		}
+	}
+
+	return
+}
+
 func newProxyAuthHeader(p ProxyAuthParams) (s proxyAuthServicerHeader, err error) {
 	s = proxyAuthServicerHeader{
 		params: p.Params,
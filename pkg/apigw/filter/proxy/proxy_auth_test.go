@@ -76,6 +76,39 @@ func Test_authDo(t *testing.T) {
 				exp:  http.Header{},
 				errv: "invalid param password",
 			},
+			{
+				name: "auth basic with password from secure storage",
+				params: ProxyAuthParams{
+					Type: proxyAuthTypeBasic,
+					Params: map[string]interface{}{
+						"username": "user",
+						"password": map[string]interface{}{"secret": "basic-pass"},
+					},
+				},
+				exp: http.Header{"Authorization": []string{"Basic dXNlcjpwYXNzMTIzNA=="}},
+			},
+			{
+				name: "auth header with value from secure storage",
+				params: ProxyAuthParams{
+					Type: proxyAuthTypeHeader,
+					Params: map[string]interface{}{
+						"X-Api-Key": map[string]interface{}{"secret": "api-key"},
+					},
+				},
+				exp: http.Header{"X-Api-Key": []string{"key1234"}},
+			},
+			{
+				name: "auth basic with missing secret",
+				params: ProxyAuthParams{
+					Type: proxyAuthTypeBasic,
+					Params: map[string]interface{}{
+						"username": "user",
+						"password": map[string]interface{}{"secret": "missing"},
+					},
+				},
+				exp:  http.Header{},
+				errv: `could not resolve secret for param password: secret "missing" not found`,
+			},
 			{
 				name:   "noop default fallback",
 				params: ProxyAuthParams{},
@@ -105,7 +138,10 @@ func Test_authDo(t *testing.T) {
 
 			rq, _ := http.NewRequest("POST", "/foo", http.NoBody)
 
-			authServicer, err := NewProxyAuthServicer(c, tc.params, struct{}{})
+			authServicer, err := NewProxyAuthServicer(c, tc.params, types.MockSecureStorager{
+				"basic-pass": "pass1234",
+				"api-key":    "key1234",
+			})
 
 			if tc.errv != "" {
 				req.EqualError(err, tc.errv)
@@ -0,0 +1,69 @@
+package apigw
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	identityStorer interface {
+		LookupUserByID(ctx context.Context, id uint64) (*st.User, error)
+		LookupUserByEmail(ctx context.Context, email string) (*st.User, error)
+		LookupUserByHandle(ctx context.Context, handle string) (*st.User, error)
+		SearchRoleMembers(ctx context.Context, f st.RoleMemberFilter) (st.RoleMemberSet, st.RoleMemberFilter, error)
+	}
+
+	// identityResolver maps authenticated callers
+	// of API Gateway routes to Corteza users
+	identityResolver struct {
+		storer identityStorer
+	}
+)
+
+func (ir identityResolver) ResolveIdentity(ctx context.Context, lookup, value string) (auth.Identifiable, error) {
+	var (
+		u   *st.User
+		err error
+	)
+
+	switch lookup {
+	case types.IdentityLookupID:
+		var userID uint64
+		if userID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid user ID %q", value)
+		}
+
+		u, err = ir.storer.LookupUserByID(ctx, userID)
+	case types.IdentityLookupEmail:
+		u, err = ir.storer.LookupUserByEmail(ctx, value)
+	case types.IdentityLookupHandle:
+		u, err = ir.storer.LookupUserByHandle(ctx, value)
+	default:
+		return nil, fmt.Errorf("unknown user lookup %q", lookup)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not find user %q: %w", value, err)
+	}
+
+	if !u.Valid() {
+		return nil, fmt.Errorf("user %q is suspended or deleted", value)
+	}
+
+	mm, _, err := ir.storer.SearchRoleMembers(ctx, st.RoleMemberFilter{UserID: u.ID})
+	if err != nil {
+		return nil, fmt.Errorf("could not load roles of user %q: %w", value, err)
+	}
+
+	rr := make([]uint64, 0, len(mm))
+	for _, m := range mm {
+		rr = append(rr, m.RoleID)
+	}
+
+	return auth.Authenticated(u.ID, rr...), nil
+}
@@ -0,0 +1,60 @@
+package apigw
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	st "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_identityResolver(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		suspended = time.Now()
+
+		ir = identityResolver{storer: &types.MockStorer{
+			U: func(context.Context, st.UserFilter) (st.UserSet, st.UserFilter, error) {
+				return st.UserSet{
+					{ID: 1, Email: "active@example.com", Handle: "active"},
+					{ID: 2, Email: "suspended@example.com", SuspendedAt: &suspended},
+				}, st.UserFilter{}, nil
+			},
+			M: func(_ context.Context, f st.RoleMemberFilter) (st.RoleMemberSet, st.RoleMemberFilter, error) {
+				return st.RoleMemberSet{{UserID: f.UserID, RoleID: 10}}, f, nil
+			},
+		}}
+	)
+
+	t.Run("lookups", func(t *testing.T) {
+		req := require.New(t)
+
+		for lookup, value := range map[string]string{
+			types.IdentityLookupID:     "1",
+			types.IdentityLookupEmail:  "active@example.com",
+			types.IdentityLookupHandle: "active",
+		} {
+			i, err := ir.ResolveIdentity(ctx, lookup, value)
+			req.NoError(err)
+			req.Equal(uint64(1), i.Identity())
+			req.Contains(i.Roles(), uint64(10))
+		}
+	})
+
+	t.Run("suspended user", func(t *testing.T) {
+		_, err := ir.ResolveIdentity(ctx, types.IdentityLookupEmail, "suspended@example.com")
+		require.EqualError(t, err, `user "suspended@example.com" is suspended or deleted`)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, err := ir.ResolveIdentity(ctx, types.IdentityLookupEmail, "unknown@example.com")
+		require.Error(t, err)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		_, err := ir.ResolveIdentity(ctx, types.IdentityLookupID, "foo")
+		require.EqualError(t, err, `invalid user ID "foo"`)
+	})
+}
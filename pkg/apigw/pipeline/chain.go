@@ -17,3 +17,8 @@ func (cc chiHandlerChain) Handler() (h http.Handler) {
 		Chain(cc.chain...).
 		HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {})
 }
+
+// Wrap chains handlers in front of the given handler
+func (cc chiHandlerChain) Wrap(h http.Handler) http.Handler {
+	return chi.Chain(cc.chain...).Handler(h)
+}
@@ -2,6 +2,7 @@ package pipeline
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"sort"
 	"time"
@@ -19,6 +20,10 @@ type (
 		Name    string
 		Type    types.FilterKind
 		Handler func(rw http.ResponseWriter, r *http.Request) error
+
+		// Wrap is set on workers that wrap processers
+		// and postfilters (see types.RouteWrapper)
+		Wrap func(next http.Handler) http.Handler
 	}
 
 	workerSet []*Worker
@@ -63,13 +68,41 @@ func (pp *Pl) Add(w *Worker) {
 
 // Handler is the main operating entry point for requests
 // that handles filter groups
+//
+// Wrapping workers are placed between prefilters and the
+// rest of the chain; prefilters (authentication, rate limits...)
+// are always executed
 func (pp *Pl) Handler() http.Handler {
-	// use the chi implementation of chains
-	chiChain := chiHandlerChain{
-		chain: pp.makeMiddleware(pp.workers...),
+	var (
+		wrappers, _ = pp.workers.Filter(func(w *Worker) (bool, error) { return w.Wrap != nil, nil })
+	)
+
+	if len(wrappers) == 0 {
+		// use the chi implementation of chains
+		chiChain := chiHandlerChain{
+			chain: pp.makeMiddleware(pp.workers...),
+		}
+
+		return chiChain.Handler()
 	}
 
-	return chiChain.Handler()
+	var (
+		pre, _ = pp.workers.Filter(func(w *Worker) (bool, error) {
+			return w.Wrap == nil && w.Type == types.PreFilter, nil
+		})
+
+		rest, _ = pp.workers.Filter(func(w *Worker) (bool, error) {
+			return w.Wrap == nil && w.Type != types.PreFilter, nil
+		})
+
+		h = chiHandlerChain{chain: pp.makeMiddleware(rest...)}.Handler()
+	)
+
+	for i := len(wrappers) - 1; i >= 0; i-- {
+		h = wrappers[i].Wrap(h)
+	}
+
+	return chiHandlerChain{chain: pp.makeMiddleware(pre...)}.Wrap(h)
 }
 
 // makeMiddleware creates a list of handlers from workers
@@ -125,16 +158,35 @@ func (pp *Pl) makeHandler(hh Worker) func(next http.Handler) http.Handler {
 				err = fn()
 			}
 
-			if err != nil {
+			if errors.Is(err, types.ErrStopProcessing) {
+				log.Debug("stopped processing")
+				return
+			} else if err != nil {
 				pp.err(rw, r, err)
 				return
 			} else {
-				next.ServeHTTP(rw, r)
+				next.ServeHTTP(rw, withScopeIdentity(r))
 			}
 		})
 	}
 }
 
+// withScopeIdentity sets identity from the scope (when set by
+// one of the filters) to the request context, so the rest of
+// the pipeline runs as the authenticated user
+func withScopeIdentity(r *http.Request) *http.Request {
+	var (
+		ctx = r.Context()
+		i   = actx.ScopeFromContext(ctx).Identity()
+	)
+
+	if i == nil {
+		return r
+	}
+
+	return r.WithContext(auth.SetIdentityToContext(ctx, i))
+}
+
 func (a workerSet) Len() int { return len(a) }
 func (a workerSet) Less(i, j int) bool {
 	return a[i].Weight < a[j].Weight
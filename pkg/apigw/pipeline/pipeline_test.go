@@ -2,11 +2,14 @@ package pipeline
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	actx "github.com/cortezaproject/corteza-server/pkg/apigw/ctx"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -72,6 +75,140 @@ func Test_pipelineHandleMultiple(t *testing.T) {
 	req.Equal(`secondfirst`, rr.Body.String())
 }
 
+func Test_pipelineStopProcessing(t *testing.T) {
+	var (
+		req = require.New(t)
+		rr  = httptest.NewRecorder()
+		p   = NewPl()
+
+		first = types.MockHandler{
+			Handler_: func(rw http.ResponseWriter, r *http.Request) error {
+				rw.WriteHeader(http.StatusNoContent)
+				return types.ErrStopProcessing
+			},
+		}
+
+		second = types.MockHandler{
+			Handler_: func(rw http.ResponseWriter, r *http.Request) error {
+				rw.Write([]byte(`second`))
+				return nil
+			},
+		}
+	)
+
+	p.Add(&Worker{
+		Handler: first.Handler(),
+		Weight:  0,
+		Name:    "mockHandler",
+	})
+
+	p.Add(&Worker{
+		Handler: second.Handler(),
+		Weight:  5,
+		Name:    "mockHandler",
+	})
+
+	p.Handler().ServeHTTP(rr, &http.Request{})
+
+	req.Equal(http.StatusNoContent, rr.Code)
+	req.Empty(rr.Body.String())
+}
+
+func Test_pipelineScopeIdentity(t *testing.T) {
+	var (
+		req   = require.New(t)
+		rr    = httptest.NewRecorder()
+		p     = NewPl()
+		scope = &types.Scp{}
+
+		authenticate = types.MockHandler{
+			Handler_: func(rw http.ResponseWriter, r *http.Request) error {
+				actx.ScopeFromContext(r.Context()).Set("identity", auth.Authenticated(42))
+				return nil
+			},
+		}
+
+		process = types.MockHandler{
+			Handler_: func(rw http.ResponseWriter, r *http.Request) error {
+				rw.Write([]byte(fmt.Sprintf("%d", auth.GetIdentityFromContext(r.Context()).Identity())))
+				return nil
+			},
+		}
+	)
+
+	p.Add(&Worker{
+		Handler: authenticate.Handler(),
+		Weight:  0,
+		Name:    "mockHandler",
+	})
+
+	p.Add(&Worker{
+		Handler: process.Handler(),
+		Weight:  5,
+		Name:    "mockHandler",
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/foo", http.NoBody)
+	r = r.WithContext(actx.ScopeToContext(r.Context(), scope))
+
+	p.Handler().ServeHTTP(rr, r)
+
+	req.Equal(`42`, rr.Body.String())
+}
+
+func Test_pipelineWrap(t *testing.T) {
+	var (
+		req = require.New(t)
+		rr  = httptest.NewRecorder()
+		p   = NewPl()
+
+		write = func(s string) types.HandlerFunc {
+			return func(rw http.ResponseWriter, r *http.Request) error {
+				rw.Write([]byte(s))
+				return nil
+			}
+		}
+	)
+
+	p.Add(&Worker{
+		Handler: write("pre"),
+		Weight:  0,
+		Type:    types.PreFilter,
+		Name:    "mockHandler",
+	})
+
+	p.Add(&Worker{
+		Handler: write("proc"),
+		Weight:  100,
+		Type:    types.Processer,
+		Name:    "mockHandler",
+	})
+
+	p.Add(&Worker{
+		Handler: write("post"),
+		Weight:  200,
+		Type:    types.PostFilter,
+		Name:    "mockHandler",
+	})
+
+	p.Add(&Worker{
+		Weight: 201,
+		Type:   types.PostFilter,
+		Name:   "mockWrapper",
+		Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.Write([]byte("["))
+				next.ServeHTTP(rw, r)
+				rw.Write([]byte("]"))
+			})
+		},
+	})
+
+	p.Handler().ServeHTTP(rr, &http.Request{})
+
+	req.Equal(`pre[procpost]`, rr.Body.String())
+}
+
 func Test_pipelineExecErr(t *testing.T) {
 	type (
 		tf struct {
@@ -16,8 +16,6 @@ type (
 	Registry struct {
 		h map[string]types.Handler
 	}
-
-	secureStorageTodo struct{}
 )
 
 func NewRegistry() *Registry {
@@ -57,24 +55,36 @@ func (r *Registry) All() (list types.FilterMetaList) {
 	return
 }
 
-func (r *Registry) Preload() {
+// Preload registers all available filters
+//
+// Secure storage is used by filters that need credentials (proxy auth),
+// identity resolver maps authenticated callers to users (auth prefilter),
+// response caches (by storage type) are used by response cache postfilter
+func (r *Registry) Preload(ss types.SecureStorager, ir types.IdentityResolver, rc map[string]types.ResponseCache) {
 	// prefilters
 	r.Add("queryParam", filter.NewQueryParam())
 	r.Add("header", filter.NewHeader())
+	r.Add("rateLimit", filter.NewRateLimit())
+	r.Add("cors", filter.NewCors())
+	r.Add("ipFilter", filter.NewIpFilter())
+
+	// Corteza access tokens are validated with the default JWT handler
+	r.Add("auth", filter.NewAuth(http.DefaultClient, nil, ir))
 
 	// processers
 	r.Add("workflow", filter.NewWorkflow(NewWorkflow()))
-	r.Add("proxy", proxy.New(service.DefaultLogger, http.DefaultClient, secureStorageTodo{}))
+	r.Add("proxy", proxy.New(service.DefaultLogger, http.DefaultClient, ss))
 	r.Add("payload", filter.NewPayload(service.DefaultLogger))
 
 	// postfilters
 	r.Add("redirection", filter.NewRedirection())
 	r.Add("jsonResponse", filter.NewJsonResponse(service.Registry()))
 	r.Add("defaultJsonResponse", filter.NewDefaultJsonResponse())
+	r.Add("responseCache", filter.NewResponseCache(rc))
 }
 
 func NewWorkflow() (wf filter.WfExecer) {
 	// implementation assumes that Corredor & Workflow options can not be changed
 	// in the run-time.
-	return service.Workflow(logger.Default().Named("workflow"), *options.Corredor(), *options.Workflow())
+	return service.Workflow(logger.Default().Named("workflow"), *options.Corredor(), *options.Workflow(), service.WorkflowLimits{})
 }
@@ -30,6 +30,10 @@ type (
 	routeMeta struct {
 		debug bool
 		async bool
+
+		// route has CORS prefilter that
+		// answers preflight requests
+		preflight bool
 	}
 )
 
@@ -0,0 +1,190 @@
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// Keyring holds keys for envelope encryption of secrets
+	//
+	// Every secret is encrypted with its own random data key and
+	// data key is encrypted (wrapped) with the current key.
+	// Previous keys are used only for unwrapping data keys of secrets
+	// that were not rotated yet.
+	Keyring struct {
+		current *key
+		keys    map[string]*key
+	}
+
+	key struct {
+		id  string
+		aes []byte
+	}
+)
+
+const (
+	dataKeySize = 32
+)
+
+var (
+	ErrKeyNotConfigured = fmt.Errorf("secure storage key not configured")
+)
+
+// NewKeyring prepares keyring from the current key and
+// comma separated list of previous keys
+func NewKeyring(current, previous string) *Keyring {
+	kr := &Keyring{keys: make(map[string]*key)}
+
+	if current = strings.TrimSpace(current); current != "" {
+		kr.current = makeKey(current)
+		kr.keys[kr.current.id] = kr.current
+	}
+
+	for _, p := range strings.Split(previous, ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+
+		k := makeKey(p)
+		if _, has := kr.keys[k.id]; !has {
+			kr.keys[k.id] = k
+		}
+	}
+
+	return kr
+}
+
+// makeKey derives AES-256 key from the configured value
+//
+// Key ID is derived from the key itself so we know which key
+// was used without storing anything sensitive
+func makeKey(raw string) *key {
+	sum := sha256.Sum256([]byte(raw))
+	id := sha256.Sum256(sum[:])
+
+	return &key{
+		id:  hex.EncodeToString(id[:8]),
+		aes: sum[:],
+	}
+}
+
+// CurrentKeyID returns ID of the key used for encryption
+func (kr *Keyring) CurrentKeyID() string {
+	if kr.current == nil {
+		return ""
+	}
+
+	return kr.current.id
+}
+
+// Seal encrypts plaintext with a new data key
+//
+// Returns ID of the key that wrapped the data key, wrapped data key and ciphertext
+func (kr *Keyring) Seal(plaintext []byte) (keyID string, dataKey, ciphertext []byte, err error) {
+	if kr.current == nil {
+		return "", nil, nil, ErrKeyNotConfigured
+	}
+
+	dk := make([]byte, dataKeySize)
+	if _, err = io.ReadFull(rand.Reader, dk); err != nil {
+		return
+	}
+
+	if ciphertext, err = encrypt(dk, plaintext); err != nil {
+		return
+	}
+
+	if dataKey, err = encrypt(kr.current.aes, dk); err != nil {
+		return
+	}
+
+	return kr.current.id, dataKey, ciphertext, nil
+}
+
+// Open decrypts ciphertext with the data key
+func (kr *Keyring) Open(keyID string, dataKey, ciphertext []byte) ([]byte, error) {
+	dk, err := kr.unwrap(keyID, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return decrypt(dk, ciphertext)
+}
+
+// Rewrap re-encrypts data key with the current key
+//
+// Ciphertext does not change since data key stays the same
+func (kr *Keyring) Rewrap(keyID string, dataKey []byte) (string, []byte, error) {
+	if kr.current == nil {
+		return "", nil, ErrKeyNotConfigured
+	}
+
+	dk, err := kr.unwrap(keyID, dataKey)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if dataKey, err = encrypt(kr.current.aes, dk); err != nil {
+		return "", nil, err
+	}
+
+	return kr.current.id, dataKey, nil
+}
+
+func (kr *Keyring) unwrap(keyID string, dataKey []byte) ([]byte, error) {
+	if len(kr.keys) == 0 {
+		return nil, ErrKeyNotConfigured
+	}
+
+	k, has := kr.keys[keyID]
+	if !has {
+		return nil, fmt.Errorf("secure storage key %q not configured", keyID)
+	}
+
+	return decrypt(k.aes, dataKey)
+}
+
+// encrypt with AES-GCM, nonce is prepended to the ciphertext
+func encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := makeGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func decrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := makeGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+
+	nonce, ciphertext := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func makeGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
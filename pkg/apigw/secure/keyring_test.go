@@ -0,0 +1,85 @@
+package secure
+
+import (
+	"testing"
+
+	st "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring_SealOpen(t *testing.T) {
+	var (
+		req = require.New(t)
+		kr  = NewKeyring("current", "")
+	)
+
+	keyID, dataKey, ciphertext, err := kr.Seal([]byte("s3cr3t"))
+	req.NoError(err)
+	req.Equal(kr.CurrentKeyID(), keyID)
+	req.NotContains(string(ciphertext), "s3cr3t")
+
+	p, err := kr.Open(keyID, dataKey, ciphertext)
+	req.NoError(err)
+	req.Equal("s3cr3t", string(p))
+
+	// tampered ciphertext
+	ciphertext[len(ciphertext)-1] ^= 0xff
+	_, err = kr.Open(keyID, dataKey, ciphertext)
+	req.Error(err)
+
+	// wrong key
+	_, err = NewKeyring("other", "").Open(keyID, dataKey, ciphertext)
+	req.Error(err)
+}
+
+func TestKeyring_NotConfigured(t *testing.T) {
+	var (
+		req = require.New(t)
+		kr  = NewKeyring("", "")
+	)
+
+	_, _, _, err := kr.Seal([]byte("s3cr3t"))
+	req.ErrorIs(err, ErrKeyNotConfigured)
+
+	_, err = kr.Open("", nil, nil)
+	req.ErrorIs(err, ErrKeyNotConfigured)
+}
+
+func TestKeyring_RewrapSecret(t *testing.T) {
+	var (
+		req = require.New(t)
+		old = NewKeyring("old", "")
+		kr  = NewKeyring("new", "foo, old")
+
+		s = &st.ApigwSecret{Handle: "test", Value: "s3cr3t"}
+	)
+
+	req.NoError(old.SealSecret(s))
+	req.Empty(s.Value)
+	req.Equal(old.CurrentKeyID(), s.KeyID)
+
+	ciphertext := s.Ciphertext
+
+	// previous keys can still decrypt
+	v, err := kr.OpenSecret(s)
+	req.NoError(err)
+	req.Equal("s3cr3t", v)
+
+	rotated, err := kr.RewrapSecret(s)
+	req.NoError(err)
+	req.True(rotated)
+	req.Equal(kr.CurrentKeyID(), s.KeyID)
+	req.Equal(ciphertext, s.Ciphertext)
+
+	rotated, err = kr.RewrapSecret(s)
+	req.NoError(err)
+	req.False(rotated)
+
+	// after rotation old key is not needed anymore
+	v, err = NewKeyring("new", "").OpenSecret(s)
+	req.NoError(err)
+	req.Equal("s3cr3t", v)
+
+	_, err = old.OpenSecret(s)
+	req.Error(err)
+}
@@ -0,0 +1,75 @@
+package secure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cortezaproject/corteza-server/store"
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	storer interface {
+		LookupApigwSecretByHandle(ctx context.Context, handle string) (*st.ApigwSecret, error)
+	}
+
+	// Storage provides decrypted secrets to API Gateway filters
+	Storage struct {
+		store storer
+		keys  *Keyring
+	}
+)
+
+func NewStorage(s storer, kr *Keyring) *Storage {
+	return &Storage{store: s, keys: kr}
+}
+
+func (ss *Storage) Secret(ctx context.Context, handle string) (string, error) {
+	s, err := ss.store.LookupApigwSecretByHandle(ctx, handle)
+	if err == store.ErrNotFound {
+		return "", fmt.Errorf("secret %q not found", handle)
+	} else if err != nil {
+		return "", err
+	}
+
+	return ss.keys.OpenSecret(s)
+}
+
+// SealSecret encrypts secret's value
+//
+// Plaintext value is removed from the secret
+func (kr *Keyring) SealSecret(s *st.ApigwSecret) (err error) {
+	if s.KeyID, s.DataKey, s.Ciphertext, err = kr.Seal([]byte(s.Value)); err != nil {
+		return
+	}
+
+	s.Value = ""
+	return
+}
+
+// OpenSecret decrypts secret's value
+func (kr *Keyring) OpenSecret(s *st.ApigwSecret) (string, error) {
+	p, err := kr.Open(s.KeyID, s.DataKey, s.Ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt secret %q: %w", s.Handle, err)
+	}
+
+	return string(p), nil
+}
+
+// RewrapSecret re-encrypts secret's data key with the current key
+//
+// Returns false when secret is already encrypted with the current key
+func (kr *Keyring) RewrapSecret(s *st.ApigwSecret) (bool, error) {
+	if s.KeyID == kr.CurrentKeyID() {
+		return false, nil
+	}
+
+	keyID, dataKey, err := kr.Rewrap(s.KeyID, s.DataKey)
+	if err != nil {
+		return false, fmt.Errorf("could not rotate key of secret %q: %w", s.Handle, err)
+	}
+
+	s.KeyID, s.DataKey = keyID, dataKey
+	return true, nil
+}
@@ -7,10 +7,13 @@ import (
 	"math"
 	"net/http"
 
+	"github.com/cortezaproject/corteza-server/pkg/api/server"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/cache"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/filter"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/filter/proxy"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/pipeline"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/registry"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/secure"
 	"github.com/cortezaproject/corteza-server/pkg/apigw/types"
 	f "github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/options"
@@ -23,6 +26,15 @@ type (
 	storer interface {
 		SearchApigwRoutes(ctx context.Context, f st.ApigwRouteFilter) (st.ApigwRouteSet, st.ApigwRouteFilter, error)
 		SearchApigwFilters(ctx context.Context, f st.ApigwFilterFilter) (st.ApigwFilterSet, st.ApigwFilterFilter, error)
+		LookupApigwSecretByHandle(ctx context.Context, handle string) (*st.ApigwSecret, error)
+
+		LookupApigwCacheEntryByKey(ctx context.Context, key string) (*st.ApigwCacheEntry, error)
+		UpsertApigwCacheEntry(ctx context.Context, rr ...*st.ApigwCacheEntry) error
+		DeleteApigwCacheEntryByKey(ctx context.Context, key string) error
+		DeleteApigwCacheEntriesByRouteID(ctx context.Context, routeID uint64) error
+		DeleteExpiredApigwCacheEntries(ctx context.Context) error
+
+		identityStorer
 	}
 
 	apigw struct {
@@ -32,6 +44,7 @@ type (
 		routes []*route
 		mx     *chi.Mux
 		storer storer
+		caches map[string]types.ResponseCache
 	}
 )
 
@@ -54,14 +67,26 @@ func Setup(opts *options.ApigwOpt, log *zap.Logger, storer storer) {
 }
 
 func New(opts *options.ApigwOpt, logger *zap.Logger, storer storer) *apigw {
-	reg := registry.NewRegistry()
-	reg.Preload()
+	var (
+		reg    = registry.NewRegistry()
+		caches = map[string]types.ResponseCache{
+			types.ResponseCacheMemory: cache.NewMemory(opts.CacheMaxEntries),
+			types.ResponseCacheStore:  cache.NewStore(storer),
+		}
+	)
+
+	reg.Preload(
+		secure.NewStorage(storer, secure.NewKeyring(opts.SecretsKey, opts.SecretsPreviousKeys)),
+		identityResolver{storer: storer},
+		caches,
+	)
 
 	return &apigw{
 		opts:   opts,
 		log:    logger,
 		storer: storer,
 		reg:    reg,
+		caches: caches,
 	}
 }
 
@@ -75,6 +100,13 @@ func (s *apigw) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == http.MethodOptions && !s.mx.Match(chi.NewRouteContext(), r.Method, r.URL.Path) {
+		// preflight requests for routes without
+		// CORS prefilter are handled with default rules
+		server.DefaultCors().Handler(http.NotFoundHandler()).ServeHTTP(w, r)
+		return
+	}
+
 	if len(s.routes) == 0 {
 		helperDefaultResponse(s.opts)(w, r)
 		return
@@ -111,6 +143,11 @@ func (s *apigw) Reload(ctx context.Context) (err error) {
 	for _, r := range s.routes {
 		// Register route handler on endpoint & method
 		s.mx.Method(r.method, r.endpoint, r)
+
+		if r.meta.preflight && r.method != http.MethodOptions {
+			// CORS prefilter answers preflight requests
+			s.mx.Method(http.MethodOptions, r.endpoint, r)
+		}
 	}
 
 	// API GW 404 handler
@@ -174,6 +211,10 @@ func (s *apigw) Init(ctx context.Context, routes ...*route) {
 
 			pipe.Add(ff)
 
+			if rf.Ref == "cors" {
+				r.meta.preflight = true
+			}
+
 			flog.Debug("registered filter")
 		}
 
@@ -226,6 +267,23 @@ func (s *apigw) registerFilter(f *st.ApigwFilter, r *route) (ff *pipeline.Worker
 		Weight:  filter.FilterWeight(int(f.Weight), types.FilterKind(f.Kind)),
 	}
 
+	if w, ok := handler.(types.RouteWrapper); ok {
+		ff.Wrap = func(next http.Handler) http.Handler {
+			return w.WrapRoute(r.ID, next)
+		}
+	}
+
+	return
+}
+
+// PurgeCache removes all cached responses of the route
+func (s *apigw) PurgeCache(ctx context.Context, routeID uint64) (err error) {
+	for _, c := range s.caches {
+		if err = c.Purge(ctx, routeID); err != nil {
+			return
+		}
+	}
+
 	return
 }
 
@@ -1,6 +1,7 @@
 package types
 
 import (
+	goerrors "errors"
 	"net/http"
 
 	"github.com/cortezaproject/corteza-server/pkg/errors"
@@ -13,6 +14,13 @@ type (
 	}
 )
 
+var (
+	// ErrStopProcessing is returned by filters that already
+	// wrote the response (ie. CORS preflight, rate limiting)
+	// and need the rest of the pipeline to be skipped
+	ErrStopProcessing = goerrors.New("processing stopped")
+)
+
 func NewDefaultErrorHandler(log *zap.Logger) DefaultErrorHandler {
 	return DefaultErrorHandler{
 		log: log,
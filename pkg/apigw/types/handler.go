@@ -23,6 +23,13 @@ type (
 		Meta() FilterMeta
 	}
 
+	// RouteWrapper is implemented by filters that wrap
+	// processers and postfilters of the route instead of
+	// being a link in the chain (response cache)
+	RouteWrapper interface {
+		WrapRoute(routeID uint64, next http.Handler) http.Handler
+	}
+
 	HandlerFunc      func(rw http.ResponseWriter, r *http.Request) error
 	ErrorHandlerFunc func(rw http.ResponseWriter, r *http.Request, err error)
 )
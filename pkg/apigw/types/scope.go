@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 )
@@ -50,6 +51,16 @@ func (s Scp) Opts() *options.ApigwOpt {
 	return nil
 }
 
+// Identity returns identity of the request's
+// caller when set by one of the filters (auth prefilter)
+func (s Scp) Identity() auth.Identifiable {
+	if i, ok := s["identity"].(auth.Identifiable); ok {
+		return i
+	}
+
+	return nil
+}
+
 func (s Scp) Set(k string, v interface{}) {
 	s[k] = v
 }
@@ -1,5 +1,40 @@
 package types
 
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	st "github.com/cortezaproject/corteza-server/system/types"
+)
+
+const (
+	IdentityLookupID     = "id"
+	IdentityLookupEmail  = "email"
+	IdentityLookupHandle = "handle"
+
+	ResponseCacheMemory = "memory"
+	ResponseCacheStore  = "store"
+)
+
 type (
-	SecureStorager interface{}
+	SecureStorager interface {
+		// Secret returns decrypted value of the secret with the given handle
+		Secret(ctx context.Context, handle string) (string, error)
+	}
+
+	IdentityResolver interface {
+		// ResolveIdentity finds active user by ID, email or handle
+		// and returns its identity with all roles
+		ResolveIdentity(ctx context.Context, lookup, value string) (auth.Identifiable, error)
+	}
+
+	ResponseCache interface {
+		// Get returns cached response or nil when
+		// there is no valid entry for the key
+		Get(ctx context.Context, key string) (*st.ApigwCacheEntry, error)
+		Set(ctx context.Context, e *st.ApigwCacheEntry) error
+
+		// Purge removes all cached responses of the route
+		Purge(ctx context.Context, routeID uint64) error
+	}
 )
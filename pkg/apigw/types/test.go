@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	st "github.com/cortezaproject/corteza-server/system/types"
 )
 
@@ -27,9 +29,18 @@ type (
 	MockStorer struct {
 		F func(context.Context, st.ApigwFilterFilter) (st.ApigwFilterSet, st.ApigwFilterFilter, error)
 		R func(context.Context, st.ApigwRouteFilter) (st.ApigwRouteSet, st.ApigwRouteFilter, error)
+		S func(context.Context, string) (*st.ApigwSecret, error)
+		U func(context.Context, st.UserFilter) (st.UserSet, st.UserFilter, error)
+		M func(context.Context, st.RoleMemberFilter) (st.RoleMemberSet, st.RoleMemberFilter, error)
 	}
 
 	MockRoundTripper func(*http.Request) (*http.Response, error)
+
+	// MockSecureStorager holds plaintext secrets by handle
+	MockSecureStorager map[string]string
+
+	// MockIdentityResolver holds identities by lookup value
+	MockIdentityResolver map[string]auth.Identifiable
 )
 
 func (h MockHandler) New() Handler {
@@ -63,6 +74,69 @@ func (td MockStorer) SearchApigwFilters(ctx context.Context, f st.ApigwFilterFil
 	return td.F(ctx, f)
 }
 
+func (ss MockSecureStorager) Secret(ctx context.Context, handle string) (string, error) {
+	if v, ok := ss[handle]; ok {
+		return v, nil
+	}
+
+	return "", fmt.Errorf("secret %q not found", handle)
+}
+
+func (td MockStorer) LookupApigwSecretByHandle(ctx context.Context, handle string) (*st.ApigwSecret, error) {
+	return td.S(ctx, handle)
+}
+
+func (td MockStorer) LookupApigwCacheEntryByKey(ctx context.Context, key string) (*st.ApigwCacheEntry, error) {
+	return nil, fmt.Errorf("cache entry not found")
+}
+
+func (td MockStorer) UpsertApigwCacheEntry(ctx context.Context, rr ...*st.ApigwCacheEntry) error {
+	return nil
+}
+
+func (td MockStorer) DeleteApigwCacheEntryByKey(ctx context.Context, key string) error {
+	return nil
+}
+
+func (td MockStorer) DeleteApigwCacheEntriesByRouteID(ctx context.Context, routeID uint64) error {
+	return nil
+}
+
+func (td MockStorer) DeleteExpiredApigwCacheEntries(ctx context.Context) error {
+	return nil
+}
+
+func (td MockStorer) LookupUserByID(ctx context.Context, ID uint64) (*st.User, error) {
+	return td.lookupUser(ctx, func(u *st.User) bool { return u.ID == ID })
+}
+
+func (td MockStorer) LookupUserByEmail(ctx context.Context, email string) (*st.User, error) {
+	return td.lookupUser(ctx, func(u *st.User) bool { return u.Email == email })
+}
+
+func (td MockStorer) LookupUserByHandle(ctx context.Context, handle string) (*st.User, error) {
+	return td.lookupUser(ctx, func(u *st.User) bool { return u.Handle == handle })
+}
+
+func (td MockStorer) SearchRoleMembers(ctx context.Context, f st.RoleMemberFilter) (st.RoleMemberSet, st.RoleMemberFilter, error) {
+	return td.M(ctx, f)
+}
+
+func (td MockStorer) lookupUser(ctx context.Context, match func(*st.User) bool) (*st.User, error) {
+	uu, _, err := td.U(ctx, st.UserFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range uu {
+		if match(u) {
+			return u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("user not found")
+}
+
 func (h MockErrorHandler) Handler() ErrorHandlerFunc {
 	return h.Handler_
 }
@@ -70,3 +144,11 @@ func (h MockErrorHandler) Handler() ErrorHandlerFunc {
 func (mrt MockRoundTripper) RoundTrip(rq *http.Request) (r *http.Response, err error) {
 	return mrt(rq)
 }
+
+func (ir MockIdentityResolver) ResolveIdentity(ctx context.Context, lookup, value string) (auth.Identifiable, error) {
+	if i, ok := ir[value]; ok {
+		return i, nil
+	}
+
+	return nil, fmt.Errorf("user %q not found", value)
+}
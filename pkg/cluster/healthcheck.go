@@ -0,0 +1,34 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Healthcheck for (global) cluster service
+//
+// Reports failed lease syncs and lease syncs that
+// did not happen in the lease TTL
+func Healthcheck(ctx context.Context) error {
+	if gCluster == nil || !gCluster.opt.Enabled {
+		return nil
+	}
+
+	gCluster.l.RLock()
+	defer gCluster.l.RUnlock()
+
+	if gCluster.syncErr != nil {
+		return fmt.Errorf("lease sync failed: %w", gCluster.syncErr)
+	}
+
+	if gCluster.synced.IsZero() {
+		return fmt.Errorf("not started")
+	}
+
+	if now().Sub(gCluster.synced) > gCluster.opt.LeaseTTL {
+		return fmt.Errorf("leases not renewed since %s", gCluster.synced.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/cluster/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"go.uber.org/zap"
+)
+
+type (
+	service struct {
+		log   *zap.Logger
+		opt   options.ClusterOpt
+		store leaseStore
+
+		// Read & write locking
+		l sync.RWMutex
+
+		// names of all leases this node competes for
+		names map[string]bool
+
+		// expiration times of all leases held by this node
+		held map[string]time.Time
+
+		// time and result of the last lease sync
+		synced  time.Time
+		syncErr error
+	}
+
+	leaseStore interface {
+		LookupClusterLeaseByName(ctx context.Context, name string) (*types.Lease, error)
+		AcquireClusterLease(ctx context.Context, lease *types.Lease) (bool, error)
+		ReleaseClusterLease(ctx context.Context, name string, holder string) error
+	}
+)
+
+const (
+	// LeaderLease is held by exactly one node in the cluster
+	LeaderLease = "leader"
+
+	// prefix for per-node leases, used to detect running nodes
+	nodeLeasePrefix = "node."
+
+	// how long we wait for leases to be released when node is shutting down
+	releaseTimeout = time.Second * 5
+)
+
+var (
+	now = func() time.Time { return time.Now() }
+
+	// Global cluster service
+	gCluster *service
+)
+
+// Setup configures global cluster service
+func Setup(log *zap.Logger, s leaseStore, opt options.ClusterOpt) {
+	gCluster = NewService(log, s, opt)
+}
+
+func Service() *service {
+	return gCluster
+}
+
+func NewService(log *zap.Logger, s leaseStore, opt options.ClusterOpt) *service {
+	if opt.LeaseTTL == 0 {
+		opt.LeaseTTL = options.Cluster().LeaseTTL
+	}
+
+	if opt.RenewInterval == 0 || opt.RenewInterval >= opt.LeaseTTL {
+		opt.RenewInterval = opt.LeaseTTL / 3
+	}
+
+	return &service{
+		log:   log.Named("cluster").With(zap.String("nodeID", opt.NodeID)),
+		opt:   opt,
+		store: s,
+		names: make(map[string]bool),
+		held:  make(map[string]time.Time),
+	}
+}
+
+// NodeID returns unique identifier of this node
+func (svc *service) NodeID() string {
+	return svc.opt.NodeID
+}
+
+// Enabled returns true when cluster coordination is enabled
+func (svc *service) Enabled() bool {
+	return svc.opt.Enabled
+}
+
+// Register adds leases this node competes for and tries to acquire them right away
+//
+// Registered leases are renewed (or acquired when released by other nodes)
+// until the node is shut down
+func (svc *service) Register(ctx context.Context, names ...string) {
+	if !svc.opt.Enabled {
+		return
+	}
+
+	svc.l.Lock()
+	for _, name := range names {
+		svc.names[name] = true
+	}
+	svc.l.Unlock()
+
+	svc.sync(ctx, names...)
+}
+
+// Holds returns true if this node holds the lease
+//
+// With disabled cluster coordination, node holds all leases
+func (svc *service) Holds(name string) bool {
+	if !svc.opt.Enabled {
+		return true
+	}
+
+	svc.l.RLock()
+	defer svc.l.RUnlock()
+
+	exp, has := svc.held[name]
+	return has && exp.After(now())
+}
+
+// IsLeader returns true if this node is the cluster leader
+func (svc *service) IsLeader() bool {
+	return svc.Holds(LeaderLease)
+}
+
+// NodeAlive returns true when another node with the given ID is running
+//
+// Node is considered running while it keeps renewing its node lease
+func (svc *service) NodeAlive(ctx context.Context, nodeID string) (bool, error) {
+	if !svc.opt.Enabled || nodeID == svc.opt.NodeID {
+		return false, nil
+	}
+
+	l, err := svc.store.LookupClusterLeaseByName(ctx, nodeLeasePrefix+nodeID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return l.Holder == nodeID && !l.Expired(now()), nil
+}
+
+// Watch acquires node and leader leases and starts renewing all registered leases
+//
+// All held leases are released when context is done
+func (svc *service) Watch(ctx context.Context) {
+	if !svc.opt.Enabled {
+		svc.log.Debug("cluster coordination disabled (CLUSTER_ENABLED=false)")
+		return
+	}
+
+	svc.Register(ctx, nodeLeasePrefix+svc.opt.NodeID, LeaderLease)
+
+	go func() {
+		defer sentry.Recover()
+
+		var (
+			ticker = time.NewTicker(svc.opt.RenewInterval)
+		)
+
+		defer ticker.Stop()
+
+		svc.log.Info("watching leases", zap.Duration("interval", svc.opt.RenewInterval))
+
+		for {
+			select {
+			case <-ticker.C:
+				svc.sync(ctx)
+
+			case <-ctx.Done():
+				svc.releaseAll()
+				svc.log.Info("stopped")
+				return
+			}
+		}
+	}()
+}
+
+// sync acquires or renews given leases (or all registered when none are given)
+func (svc *service) sync(ctx context.Context, names ...string) {
+	if len(names) == 0 {
+		svc.l.RLock()
+		for name := range svc.names {
+			names = append(names, name)
+		}
+		svc.l.RUnlock()
+	}
+
+	var (
+		syncErr error
+		at      = now()
+	)
+
+	for _, name := range names {
+		lease := &types.Lease{
+			Name:       name,
+			Holder:     svc.opt.NodeID,
+			AcquiredAt: at,
+			ExpiresAt:  at.Add(svc.opt.LeaseTTL),
+		}
+
+		ok, err := svc.store.AcquireClusterLease(ctx, lease)
+		if err != nil {
+			svc.log.Error("could not acquire lease", zap.String("lease", name), zap.Error(err))
+			syncErr = err
+			continue
+		}
+
+		svc.l.Lock()
+		_, had := svc.held[name]
+		if ok {
+			svc.held[name] = lease.ExpiresAt
+		} else {
+			delete(svc.held, name)
+		}
+		svc.l.Unlock()
+
+		switch {
+		case ok && !had:
+			svc.log.Info("lease acquired", zap.String("lease", name))
+		case !ok && had:
+			svc.log.Warn("lease lost", zap.String("lease", name))
+		}
+	}
+
+	svc.l.Lock()
+	svc.synced = at
+	svc.syncErr = syncErr
+	svc.l.Unlock()
+}
+
+// releaseAll releases all leases held by this node so other nodes
+// can take them over without waiting for them to expire
+func (svc *service) releaseAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+	defer cancel()
+
+	svc.l.Lock()
+	defer svc.l.Unlock()
+
+	for name := range svc.held {
+		if err := svc.store.ReleaseClusterLease(ctx, name, svc.opt.NodeID); err != nil {
+			svc.log.Error("could not release lease", zap.String("lease", name), zap.Error(err))
+		}
+
+		delete(svc.held, name)
+	}
+}
+
+// Register leases on the global cluster service
+func Register(ctx context.Context, names ...string) {
+	if gCluster == nil {
+		return
+	}
+
+	gCluster.Register(ctx, names...)
+}
+
+// Holds checks if lease is held by this node
+//
+// Without configured global cluster service, node holds all leases
+func Holds(name string) bool {
+	if gCluster == nil {
+		return true
+	}
+
+	return gCluster.Holds(name)
+}
+
+// NodeID returns unique identifier of this node or
+// an empty string when cluster service is not configured
+func NodeID() string {
+	if gCluster == nil {
+		return ""
+	}
+
+	return gCluster.NodeID()
+}
+
+// NodeAlive checks if node is running
+func NodeAlive(ctx context.Context, nodeID string) (bool, error) {
+	if gCluster == nil {
+		return false, nil
+	}
+
+	return gCluster.NodeAlive(ctx, nodeID)
+}
@@ -0,0 +1,140 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/cluster/types"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type (
+	mockLeaseStore struct {
+		l  sync.Mutex
+		ll map[string]types.Lease
+	}
+)
+
+func (s *mockLeaseStore) LookupClusterLeaseByName(ctx context.Context, name string) (*types.Lease, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if l, has := s.ll[name]; has {
+		return &l, nil
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func (s *mockLeaseStore) AcquireClusterLease(ctx context.Context, lease *types.Lease) (bool, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if l, has := s.ll[lease.Name]; has && l.Holder != lease.Holder && !l.Expired(lease.AcquiredAt) {
+		return false, nil
+	}
+
+	s.ll[lease.Name] = *lease
+	return true, nil
+}
+
+func (s *mockLeaseStore) ReleaseClusterLease(ctx context.Context, name string, holder string) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if l, has := s.ll[name]; has && l.Holder == holder {
+		delete(s.ll, name)
+	}
+
+	return nil
+}
+
+func TestService_Leases(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		s   = &mockLeaseStore{ll: make(map[string]types.Lease)}
+		opt = func(nodeID string) options.ClusterOpt {
+			return options.ClusterOpt{Enabled: true, NodeID: nodeID, LeaseTTL: time.Minute}
+		}
+
+		n1 = NewService(zap.NewNop(), s, opt("n1"))
+		n2 = NewService(zap.NewNop(), s, opt("n2"))
+
+		at = time.Now()
+	)
+
+	defer func() { now = time.Now }()
+	now = func() time.Time { return at }
+
+	n1.Register(ctx, LeaderLease, "job")
+	n2.Register(ctx, LeaderLease, "job")
+
+	req.True(n1.IsLeader())
+	req.True(n1.Holds("job"))
+	req.False(n2.IsLeader())
+	req.False(n2.Holds("job"))
+
+	// first node stops renewing its leases
+	at = at.Add(time.Minute)
+	req.False(n1.Holds("job"))
+
+	n2.sync(ctx)
+	req.True(n2.IsLeader())
+	req.True(n2.Holds("job"))
+
+	// first node can not get its leases back
+	n1.sync(ctx)
+	req.False(n1.IsLeader())
+
+	// released leases are acquired on the next sync
+	n2.releaseAll()
+	n1.sync(ctx)
+	req.True(n1.IsLeader())
+	req.True(n1.Holds("job"))
+}
+
+func TestService_Disabled(t *testing.T) {
+	var (
+		req = require.New(t)
+		svc = NewService(zap.NewNop(), &mockLeaseStore{}, options.ClusterOpt{NodeID: "n1"})
+	)
+
+	svc.Register(context.Background(), "job")
+	req.True(svc.Holds("job"))
+	req.True(svc.IsLeader())
+
+	alive, err := svc.NodeAlive(context.Background(), "n2")
+	req.NoError(err)
+	req.False(alive)
+}
+
+func TestService_NodeAlive(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		s   = &mockLeaseStore{ll: make(map[string]types.Lease)}
+		n1  = NewService(zap.NewNop(), s, options.ClusterOpt{Enabled: true, NodeID: "n1", LeaseTTL: time.Minute})
+		n2  = NewService(zap.NewNop(), s, options.ClusterOpt{Enabled: true, NodeID: "n2", LeaseTTL: time.Minute})
+	)
+
+	n1.Register(ctx, nodeLeasePrefix+"n1")
+
+	alive, err := n2.NodeAlive(ctx, "n1")
+	req.NoError(err)
+	req.True(alive)
+
+	alive, err = n2.NodeAlive(ctx, "n3")
+	req.NoError(err)
+	req.False(alive)
+
+	n1.releaseAll()
+	alive, err = n2.NodeAlive(ctx, "n1")
+	req.NoError(err)
+	req.False(alive)
+}
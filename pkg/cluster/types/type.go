@@ -0,0 +1,32 @@
+package types
+
+import (
+	"time"
+)
+
+type (
+	// Lease is a time limited claim of a node over a named job
+	//
+	// Only one node can hold a lease at a time; lease is held
+	// until it expires or until it is released by the holder
+	Lease struct {
+		Name       string    `json:"name"`
+		Holder     string    `json:"holder"`
+		AcquiredAt time.Time `json:"acquiredAt"`
+		ExpiresAt  time.Time `json:"expiresAt"`
+	}
+
+	LeaseSet []*Lease
+
+	LeaseFilter struct {
+		Holder string `json:"holder"`
+
+		// Only leases that are not expired
+		Valid bool `json:"valid"`
+	}
+)
+
+// Expired returns true if lease expired at the given time
+func (l Lease) Expired(at time.Time) bool {
+	return !l.ExpiresAt.After(at)
+}
@@ -22,6 +22,16 @@ type (
 		ID     string
 		Values map[string]string
 
+		// MultiValues holds all values of fields with more than one value
+		//
+		// Values holds only one of them; encoders of formats
+		// that can represent multiple values use these instead.
+		MultiValues map[string][]string
+
+		// MultiValueDelimiter separates values of multi-value fields
+		// packed into a single value; empty when the source format does not do that
+		MultiValueDelimiter string
+
 		Ts *Timestamps
 		Us *Userstamps
 
@@ -57,6 +57,8 @@ func (crt *composeRecordShaper) toResource(def *ComposeRecordTemplate, dt *Resou
 			rRaw := &ComposeRecordRaw{
 				ID:     crt.getKey(mr, def.Key),
 				Values: crt.mapValues(mr, def.FieldMap, def.Defaultable),
+
+				MultiValueDelimiter: dt.MultiValueDelimiter,
 			}
 
 			crt.getTimestamps(rRaw)
@@ -12,6 +12,10 @@ type (
 		*base
 
 		P provider
+
+		// MultiValueDelimiter separates values of multi-value fields
+		// packed into a single cell; empty when the format does not do that
+		MultiValueDelimiter string
 	}
 )
 
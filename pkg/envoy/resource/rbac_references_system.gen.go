@@ -15,6 +15,7 @@ package resource
 // - system.role.yaml
 // - system.template.yaml
 // - system.user.yaml
+// - system.webhook.yaml
 // - system.yaml
 
 import (
@@ -124,3 +125,16 @@ func SystemUserRbacReferences(user string) (res *Ref, pp []*Ref, err error) {
 
 	return
 }
+
+// SystemWebhookRbacReferences generates RBAC references
+//
+// Resources with "envoy: false" are skipped
+//
+// This function is auto-generated
+func SystemWebhookRbacReferences(webhook string) (res *Ref, pp []*Ref, err error) {
+	if webhook != "*" {
+		res = &Ref{ResourceType: types.WebhookResourceType, Identifiers: MakeIdentifiers(webhook)}
+	}
+
+	return
+}
@@ -24,6 +24,7 @@ package resource
 // - system.role.yaml
 // - system.template.yaml
 // - system.user.yaml
+// - system.webhook.yaml
 // - system.yaml
 
 import (
@@ -251,6 +252,16 @@ func ParseRule(res string) (string, *Ref, []*Ref, error) {
 		)
 		return systemTypes.UserResourceType, ref, pp, err
 
+	case systemTypes.WebhookResourceType:
+		if len(path) != 1 {
+			return "", nil, nil, fmt.Errorf("expecting 1 reference components in path, got %d", len(path))
+		}
+		ref, pp, err := SystemWebhookRbacReferences(
+			// webhook
+			path[0],
+		)
+		return systemTypes.WebhookResourceType, ref, pp, err
+
 	case systemTypes.ComponentResourceType:
 		if len(path) != 0 {
 			return "", nil, nil, fmt.Errorf("expecting 0 reference components in path, got %d", len(path))
@@ -0,0 +1,203 @@
+package spreadsheet
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+)
+
+type (
+	bulkComposeRecordEncoder struct {
+		encoderConfig *EncoderConfig
+		loc           *time.Location
+
+		res *resource.ComposeRecord
+	}
+)
+
+const (
+	// values of multi-value fields are written one per line
+	multiValueDelimiter = "\n"
+)
+
+var (
+	recordSysFields = []string{"ownedBy", "createdAt", "createdBy", "updatedAt", "updatedBy", "deletedAt", "deletedBy"}
+)
+
+func bulkComposeRecordEncoderFromResource(rec *resource.ComposeRecord, cfg *EncoderConfig, loc *time.Location) *bulkComposeRecordEncoder {
+	return &bulkComposeRecordEncoder{
+		encoderConfig: cfg,
+		loc:           loc,
+
+		res: rec,
+	}
+}
+
+// Prepare prepares the composeRecord to be encoded
+//
+// Any validation, additional constraining should be performed here.
+func (n *bulkComposeRecordEncoder) Prepare(ctx context.Context, state *envoy.ResourceState) (err error) {
+	rec, ok := state.Res.(*resource.ComposeRecord)
+	if !ok {
+		return encoderErrInvalidResource(types.RecordResourceType, state.Res.ResourceType())
+	}
+
+	if rec.RelMod == nil {
+		return fmt.Errorf("module not resolved")
+	}
+
+	return nil
+}
+
+// SheetName returns the name of the sheet the records are encoded into
+//
+// Module name is used when defined; handle and identifier otherwise
+func (n *bulkComposeRecordEncoder) SheetName() string {
+	switch {
+	case n.res.RelMod.Name != "":
+		return n.res.RelMod.Name
+	case n.res.RelMod.Handle != "":
+		return n.res.RelMod.Handle
+	}
+
+	return n.res.Identifiers().First()
+}
+
+// Encode encodes the composeRecord into its own sheet
+//
+// Cells are typed based on the module field kind; multi-value fields
+// are encoded as text with one value per line.
+func (n *bulkComposeRecordEncoder) Encode(ctx context.Context, sheet string, wb workbookWriter, state *envoy.ResourceState) (err error) {
+	all := len(n.encoderConfig.Fields) == 0
+
+	if err = wb.addSheet(sheet); err != nil {
+		return err
+	}
+
+	// Generate header & cell index
+	hh := make([]string, 0, 100)
+	hh = append(hh, "id")
+
+	fx := make(map[string]int)
+	// 1 sys fields
+	for _, sf := range recordSysFields {
+		if all || n.encoderConfig.Fields[sf] {
+			fx[sf] = len(hh)
+			hh = append(hh, sf)
+		}
+	}
+
+	// 2 module fields
+	for _, f := range n.res.RelMod.Fields {
+		if all || n.encoderConfig.Fields[f.Name] {
+			fx[f.Name] = len(hh)
+			hh = append(hh, f.Name)
+		}
+	}
+
+	if err = wb.writeHeader(hh); err != nil {
+		return err
+	}
+
+	userCell := func(name string, us *resource.Userstamp) (c cell, err error) {
+		if us == nil || !(all || n.encoderConfig.Fields[name]) {
+			return
+		}
+
+		var u string
+		u, err = n.res.UserFlakes.GetByStamp(us).Model()
+		return stringCell(u), err
+	}
+
+	timeCellFor := func(name string, ts *resource.Timestamp) cell {
+		if ts == nil || ts.T == nil || !(all || n.encoderConfig.Fields[name]) {
+			return cell{}
+		}
+
+		return timeCell(*ts.T, n.loc, cellLayoutDateTime)
+	}
+
+	return n.res.Walker(func(r *resource.ComposeRecordRaw) error {
+		var (
+			err error
+			row = make([]cell, len(hh))
+		)
+
+		// IDs are too large for numeric cells
+		row[0] = stringCell(r.ID)
+
+		if r.Us != nil {
+			for name, us := range map[string]*resource.Userstamp{
+				"ownedBy":   r.Us.OwnedBy,
+				"createdBy": r.Us.CreatedBy,
+				"updatedBy": r.Us.UpdatedBy,
+				"deletedBy": r.Us.DeletedBy,
+			} {
+				var c cell
+				if c, err = userCell(name, us); err != nil {
+					return err
+				} else if c.value != nil {
+					row[fx[name]] = c
+				}
+			}
+		}
+
+		if r.Ts != nil {
+			for name, ts := range map[string]*resource.Timestamp{
+				"createdAt": r.Ts.CreatedAt,
+				"updatedAt": r.Ts.UpdatedAt,
+				"deletedAt": r.Ts.DeletedAt,
+			} {
+				if c := timeCellFor(name, ts); c.value != nil {
+					row[fx[name]] = c
+				}
+			}
+		}
+
+		for k, v := range r.Values {
+			// Skip fields we don't want
+			if !all && !n.encoderConfig.Fields[k] {
+				continue
+			}
+
+			col, has := fx[k]
+			if !has {
+				return fmt.Errorf("unknown cell %s", k)
+			}
+			f := n.res.RelMod.Fields.FindByName(k)
+			if f == nil {
+				return fmt.Errorf("field %s not found", k)
+			}
+
+			vv := []string{v}
+			if mv, has := r.MultiValues[k]; has && f.Multi {
+				vv = mv
+			}
+
+			switch {
+			case f.Kind == "User":
+				uu := make([]string, len(vv))
+				for i := range vv {
+					if uu[i], err = n.res.UserFlakes.GetByKey(vv[i]).Model(); err != nil {
+						return err
+					}
+				}
+
+				row[col] = stringCell(strings.Join(uu, multiValueDelimiter))
+
+			case f.Multi:
+				row[col] = stringCell(strings.Join(vv, multiValueDelimiter))
+
+			default:
+				row[col] = valueCell(f.Kind, f.Options.Bool("onlyDate"), f.Options.Bool("onlyTime"), v, n.loc)
+			}
+		}
+
+		return wb.writeRow(row)
+	})
+}
@@ -0,0 +1,157 @@
+package spreadsheet
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// cell is a typed spreadsheet cell
+	//
+	// Value is one of string, float64, bool or time.Time; nil for an empty cell.
+	cell struct {
+		value interface{}
+
+		// layout of time.Time values (cellLayoutDate, cellLayoutTime or cellLayoutDateTime)
+		layout string
+	}
+
+	// workbookWriter writes records into a spreadsheet document
+	//
+	// Sheets are written one after another; rows are written
+	// into the sheet that was last added.
+	workbookWriter interface {
+		addSheet(name string) error
+		writeHeader(hh []string) error
+		writeRow(cc []cell) error
+		writeTo(w io.Writer) error
+	}
+)
+
+const (
+	FormatXLSX = "xlsx"
+	FormatODS  = "ods"
+
+	cellLayoutDate     = "date"
+	cellLayoutTime     = "time"
+	cellLayoutDateTime = "datetime"
+
+	// Limitations of spreadsheet applications
+	maxSheetNameLength = 31
+	maxCellTextLength  = 32767
+)
+
+var (
+	// characters that can not be used in sheet names
+	sheetNameReplacer = strings.NewReplacer(
+		"[", "", "]", "", ":", "", "*", "", "?", "", "/", "", "\\", "",
+	)
+)
+
+func newWorkbookWriter(format string) workbookWriter {
+	if format == FormatODS {
+		return newOdsWriter()
+	}
+
+	return newXlsxWriter()
+}
+
+// stringCell makes a text cell
+//
+// Text is truncated to the max length of cell content
+func stringCell(s string) cell {
+	if s == "" {
+		return cell{}
+	}
+
+	// limit is in characters; there can not be more of them than bytes
+	if len(s) > maxCellTextLength {
+		if r := []rune(s); len(r) > maxCellTextLength {
+			s = string(r[:maxCellTextLength])
+		}
+	}
+
+	return cell{value: s}
+}
+
+// timeCell makes a date/time cell
+//
+// Spreadsheets do not store timezones; the wall clock time in the
+// given location is used.
+func timeCell(t time.Time, loc *time.Location, layout string) cell {
+	if loc != nil && layout != cellLayoutDate {
+		t = t.In(loc)
+	}
+
+	return cell{
+		value:  time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC),
+		layout: layout,
+	}
+}
+
+// valueCell makes a typed cell from the record value, based on the module field kind
+//
+// Values that can not be converted are encoded as text.
+func valueCell(kind string, onlyDate, onlyTime bool, v string, loc *time.Location) cell {
+	if v == "" {
+		return cell{}
+	}
+
+	switch strings.ToLower(kind) {
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return cell{value: n}
+		}
+
+	case "bool":
+		return cell{value: v == "1" || v == "true"}
+
+	case "datetime":
+		switch {
+		case onlyTime:
+			if t, err := time.Parse("15:04:05", v); err == nil {
+				return timeCell(t, nil, cellLayoutTime)
+			}
+
+		case onlyDate:
+			if t, err := time.Parse("2006-01-02", v); err == nil {
+				return timeCell(t, nil, cellLayoutDate)
+			}
+
+		default:
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return timeCell(t, loc, cellLayoutDateTime)
+			}
+		}
+	}
+
+	return stringCell(v)
+}
+
+// sheetName makes a valid, unique sheet name
+func sheetName(name string, used map[string]bool) string {
+	name = strings.TrimSpace(sheetNameReplacer.Replace(name))
+	if name == "" {
+		name = "Sheet"
+	}
+
+	if r := []rune(name); len(r) > maxSheetNameLength {
+		name = string(r[:maxSheetNameLength])
+	}
+
+	base := name
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		sfx := " (" + strconv.Itoa(i) + ")"
+		r := []rune(base)
+		if len(r)+len(sfx) > maxSheetNameLength {
+			r = r[:maxSheetNameLength-len(sfx)]
+		}
+
+		name = string(r) + sfx
+	}
+
+	used[strings.ToLower(name)] = true
+	return name
+}
@@ -0,0 +1,248 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/gabriel-vasile/mimetype"
+)
+
+type (
+	// wrapper struct for spreadsheet related methods
+	decoder struct{}
+
+	// sheet reader wrapper for additional bits
+	//
+	// One reader is used for each of the sheets
+	reader struct {
+		header []string
+		count  uint64
+
+		cache      []map[string]string
+		cacheIndex int
+	}
+
+	// sheet holds rows of a single sheet
+	sheet struct {
+		name string
+		rows [][]string
+	}
+)
+
+const (
+	mimeXlsx = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	mimeOds  = "application/vnd.oasis.opendocument.spreadsheet"
+)
+
+// Decoder initializes and returns a fresh spreadsheet decoder
+//
+// Office Open XML (.xlsx) and OpenDocument (.ods) spreadsheets are supported.
+func Decoder() *decoder {
+	return &decoder{}
+}
+
+// MimeType returns the mime type of the given spreadsheet format
+func MimeType(format string) string {
+	if format == FormatODS {
+		return mimeOds
+	}
+
+	return mimeXlsx
+}
+
+// CanDecodeFile determines if the file can be decoded by this decoder
+func (d *decoder) CanDecodeFile(f io.Reader) bool {
+	m, err := mimetype.DetectReader(f)
+	if err != nil {
+		return false
+	}
+
+	return d.CanDecodeMime(m.String()) || d.CanDecodeExt(m.Extension())
+}
+
+func (d *decoder) CanDecodeMime(m string) bool {
+	return m == mimeXlsx || m == mimeOds
+}
+
+func (d *decoder) CanDecodeExt(ext string) bool {
+	pt := strings.Split(ext, ".")
+	ext = strings.ToLower(strings.TrimSpace(pt[len(pt)-1]))
+	return ext == FormatXLSX || ext == FormatODS
+}
+
+// Decode decodes the given io.Reader into a set of generic resource datasets
+//
+// Each non-empty sheet is decoded into its own dataset, identified by the sheet name.
+// The first dataset is additionally identified by the provided name so it can be
+// used the same way as single-dataset sources (csv, json).
+//
+// The first row of each sheet is used as the header.
+func (d *decoder) Decode(ctx context.Context, r io.Reader, do *envoy.DecoderOpts) ([]resource.Interface, error) {
+	var (
+		ss []*sheet
+	)
+
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf), int64(len(buf)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open spreadsheet: %w", err)
+	}
+
+	switch detectFormat(zr) {
+	case FormatXLSX:
+		ss, err = readXlsx(buf)
+	case FormatODS:
+		ss, err = readOds(zr)
+	default:
+		return nil, fmt.Errorf("could not open spreadsheet: unknown format")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	rr := make([]resource.Interface, 0, len(ss))
+	for _, s := range ss {
+		sr := &reader{
+			cacheIndex: 0,
+			cache:      make([]map[string]string, 0, len(s.rows)),
+		}
+
+		if !sr.prepare(s.rows) {
+			// empty sheet
+			continue
+		}
+
+		ds := resource.NewResourceDataset(s.name, sr)
+		ds.MultiValueDelimiter = multiValueDelimiter
+		if len(rr) == 0 && do != nil && do.Name != "" {
+			ds.AddIdentifier(do.Name)
+		}
+
+		rr = append(rr, ds)
+	}
+
+	if len(rr) == 0 {
+		return nil, fmt.Errorf("could not decode spreadsheet: no data")
+	}
+
+	return rr, nil
+}
+
+// detectFormat determines the spreadsheet format from the archive contents
+func detectFormat(zr *zip.Reader) string {
+	for _, f := range zr.File {
+		switch f.Name {
+		case "xl/workbook.xml":
+			return FormatXLSX
+
+		case "mimetype":
+			rc, err := f.Open()
+			if err != nil {
+				return ""
+			}
+
+			m, _ := ioutil.ReadAll(rc)
+			_ = rc.Close()
+
+			if strings.TrimSpace(string(m)) == mimeOds {
+				return FormatODS
+			}
+		}
+	}
+
+	return ""
+}
+
+// The prepare step caches all of the rows into a cache array that will be used
+// to access already visited sheet rows.
+//
+// Returns false when sheet has no header
+func (sr *reader) prepare(rows [][]string) bool {
+	if len(rows) == 0 {
+		return false
+	}
+
+	// Header; empty columns are ignored
+	sr.header = make([]string, 0, len(rows[0]))
+	hx := make(map[int]string)
+	for i, h := range rows[0] {
+		if h = strings.TrimSpace(h); h != "" {
+			hx[i] = h
+			sr.header = append(sr.header, h)
+		}
+	}
+
+	if len(sr.header) == 0 {
+		return false
+	}
+
+	for _, row := range rows[1:] {
+		if isEmptyRow(row) {
+			continue
+		}
+
+		aux := make(map[string]string)
+		for i, h := range hx {
+			if i < len(row) {
+				aux[h] = row[i]
+			} else {
+				aux[h] = ""
+			}
+		}
+
+		// Entry count
+		sr.count++
+
+		// Cache entry
+		sr.cache = append(sr.cache, aux)
+	}
+
+	return true
+}
+
+// Fields returns every available field in this dataset
+func (sr *reader) Fields() []string {
+	return sr.header
+}
+
+func (sr *reader) Reset() error {
+	sr.cacheIndex = 0
+	return nil
+}
+
+// Next returns the field: value mapping for the next row
+func (sr *reader) Next() (map[string]string, error) {
+	if sr.cacheIndex >= len(sr.cache) {
+		return nil, nil
+	}
+
+	mr := sr.cache[sr.cacheIndex]
+	sr.cacheIndex++
+	return mr, nil
+}
+
+func (sr *reader) Count() uint64 {
+	return sr.count
+}
+
+func isEmptyRow(row []string) bool {
+	for _, c := range row {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,146 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/stretchr/testify/require"
+)
+
+// makeDocument writes a two-sheet document with typed cells
+func makeDocument(t *testing.T, format string) *bytes.Buffer {
+	var (
+		req = require.New(t)
+		wb  = newWorkbookWriter(format)
+		buf = &bytes.Buffer{}
+
+		dt = time.Date(2021, 7, 15, 13, 14, 15, 0, time.UTC)
+	)
+
+	req.NoError(wb.addSheet("Accounts"))
+	req.NoError(wb.writeHeader([]string{"id", "Name", "Amount", "Active", "Date", "Time", "DateTime", "Tags"}))
+	req.NoError(wb.writeRow([]cell{
+		stringCell("263544855454105601"),
+		stringCell("Acme"),
+		{value: 42.5},
+		{value: true},
+		timeCell(dt, nil, cellLayoutDate),
+		timeCell(dt, nil, cellLayoutTime),
+		timeCell(dt, nil, cellLayoutDateTime),
+		stringCell("a\nb"),
+	}))
+	req.NoError(wb.writeRow(make([]cell, 8)))
+	req.NoError(wb.writeRow([]cell{stringCell("263544855454105602"), stringCell("Globex")}))
+
+	req.NoError(wb.addSheet("Contacts"))
+	req.NoError(wb.writeHeader([]string{"Email"}))
+	req.NoError(wb.writeRow([]cell{stringCell("jane@example.tld")}))
+
+	req.NoError(wb.writeTo(buf))
+	return buf
+}
+
+func TestDecoder(t *testing.T) {
+	var (
+		ctx = context.Background()
+	)
+
+	for format, active := range map[string]string{FormatXLSX: "1", FormatODS: "true"} {
+		format, active := format, active
+		t.Run(format, func(t *testing.T) {
+			var (
+				req = require.New(t)
+				d   = Decoder()
+				doc = makeDocument(t, format)
+			)
+
+			req.True(d.CanDecodeFile(bytes.NewReader(doc.Bytes())))
+			req.True(d.CanDecodeExt("records." + format))
+			req.True(d.CanDecodeMime(MimeType(format)))
+
+			rr, err := d.Decode(ctx, doc, &envoy.DecoderOpts{Name: "records"})
+			req.NoError(err)
+			req.Len(rr, 2)
+
+			ds, ok := rr[0].(*resource.ResourceDataset)
+			req.True(ok)
+			req.True(ds.Identifiers().HasAny(resource.MakeIdentifiers("records", "Accounts")))
+			req.Equal([]string{"id", "Name", "Amount", "Active", "Date", "Time", "DateTime", "Tags"}, ds.P.Fields())
+			req.Equal(uint64(2), ds.P.Count())
+
+			row, err := ds.P.Next()
+			req.NoError(err)
+			req.Equal(map[string]string{
+				"id":       "263544855454105601",
+				"Name":     "Acme",
+				"Amount":   "42.5",
+				"Active":   active,
+				"Date":     "2021-07-15",
+				"Time":     "13:14:15",
+				"DateTime": "2021-07-15 13:14:15",
+				"Tags":     "a\nb",
+			}, row)
+
+			row, err = ds.P.Next()
+			req.NoError(err)
+			req.Equal("Globex", row["Name"])
+			req.Equal("", row["Amount"])
+
+			row, err = ds.P.Next()
+			req.NoError(err)
+			req.Nil(row)
+
+			ds, ok = rr[1].(*resource.ResourceDataset)
+			req.True(ok)
+			req.Equal(resource.MakeIdentifiers("Contacts"), ds.Identifiers())
+			req.Equal(uint64(1), ds.P.Count())
+		})
+	}
+
+	t.Run("not a spreadsheet", func(t *testing.T) {
+		req := require.New(t)
+
+		_, err := Decoder().Decode(ctx, bytes.NewBufferString("id,name\n1,foo\n"), nil)
+		req.Error(err)
+	})
+}
+
+func TestSheetName(t *testing.T) {
+	var (
+		req  = require.New(t)
+		used = make(map[string]bool)
+	)
+
+	req.Equal("Accounts", sheetName("Accounts", used))
+	req.Equal("accounts (2)", sheetName("accounts", used))
+	req.Equal("ab", sheetName("[a:b]", used))
+	req.Equal("Sheet", sheetName("", used))
+	req.Equal("A very long module name that ne", sheetName("A very long module name that needs truncating", used))
+	req.Equal("A very long module name tha (2)", sheetName("A very long module name that needs truncating", used))
+}
+
+func TestStringCell(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		// multi-byte characters around the limit
+		long = strings.Repeat("a", maxCellTextLength-1) + "čšž"
+	)
+
+	req.Nil(stringCell("").value)
+	req.Equal("foo", stringCell("foo").value)
+
+	v := stringCell(long).value.(string)
+	req.True(utf8.ValidString(v))
+	req.Equal(maxCellTextLength, utf8.RuneCountInString(v))
+	req.True(strings.HasSuffix(v, "ač"))
+
+	v = stringCell(strings.Repeat("č", maxCellTextLength)).value.(string)
+	req.Equal(maxCellTextLength, utf8.RuneCountInString(v))
+}
@@ -0,0 +1,192 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+)
+
+type (
+	// bulkRecordEncoder encodes records of one or more modules into
+	// a single workbook; records of each module are placed in their own sheet
+	bulkRecordEncoder struct {
+		cfg *EncoderConfig
+		loc *time.Location
+
+		resState map[resource.Interface]resourceState
+
+		source *bytes.Buffer
+		wb     workbookWriter
+		sheets map[string]bool
+	}
+
+	// EncoderConfig allows us to configure the resource encoding process
+	EncoderConfig struct {
+		// Format defines the spreadsheet format; xlsx or ods
+		//
+		// If not defined, xlsx is used
+		Format string
+		// Timezone defines what timezone should be used when encoding timestamps
+		//
+		// If not defined, UTC is used
+		Timezone string
+		// Fields specifies what fields we wish to include in the export
+		Fields map[string]bool
+	}
+
+	// resourceState holds some intermedia values to help with encoding
+	resourceState interface {
+		Prepare(ctx context.Context, state *envoy.ResourceState) (err error)
+		Encode(ctx context.Context, sheet string, wb workbookWriter, state *envoy.ResourceState) (err error)
+		SheetName() string
+	}
+)
+
+var (
+	ErrUnknownResource        = errors.New("unknown resource")
+	ErrResourceStateUndefined = errors.New("undefined resource state")
+)
+
+func NewBulkRecordEncoder(cfg *EncoderConfig) envoy.PrepareEncodeStreamer {
+	if cfg == nil {
+		cfg = &EncoderConfig{}
+	}
+
+	if cfg.Format != FormatODS {
+		cfg.Format = FormatXLSX
+	}
+
+	return &bulkRecordEncoder{
+		cfg: cfg,
+
+		resState: make(map[resource.Interface]resourceState),
+	}
+}
+
+// Prepare prepares the encoder for the given set of resources
+//
+// It initializes and prepares the resource state for each provided resource
+func (se *bulkRecordEncoder) Prepare(ctx context.Context, ee ...*envoy.ResourceState) (err error) {
+	if se.loc == nil {
+		if se.loc, err = loadLocation(se.cfg.Timezone); err != nil {
+			return err
+		}
+	}
+
+	for _, e := range ee {
+		var rs resourceState
+
+		switch res := e.Res.(type) {
+		// @todo other resources; we'll only do records for now
+		case *resource.ComposeRecord:
+			rs = bulkComposeRecordEncoderFromResource(res, se.cfg, se.loc)
+
+		default:
+			err = ErrUnknownResource
+		}
+
+		if err == nil {
+			err = rs.Prepare(ctx, e)
+		}
+
+		if err != nil {
+			return se.WrapError("prepare", e.Res, err)
+		}
+
+		se.resState[e.Res] = rs
+	}
+
+	return nil
+}
+
+// Encode encodes the resources into a workbook
+func (se *bulkRecordEncoder) Encode(ctx context.Context, p envoy.Provider) error {
+	var e *envoy.ResourceState
+	var err error
+
+	se.source = &bytes.Buffer{}
+	se.wb = newWorkbookWriter(se.cfg.Format)
+	se.sheets = make(map[string]bool)
+
+	// Encode the resources into sheets
+	for {
+		e, err = p.NextInverted(ctx)
+
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			break
+		}
+
+		state := se.resState[e.Res]
+		if state == nil {
+			err = ErrResourceStateUndefined
+		} else {
+			err = state.Encode(ctx, sheetName(state.SheetName(), se.sheets), se.wb, e)
+		}
+
+		if err != nil {
+			return se.WrapError("encode: build sheet", e.Res, err)
+		}
+	}
+
+	if len(se.sheets) == 0 {
+		// workbook needs at least one sheet
+		if err = se.wb.addSheet(sheetName("", se.sheets)); err != nil {
+			return err
+		}
+	}
+
+	for r := range se.resState {
+		se.resState[r] = nil
+	}
+
+	if err = se.wb.writeTo(se.source); err != nil {
+		return fmt.Errorf("%s encoder: %w", se.cfg.Format, err)
+	}
+
+	return nil
+}
+
+// Stream returns the workbook
+//
+// Since all of the records are encoded into the same document, there is only one stream
+func (se *bulkRecordEncoder) Stream() []*envoy.Stream {
+	if se.source == nil {
+		return nil
+	}
+
+	return []*envoy.Stream{{
+		Resource:   types.RecordResourceType,
+		Identifier: "records." + se.cfg.Format,
+		Source:     se.source,
+	}}
+}
+
+// WrapError wraps errors related to spreadsheet encoding
+//
+// Always wrap your errors.
+func (se *bulkRecordEncoder) WrapError(act string, res resource.Interface, err error) error {
+	rt := strings.Join(strings.Split(strings.TrimSpace(strings.TrimRight(res.ResourceType(), ":")), ":"), " ")
+	return fmt.Errorf("%s encoder %s %s %v: %s", se.cfg.Format, act, rt, res.Identifiers().StringSlice(), err)
+}
+
+func loadLocation(tz string) (*time.Location, error) {
+	if tz == "" {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(tz)
+}
+
+func encoderErrInvalidResource(exp, got string) error {
+	return fmt.Errorf("invalid resource type: expecting %s, got %s", exp, got)
+}
@@ -0,0 +1,383 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	odsWriter struct {
+		// sheets are written directly into the content body
+		body   bytes.Buffer
+		inside bool
+	}
+
+	// odsCellReader collects cell attributes and text
+	odsCellReader struct {
+		valueType string
+		value     string
+		repeated  int
+
+		text       strings.Builder
+		paragraphs int
+	}
+)
+
+const (
+	odsNsOffice = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	odsNsTable  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	odsNsText   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+
+	// repeated empty rows and columns are used to fill the sheet;
+	// anything above is not expanded
+	odsMaxRepeat = 1000
+
+	odsManifest = `<?xml version="1.0" encoding="UTF-8"?>
+<manifest:manifest xmlns:manifest="urn:oasis:names:tc:opendocument:xmlns:manifest:1.0" manifest:version="1.2">
+ <manifest:file-entry manifest:full-path="/" manifest:version="1.2" manifest:media-type="application/vnd.oasis.opendocument.spreadsheet"/>
+ <manifest:file-entry manifest:full-path="content.xml" manifest:media-type="text/xml"/>
+</manifest:manifest>`
+
+	odsContentHead = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content` +
+		` xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"` +
+		` xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0"` +
+		` xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"` +
+		` xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"` +
+		` xmlns:number="urn:oasis:names:tc:opendocument:xmlns:datastyle:1.0"` +
+		` xmlns:fo="urn:oasis:names:tc:opendocument:xmlns:xsl-fo-compatible:1.0"` +
+		` office:version="1.2">
+<office:automatic-styles>
+ <number:date-style style:name="N_DATE"><number:year number:style="long"/><number:text>-</number:text><number:month number:style="long"/><number:text>-</number:text><number:day number:style="long"/></number:date-style>
+ <number:date-style style:name="N_DATETIME"><number:year number:style="long"/><number:text>-</number:text><number:month number:style="long"/><number:text>-</number:text><number:day number:style="long"/><number:text> </number:text><number:hours number:style="long"/><number:text>:</number:text><number:minutes number:style="long"/><number:text>:</number:text><number:seconds number:style="long"/></number:date-style>
+ <number:time-style style:name="N_TIME"><number:hours number:style="long"/><number:text>:</number:text><number:minutes number:style="long"/><number:text>:</number:text><number:seconds number:style="long"/></number:time-style>
+ <style:style style:name="ce_header" style:family="table-cell"><style:text-properties fo:font-weight="bold"/></style:style>
+ <style:style style:name="ce_date" style:family="table-cell" style:data-style-name="N_DATE"/>
+ <style:style style:name="ce_time" style:family="table-cell" style:data-style-name="N_TIME"/>
+ <style:style style:name="ce_datetime" style:family="table-cell" style:data-style-name="N_DATETIME"/>
+</office:automatic-styles>
+<office:body><office:spreadsheet>`
+
+	odsContentTail = `</office:spreadsheet></office:body></office:document-content>`
+)
+
+var (
+	// ISO 8601 duration as used for time values (PT15H04M05S)
+	odsDuration = regexp.MustCompile(`^-?PT(\d+)H(\d+)M(\d+)(?:[.,]\d+)?S$`)
+)
+
+// readOds reads rows of all sheets in the document
+//
+// Typed cells are read from their value attributes so that the values
+// do not depend on the formatting; empty rows are omitted.
+func readOds(zr *zip.Reader) ([]*sheet, error) {
+	var (
+		content io.ReadCloser
+		err     error
+
+		ss = make([]*sheet, 0, 4)
+
+		s   *sheet
+		row []string
+
+		rowRepeat int
+		c         *odsCellReader
+	)
+
+	for _, f := range zr.File {
+		if f.Name == "content.xml" {
+			if content, err = f.Open(); err != nil {
+				return nil, fmt.Errorf("could not open ods document: %w", err)
+			}
+
+			break
+		}
+	}
+
+	if content == nil {
+		return nil, fmt.Errorf("could not open ods document: content missing")
+	}
+
+	defer content.Close()
+
+	dec := xml.NewDecoder(content)
+	for {
+		tkn, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read ods document: %w", err)
+		}
+
+		switch t := tkn.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == odsNsTable && t.Name.Local == "table":
+				s = &sheet{name: odsAttr(t, odsNsTable, "name")}
+				ss = append(ss, s)
+
+			case t.Name.Space == odsNsTable && t.Name.Local == "table-row":
+				row = make([]string, 0, 16)
+				rowRepeat = odsRepeat(odsAttr(t, odsNsTable, "number-rows-repeated"))
+
+			case t.Name.Space == odsNsTable && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				c = &odsCellReader{
+					valueType: odsAttr(t, odsNsOffice, "value-type"),
+					repeated:  odsRepeat(odsAttr(t, odsNsTable, "number-columns-repeated")),
+				}
+
+				switch c.valueType {
+				case "float", "percentage", "currency":
+					c.value = odsAttr(t, odsNsOffice, "value")
+				case "date":
+					// same layout as dates read from xlsx documents
+					c.value = strings.Replace(odsAttr(t, odsNsOffice, "date-value"), "T", " ", 1)
+				case "time":
+					c.value = odsAttr(t, odsNsOffice, "time-value")
+				case "boolean":
+					c.value = odsAttr(t, odsNsOffice, "boolean-value")
+				}
+
+			case c != nil && t.Name.Space == odsNsText:
+				switch t.Name.Local {
+				case "p":
+					if c.paragraphs > 0 {
+						c.text.WriteString("\n")
+					}
+					c.paragraphs++
+
+				case "s":
+					n, _ := strconv.Atoi(odsAttr(t, odsNsText, "c"))
+					if n < 1 {
+						n = 1
+					}
+					c.text.WriteString(strings.Repeat(" ", n))
+
+				case "tab":
+					c.text.WriteString("\t")
+
+				case "line-break":
+					c.text.WriteString("\n")
+				}
+			}
+
+		case xml.CharData:
+			if c != nil && c.paragraphs > 0 {
+				c.text.Write(t)
+			}
+
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == odsNsTable && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				v := c.String()
+				if v == "" && c.repeated > odsMaxRepeat {
+					// filler till the end of the row
+					c.repeated = 1
+				}
+
+				for i := 0; i < c.repeated; i++ {
+					row = append(row, v)
+				}
+
+				c = nil
+
+			case t.Name.Space == odsNsTable && t.Name.Local == "table-row":
+				if s == nil || isEmptyRow(row) {
+					continue
+				}
+
+				// trim trailing empty cells
+				for len(row) > 0 && row[len(row)-1] == "" {
+					row = row[:len(row)-1]
+				}
+
+				if rowRepeat > odsMaxRepeat {
+					rowRepeat = odsMaxRepeat
+				}
+
+				for i := 0; i < rowRepeat; i++ {
+					s.rows = append(s.rows, row)
+				}
+			}
+		}
+	}
+
+	return ss, nil
+}
+
+// String returns cell value in the format record values are sanitized from
+func (c *odsCellReader) String() string {
+	switch c.valueType {
+	case "float", "percentage", "currency", "boolean":
+		return c.value
+
+	case "date":
+		// fractions of seconds are not supported
+		if i := strings.IndexByte(c.value, '.'); i > 0 {
+			return c.value[:i]
+		}
+
+		return c.value
+
+	case "time":
+		if m := odsDuration.FindStringSubmatch(c.value); m != nil {
+			h, _ := strconv.Atoi(m[1])
+			mi, _ := strconv.Atoi(m[2])
+			s, _ := strconv.Atoi(m[3])
+			return fmt.Sprintf("%02d:%02d:%02d", h%24, mi, s)
+		}
+
+		return c.value
+	}
+
+	return c.text.String()
+}
+
+func odsAttr(t xml.StartElement, space, local string) string {
+	for _, a := range t.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+func odsRepeat(v string) int {
+	if n, err := strconv.Atoi(v); err == nil && n > 1 {
+		return n
+	}
+
+	return 1
+}
+
+func newOdsWriter() *odsWriter {
+	return &odsWriter{}
+}
+
+func (o *odsWriter) addSheet(name string) error {
+	o.closeSheet()
+
+	o.body.WriteString(`<table:table table:name="`)
+	o.escape(name)
+	o.body.WriteString(`">`)
+
+	o.inside = true
+	return nil
+}
+
+func (o *odsWriter) writeHeader(hh []string) error {
+	o.body.WriteString(`<table:table-row>`)
+	for _, h := range hh {
+		o.body.WriteString(`<table:table-cell table:style-name="ce_header" office:value-type="string"><text:p>`)
+		o.escape(h)
+		o.body.WriteString(`</text:p></table:table-cell>`)
+	}
+	o.body.WriteString(`</table:table-row>`)
+
+	return nil
+}
+
+func (o *odsWriter) writeRow(cc []cell) error {
+	o.body.WriteString(`<table:table-row>`)
+
+	for _, c := range cc {
+		switch v := c.value.(type) {
+		case string:
+			o.body.WriteString(`<table:table-cell office:value-type="string">`)
+			for _, p := range strings.Split(v, "\n") {
+				o.body.WriteString(`<text:p>`)
+				o.escape(p)
+				o.body.WriteString(`</text:p>`)
+			}
+			o.body.WriteString(`</table:table-cell>`)
+
+		case float64:
+			n := strconv.FormatFloat(v, 'f', -1, 64)
+			fmt.Fprintf(&o.body, `<table:table-cell office:value-type="float" office:value="%s"><text:p>%s</text:p></table:table-cell>`, n, n)
+
+		case bool:
+			fmt.Fprintf(&o.body, `<table:table-cell office:value-type="boolean" office:boolean-value="%t"><text:p>%s</text:p></table:table-cell>`, v, strings.ToUpper(strconv.FormatBool(v)))
+
+		case time.Time:
+			switch c.layout {
+			case cellLayoutTime:
+				fmt.Fprintf(&o.body, `<table:table-cell table:style-name="ce_time" office:value-type="time" office:time-value="PT%02dH%02dM%02dS"><text:p>%s</text:p></table:table-cell>`,
+					v.Hour(), v.Minute(), v.Second(), v.Format("15:04:05"))
+
+			case cellLayoutDate:
+				fmt.Fprintf(&o.body, `<table:table-cell table:style-name="ce_date" office:value-type="date" office:date-value="%s"><text:p>%s</text:p></table:table-cell>`,
+					v.Format("2006-01-02"), v.Format("2006-01-02"))
+
+			default:
+				fmt.Fprintf(&o.body, `<table:table-cell table:style-name="ce_datetime" office:value-type="date" office:date-value="%s"><text:p>%s</text:p></table:table-cell>`,
+					v.Format("2006-01-02T15:04:05"), v.Format("2006-01-02 15:04:05"))
+			}
+
+		default:
+			o.body.WriteString(`<table:table-cell/>`)
+		}
+	}
+
+	o.body.WriteString(`</table:table-row>`)
+	return nil
+}
+
+// writeTo writes the ODS package
+//
+// mimetype file must be the first, uncompressed entry of the package
+func (o *odsWriter) writeTo(w io.Writer) (err error) {
+	var (
+		zw = zip.NewWriter(w)
+		fw io.Writer
+	)
+
+	o.closeSheet()
+
+	if fw, err = zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store}); err != nil {
+		return
+	}
+	if _, err = io.WriteString(fw, mimeOds); err != nil {
+		return
+	}
+
+	if fw, err = zw.Create("META-INF/manifest.xml"); err != nil {
+		return
+	}
+	if _, err = io.WriteString(fw, odsManifest); err != nil {
+		return
+	}
+
+	if fw, err = zw.Create("content.xml"); err != nil {
+		return
+	}
+	if _, err = io.WriteString(fw, odsContentHead); err != nil {
+		return
+	}
+	if _, err = o.body.WriteTo(fw); err != nil {
+		return
+	}
+	if _, err = io.WriteString(fw, odsContentTail); err != nil {
+		return
+	}
+
+	return zw.Close()
+}
+
+func (o *odsWriter) closeSheet() {
+	if o.inside {
+		o.body.WriteString(`</table:table>`)
+		o.inside = false
+	}
+}
+
+func (o *odsWriter) escape(s string) {
+	_ = xml.EscapeText(&o.body, []byte(s))
+}
@@ -0,0 +1,214 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	xlsxWriter struct {
+		f  *excelize.File
+		sw *excelize.StreamWriter
+
+		sheets int
+		row    int
+
+		// style IDs
+		styles map[string]int
+	}
+)
+
+var (
+	// Number formats of date/time cells
+	//
+	// Formats are the same as the ones the values are sanitized into
+	// so they can be imported back without any conversion.
+	xlsxNumFmt = map[string]string{
+		cellLayoutDate:     "yyyy-mm-dd",
+		cellLayoutTime:     "hh:mm:ss",
+		cellLayoutDateTime: "yyyy-mm-dd hh:mm:ss",
+	}
+)
+
+const (
+	xlsxStyleHeader    = "header"
+	xlsxStyleMultiline = "multiline"
+)
+
+// readXlsx reads rows of all sheets in the workbook
+//
+// Empty rows are omitted.
+func readXlsx(buf []byte) ([]*sheet, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(buf))
+	if err != nil {
+		return nil, fmt.Errorf("could not open xlsx document: %w", err)
+	}
+
+	ss := make([]*sheet, 0, f.SheetCount)
+	for _, name := range f.GetSheetList() {
+		rr, err := f.GetRows(name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read xlsx sheet %s: %w", name, err)
+		}
+
+		s := &sheet{name: name, rows: make([][]string, 0, len(rr))}
+		for _, r := range rr {
+			if !isEmptyRow(r) {
+				s.rows = append(s.rows, r)
+			}
+		}
+
+		ss = append(ss, s)
+	}
+
+	return ss, nil
+}
+
+func newXlsxWriter() *xlsxWriter {
+	return &xlsxWriter{
+		f:      excelize.NewFile(),
+		styles: make(map[string]int),
+	}
+}
+
+func (x *xlsxWriter) addSheet(name string) (err error) {
+	if err = x.flush(); err != nil {
+		return
+	}
+
+	if x.sheets == 0 {
+		// new workbook is always created with one (default) sheet
+		x.f.SetSheetName(x.f.GetSheetName(0), name)
+	} else {
+		x.f.NewSheet(name)
+	}
+
+	x.sheets++
+	x.row = 0
+
+	x.sw, err = x.f.NewStreamWriter(name)
+	return
+}
+
+func (x *xlsxWriter) writeHeader(hh []string) error {
+	style, err := x.style(xlsxStyleHeader)
+	if err != nil {
+		return err
+	}
+
+	row := make([]interface{}, len(hh))
+	for i, h := range hh {
+		row[i] = excelize.Cell{StyleID: style, Value: h}
+	}
+
+	return x.setRow(row)
+}
+
+func (x *xlsxWriter) writeRow(cc []cell) (err error) {
+	var (
+		row   = make([]interface{}, len(cc))
+		style int
+	)
+
+	for i, c := range cc {
+		switch v := c.value.(type) {
+		case time.Time:
+			if style, err = x.style(c.layout); err != nil {
+				return
+			}
+
+			if c.layout == cellLayoutTime {
+				// time only values are stored as a fraction of the day
+				row[i] = excelize.Cell{StyleID: style, Value: float64(v.Hour()*3600+v.Minute()*60+v.Second()) / 86400}
+			} else {
+				// serial date values are truncated to seconds when read, so a
+				// millisecond is added to compensate for floating point errors
+				row[i] = excelize.Cell{StyleID: style, Value: v.Add(time.Millisecond)}
+			}
+
+		case string:
+			if strings.ContainsRune(v, '\n') {
+				if style, err = x.style(xlsxStyleMultiline); err != nil {
+					return
+				}
+
+				row[i] = excelize.Cell{StyleID: style, Value: v}
+			} else {
+				row[i] = v
+			}
+
+		default:
+			row[i] = v
+		}
+	}
+
+	return x.setRow(row)
+}
+
+func (x *xlsxWriter) writeTo(w io.Writer) (err error) {
+	if err = x.flush(); err != nil {
+		return
+	}
+
+	_, err = x.f.WriteTo(w)
+	return
+}
+
+func (x *xlsxWriter) setRow(row []interface{}) error {
+	if x.sw == nil {
+		return fmt.Errorf("no sheet to write into")
+	}
+
+	x.row++
+	axis, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+
+	return x.sw.SetRow(axis, row)
+}
+
+func (x *xlsxWriter) flush() error {
+	if x.sw == nil {
+		return nil
+	}
+
+	defer func() { x.sw = nil }()
+	return x.sw.Flush()
+}
+
+// style returns (and registers when needed) ID of the named style
+func (x *xlsxWriter) style(name string) (id int, err error) {
+	var (
+		has bool
+		s   = &excelize.Style{}
+	)
+
+	if id, has = x.styles[name]; has {
+		return
+	}
+
+	switch name {
+	case xlsxStyleHeader:
+		s.Font = &excelize.Font{Bold: true}
+
+	case xlsxStyleMultiline:
+		s.Alignment = &excelize.Alignment{WrapText: true, Vertical: "top"}
+
+	default:
+		numFmt := xlsxNumFmt[name]
+		s.CustomNumFmt = &numFmt
+	}
+
+	if id, err = x.f.NewStyle(s); err != nil {
+		return
+	}
+
+	x.styles[name] = id
+	return
+}
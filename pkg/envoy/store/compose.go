@@ -212,6 +212,26 @@ func (d *composeDecoder) decodeComposeRecord(ctx context.Context, s store.Storer
 		return rr
 	}
 
+	// all values of fields with more than one value
+	mapMultiValues := func(r *types.Record) map[string][]string {
+		var (
+			vv = make(map[string][]string)
+			rr = make(map[string][]string)
+		)
+
+		for _, v := range r.Values {
+			vv[v.Name] = append(vv[v.Name], v.Value)
+		}
+
+		for k, v := range vv {
+			if len(v) > 1 {
+				rr[k] = v
+			}
+		}
+
+		return rr
+	}
+
 	// Prepare a series of resource.ComposeRecord instances; one for each provided filter
 	for _, f := range ff {
 		aux := *f
@@ -262,10 +282,11 @@ func (d *composeDecoder) decodeComposeRecord(ctx context.Context, s store.Storer
 				for _, n := range rr {
 					// Create a raw record
 					r := &resource.ComposeRecordRaw{
-						ID:     strconv.FormatUint(n.ID, 10),
-						Values: mapValues(n),
-						Ts:     resource.MakeTimestampsCUDA(&n.CreatedAt, n.UpdatedAt, n.DeletedAt, nil),
-						Us:     resource.MakeUserstampsCUDO(n.CreatedBy, n.UpdatedBy, n.DeletedBy, n.OwnedBy),
+						ID:          strconv.FormatUint(n.ID, 10),
+						Values:      mapValues(n),
+						MultiValues: mapMultiValues(n),
+						Ts:          resource.MakeTimestampsCUDA(&n.CreatedAt, n.UpdatedAt, n.DeletedAt, nil),
+						Us:          resource.MakeUserstampsCUDO(n.CreatedBy, n.UpdatedBy, n.DeletedBy, n.OwnedBy),
 					}
 
 					err = cb(r)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/service"
@@ -330,78 +331,87 @@ func (n *composeRecord) Encode(ctx context.Context, pl *payload) (err error) {
 			service.RecordUpdateOwner(pl.invokerID, rec, old)
 
 			rvs := make(composeTypes.RecordValueSet, 0, len(r.Values))
-			for k, v := range r.Values {
-				rv := &composeTypes.RecordValue{
-					RecordID: rec.ID,
-					Name:     k,
-					Value:    v,
-					Updated:  true,
-				}
-
+			for k, raw := range r.Values {
 				f := mod.Fields.FindByName(k)
-				if f != nil && v != "" {
-					switch f.Kind {
-					case "User":
-						uID := ux[v]
-						if uID == 0 {
-							return resource.UserErrUnresolved(resource.MakeIdentifiers(v))
-						}
-						rv.Value = strconv.FormatUint(uID, 10)
-						rv.Ref = uID
 
-					case "Record":
-						refIdentifiers, ok := n.fieldModRef[f.Name]
-						if !ok {
-							return fmt.Errorf("module field record reference not resoled: %s", f.Name)
-						}
+				vv := []string{raw}
+				if f != nil && f.Multi && r.MultiValueDelimiter != "" {
+					vv = strings.Split(raw, r.MultiValueDelimiter)
+				}
 
-						// if self...
-						if n.res.RefMod.Identifiers.HasAny(refIdentifiers) {
-							rID := im[v]
+				for place, v := range vv {
+					rv := &composeTypes.RecordValue{
+						RecordID: rec.ID,
+						Name:     k,
+						Value:    v,
+						Place:    uint(place),
+						Updated:  true,
+					}
 
-							// Check if its in the store
-							if rID == 0 {
-								// Check if we have an xref
-								rID, err = checkXRef(refIdentifiers, v)
-								if err != nil {
-									return err
-								}
+					if f != nil && v != "" {
+						switch f.Kind {
+						case "User":
+							uID := ux[v]
+							if uID == 0 {
+								return resource.UserErrUnresolved(resource.MakeIdentifiers(v))
 							}
+							rv.Value = strconv.FormatUint(uID, 10)
+							rv.Ref = uID
 
-							if rID == 0 {
-								return resource.ComposeRecordErrUnresolved(resource.MakeIdentifiers(v))
-							}
-							rv.Value = strconv.FormatUint(rID, 10)
-							rv.Ref = rID
-						} else {
-							// not self...
-							rID := uint64(0)
-							refRes := resource.FindComposeRecordResource(pl.state.ParentResources, refIdentifiers)
-
-							if refRes != nil {
-								// check if parent has it
-								rID = refRes.IDMap[v]
+						case "Record":
+							refIdentifiers, ok := n.fieldModRef[f.Name]
+							if !ok {
+								return fmt.Errorf("module field record reference not resoled: %s", f.Name)
 							}
 
-							if rID == 0 {
-								// Check if we have an xref
-								rID, err = checkXRef(refIdentifiers, v)
-								if err != nil {
-									return err
+							// if self...
+							if n.res.RefMod.Identifiers.HasAny(refIdentifiers) {
+								rID := im[v]
+
+								// Check if its in the store
+								if rID == 0 {
+									// Check if we have an xref
+									rID, err = checkXRef(refIdentifiers, v)
+									if err != nil {
+										return err
+									}
 								}
-							}
 
-							if rID == 0 {
-								return fmt.Errorf("referenced record not resolved: %s", resource.ComposeRecordErrUnresolved(resource.MakeIdentifiers(v)))
-							}
+								if rID == 0 {
+									return resource.ComposeRecordErrUnresolved(resource.MakeIdentifiers(v))
+								}
+								rv.Value = strconv.FormatUint(rID, 10)
+								rv.Ref = rID
+							} else {
+								// not self...
+								rID := uint64(0)
+								refRes := resource.FindComposeRecordResource(pl.state.ParentResources, refIdentifiers)
+
+								if refRes != nil {
+									// check if parent has it
+									rID = refRes.IDMap[v]
+								}
 
-							rv.Value = strconv.FormatUint(rID, 10)
-							rv.Ref = rID
+								if rID == 0 {
+									// Check if we have an xref
+									rID, err = checkXRef(refIdentifiers, v)
+									if err != nil {
+										return err
+									}
+								}
+
+								if rID == 0 {
+									return fmt.Errorf("referenced record not resolved: %s", resource.ComposeRecordErrUnresolved(resource.MakeIdentifiers(v)))
+								}
+
+								rv.Value = strconv.FormatUint(rID, 10)
+								rv.Ref = rID
+							}
 						}
 					}
-				}
 
-				rvs = append(rvs, rv)
+					rvs = append(rvs, rv)
+				}
 			}
 
 			if err = service.RecordValueSanitization(mod, rvs); err != nil {
@@ -65,7 +65,7 @@ func Test_oauth2(t *testing.T) {
 			var (
 				req    = require.New(t)
 				c      = http.DefaultClient
-				_, err = NewOauth2(tc.params, c, struct{}{})
+				_, err = NewOauth2(tc.params, c, nil)
 			)
 
 			if tc.err != "" {
@@ -12,7 +12,7 @@ var _sonyflake *sonyflake.Sonyflake
 
 func init() {
 	_sonyflake = sonyflake.NewSonyflake(sonyflake.Settings{
-		StartTime: time.Unix(jan1st2017, 0),
+		StartTime: time.Unix(jan1st2017, 0), MachineID: func() (uint16, error) { return 1, nil },
 	})
 }
 
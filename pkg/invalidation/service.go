@@ -0,0 +1,159 @@
+package invalidation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"go.uber.org/zap"
+)
+
+type (
+	service struct {
+		log       *zap.Logger
+		nodeID    string
+		transport Transport
+
+		// Read & write locking
+		l sync.RWMutex
+
+		handlers map[string][]HandlerFn
+	}
+
+	// Notification about the change of the cached data
+	Notification struct {
+		// Kind of the cached data that changed
+		Kind string `json:"kind"`
+
+		// Node that made the change
+		Node string `json:"node"`
+	}
+
+	// Transport broadcasts notifications between nodes
+	Transport interface {
+		// Publish broadcasts notification to all other nodes
+		Publish(ctx context.Context, n Notification) error
+
+		// Subscribe starts receiving notifications from other nodes
+		Subscribe(ctx context.Context, fn func(context.Context, Notification)) error
+	}
+
+	// HandlerFn reloads the cached data
+	HandlerFn func(ctx context.Context) error
+)
+
+const (
+	TransportStore      = "store"
+	TransportMessagebus = "messagebus"
+)
+
+var (
+	// Global invalidation service
+	gInvalidation *service
+)
+
+// Setup configures global invalidation service
+//
+// Without enabled invalidation, global service is not set and
+// all (package level) registrations and notifications are ignored
+func Setup(ctx context.Context, log *zap.Logger, s versionStore, nodeID string, opt options.InvalidationOpt) (err error) {
+	gInvalidation = nil
+
+	if !opt.Enabled {
+		return nil
+	}
+
+	log = log.Named("invalidation").With(zap.String("nodeID", nodeID))
+
+	var t Transport
+
+	switch opt.Transport {
+	case TransportStore:
+		t = StoreTransport(log, s, nodeID, opt.PollInterval)
+	case TransportMessagebus:
+		if t, err = MessagebusTransport(ctx, log, nodeID, opt.Queue, opt.RedisURL); err != nil {
+			return fmt.Errorf("could not initialize messagebus cache invalidation transport: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported cache invalidation transport %q", opt.Transport)
+	}
+
+	gInvalidation = NewService(log, t, nodeID)
+	return nil
+}
+
+func Service() *service {
+	return gInvalidation
+}
+
+func NewService(log *zap.Logger, t Transport, nodeID string) *service {
+	return &service{
+		log:       log,
+		nodeID:    nodeID,
+		transport: t,
+		handlers:  make(map[string][]HandlerFn),
+	}
+}
+
+// Register adds handler that is called when other nodes
+// notify about the change of the given kind of data
+func (svc *service) Register(kind string, fn HandlerFn) {
+	svc.l.Lock()
+	defer svc.l.Unlock()
+
+	svc.handlers[kind] = append(svc.handlers[kind], fn)
+}
+
+// Notify broadcasts the change of the given kind of data to other nodes
+func (svc *service) Notify(ctx context.Context, kind string) {
+	err := svc.transport.Publish(ctx, Notification{Kind: kind, Node: svc.nodeID})
+	if err != nil {
+		svc.log.Error("failed to publish invalidation notification", zap.String("kind", kind), zap.Error(err))
+	}
+}
+
+// Watch starts receiving notifications from other nodes
+func (svc *service) Watch(ctx context.Context) error {
+	return svc.transport.Subscribe(ctx, svc.handle)
+}
+
+func (svc *service) handle(ctx context.Context, n Notification) {
+	if n.Node == svc.nodeID {
+		return
+	}
+
+	svc.l.RLock()
+	hh := svc.handlers[n.Kind]
+	svc.l.RUnlock()
+
+	svc.log.Debug("cached data changed on another node",
+		zap.String("kind", n.Kind),
+		zap.String("node", n.Node),
+		zap.Int("handlers", len(hh)),
+	)
+
+	for _, h := range hh {
+		if err := h(ctx); err != nil {
+			svc.log.Error("failed to reload cached data", zap.String("kind", n.Kind), zap.Error(err))
+		}
+	}
+}
+
+// Register handler on the global invalidation service
+func Register(kind string, fn HandlerFn) {
+	if gInvalidation == nil {
+		return
+	}
+
+	gInvalidation.Register(kind, fn)
+}
+
+// Notify other nodes about the change using global invalidation service
+func Notify(ctx context.Context, kind string) {
+	if gInvalidation == nil {
+		return
+	}
+
+	gInvalidation.Notify(ctx, kind)
+}
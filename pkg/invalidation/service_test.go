@@ -0,0 +1,231 @@
+package invalidation
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type (
+	mockVersionStore struct {
+		l  sync.Mutex
+		vv map[string]types.Version
+	}
+)
+
+func (s *mockVersionStore) SearchInvalidationVersions(ctx context.Context, f types.VersionFilter) (set types.VersionSet, _ types.VersionFilter, _ error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	for _, v := range s.vv {
+		if len(f.Kind) > 0 && f.Kind[0] != v.Kind {
+			continue
+		}
+
+		v := v
+		set = append(set, &v)
+	}
+
+	return set, f, nil
+}
+
+func (s *mockVersionStore) UpsertInvalidationVersion(ctx context.Context, rr ...*types.Version) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	for _, r := range rr {
+		s.vv[r.Kind] = *r
+	}
+
+	return nil
+}
+
+func TestService_StoreTransport(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		s = &mockVersionStore{vv: make(map[string]types.Version)}
+
+		node = func(nodeID string) *service {
+			return NewService(zap.NewNop(), StoreTransport(zap.NewNop(), s, nodeID, time.Millisecond), nodeID)
+		}
+
+		n1 = node("n1")
+		n2 = node("n2")
+
+		reloads = make(chan string, 10)
+		handler = func(nodeID string) HandlerFn {
+			return func(ctx context.Context) error {
+				reloads <- nodeID
+				return nil
+			}
+		}
+
+		expect = func(nodeID string) {
+			select {
+			case r := <-reloads:
+				req.Equal(nodeID, r)
+			case <-time.After(time.Second):
+				req.FailNow("cached data not reloaded on " + nodeID)
+			}
+		}
+	)
+
+	defer cancel()
+
+	// changes made before nodes start watching are ignored
+	n1.Notify(ctx, "rbac")
+
+	n1.Register("rbac", handler("n1"))
+	n2.Register("rbac", handler("n2"))
+	req.NoError(n1.Watch(ctx))
+	req.NoError(n2.Watch(ctx))
+
+	n1.Notify(ctx, "rbac")
+	expect("n2")
+
+	n2.Notify(ctx, "rbac")
+	expect("n1")
+
+	// unknown kinds are ignored
+	n2.Notify(ctx, "settings")
+
+	time.Sleep(time.Millisecond * 20)
+	req.Empty(reloads)
+}
+
+func TestService_StoreTransportEmpty(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		// no versions stored yet
+		s = &mockVersionStore{vv: make(map[string]types.Version)}
+
+		n1 = NewService(zap.NewNop(), StoreTransport(zap.NewNop(), s, "n1", time.Millisecond), "n1")
+		n2 = NewService(zap.NewNop(), StoreTransport(zap.NewNop(), s, "n2", time.Millisecond), "n2")
+
+		reloads = make(chan struct{}, 10)
+	)
+
+	defer cancel()
+
+	n2.Register("rbac", func(ctx context.Context) error {
+		reloads <- struct{}{}
+		return nil
+	})
+
+	req.NoError(n1.Watch(ctx))
+	req.NoError(n2.Watch(ctx))
+
+	// let n2 poll the empty table a few times
+	time.Sleep(time.Millisecond * 20)
+
+	// very first version in the store must not be taken as a baseline
+	n1.Notify(ctx, "rbac")
+
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		req.FailNow("first change not noticed")
+	}
+}
+
+func TestService_StoreTransportPublishAfterChange(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		s = &mockVersionStore{vv: make(map[string]types.Version)}
+
+		// n2 polls rarely so that it publishes its own change
+		// before it notices the change made by n1
+		n1 = NewService(zap.NewNop(), StoreTransport(zap.NewNop(), s, "n1", time.Millisecond), "n1")
+		n2 = NewService(zap.NewNop(), StoreTransport(zap.NewNop(), s, "n2", time.Millisecond*50), "n2")
+
+		reloads = make(chan struct{}, 10)
+	)
+
+	defer cancel()
+
+	n2.Register("rbac", func(ctx context.Context) error {
+		reloads <- struct{}{}
+		return nil
+	})
+
+	req.NoError(n1.Watch(ctx))
+	req.NoError(n2.Watch(ctx))
+
+	n1.Notify(ctx, "rbac")
+	n2.Notify(ctx, "rbac")
+
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		req.FailNow("change made by the other node was overwritten and not noticed")
+	}
+}
+
+func TestService_MessagebusTransport(t *testing.T) {
+	var (
+		req = require.New(t)
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		srv = miniredis.RunT(t)
+
+		node = func(nodeID string) *service {
+			tr, err := MessagebusTransport(ctx, zap.NewNop(), nodeID, "cache-invalidation", "redis://"+srv.Addr())
+			req.NoError(err)
+			return NewService(zap.NewNop(), tr, nodeID)
+		}
+
+		// three nodes, each in its own consumer group
+		n1 = node("n1")
+		n2 = node("n2")
+		n3 = node("n3")
+
+		reloads = make(chan string, 10)
+		handler = func(nodeID string) HandlerFn {
+			return func(ctx context.Context) error {
+				reloads <- nodeID
+				return nil
+			}
+		}
+	)
+
+	defer cancel()
+
+	for _, n := range []*service{n1, n2, n3} {
+		n.Register("rbac", handler(n.nodeID))
+		req.NoError(n.Watch(ctx))
+	}
+
+	// notification is delivered to all other nodes
+	n1.Notify(ctx, "rbac")
+
+	got := make([]string, 0, 2)
+	for len(got) < 2 {
+		select {
+		case r := <-reloads:
+			got = append(got, r)
+		case <-time.After(time.Second):
+			req.FailNow("cached data not reloaded on all nodes", "reloaded on %v", got)
+		}
+	}
+
+	req.ElementsMatch([]string{"n2", "n3"}, got)
+
+	time.Sleep(time.Millisecond * 20)
+	req.Empty(reloads)
+}
@@ -0,0 +1,132 @@
+package invalidation
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/redis"
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/types"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"go.uber.org/zap"
+)
+
+type (
+	messagebusTransport struct {
+		log    *zap.Logger
+		client messagebusClient
+
+		// delay after failed read
+		retryDelay time.Duration
+	}
+
+	messagebusClient interface {
+		redis.Client
+		DestroyGroup(context.Context) error
+	}
+)
+
+const (
+	// notifications older than that are useless;
+	// keep the stream short
+	messagebusMaxLen = 1000
+)
+
+// MessagebusTransport pushes notifications to the messagebus (redis) stream
+//
+// Every node reads the stream in its own consumer group so that
+// each notification is delivered to all nodes (and not just to one
+// of the consumers in the group as with the messagebus queues).
+// Consumer group is removed when node stops watching.
+func MessagebusTransport(ctx context.Context, log *zap.Logger, nodeID, queue, url string) (*messagebusTransport, error) {
+	opts, err := redis.MakeOptions(queue, types.QueueMeta{
+		RedisGroup:    queue + ":" + nodeID,
+		RedisConsumer: nodeID,
+		RedisMaxLen:   messagebusMaxLen,
+	}, url)
+
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := redis.NewClient(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &messagebusTransport{
+		log:        log.With(zap.String("stream", opts.Stream), zap.String("group", opts.Group)),
+		client:     client,
+		retryDelay: time.Second * 5,
+	}, nil
+}
+
+func (t *messagebusTransport) Publish(ctx context.Context, n Notification) error {
+	p, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	return t.client.Add(ctx, p)
+}
+
+func (t *messagebusTransport) Subscribe(ctx context.Context, fn func(context.Context, Notification)) error {
+	go func() {
+		defer sentry.Recover()
+		defer t.close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if err := t.read(ctx, fn); err != nil && ctx.Err() == nil {
+				t.log.Error("failed to read cache invalidation notifications", zap.Error(err))
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(t.retryDelay):
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// read handles and acknowledges one batch of notifications
+func (t *messagebusTransport) read(ctx context.Context, fn func(context.Context, Notification)) error {
+	mm, err := t.client.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	ack := make([]string, 0, len(mm))
+	for _, m := range mm {
+		n := Notification{}
+		if err = json.Unmarshal(m.Payload, &n); err != nil {
+			t.log.Warn("invalid invalidation notification", zap.Error(err))
+		} else {
+			fn(ctx, n)
+		}
+
+		ack = append(ack, m.ID)
+	}
+
+	return t.client.Ack(ctx, ack...)
+}
+
+// close removes consumer group of this node and closes the connection
+func (t *messagebusTransport) close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	if err := t.client.DestroyGroup(ctx); err != nil {
+		t.log.Warn("failed to remove consumer group", zap.Error(err))
+	}
+
+	_ = t.client.Close()
+}
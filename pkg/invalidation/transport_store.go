@@ -0,0 +1,163 @@
+package invalidation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation/types"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"go.uber.org/zap"
+)
+
+type (
+	storeTransport struct {
+		log      *zap.Logger
+		store    versionStore
+		nodeID   string
+		interval time.Duration
+
+		// Read & write locking
+		l sync.Mutex
+
+		// last known versions
+		known map[string]uint64
+
+		// set after the first poll; versions loaded
+		// by the first poll are not notified
+		primed bool
+
+		// changes of other nodes that were noticed when publishing;
+		// notified with the next poll
+		missed []Notification
+	}
+
+	versionStore interface {
+		SearchInvalidationVersions(ctx context.Context, f types.VersionFilter) (types.VersionSet, types.VersionFilter, error)
+		UpsertInvalidationVersion(ctx context.Context, rr ...*types.Version) error
+	}
+)
+
+const (
+	defaultPollInterval = time.Second * 5
+)
+
+// StoreTransport keeps versions of the cached data in the store
+//
+// Publishing sets new version of the data;
+// nodes poll versions and notice when they change
+func StoreTransport(log *zap.Logger, s versionStore, nodeID string, interval time.Duration) *storeTransport {
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	return &storeTransport{
+		log:      log,
+		store:    s,
+		nodeID:   nodeID,
+		interval: interval,
+		known:    make(map[string]uint64),
+	}
+}
+
+func (t *storeTransport) Publish(ctx context.Context, n Notification) error {
+	// version that is about to be overwritten might be set
+	// by another node after the last poll
+	vv, _, err := t.store.SearchInvalidationVersions(ctx, types.VersionFilter{Kind: []string{n.Kind}})
+	if err != nil {
+		return err
+	}
+
+	t.l.Lock()
+	for _, v := range vv {
+		if t.primed && t.known[v.Kind] != v.Version && v.Node != n.Node {
+			t.missed = append(t.missed, Notification{Kind: v.Kind, Node: v.Node})
+		}
+	}
+	t.l.Unlock()
+
+	v := &types.Version{
+		Kind:      n.Kind,
+		Version:   id.Next(),
+		Node:      n.Node,
+		UpdatedAt: time.Now(),
+	}
+
+	if err := t.store.UpsertInvalidationVersion(ctx, v); err != nil {
+		return err
+	}
+
+	// we do not want to be notified about our own changes
+	t.l.Lock()
+	t.known[v.Kind] = v.Version
+	t.l.Unlock()
+
+	return nil
+}
+
+func (t *storeTransport) Subscribe(ctx context.Context, fn func(context.Context, Notification)) error {
+	// load current versions so that only changes
+	// made from now on are noticed
+	if _, err := t.poll(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		defer sentry.Recover()
+
+		var (
+			ticker = time.NewTicker(t.interval)
+		)
+
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				nn, err := t.poll(ctx)
+				if err != nil {
+					t.log.Error("failed to poll cache versions", zap.Error(err))
+					continue
+				}
+
+				for _, n := range nn {
+					fn(ctx, n)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// poll loads versions from the store and returns notifications for all changed
+func (t *storeTransport) poll(ctx context.Context) (nn []Notification, err error) {
+	vv, _, err := t.store.SearchInvalidationVersions(ctx, types.VersionFilter{})
+	if err != nil {
+		return
+	}
+
+	t.l.Lock()
+	defer t.l.Unlock()
+
+	nn, t.missed = t.missed, nil
+
+	for _, v := range vv {
+		if t.known[v.Kind] == v.Version {
+			continue
+		}
+
+		t.known[v.Kind] = v.Version
+
+		if t.primed {
+			nn = append(nn, Notification{Kind: v.Kind, Node: v.Node})
+		}
+	}
+
+	t.primed = true
+	return
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+)
+
+type (
+	// Version of the cached data
+	//
+	// Each change of the data that is cached by the nodes
+	// sets new version; nodes reload cached data when they
+	// notice version change
+	Version struct {
+		Kind      string    `json:"kind"`
+		Version   uint64    `json:"version,string"`
+		Node      string    `json:"node"`
+		UpdatedAt time.Time `json:"updatedAt"`
+	}
+
+	VersionSet []*Version
+
+	VersionFilter struct {
+		Kind []string `json:"kind"`
+	}
+)
@@ -0,0 +1,112 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/redis"
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/types"
+	"go.uber.org/zap"
+)
+
+type (
+	// RedisConsumer writes messages to the redis stream
+	//
+	// When queue has dispatching enabled, consumer also reads the stream
+	// (as a member of the consumer group) and dispatches messages to the eventbus.
+	// Messages are acknowledged after all event handlers are done; messages
+	// that fail are left pending and are redelivered after claim-idle time.
+	RedisConsumer struct {
+		queue      string
+		handle     types.ConsumerType
+		client     redis.Client
+		dispatch   bool
+		dispatcher Dispatcher
+		servicer   types.QueueEventBuilder
+		logger     *zap.Logger
+
+		// delay after failed read
+		retryDelay time.Duration
+	}
+)
+
+func NewRedisConsumer(ctx context.Context, q string, meta types.QueueMeta, defaultURL string, servicer types.QueueEventBuilder, logger *zap.Logger) (*RedisConsumer, error) {
+	opts, err := redis.MakeOptions(q, meta, defaultURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := redis.NewClient(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &RedisConsumer{
+		queue:      q,
+		handle:     types.ConsumerRedis,
+		client:     client,
+		dispatch:   meta.DispatchEvents,
+		dispatcher: eventbus.Service(),
+		servicer:   servicer,
+		logger:     logger.With(zap.String("queue", q), zap.String("stream", opts.Stream)),
+		retryDelay: time.Second * 5,
+	}
+
+	return h, nil
+}
+
+func (cq *RedisConsumer) Write(ctx context.Context, p []byte) error {
+	return cq.client.Add(ctx, p)
+}
+
+// Listen reads and dispatches messages until context is canceled
+//
+// Connection to redis is closed when listener is done
+func (cq *RedisConsumer) Listen(ctx context.Context) {
+	defer cq.client.Close()
+
+	if !cq.dispatch {
+		// only writing to the stream, someone else is reading
+		<-ctx.Done()
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if err := cq.process(ctx); err != nil && ctx.Err() == nil {
+			cq.logger.Warn("could not read messages from stream", zap.Error(err))
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cq.retryDelay):
+			}
+		}
+	}
+}
+
+// process reads one batch of messages and dispatches them
+func (cq *RedisConsumer) process(ctx context.Context) error {
+	mm, err := cq.client.Read(ctx)
+	if err != nil {
+		return err
+	}
+
+	ack := make([]string, 0, len(mm))
+	for _, m := range mm {
+		if err = cq.dispatcher.WaitFor(ctx, cq.servicer.CreateQueueEvent(cq.queue, m.Payload)); err != nil {
+			cq.logger.Warn("could not process message", zap.String("messageID", m.ID), zap.Error(err))
+			continue
+		}
+
+		ack = append(ack, m.ID)
+	}
+
+	return cq.client.Ack(ctx, ack...)
+}
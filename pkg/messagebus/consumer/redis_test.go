@@ -0,0 +1,110 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/redis"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type (
+	mockRedisClient struct {
+		messages []redis.Message
+		acked    []string
+		readErr  error
+	}
+
+	mockDispatcher struct {
+		waitFor func(ctx context.Context, ev eventbus.Event) error
+	}
+
+	mockEventBuilder struct{}
+
+	mockQueueEvent struct {
+		payload []byte
+	}
+)
+
+func (mockQueueEvent) ResourceType() string                  { return "system:queue" }
+func (mockQueueEvent) EventType() string                     { return "onMessage" }
+func (mockQueueEvent) Match(eventbus.ConstraintMatcher) bool { return true }
+
+func (c *mockRedisClient) Add(_ context.Context, p []byte) error {
+	c.messages = append(c.messages, redis.Message{ID: string(p), Payload: p})
+	return nil
+}
+
+func (c *mockRedisClient) Read(context.Context) ([]redis.Message, error) {
+	return c.messages, c.readErr
+}
+
+func (c *mockRedisClient) Ack(_ context.Context, ids ...string) error {
+	c.acked = append(c.acked, ids...)
+	return nil
+}
+
+func (c *mockRedisClient) Close() error { return nil }
+
+func (d mockDispatcher) Dispatch(ctx context.Context, ev eventbus.Event) { _ = d.waitFor(ctx, ev) }
+
+func (d mockDispatcher) WaitFor(ctx context.Context, ev eventbus.Event) error {
+	return d.waitFor(ctx, ev)
+}
+
+func (mockEventBuilder) CreateQueueEvent(q string, p []byte) eventbus.Event {
+	return &mockQueueEvent{payload: p}
+}
+
+func Test_handlerRedisProcess(t *testing.T) {
+	var (
+		req    = require.New(t)
+		ctx    = context.Background()
+		client = &mockRedisClient{}
+
+		cq = &RedisConsumer{
+			queue:  "test",
+			client: client,
+			dispatcher: mockDispatcher{waitFor: func(ctx context.Context, ev eventbus.Event) error {
+				if string(ev.(*mockQueueEvent).payload) == "fail" {
+					return errors.New("could not process message")
+				}
+
+				return nil
+			}},
+			servicer: mockEventBuilder{},
+			logger:   zap.NewNop(),
+		}
+	)
+
+	req.NoError(cq.Write(ctx, []byte("foo")))
+	req.NoError(cq.Write(ctx, []byte("fail")))
+	req.NoError(cq.Write(ctx, []byte("bar")))
+
+	req.NoError(cq.process(ctx))
+
+	// failed message is not acknowledged and stays pending
+	req.Equal([]string{"foo", "bar"}, client.acked)
+}
+
+func Test_handlerRedisProcessReadError(t *testing.T) {
+	var (
+		req    = require.New(t)
+		ctx    = context.Background()
+		client = &mockRedisClient{readErr: errors.New("could not read")}
+
+		cq = &RedisConsumer{
+			queue:      "test",
+			client:     client,
+			dispatcher: mockDispatcher{waitFor: func(context.Context, eventbus.Event) error { return nil }},
+			servicer:   mockEventBuilder{},
+			logger:     zap.NewNop(),
+		}
+	)
+
+	req.Error(cq.process(ctx))
+	req.Empty(client.acked)
+}
@@ -0,0 +1,233 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/types"
+	"github.com/go-redis/redis/v8"
+)
+
+type (
+	Client interface {
+		Add(context.Context, []byte) error
+		Read(context.Context) ([]Message, error)
+		Ack(context.Context, ...string) error
+		Close() error
+	}
+
+	// Message read from the stream
+	//
+	// Message stays pending (within the consumer group) until it's acknowledged
+	Message struct {
+		ID      string
+		Payload []byte
+	}
+
+	// Options for stream & consumer group
+	Options struct {
+		// Redis server URL
+		URL string
+
+		// Stream to write to and read from
+		Stream string
+
+		// Consumer group and name of this consumer in the group
+		Group    string
+		Consumer string
+
+		// Approximate max number of messages kept in the stream (0 for no limit)
+		MaxLen int64
+
+		// Pending messages that were not acknowledged within this time
+		// are claimed and redelivered (to any of the consumers in the group)
+		ClaimIdle time.Duration
+
+		// How long read blocks while waiting for new messages
+		Block time.Duration
+
+		// Max number of messages returned from one read
+		Count int64
+	}
+
+	rClient struct {
+		opts  Options
+		redis *redis.Client
+	}
+)
+
+const (
+	payloadField = "payload"
+
+	defaultGroup     = "corteza"
+	defaultClaimIdle = time.Minute
+	defaultBlock     = time.Second * 5
+	defaultCount     = 10
+)
+
+// MakeOptions prepares client options from queue meta
+//
+// Default URL is used when queue does not have its own
+func MakeOptions(queue string, meta types.QueueMeta, defaultURL string) (o Options, err error) {
+	o = Options{
+		URL:       meta.RedisURL,
+		Stream:    meta.RedisStream,
+		Group:     meta.RedisGroup,
+		Consumer:  meta.RedisConsumer,
+		MaxLen:    meta.RedisMaxLen,
+		ClaimIdle: defaultClaimIdle,
+		Block:     defaultBlock,
+		Count:     defaultCount,
+	}
+
+	if o.URL == "" {
+		o.URL = defaultURL
+	}
+
+	if o.URL == "" {
+		return o, fmt.Errorf("redis URL for queue %s not set", queue)
+	}
+
+	if o.Stream == "" {
+		o.Stream = queue
+	}
+
+	if o.Group == "" {
+		o.Group = defaultGroup
+	}
+
+	if o.Consumer == "" {
+		// consumer name should be stable across restarts
+		// so that our own pending messages are picked up again
+		if o.Consumer, _ = os.Hostname(); o.Consumer == "" {
+			o.Consumer = defaultGroup
+		}
+	}
+
+	if meta.RedisClaimIdle != nil && *meta.RedisClaimIdle > 0 {
+		o.ClaimIdle = *meta.RedisClaimIdle
+	}
+
+	return
+}
+
+// NewClient connects to redis and makes sure consumer group exists
+func NewClient(ctx context.Context, opts Options) (*rClient, error) {
+	ro, err := redis.ParseURL(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	c := &rClient{opts: opts, redis: redis.NewClient(ro)}
+
+	// creates stream and group (reading only new messages);
+	// group might already exist
+	err = c.redis.XGroupCreateMkStream(ctx, opts.Stream, opts.Group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		_ = c.redis.Close()
+		return nil, fmt.Errorf("could not create redis consumer group: %w", err)
+	}
+
+	return c, nil
+}
+
+// Add appends message to the stream
+func (c *rClient) Add(ctx context.Context, payload []byte) error {
+	args := &redis.XAddArgs{
+		Stream: c.opts.Stream,
+		Values: map[string]interface{}{payloadField: payload},
+	}
+
+	if c.opts.MaxLen > 0 {
+		args.MaxLen = c.opts.MaxLen
+		args.Approx = true
+	}
+
+	return c.redis.XAdd(ctx, args).Err()
+}
+
+// Read returns the next batch of messages for this consumer
+//
+// Messages that are pending for longer than ClaimIdle (consumer failed to
+// process or acknowledge them) are claimed first; when there are none, read
+// blocks until new messages arrive or Block time passes.
+func (c *rClient) Read(ctx context.Context) ([]Message, error) {
+	claimed, _, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   c.opts.Stream,
+		Group:    c.opts.Group,
+		Consumer: c.opts.Consumer,
+		MinIdle:  c.opts.ClaimIdle,
+		Start:    "0-0",
+		Count:    c.opts.Count,
+	}).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(claimed) > 0 {
+		return toMessages(claimed), nil
+	}
+
+	ss, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    c.opts.Group,
+		Consumer: c.opts.Consumer,
+		Streams:  []string{c.opts.Stream, ">"},
+		Count:    c.opts.Count,
+		Block:    c.opts.Block,
+	}).Result()
+
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var mm []Message
+	for _, s := range ss {
+		mm = append(mm, toMessages(s.Messages)...)
+	}
+
+	return mm, nil
+}
+
+// Ack acknowledges processed messages and removes them from the pending list
+func (c *rClient) Ack(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return c.redis.XAck(ctx, c.opts.Stream, c.opts.Group, ids...).Err()
+}
+
+// DestroyGroup removes consumer group together with its pending messages
+//
+// Stream and other groups are not affected
+func (c *rClient) DestroyGroup(ctx context.Context) error {
+	return c.redis.XGroupDestroy(ctx, c.opts.Stream, c.opts.Group).Err()
+}
+
+func (c *rClient) Close() error {
+	return c.redis.Close()
+}
+
+func toMessages(xx []redis.XMessage) []Message {
+	mm := make([]Message, 0, len(xx))
+	for _, x := range xx {
+		m := Message{ID: x.ID}
+
+		switch p := x.Values[payloadField].(type) {
+		case string:
+			m.Payload = []byte(p)
+		case []byte:
+			m.Payload = p
+		}
+
+		mm = append(mm, m)
+	}
+
+	return mm
+}
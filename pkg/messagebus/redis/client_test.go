@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cortezaproject/corteza-server/pkg/messagebus/types"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestClient(t *testing.T, srv *miniredis.Miniredis, consumer string) *rClient {
+	opts, err := MakeOptions("test", types.QueueMeta{RedisConsumer: consumer}, "redis://"+srv.Addr())
+	require.NoError(t, err)
+
+	opts.Block = time.Millisecond * 10
+
+	c, err := NewClient(context.Background(), opts)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestMakeOptions(t *testing.T) {
+	var (
+		req  = require.New(t)
+		idle = time.Second * 30
+	)
+
+	_, err := MakeOptions("test", types.QueueMeta{}, "")
+	req.Error(err)
+
+	o, err := MakeOptions("test", types.QueueMeta{}, "redis://localhost:6379")
+	req.NoError(err)
+	req.Equal("redis://localhost:6379", o.URL)
+	req.Equal("test", o.Stream)
+	req.Equal(defaultGroup, o.Group)
+	req.NotEmpty(o.Consumer)
+	req.Equal(defaultClaimIdle, o.ClaimIdle)
+
+	o, err = MakeOptions("test", types.QueueMeta{
+		RedisURL:       "redis://redis:6379/1",
+		RedisStream:    "stream",
+		RedisGroup:     "group",
+		RedisConsumer:  "node1",
+		RedisMaxLen:    100,
+		RedisClaimIdle: &idle,
+	}, "redis://localhost:6379")
+
+	req.NoError(err)
+	req.Equal("redis://redis:6379/1", o.URL)
+	req.Equal("stream", o.Stream)
+	req.Equal("group", o.Group)
+	req.Equal("node1", o.Consumer)
+	req.Equal(int64(100), o.MaxLen)
+	req.Equal(idle, o.ClaimIdle)
+}
+
+func TestClient_AddReadAck(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		srv = miniredis.RunT(t)
+
+		c1 = makeTestClient(t, srv, "c1")
+		c2 = makeTestClient(t, srv, "c2")
+	)
+
+	req.NoError(c1.Add(ctx, []byte("foo")))
+	req.NoError(c1.Add(ctx, []byte("bar")))
+
+	mm, err := c1.Read(ctx)
+	req.NoError(err)
+	req.Len(mm, 2)
+	req.Equal("foo", string(mm[0].Payload))
+	req.Equal("bar", string(mm[1].Payload))
+
+	// each message is delivered to one consumer in the group
+	mm2, err := c2.Read(ctx)
+	req.NoError(err)
+	req.Empty(mm2)
+
+	req.NoError(c1.Ack(ctx, mm[0].ID, mm[1].ID))
+
+	pending, err := c1.redis.XPending(ctx, "test", defaultGroup).Result()
+	req.NoError(err)
+	req.Zero(pending.Count)
+}
+
+func TestClient_PendingRecovery(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		srv = miniredis.RunT(t)
+
+		c1 = makeTestClient(t, srv, "c1")
+		c2 = makeTestClient(t, srv, "c2")
+	)
+
+	req.NoError(c1.Add(ctx, []byte("foo")))
+
+	mm, err := c1.Read(ctx)
+	req.NoError(err)
+	req.Len(mm, 1)
+
+	// c1 failed to process the message, it's still pending
+	// but not long enough to be claimed
+	mm, err = c2.Read(ctx)
+	req.NoError(err)
+	req.Empty(mm)
+
+	srv.SetTime(time.Now().Add(defaultClaimIdle * 2))
+
+	mm, err = c2.Read(ctx)
+	req.NoError(err)
+	req.Len(mm, 1)
+	req.Equal("foo", string(mm[0].Payload))
+	req.NoError(c2.Ack(ctx, mm[0].ID))
+
+	srv.SetTime(time.Now().Add(defaultClaimIdle * 4))
+
+	mm, err = c1.Read(ctx)
+	req.NoError(err)
+	req.Empty(mm)
+}
+
+func TestClient_MaxLen(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		srv = miniredis.RunT(t)
+		c   = makeTestClient(t, srv, "c1")
+	)
+
+	c.opts.MaxLen = 2
+
+	for i := 0; i < 5; i++ {
+		req.NoError(c.Add(ctx, []byte("foo")))
+	}
+
+	l, err := c.redis.XLen(ctx, "test").Result()
+	req.NoError(err)
+	req.Less(l, int64(5))
+}
@@ -28,6 +28,9 @@ type (
 		in     chan types.Message
 		quit   chan bool
 		reload chan bool
+
+		// stops background listeners of the consumers
+		stopListeners context.CancelFunc
 	}
 )
 
@@ -72,6 +75,8 @@ func (mb *messageBus) Init(ctx context.Context, storer types.QueueServicer) {
 // Listen sets read and write channel listeners,
 // used on boot
 func (mb *messageBus) Listen(ctx context.Context) {
+	mb.listenConsumers(ctx)
+
 	// check in channel and act accordingly
 	go func() {
 		for {
@@ -97,10 +102,12 @@ func (mb *messageBus) Listen(ctx context.Context) {
 
 			case <-mb.quit:
 				mb.logger.Debug("quitting from messagebus listener")
+				mb.stopConsumers()
 				return
 
 			case <-ctx.Done():
 				mb.logger.Debug("quitting from messagebus listener")
+				mb.stopConsumers()
 				return
 			}
 		}
@@ -155,6 +162,36 @@ func (mb *messageBus) Push(q string, p []byte) {
 	mb.in <- types.Message{P: p, Q: q}
 }
 
+// listenConsumers starts background listeners of all consumers that have one
+func (mb *messageBus) listenConsumers(ctx context.Context) {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+
+	if mb.stopListeners != nil {
+		mb.stopListeners()
+	}
+
+	ctx, mb.stopListeners = context.WithCancel(ctx)
+
+	for _, q := range mb.queues {
+		if l, is := q.Consumer.(types.ConsumerListener); is {
+			mb.logger.Debug("starting consumer listener", zap.String("queue", q.Name))
+			go l.Listen(ctx)
+		}
+	}
+}
+
+// stopConsumers stops background listeners of all consumers
+func (mb *messageBus) stopConsumers() {
+	mb.mutex.Lock()
+	defer mb.mutex.Unlock()
+
+	if mb.stopListeners != nil {
+		mb.stopListeners()
+		mb.stopListeners = nil
+	}
+}
+
 func (mb *messageBus) Register(ctx context.Context, qs *types.Queue) {
 	mb.queues[qs.Name] = qs
 }
@@ -184,7 +221,7 @@ func (mb *messageBus) initQueues(ctx context.Context) error {
 	for _, q := range list {
 		mb.logger.Debug("initializing queue", zap.String("queue", q.Queue))
 
-		c, err := mb.initConsumer(ctx, q.Queue, q.Consumer)
+		c, err := mb.initConsumer(ctx, q)
 
 		if err != nil {
 			mb.logger.Warn("could not init consumer for queue", zap.String("queue", q.Queue), zap.Error(err))
@@ -206,18 +243,22 @@ func (mb *messageBus) initQueues(ctx context.Context) error {
 }
 
 // initHandler returns a new instance for a specific handler
-func (mb *messageBus) initConsumer(ctx context.Context, q string, c string) (cns types.Consumer, err error) {
-	switch c {
+func (mb *messageBus) initConsumer(ctx context.Context, q types.QueueDb) (cns types.Consumer, err error) {
+	switch q.Consumer {
 	case string(types.ConsumerEventbus):
-		cns = consumer.NewEventbusConsumer(q, mb.qservicer)
+		cns = consumer.NewEventbusConsumer(q.Queue, mb.qservicer)
 		return
 
 	case string(types.ConsumerStore):
-		cns = consumer.NewStoreConsumer(q, mb.qservicer)
+		cns = consumer.NewStoreConsumer(q.Queue, mb.qservicer)
+		return
+
+	case string(types.ConsumerRedis):
+		cns, err = consumer.NewRedisConsumer(ctx, q.Queue, q.Meta, mb.opts.RedisURL, mb.qservicer, mb.logger)
 		return
 
 	default:
-		err = fmt.Errorf("message queue consumer %s not implemented", c)
+		err = fmt.Errorf("message queue consumer %s not implemented", q.Consumer)
 		return
 	}
 }
@@ -18,6 +18,12 @@ type (
 	Consumer interface {
 		Write(ctx context.Context, p []byte) error
 	}
+
+	// ConsumerListener is implemented by consumers that read
+	// messages back and need to run in the background
+	ConsumerListener interface {
+		Listen(ctx context.Context)
+	}
 )
 
 func ConsumerTypes() []ConsumerType {
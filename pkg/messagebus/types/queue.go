@@ -43,6 +43,14 @@ type (
 	QueueMeta struct {
 		PollDelay      *time.Duration `json:"poll_delay"`
 		DispatchEvents bool           `json:"dispatch_events"`
+
+		// Redis consumer settings; stream defaults to queue name
+		RedisURL       string         `json:"redis_url"`
+		RedisStream    string         `json:"redis_stream"`
+		RedisGroup     string         `json:"redis_group"`
+		RedisConsumer  string         `json:"redis_consumer"`
+		RedisMaxLen    int64          `json:"redis_max_len"`
+		RedisClaimIdle *time.Duration `json:"redis_claim_idle"`
 	}
 
 	QueueMessage struct {
@@ -57,7 +65,8 @@ func (h *QueueMeta) UnmarshalJSON(s []byte) error {
 	type Alias QueueMeta
 
 	aux := &struct {
-		PollDelay string `json:"poll_delay"`
+		PollDelay      string `json:"poll_delay"`
+		RedisClaimIdle string `json:"redis_claim_idle"`
 		*Alias
 	}{
 		Alias: (*Alias)(h),
@@ -74,6 +83,10 @@ func (h *QueueMeta) UnmarshalJSON(s []byte) error {
 		h.PollDelay = &d
 	}
 
+	if d, err := cast.ToDurationE(aux.RedisClaimIdle); err == nil && aux.RedisClaimIdle != "" {
+		h.RedisClaimIdle = &d
+	}
+
 	return nil
 }
 
@@ -88,12 +101,31 @@ func (m QueueMeta) MarshalJSON() ([]byte, error) {
 		pollDelay = m.PollDelay.String()
 	}
 
+	redisClaimIdle := ""
+	if m.RedisClaimIdle != nil {
+		redisClaimIdle = m.RedisClaimIdle.String()
+	}
+
 	return json.Marshal(struct {
 		PollDelay      string `json:"poll_delay"`
 		DispatchEvents bool   `json:"dispatch_events"`
+
+		RedisURL       string `json:"redis_url,omitempty"`
+		RedisStream    string `json:"redis_stream,omitempty"`
+		RedisGroup     string `json:"redis_group,omitempty"`
+		RedisConsumer  string `json:"redis_consumer,omitempty"`
+		RedisMaxLen    int64  `json:"redis_max_len,omitempty"`
+		RedisClaimIdle string `json:"redis_claim_idle,omitempty"`
 	}{
 		PollDelay:      pollDelay,
 		DispatchEvents: m.DispatchEvents,
+
+		RedisURL:       m.RedisURL,
+		RedisStream:    m.RedisStream,
+		RedisGroup:     m.RedisGroup,
+		RedisConsumer:  m.RedisConsumer,
+		RedisMaxLen:    m.RedisMaxLen,
+		RedisClaimIdle: redisClaimIdle,
 	})
 }
 
@@ -0,0 +1,405 @@
+package cas
+
+// Content addressed object store
+//
+// Wraps one of the regular object stores (plain, minio) and stores
+// file contents under the name derived from the content hash.
+// Identical files are stored only once; file names are mapped to blobs
+// through object store references (see store.ObjstoreReferences).
+// Blob is removed when the last reference to it is removed.
+//
+// References of the blob are counted in the store (see store.ObjstoreBlobs)
+// with conditional updates inside of a store transaction so that nodes
+// sharing the same store and backend agree on when the blob content
+// needs to be stored and when it can be removed.
+//
+// Files that were stored before content addressing was enabled
+// (and have no reference) are still accessible under their original name;
+// they are removed when overwritten. See Rehash for migrating them.
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/objstore"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/pkg/errors"
+)
+
+type (
+	refStore interface {
+		store.ObjstoreReferences
+		store.ObjstoreBlobs
+	}
+
+	blobStore struct {
+		backend objstore.Store
+		refs    refStore
+
+		// runs reference bookkeeping in a store transaction
+		tx func(ctx context.Context, fn func(context.Context, refStore) error) error
+
+		// namespace of the references (compose, system)
+		namespace string
+
+		// prefix used for blob names on the backend
+		prefix string
+	}
+
+	readCloser struct {
+		io.Reader
+		io.Closer
+	}
+)
+
+const (
+	blobsFolder = "blobs"
+
+	// number of attempts to store a file when another node
+	// creates the same blob at the same time
+	saveAttempts = 3
+)
+
+// New wraps backend object store with content addressing
+//
+// Namespace is used to separate references of different object stores
+// that share the same references store.
+// Prefix is prepended to all blob names stored on the backend.
+func New(backend objstore.Store, s store.Storer, namespace, prefix string) (*blobStore, error) {
+	if s == nil {
+		return nil, errors.New("references store not set")
+	}
+
+	tx := func(ctx context.Context, fn func(context.Context, refStore) error) error {
+		return store.Tx(ctx, s, func(ctx context.Context, s store.Storer) error {
+			return fn(ctx, s)
+		})
+	}
+
+	return newBlobStore(backend, s, tx, namespace, prefix)
+}
+
+func newBlobStore(backend objstore.Store, refs refStore, tx func(context.Context, func(context.Context, refStore) error) error, namespace, prefix string) (*blobStore, error) {
+	if backend == nil {
+		return nil, errors.New("backend object store not set")
+	}
+
+	if refs == nil {
+		return nil, errors.New("references store not set")
+	}
+
+	return &blobStore{
+		backend:   backend,
+		refs:      refs,
+		tx:        tx,
+		namespace: namespace,
+		prefix:    prefix,
+	}, nil
+}
+
+func (s *blobStore) Original(id uint64, ext string) string {
+	return s.backend.Original(id, ext)
+}
+
+func (s *blobStore) Preview(id uint64, ext string) string {
+	return s.backend.Preview(id, ext)
+}
+
+// Save stores content as a blob (when not already stored) and references it under the given filename
+//
+// Content is hashed while it is copied to a temporary file
+// so that it never needs to be held in memory as a whole
+func (s *blobStore) Save(filename string, f io.Reader) (err error) {
+	if len(filename) == 0 {
+		return errors.Errorf("invalid filename when trying to store file: '%s' (for %s)", filename, s.namespace)
+	}
+
+	tmp, err := ioutil.TempFile("", "corteza-objstore-*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	h := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tmp, h), f); err != nil {
+		return
+	}
+
+	return s.save(context.Background(), filename, hex.EncodeToString(h.Sum(nil)), tmp)
+}
+
+func (s *blobStore) save(ctx context.Context, filename, hash string, content io.ReadSeeker) (err error) {
+	var (
+		// file was not content-addressed before
+		unreferenced bool
+
+		// blob was created by another node at the same time
+		conflict bool
+	)
+
+	for attempt := 1; ; attempt++ {
+		unreferenced, conflict = false, false
+		err = s.tx(ctx, func(ctx context.Context, refs refStore) (err error) {
+			ref, err := s.lookup(ctx, refs, filename)
+			if err != nil {
+				return
+			}
+
+			if ref != nil && ref.Hash == hash {
+				// same content already stored under the same name
+				return nil
+			}
+
+			created, err := refs.AcquireObjstoreBlob(ctx, &types.Blob{
+				Namespace: s.namespace,
+				Hash:      hash,
+				CreatedAt: time.Now(),
+			})
+
+			if err != nil {
+				conflict = true
+				return
+			}
+
+			if created {
+				// content is stored before the transaction is committed;
+				// nodes referencing or releasing the same blob wait for it
+				if _, err = content.Seek(0, io.SeekStart); err != nil {
+					return
+				}
+
+				if err = s.backend.Save(s.blobName(hash), content); err != nil {
+					return
+				}
+			}
+
+			if ref == nil {
+				unreferenced = true
+				return refs.CreateObjstoreReference(ctx, &types.Reference{
+					Namespace: s.namespace,
+					Name:      filename,
+					Hash:      hash,
+					CreatedAt: time.Now(),
+				})
+			}
+
+			// file is overwritten with a different content;
+			// point the reference to the new blob and release the old one
+			old := ref.Hash
+			ref.Hash = hash
+			if err = refs.UpdateObjstoreReference(ctx, ref); err != nil {
+				return
+			}
+
+			return s.release(ctx, refs, old)
+		})
+
+		if !conflict || attempt >= saveAttempts {
+			break
+		}
+	}
+
+	if err != nil || !unreferenced {
+		return
+	}
+
+	// file stored before content addressing was enabled
+	// is overwritten and no longer accessible
+	return s.removeUnreferenced(filename)
+}
+
+// Remove removes reference and the blob when it is no longer referenced
+func (s *blobStore) Remove(filename string) (err error) {
+	var (
+		ctx = context.Background()
+	)
+
+	return s.tx(ctx, func(ctx context.Context, refs refStore) (err error) {
+		var ref *types.Reference
+		if ref, err = s.lookup(ctx, refs, filename); err != nil {
+			return
+		}
+
+		if ref == nil {
+			// not content-addressed (yet)
+			return s.backend.Remove(filename)
+		}
+
+		if err = refs.DeleteObjstoreReference(ctx, ref); err != nil {
+			return
+		}
+
+		return s.release(ctx, refs, ref.Hash)
+	})
+}
+
+func (s *blobStore) Open(filename string) (io.ReadSeeker, error) {
+	name, err := s.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.backend.Open(name)
+}
+
+// Size returns size of the referenced blob
+func (s *blobStore) Size(filename string) (int64, error) {
+	name, err := s.resolve(filename)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.size(name)
+}
+
+// size returns size of the object on the backend
+func (s *blobStore) size(name string) (int64, error) {
+	if rr, ok := s.backend.(objstore.RangeReader); ok {
+		return rr.Size(name)
+	}
+
+	fh, err := s.backend.Open(name)
+	if err != nil {
+		return 0, err
+	}
+
+	defer closeIfCloser(fh)
+	return fh.Seek(0, io.SeekEnd)
+}
+
+// OpenRange opens part of the referenced blob
+//
+// When backend does not support range reads,
+// file is opened and positioned to the offset
+func (s *blobStore) OpenRange(filename string, offset, length int64) (io.ReadCloser, error) {
+	name, err := s.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if rr, ok := s.backend.(objstore.RangeReader); ok {
+		return rr.OpenRange(name, offset, length)
+	}
+
+	fh, err := s.backend.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = fh.Seek(offset, io.SeekStart); err != nil {
+		closeIfCloser(fh)
+		return nil, err
+	}
+
+	rc := &readCloser{Reader: io.LimitReader(fh, length), Closer: ioutil.NopCloser(nil)}
+	if c, ok := fh.(io.Closer); ok {
+		rc.Closer = c
+	}
+
+	return rc, nil
+}
+
+// Rehash moves file that was stored without content addressing
+// under the content addressed blob
+//
+// Returns false when file is already content-addressed
+func (s *blobStore) Rehash(filename string) (bool, error) {
+	ref, err := s.lookup(context.Background(), s.refs, filename)
+	if err != nil || ref != nil {
+		return false, err
+	}
+
+	fh, err := s.backend.Open(filename)
+	if err != nil {
+		return false, err
+	}
+
+	// original file is removed when saved under the reference
+	err = s.Save(filename, fh)
+	closeIfCloser(fh)
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *blobStore) Healthcheck(ctx context.Context) error {
+	if s == nil {
+		return fmt.Errorf("uninitialized")
+	}
+
+	return s.backend.Healthcheck(ctx)
+}
+
+// resolve returns name of the blob on the backend
+//
+// Original filename is returned for files without reference
+func (s *blobStore) resolve(filename string) (string, error) {
+	ref, err := s.lookup(context.Background(), s.refs, filename)
+	if err != nil {
+		return "", err
+	}
+
+	if ref == nil {
+		return filename, nil
+	}
+
+	return s.blobName(ref.Hash), nil
+}
+
+// lookup returns reference for the filename or nil when file is not referenced
+func (s *blobStore) lookup(ctx context.Context, refs refStore, filename string) (*types.Reference, error) {
+	ref, err := refs.LookupObjstoreReferenceByNamespaceName(ctx, s.namespace, filename)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, nil
+	}
+
+	return ref, err
+}
+
+// release removes one reference from the blob and
+// removes blob content when it is no longer referenced
+func (s *blobStore) release(ctx context.Context, refs refStore, hash string) error {
+	if removed, err := refs.ReleaseObjstoreBlob(ctx, s.namespace, hash); err != nil || !removed {
+		return err
+	}
+
+	return s.backend.Remove(s.blobName(hash))
+}
+
+// removeUnreferenced removes file stored under its original name (if it exists)
+func (s *blobStore) removeUnreferenced(filename string) error {
+	if _, err := s.size(filename); err != nil {
+		return nil
+	}
+
+	return s.backend.Remove(filename)
+}
+
+// blobName returns name of the blob on the backend
+//
+// Blobs are spread across sub-folders by the first 2 characters of the hash
+// to avoid huge number of files in a single folder
+func (s *blobStore) blobName(hash string) string {
+	return path.Join(s.prefix, blobsFolder, hash[:2], hash)
+}
+
+func closeIfCloser(r io.Reader) {
+	if c, ok := r.(io.Closer); ok {
+		_ = c.Close()
+	}
+}
@@ -0,0 +1,354 @@
+package cas
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/objstore"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	testRefs struct {
+		rr map[string]*types.Reference
+		bb map[string]*types.Blob
+
+		// number of acquire calls that fail as if blob was created by another node
+		conflicts int
+	}
+)
+
+func newTestRefs() *testRefs {
+	return &testRefs{
+		rr: make(map[string]*types.Reference),
+		bb: make(map[string]*types.Blob),
+	}
+}
+
+func newTestStore(backend objstore.Store, refs *testRefs) (*blobStore, error) {
+	tx := func(ctx context.Context, fn func(context.Context, refStore) error) error {
+		return fn(ctx, refs)
+	}
+
+	return newBlobStore(backend, refs, tx, "test", "test")
+}
+
+func (t *testRefs) SearchObjstoreReferences(ctx context.Context, f types.ReferenceFilter) (set types.ReferenceSet, _ types.ReferenceFilter, err error) {
+	for _, r := range t.rr {
+		if r.Namespace == f.Namespace && r.Hash == f.Hash {
+			set = append(set, r)
+		}
+	}
+
+	return set, f, nil
+}
+
+func (t *testRefs) LookupObjstoreReferenceByNamespaceName(ctx context.Context, namespace string, name string) (*types.Reference, error) {
+	if r, ok := t.rr[namespace+"/"+name]; ok {
+		c := *r
+		return &c, nil
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func (t *testRefs) CreateObjstoreReference(ctx context.Context, rr ...*types.Reference) error {
+	return t.UpsertObjstoreReference(ctx, rr...)
+}
+
+func (t *testRefs) UpdateObjstoreReference(ctx context.Context, rr ...*types.Reference) error {
+	return t.UpsertObjstoreReference(ctx, rr...)
+}
+
+func (t *testRefs) UpsertObjstoreReference(ctx context.Context, rr ...*types.Reference) error {
+	for _, r := range rr {
+		t.rr[r.Namespace+"/"+r.Name] = r
+	}
+
+	return nil
+}
+
+func (t *testRefs) DeleteObjstoreReference(ctx context.Context, rr ...*types.Reference) error {
+	for _, r := range rr {
+		delete(t.rr, r.Namespace+"/"+r.Name)
+	}
+
+	return nil
+}
+
+func (t *testRefs) DeleteObjstoreReferenceByNamespaceName(ctx context.Context, namespace string, name string) error {
+	delete(t.rr, namespace+"/"+name)
+	return nil
+}
+
+func (t *testRefs) TruncateObjstoreReferences(ctx context.Context) error {
+	t.rr = make(map[string]*types.Reference)
+	return nil
+}
+
+func (t *testRefs) SearchObjstoreBlobs(ctx context.Context, f types.BlobFilter) (set types.BlobSet, _ types.BlobFilter, err error) {
+	for _, b := range t.bb {
+		if b.Namespace == f.Namespace {
+			set = append(set, b)
+		}
+	}
+
+	return set, f, nil
+}
+
+func (t *testRefs) LookupObjstoreBlobByNamespaceHash(ctx context.Context, namespace string, hash string) (*types.Blob, error) {
+	if b, ok := t.bb[namespace+"/"+hash]; ok {
+		c := *b
+		return &c, nil
+	}
+
+	return nil, store.ErrNotFound
+}
+
+func (t *testRefs) CreateObjstoreBlob(ctx context.Context, bb ...*types.Blob) error {
+	return t.UpsertObjstoreBlob(ctx, bb...)
+}
+
+func (t *testRefs) UpdateObjstoreBlob(ctx context.Context, bb ...*types.Blob) error {
+	return t.UpsertObjstoreBlob(ctx, bb...)
+}
+
+func (t *testRefs) UpsertObjstoreBlob(ctx context.Context, bb ...*types.Blob) error {
+	for _, b := range bb {
+		t.bb[b.Namespace+"/"+b.Hash] = b
+	}
+
+	return nil
+}
+
+func (t *testRefs) DeleteObjstoreBlob(ctx context.Context, bb ...*types.Blob) error {
+	for _, b := range bb {
+		delete(t.bb, b.Namespace+"/"+b.Hash)
+	}
+
+	return nil
+}
+
+func (t *testRefs) DeleteObjstoreBlobByNamespaceHash(ctx context.Context, namespace string, hash string) error {
+	delete(t.bb, namespace+"/"+hash)
+	return nil
+}
+
+func (t *testRefs) TruncateObjstoreBlobs(ctx context.Context) error {
+	t.bb = make(map[string]*types.Blob)
+	return nil
+}
+
+func (t *testRefs) AcquireObjstoreBlob(ctx context.Context, b *types.Blob) (bool, error) {
+	if t.conflicts > 0 {
+		t.conflicts--
+		return false, fmt.Errorf("duplicate blob")
+	}
+
+	if e, ok := t.bb[b.Namespace+"/"+b.Hash]; ok {
+		e.Refs++
+		return false, nil
+	}
+
+	b.Refs = 1
+	t.bb[b.Namespace+"/"+b.Hash] = b
+	return true, nil
+}
+
+func (t *testRefs) ReleaseObjstoreBlob(ctx context.Context, namespace string, hash string) (bool, error) {
+	b, ok := t.bb[namespace+"/"+hash]
+	if !ok {
+		return false, nil
+	}
+
+	if b.Refs--; b.Refs > 0 {
+		return false, nil
+	}
+
+	delete(t.bb, namespace+"/"+hash)
+	return true, nil
+}
+
+func TestStore(t *testing.T) {
+	var (
+		req  = require.New(t)
+		fs   = afero.NewMemMapFs()
+		refs = newTestRefs()
+
+		read = func(r io.Reader, err error) string {
+			req.NoError(err)
+			b, err := ioutil.ReadAll(r)
+			req.NoError(err)
+			return string(b)
+		}
+	)
+
+	backend, err := plain.NewWithAfero(fs, "test")
+	req.NoError(err)
+
+	s, err := newTestStore(backend, refs)
+	req.NoError(err)
+
+	// store same content under 2 names
+	req.NoError(s.Save("test/1.pdf", bytes.NewBufferString("same content")))
+	req.NoError(s.Save("test/2.pdf", bytes.NewBufferString("same content")))
+	req.Len(refs.rr, 2)
+	req.Equal(refs.rr["test/test/1.pdf"].Hash, refs.rr["test/test/2.pdf"].Hash)
+
+	blob := s.blobName(refs.rr["test/test/1.pdf"].Hash)
+	_, err = fs.Stat(blob)
+	req.NoError(err, "blob should be stored")
+
+	_, err = fs.Stat("test/1.pdf")
+	req.Error(err, "file should not be stored under its name")
+
+	req.Equal("same content", read(s.Open("test/1.pdf")))
+	req.Equal("same content", read(s.Open("test/2.pdf")))
+
+	// range reads
+	size, err := s.Size("test/1.pdf")
+	req.NoError(err)
+	req.Equal(int64(12), size)
+	req.Equal("content", read(s.OpenRange("test/1.pdf", 5, 7)))
+
+	// removing one reference keeps the blob
+	req.NoError(s.Remove("test/1.pdf"))
+	_, err = fs.Stat(blob)
+	req.NoError(err)
+	req.Equal("same content", read(s.Open("test/2.pdf")))
+
+	// removing last reference removes the blob
+	req.NoError(s.Remove("test/2.pdf"))
+	_, err = fs.Stat(blob)
+	req.Error(err)
+	req.Empty(refs.rr)
+	req.Empty(refs.bb)
+}
+
+func TestStoreOverwrite(t *testing.T) {
+	var (
+		req  = require.New(t)
+		fs   = afero.NewMemMapFs()
+		refs = newTestRefs()
+	)
+
+	backend, err := plain.NewWithAfero(fs, "test")
+	req.NoError(err)
+
+	s, err := newTestStore(backend, refs)
+	req.NoError(err)
+
+	req.NoError(s.Save("test/1.pdf", bytes.NewBufferString("v1")))
+	old := s.blobName(refs.rr["test/test/1.pdf"].Hash)
+
+	req.NoError(s.Save("test/1.pdf", bytes.NewBufferString("v2")))
+	_, err = fs.Stat(old)
+	req.Error(err, "unreferenced blob should be removed")
+	req.Len(refs.bb, 1)
+
+	fh, err := s.Open("test/1.pdf")
+	req.NoError(err)
+	b, _ := ioutil.ReadAll(fh)
+	req.Equal("v2", string(b))
+}
+
+func TestStoreRehash(t *testing.T) {
+	var (
+		req  = require.New(t)
+		fs   = afero.NewMemMapFs()
+		refs = newTestRefs()
+	)
+
+	backend, err := plain.NewWithAfero(fs, "test")
+	req.NoError(err)
+
+	// file stored before content addressing was enabled
+	req.NoError(backend.Save("test/1.pdf", bytes.NewBufferString("legacy")))
+
+	s, err := newTestStore(backend, refs)
+	req.NoError(err)
+
+	// still accessible
+	fh, err := s.Open("test/1.pdf")
+	req.NoError(err)
+	b, _ := ioutil.ReadAll(fh)
+	req.Equal("legacy", string(b))
+
+	migrated, err := s.Rehash("test/1.pdf")
+	req.NoError(err)
+	req.True(migrated)
+
+	_, err = fs.Stat("test/1.pdf")
+	req.Error(err, "original file should be removed")
+
+	fh, err = s.Open("test/1.pdf")
+	req.NoError(err)
+	b, _ = ioutil.ReadAll(fh)
+	req.Equal("legacy", string(b))
+
+	migrated, err = s.Rehash("test/1.pdf")
+	req.NoError(err)
+	req.False(migrated)
+}
+
+func TestStoreOverwriteLegacy(t *testing.T) {
+	var (
+		req  = require.New(t)
+		fs   = afero.NewMemMapFs()
+		refs = newTestRefs()
+	)
+
+	backend, err := plain.NewWithAfero(fs, "test")
+	req.NoError(err)
+
+	// file stored before content addressing was enabled
+	req.NoError(backend.Save("test/1.pdf", bytes.NewBufferString("legacy")))
+
+	s, err := newTestStore(backend, refs)
+	req.NoError(err)
+
+	req.NoError(s.Save("test/1.pdf", bytes.NewBufferString("v2")))
+
+	_, err = fs.Stat("test/1.pdf")
+	req.Error(err, "overwritten original file should be removed")
+
+	fh, err := s.Open("test/1.pdf")
+	req.NoError(err)
+	b, _ := ioutil.ReadAll(fh)
+	req.Equal("v2", string(b))
+}
+
+func TestStoreSaveConflict(t *testing.T) {
+	var (
+		req  = require.New(t)
+		fs   = afero.NewMemMapFs()
+		refs = newTestRefs()
+	)
+
+	backend, err := plain.NewWithAfero(fs, "test")
+	req.NoError(err)
+
+	s, err := newTestStore(backend, refs)
+	req.NoError(err)
+
+	// blob created by another node in the meantime
+	refs.conflicts = saveAttempts - 1
+	req.NoError(s.Save("test/1.pdf", bytes.NewBufferString("content")))
+
+	fh, err := s.Open("test/1.pdf")
+	req.NoError(err)
+	b, _ := ioutil.ReadAll(fh)
+	req.Equal("content", string(b))
+
+	refs.conflicts = saveAttempts
+	req.Error(s.Save("test/2.pdf", bytes.NewBufferString("content")))
+	req.Len(refs.rr, 1)
+}
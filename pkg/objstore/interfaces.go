@@ -24,3 +24,13 @@ type Store interface {
 	// Healthcheck checks health status of the store
 	Healthcheck(ctx context.Context) error
 }
+
+// RangeReader is implemented by stores that can read a part of the file
+// without reading the whole content
+type RangeReader interface {
+	// Size returns size of the file (in bytes)
+	Size(filename string) (int64, error)
+
+	// OpenRange returns reader for length bytes of the file, starting at the offset
+	OpenRange(filename string, offset, length int64) (io.ReadCloser, error)
+}
@@ -30,6 +30,7 @@ type (
 		PutObject(bucketName, objectName string, reader io.Reader, objectSize int64, opts minio.PutObjectOptions) (n int64, err error)
 		RemoveObject(bucketName, objectName string) error
 		GetObject(bucketName, objectName string, opts minio.GetObjectOptions) (*minio.Object, error)
+		StatObject(bucketName, objectName string, opts minio.StatObjectOptions) (minio.ObjectInfo, error)
 	}
 
 	store struct {
@@ -138,6 +139,30 @@ func (s store) Open(name string) (io.ReadSeeker, error) {
 	})
 }
 
+func (s store) Size(name string) (int64, error) {
+	info, err := s.mc.StatObject(s.bucket, s.getObjectName(name), minio.StatObjectOptions{
+		GetObjectOptions: minio.GetObjectOptions{ServerSideEncryption: s.sse},
+	})
+
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size, nil
+}
+
+func (s store) OpenRange(name string, offset, length int64) (io.ReadCloser, error) {
+	opts := minio.GetObjectOptions{
+		ServerSideEncryption: s.sse,
+	}
+
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+
+	return s.mc.GetObject(s.bucket, s.getObjectName(name), opts)
+}
+
 func (s *store) Healthcheck(_ context.Context) error {
 	return nil
 }
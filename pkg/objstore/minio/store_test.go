@@ -34,6 +34,10 @@ func (t testMinio) GetObject(bucketName, objectName string, opts minio.GetObject
 	return
 }
 
+func (t testMinio) StatObject(bucketName, objectName string, opts minio.StatObjectOptions) (out minio.ObjectInfo, err error) {
+	return
+}
+
 func TestBucketName(t *testing.T) {
 	type (
 		tf struct {
@@ -20,6 +20,11 @@ type (
 		originalFn func(id uint64, ext string) string
 		previewFn  func(id uint64, ext string) string
 	}
+
+	limitedFile struct {
+		io.Reader
+		io.Closer
+	}
 )
 
 var (
@@ -99,6 +104,39 @@ func (s *store) Open(filename string) (io.ReadSeeker, error) {
 	return s.fs.Open(filename)
 }
 
+func (s *store) Size(filename string) (int64, error) {
+	// check filename for validity
+	if err := s.check(filename); err != nil {
+		return 0, err
+	}
+
+	fi, err := s.fs.Stat(filename)
+	if err != nil {
+		return 0, err
+	}
+
+	return fi.Size(), nil
+}
+
+func (s *store) OpenRange(filename string, offset, length int64) (io.ReadCloser, error) {
+	// check filename for validity
+	if err := s.check(filename); err != nil {
+		return nil, err
+	}
+
+	f, err := s.fs.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &limitedFile{Reader: io.LimitReader(f, length), Closer: f}, nil
+}
+
 func (s *store) Healthcheck(ctx context.Context) error {
 	var (
 		fname = s.namespace + "/.healthcheck"
@@ -0,0 +1,97 @@
+package objstore
+
+import (
+	"errors"
+	"io"
+)
+
+type (
+	// rangeSeeker is io.ReadSeeker on top of the RangeReader
+	//
+	// Underlying range reader is opened on the first read after seek
+	// and reads from the current offset to the end of the file;
+	// this allows http.ServeContent to serve Range requests
+	// without fetching the content that is not requested
+	rangeSeeker struct {
+		rr       RangeReader
+		filename string
+		size     int64
+		offset   int64
+		rc       io.ReadCloser
+	}
+)
+
+// OpenSeeker opens file for streaming reads
+//
+// When store supports range reads, returned handle reads
+// only the parts of the file that are actually requested.
+// In all other cases it falls back to Open.
+func OpenSeeker(s Store, filename string) (io.ReadSeeker, error) {
+	rr, ok := s.(RangeReader)
+	if !ok {
+		return s.Open(filename)
+	}
+
+	size, err := rr.Size(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rangeSeeker{rr: rr, filename: filename, size: size}, nil
+}
+
+func (s *rangeSeeker) Read(p []byte) (n int, err error) {
+	if s.offset >= s.size {
+		return 0, io.EOF
+	}
+
+	if s.rc == nil {
+		if s.rc, err = s.rr.OpenRange(s.filename, s.offset, s.size-s.offset); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = s.rc.Read(p)
+	s.offset += int64(n)
+	return
+}
+
+func (s *rangeSeeker) Seek(offset int64, whence int) (int64, error) {
+	var abs int64
+
+	switch whence {
+	case io.SeekStart:
+		abs = offset
+	case io.SeekCurrent:
+		abs = s.offset + offset
+	case io.SeekEnd:
+		abs = s.size + offset
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if abs < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if abs != s.offset {
+		// underlying reader can not be reused
+		// when position changes
+		if err := s.Close(); err != nil {
+			return 0, err
+		}
+	}
+
+	s.offset = abs
+	return abs, nil
+}
+
+// Close releases underlying range reader (if opened)
+func (s *rangeSeeker) Close() (err error) {
+	if s.rc != nil {
+		err = s.rc.Close()
+		s.rc = nil
+	}
+
+	return
+}
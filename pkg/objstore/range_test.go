@@ -0,0 +1,70 @@
+package objstore_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/objstore"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSeeker(t *testing.T) {
+	var (
+		req = require.New(t)
+	)
+
+	s, err := plain.NewWithAfero(afero.NewMemMapFs(), "test")
+	req.NoError(err)
+	req.NoError(s.Save("test/1.txt", bytes.NewBufferString("0123456789")))
+
+	serve := func(rangeHeader string) *httptest.ResponseRecorder {
+		fh, err := objstore.OpenSeeker(s, "test/1.txt")
+		req.NoError(err)
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if rangeHeader != "" {
+			r.Header.Set("Range", rangeHeader)
+		}
+
+		w := httptest.NewRecorder()
+		http.ServeContent(w, r, "1.txt", time.Now(), fh)
+		return w
+	}
+
+	{
+		w := serve("")
+		req.Equal(http.StatusOK, w.Code)
+		req.Equal("0123456789", w.Body.String())
+	}
+
+	{
+		w := serve("bytes=2-5")
+		req.Equal(http.StatusPartialContent, w.Code)
+		req.Equal("bytes 2-5/10", w.Header().Get("Content-Range"))
+		req.Equal("2345", w.Body.String())
+	}
+
+	{
+		w := serve("bytes=-3")
+		req.Equal(http.StatusPartialContent, w.Code)
+		req.Equal("789", w.Body.String())
+	}
+
+	{
+		// seeking and reading directly
+		fh, err := objstore.OpenSeeker(s, "test/1.txt")
+		req.NoError(err)
+
+		_, err = fh.Seek(4, 0)
+		req.NoError(err)
+		b, err := ioutil.ReadAll(fh)
+		req.NoError(err)
+		req.Equal("456789", string(b))
+	}
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	// Reference links stored file (by its name) with content-addressed blob
+	//
+	// Multiple references can point to the same blob
+	Reference struct {
+		// Namespace of the object store (compose, system)
+		Namespace string
+
+		// Name of the file, as known to the caller
+		Name string
+
+		// Content hash (hex encoded sha256)
+		Hash string
+
+		CreatedAt time.Time
+	}
+
+	// Blob counts references to the content-addressed blob
+	//
+	// Counter is changed only with conditional updates so that competing
+	// nodes agree on when the blob needs to be stored or removed
+	Blob struct {
+		// Namespace of the object store (compose, system)
+		Namespace string
+
+		// Content hash (hex encoded sha256)
+		Hash string
+
+		// Number of references to the blob
+		Refs uint
+
+		CreatedAt time.Time
+	}
+
+	BlobFilter struct {
+		Namespace string
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+
+	ReferenceFilter struct {
+		Namespace string
+		Hash      string
+
+		// Standard helpers for paging and sorting
+		filter.Sorting
+		filter.Paging
+	}
+)
@@ -0,0 +1,82 @@
+package types
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// pkg/objstore/types.yaml
+
+type (
+
+	// BlobSet slice of Blob
+	//
+	// This type is auto-generated.
+	BlobSet []*Blob
+
+	// ReferenceSet slice of Reference
+	//
+	// This type is auto-generated.
+	ReferenceSet []*Reference
+)
+
+// Walk iterates through every slice item and calls w(Blob) err
+//
+// This function is auto-generated.
+func (set BlobSet) Walk(w func(*Blob) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// BlobFilter iterates through every slice item, calls f(Blob) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set BlobSet) Filter(f func(*Blob) (bool, error)) (out BlobSet, err error) {
+	var ok bool
+	out = BlobSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// Walk iterates through every slice item and calls w(Reference) err
+//
+// This function is auto-generated.
+func (set ReferenceSet) Walk(w func(*Reference) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// ReferenceFilter iterates through every slice item, calls f(Reference) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set ReferenceSet) Filter(f func(*Reference) (bool, error)) (out ReferenceSet, err error) {
+	var ok bool
+	out = ReferenceSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
@@ -33,6 +33,7 @@ type (
 		WebappBaseUrl          string `env:"HTTP_WEBAPP_BASE_URL"`
 		WebappBaseDir          string `env:"HTTP_WEBAPP_BASE_DIR"`
 		WebappList             string `env:"HTTP_WEBAPP_LIST"`
+		TrustedProxies         string `env:"HTTP_TRUSTED_PROXIES"`
 		SslTerminated          bool   `env:"HTTP_SSL_TERMINATED"`
 	}
 )
@@ -59,6 +60,7 @@ func HTTPServer() (o *HTTPServerOpt) {
 		WebappBaseUrl:          "/",
 		WebappBaseDir:          "./webapp/public",
 		WebappList:             "admin,compose,workflow,reporter",
+		TrustedProxies:         "127.0.0.0/8,::1/128,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,fc00::/7",
 		SslTerminated:          isSecure(),
 	}
 
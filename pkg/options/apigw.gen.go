@@ -21,6 +21,9 @@ type (
 		ProxyEnableDebugLog  bool          `env:"APIGW_PROXY_ENABLE_DEBUG_LOG"`
 		ProxyFollowRedirects bool          `env:"APIGW_PROXY_FOLLOW_REDIRECTS"`
 		ProxyOutboundTimeout time.Duration `env:"APIGW_PROXY_OUTBOUND_TIMEOUT"`
+		CacheMaxEntries      int           `env:"APIGW_CACHE_MAX_ENTRIES"`
+		SecretsKey           string        `env:"APIGW_SECRETS_KEY"`
+		SecretsPreviousKeys  string        `env:"APIGW_SECRETS_PREVIOUS_KEYS"`
 	}
 )
 
@@ -34,6 +37,7 @@ func Apigw() (o *ApigwOpt) {
 		ProxyEnableDebugLog:  false,
 		ProxyFollowRedirects: true,
 		ProxyOutboundTimeout: time.Second * 30,
+		CacheMaxEntries:      1000,
 	}
 
 	fill(o)
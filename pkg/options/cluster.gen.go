@@ -0,0 +1,44 @@
+package options
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// pkg/options/cluster.yaml
+
+import (
+	"time"
+)
+
+type (
+	ClusterOpt struct {
+		Enabled       bool          `env:"CLUSTER_ENABLED"`
+		NodeID        string        `env:"CLUSTER_NODE_ID"`
+		LeaseTTL      time.Duration `env:"CLUSTER_LEASE_TTL"`
+		RenewInterval time.Duration `env:"CLUSTER_RENEW_INTERVAL"`
+	}
+)
+
+// Cluster initializes and returns a ClusterOpt with default values
+func Cluster() (o *ClusterOpt) {
+	o = &ClusterOpt{
+		Enabled:       false,
+		LeaseTTL:      time.Second * 30,
+		RenewInterval: time.Second * 10,
+	}
+
+	fill(o)
+
+	// Function that allows access to custom logic inside the parent function.
+	// The custom logic in the other file should be like:
+	// func (o *Cluster) Defaults() {...}
+	func(o interface{}) {
+		if def, ok := o.(interface{ Defaults() }); ok {
+			def.Defaults()
+		}
+	}(o)
+
+	return
+}
@@ -0,0 +1,17 @@
+package options
+
+import (
+	"fmt"
+	"os"
+)
+
+func (o *ClusterOpt) Defaults() {
+	if o.NodeID == "" {
+		host, err := os.Hostname()
+		if err != nil || host == "" {
+			host = guessHostname()
+		}
+
+		o.NodeID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+}
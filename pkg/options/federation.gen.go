@@ -21,6 +21,9 @@ type (
 		StructurePageSize        int           `env:"FEDERATION_SYNC_STRUCTURE_PAGE_SIZE"`
 		DataMonitorInterval      time.Duration `env:"FEDERATION_SYNC_DATA_MONITOR_INTERVAL"`
 		DataPageSize             int           `env:"FEDERATION_SYNC_DATA_PAGE_SIZE"`
+		DataPush                 bool          `env:"FEDERATION_SYNC_DATA_PUSH"`
+		DataPushInterval         time.Duration `env:"FEDERATION_SYNC_DATA_PUSH_INTERVAL"`
+		DataFollowReferences     bool          `env:"FEDERATION_SYNC_DATA_FOLLOW_REFERENCES"`
 	}
 )
 
@@ -34,6 +37,9 @@ func Federation() (o *FederationOpt) {
 		StructurePageSize:        1,
 		DataMonitorInterval:      time.Second * 60,
 		DataPageSize:             100,
+		DataPush:                 false,
+		DataPushInterval:         time.Second,
+		DataFollowReferences:     false,
 	}
 
 	fill(o)
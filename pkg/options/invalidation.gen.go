@@ -0,0 +1,46 @@
+package options
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// pkg/options/invalidation.yaml
+
+import (
+	"time"
+)
+
+type (
+	InvalidationOpt struct {
+		Enabled      bool          `env:"INVALIDATION_ENABLED"`
+		Transport    string        `env:"INVALIDATION_TRANSPORT"`
+		PollInterval time.Duration `env:"INVALIDATION_POLL_INTERVAL"`
+		Queue        string        `env:"INVALIDATION_QUEUE"`
+		RedisURL     string        `env:"INVALIDATION_REDIS_URL"`
+	}
+)
+
+// Invalidation initializes and returns a InvalidationOpt with default values
+func Invalidation() (o *InvalidationOpt) {
+	o = &InvalidationOpt{
+		Enabled:      false,
+		Transport:    "store",
+		PollInterval: time.Second * 5,
+		Queue:        "cache-invalidation",
+	}
+
+	fill(o)
+
+	// Function that allows access to custom logic inside the parent function.
+	// The custom logic in the other file should be like:
+	// func (o *Invalidation) Defaults() {...}
+	func(o interface{}) {
+		if def, ok := o.(interface{ Defaults() }); ok {
+			def.Defaults()
+		}
+	}(o)
+
+	return
+}
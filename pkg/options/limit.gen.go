@@ -10,7 +10,14 @@ package options
 
 type (
 	LimitOpt struct {
-		SystemUsers int `env:"LIMIT_SYSTEM_USERS"`
+		SystemUsers         int `env:"LIMIT_SYSTEM_USERS"`
+		SystemRoles         int `env:"LIMIT_SYSTEM_ROLES"`
+		SystemGatewayRoutes int `env:"LIMIT_SYSTEM_GATEWAY_ROUTES"`
+		SystemTemplates     int `env:"LIMIT_SYSTEM_TEMPLATES"`
+		ComposeNamespaces   int `env:"LIMIT_COMPOSE_NAMESPACES"`
+		ComposeModules      int `env:"LIMIT_COMPOSE_MODULES"`
+		ComposeRecords      int `env:"LIMIT_COMPOSE_RECORDS"`
+		AutomationWorkflows int `env:"LIMIT_AUTOMATION_WORKFLOWS"`
 	}
 )
 
@@ -10,8 +10,9 @@ package options
 
 type (
 	MessagebusOpt struct {
-		Enabled    bool `env:"MESSAGEBUS_ENABLED"`
-		LogEnabled bool `env:"MESSAGEBUS_LOG_ENABLED"`
+		Enabled    bool   `env:"MESSAGEBUS_ENABLED"`
+		LogEnabled bool   `env:"MESSAGEBUS_LOG_ENABLED"`
+		RedisURL   string `env:"MESSAGEBUS_REDIS_URL"`
 	}
 )
 
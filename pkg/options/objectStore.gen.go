@@ -11,6 +11,7 @@ package options
 type (
 	ObjectStoreOpt struct {
 		Path            string `env:"STORAGE_PATH"`
+		Dedup           bool   `env:"STORAGE_DEDUP"`
 		MinioEndpoint   string `env:"MINIO_ENDPOINT"`
 		MinioSecure     bool   `env:"MINIO_SECURE"`
 		MinioAccessKey  string `env:"MINIO_ACCESS_KEY"`
@@ -26,6 +27,7 @@ type (
 func ObjectStore() (o *ObjectStoreOpt) {
 	o = &ObjectStoreOpt{
 		Path:        "var/store",
+		Dedup:       false,
 		MinioSecure: true,
 		MinioBucket: "{component}",
 		MinioStrict: false,
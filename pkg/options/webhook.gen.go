@@ -0,0 +1,47 @@
+package options
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+// pkg/options/webhook.yaml
+
+import (
+	"time"
+)
+
+type (
+	WebhookOpt struct {
+		Enabled       bool          `env:"WEBHOOK_ENABLED"`
+		Timeout       time.Duration `env:"WEBHOOK_TIMEOUT"`
+		MaxAttempts   int           `env:"WEBHOOK_MAX_ATTEMPTS"`
+		RetryDelay    time.Duration `env:"WEBHOOK_RETRY_DELAY"`
+		RetryInterval time.Duration `env:"WEBHOOK_RETRY_INTERVAL"`
+	}
+)
+
+// Webhook initializes and returns a WebhookOpt with default values
+func Webhook() (o *WebhookOpt) {
+	o = &WebhookOpt{
+		Enabled:       true,
+		Timeout:       time.Second * 10,
+		MaxAttempts:   8,
+		RetryDelay:    time.Second * 30,
+		RetryInterval: time.Second * 15,
+	}
+
+	fill(o)
+
+	// Function that allows access to custom logic inside the parent function.
+	// The custom logic in the other file should be like:
+	// func (o *Webhook) Defaults() {...}
+	func(o interface{}) {
+		if def, ok := o.(interface{ Defaults() }); ok {
+			def.Defaults()
+		}
+	}(o)
+
+	return
+}
@@ -18,6 +18,8 @@ type (
 		Timeout     time.Duration `env:"WEBSOCKET_TIMEOUT"`
 		PingTimeout time.Duration `env:"WEBSOCKET_PING_TIMEOUT"`
 		PingPeriod  time.Duration `env:"WEBSOCKET_PING_PERIOD"`
+		BusRedisURL string        `env:"WEBSOCKET_BUS_REDIS_URL"`
+		BusChannel  string        `env:"WEBSOCKET_BUS_CHANNEL"`
 	}
 )
 
@@ -27,6 +29,7 @@ func Websocket() (o *WebsocketOpt) {
 		Timeout:     15 * time.Second,
 		PingTimeout: 120 * time.Second,
 		PingPeriod:  ((120 * time.Second) * 9) / 10,
+		BusChannel:  "corteza:websocket",
 	}
 
 	fill(o)
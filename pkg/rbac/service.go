@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/sentry"
 	"go.uber.org/zap"
 )
@@ -45,6 +46,9 @@ var (
 
 const (
 	watchInterval = time.Hour
+
+	// InvalidationKind is used to notify other nodes about changed rules
+	InvalidationKind = "rbac:rule"
 )
 
 // Global returns global RBAC service
@@ -123,7 +127,12 @@ func (svc *service) Grant(ctx context.Context, rules ...*Rule) (err error) {
 	}
 
 	svc.grant(rules...)
-	return svc.flush(ctx)
+	if err = svc.flush(ctx); err != nil {
+		return
+	}
+
+	invalidation.Notify(ctx, InvalidationKind)
+	return
 }
 
 func (svc *service) grant(rules ...*Rule) {
@@ -133,6 +142,12 @@ func (svc *service) grant(rules ...*Rule) {
 
 // Watch reloads RBAC rules in intervals and on request
 func (svc *service) Watch(ctx context.Context) {
+	// reload rules when they are changed on other nodes
+	invalidation.Register(InvalidationKind, func(ctx context.Context) error {
+		svc.Reload(ctx)
+		return nil
+	})
+
 	go func() {
 		defer sentry.Recover()
 
@@ -7,6 +7,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/sentry"
 )
@@ -163,6 +164,13 @@ func (svc *service) Started() (started bool) {
 }
 
 func (svc *service) dispatch(ctx context.Context) {
+	if !cluster.Holds(cluster.LeaderLease) {
+		// scheduled events are dispatched only by the cluster leader
+		// to prevent triggering them on each node
+		svc.log.Debug("not a cluster leader, skipping dispatch")
+		return
+	}
+
 	svc.l.RLock()
 
 	ee := make([]eventbus.Event, len(svc.events))
@@ -0,0 +1,161 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// COSE key parameters
+//
+// See https://www.iana.org/assignments/cose/cose.xhtml
+const (
+	coseKeyKty = 1
+	coseKeyAlg = 3
+
+	// EC2 & OKP key parameters
+	coseKeyCrv = -1
+	coseKeyX   = -2
+	coseKeyY   = -3
+
+	// RSA key parameters
+	coseKeyN = -1
+	coseKeyE = -2
+
+	ktyOKP = 1
+	ktyEC2 = 2
+	ktyRSA = 3
+
+	crvP256    = 1
+	crvEd25519 = 6
+
+	algES256 = -7
+	algEdDSA = -8
+	algRS256 = -257
+)
+
+type (
+	publicKey interface {
+		verify(data, sig []byte) error
+	}
+
+	ec2PublicKey struct{ *ecdsa.PublicKey }
+	okpPublicKey struct{ ed25519.PublicKey }
+	rsaPublicKey struct{ *rsa.PublicKey }
+)
+
+// parsePublicKey decodes COSE encoded credential public key
+func parsePublicKey(raw []byte) (publicKey, error) {
+	var (
+		key = make(map[int]interface{})
+	)
+
+	if err := cbor.Unmarshal(raw, &key); err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+
+	var (
+		kty, _ = coseInt(key[coseKeyKty])
+		alg, _ = coseInt(key[coseKeyAlg])
+	)
+
+	switch {
+	case kty == ktyEC2 && alg == algES256:
+		var (
+			crv, _ = coseInt(key[coseKeyCrv])
+			x, _   = key[coseKeyX].([]byte)
+			y, _   = key[coseKeyY].([]byte)
+		)
+
+		if crv != crvP256 || len(x) == 0 || len(y) == 0 {
+			return nil, fmt.Errorf("invalid EC2 public key")
+		}
+
+		pk := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+
+		if !pk.Curve.IsOnCurve(pk.X, pk.Y) {
+			return nil, fmt.Errorf("invalid EC2 public key")
+		}
+
+		return ec2PublicKey{pk}, nil
+
+	case kty == ktyOKP && alg == algEdDSA:
+		var (
+			crv, _ = coseInt(key[coseKeyCrv])
+			x, _   = key[coseKeyX].([]byte)
+		)
+
+		if crv != crvEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid OKP public key")
+		}
+
+		return okpPublicKey{ed25519.PublicKey(x)}, nil
+
+	case kty == ktyRSA && alg == algRS256:
+		var (
+			n, _ = key[coseKeyN].([]byte)
+			e, _ = key[coseKeyE].([]byte)
+		)
+
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("invalid RSA public key")
+		}
+
+		return rsaPublicKey{&rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported public key type %d with algorithm %d", kty, alg)
+	}
+}
+
+func (pk ec2PublicKey) verify(data, sig []byte) error {
+	h := sha256.Sum256(data)
+	if !ecdsa.VerifyASN1(pk.PublicKey, h[:], sig) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+func (pk okpPublicKey) verify(data, sig []byte) error {
+	if !ed25519.Verify(pk.PublicKey, data, sig) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+func (pk rsaPublicKey) verify(data, sig []byte) error {
+	h := sha256.Sum256(data)
+	if err := rsa.VerifyPKCS1v15(pk.PublicKey, crypto.SHA256, h[:], sig); err != nil {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+// coseInt converts decoded CBOR integer
+func coseInt(v interface{}) (int, bool) {
+	switch i := v.(type) {
+	case int64:
+		return int(i), true
+	case uint64:
+		return int(i), true
+	default:
+		return 0, false
+	}
+}
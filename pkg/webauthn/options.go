@@ -0,0 +1,82 @@
+package webauthn
+
+// Options passed to the navigator.credentials API
+//
+// Binary values are encoded as base64url strings and
+// must be decoded by the client
+
+type (
+	// CredentialCreation options for navigator.credentials.create()
+	CredentialCreation struct {
+		PublicKey PublicKeyCredentialCreationOptions `json:"publicKey"`
+	}
+
+	PublicKeyCredentialCreationOptions struct {
+		Challenge              URLEncodedBase64       `json:"challenge"`
+		RelyingParty           RelyingPartyEntity     `json:"rp"`
+		User                   UserEntity             `json:"user"`
+		Parameters             []CredentialParameter  `json:"pubKeyCredParams"`
+		Timeout                int                    `json:"timeout,omitempty"`
+		ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials,omitempty"`
+		AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+		Attestation            string                 `json:"attestation,omitempty"`
+	}
+
+	// CredentialAssertion options for navigator.credentials.get()
+	CredentialAssertion struct {
+		PublicKey PublicKeyCredentialRequestOptions `json:"publicKey"`
+	}
+
+	PublicKeyCredentialRequestOptions struct {
+		Challenge        URLEncodedBase64       `json:"challenge"`
+		Timeout          int                    `json:"timeout,omitempty"`
+		RelyingPartyID   string                 `json:"rpId"`
+		AllowCredentials []CredentialDescriptor `json:"allowCredentials,omitempty"`
+		UserVerification UserVerification       `json:"userVerification,omitempty"`
+	}
+
+	RelyingPartyEntity struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	UserEntity struct {
+		ID          URLEncodedBase64 `json:"id"`
+		Name        string           `json:"name"`
+		DisplayName string           `json:"displayName"`
+	}
+
+	CredentialParameter struct {
+		Type      string `json:"type"`
+		Algorithm int    `json:"alg"`
+	}
+
+	CredentialDescriptor struct {
+		Type string           `json:"type"`
+		ID   URLEncodedBase64 `json:"id"`
+	}
+
+	AuthenticatorSelection struct {
+		ResidentKey        string           `json:"residentKey,omitempty"`
+		RequireResidentKey bool             `json:"requireResidentKey"`
+		UserVerification   UserVerification `json:"userVerification,omitempty"`
+	}
+)
+
+const (
+	// ceremony timeout in milliseconds
+	defaultTimeout = 120000
+)
+
+// supportedParameters lists supported public key algorithms in order of preference
+func supportedParameters() []CredentialParameter {
+	return []CredentialParameter{
+		{Type: "public-key", Algorithm: algES256},
+		{Type: "public-key", Algorithm: algEdDSA},
+		{Type: "public-key", Algorithm: algRS256},
+	}
+}
+
+func credentialDescriptor(id []byte) CredentialDescriptor {
+	return CredentialDescriptor{Type: "public-key", ID: id}
+}
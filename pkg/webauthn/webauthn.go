@@ -0,0 +1,539 @@
+package webauthn
+
+// Minimal WebAuthn relying party
+//
+// Supports registration (attestation) and authentication (assertion) ceremonies
+// with ES256, EdDSA and RS256 public keys.
+//
+// Attestation statements are not verified; credentials are requested
+// with "none" attestation conveyance and authenticators are trusted
+// by the user that registers them.
+//
+// See https://www.w3.org/TR/webauthn-2/
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+type (
+	// RelyingParty the credentials are bound to
+	RelyingParty struct {
+		// ID is the effective domain of the relying party (hostname)
+		ID string
+
+		// Name is displayed to the user by the client
+		Name string
+
+		// Origin that the client data must be collected on
+		Origin string
+	}
+
+	// User that the credentials are registered for
+	User struct {
+		// ID is an opaque user handle (not displayed to the user)
+		ID          []byte
+		Name        string
+		DisplayName string
+	}
+
+	// Credential holds the registered public key credential
+	Credential struct {
+		ID        []byte `json:"-"`
+		PublicKey []byte `json:"publicKey"`
+		SignCount uint32 `json:"signCount"`
+		AAGUID    []byte `json:"aaguid,omitempty"`
+	}
+
+	// Session holds the ceremony state between generating the options
+	// and verifying the response of the client
+	Session struct {
+		Challenge          URLEncodedBase64 `json:"challenge"`
+		UserID             []byte           `json:"userID,omitempty"`
+		AllowedCredentials [][]byte         `json:"allowedCredentials,omitempty"`
+		UserVerification   UserVerification `json:"userVerification"`
+	}
+
+	// Assertion is the parsed response of the authentication ceremony
+	Assertion struct {
+		CredentialID   []byte
+		UserHandle     []byte
+		ClientDataJSON []byte
+		AuthData       []byte
+		Signature      []byte
+	}
+
+	// URLEncodedBase64 is encoded in JSON as unpadded base64url string
+	URLEncodedBase64 []byte
+
+	UserVerification string
+)
+
+const (
+	VerificationRequired    UserVerification = "required"
+	VerificationPreferred   UserVerification = "preferred"
+	VerificationDiscouraged UserVerification = "discouraged"
+
+	challengeLength = 32
+
+	ceremonyCreate = "webauthn.create"
+	ceremonyGet    = "webauthn.get"
+
+	flagUserPresent      byte = 0x01
+	flagUserVerified     byte = 0x04
+	flagAttestedCredData byte = 0x40
+	flagExtensionData    byte = 0x80
+
+	// rpIdHash (32) + flags (1) + signCount (4)
+	authDataMinLength = 37
+)
+
+// NewRelyingParty configures the relying party from the base URL
+// of the application
+//
+// Hostname of the URL is used as relying party ID
+func NewRelyingParty(name, baseURL string) (rp RelyingParty, err error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return rp, err
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return rp, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+
+	rp.ID = u.Hostname()
+	rp.Origin = u.Scheme + "://" + u.Host
+	rp.Name = name
+
+	if rp.Name == "" {
+		rp.Name = rp.ID
+	}
+
+	return rp, nil
+}
+
+// BeginRegistration generates options for navigator.credentials.create()
+//
+// Existing credentials are excluded so that the same authenticator
+// is not registered twice. When resident key is required,
+// credential is discoverable and can be used without the username.
+func (rp RelyingParty) BeginRegistration(u User, exclude [][]byte, residentKey bool) (*CredentialCreation, *Session, error) {
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		s = &Session{
+			Challenge:        challenge,
+			UserID:           u.ID,
+			UserVerification: VerificationPreferred,
+		}
+
+		cc = &CredentialCreation{PublicKey: PublicKeyCredentialCreationOptions{
+			Challenge: challenge,
+			RelyingParty: RelyingPartyEntity{
+				ID:   rp.ID,
+				Name: rp.Name,
+			},
+			User: UserEntity{
+				ID:          u.ID,
+				Name:        u.Name,
+				DisplayName: u.DisplayName,
+			},
+			Parameters:  supportedParameters(),
+			Timeout:     defaultTimeout,
+			Attestation: "none",
+			AuthenticatorSelection: AuthenticatorSelection{
+				RequireResidentKey: residentKey,
+				UserVerification:   VerificationPreferred,
+			},
+		}}
+	)
+
+	if residentKey {
+		// discoverable credentials are used instead of the password,
+		// user must be verified by the authenticator
+		s.UserVerification = VerificationRequired
+		cc.PublicKey.AuthenticatorSelection.ResidentKey = "required"
+		cc.PublicKey.AuthenticatorSelection.UserVerification = VerificationRequired
+	}
+
+	for _, id := range exclude {
+		cc.PublicKey.ExcludeCredentials = append(cc.PublicKey.ExcludeCredentials, credentialDescriptor(id))
+	}
+
+	return cc, s, nil
+}
+
+// FinishRegistration verifies the response of navigator.credentials.create()
+// and returns the registered credential
+func (rp RelyingParty) FinishRegistration(s *Session, response []byte) (*Credential, error) {
+	var (
+		aux struct {
+			RawID    URLEncodedBase64 `json:"rawId"`
+			Type     string           `json:"type"`
+			Response struct {
+				ClientDataJSON    URLEncodedBase64 `json:"clientDataJSON"`
+				AttestationObject URLEncodedBase64 `json:"attestationObject"`
+			} `json:"response"`
+		}
+
+		att struct {
+			Format   string          `cbor:"fmt"`
+			AttStmt  cbor.RawMessage `cbor:"attStmt"`
+			AuthData []byte          `cbor:"authData"`
+		}
+	)
+
+	if s == nil {
+		return nil, fmt.Errorf("missing session")
+	}
+
+	if err := json.Unmarshal(response, &aux); err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+
+	if aux.Type != "public-key" {
+		return nil, fmt.Errorf("unsupported credential type %q", aux.Type)
+	}
+
+	if err := rp.verifyClientData(aux.Response.ClientDataJSON, ceremonyCreate, s.Challenge); err != nil {
+		return nil, err
+	}
+
+	if err := cbor.Unmarshal(aux.Response.AttestationObject, &att); err != nil {
+		return nil, fmt.Errorf("invalid attestation object: %w", err)
+	}
+
+	ad, err := rp.parseAuthData(att.AuthData, s.UserVerification)
+	if err != nil {
+		return nil, err
+	}
+
+	if ad.flags&flagAttestedCredData == 0 {
+		return nil, fmt.Errorf("attested credential data missing")
+	}
+
+	if !bytes.Equal(ad.credentialID, aux.RawID) {
+		return nil, fmt.Errorf("credential ID mismatch")
+	}
+
+	if _, err = parsePublicKey(ad.publicKey); err != nil {
+		return nil, err
+	}
+
+	return &Credential{
+		ID:        ad.credentialID,
+		PublicKey: ad.publicKey,
+		SignCount: ad.signCount,
+		AAGUID:    ad.aaguid,
+	}, nil
+}
+
+// BeginLogin generates options for navigator.credentials.get()
+//
+// When no credentials are allowed, client can use any discoverable
+// credential for this relying party; user is then identified by the
+// user handle from the assertion.
+func (rp RelyingParty) BeginLogin(allowed [][]byte, uv UserVerification) (*CredentialAssertion, *Session, error) {
+	challenge, err := newChallenge()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if uv == "" {
+		uv = VerificationPreferred
+	}
+
+	var (
+		s = &Session{
+			Challenge:          challenge,
+			AllowedCredentials: allowed,
+			UserVerification:   uv,
+		}
+
+		ca = &CredentialAssertion{PublicKey: PublicKeyCredentialRequestOptions{
+			Challenge:        challenge,
+			RelyingPartyID:   rp.ID,
+			Timeout:          defaultTimeout,
+			UserVerification: uv,
+		}}
+	)
+
+	for _, id := range allowed {
+		ca.PublicKey.AllowCredentials = append(ca.PublicKey.AllowCredentials, credentialDescriptor(id))
+	}
+
+	return ca, s, nil
+}
+
+// ParseAssertion parses the response of navigator.credentials.get()
+//
+// Assertion must be verified with FinishLogin after the
+// credential is found by its ID
+func ParseAssertion(response []byte) (*Assertion, error) {
+	var (
+		aux struct {
+			RawID    URLEncodedBase64 `json:"rawId"`
+			Type     string           `json:"type"`
+			Response struct {
+				ClientDataJSON    URLEncodedBase64 `json:"clientDataJSON"`
+				AuthenticatorData URLEncodedBase64 `json:"authenticatorData"`
+				Signature         URLEncodedBase64 `json:"signature"`
+				UserHandle        URLEncodedBase64 `json:"userHandle"`
+			} `json:"response"`
+		}
+	)
+
+	if err := json.Unmarshal(response, &aux); err != nil {
+		return nil, fmt.Errorf("invalid assertion: %w", err)
+	}
+
+	if aux.Type != "public-key" {
+		return nil, fmt.Errorf("unsupported credential type %q", aux.Type)
+	}
+
+	if len(aux.RawID) == 0 {
+		return nil, fmt.Errorf("credential ID missing")
+	}
+
+	return &Assertion{
+		CredentialID:   aux.RawID,
+		UserHandle:     aux.Response.UserHandle,
+		ClientDataJSON: aux.Response.ClientDataJSON,
+		AuthData:       aux.Response.AuthenticatorData,
+		Signature:      aux.Response.Signature,
+	}, nil
+}
+
+// FinishLogin verifies the assertion with the stored credential
+//
+// It returns the new signature counter that should be stored
+// with the credential
+func (rp RelyingParty) FinishLogin(s *Session, a *Assertion, c *Credential) (uint32, error) {
+	if s == nil {
+		return 0, fmt.Errorf("missing session")
+	}
+
+	if a == nil || c == nil {
+		return 0, fmt.Errorf("missing credential")
+	}
+
+	if !bytes.Equal(a.CredentialID, c.ID) {
+		return 0, fmt.Errorf("credential ID mismatch")
+	}
+
+	if len(s.AllowedCredentials) > 0 && !containsID(s.AllowedCredentials, a.CredentialID) {
+		return 0, fmt.Errorf("credential not allowed")
+	}
+
+	if len(s.UserID) > 0 && len(a.UserHandle) > 0 && !bytes.Equal(s.UserID, a.UserHandle) {
+		return 0, fmt.Errorf("user handle mismatch")
+	}
+
+	if err := rp.verifyClientData(a.ClientDataJSON, ceremonyGet, s.Challenge); err != nil {
+		return 0, err
+	}
+
+	ad, err := rp.parseAuthData(a.AuthData, s.UserVerification)
+	if err != nil {
+		return 0, err
+	}
+
+	pk, err := parsePublicKey(c.PublicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		cdHash = sha256.Sum256(a.ClientDataJSON)
+		signed = append(append([]byte{}, a.AuthData...), cdHash[:]...)
+	)
+
+	if err = pk.verify(signed, a.Signature); err != nil {
+		return 0, err
+	}
+
+	// counters are not supported by all authenticators (always 0);
+	// a counter that does not increase indicates a cloned authenticator
+	if (ad.signCount != 0 || c.SignCount != 0) && ad.signCount <= c.SignCount {
+		return 0, fmt.Errorf("signature counter did not increase")
+	}
+
+	return ad.signCount, nil
+}
+
+type (
+	collectedClientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+
+	authData struct {
+		flags        byte
+		signCount    uint32
+		aaguid       []byte
+		credentialID []byte
+		publicKey    []byte
+	}
+)
+
+func (rp RelyingParty) verifyClientData(raw []byte, ceremony string, challenge []byte) error {
+	var cd collectedClientData
+
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return fmt.Errorf("invalid client data: %w", err)
+	}
+
+	if cd.Type != ceremony {
+		return fmt.Errorf("invalid ceremony type %q", cd.Type)
+	}
+
+	if c, err := decodeBase64(cd.Challenge); err != nil || subtle.ConstantTimeCompare(c, challenge) != 1 {
+		return fmt.Errorf("challenge mismatch")
+	}
+
+	if !strings.EqualFold(cd.Origin, rp.Origin) {
+		return fmt.Errorf("origin mismatch")
+	}
+
+	return nil
+}
+
+// parseAuthData parses and verifies the authenticator data
+//
+// See https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data
+func (rp RelyingParty) parseAuthData(raw []byte, uv UserVerification) (*authData, error) {
+	if len(raw) < authDataMinLength {
+		return nil, fmt.Errorf("authenticator data too short")
+	}
+
+	var (
+		rpIDHash = sha256.Sum256([]byte(rp.ID))
+		ad       = &authData{
+			flags:     raw[32],
+			signCount: binary.BigEndian.Uint32(raw[33:37]),
+		}
+	)
+
+	if !bytes.Equal(raw[:32], rpIDHash[:]) {
+		return nil, fmt.Errorf("relying party ID mismatch")
+	}
+
+	if ad.flags&flagUserPresent == 0 {
+		return nil, fmt.Errorf("user not present")
+	}
+
+	if uv == VerificationRequired && ad.flags&flagUserVerified == 0 {
+		return nil, fmt.Errorf("user not verified")
+	}
+
+	if ad.flags&flagAttestedCredData == 0 {
+		return ad, nil
+	}
+
+	// aaguid (16) + credentialIdLength (2)
+	rest := raw[authDataMinLength:]
+	if len(rest) < 18 {
+		return nil, fmt.Errorf("attested credential data too short")
+	}
+
+	ad.aaguid = rest[:16]
+	l := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+
+	if len(rest) < l {
+		return nil, fmt.Errorf("attested credential data too short")
+	}
+
+	ad.credentialID = rest[:l]
+	rest = rest[l:]
+
+	// credential public key is followed by the extensions (if any),
+	// length of the key is known only after it is decoded
+	var (
+		key cbor.RawMessage
+		dec = cbor.NewDecoder(bytes.NewReader(rest))
+	)
+
+	if err := dec.Decode(&key); err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+
+	ad.publicKey = rest[:dec.NumBytesRead()]
+
+	if ad.flags&flagExtensionData == 0 && dec.NumBytesRead() != len(rest) {
+		return nil, fmt.Errorf("unexpected trailing authenticator data")
+	}
+
+	return ad, nil
+}
+
+func newChallenge() ([]byte, error) {
+	c := make([]byte, challengeLength)
+	if _, err := rand.Read(c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func containsID(ids [][]byte, id []byte) bool {
+	for _, i := range ids {
+		if bytes.Equal(i, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EncodeID encodes credential ID or user handle as unpadded base64url string
+func EncodeID(id []byte) string {
+	return base64.RawURLEncoding.EncodeToString(id)
+}
+
+// DecodeID decodes credential ID or user handle from base64url string
+func DecodeID(id string) ([]byte, error) {
+	return decodeBase64(id)
+}
+
+// decodeBase64 accepts padded and unpadded base64url strings
+func decodeBase64(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+func (e URLEncodedBase64) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(EncodeID(e))
+}
+
+func (e *URLEncodedBase64) UnmarshalJSON(data []byte) (err error) {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var s string
+	if err = json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	*e, err = decodeBase64(s)
+	return err
+}
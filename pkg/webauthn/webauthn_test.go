@@ -0,0 +1,267 @@
+package webauthn
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// testAuthenticator emulates the authenticator and the client
+	testAuthenticator struct {
+		t         *testing.T
+		rp        RelyingParty
+		origin    string
+		key       *ecdsa.PrivateKey
+		id        []byte
+		signCount uint32
+	}
+)
+
+func newTestAuthenticator(t *testing.T, rp RelyingParty) *testAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return &testAuthenticator{
+		t:      t,
+		rp:     rp,
+		origin: rp.Origin,
+		key:    key,
+		id:     []byte("test-credential-id"),
+	}
+}
+
+func (a *testAuthenticator) authData(flags byte, attested bool) []byte {
+	var (
+		h   = sha256.Sum256([]byte(a.rp.ID))
+		out = append([]byte{}, h[:]...)
+		cnt = make([]byte, 4)
+	)
+
+	binary.BigEndian.PutUint32(cnt, a.signCount)
+	out = append(out, flags)
+	out = append(out, cnt...)
+
+	if attested {
+		pk, err := cbor.Marshal(map[int]interface{}{
+			coseKeyKty: ktyEC2,
+			coseKeyAlg: algES256,
+			coseKeyCrv: crvP256,
+			coseKeyX:   a.key.X.FillBytes(make([]byte, 32)),
+			coseKeyY:   a.key.Y.FillBytes(make([]byte, 32)),
+		})
+		require.NoError(a.t, err)
+
+		l := make([]byte, 2)
+		binary.BigEndian.PutUint16(l, uint16(len(a.id)))
+
+		out = append(out, make([]byte, 16)...)
+		out = append(out, l...)
+		out = append(out, a.id...)
+		out = append(out, pk...)
+	}
+
+	return out
+}
+
+func (a *testAuthenticator) clientData(ceremony string, challenge []byte) []byte {
+	cd, err := json.Marshal(collectedClientData{
+		Type:      ceremony,
+		Challenge: EncodeID(challenge),
+		Origin:    a.origin,
+	})
+	require.NoError(a.t, err)
+	return cd
+}
+
+func (a *testAuthenticator) create(cc *CredentialCreation) []byte {
+	att, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(flagUserPresent|flagUserVerified|flagAttestedCredData, true),
+	})
+	require.NoError(a.t, err)
+
+	rsp, err := json.Marshal(map[string]interface{}{
+		"id":    EncodeID(a.id),
+		"rawId": EncodeID(a.id),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    URLEncodedBase64(a.clientData(ceremonyCreate, cc.PublicKey.Challenge)),
+			"attestationObject": URLEncodedBase64(att),
+		},
+	})
+	require.NoError(a.t, err)
+	return rsp
+}
+
+func (a *testAuthenticator) get(ca *CredentialAssertion, userHandle []byte) []byte {
+	a.signCount++
+
+	var (
+		ad     = a.authData(flagUserPresent|flagUserVerified, false)
+		cd     = a.clientData(ceremonyGet, ca.PublicKey.Challenge)
+		cdHash = sha256.Sum256(cd)
+		h      = sha256.Sum256(append(append([]byte{}, ad...), cdHash[:]...))
+	)
+
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, h[:])
+	require.NoError(a.t, err)
+
+	rsp, err := json.Marshal(map[string]interface{}{
+		"id":    EncodeID(a.id),
+		"rawId": EncodeID(a.id),
+		"type":  "public-key",
+		"response": map[string]interface{}{
+			"clientDataJSON":    URLEncodedBase64(cd),
+			"authenticatorData": URLEncodedBase64(ad),
+			"signature":         URLEncodedBase64(sig),
+			"userHandle":        URLEncodedBase64(userHandle),
+		},
+	})
+	require.NoError(a.t, err)
+	return rsp
+}
+
+func TestNewRelyingParty(t *testing.T) {
+	req := require.New(t)
+
+	rp, err := NewRelyingParty("", "https://corteza.example.tld:8443/auth")
+	req.NoError(err)
+	req.Equal("corteza.example.tld", rp.ID)
+	req.Equal("corteza.example.tld", rp.Name)
+	req.Equal("https://corteza.example.tld:8443", rp.Origin)
+
+	_, err = NewRelyingParty("", "/auth")
+	req.Error(err)
+}
+
+func TestRelyingParty_ceremonies(t *testing.T) {
+	var (
+		req    = require.New(t)
+		rp     = RelyingParty{ID: "corteza.example.tld", Name: "Corteza", Origin: "https://corteza.example.tld"}
+		a      = newTestAuthenticator(t, rp)
+		userID = []byte("42")
+	)
+
+	cc, rs, err := rp.BeginRegistration(User{ID: userID, Name: "user"}, nil, true)
+	req.NoError(err)
+	req.True(cc.PublicKey.AuthenticatorSelection.RequireResidentKey)
+	req.Equal(VerificationRequired, rs.UserVerification)
+
+	c, err := rp.FinishRegistration(rs, a.create(cc))
+	req.NoError(err)
+	req.Equal(a.id, c.ID)
+
+	t.Run("login", func(t *testing.T) {
+		req := require.New(t)
+
+		ca, ls, err := rp.BeginLogin([][]byte{c.ID}, VerificationPreferred)
+		req.NoError(err)
+		req.Len(ca.PublicKey.AllowCredentials, 1)
+
+		as, err := ParseAssertion(a.get(ca, userID))
+		req.NoError(err)
+
+		cnt, err := rp.FinishLogin(ls, as, c)
+		req.NoError(err)
+		req.Equal(a.signCount, cnt)
+		c.SignCount = cnt
+	})
+
+	t.Run("discoverable login", func(t *testing.T) {
+		req := require.New(t)
+
+		ca, ls, err := rp.BeginLogin(nil, VerificationRequired)
+		req.NoError(err)
+		req.Empty(ca.PublicKey.AllowCredentials)
+
+		as, err := ParseAssertion(a.get(ca, userID))
+		req.NoError(err)
+		req.Equal(userID, as.UserHandle)
+
+		cnt, err := rp.FinishLogin(ls, as, c)
+		req.NoError(err)
+		c.SignCount = cnt
+	})
+
+	t.Run("replayed assertion", func(t *testing.T) {
+		req := require.New(t)
+
+		ca, ls, err := rp.BeginLogin(nil, VerificationPreferred)
+		req.NoError(err)
+
+		// signature counter that does not increase
+		a.signCount = c.SignCount - 1
+		as, err := ParseAssertion(a.get(ca, userID))
+		req.NoError(err)
+
+		_, err = rp.FinishLogin(ls, as, c)
+		req.EqualError(err, "signature counter did not increase")
+	})
+
+	t.Run("challenge mismatch", func(t *testing.T) {
+		req := require.New(t)
+
+		ca, _, err := rp.BeginLogin(nil, VerificationPreferred)
+		req.NoError(err)
+		_, ls, err := rp.BeginLogin(nil, VerificationPreferred)
+		req.NoError(err)
+
+		as, err := ParseAssertion(a.get(ca, userID))
+		req.NoError(err)
+
+		_, err = rp.FinishLogin(ls, as, c)
+		req.EqualError(err, "challenge mismatch")
+	})
+
+	t.Run("origin mismatch", func(t *testing.T) {
+		req := require.New(t)
+
+		ca, ls, err := rp.BeginLogin(nil, VerificationPreferred)
+		req.NoError(err)
+
+		a.origin = "https://evil.example.tld"
+		defer func() { a.origin = rp.Origin }()
+
+		as, err := ParseAssertion(a.get(ca, userID))
+		req.NoError(err)
+
+		_, err = rp.FinishLogin(ls, as, c)
+		req.EqualError(err, "origin mismatch")
+	})
+
+	t.Run("credential not allowed", func(t *testing.T) {
+		req := require.New(t)
+
+		ca, ls, err := rp.BeginLogin([][]byte{[]byte("other")}, VerificationPreferred)
+		req.NoError(err)
+
+		as, err := ParseAssertion(a.get(ca, userID))
+		req.NoError(err)
+
+		_, err = rp.FinishLogin(ls, as, c)
+		req.EqualError(err, "credential not allowed")
+	})
+}
+
+func TestRelyingParty_FinishRegistration_rpIDMismatch(t *testing.T) {
+	var (
+		req   = require.New(t)
+		rp    = RelyingParty{ID: "corteza.example.tld", Origin: "https://corteza.example.tld"}
+		other = RelyingParty{ID: "evil.example.tld", Origin: rp.Origin}
+	)
+
+	cc, rs, err := rp.BeginRegistration(User{ID: []byte("42")}, nil, false)
+	req.NoError(err)
+
+	_, err = rp.FinishRegistration(rs, newTestAuthenticator(t, other).create(cc))
+	req.EqualError(err, "relying party ID mismatch")
+}
@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+)
+
+type (
+	// Bus fans broadcast messages out to all nodes
+	//
+	// Every subscribed node receives the message, including
+	// the one that broadcast it.
+	Bus interface {
+		Broadcast(ctx context.Context, msg []byte) error
+		Subscribe(ctx context.Context, fn func(context.Context, []byte)) error
+	}
+
+	// BroadcastHandler handles broadcast message of one kind
+	//
+	// Handlers run on every node and usually publish
+	// the message to the subscribers connected to that node
+	BroadcastHandler func(ctx context.Context, payload []byte) error
+
+	// busMessage wraps payload of the broadcast message
+	busMessage struct {
+		Kind    string `json:"kind"`
+		Payload []byte `json:"payload"`
+	}
+
+	redisBus struct {
+		logger  *zap.Logger
+		channel string
+		redis   *redis.Client
+	}
+)
+
+// RedisBus uses redis pub/sub channel to fan out messages
+func RedisBus(ctx context.Context, logger *zap.Logger, url, channel string) (*redisBus, error) {
+	ro, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+
+	if channel == "" {
+		return nil, fmt.Errorf("redis channel not set")
+	}
+
+	b := &redisBus{
+		logger:  logger.With(zap.String("channel", channel)),
+		channel: channel,
+		redis:   redis.NewClient(ro),
+	}
+
+	if err = b.redis.Ping(ctx).Err(); err != nil {
+		_ = b.redis.Close()
+		return nil, fmt.Errorf("could not connect to redis: %w", err)
+	}
+
+	return b, nil
+}
+
+func (b *redisBus) Broadcast(ctx context.Context, msg []byte) error {
+	return b.redis.Publish(ctx, b.channel, msg).Err()
+}
+
+// Subscribe receives messages until context is canceled
+//
+// Function returns after subscription is confirmed so that
+// no message broadcast after that is missed
+func (b *redisBus) Subscribe(ctx context.Context, fn func(context.Context, []byte)) error {
+	ps := b.redis.Subscribe(ctx, b.channel)
+	if _, err := ps.Receive(ctx); err != nil {
+		_ = ps.Close()
+		return fmt.Errorf("could not subscribe to redis channel: %w", err)
+	}
+
+	go func() {
+		defer ps.Close()
+
+		ch := ps.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-ch:
+				if !ok {
+					return
+				}
+
+				fn(ctx, []byte(m.Payload))
+			}
+		}
+	}()
+
+	return nil
+}
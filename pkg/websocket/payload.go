@@ -11,6 +11,11 @@ type (
 		AccessToken string `json:"accessToken"`
 	}
 
+	// Subscription to (or unsubscription from) a channel
+	payloadSubscription struct {
+		Channel string `json:"channel"`
+	}
+
 	payloadWrap struct {
 		Type  string          `json:"@type"`
 		Value json.RawMessage `json:"@value"`
@@ -19,6 +24,8 @@ type (
 
 const (
 	payloadTypeCredentials = "credentials"
+	payloadTypeSubscribe   = "subscribe"
+	payloadTypeUnsubscribe = "unsubscribe"
 )
 
 var (
@@ -1,14 +1,22 @@
 package websocket
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
 	"github.com/cortezaproject/corteza-server/pkg/slice"
+	st "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/websocket"
 	"go.uber.org/zap"
@@ -33,13 +41,77 @@ type (
 		// user id => session id => session
 		sessions map[uint64]map[uint64]io.Writer
 
+		// channel => session id => subscriber
+		subscriptions map[string]map[uint64]*subscriber
+
+		// session id => channels
+		channels map[uint64]map[string]bool
+
 		accessToken interface {
 			Authenticate(string) (jwt.MapClaims, error)
 		}
 
+		// loads current roles of the subscribers
+		store identityStorer
+
+		// fans broadcast messages out to all nodes;
+		// without it, broadcast messages are handled only on this node
+		bus Bus
+
+		// kind => broadcast message handlers
+		handlers map[string][]BroadcastHandler
+
+		// broadcast messages handled on this node
+		// when there is no bus
+		queue chan []byte
+
+		// user id => identity with current roles
+		identities map[uint64]*cachedIdentity
+		il         sync.Mutex
+
+		// bumped on each reset so that identities loaded
+		// before the reset are not cached
+		identitiesGen uint
+
 		// keep lock on session map changes
 		l sync.RWMutex
 	}
+
+	// cachedIdentity is identity of the subscriber with current roles;
+	// nil when user is suspended or deleted
+	cachedIdentity struct {
+		identity auth.Identifiable
+		loadedAt time.Time
+	}
+
+	identityStorer interface {
+		LookupUserByID(ctx context.Context, id uint64) (*st.User, error)
+		SearchRoleMembers(ctx context.Context, f st.RoleMemberFilter) (st.RoleMemberSet, st.RoleMemberFilter, error)
+	}
+
+	subscriber struct {
+		w        io.Writer
+		identity auth.Identifiable
+	}
+)
+
+const (
+	// max number of channels one session can subscribe to
+	maxSubscriptions = 256
+
+	// max length of the channel name
+	maxChannelLength = 256
+
+	// max number of broadcast messages waiting to be handled on this node
+	queueSize = 1000
+
+	// cached identities are reloaded after that even when
+	// no change of users or role members is noticed
+	identityTTL = time.Minute * 5
+
+	// IdentityInvalidationKind is used to notify other nodes
+	// about changed users and role members
+	IdentityInvalidationKind = "websocket:identity"
 )
 
 func Server(logger *zap.Logger, config options.WebsocketOpt) *server {
@@ -52,7 +124,66 @@ func Server(logger *zap.Logger, config options.WebsocketOpt) *server {
 		logger:      logger.Named("websocket"),
 		accessToken: auth.DefaultJwtHandler,
 		sessions:    make(map[uint64]map[uint64]io.Writer),
+
+		subscriptions: make(map[string]map[uint64]*subscriber),
+		channels:      make(map[uint64]map[string]bool),
+		handlers:      make(map[string][]BroadcastHandler),
+		queue:         make(chan []byte, queueSize),
+		identities:    make(map[uint64]*cachedIdentity),
+	}
+}
+
+// Init sets the store used for loading current roles of the subscribers
+// and connects to the bus when configured
+func (ws *server) Init(ctx context.Context, s identityStorer) (err error) {
+	ws.store = s
+
+	if ws.config.BusRedisURL != "" {
+		ws.bus, err = RedisBus(ctx, ws.logger, ws.config.BusRedisURL, ws.config.BusChannel)
 	}
+
+	return
+}
+
+// Watch starts handling broadcast messages and resets cached
+// identities when users or role members change
+//
+// Without the bus, messages broadcast on this node are handled
+// in the background; otherwise they are received from the bus.
+func (ws *server) Watch(ctx context.Context) error {
+	eventbus.Service().Register(
+		func(ctx context.Context, ev eventbus.Event) error {
+			ws.ResetIdentities()
+			invalidation.Notify(ctx, IdentityInvalidationKind)
+			return nil
+		},
+		eventbus.For("system:user", "system:role", "system:role:member"),
+		eventbus.On("afterUpdate", "afterDelete", "afterSuspend", "afterAdd", "afterRemove"),
+	)
+
+	invalidation.Register(IdentityInvalidationKind, func(ctx context.Context) error {
+		ws.ResetIdentities()
+		return nil
+	})
+
+	if ws.bus != nil {
+		return ws.bus.Subscribe(ctx, ws.handleBroadcast)
+	}
+
+	go func() {
+		defer sentry.Recover()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg := <-ws.queue:
+				ws.handleBroadcast(ctx, msg)
+			}
+		}
+	}()
+
+	return nil
 }
 
 func (ws *server) Open(w http.ResponseWriter, r *http.Request) {
@@ -106,11 +237,243 @@ func (ws *server) Send(t string, payload interface{}, userIDs ...uint64) error {
 	return nil
 }
 
+// HandleBroadcast registers handler for broadcast messages of the given kind
+func (ws *server) HandleBroadcast(kind string, fn func(ctx context.Context, payload []byte) error) {
+	ws.l.Lock()
+	defer ws.l.Unlock()
+	ws.handlers[kind] = append(ws.handlers[kind], fn)
+}
+
+// Broadcast sends message to all nodes where it is passed to the handlers
+// registered for the kind
+//
+// Without the bus, message is queued and handled only on this node.
+func (ws *server) Broadcast(ctx context.Context, kind string, payload interface{}) error {
+	pb, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	msg, err := json.Marshal(busMessage{Kind: kind, Payload: pb})
+	if err != nil {
+		return err
+	}
+
+	if ws.bus == nil {
+		select {
+		case ws.queue <- msg:
+		default:
+			ws.logger.Warn("broadcast queue is full, message dropped", zap.String("kind", kind))
+		}
+
+		return nil
+	}
+
+	return ws.bus.Broadcast(ctx, msg)
+}
+
+func (ws *server) handleBroadcast(ctx context.Context, raw []byte) {
+	var msg busMessage
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		ws.logger.Warn("could not decode broadcast message", zap.Error(err))
+		return
+	}
+
+	ws.l.RLock()
+	hh := ws.handlers[msg.Kind]
+	ws.l.RUnlock()
+
+	for _, h := range hh {
+		if err := h(ctx, msg.Payload); err != nil {
+			ws.logger.Warn("could not handle broadcast message", zap.String("kind", msg.Kind), zap.Error(err))
+		}
+	}
+}
+
+// Publish delivers payload to all sessions subscribed to any of the channels
+//
+// Payload is prepared for each subscriber's identity, subscriber is skipped
+// when prepare fn returns nil (e.g. when identity is not allowed to see the payload).
+//
+// Current roles of the subscribers are used so that membership changes made
+// after the session was authenticated are respected.
+//
+// Each subscriber receives the payload at most once.
+func (ws *server) Publish(t string, prepare func(auth.Identifiable) interface{}, channels ...string) error {
+	var (
+		ctx  = context.Background()
+		subs = make(map[uint64]*subscriber)
+
+		// user id => current identity
+		identities = make(map[uint64]auth.Identifiable)
+	)
+
+	ws.l.RLock()
+	for _, ch := range channels {
+		for sid, sub := range ws.subscriptions[ch] {
+			subs[sid] = sub
+		}
+	}
+	ws.l.RUnlock()
+
+	for _, sub := range subs {
+		identity, ok := identities[sub.identity.Identity()]
+		if !ok {
+			identity = ws.currentIdentity(ctx, sub.identity)
+			identities[sub.identity.Identity()] = identity
+		}
+
+		if identity == nil {
+			continue
+		}
+
+		payload := prepare(identity)
+		if payload == nil {
+			continue
+		}
+
+		pb, err := MarshalPayload(t, payload)
+		if err != nil {
+			return err
+		}
+
+		_, _ = sub.w.Write(pb)
+	}
+
+	return nil
+}
+
+// currentIdentity returns identity of the subscriber with current roles
+//
+// Identities are cached until users or role members change.
+// Nil is returned when user is suspended or deleted or when roles can not be loaded.
+func (ws *server) currentIdentity(ctx context.Context, i auth.Identifiable) auth.Identifiable {
+	if ws.store == nil {
+		return i
+	}
+
+	ws.il.Lock()
+	c, gen := ws.identities[i.Identity()], ws.identitiesGen
+	ws.il.Unlock()
+
+	if c != nil && time.Since(c.loadedAt) < identityTTL {
+		return c.identity
+	}
+
+	identity, err := ws.loadIdentity(ctx, i.Identity())
+	if err != nil {
+		// not cached, load is retried on the next publish
+		ws.logger.Warn("could not load subscriber", zap.Uint64("userID", i.Identity()), zap.Error(err))
+		return nil
+	}
+
+	ws.il.Lock()
+	if gen == ws.identitiesGen {
+		ws.identities[i.Identity()] = &cachedIdentity{identity: identity, loadedAt: time.Now()}
+	}
+	ws.il.Unlock()
+
+	return identity
+}
+
+// ResetIdentities drops cached identities of the subscribers
+func (ws *server) ResetIdentities() {
+	ws.il.Lock()
+	defer ws.il.Unlock()
+
+	ws.identities = make(map[uint64]*cachedIdentity)
+	ws.identitiesGen++
+}
+
+// loadIdentity loads user and their current roles
+//
+// Nil is returned when user is suspended or deleted
+func (ws *server) loadIdentity(ctx context.Context, userID uint64) (auth.Identifiable, error) {
+	u, err := ws.store.LookupUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.Valid() {
+		return nil, nil
+	}
+
+	mm, _, err := ws.store.SearchRoleMembers(ctx, st.RoleMemberFilter{UserID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	rr := make([]uint64, 0, len(mm))
+	for _, m := range mm {
+		rr = append(rr, m.RoleID)
+	}
+
+	return auth.Authenticated(u.ID, rr...), nil
+}
+
 func (ws *server) StoreSession(s *session) {
 	ws.l.Lock()
 	defer ws.l.Unlock()
 	if s.identity != nil {
 		ws.storeSession(s, s.identity.Identity(), s.id)
+
+		// identity (roles) can change when session is re-authenticated
+		for ch := range ws.channels[s.id] {
+			ws.subscriptions[ch][s.id] = &subscriber{w: s, identity: s.identity}
+		}
+	}
+}
+
+// Subscribe adds session to the channel subscribers
+func (ws *server) Subscribe(s *session, channel string) error {
+	ws.l.Lock()
+	defer ws.l.Unlock()
+	return ws.subscribe(s, s.identity, s.id, channel)
+}
+
+func (ws *server) subscribe(w io.Writer, identity auth.Identifiable, sid uint64, channel string) error {
+	if channel == "" || len(channel) > maxChannelLength {
+		return fmt.Errorf("invalid channel")
+	}
+
+	if ws.channels[sid] == nil {
+		ws.channels[sid] = make(map[string]bool)
+	}
+
+	if ws.channels[sid][channel] {
+		// already subscribed
+		return nil
+	}
+
+	if len(ws.channels[sid]) >= maxSubscriptions {
+		return fmt.Errorf("too many subscriptions")
+	}
+
+	if ws.subscriptions[channel] == nil {
+		ws.subscriptions[channel] = make(map[uint64]*subscriber)
+	}
+
+	ws.channels[sid][channel] = true
+	ws.subscriptions[channel][sid] = &subscriber{w: w, identity: identity}
+	return nil
+}
+
+// Unsubscribe removes session from the channel subscribers
+func (ws *server) Unsubscribe(s *session, channel string) {
+	ws.l.Lock()
+	defer ws.l.Unlock()
+	ws.unsubscribe(s.id, channel)
+}
+
+func (ws *server) unsubscribe(sid uint64, channel string) {
+	delete(ws.subscriptions[channel], sid)
+	if len(ws.subscriptions[channel]) == 0 {
+		delete(ws.subscriptions, channel)
+	}
+
+	delete(ws.channels[sid], channel)
+	if len(ws.channels[sid]) == 0 {
+		delete(ws.channels, sid)
 	}
 }
 
@@ -134,4 +497,8 @@ func (ws *server) RemoveSession(s *session) {
 			delete(ws.sessions, uid)
 		}
 	}
+
+	for ch := range ws.channels[s.id] {
+		ws.unsubscribe(s.id, ch)
+	}
 }
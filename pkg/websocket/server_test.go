@@ -2,10 +2,17 @@ package websocket
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
 	"github.com/cortezaproject/corteza-server/pkg/options"
+	st "github.com/cortezaproject/corteza-server/system/types"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
-	"testing"
 )
 
 func TestWebsocketSend_NoSessions(t *testing.T) {
@@ -61,3 +68,198 @@ func TestWebsocketSend_ExistingSessions(t *testing.T) {
 	req.Equal(s1.String(), `{"@type":"msg","@value":"msg1"}{"@type":"both","@value":"msg3"}{"@type":"all","@value":"msg4"}`)
 	req.Equal(s2.String(), `{"@type":"msg","@value":"msg2"}{"@type":"both","@value":"msg3"}{"@type":"all","@value":"msg4"}`)
 }
+
+func TestWebsocketPublish(t *testing.T) {
+	var (
+		req = require.New(t)
+		ws  = Server(zap.NewNop(), options.WebsocketOpt{})
+
+		s1 = &bytes.Buffer{}
+		s2 = &bytes.Buffer{}
+		s3 = &bytes.Buffer{}
+
+		allowAll = func(i auth.Identifiable) interface{} { return i.Identity() }
+	)
+
+	req.NoError(ws.subscribe(s1, auth.Authenticated(100), 101, "a"))
+	req.NoError(ws.subscribe(s1, auth.Authenticated(100), 101, "b"))
+	req.NoError(ws.subscribe(s2, auth.Authenticated(200), 201, "b"))
+	req.NoError(ws.subscribe(s3, auth.Authenticated(300), 301, "c"))
+	req.Error(ws.subscribe(s3, auth.Authenticated(300), 301, ""))
+
+	// subscribed to both channels but delivered only once
+	req.NoError(ws.Publish("msg", allowAll, "a", "b"))
+	req.Equal(`{"@type":"msg","@value":100}`, s1.String())
+	req.Equal(`{"@type":"msg","@value":200}`, s2.String())
+	req.Empty(s3.String())
+
+	// payload is not delivered to subscribers that are not allowed to see it
+	s1.Reset()
+	s2.Reset()
+	req.NoError(ws.Publish("msg", func(i auth.Identifiable) interface{} {
+		if i.Identity() == 100 {
+			return nil
+		}
+
+		return "ok"
+	}, "b"))
+	req.Empty(s1.String())
+	req.Equal(`{"@type":"msg","@value":"ok"}`, s2.String())
+
+	s2.Reset()
+	ws.unsubscribe(201, "b")
+	req.NoError(ws.Publish("msg", allowAll, "b"))
+	req.Empty(s2.String())
+	req.NotContains(ws.channels, uint64(201))
+	req.NotContains(ws.subscriptions["b"], uint64(201))
+}
+
+type testIdentityStorer struct {
+	users   map[uint64]*st.User
+	members st.RoleMemberSet
+}
+
+func (s testIdentityStorer) LookupUserByID(_ context.Context, id uint64) (*st.User, error) {
+	if u, ok := s.users[id]; ok {
+		return u, nil
+	}
+
+	return nil, fmt.Errorf("not found")
+}
+
+func (s testIdentityStorer) SearchRoleMembers(_ context.Context, f st.RoleMemberFilter) (st.RoleMemberSet, st.RoleMemberFilter, error) {
+	out, err := s.members.Filter(func(m *st.RoleMember) (bool, error) { return m.UserID == f.UserID, nil })
+	return out, f, err
+}
+
+func TestWebsocketPublish_CurrentRoles(t *testing.T) {
+	var (
+		req = require.New(t)
+		ws  = Server(zap.NewNop(), options.WebsocketOpt{})
+
+		now = time.Now()
+
+		s1 = &bytes.Buffer{}
+		s2 = &bytes.Buffer{}
+		s3 = &bytes.Buffer{}
+
+		roles = func(i auth.Identifiable) interface{} { return i.Roles() }
+	)
+
+	store := &testIdentityStorer{
+		users: map[uint64]*st.User{
+			100: {ID: 100},
+			200: {ID: 200},
+			300: {ID: 300, SuspendedAt: &now},
+		},
+		members: st.RoleMemberSet{
+			{UserID: 100, RoleID: 2},
+		},
+	}
+
+	req.NoError(ws.Init(context.Background(), store))
+
+	// identities with roles from the access token
+	req.NoError(ws.subscribe(s1, auth.Authenticated(100, 1), 101, "a"))
+	req.NoError(ws.subscribe(s2, auth.Authenticated(200, 1), 201, "a"))
+	req.NoError(ws.subscribe(s3, auth.Authenticated(300, 1), 301, "a"))
+
+	req.NoError(ws.Publish("msg", roles, "a"))
+	req.Equal(`{"@type":"msg","@value":[2]}`, s1.String())
+	req.Equal(`{"@type":"msg","@value":[]}`, s2.String())
+
+	// suspended user does not receive anything
+	req.Empty(s3.String())
+
+	// identities are cached until reset
+	store.members = append(store.members, &st.RoleMember{UserID: 200, RoleID: 3})
+	s2.Reset()
+	req.NoError(ws.Publish("msg", roles, "a"))
+	req.Equal(`{"@type":"msg","@value":[]}`, s2.String())
+
+	ws.ResetIdentities()
+	s2.Reset()
+	req.NoError(ws.Publish("msg", roles, "a"))
+	req.Equal(`{"@type":"msg","@value":[3]}`, s2.String())
+}
+
+func TestWebsocketBroadcast(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+		srv = miniredis.RunT(t)
+
+		opt = options.WebsocketOpt{BusRedisURL: "redis://" + srv.Addr(), BusChannel: "test"}
+
+		node1 = Server(zap.NewNop(), opt)
+		node2 = Server(zap.NewNop(), opt)
+
+		received = make(chan string, 2)
+		handler  = func(node string) func(context.Context, []byte) error {
+			return func(_ context.Context, p []byte) error {
+				received <- node + ":" + string(p)
+				return nil
+			}
+		}
+	)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for _, ws := range []*server{node1, node2} {
+		req.NoError(ws.Init(ctx, nil))
+		req.NoError(ws.Watch(ctx))
+	}
+
+	node1.HandleBroadcast("msg", handler("node1"))
+	node2.HandleBroadcast("msg", handler("node2"))
+	node2.HandleBroadcast("other", handler("other"))
+
+	req.NoError(node1.Broadcast(ctx, "msg", "hello"))
+
+	var rr []string
+	for len(rr) < 2 {
+		select {
+		case r := <-received:
+			rr = append(rr, r)
+		case <-time.After(time.Second):
+			req.FailNow("broadcast message not received")
+		}
+	}
+
+	req.ElementsMatch([]string{`node1:"hello"`, `node2:"hello"`}, rr)
+}
+
+func TestWebsocketBroadcast_WithoutBus(t *testing.T) {
+	var (
+		req = require.New(t)
+		ws  = Server(zap.NewNop(), options.WebsocketOpt{})
+
+		ctx, cancel = context.WithCancel(context.Background())
+
+		received = make(chan string, 1)
+	)
+
+	defer cancel()
+
+	ws.HandleBroadcast("msg", func(_ context.Context, p []byte) error {
+		received <- string(p)
+		return nil
+	})
+
+	req.NoError(ws.Init(ctx, nil))
+
+	// queued until the server starts watching
+	req.NoError(ws.Broadcast(ctx, "msg", "hello"))
+	req.Empty(received)
+
+	// and handled in the background on this node
+	req.NoError(ws.Watch(ctx))
+
+	select {
+	case r := <-received:
+		req.Equal(`"hello"`, r)
+	case <-time.After(time.Second):
+		req.FailNow("broadcast message not handled")
+	}
+}
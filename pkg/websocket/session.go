@@ -209,7 +209,26 @@ func (s *session) procRawMessage(raw []byte) (err error) {
 		return fmt.Errorf("unauthenticated session")
 	}
 
-	// at the moment we do not support any other kinds of message types
+	switch pw.Type {
+	case payloadTypeSubscribe, payloadTypeUnsubscribe:
+		sub := &payloadSubscription{}
+		if err = pw.UnmarshalValue(sub); err != nil {
+			return fmt.Errorf("could not unmarshal session payload: %w", err)
+		}
+
+		if pw.Type == payloadTypeUnsubscribe {
+			s.server.Unsubscribe(s, sub.Channel)
+			return
+		}
+
+		if err = s.server.Subscribe(s, sub.Channel); err != nil {
+			return fmt.Errorf("could not subscribe: %w", err)
+		}
+
+		s.logger.Debug("subscribed", zap.String("channel", sub.Channel))
+		return
+	}
+
 	return fmt.Errorf("unknown message type '%s'", pw.Type)
 }
 
@@ -44,6 +44,14 @@ func TestSession_procRawMessage(t *testing.T) {
 	req.EqualError(s.procRawMessage([]byte("{}")), "unknown message type ''")
 	req.Equal(userID, s.identity.Identity())
 
+	req.NoError(s.procRawMessage([]byte(`{"@type": "subscribe", "@value": {"channel": "foo"}}`)))
+	req.True(s.server.channels[s.id]["foo"])
+
+	req.EqualError(s.procRawMessage([]byte(`{"@type": "subscribe", "@value": {"channel": ""}}`)), "could not subscribe: invalid channel")
+
+	req.NoError(s.procRawMessage([]byte(`{"@type": "unsubscribe", "@value": {"channel": "foo"}}`)))
+	req.Empty(s.server.channels[s.id])
+
 	// Repeat with the same user
 	jwt = jwtHandler.Encode(auth.Authenticated(userID, 456, 789))
 
@@ -156,6 +156,19 @@ func (s SessionStatus) String() string {
 }
 
 func NewSession(ctx context.Context, g *Graph, oo ...SessionOpt) *Session {
+	s := newSession(g, oo...)
+
+	s.log = s.log.
+		With(zap.Uint64("sessionID", s.id))
+
+	s.callStack = append(s.callStack, s.id)
+
+	go s.worker(ctx)
+
+	return s
+}
+
+func newSession(g *Graph, oo ...SessionOpt) *Session {
 	s := &Session{
 		g:        g,
 		id:       nextID(),
@@ -181,13 +194,6 @@ func NewSession(ctx context.Context, g *Graph, oo ...SessionOpt) *Session {
 		o(s)
 	}
 
-	s.log = s.log.
-		With(zap.Uint64("sessionID", s.id))
-
-	s.callStack = append(s.callStack, s.id)
-
-	go s.worker(ctx)
-
 	return s
 }
 
@@ -571,9 +577,15 @@ func (s *Session) Cancel() {
 	s.qErr <- fmt.Errorf("canceled")
 }
 
+// Stop stops the worker without changing the status of the session
+//
+// Does not block when worker is already stopped
 func (s *Session) Stop() {
 	s.log.Debug("stopping worker")
-	s.qErr <- nil
+	select {
+	case s.qErr <- nil:
+	default:
+	}
 }
 
 func (s *Session) Suspended() bool {
@@ -0,0 +1,272 @@
+package wfexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"go.uber.org/zap"
+)
+
+type (
+	// SessionSnapshot holds serializable state of a suspended session
+	//
+	// Only delayed and prompted states are captured; these are the only
+	// states that can wait for a longer period of time (and across restarts).
+	// States that are currently executing or queued are not part of the snapshot
+	SessionSnapshot struct {
+		SessionID  uint64    `json:"sessionID,string"`
+		WorkflowID uint64    `json:"workflowID,string"`
+		Started    time.Time `json:"started"`
+		CallStack  []uint64  `json:"callStack"`
+
+		Delayed  []*DelayedSnapshot  `json:"delayed,omitempty"`
+		Prompted []*PromptedSnapshot `json:"prompted,omitempty"`
+	}
+
+	// StateSnapshot holds serializable state
+	//
+	// Steps are referenced by their IDs and resolved from the graph when
+	// session is restored
+	StateSnapshot struct {
+		StateID    uint64    `json:"stateID,string"`
+		CreatedAt  time.Time `json:"createdAt"`
+		OwnerID    uint64    `json:"ownerID,string,omitempty"`
+		OwnerRoles []uint64  `json:"ownerRoles,omitempty"`
+
+		StepID       uint64 `json:"stepID,string"`
+		ParentID     uint64 `json:"parentID,string,omitempty"`
+		ErrHandlerID uint64 `json:"errHandlerID,string,omitempty"`
+
+		Scope *expr.Vars `json:"scope"`
+	}
+
+	DelayedSnapshot struct {
+		State    *StateSnapshot `json:"state"`
+		ResumeAt time.Time      `json:"resumeAt"`
+	}
+
+	PromptedSnapshot struct {
+		State   *StateSnapshot `json:"state"`
+		OwnerID uint64         `json:"ownerID,string"`
+		Ref     string         `json:"ref"`
+		Payload *expr.Vars     `json:"payload"`
+		Sent    bool           `json:"sent"`
+	}
+)
+
+var (
+	// ErrSuspendedInLoop is returned when snapshot is made of a session
+	// with a state that is suspended inside a loop
+	ErrSuspendedInLoop = errors.New("state suspended inside a loop can not be persisted")
+)
+
+// Snapshot captures state of all suspended (delayed & prompted) states
+//
+// Iterators do not keep their state in a serializable form, so
+// sessions suspended inside a loop can not be captured (ErrSuspendedInLoop)
+func (s *Session) Snapshot() (*SessionSnapshot, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	var (
+		err  error
+		snap = &SessionSnapshot{
+			SessionID:  s.id,
+			WorkflowID: s.workflowID,
+			Started:    s.started,
+			CallStack:  append([]uint64{}, s.callStack...),
+			Delayed:    make([]*DelayedSnapshot, 0, len(s.delayed)),
+			Prompted:   make([]*PromptedSnapshot, 0, len(s.prompted)),
+		}
+	)
+
+	for _, d := range s.delayed {
+		ds := &DelayedSnapshot{ResumeAt: d.resumeAt}
+		if ds.State, err = d.state.snapshot(); err != nil {
+			return nil, err
+		}
+
+		snap.Delayed = append(snap.Delayed, ds)
+	}
+
+	for _, p := range s.prompted {
+		ps := &PromptedSnapshot{OwnerID: p.ownerId, Ref: p.ref, Payload: p.payload, Sent: p.sent}
+		if ps.State, err = p.state.snapshot(); err != nil {
+			return nil, err
+		}
+
+		snap.Prompted = append(snap.Prompted, ps)
+	}
+
+	return snap, nil
+}
+
+// ResolveTypes resolves types of all variables in the snapshot
+//
+// Variables lose type information when snapshot is encoded
+// and need to be resolved before session is restored
+func (snap *SessionSnapshot) ResolveTypes(res func(typ string) expr.Type) (err error) {
+	for _, d := range snap.Delayed {
+		if d.State != nil {
+			if err = d.State.Scope.ResolveTypes(res); err != nil {
+				return
+			}
+		}
+	}
+
+	for _, p := range snap.Prompted {
+		if p.State != nil {
+			if err = p.State.Scope.ResolveTypes(res); err != nil {
+				return
+			}
+		}
+
+		if err = p.Payload.ResolveTypes(res); err != nil {
+			return
+		}
+	}
+
+	return nil
+}
+
+// RestoreSession creates new session from the snapshot
+//
+// All steps referenced in the snapshot must exist in the graph.
+// Restored session keeps the original ID and continues with the
+// execution when delayed states are due or when prompts are resumed.
+func RestoreSession(ctx context.Context, g *Graph, snap *SessionSnapshot, oo ...SessionOpt) (*Session, error) {
+	if snap == nil {
+		return nil, fmt.Errorf("can not restore session without snapshot")
+	}
+
+	s := newSession(g, oo...)
+	s.id = snap.SessionID
+	s.workflowID = snap.WorkflowID
+	s.started = snap.Started
+	s.callStack = snap.CallStack
+
+	for _, ds := range snap.Delayed {
+		st, err := restoreState(g, s, ds.State)
+		if err != nil {
+			return nil, err
+		}
+
+		s.delayed[st.stateId] = &delayed{resumeAt: ds.ResumeAt, state: st}
+	}
+
+	for _, ps := range snap.Prompted {
+		st, err := restoreState(g, s, ps.State)
+		if err != nil {
+			return nil, err
+		}
+
+		s.prompted[st.stateId] = &prompted{
+			payload: ps.Payload,
+			ownerId: ps.OwnerID,
+			state:   st,
+			sent:    ps.Sent,
+			ref:     ps.Ref,
+		}
+	}
+
+	s.log = s.log.
+		With(zap.Uint64("sessionID", s.id))
+
+	go s.worker(ctx)
+
+	return s, nil
+}
+
+func (s *State) snapshot() (*StateSnapshot, error) {
+	if len(s.loops) > 0 {
+		var stepID uint64
+		if s.step != nil {
+			stepID = s.step.ID()
+		}
+
+		return nil, fmt.Errorf("can not make snapshot of state %d on step %d: %w", s.stateId, stepID, ErrSuspendedInLoop)
+	}
+
+	ss := &StateSnapshot{
+		StateID:   s.stateId,
+		CreatedAt: s.created,
+		Scope:     s.scope,
+	}
+
+	if s.owner != nil {
+		ss.OwnerID = s.owner.Identity()
+		ss.OwnerRoles = s.owner.Roles()
+	}
+
+	if s.step != nil {
+		ss.StepID = s.step.ID()
+	}
+
+	if s.parent != nil {
+		ss.ParentID = s.parent.ID()
+	}
+
+	if s.errHandler != nil {
+		ss.ErrHandlerID = s.errHandler.ID()
+	}
+
+	return ss, nil
+}
+
+func restoreState(g *Graph, ses *Session, ss *StateSnapshot) (st *State, err error) {
+	if ss == nil {
+		return nil, fmt.Errorf("state snapshot is nil")
+	}
+
+	var (
+		stepByID = func(ID uint64) (Step, error) {
+			if ID == 0 {
+				return nil, nil
+			}
+
+			if s := g.StepByID(ID); s != nil {
+				return s, nil
+			}
+
+			return nil, fmt.Errorf("can not restore state %d, step %d not found", ss.StateID, ID)
+		}
+	)
+
+	st = &State{
+		stateId:   ss.StateID,
+		sessionId: ses.id,
+		created:   ss.CreatedAt,
+		scope:     ss.Scope,
+		loops:     make([]Iterator, 0, 4),
+	}
+
+	if ss.OwnerID > 0 {
+		st.owner = auth.Authenticated(ss.OwnerID, ss.OwnerRoles...)
+	}
+
+	if st.step, err = stepByID(ss.StepID); err != nil {
+		return
+	}
+
+	if st.step == nil {
+		return nil, fmt.Errorf("can not restore state %d without step", ss.StateID)
+	}
+
+	if st.parent, err = stepByID(ss.ParentID); err != nil {
+		return
+	}
+
+	if st.errHandler, err = stepByID(ss.ErrHandlerID); err != nil {
+		return
+	}
+
+	if st.scope == nil {
+		st.scope = &expr.Vars{}
+	}
+
+	return
+}
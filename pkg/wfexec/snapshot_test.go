@@ -0,0 +1,217 @@
+package wfexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/stretchr/testify/require"
+)
+
+// encodes and decodes snapshot to simulate storing and loading it
+func snapshotRoundTrip(req *require.Assertions, snap *SessionSnapshot) *SessionSnapshot {
+	enc, err := json.Marshal(snap)
+	req.NoError(err)
+
+	out := &SessionSnapshot{}
+	req.NoError(json.Unmarshal(enc, out))
+	req.NoError(out.ResolveTypes(func(typ string) expr.Type {
+		switch typ {
+		case "Integer":
+			return &expr.Integer{}
+		case "String":
+			return &expr.String{}
+		}
+
+		return &expr.Any{}
+	}))
+
+	return out
+}
+
+func TestSession_SnapshotDelayed(t *testing.T) {
+	var (
+		ctx = context.Background()
+		req = require.New(t)
+		wf  = NewGraph()
+		ses = NewSession(ctx, wf, SetWorkerInterval(time.Millisecond), SetWorkflowID(42))
+
+		s1  = &sesTestStep{name: "s1"}
+		tmp = &sesTestTemporal{delay: time.Millisecond * 50}
+		s2  = &sesTestStep{name: "s2"}
+	)
+
+	s1.SetID(1)
+	tmp.SetID(2)
+	s2.SetID(3)
+
+	wf.AddStep(s1, tmp)
+	wf.AddStep(tmp, s2)
+	wf.AddStep(s2)
+
+	req.NoError(ses.Exec(ctx, s1, nil))
+	req.NoError(ses.WaitUntil(ctx, SessionDelayed))
+
+	snap, err := ses.Snapshot()
+	req.NoError(err)
+	req.Len(snap.Delayed, 1)
+	req.Empty(snap.Prompted)
+	req.Equal(uint64(42), snap.WorkflowID)
+	req.Equal(uint64(2), snap.Delayed[0].State.StepID)
+	req.Equal(uint64(1), snap.Delayed[0].State.ParentID)
+
+	// original session is stopped as it would be when node is terminated
+	ses.Cancel()
+
+	restored, err := RestoreSession(ctx, wf, snapshotRoundTrip(req, snap), SetWorkerInterval(time.Millisecond))
+	req.NoError(err)
+	req.Equal(ses.ID(), restored.ID())
+	req.Equal(SessionDelayed, restored.Status())
+
+	req.NoError(restored.WaitUntil(ctx, SessionFailed, SessionCompleted))
+	req.Equal(SessionCompleted, restored.Status())
+	req.Equal("/s1/s2", expr.Must(expr.Select(restored.Result(), "path")).Get())
+	req.Equal("executed", expr.Must(expr.Select(restored.Result(), "waitForMoment")).Get())
+}
+
+func TestSession_SnapshotPrompted(t *testing.T) {
+	var (
+		ctx = auth.SetIdentityToContext(context.Background(), auth.Authenticated(100))
+		req = require.New(t)
+		wf  = NewGraph()
+		ses = NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+
+		s1 = &sesTestStep{name: "s1"}
+		p  = &sesTestStep{name: "p", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if r.Input == nil {
+				return Prompt(100, "ref", nil), nil
+			}
+
+			return r.Input, nil
+		}}
+	)
+
+	s1.SetID(1)
+	p.SetID(2)
+
+	wf.AddStep(s1, p)
+	wf.AddStep(p)
+
+	req.NoError(ses.Exec(ctx, s1, nil))
+	req.NoError(ses.WaitUntil(ctx, SessionPrompted))
+
+	snap, err := ses.Snapshot()
+	req.NoError(err)
+	req.Len(snap.Prompted, 1)
+	req.Equal(uint64(100), snap.Prompted[0].OwnerID)
+	req.Equal(uint64(100), snap.Prompted[0].State.OwnerID)
+
+	ses.Cancel()
+
+	restored, err := RestoreSession(ctx, wf, snapshotRoundTrip(req, snap), SetWorkerInterval(time.Millisecond))
+	req.NoError(err)
+	req.Equal(SessionPrompted, restored.Status())
+
+	pp := restored.UserPendingPrompts(100)
+	req.Len(pp, 1)
+	req.Equal("ref", pp[0].Ref)
+
+	input, _ := expr.NewVars(map[string]interface{}{"answer": "yes"})
+	_, err = restored.Resume(ctx, pp[0].StateID, input)
+	req.NoError(err)
+
+	req.NoError(restored.WaitUntil(ctx, SessionFailed, SessionCompleted))
+	req.Equal(SessionCompleted, restored.Status())
+	req.Equal("yes", expr.Must(expr.Select(restored.Result(), "answer")).Get())
+	req.Equal("/s1", expr.Must(expr.Select(restored.Result(), "path")).Get())
+}
+
+type sesTestIteratorHandler struct {
+	i, n int
+}
+
+func (h *sesTestIteratorHandler) Start(context.Context, *expr.Vars) error { return nil }
+
+func (h *sesTestIteratorHandler) More(context.Context, *expr.Vars) (bool, error) {
+	return h.i < h.n, nil
+}
+
+func (h *sesTestIteratorHandler) Next(context.Context, *expr.Vars) (*expr.Vars, error) {
+	h.i++
+	return &expr.Vars{}, nil
+}
+
+func TestSession_SnapshotInLoop(t *testing.T) {
+	var (
+		ctx = auth.SetIdentityToContext(context.Background(), auth.Authenticated(100))
+		req = require.New(t)
+		wf  = NewGraph()
+		ses = NewSession(ctx, wf, SetWorkerInterval(time.Millisecond))
+
+		s1   = &sesTestStep{name: "s1"}
+		iter = &sesTestStep{name: "iter"}
+		s2   = &sesTestStep{name: "s2"}
+		p    = &sesTestStep{name: "p", exec: func(ctx context.Context, r *ExecRequest) (ExecResponse, error) {
+			if r.Input == nil {
+				return Prompt(100, "ref", nil), nil
+			}
+
+			return r.Input, nil
+		}}
+	)
+
+	iter.exec = func(context.Context, *ExecRequest) (ExecResponse, error) {
+		return GenericIterator(iter, p, s2, &sesTestIteratorHandler{n: 2}), nil
+	}
+
+	s1.SetID(1)
+	iter.SetID(2)
+	p.SetID(3)
+	s2.SetID(4)
+
+	wf.AddStep(s1, iter)
+	wf.AddStep(iter, p, s2)
+	wf.AddStep(p)
+	wf.AddStep(s2)
+
+	req.NoError(ses.Exec(ctx, s1, nil))
+
+	// prompted in both iterations; snapshot is rejected each time
+	// but session itself continues
+	for i := 0; i < 2; i++ {
+		req.NoError(ses.WaitUntil(ctx, SessionPrompted))
+
+		_, err := ses.Snapshot()
+		req.True(errors.Is(err, ErrSuspendedInLoop))
+		req.Contains(err.Error(), "on step 3")
+
+		pp := ses.UserPendingPrompts(100)
+		req.Len(pp, 1)
+
+		_, err = ses.Resume(ctx, pp[0].StateID, &expr.Vars{})
+		req.NoError(err)
+	}
+
+	req.NoError(ses.WaitUntil(ctx, SessionFailed, SessionCompleted))
+	req.Equal(SessionCompleted, ses.Status())
+	req.Equal("/s1/s2", expr.Must(expr.Select(ses.Result(), "path")).Get())
+}
+
+func TestSession_RestoreMissingStep(t *testing.T) {
+	var (
+		req  = require.New(t)
+		snap = &SessionSnapshot{
+			SessionID: 1,
+			Delayed: []*DelayedSnapshot{
+				{State: &StateSnapshot{StateID: 1, StepID: 99}, ResumeAt: time.Now()},
+			},
+		}
+	)
+
+	_, err := RestoreSession(context.Background(), NewGraph(), snap)
+	req.Error(err)
+}
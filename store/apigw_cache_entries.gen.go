@@ -0,0 +1,91 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/apigw_cache_entries.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	ApigwCacheEntries interface {
+		SearchApigwCacheEntries(ctx context.Context, f types.ApigwCacheEntryFilter) (types.ApigwCacheEntrySet, types.ApigwCacheEntryFilter, error)
+		LookupApigwCacheEntryByKey(ctx context.Context, key string) (*types.ApigwCacheEntry, error)
+
+		CreateApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) error
+
+		UpdateApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) error
+
+		UpsertApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) error
+
+		DeleteApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) error
+		DeleteApigwCacheEntryByKey(ctx context.Context, key string) error
+
+		TruncateApigwCacheEntries(ctx context.Context) error
+
+		// Additional custom functions
+
+		// DeleteApigwCacheEntriesByRouteID (custom function)
+		DeleteApigwCacheEntriesByRouteID(ctx context.Context, _routeID uint64) error
+
+		// DeleteExpiredApigwCacheEntries (custom function)
+		DeleteExpiredApigwCacheEntries(ctx context.Context) error
+	}
+)
+
+var _ *types.ApigwCacheEntry
+var _ context.Context
+
+// SearchApigwCacheEntries returns all matching ApigwCacheEntries from store
+func SearchApigwCacheEntries(ctx context.Context, s ApigwCacheEntries, f types.ApigwCacheEntryFilter) (types.ApigwCacheEntrySet, types.ApigwCacheEntryFilter, error) {
+	return s.SearchApigwCacheEntries(ctx, f)
+}
+
+// LookupApigwCacheEntryByKey searches for cached response by key
+func LookupApigwCacheEntryByKey(ctx context.Context, s ApigwCacheEntries, key string) (*types.ApigwCacheEntry, error) {
+	return s.LookupApigwCacheEntryByKey(ctx, key)
+}
+
+// CreateApigwCacheEntry creates one or more ApigwCacheEntries in store
+func CreateApigwCacheEntry(ctx context.Context, s ApigwCacheEntries, rr ...*types.ApigwCacheEntry) error {
+	return s.CreateApigwCacheEntry(ctx, rr...)
+}
+
+// UpdateApigwCacheEntry updates one or more (existing) ApigwCacheEntries in store
+func UpdateApigwCacheEntry(ctx context.Context, s ApigwCacheEntries, rr ...*types.ApigwCacheEntry) error {
+	return s.UpdateApigwCacheEntry(ctx, rr...)
+}
+
+// UpsertApigwCacheEntry creates new or updates existing one or more ApigwCacheEntries in store
+func UpsertApigwCacheEntry(ctx context.Context, s ApigwCacheEntries, rr ...*types.ApigwCacheEntry) error {
+	return s.UpsertApigwCacheEntry(ctx, rr...)
+}
+
+// DeleteApigwCacheEntry Deletes one or more ApigwCacheEntries from store
+func DeleteApigwCacheEntry(ctx context.Context, s ApigwCacheEntries, rr ...*types.ApigwCacheEntry) error {
+	return s.DeleteApigwCacheEntry(ctx, rr...)
+}
+
+// DeleteApigwCacheEntryByKey Deletes ApigwCacheEntry from store
+func DeleteApigwCacheEntryByKey(ctx context.Context, s ApigwCacheEntries, key string) error {
+	return s.DeleteApigwCacheEntryByKey(ctx, key)
+}
+
+// TruncateApigwCacheEntries Deletes all ApigwCacheEntries from store
+func TruncateApigwCacheEntries(ctx context.Context, s ApigwCacheEntries) error {
+	return s.TruncateApigwCacheEntries(ctx)
+}
+
+func DeleteApigwCacheEntriesByRouteID(ctx context.Context, s ApigwCacheEntries, _routeID uint64) error {
+	return s.DeleteApigwCacheEntriesByRouteID(ctx, _routeID)
+}
+
+func DeleteExpiredApigwCacheEntries(ctx context.Context, s ApigwCacheEntries) error {
+	return s.DeleteExpiredApigwCacheEntries(ctx)
+}
@@ -27,6 +27,11 @@ type (
 		DeleteApigwRouteByID(ctx context.Context, ID uint64) error
 
 		TruncateApigwRoutes(ctx context.Context) error
+
+		// Additional custom functions
+
+		// CountApigwRoutes (custom function)
+		CountApigwRoutes(ctx context.Context, _f types.ApigwRouteFilter) (uint, error)
 	}
 )
 
@@ -72,3 +77,7 @@ func DeleteApigwRouteByID(ctx context.Context, s ApigwRoutes, ID uint64) error {
 func TruncateApigwRoutes(ctx context.Context, s ApigwRoutes) error {
 	return s.TruncateApigwRoutes(ctx)
 }
+
+func CountApigwRoutes(ctx context.Context, s ApigwRoutes, _f types.ApigwRouteFilter) (uint, error) {
+	return s.CountApigwRoutes(ctx, _f)
+}
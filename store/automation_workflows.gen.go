@@ -29,6 +29,11 @@ type (
 		DeleteAutomationWorkflowByID(ctx context.Context, ID uint64) error
 
 		TruncateAutomationWorkflows(ctx context.Context) error
+
+		// Additional custom functions
+
+		// CountAutomationWorkflows (custom function)
+		CountAutomationWorkflows(ctx context.Context, _f types.WorkflowFilter) (uint, error)
 	}
 )
 
@@ -83,3 +88,7 @@ func DeleteAutomationWorkflowByID(ctx context.Context, s AutomationWorkflows, ID
 func TruncateAutomationWorkflows(ctx context.Context, s AutomationWorkflows) error {
 	return s.TruncateAutomationWorkflows(ctx)
 }
+
+func CountAutomationWorkflows(ctx context.Context, s AutomationWorkflows, _f types.WorkflowFilter) (uint, error) {
+	return s.CountAutomationWorkflows(ctx, _f)
+}
@@ -0,0 +1,84 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/cluster_leases.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/cluster/types"
+)
+
+type (
+	ClusterLeases interface {
+		SearchClusterLeases(ctx context.Context, f types.LeaseFilter) (types.LeaseSet, types.LeaseFilter, error)
+		LookupClusterLeaseByName(ctx context.Context, name string) (*types.Lease, error)
+
+		CreateClusterLease(ctx context.Context, rr ...*types.Lease) error
+
+		UpdateClusterLease(ctx context.Context, rr ...*types.Lease) error
+
+		DeleteClusterLease(ctx context.Context, rr ...*types.Lease) error
+		DeleteClusterLeaseByName(ctx context.Context, name string) error
+
+		TruncateClusterLeases(ctx context.Context) error
+
+		// Additional custom functions
+
+		// AcquireClusterLease (custom function)
+		AcquireClusterLease(ctx context.Context, _lease *types.Lease) (bool, error)
+
+		// ReleaseClusterLease (custom function)
+		ReleaseClusterLease(ctx context.Context, _name string, _holder string) error
+	}
+)
+
+var _ *types.Lease
+var _ context.Context
+
+// SearchClusterLeases returns all matching ClusterLeases from store
+func SearchClusterLeases(ctx context.Context, s ClusterLeases, f types.LeaseFilter) (types.LeaseSet, types.LeaseFilter, error) {
+	return s.SearchClusterLeases(ctx, f)
+}
+
+// LookupClusterLeaseByName searches for cluster lease by name
+func LookupClusterLeaseByName(ctx context.Context, s ClusterLeases, name string) (*types.Lease, error) {
+	return s.LookupClusterLeaseByName(ctx, name)
+}
+
+// CreateClusterLease creates one or more ClusterLeases in store
+func CreateClusterLease(ctx context.Context, s ClusterLeases, rr ...*types.Lease) error {
+	return s.CreateClusterLease(ctx, rr...)
+}
+
+// UpdateClusterLease updates one or more (existing) ClusterLeases in store
+func UpdateClusterLease(ctx context.Context, s ClusterLeases, rr ...*types.Lease) error {
+	return s.UpdateClusterLease(ctx, rr...)
+}
+
+// DeleteClusterLease Deletes one or more ClusterLeases from store
+func DeleteClusterLease(ctx context.Context, s ClusterLeases, rr ...*types.Lease) error {
+	return s.DeleteClusterLease(ctx, rr...)
+}
+
+// DeleteClusterLeaseByName Deletes ClusterLease from store
+func DeleteClusterLeaseByName(ctx context.Context, s ClusterLeases, name string) error {
+	return s.DeleteClusterLeaseByName(ctx, name)
+}
+
+// TruncateClusterLeases Deletes all ClusterLeases from store
+func TruncateClusterLeases(ctx context.Context, s ClusterLeases) error {
+	return s.TruncateClusterLeases(ctx)
+}
+
+func AcquireClusterLease(ctx context.Context, s ClusterLeases, _lease *types.Lease) (bool, error) {
+	return s.AcquireClusterLease(ctx, _lease)
+}
+
+func ReleaseClusterLease(ctx context.Context, s ClusterLeases, _name string, _holder string) error {
+	return s.ReleaseClusterLease(ctx, _name, _holder)
+}
@@ -30,6 +30,11 @@ type (
 		DeleteComposeModuleByID(ctx context.Context, ID uint64) error
 
 		TruncateComposeModules(ctx context.Context) error
+
+		// Additional custom functions
+
+		// CountComposeModules (custom function)
+		CountComposeModules(ctx context.Context, _f types.ModuleFilter) (uint, error)
 	}
 )
 
@@ -87,3 +92,7 @@ func DeleteComposeModuleByID(ctx context.Context, s ComposeModules, ID uint64) e
 func TruncateComposeModules(ctx context.Context, s ComposeModules) error {
 	return s.TruncateComposeModules(ctx)
 }
+
+func CountComposeModules(ctx context.Context, s ComposeModules, _f types.ModuleFilter) (uint, error) {
+	return s.CountComposeModules(ctx, _f)
+}
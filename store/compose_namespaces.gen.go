@@ -29,6 +29,11 @@ type (
 		DeleteComposeNamespaceByID(ctx context.Context, ID uint64) error
 
 		TruncateComposeNamespaces(ctx context.Context) error
+
+		// Additional custom functions
+
+		// CountComposeNamespaces (custom function)
+		CountComposeNamespaces(ctx context.Context, _f types.NamespaceFilter) (uint, error)
 	}
 )
 
@@ -81,3 +86,7 @@ func DeleteComposeNamespaceByID(ctx context.Context, s ComposeNamespaces, ID uin
 func TruncateComposeNamespaces(ctx context.Context, s ComposeNamespaces) error {
 	return s.TruncateComposeNamespaces(ctx)
 }
+
+func CountComposeNamespaces(ctx context.Context, s ComposeNamespaces, _f types.NamespaceFilter) (uint, error) {
+	return s.CountComposeNamespaces(ctx, _f)
+}
@@ -32,6 +32,9 @@ type (
 
 		// Additional custom functions
 
+		// CountComposeRecords (custom function)
+		CountComposeRecords(ctx context.Context) (uint, error)
+
 		// ComposeRecordReport (custom function)
 		ComposeRecordReport(ctx context.Context, _mod *types.Module, _metrics string, _dimensions string, _filters string) ([]map[string]interface{}, error)
 
@@ -40,6 +43,9 @@ type (
 
 		// PartialComposeRecordValueUpdate (custom function)
 		PartialComposeRecordValueUpdate(ctx context.Context, _mod *types.Module, _values ...*types.RecordValue) error
+
+		// ReindexComposeRecordUniqueValues (custom function)
+		ReindexComposeRecordUniqueValues(ctx context.Context, _mod *types.Module) error
 	}
 )
 
@@ -87,6 +93,10 @@ func TruncateComposeRecords(ctx context.Context, s ComposeRecords, _mod *types.M
 	return s.TruncateComposeRecords(ctx, _mod)
 }
 
+func CountComposeRecords(ctx context.Context, s ComposeRecords) (uint, error) {
+	return s.CountComposeRecords(ctx)
+}
+
 func ComposeRecordReport(ctx context.Context, s ComposeRecords, _mod *types.Module, _metrics string, _dimensions string, _filters string) ([]map[string]interface{}, error) {
 	return s.ComposeRecordReport(ctx, _mod, _metrics, _dimensions, _filters)
 }
@@ -98,3 +108,7 @@ func ComposeRecordDatasource(ctx context.Context, s ComposeRecords, _mod *types.
 func PartialComposeRecordValueUpdate(ctx context.Context, s ComposeRecords, _mod *types.Module, _values ...*types.RecordValue) error {
 	return s.PartialComposeRecordValueUpdate(ctx, _mod, _values...)
 }
+
+func ReindexComposeRecordUniqueValues(ctx context.Context, s ComposeRecords, _mod *types.Module) error {
+	return s.ReindexComposeRecordUniqueValues(ctx, _mod)
+}
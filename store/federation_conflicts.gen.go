@@ -0,0 +1,77 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/federation_conflicts.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/federation/types"
+)
+
+type (
+	FederationConflicts interface {
+		SearchFederationConflicts(ctx context.Context, f types.ConflictFilter) (types.ConflictSet, types.ConflictFilter, error)
+		LookupFederationConflictByID(ctx context.Context, id uint64) (*types.Conflict, error)
+
+		CreateFederationConflict(ctx context.Context, rr ...*types.Conflict) error
+
+		UpdateFederationConflict(ctx context.Context, rr ...*types.Conflict) error
+
+		UpsertFederationConflict(ctx context.Context, rr ...*types.Conflict) error
+
+		DeleteFederationConflict(ctx context.Context, rr ...*types.Conflict) error
+		DeleteFederationConflictByID(ctx context.Context, ID uint64) error
+
+		TruncateFederationConflicts(ctx context.Context) error
+	}
+)
+
+var _ *types.Conflict
+var _ context.Context
+
+// SearchFederationConflicts returns all matching FederationConflicts from store
+func SearchFederationConflicts(ctx context.Context, s FederationConflicts, f types.ConflictFilter) (types.ConflictSet, types.ConflictFilter, error) {
+	return s.SearchFederationConflicts(ctx, f)
+}
+
+// LookupFederationConflictByID searches for federation conflict by ID
+//
+// It returns federation conflict
+func LookupFederationConflictByID(ctx context.Context, s FederationConflicts, id uint64) (*types.Conflict, error) {
+	return s.LookupFederationConflictByID(ctx, id)
+}
+
+// CreateFederationConflict creates one or more FederationConflicts in store
+func CreateFederationConflict(ctx context.Context, s FederationConflicts, rr ...*types.Conflict) error {
+	return s.CreateFederationConflict(ctx, rr...)
+}
+
+// UpdateFederationConflict updates one or more (existing) FederationConflicts in store
+func UpdateFederationConflict(ctx context.Context, s FederationConflicts, rr ...*types.Conflict) error {
+	return s.UpdateFederationConflict(ctx, rr...)
+}
+
+// UpsertFederationConflict creates new or updates existing one or more FederationConflicts in store
+func UpsertFederationConflict(ctx context.Context, s FederationConflicts, rr ...*types.Conflict) error {
+	return s.UpsertFederationConflict(ctx, rr...)
+}
+
+// DeleteFederationConflict Deletes one or more FederationConflicts from store
+func DeleteFederationConflict(ctx context.Context, s FederationConflicts, rr ...*types.Conflict) error {
+	return s.DeleteFederationConflict(ctx, rr...)
+}
+
+// DeleteFederationConflictByID Deletes FederationConflict from store
+func DeleteFederationConflictByID(ctx context.Context, s FederationConflicts, ID uint64) error {
+	return s.DeleteFederationConflictByID(ctx, ID)
+}
+
+// TruncateFederationConflicts Deletes all FederationConflicts from store
+func TruncateFederationConflicts(ctx context.Context, s FederationConflicts) error {
+	return s.TruncateFederationConflicts(ctx)
+}
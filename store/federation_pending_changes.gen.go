@@ -0,0 +1,77 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/federation_pending_changes.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/federation/types"
+)
+
+type (
+	FederationPendingChanges interface {
+		SearchFederationPendingChanges(ctx context.Context, f types.PendingChangeFilter) (types.PendingChangeSet, types.PendingChangeFilter, error)
+		LookupFederationPendingChangeByID(ctx context.Context, id uint64) (*types.PendingChange, error)
+
+		CreateFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) error
+
+		UpdateFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) error
+
+		UpsertFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) error
+
+		DeleteFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) error
+		DeleteFederationPendingChangeByID(ctx context.Context, ID uint64) error
+
+		TruncateFederationPendingChanges(ctx context.Context) error
+	}
+)
+
+var _ *types.PendingChange
+var _ context.Context
+
+// SearchFederationPendingChanges returns all matching FederationPendingChanges from store
+func SearchFederationPendingChanges(ctx context.Context, s FederationPendingChanges, f types.PendingChangeFilter) (types.PendingChangeSet, types.PendingChangeFilter, error) {
+	return s.SearchFederationPendingChanges(ctx, f)
+}
+
+// LookupFederationPendingChangeByID searches for pending federation change by ID
+//
+// It returns pending federation change
+func LookupFederationPendingChangeByID(ctx context.Context, s FederationPendingChanges, id uint64) (*types.PendingChange, error) {
+	return s.LookupFederationPendingChangeByID(ctx, id)
+}
+
+// CreateFederationPendingChange creates one or more FederationPendingChanges in store
+func CreateFederationPendingChange(ctx context.Context, s FederationPendingChanges, rr ...*types.PendingChange) error {
+	return s.CreateFederationPendingChange(ctx, rr...)
+}
+
+// UpdateFederationPendingChange updates one or more (existing) FederationPendingChanges in store
+func UpdateFederationPendingChange(ctx context.Context, s FederationPendingChanges, rr ...*types.PendingChange) error {
+	return s.UpdateFederationPendingChange(ctx, rr...)
+}
+
+// UpsertFederationPendingChange creates new or updates existing one or more FederationPendingChanges in store
+func UpsertFederationPendingChange(ctx context.Context, s FederationPendingChanges, rr ...*types.PendingChange) error {
+	return s.UpsertFederationPendingChange(ctx, rr...)
+}
+
+// DeleteFederationPendingChange Deletes one or more FederationPendingChanges from store
+func DeleteFederationPendingChange(ctx context.Context, s FederationPendingChanges, rr ...*types.PendingChange) error {
+	return s.DeleteFederationPendingChange(ctx, rr...)
+}
+
+// DeleteFederationPendingChangeByID Deletes FederationPendingChange from store
+func DeleteFederationPendingChangeByID(ctx context.Context, s FederationPendingChanges, ID uint64) error {
+	return s.DeleteFederationPendingChangeByID(ctx, ID)
+}
+
+// TruncateFederationPendingChanges Deletes all FederationPendingChanges from store
+func TruncateFederationPendingChanges(ctx context.Context, s FederationPendingChanges) error {
+	return s.TruncateFederationPendingChanges(ctx)
+}
@@ -5,8 +5,10 @@ package store
 // Template:	pkg/codegen/assets/store_interfaces_joined.gen.go.tpl
 // Definitions:
 //  - store/actionlog.yaml
+//  - store/apigw_cache_entries.yaml
 //  - store/apigw_filter.yaml
 //  - store/apigw_route.yaml
+//  - store/apigw_secrets.yaml
 //  - store/applications.yaml
 //  - store/attachments.yaml
 //  - store/auth_clients.yaml
@@ -16,22 +18,29 @@ package store
 //  - store/automation_sessions.yaml
 //  - store/automation_triggers.yaml
 //  - store/automation_workflows.yaml
+//  - store/cluster_leases.yaml
 //  - store/compose_attachments.yaml
 //  - store/compose_charts.yaml
 //  - store/compose_module_fields.yaml
 //  - store/compose_modules.yaml
 //  - store/compose_namespaces.yaml
 //  - store/compose_pages.yaml
+//  - store/compose_record_revisions.yaml
 //  - store/compose_record_values.yaml
 //  - store/compose_records.yaml
 //  - store/credentials.yaml
+//  - store/federation_conflicts.yaml
 //  - store/federation_exposed_modules.yaml
 //  - store/federation_module_mappings.yaml
 //  - store/federation_nodes.yaml
 //  - store/federation_nodes_sync.yaml
+//  - store/federation_pending_changes.yaml
 //  - store/federation_shared_modules.yaml
 //  - store/flags.yaml
+//  - store/invalidation_versions.yaml
 //  - store/labels.yaml
+//  - store/objstore_blobs.yaml
+//  - store/objstore_references.yaml
 //  - store/queue.yaml
 //  - store/queue_message.yaml
 //  - store/rbac_rules.yaml
@@ -43,6 +52,8 @@ package store
 //  - store/settings.yaml
 //  - store/templates.yaml
 //  - store/users.yaml
+//  - store/webhook_deliveries.yaml
+//  - store/webhooks.yaml
 //
 // Changes to this file may cause incorrect behavior and will be lost if
 // the code is regenerated.
@@ -52,8 +63,10 @@ type (
 	// Sortable interface combines interfaces of all supported store interfaces
 	storerGenerated interface {
 		Actionlogs
+		ApigwCacheEntries
 		ApigwFilters
 		ApigwRoutes
+		ApigwSecrets
 		Applications
 		Attachments
 		AuthClients
@@ -63,22 +76,29 @@ type (
 		AutomationSessions
 		AutomationTriggers
 		AutomationWorkflows
+		ClusterLeases
 		ComposeAttachments
 		ComposeCharts
 		ComposeModuleFields
 		ComposeModules
 		ComposeNamespaces
 		ComposePages
+		ComposeRecordRevisions
 		ComposeRecordValues
 		ComposeRecords
 		Credentials
+		FederationConflicts
 		FederationExposedModules
 		FederationModuleMappings
 		FederationNodes
 		FederationNodesSyncs
+		FederationPendingChanges
 		FederationSharedModules
 		Flags
+		InvalidationVersions
 		Labels
+		ObjstoreBlobs
+		ObjstoreReferences
 		Queues
 		QueueMessages
 		RbacRules
@@ -90,5 +110,7 @@ type (
 		Settings
 		Templates
 		Users
+		WebhookDeliveries
+		Webhooks
 	}
 )
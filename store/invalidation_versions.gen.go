@@ -0,0 +1,75 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/invalidation_versions.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation/types"
+)
+
+type (
+	InvalidationVersions interface {
+		SearchInvalidationVersions(ctx context.Context, f types.VersionFilter) (types.VersionSet, types.VersionFilter, error)
+		LookupInvalidationVersionByKind(ctx context.Context, kind string) (*types.Version, error)
+
+		CreateInvalidationVersion(ctx context.Context, rr ...*types.Version) error
+
+		UpdateInvalidationVersion(ctx context.Context, rr ...*types.Version) error
+
+		UpsertInvalidationVersion(ctx context.Context, rr ...*types.Version) error
+
+		DeleteInvalidationVersion(ctx context.Context, rr ...*types.Version) error
+		DeleteInvalidationVersionByKind(ctx context.Context, kind string) error
+
+		TruncateInvalidationVersions(ctx context.Context) error
+	}
+)
+
+var _ *types.Version
+var _ context.Context
+
+// SearchInvalidationVersions returns all matching InvalidationVersions from store
+func SearchInvalidationVersions(ctx context.Context, s InvalidationVersions, f types.VersionFilter) (types.VersionSet, types.VersionFilter, error) {
+	return s.SearchInvalidationVersions(ctx, f)
+}
+
+// LookupInvalidationVersionByKind searches for invalidation version by kind
+func LookupInvalidationVersionByKind(ctx context.Context, s InvalidationVersions, kind string) (*types.Version, error) {
+	return s.LookupInvalidationVersionByKind(ctx, kind)
+}
+
+// CreateInvalidationVersion creates one or more InvalidationVersions in store
+func CreateInvalidationVersion(ctx context.Context, s InvalidationVersions, rr ...*types.Version) error {
+	return s.CreateInvalidationVersion(ctx, rr...)
+}
+
+// UpdateInvalidationVersion updates one or more (existing) InvalidationVersions in store
+func UpdateInvalidationVersion(ctx context.Context, s InvalidationVersions, rr ...*types.Version) error {
+	return s.UpdateInvalidationVersion(ctx, rr...)
+}
+
+// UpsertInvalidationVersion creates new or updates existing one or more InvalidationVersions in store
+func UpsertInvalidationVersion(ctx context.Context, s InvalidationVersions, rr ...*types.Version) error {
+	return s.UpsertInvalidationVersion(ctx, rr...)
+}
+
+// DeleteInvalidationVersion Deletes one or more InvalidationVersions from store
+func DeleteInvalidationVersion(ctx context.Context, s InvalidationVersions, rr ...*types.Version) error {
+	return s.DeleteInvalidationVersion(ctx, rr...)
+}
+
+// DeleteInvalidationVersionByKind Deletes InvalidationVersion from store
+func DeleteInvalidationVersionByKind(ctx context.Context, s InvalidationVersions, kind string) error {
+	return s.DeleteInvalidationVersionByKind(ctx, kind)
+}
+
+// TruncateInvalidationVersions Deletes all InvalidationVersions from store
+func TruncateInvalidationVersions(ctx context.Context, s InvalidationVersions) error {
+	return s.TruncateInvalidationVersions(ctx)
+}
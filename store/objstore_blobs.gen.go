@@ -0,0 +1,91 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/objstore_blobs.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+)
+
+type (
+	ObjstoreBlobs interface {
+		SearchObjstoreBlobs(ctx context.Context, f types.BlobFilter) (types.BlobSet, types.BlobFilter, error)
+		LookupObjstoreBlobByNamespaceHash(ctx context.Context, namespace string, hash string) (*types.Blob, error)
+
+		CreateObjstoreBlob(ctx context.Context, rr ...*types.Blob) error
+
+		UpdateObjstoreBlob(ctx context.Context, rr ...*types.Blob) error
+
+		UpsertObjstoreBlob(ctx context.Context, rr ...*types.Blob) error
+
+		DeleteObjstoreBlob(ctx context.Context, rr ...*types.Blob) error
+		DeleteObjstoreBlobByNamespaceHash(ctx context.Context, namespace string, hash string) error
+
+		TruncateObjstoreBlobs(ctx context.Context) error
+
+		// Additional custom functions
+
+		// AcquireObjstoreBlob (custom function)
+		AcquireObjstoreBlob(ctx context.Context, _b *types.Blob) (bool, error)
+
+		// ReleaseObjstoreBlob (custom function)
+		ReleaseObjstoreBlob(ctx context.Context, _namespace string, _hash string) (bool, error)
+	}
+)
+
+var _ *types.Blob
+var _ context.Context
+
+// SearchObjstoreBlobs returns all matching ObjstoreBlobs from store
+func SearchObjstoreBlobs(ctx context.Context, s ObjstoreBlobs, f types.BlobFilter) (types.BlobSet, types.BlobFilter, error) {
+	return s.SearchObjstoreBlobs(ctx, f)
+}
+
+// LookupObjstoreBlobByNamespaceHash searches for content-addressed blob by namespace and hash
+func LookupObjstoreBlobByNamespaceHash(ctx context.Context, s ObjstoreBlobs, namespace string, hash string) (*types.Blob, error) {
+	return s.LookupObjstoreBlobByNamespaceHash(ctx, namespace, hash)
+}
+
+// CreateObjstoreBlob creates one or more ObjstoreBlobs in store
+func CreateObjstoreBlob(ctx context.Context, s ObjstoreBlobs, rr ...*types.Blob) error {
+	return s.CreateObjstoreBlob(ctx, rr...)
+}
+
+// UpdateObjstoreBlob updates one or more (existing) ObjstoreBlobs in store
+func UpdateObjstoreBlob(ctx context.Context, s ObjstoreBlobs, rr ...*types.Blob) error {
+	return s.UpdateObjstoreBlob(ctx, rr...)
+}
+
+// UpsertObjstoreBlob creates new or updates existing one or more ObjstoreBlobs in store
+func UpsertObjstoreBlob(ctx context.Context, s ObjstoreBlobs, rr ...*types.Blob) error {
+	return s.UpsertObjstoreBlob(ctx, rr...)
+}
+
+// DeleteObjstoreBlob Deletes one or more ObjstoreBlobs from store
+func DeleteObjstoreBlob(ctx context.Context, s ObjstoreBlobs, rr ...*types.Blob) error {
+	return s.DeleteObjstoreBlob(ctx, rr...)
+}
+
+// DeleteObjstoreBlobByNamespaceHash Deletes ObjstoreBlob from store
+func DeleteObjstoreBlobByNamespaceHash(ctx context.Context, s ObjstoreBlobs, namespace string, hash string) error {
+	return s.DeleteObjstoreBlobByNamespaceHash(ctx, namespace, hash)
+}
+
+// TruncateObjstoreBlobs Deletes all ObjstoreBlobs from store
+func TruncateObjstoreBlobs(ctx context.Context, s ObjstoreBlobs) error {
+	return s.TruncateObjstoreBlobs(ctx)
+}
+
+func AcquireObjstoreBlob(ctx context.Context, s ObjstoreBlobs, _b *types.Blob) (bool, error) {
+	return s.AcquireObjstoreBlob(ctx, _b)
+}
+
+func ReleaseObjstoreBlob(ctx context.Context, s ObjstoreBlobs, _namespace string, _hash string) (bool, error) {
+	return s.ReleaseObjstoreBlob(ctx, _namespace, _hash)
+}
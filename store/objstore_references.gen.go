@@ -0,0 +1,75 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/objstore_references.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+)
+
+type (
+	ObjstoreReferences interface {
+		SearchObjstoreReferences(ctx context.Context, f types.ReferenceFilter) (types.ReferenceSet, types.ReferenceFilter, error)
+		LookupObjstoreReferenceByNamespaceName(ctx context.Context, namespace string, name string) (*types.Reference, error)
+
+		CreateObjstoreReference(ctx context.Context, rr ...*types.Reference) error
+
+		UpdateObjstoreReference(ctx context.Context, rr ...*types.Reference) error
+
+		UpsertObjstoreReference(ctx context.Context, rr ...*types.Reference) error
+
+		DeleteObjstoreReference(ctx context.Context, rr ...*types.Reference) error
+		DeleteObjstoreReferenceByNamespaceName(ctx context.Context, namespace string, name string) error
+
+		TruncateObjstoreReferences(ctx context.Context) error
+	}
+)
+
+var _ *types.Reference
+var _ context.Context
+
+// SearchObjstoreReferences returns all matching ObjstoreReferences from store
+func SearchObjstoreReferences(ctx context.Context, s ObjstoreReferences, f types.ReferenceFilter) (types.ReferenceSet, types.ReferenceFilter, error) {
+	return s.SearchObjstoreReferences(ctx, f)
+}
+
+// LookupObjstoreReferenceByNamespaceName searches for object store reference by namespace and name
+func LookupObjstoreReferenceByNamespaceName(ctx context.Context, s ObjstoreReferences, namespace string, name string) (*types.Reference, error) {
+	return s.LookupObjstoreReferenceByNamespaceName(ctx, namespace, name)
+}
+
+// CreateObjstoreReference creates one or more ObjstoreReferences in store
+func CreateObjstoreReference(ctx context.Context, s ObjstoreReferences, rr ...*types.Reference) error {
+	return s.CreateObjstoreReference(ctx, rr...)
+}
+
+// UpdateObjstoreReference updates one or more (existing) ObjstoreReferences in store
+func UpdateObjstoreReference(ctx context.Context, s ObjstoreReferences, rr ...*types.Reference) error {
+	return s.UpdateObjstoreReference(ctx, rr...)
+}
+
+// UpsertObjstoreReference creates new or updates existing one or more ObjstoreReferences in store
+func UpsertObjstoreReference(ctx context.Context, s ObjstoreReferences, rr ...*types.Reference) error {
+	return s.UpsertObjstoreReference(ctx, rr...)
+}
+
+// DeleteObjstoreReference Deletes one or more ObjstoreReferences from store
+func DeleteObjstoreReference(ctx context.Context, s ObjstoreReferences, rr ...*types.Reference) error {
+	return s.DeleteObjstoreReference(ctx, rr...)
+}
+
+// DeleteObjstoreReferenceByNamespaceName Deletes ObjstoreReference from store
+func DeleteObjstoreReferenceByNamespaceName(ctx context.Context, s ObjstoreReferences, namespace string, name string) error {
+	return s.DeleteObjstoreReferenceByNamespaceName(ctx, namespace, name)
+}
+
+// TruncateObjstoreReferences Deletes all ObjstoreReferences from store
+func TruncateObjstoreReferences(ctx context.Context, s ObjstoreReferences) error {
+	return s.TruncateObjstoreReferences(ctx)
+}
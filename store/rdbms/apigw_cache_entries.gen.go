@@ -0,0 +1,345 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/apigw_cache_entries.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+var _ = errors.Is
+
+// SearchApigwCacheEntries returns all matching rows
+//
+// This function calls convertApigwCacheEntryFilter with the given
+// types.ApigwCacheEntryFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchApigwCacheEntries(ctx context.Context, f types.ApigwCacheEntryFilter) (types.ApigwCacheEntrySet, types.ApigwCacheEntryFilter, error) {
+	var (
+		err error
+		set []*types.ApigwCacheEntry
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertApigwCacheEntryFilter(f)
+		if err != nil {
+			return err
+		}
+
+		set, err = s.QueryApigwCacheEntries(ctx, q, nil)
+		return err
+	}()
+}
+
+// QueryApigwCacheEntries queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryApigwCacheEntries(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.ApigwCacheEntry) (bool, error),
+) ([]*types.ApigwCacheEntry, error) {
+	var (
+		tmp = make([]*types.ApigwCacheEntry, 0, DefaultSliceCapacity)
+		set = make([]*types.ApigwCacheEntry, 0, DefaultSliceCapacity)
+		res *types.ApigwCacheEntry
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalApigwCacheEntryRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupApigwCacheEntryByKey searches for cached response by key
+func (s Store) LookupApigwCacheEntryByKey(ctx context.Context, key string) (*types.ApigwCacheEntry, error) {
+	return s.execLookupApigwCacheEntry(ctx, squirrel.Eq{
+		s.preprocessColumn("agce.cache_key", ""): store.PreprocessValue(key, ""),
+	})
+}
+
+// CreateApigwCacheEntry creates one or more rows in apigw_cache_entries table
+func (s Store) CreateApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) (err error) {
+	for _, res := range rr {
+		err = s.checkApigwCacheEntryConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateApigwCacheEntries(ctx, s.internalApigwCacheEntryEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateApigwCacheEntry updates one or more existing rows in apigw_cache_entries
+func (s Store) UpdateApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) error {
+	return s.partialApigwCacheEntryUpdate(ctx, nil, rr...)
+}
+
+// partialApigwCacheEntryUpdate updates one or more existing rows in apigw_cache_entries
+func (s Store) partialApigwCacheEntryUpdate(ctx context.Context, onlyColumns []string, rr ...*types.ApigwCacheEntry) (err error) {
+	for _, res := range rr {
+		err = s.checkApigwCacheEntryConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateApigwCacheEntries(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("agce.cache_key", ""): store.PreprocessValue(res.Key, ""),
+			},
+			s.internalApigwCacheEntryEncoder(res).Skip("cache_key").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertApigwCacheEntry updates one or more existing rows in apigw_cache_entries
+func (s Store) UpsertApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) (err error) {
+	for _, res := range rr {
+		err = s.checkApigwCacheEntryConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertApigwCacheEntries(ctx, s.internalApigwCacheEntryEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteApigwCacheEntry Deletes one or more rows from apigw_cache_entries table
+func (s Store) DeleteApigwCacheEntry(ctx context.Context, rr ...*types.ApigwCacheEntry) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteApigwCacheEntries(ctx, squirrel.Eq{
+			s.preprocessColumn("agce.cache_key", ""): store.PreprocessValue(res.Key, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteApigwCacheEntryByKey Deletes row from the apigw_cache_entries table
+func (s Store) DeleteApigwCacheEntryByKey(ctx context.Context, key string) error {
+	return s.execDeleteApigwCacheEntries(ctx, squirrel.Eq{
+		s.preprocessColumn("agce.cache_key", ""): store.PreprocessValue(key, ""),
+	})
+}
+
+// TruncateApigwCacheEntries Deletes all rows from the apigw_cache_entries table
+func (s Store) TruncateApigwCacheEntries(ctx context.Context) error {
+	return s.Truncate(ctx, s.apigwCacheEntryTable())
+}
+
+// execLookupApigwCacheEntry prepares ApigwCacheEntry query and executes it,
+// returning types.ApigwCacheEntry (or error)
+func (s Store) execLookupApigwCacheEntry(ctx context.Context, cnd squirrel.Sqlizer) (res *types.ApigwCacheEntry, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.apigwCacheEntriesSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalApigwCacheEntryRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateApigwCacheEntries updates all matched (by cnd) rows in apigw_cache_entries with given data
+func (s Store) execCreateApigwCacheEntries(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.apigwCacheEntryTable()).SetMap(payload))
+}
+
+// execUpdateApigwCacheEntries updates all matched (by cnd) rows in apigw_cache_entries with given data
+func (s Store) execUpdateApigwCacheEntries(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.apigwCacheEntryTable("agce")).Where(cnd).SetMap(set))
+}
+
+// execUpsertApigwCacheEntries inserts new or updates matching (by-primary-key) rows in apigw_cache_entries with given data
+func (s Store) execUpsertApigwCacheEntries(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.apigwCacheEntryTable(),
+		set,
+		s.preprocessColumn("cache_key", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteApigwCacheEntries Deletes all matched (by cnd) rows in apigw_cache_entries with given data
+func (s Store) execDeleteApigwCacheEntries(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.apigwCacheEntryTable("agce")).Where(cnd))
+}
+
+func (s Store) internalApigwCacheEntryRowScanner(row rowScanner) (res *types.ApigwCacheEntry, err error) {
+	res = &types.ApigwCacheEntry{}
+
+	if _, has := s.config.RowScanners["apigwCacheEntry"]; has {
+		scanner := s.config.RowScanners["apigwCacheEntry"].(func(_ rowScanner, _ *types.ApigwCacheEntry) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.Key,
+			&res.RouteID,
+			&res.Status,
+			&res.Header,
+			&res.Body,
+			&res.ETag,
+			&res.CreatedAt,
+			&res.ExpiresAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan apigwCacheEntry db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryApigwCacheEntries returns squirrel.SelectBuilder with set table and all columns
+func (s Store) apigwCacheEntriesSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.apigwCacheEntryTable("agce"), s.apigwCacheEntryColumns("agce")...)
+}
+
+// apigwCacheEntryTable name of the db table
+func (Store) apigwCacheEntryTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "apigw_cache_entries" + alias
+}
+
+// ApigwCacheEntryColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) apigwCacheEntryColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "cache_key",
+		alias + "rel_route",
+		alias + "status",
+		alias + "header",
+		alias + "body",
+		alias + "etag",
+		alias + "created_at",
+		alias + "expires_at",
+	}
+}
+
+// {true true false false false false}
+
+// internalApigwCacheEntryEncoder encodes fields from types.ApigwCacheEntry to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeApigwCacheEntry
+// func when rdbms.customEncoder=true
+func (s Store) internalApigwCacheEntryEncoder(res *types.ApigwCacheEntry) store.Payload {
+	return store.Payload{
+		"cache_key":  res.Key,
+		"rel_route":  res.RouteID,
+		"status":     res.Status,
+		"header":     res.Header,
+		"body":       res.Body,
+		"etag":       res.ETag,
+		"created_at": res.CreatedAt,
+		"expires_at": res.ExpiresAt,
+	}
+}
+
+// checkApigwCacheEntryConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkApigwCacheEntryConstraints(ctx context.Context, res *types.ApigwCacheEntry) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
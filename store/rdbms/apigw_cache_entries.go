@@ -0,0 +1,27 @@
+package rdbms
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+func (s Store) convertApigwCacheEntryFilter(f types.ApigwCacheEntryFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.apigwCacheEntriesSelectBuilder()
+
+	if f.RouteID > 0 {
+		query = query.Where(squirrel.Eq{"agce.rel_route": f.RouteID})
+	}
+
+	return
+}
+
+func (s Store) DeleteApigwCacheEntriesByRouteID(ctx context.Context, routeID uint64) error {
+	return s.execDeleteApigwCacheEntries(ctx, squirrel.Eq{"agce.rel_route": routeID})
+}
+
+func (s Store) DeleteExpiredApigwCacheEntries(ctx context.Context) error {
+	return s.execDeleteApigwCacheEntries(ctx, squirrel.LtOrEq{"agce.expires_at": time.Now()})
+}
@@ -1,6 +1,8 @@
 package rdbms
 
 import (
+	"context"
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -13,3 +15,11 @@ func (s Store) convertApigwRouteFilter(f types.ApigwRouteFilter) (query squirrel
 
 	return
 }
+
+func (s Store) CountApigwRoutes(ctx context.Context, f types.ApigwRouteFilter) (uint, error) {
+	if q, err := s.convertApigwRouteFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count routes: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
@@ -0,0 +1,31 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+func (s Store) convertApigwSecretFilter(f types.ApigwSecretFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.apigwSecretsSelectBuilder()
+	query = filter.StateCondition(query, "asec.deleted_at", f.Deleted)
+
+	if len(f.SecretID) > 0 {
+		query = query.Where(squirrel.Eq{"asec.id": f.SecretID})
+	}
+
+	if f.Handle != "" {
+		query = query.Where(squirrel.Eq{"asec.handle": f.Handle})
+	}
+
+	if f.KeyIDNot != "" {
+		query = query.Where(squirrel.NotEq{"asec.key_id": f.KeyIDNot})
+	}
+
+	if f.Query != "" {
+		qs := "%" + f.Query + "%"
+		query = query.Where(squirrel.Like{"asec.handle": qs})
+	}
+
+	return
+}
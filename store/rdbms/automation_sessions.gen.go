@@ -451,6 +451,7 @@ func (s Store) internalAutomationSessionRowScanner(row rowScanner) (res *types.S
 			&res.CompletedAt,
 			&res.SuspendedAt,
 			&res.Error,
+			&res.State,
 		)
 	}
 
@@ -504,6 +505,7 @@ func (Store) automationSessionColumns(aa ...string) []string {
 		alias + "completed_at",
 		alias + "suspended_at",
 		alias + "error",
+		alias + "state",
 	}
 }
 
@@ -547,6 +549,7 @@ func (s Store) internalAutomationSessionEncoder(res *types.Session) store.Payloa
 		"completed_at":  res.CompletedAt,
 		"suspended_at":  res.SuspendedAt,
 		"error":         res.Error,
+		"state":         res.State,
 	}
 }
 
@@ -1,6 +1,8 @@
 package rdbms
 
 import (
+	"context"
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/automation/types"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
@@ -29,3 +31,11 @@ func (s Store) convertAutomationWorkflowFilter(f types.WorkflowFilter) (query sq
 
 	return
 }
+
+func (s Store) CountAutomationWorkflows(ctx context.Context, f types.WorkflowFilter) (uint, error) {
+	if q, err := s.convertAutomationWorkflowFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count workflows: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
@@ -0,0 +1,300 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/cluster_leases.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/cluster/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+var _ = errors.Is
+
+// SearchClusterLeases returns all matching rows
+//
+// This function calls convertClusterLeaseFilter with the given
+// types.LeaseFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchClusterLeases(ctx context.Context, f types.LeaseFilter) (types.LeaseSet, types.LeaseFilter, error) {
+	var (
+		err error
+		set []*types.Lease
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertClusterLeaseFilter(f)
+		if err != nil {
+			return err
+		}
+
+		set, err = s.QueryClusterLeases(ctx, q, nil)
+		return err
+	}()
+}
+
+// QueryClusterLeases queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryClusterLeases(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.Lease) (bool, error),
+) ([]*types.Lease, error) {
+	var (
+		tmp = make([]*types.Lease, 0, DefaultSliceCapacity)
+		set = make([]*types.Lease, 0, DefaultSliceCapacity)
+		res *types.Lease
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalClusterLeaseRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupClusterLeaseByName searches for cluster lease by name
+func (s Store) LookupClusterLeaseByName(ctx context.Context, name string) (*types.Lease, error) {
+	return s.execLookupClusterLease(ctx, squirrel.Eq{
+		s.preprocessColumn("clsl.name", ""): store.PreprocessValue(name, ""),
+	})
+}
+
+// CreateClusterLease creates one or more rows in cluster_leases table
+func (s Store) CreateClusterLease(ctx context.Context, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+		err = s.checkClusterLeaseConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateClusterLeases(ctx, s.internalClusterLeaseEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateClusterLease updates one or more existing rows in cluster_leases
+func (s Store) UpdateClusterLease(ctx context.Context, rr ...*types.Lease) error {
+	return s.partialClusterLeaseUpdate(ctx, nil, rr...)
+}
+
+// partialClusterLeaseUpdate updates one or more existing rows in cluster_leases
+func (s Store) partialClusterLeaseUpdate(ctx context.Context, onlyColumns []string, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+		err = s.checkClusterLeaseConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateClusterLeases(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("clsl.name", ""): store.PreprocessValue(res.Name, ""),
+			},
+			s.internalClusterLeaseEncoder(res).Skip("name").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// DeleteClusterLease Deletes one or more rows from cluster_leases table
+func (s Store) DeleteClusterLease(ctx context.Context, rr ...*types.Lease) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteClusterLeases(ctx, squirrel.Eq{
+			s.preprocessColumn("clsl.name", ""): store.PreprocessValue(res.Name, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteClusterLeaseByName Deletes row from the cluster_leases table
+func (s Store) DeleteClusterLeaseByName(ctx context.Context, name string) error {
+	return s.execDeleteClusterLeases(ctx, squirrel.Eq{
+		s.preprocessColumn("clsl.name", ""): store.PreprocessValue(name, ""),
+	})
+}
+
+// TruncateClusterLeases Deletes all rows from the cluster_leases table
+func (s Store) TruncateClusterLeases(ctx context.Context) error {
+	return s.Truncate(ctx, s.clusterLeaseTable())
+}
+
+// execLookupClusterLease prepares ClusterLease query and executes it,
+// returning types.Lease (or error)
+func (s Store) execLookupClusterLease(ctx context.Context, cnd squirrel.Sqlizer) (res *types.Lease, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.clusterLeasesSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalClusterLeaseRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateClusterLeases updates all matched (by cnd) rows in cluster_leases with given data
+func (s Store) execCreateClusterLeases(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.clusterLeaseTable()).SetMap(payload))
+}
+
+// execUpdateClusterLeases updates all matched (by cnd) rows in cluster_leases with given data
+func (s Store) execUpdateClusterLeases(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.clusterLeaseTable("clsl")).Where(cnd).SetMap(set))
+}
+
+// execDeleteClusterLeases Deletes all matched (by cnd) rows in cluster_leases with given data
+func (s Store) execDeleteClusterLeases(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.clusterLeaseTable("clsl")).Where(cnd))
+}
+
+func (s Store) internalClusterLeaseRowScanner(row rowScanner) (res *types.Lease, err error) {
+	res = &types.Lease{}
+
+	if _, has := s.config.RowScanners["clusterLease"]; has {
+		scanner := s.config.RowScanners["clusterLease"].(func(_ rowScanner, _ *types.Lease) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.Name,
+			&res.Holder,
+			&res.AcquiredAt,
+			&res.ExpiresAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan clusterLease db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryClusterLeases returns squirrel.SelectBuilder with set table and all columns
+func (s Store) clusterLeasesSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.clusterLeaseTable("clsl"), s.clusterLeaseColumns("clsl")...)
+}
+
+// clusterLeaseTable name of the db table
+func (Store) clusterLeaseTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "cluster_leases" + alias
+}
+
+// ClusterLeaseColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) clusterLeaseColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "name",
+		alias + "holder",
+		alias + "acquired_at",
+		alias + "expires_at",
+	}
+}
+
+// {true true false false false false}
+
+// internalClusterLeaseEncoder encodes fields from types.Lease to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeClusterLease
+// func when rdbms.customEncoder=true
+func (s Store) internalClusterLeaseEncoder(res *types.Lease) store.Payload {
+	return store.Payload{
+		"name":        res.Name,
+		"holder":      res.Holder,
+		"acquired_at": res.AcquiredAt,
+		"expires_at":  res.ExpiresAt,
+	}
+}
+
+// checkClusterLeaseConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkClusterLeaseConstraints(ctx context.Context, res *types.Lease) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
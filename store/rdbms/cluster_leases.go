@@ -0,0 +1,77 @@
+package rdbms
+
+import (
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/cluster/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+func (s Store) convertClusterLeaseFilter(f types.LeaseFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.clusterLeasesSelectBuilder()
+
+	if f.Holder != "" {
+		query = query.Where(squirrel.Eq{"clsl.holder": f.Holder})
+	}
+
+	if f.Valid {
+		query = query.Where(squirrel.Gt{"clsl.expires_at": time.Now()})
+	}
+
+	return
+}
+
+// AcquireClusterLease acquires or renews the lease
+//
+// Lease is acquired when it does not exist yet, when it is already held by
+// the same holder or when it expired. Existing leases are taken over with a
+// single conditional update so that only one of the competing nodes succeeds.
+//
+// Returns false (without error) when lease is held by someone else
+func (s Store) AcquireClusterLease(ctx context.Context, l *types.Lease) (bool, error) {
+	query, args, err := s.UpdateBuilder(s.clusterLeaseTable()).
+		Set("acquired_at", squirrel.Expr("CASE WHEN holder = ? THEN acquired_at ELSE ? END", l.Holder, l.AcquiredAt)).
+		Set("holder", l.Holder).
+		Set("expires_at", l.ExpiresAt).
+		Where(squirrel.Eq{"name": l.Name}).
+		Where(squirrel.Or{
+			squirrel.Eq{"holder": l.Holder},
+			squirrel.LtOrEq{"expires_at": l.AcquiredAt},
+		}).
+		ToSql()
+
+	if err != nil {
+		return false, err
+	}
+
+	res, err := s.DB().ExecContext(ctx, query, args...)
+	if err = store.HandleError(err, s.config.ErrorHandler); err != nil {
+		return false, err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return false, err
+	} else if n > 0 {
+		return true, nil
+	}
+
+	// Nothing was updated; lease either does not exist yet
+	// or it is held by someone else
+	err = s.execCreateClusterLeases(ctx, s.internalClusterLeaseEncoder(l))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.IsDuplicateData(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// ReleaseClusterLease removes the lease if it is held by the given holder
+func (s Store) ReleaseClusterLease(ctx context.Context, name, holder string) error {
+	return s.execDeleteClusterLeases(ctx, squirrel.Eq{"clsl.name": name, "clsl.holder": holder})
+}
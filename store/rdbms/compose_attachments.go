@@ -7,17 +7,20 @@ import (
 )
 
 func (s Store) convertComposeAttachmentFilter(f types.AttachmentFilter) (query squirrel.SelectBuilder, err error) {
-	query = s.attachmentsSelectBuilder()
+	query = s.composeAttachmentsSelectBuilder()
 
 	if f.Kind != "" {
 		query = query.Where(squirrel.Eq{"att.kind": f.Kind})
 	}
 
 	if f.NamespaceID > 0 {
-		query = query.Where("a.rel_namespace = ?", f.NamespaceID)
+		query = query.Where("att.rel_namespace = ?", f.NamespaceID)
 	}
 
 	switch f.Kind {
+	case "", types.NamespaceAttachment:
+		// no additional filtering
+
 	case types.PageAttachment:
 		// @todo implement filtering by page
 		if f.PageID > 0 {
@@ -27,7 +30,7 @@ func (s Store) convertComposeAttachmentFilter(f types.AttachmentFilter) (query s
 
 	case types.RecordAttachment:
 		query = query.
-			Join("compose_record_value AS v ON (v.ref = a.id)")
+			Join("compose_record_value AS v ON (v.ref = att.id)")
 
 		if f.ModuleID > 0 {
 			query = query.
@@ -1,6 +1,8 @@
 package rdbms
 
 import (
+	"context"
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/compose/types"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
@@ -42,3 +44,11 @@ func (s Store) convertComposeModuleFilter(f types.ModuleFilter) (query squirrel.
 
 	return
 }
+
+func (s Store) CountComposeModules(ctx context.Context, f types.ModuleFilter) (uint, error) {
+	if q, err := s.convertComposeModuleFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count modules: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
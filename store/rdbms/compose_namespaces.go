@@ -1,6 +1,8 @@
 package rdbms
 
 import (
+	"context"
+	"fmt"
 	"strings"
 
 	"github.com/Masterminds/squirrel"
@@ -39,3 +41,11 @@ func (s Store) convertComposeNamespaceFilter(f types.NamespaceFilter) (query squ
 
 	return
 }
+
+func (s Store) CountComposeNamespaces(ctx context.Context, f types.NamespaceFilter) (uint, error) {
+	if q, err := s.convertComposeNamespaceFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count namespaces: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
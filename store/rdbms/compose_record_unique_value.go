@@ -0,0 +1,183 @@
+package rdbms
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+// Unique constraints (see types.ModuleConfigUniqueConstraint) are enforced
+// with an index table that holds one row per record and constraint.
+//
+// Rows are keyed by module, constraint name and hash of the constrained
+// values so that the database refuses any duplicates, even when records
+// are stored concurrently.
+
+func (Store) composeRecordUniqueValueTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "compose_record_unique_value" + alias
+}
+
+// updateComposeRecordUniqueValues replaces all unique constraint index rows of the record
+//
+// Deleted records are only removed from the index
+func (s Store) updateComposeRecordUniqueValues(ctx context.Context, m *types.Module, r *types.Record) (err error) {
+	if len(m.Config.UniqueConstraints) == 0 {
+		return
+	}
+
+	err = s.Exec(ctx, s.DeleteBuilder(s.composeRecordUniqueValueTable()).Where(squirrel.Eq{"rel_record": r.ID}))
+	if err != nil || r.DeletedAt != nil {
+		return
+	}
+
+	for _, c := range m.Config.UniqueConstraints {
+		key, ok := c.Key(r.Values)
+		if !ok {
+			continue
+		}
+
+		if err = s.createComposeRecordUniqueValue(ctx, m, c, key, r.ID); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// createComposeRecordUniqueValue adds one unique constraint index row
+//
+// Duplicates are reported with RecordValueErrorSet, with one error for every constraint field
+func (s Store) createComposeRecordUniqueValue(ctx context.Context, m *types.Module, c *types.ModuleConfigUniqueConstraint, key string, recordID uint64) error {
+	var (
+		cnd = squirrel.Eq{
+			"rel_module": m.ID,
+			"name":       c.Name,
+			"hash":       key,
+		}
+
+		duplicateErr = func(duplicateRecordID uint64) error {
+			rve := &types.RecordValueErrorSet{}
+			for _, name := range c.Fields {
+				meta := map[string]interface{}{"field": name, "constraint": c.Name}
+				if duplicateRecordID > 0 {
+					meta["recordID"] = duplicateRecordID
+				}
+
+				rve.Push(types.RecordValueError{Kind: "duplicateValue", Meta: meta})
+			}
+
+			return rve
+		}
+	)
+
+	// Look for the existing row first so we can tell which record holds the values;
+	// insert below is what actually guards against concurrent writes
+	row, err := s.QueryRow(ctx, s.SelectBuilder(s.composeRecordUniqueValueTable(), "rel_record").Where(cnd))
+	if err != nil {
+		return err
+	}
+
+	var duplicateRecordID uint64
+	if err = row.Scan(&duplicateRecordID); err == nil {
+		return duplicateErr(duplicateRecordID)
+	} else if err != sql.ErrNoRows {
+		return err
+	}
+
+	err = s.Exec(ctx, s.InsertBuilder(s.composeRecordUniqueValueTable()).SetMap(store.Payload{
+		"rel_module": m.ID,
+		"name":       c.Name,
+		"hash":       key,
+		"rel_record": recordID,
+	}))
+
+	if errors.IsDuplicateData(err) {
+		return duplicateErr(0)
+	}
+
+	return err
+}
+
+// ReindexComposeRecordUniqueValues rebuilds unique constraint index for all records of the module
+//
+// Returns store.ErrNotUnique when existing records violate any of the constraints
+func (s Store) ReindexComposeRecordUniqueValues(ctx context.Context, m *types.Module) (err error) {
+	err = s.Exec(ctx, s.DeleteBuilder(s.composeRecordUniqueValueTable()).Where(squirrel.Eq{"rel_module": m.ID}))
+	if err != nil || len(m.Config.UniqueConstraints) == 0 {
+		return
+	}
+
+	var (
+		names  = make([]string, 0)
+		values = make(map[uint64]types.RecordValueSet)
+		order  = make([]uint64, 0)
+
+		rows *sql.Rows
+	)
+
+	for _, c := range m.Config.UniqueConstraints {
+		names = append(names, c.Fields...)
+	}
+
+	rows, err = s.Query(ctx, s.composeRecordValuesSelectBuilder().
+		Join(s.composeRecordTable("crd")+" ON crv.record_id = crd.id").
+		Where(squirrel.Eq{
+			"crv.name":       names,
+			"crv.place":      0,
+			"crv.deleted_at": nil,
+			"crd.module_id":  m.ID,
+			"crd.deleted_at": nil,
+		}))
+
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v *types.RecordValue
+		if v, err = s.internalComposeRecordValueRowScanner(m, rows); err != nil {
+			return
+		}
+
+		if _, has := values[v.RecordID]; !has {
+			order = append(order, v.RecordID)
+		}
+
+		values[v.RecordID] = append(values[v.RecordID], v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, recordID := range order {
+		for _, c := range m.Config.UniqueConstraints {
+			key, ok := c.Key(values[recordID])
+			if !ok {
+				continue
+			}
+
+			err = s.Exec(ctx, s.InsertBuilder(s.composeRecordUniqueValueTable()).SetMap(store.Payload{
+				"rel_module": m.ID,
+				"name":       c.Name,
+				"hash":       key,
+				"rel_record": recordID,
+			}))
+
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
@@ -334,6 +334,11 @@ func (s Store) CreateComposeRecord(ctx context.Context, m *types.Module, rr ...*
 		if err != nil {
 			return
 		}
+
+		err = s.updateComposeRecordUniqueValues(ctx, m, res)
+		if err != nil {
+			return
+		}
 	}
 
 	return
@@ -371,6 +376,14 @@ func (s Store) UpdateComposeRecord(ctx context.Context, m *types.Module, rr ...*
 			res.Values.SetRecordID(res.ID)
 
 			err = s.createComposeRecordValue(ctx, m, res.Values.GetClean()...)
+			if err != nil {
+				return
+			}
+		}
+
+		err = s.updateComposeRecordUniqueValues(ctx, m, res)
+		if err != nil {
+			return
 		}
 	}
 
@@ -430,6 +443,11 @@ func (s Store) DeleteComposeRecordByID(ctx context.Context, m *types.Module, ID
 		return
 	}
 
+	err = s.Exec(ctx, s.DeleteBuilder(s.composeRecordUniqueValueTable()).Where(squirrel.Eq{"rel_record": ID}))
+	if err != nil {
+		return
+	}
+
 	return
 }
 
@@ -445,9 +463,19 @@ func (s Store) TruncateComposeRecords(ctx context.Context, m *types.Module) (err
 		return
 	}
 
+	err = s.Truncate(ctx, s.composeRecordUniqueValueTable())
+	if err != nil {
+		return
+	}
+
 	return
 }
 
+// CountComposeRecords counts valid (not deleted) records across all modules
+func (s Store) CountComposeRecords(ctx context.Context) (uint, error) {
+	return Count(ctx, s.db, s.composeRecordsSelectBuilder().Where(squirrel.Eq{"crd.deleted_at": nil}))
+}
+
 func (s Store) ComposeRecordReport(ctx context.Context, m *types.Module, metrics, dimensions, filter string) ([]map[string]interface{}, error) {
 	return ComposeRecordReportBuilder(&s, m, metrics, dimensions, filter).Run(ctx)
 }
@@ -0,0 +1,627 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/federation_conflicts.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchFederationConflicts returns all matching rows
+//
+// This function calls convertFederationConflictFilter with the given
+// types.ConflictFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchFederationConflicts(ctx context.Context, f types.ConflictFilter) (types.ConflictSet, types.ConflictFilter, error) {
+	var (
+		err error
+		set []*types.Conflict
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertFederationConflictFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("id") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "id",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableFederationConflictColumns(), s.Config().SqlSortHandler); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfFederationConflicts(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			f.Check,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfFederationConflicts collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfFederationConflicts(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.Conflict) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.Conflict, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.Conflict
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.Conflict, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryFederationConflicts(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectFederationConflictCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectFederationConflictCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectFederationConflictCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryFederationConflicts queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryFederationConflicts(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.Conflict) (bool, error),
+) ([]*types.Conflict, error) {
+	var (
+		tmp = make([]*types.Conflict, 0, DefaultSliceCapacity)
+		set = make([]*types.Conflict, 0, DefaultSliceCapacity)
+		res *types.Conflict
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalFederationConflictRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		// check fn set, call it and see if it passed the test
+		// if not, skip the item
+		if check != nil {
+			if chk, err := check(res); err != nil {
+				return nil, err
+			} else if !chk {
+				continue
+			}
+		}
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupFederationConflictByID searches for federation conflict by ID
+//
+// It returns federation conflict
+func (s Store) LookupFederationConflictByID(ctx context.Context, id uint64) (*types.Conflict, error) {
+	return s.execLookupFederationConflict(ctx, squirrel.Eq{
+		s.preprocessColumn("fdcf.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateFederationConflict creates one or more rows in federation_conflicts table
+func (s Store) CreateFederationConflict(ctx context.Context, rr ...*types.Conflict) (err error) {
+	for _, res := range rr {
+		err = s.checkFederationConflictConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateFederationConflicts(ctx, s.internalFederationConflictEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateFederationConflict updates one or more existing rows in federation_conflicts
+func (s Store) UpdateFederationConflict(ctx context.Context, rr ...*types.Conflict) error {
+	return s.partialFederationConflictUpdate(ctx, nil, rr...)
+}
+
+// partialFederationConflictUpdate updates one or more existing rows in federation_conflicts
+func (s Store) partialFederationConflictUpdate(ctx context.Context, onlyColumns []string, rr ...*types.Conflict) (err error) {
+	for _, res := range rr {
+		err = s.checkFederationConflictConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateFederationConflicts(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("fdcf.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalFederationConflictEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertFederationConflict updates one or more existing rows in federation_conflicts
+func (s Store) UpsertFederationConflict(ctx context.Context, rr ...*types.Conflict) (err error) {
+	for _, res := range rr {
+		err = s.checkFederationConflictConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertFederationConflicts(ctx, s.internalFederationConflictEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteFederationConflict Deletes one or more rows from federation_conflicts table
+func (s Store) DeleteFederationConflict(ctx context.Context, rr ...*types.Conflict) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteFederationConflicts(ctx, squirrel.Eq{
+			s.preprocessColumn("fdcf.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteFederationConflictByID Deletes row from the federation_conflicts table
+func (s Store) DeleteFederationConflictByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteFederationConflicts(ctx, squirrel.Eq{
+		s.preprocessColumn("fdcf.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateFederationConflicts Deletes all rows from the federation_conflicts table
+func (s Store) TruncateFederationConflicts(ctx context.Context) error {
+	return s.Truncate(ctx, s.federationConflictTable())
+}
+
+// execLookupFederationConflict prepares FederationConflict query and executes it,
+// returning types.Conflict (or error)
+func (s Store) execLookupFederationConflict(ctx context.Context, cnd squirrel.Sqlizer) (res *types.Conflict, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.federationConflictsSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalFederationConflictRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateFederationConflicts updates all matched (by cnd) rows in federation_conflicts with given data
+func (s Store) execCreateFederationConflicts(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.federationConflictTable()).SetMap(payload))
+}
+
+// execUpdateFederationConflicts updates all matched (by cnd) rows in federation_conflicts with given data
+func (s Store) execUpdateFederationConflicts(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.federationConflictTable("fdcf")).Where(cnd).SetMap(set))
+}
+
+// execUpsertFederationConflicts inserts new or updates matching (by-primary-key) rows in federation_conflicts with given data
+func (s Store) execUpsertFederationConflicts(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.federationConflictTable(),
+		set,
+		s.preprocessColumn("id", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteFederationConflicts Deletes all matched (by cnd) rows in federation_conflicts with given data
+func (s Store) execDeleteFederationConflicts(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.federationConflictTable("fdcf")).Where(cnd))
+}
+
+func (s Store) internalFederationConflictRowScanner(row rowScanner) (res *types.Conflict, err error) {
+	res = &types.Conflict{}
+
+	if _, has := s.config.RowScanners["federationConflict"]; has {
+		scanner := s.config.RowScanners["federationConflict"].(func(_ rowScanner, _ *types.Conflict) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.NodeID,
+			&res.ModuleID,
+			&res.ComposeNamespaceID,
+			&res.ComposeModuleID,
+			&res.ComposeRecordID,
+			&res.Policy,
+			&res.Status,
+			&res.Resolution,
+			&res.LocalValues,
+			&res.LocalUpdatedAt,
+			&res.RemoteValues,
+			&res.RemoteDeleted,
+			&res.RemoteUpdatedAt,
+			&res.CreatedAt,
+			&res.ResolvedAt,
+			&res.ResolvedBy,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan federationConflict db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryFederationConflicts returns squirrel.SelectBuilder with set table and all columns
+func (s Store) federationConflictsSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.federationConflictTable("fdcf"), s.federationConflictColumns("fdcf")...)
+}
+
+// federationConflictTable name of the db table
+func (Store) federationConflictTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "federation_conflicts" + alias
+}
+
+// FederationConflictColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) federationConflictColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "rel_node",
+		alias + "rel_module",
+		alias + "rel_compose_namespace",
+		alias + "rel_compose_module",
+		alias + "rel_compose_record",
+		alias + "policy",
+		alias + "status",
+		alias + "resolution",
+		alias + "local_values",
+		alias + "local_updated_at",
+		alias + "remote_values",
+		alias + "remote_deleted",
+		alias + "remote_updated_at",
+		alias + "created_at",
+		alias + "resolved_at",
+		alias + "resolved_by",
+	}
+}
+
+// {true true false true true true}
+
+// sortableFederationConflictColumns returns all FederationConflict columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableFederationConflictColumns() map[string]string {
+	return map[string]string{
+		"id": "id", "created_at": "created_at",
+		"createdat":   "created_at",
+		"resolved_at": "resolved_at",
+		"resolvedat":  "resolved_at",
+	}
+}
+
+// internalFederationConflictEncoder encodes fields from types.Conflict to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeFederationConflict
+// func when rdbms.customEncoder=true
+func (s Store) internalFederationConflictEncoder(res *types.Conflict) store.Payload {
+	return store.Payload{
+		"id":                    res.ID,
+		"rel_node":              res.NodeID,
+		"rel_module":            res.ModuleID,
+		"rel_compose_namespace": res.ComposeNamespaceID,
+		"rel_compose_module":    res.ComposeModuleID,
+		"rel_compose_record":    res.ComposeRecordID,
+		"policy":                res.Policy,
+		"status":                res.Status,
+		"resolution":            res.Resolution,
+		"local_values":          res.LocalValues,
+		"local_updated_at":      res.LocalUpdatedAt,
+		"remote_values":         res.RemoteValues,
+		"remote_deleted":        res.RemoteDeleted,
+		"remote_updated_at":     res.RemoteUpdatedAt,
+		"created_at":            res.CreatedAt,
+		"resolved_at":           res.ResolvedAt,
+		"resolved_by":           res.ResolvedBy,
+	}
+}
+
+// collectFederationConflictCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectFederationConflictCursorValues(res *types.Conflict, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkId bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "id":
+					cursor.Set(c.Column, res.ID, c.Descending)
+
+					pkId = true
+				case "created_at":
+					cursor.Set(c.Column, res.CreatedAt, c.Descending)
+
+				case "resolved_at":
+					cursor.Set(c.Column, res.ResolvedAt, c.Descending)
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkId && true) {
+		collect(&filter.SortExpr{Column: "id", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkFederationConflictConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkFederationConflictConstraints(ctx context.Context, res *types.Conflict) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
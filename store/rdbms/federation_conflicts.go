@@ -0,0 +1,28 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/federation/types"
+)
+
+func (s Store) convertFederationConflictFilter(f types.ConflictFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.federationConflictsSelectBuilder()
+
+	if f.NodeID > 0 {
+		query = query.Where("fdcf.rel_node = ?", f.NodeID)
+	}
+
+	if f.ModuleID > 0 {
+		query = query.Where("fdcf.rel_module = ?", f.ModuleID)
+	}
+
+	if f.ComposeRecordID > 0 {
+		query = query.Where("fdcf.rel_compose_record = ?", f.ComposeRecordID)
+	}
+
+	if f.Status != "" {
+		query = query.Where("fdcf.status = ?", f.Status)
+	}
+
+	return
+}
@@ -444,6 +444,8 @@ func (s Store) internalFederationExposedModuleRowScanner(row rowScanner) (res *t
 			&res.ComposeModuleID,
 			&res.ComposeNamespaceID,
 			&res.Fields,
+			&res.Bidirectional,
+			&res.ConflictPolicy,
 			&res.CreatedBy,
 			&res.UpdatedBy,
 			&res.DeletedBy,
@@ -496,6 +498,8 @@ func (Store) federationExposedModuleColumns(aa ...string) []string {
 		alias + "rel_compose_module",
 		alias + "rel_compose_namespace",
 		alias + "fields",
+		alias + "bidirectional",
+		alias + "conflict_policy",
 		alias + "created_by",
 		alias + "updated_by",
 		alias + "deleted_by",
@@ -529,6 +533,8 @@ func (s Store) internalFederationExposedModuleEncoder(res *types.ExposedModule)
 		"rel_compose_module":    res.ComposeModuleID,
 		"rel_compose_namespace": res.ComposeNamespaceID,
 		"fields":                res.Fields,
+		"bidirectional":         res.Bidirectional,
+		"conflict_policy":       res.ConflictPolicy,
 		"created_by":            res.CreatedBy,
 		"updated_by":            res.UpdatedBy,
 		"deleted_by":            res.DeletedBy,
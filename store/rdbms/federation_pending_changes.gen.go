@@ -0,0 +1,595 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/federation_pending_changes.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchFederationPendingChanges returns all matching rows
+//
+// This function calls convertFederationPendingChangeFilter with the given
+// types.PendingChangeFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchFederationPendingChanges(ctx context.Context, f types.PendingChangeFilter) (types.PendingChangeSet, types.PendingChangeFilter, error) {
+	var (
+		err error
+		set []*types.PendingChange
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertFederationPendingChangeFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("id") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "id",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableFederationPendingChangeColumns(), s.Config().SqlSortHandler); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfFederationPendingChanges(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			f.Check,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfFederationPendingChanges collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfFederationPendingChanges(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.PendingChange) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.PendingChange, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.PendingChange
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.PendingChange, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryFederationPendingChanges(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectFederationPendingChangeCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectFederationPendingChangeCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectFederationPendingChangeCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryFederationPendingChanges queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryFederationPendingChanges(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.PendingChange) (bool, error),
+) ([]*types.PendingChange, error) {
+	var (
+		tmp = make([]*types.PendingChange, 0, DefaultSliceCapacity)
+		set = make([]*types.PendingChange, 0, DefaultSliceCapacity)
+		res *types.PendingChange
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalFederationPendingChangeRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		// check fn set, call it and see if it passed the test
+		// if not, skip the item
+		if check != nil {
+			if chk, err := check(res); err != nil {
+				return nil, err
+			} else if !chk {
+				continue
+			}
+		}
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupFederationPendingChangeByID searches for pending federation change by ID
+//
+// It returns pending federation change
+func (s Store) LookupFederationPendingChangeByID(ctx context.Context, id uint64) (*types.PendingChange, error) {
+	return s.execLookupFederationPendingChange(ctx, squirrel.Eq{
+		s.preprocessColumn("fdpc.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateFederationPendingChange creates one or more rows in federation_pending_changes table
+func (s Store) CreateFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) (err error) {
+	for _, res := range rr {
+		err = s.checkFederationPendingChangeConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateFederationPendingChanges(ctx, s.internalFederationPendingChangeEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateFederationPendingChange updates one or more existing rows in federation_pending_changes
+func (s Store) UpdateFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) error {
+	return s.partialFederationPendingChangeUpdate(ctx, nil, rr...)
+}
+
+// partialFederationPendingChangeUpdate updates one or more existing rows in federation_pending_changes
+func (s Store) partialFederationPendingChangeUpdate(ctx context.Context, onlyColumns []string, rr ...*types.PendingChange) (err error) {
+	for _, res := range rr {
+		err = s.checkFederationPendingChangeConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateFederationPendingChanges(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("fdpc.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalFederationPendingChangeEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertFederationPendingChange updates one or more existing rows in federation_pending_changes
+func (s Store) UpsertFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) (err error) {
+	for _, res := range rr {
+		err = s.checkFederationPendingChangeConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertFederationPendingChanges(ctx, s.internalFederationPendingChangeEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteFederationPendingChange Deletes one or more rows from federation_pending_changes table
+func (s Store) DeleteFederationPendingChange(ctx context.Context, rr ...*types.PendingChange) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteFederationPendingChanges(ctx, squirrel.Eq{
+			s.preprocessColumn("fdpc.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteFederationPendingChangeByID Deletes row from the federation_pending_changes table
+func (s Store) DeleteFederationPendingChangeByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteFederationPendingChanges(ctx, squirrel.Eq{
+		s.preprocessColumn("fdpc.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateFederationPendingChanges Deletes all rows from the federation_pending_changes table
+func (s Store) TruncateFederationPendingChanges(ctx context.Context) error {
+	return s.Truncate(ctx, s.federationPendingChangeTable())
+}
+
+// execLookupFederationPendingChange prepares FederationPendingChange query and executes it,
+// returning types.PendingChange (or error)
+func (s Store) execLookupFederationPendingChange(ctx context.Context, cnd squirrel.Sqlizer) (res *types.PendingChange, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.federationPendingChangesSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalFederationPendingChangeRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateFederationPendingChanges updates all matched (by cnd) rows in federation_pending_changes with given data
+func (s Store) execCreateFederationPendingChanges(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.federationPendingChangeTable()).SetMap(payload))
+}
+
+// execUpdateFederationPendingChanges updates all matched (by cnd) rows in federation_pending_changes with given data
+func (s Store) execUpdateFederationPendingChanges(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.federationPendingChangeTable("fdpc")).Where(cnd).SetMap(set))
+}
+
+// execUpsertFederationPendingChanges inserts new or updates matching (by-primary-key) rows in federation_pending_changes with given data
+func (s Store) execUpsertFederationPendingChanges(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.federationPendingChangeTable(),
+		set,
+		s.preprocessColumn("id", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteFederationPendingChanges Deletes all matched (by cnd) rows in federation_pending_changes with given data
+func (s Store) execDeleteFederationPendingChanges(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.federationPendingChangeTable("fdpc")).Where(cnd))
+}
+
+func (s Store) internalFederationPendingChangeRowScanner(row rowScanner) (res *types.PendingChange, err error) {
+	res = &types.PendingChange{}
+
+	if _, has := s.config.RowScanners["federationPendingChange"]; has {
+		scanner := s.config.RowScanners["federationPendingChange"].(func(_ rowScanner, _ *types.PendingChange) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.ComposeModuleID,
+			&res.ComposeRecordID,
+			&res.Attempts,
+			&res.Error,
+			&res.CreatedAt,
+			&res.LastAttemptAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan federationPendingChange db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryFederationPendingChanges returns squirrel.SelectBuilder with set table and all columns
+func (s Store) federationPendingChangesSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.federationPendingChangeTable("fdpc"), s.federationPendingChangeColumns("fdpc")...)
+}
+
+// federationPendingChangeTable name of the db table
+func (Store) federationPendingChangeTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "federation_pending_changes" + alias
+}
+
+// FederationPendingChangeColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) federationPendingChangeColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "rel_compose_module",
+		alias + "rel_compose_record",
+		alias + "attempts",
+		alias + "error",
+		alias + "created_at",
+		alias + "last_attempt_at",
+	}
+}
+
+// {true true false true true true}
+
+// sortableFederationPendingChangeColumns returns all FederationPendingChange columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableFederationPendingChangeColumns() map[string]string {
+	return map[string]string{
+		"id": "id", "attempts": "attempts", "created_at": "created_at",
+		"createdat": "created_at",
+	}
+}
+
+// internalFederationPendingChangeEncoder encodes fields from types.PendingChange to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeFederationPendingChange
+// func when rdbms.customEncoder=true
+func (s Store) internalFederationPendingChangeEncoder(res *types.PendingChange) store.Payload {
+	return store.Payload{
+		"id":                 res.ID,
+		"rel_compose_module": res.ComposeModuleID,
+		"rel_compose_record": res.ComposeRecordID,
+		"attempts":           res.Attempts,
+		"error":              res.Error,
+		"created_at":         res.CreatedAt,
+		"last_attempt_at":    res.LastAttemptAt,
+	}
+}
+
+// collectFederationPendingChangeCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectFederationPendingChangeCursorValues(res *types.PendingChange, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkId bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "id":
+					cursor.Set(c.Column, res.ID, c.Descending)
+
+					pkId = true
+				case "attempts":
+					cursor.Set(c.Column, res.Attempts, c.Descending)
+
+				case "created_at":
+					cursor.Set(c.Column, res.CreatedAt, c.Descending)
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkId && true) {
+		collect(&filter.SortExpr{Column: "id", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkFederationPendingChangeConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkFederationPendingChangeConstraints(ctx context.Context, res *types.PendingChange) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
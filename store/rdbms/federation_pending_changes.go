@@ -0,0 +1,24 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/federation/types"
+)
+
+func (s Store) convertFederationPendingChangeFilter(f types.PendingChangeFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.federationPendingChangesSelectBuilder()
+
+	if f.ComposeModuleID > 0 {
+		query = query.Where("fdpc.rel_compose_module = ?", f.ComposeModuleID)
+	}
+
+	if f.ComposeRecordID > 0 {
+		query = query.Where("fdpc.rel_compose_record = ?", f.ComposeRecordID)
+	}
+
+	if f.MaxAttempts > 0 {
+		query = query.Where("fdpc.attempts < ?", f.MaxAttempts)
+	}
+
+	return
+}
@@ -443,6 +443,7 @@ func (s Store) internalFederationSharedModuleRowScanner(row rowScanner) (res *ty
 			&res.Name,
 			&res.ExternalFederationModuleID,
 			&res.Fields,
+			&res.Bidirectional,
 			&res.CreatedBy,
 			&res.UpdatedBy,
 			&res.DeletedBy,
@@ -494,6 +495,7 @@ func (Store) federationSharedModuleColumns(aa ...string) []string {
 		alias + "name",
 		alias + "xref_module",
 		alias + "fields",
+		alias + "bidirectional",
 		alias + "created_by",
 		alias + "updated_by",
 		alias + "deleted_by",
@@ -520,18 +522,19 @@ func (Store) sortableFederationSharedModuleColumns() map[string]string {
 // func when rdbms.customEncoder=true
 func (s Store) internalFederationSharedModuleEncoder(res *types.SharedModule) store.Payload {
 	return store.Payload{
-		"id":          res.ID,
-		"rel_node":    res.NodeID,
-		"handle":      res.Handle,
-		"name":        res.Name,
-		"xref_module": res.ExternalFederationModuleID,
-		"fields":      res.Fields,
-		"created_by":  res.CreatedBy,
-		"updated_by":  res.UpdatedBy,
-		"deleted_by":  res.DeletedBy,
-		"created_at":  res.CreatedAt,
-		"updated_at":  res.UpdatedAt,
-		"deleted_at":  res.DeletedAt,
+		"id":            res.ID,
+		"rel_node":      res.NodeID,
+		"handle":        res.Handle,
+		"name":          res.Name,
+		"xref_module":   res.ExternalFederationModuleID,
+		"fields":        res.Fields,
+		"bidirectional": res.Bidirectional,
+		"created_by":    res.CreatedBy,
+		"updated_by":    res.UpdatedBy,
+		"deleted_by":    res.DeletedBy,
+		"created_at":    res.CreatedAt,
+		"updated_at":    res.UpdatedAt,
+		"deleted_at":    res.DeletedAt,
 	}
 }
 
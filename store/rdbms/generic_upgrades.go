@@ -77,6 +77,7 @@ func (g genericUpgrades) Upgrade(ctx context.Context, t *ddl.Table) error {
 	case "compose_module":
 		return g.all(ctx,
 			g.AlterComposeModuleRenameJsonToMeta,
+			g.AlterComposeModuleAddConfig,
 		)
 	case "compose_module_field":
 		return g.all(ctx,
@@ -85,6 +86,7 @@ func (g genericUpgrades) Upgrade(ctx context.Context, t *ddl.Table) error {
 	case "automation_sessions":
 		return g.all(ctx,
 			g.CreateAutomationSessionIndexes,
+			g.AddAutomationSessionStateField,
 		)
 	//case "compose_attachment_binds":
 	//	return g.all(ctx,
@@ -95,6 +97,17 @@ func (g genericUpgrades) Upgrade(ctx context.Context, t *ddl.Table) error {
 		return g.all(ctx,
 			g.AddScenariosField,
 		)
+
+	case "federation_module_exposed":
+		return g.all(ctx,
+			g.AlterFederationModuleExposedAddBidirectional,
+			g.AlterFederationModuleExposedAddConflictPolicy,
+		)
+
+	case "federation_module_shared":
+		return g.all(ctx,
+			g.AlterFederationModuleSharedAddBidirectional,
+		)
 	}
 
 	return nil
@@ -403,6 +416,20 @@ func (g genericUpgrades) AlterComposeModuleRenameJsonToMeta(ctx context.Context)
 	return err
 }
 
+func (g genericUpgrades) AlterComposeModuleAddConfig(ctx context.Context) (err error) {
+	var (
+		col = &ddl.Column{
+			Name:         "config",
+			Type:         ddl.ColumnType{Type: ddl.ColumnTypeJson},
+			IsNull:       false,
+			DefaultValue: "'{}'",
+		}
+	)
+
+	_, err = g.u.AddColumn(ctx, "compose_module", col)
+	return
+}
+
 func (g genericUpgrades) AlterComposeModuleFieldAddExpresions(ctx context.Context) (err error) {
 	var (
 		col = &ddl.Column{
@@ -438,3 +465,46 @@ func (g genericUpgrades) CreateAutomationSessionIndexes(ctx context.Context) (er
 
 	return
 }
+
+func (g genericUpgrades) AddAutomationSessionStateField(ctx context.Context) error {
+	_, err := g.u.AddColumn(ctx, "automation_sessions", &ddl.Column{
+		Name:   "state",
+		Type:   ddl.ColumnType{Type: ddl.ColumnTypeJson},
+		IsNull: true,
+	})
+
+	return err
+}
+
+func (g genericUpgrades) AlterFederationModuleExposedAddBidirectional(ctx context.Context) error {
+	_, err := g.u.AddColumn(ctx, "federation_module_exposed", &ddl.Column{
+		Name:         "bidirectional",
+		Type:         ddl.ColumnType{Type: ddl.ColumnTypeBoolean},
+		IsNull:       false,
+		DefaultValue: "false",
+	})
+
+	return err
+}
+
+func (g genericUpgrades) AlterFederationModuleExposedAddConflictPolicy(ctx context.Context) error {
+	_, err := g.u.AddColumn(ctx, "federation_module_exposed", &ddl.Column{
+		Name:         "conflict_policy",
+		Type:         ddl.ColumnType{Type: ddl.ColumnTypeVarchar, Length: 32},
+		IsNull:       false,
+		DefaultValue: "''",
+	})
+
+	return err
+}
+
+func (g genericUpgrades) AlterFederationModuleSharedAddBidirectional(ctx context.Context) error {
+	_, err := g.u.AddColumn(ctx, "federation_module_shared", &ddl.Column{
+		Name:         "bidirectional",
+		Type:         ddl.ColumnType{Type: ddl.ColumnTypeBoolean},
+		IsNull:       false,
+		DefaultValue: "false",
+	})
+
+	return err
+}
@@ -0,0 +1,333 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/invalidation_versions.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+var _ = errors.Is
+
+// SearchInvalidationVersions returns all matching rows
+//
+// This function calls convertInvalidationVersionFilter with the given
+// types.VersionFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchInvalidationVersions(ctx context.Context, f types.VersionFilter) (types.VersionSet, types.VersionFilter, error) {
+	var (
+		err error
+		set []*types.Version
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertInvalidationVersionFilter(f)
+		if err != nil {
+			return err
+		}
+
+		set, err = s.QueryInvalidationVersions(ctx, q, nil)
+		return err
+	}()
+}
+
+// QueryInvalidationVersions queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryInvalidationVersions(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.Version) (bool, error),
+) ([]*types.Version, error) {
+	var (
+		tmp = make([]*types.Version, 0, DefaultSliceCapacity)
+		set = make([]*types.Version, 0, DefaultSliceCapacity)
+		res *types.Version
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalInvalidationVersionRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupInvalidationVersionByKind searches for invalidation version by kind
+func (s Store) LookupInvalidationVersionByKind(ctx context.Context, kind string) (*types.Version, error) {
+	return s.execLookupInvalidationVersion(ctx, squirrel.Eq{
+		s.preprocessColumn("invv.kind", ""): store.PreprocessValue(kind, ""),
+	})
+}
+
+// CreateInvalidationVersion creates one or more rows in invalidation_versions table
+func (s Store) CreateInvalidationVersion(ctx context.Context, rr ...*types.Version) (err error) {
+	for _, res := range rr {
+		err = s.checkInvalidationVersionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateInvalidationVersions(ctx, s.internalInvalidationVersionEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateInvalidationVersion updates one or more existing rows in invalidation_versions
+func (s Store) UpdateInvalidationVersion(ctx context.Context, rr ...*types.Version) error {
+	return s.partialInvalidationVersionUpdate(ctx, nil, rr...)
+}
+
+// partialInvalidationVersionUpdate updates one or more existing rows in invalidation_versions
+func (s Store) partialInvalidationVersionUpdate(ctx context.Context, onlyColumns []string, rr ...*types.Version) (err error) {
+	for _, res := range rr {
+		err = s.checkInvalidationVersionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateInvalidationVersions(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("invv.kind", ""): store.PreprocessValue(res.Kind, ""),
+			},
+			s.internalInvalidationVersionEncoder(res).Skip("kind").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertInvalidationVersion updates one or more existing rows in invalidation_versions
+func (s Store) UpsertInvalidationVersion(ctx context.Context, rr ...*types.Version) (err error) {
+	for _, res := range rr {
+		err = s.checkInvalidationVersionConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertInvalidationVersions(ctx, s.internalInvalidationVersionEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteInvalidationVersion Deletes one or more rows from invalidation_versions table
+func (s Store) DeleteInvalidationVersion(ctx context.Context, rr ...*types.Version) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteInvalidationVersions(ctx, squirrel.Eq{
+			s.preprocessColumn("invv.kind", ""): store.PreprocessValue(res.Kind, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteInvalidationVersionByKind Deletes row from the invalidation_versions table
+func (s Store) DeleteInvalidationVersionByKind(ctx context.Context, kind string) error {
+	return s.execDeleteInvalidationVersions(ctx, squirrel.Eq{
+		s.preprocessColumn("invv.kind", ""): store.PreprocessValue(kind, ""),
+	})
+}
+
+// TruncateInvalidationVersions Deletes all rows from the invalidation_versions table
+func (s Store) TruncateInvalidationVersions(ctx context.Context) error {
+	return s.Truncate(ctx, s.invalidationVersionTable())
+}
+
+// execLookupInvalidationVersion prepares InvalidationVersion query and executes it,
+// returning types.Version (or error)
+func (s Store) execLookupInvalidationVersion(ctx context.Context, cnd squirrel.Sqlizer) (res *types.Version, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.invalidationVersionsSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalInvalidationVersionRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateInvalidationVersions updates all matched (by cnd) rows in invalidation_versions with given data
+func (s Store) execCreateInvalidationVersions(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.invalidationVersionTable()).SetMap(payload))
+}
+
+// execUpdateInvalidationVersions updates all matched (by cnd) rows in invalidation_versions with given data
+func (s Store) execUpdateInvalidationVersions(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.invalidationVersionTable("invv")).Where(cnd).SetMap(set))
+}
+
+// execUpsertInvalidationVersions inserts new or updates matching (by-primary-key) rows in invalidation_versions with given data
+func (s Store) execUpsertInvalidationVersions(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.invalidationVersionTable(),
+		set,
+		s.preprocessColumn("kind", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteInvalidationVersions Deletes all matched (by cnd) rows in invalidation_versions with given data
+func (s Store) execDeleteInvalidationVersions(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.invalidationVersionTable("invv")).Where(cnd))
+}
+
+func (s Store) internalInvalidationVersionRowScanner(row rowScanner) (res *types.Version, err error) {
+	res = &types.Version{}
+
+	if _, has := s.config.RowScanners["invalidationVersion"]; has {
+		scanner := s.config.RowScanners["invalidationVersion"].(func(_ rowScanner, _ *types.Version) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.Kind,
+			&res.Version,
+			&res.Node,
+			&res.UpdatedAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan invalidationVersion db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryInvalidationVersions returns squirrel.SelectBuilder with set table and all columns
+func (s Store) invalidationVersionsSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.invalidationVersionTable("invv"), s.invalidationVersionColumns("invv")...)
+}
+
+// invalidationVersionTable name of the db table
+func (Store) invalidationVersionTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "invalidation_versions" + alias
+}
+
+// InvalidationVersionColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) invalidationVersionColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "kind",
+		alias + "version",
+		alias + "node",
+		alias + "updated_at",
+	}
+}
+
+// {true true false false false false}
+
+// internalInvalidationVersionEncoder encodes fields from types.Version to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeInvalidationVersion
+// func when rdbms.customEncoder=true
+func (s Store) internalInvalidationVersionEncoder(res *types.Version) store.Payload {
+	return store.Payload{
+		"kind":       res.Kind,
+		"version":    res.Version,
+		"node":       res.Node,
+		"updated_at": res.UpdatedAt,
+	}
+}
+
+// checkInvalidationVersionConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkInvalidationVersionConstraints(ctx context.Context, res *types.Version) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation/types"
+)
+
+func (s Store) convertInvalidationVersionFilter(f types.VersionFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.invalidationVersionsSelectBuilder()
+
+	if len(f.Kind) > 0 {
+		query = query.Where(squirrel.Eq{"invv.kind": f.Kind})
+	}
+
+	return
+}
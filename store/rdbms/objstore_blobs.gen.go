@@ -0,0 +1,583 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/objstore_blobs.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchObjstoreBlobs returns all matching rows
+//
+// This function calls convertObjstoreBlobFilter with the given
+// types.BlobFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchObjstoreBlobs(ctx context.Context, f types.BlobFilter) (types.BlobSet, types.BlobFilter, error) {
+	var (
+		err error
+		set []*types.Blob
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertObjstoreBlobFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("namespace") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "namespace",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+		if f.Sort.Get("hash") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "hash",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableObjstoreBlobColumns(), s.Config().SqlSortHandler); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfObjstoreBlobs(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			nil,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfObjstoreBlobs collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfObjstoreBlobs(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.Blob) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.Blob, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.Blob
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.Blob, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryObjstoreBlobs(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectObjstoreBlobCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectObjstoreBlobCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectObjstoreBlobCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryObjstoreBlobs queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryObjstoreBlobs(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.Blob) (bool, error),
+) ([]*types.Blob, error) {
+	var (
+		tmp = make([]*types.Blob, 0, DefaultSliceCapacity)
+		set = make([]*types.Blob, 0, DefaultSliceCapacity)
+		res *types.Blob
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalObjstoreBlobRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupObjstoreBlobByNamespaceHash searches for content-addressed blob by namespace and hash
+func (s Store) LookupObjstoreBlobByNamespaceHash(ctx context.Context, namespace string, hash string) (*types.Blob, error) {
+	return s.execLookupObjstoreBlob(ctx, squirrel.Eq{
+		s.preprocessColumn("osb.namespace", ""): store.PreprocessValue(namespace, ""),
+		s.preprocessColumn("osb.hash", ""):      store.PreprocessValue(hash, ""),
+	})
+}
+
+// CreateObjstoreBlob creates one or more rows in objstore_blobs table
+func (s Store) CreateObjstoreBlob(ctx context.Context, rr ...*types.Blob) (err error) {
+	for _, res := range rr {
+		err = s.checkObjstoreBlobConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateObjstoreBlobs(ctx, s.internalObjstoreBlobEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateObjstoreBlob updates one or more existing rows in objstore_blobs
+func (s Store) UpdateObjstoreBlob(ctx context.Context, rr ...*types.Blob) error {
+	return s.partialObjstoreBlobUpdate(ctx, nil, rr...)
+}
+
+// partialObjstoreBlobUpdate updates one or more existing rows in objstore_blobs
+func (s Store) partialObjstoreBlobUpdate(ctx context.Context, onlyColumns []string, rr ...*types.Blob) (err error) {
+	for _, res := range rr {
+		err = s.checkObjstoreBlobConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateObjstoreBlobs(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("osb.namespace", ""): store.PreprocessValue(res.Namespace, ""), s.preprocessColumn("osb.hash", ""): store.PreprocessValue(res.Hash, ""),
+			},
+			s.internalObjstoreBlobEncoder(res).Skip("namespace", "hash").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertObjstoreBlob updates one or more existing rows in objstore_blobs
+func (s Store) UpsertObjstoreBlob(ctx context.Context, rr ...*types.Blob) (err error) {
+	for _, res := range rr {
+		err = s.checkObjstoreBlobConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertObjstoreBlobs(ctx, s.internalObjstoreBlobEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteObjstoreBlob Deletes one or more rows from objstore_blobs table
+func (s Store) DeleteObjstoreBlob(ctx context.Context, rr ...*types.Blob) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteObjstoreBlobs(ctx, squirrel.Eq{
+			s.preprocessColumn("osb.namespace", ""): store.PreprocessValue(res.Namespace, ""), s.preprocessColumn("osb.hash", ""): store.PreprocessValue(res.Hash, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteObjstoreBlobByNamespaceHash Deletes row from the objstore_blobs table
+func (s Store) DeleteObjstoreBlobByNamespaceHash(ctx context.Context, namespace string, hash string) error {
+	return s.execDeleteObjstoreBlobs(ctx, squirrel.Eq{
+		s.preprocessColumn("osb.namespace", ""): store.PreprocessValue(namespace, ""),
+		s.preprocessColumn("osb.hash", ""):      store.PreprocessValue(hash, ""),
+	})
+}
+
+// TruncateObjstoreBlobs Deletes all rows from the objstore_blobs table
+func (s Store) TruncateObjstoreBlobs(ctx context.Context) error {
+	return s.Truncate(ctx, s.objstoreBlobTable())
+}
+
+// execLookupObjstoreBlob prepares ObjstoreBlob query and executes it,
+// returning types.Blob (or error)
+func (s Store) execLookupObjstoreBlob(ctx context.Context, cnd squirrel.Sqlizer) (res *types.Blob, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.objstoreBlobsSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalObjstoreBlobRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateObjstoreBlobs updates all matched (by cnd) rows in objstore_blobs with given data
+func (s Store) execCreateObjstoreBlobs(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.objstoreBlobTable()).SetMap(payload))
+}
+
+// execUpdateObjstoreBlobs updates all matched (by cnd) rows in objstore_blobs with given data
+func (s Store) execUpdateObjstoreBlobs(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.objstoreBlobTable("osb")).Where(cnd).SetMap(set))
+}
+
+// execUpsertObjstoreBlobs inserts new or updates matching (by-primary-key) rows in objstore_blobs with given data
+func (s Store) execUpsertObjstoreBlobs(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.objstoreBlobTable(),
+		set,
+		s.preprocessColumn("namespace", ""),
+		s.preprocessColumn("hash", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteObjstoreBlobs Deletes all matched (by cnd) rows in objstore_blobs with given data
+func (s Store) execDeleteObjstoreBlobs(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.objstoreBlobTable("osb")).Where(cnd))
+}
+
+func (s Store) internalObjstoreBlobRowScanner(row rowScanner) (res *types.Blob, err error) {
+	res = &types.Blob{}
+
+	if _, has := s.config.RowScanners["objstoreBlob"]; has {
+		scanner := s.config.RowScanners["objstoreBlob"].(func(_ rowScanner, _ *types.Blob) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.Namespace,
+			&res.Hash,
+			&res.Refs,
+			&res.CreatedAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan objstoreBlob db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryObjstoreBlobs returns squirrel.SelectBuilder with set table and all columns
+func (s Store) objstoreBlobsSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.objstoreBlobTable("osb"), s.objstoreBlobColumns("osb")...)
+}
+
+// objstoreBlobTable name of the db table
+func (Store) objstoreBlobTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "objstore_blobs" + alias
+}
+
+// ObjstoreBlobColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) objstoreBlobColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "namespace",
+		alias + "hash",
+		alias + "refs",
+		alias + "created_at",
+	}
+}
+
+// {true true false true true false}
+
+// sortableObjstoreBlobColumns returns all ObjstoreBlob columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableObjstoreBlobColumns() map[string]string {
+	return map[string]string{
+		"namespace": "namespace", "hash": "hash",
+	}
+}
+
+// internalObjstoreBlobEncoder encodes fields from types.Blob to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeObjstoreBlob
+// func when rdbms.customEncoder=true
+func (s Store) internalObjstoreBlobEncoder(res *types.Blob) store.Payload {
+	return store.Payload{
+		"namespace":  res.Namespace,
+		"hash":       res.Hash,
+		"refs":       res.Refs,
+		"created_at": res.CreatedAt,
+	}
+}
+
+// collectObjstoreBlobCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectObjstoreBlobCursorValues(res *types.Blob, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkNamespace bool
+
+		pkHash bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "namespace":
+					cursor.Set(c.Column, res.Namespace, c.Descending)
+
+					pkNamespace = true
+				case "hash":
+					cursor.Set(c.Column, res.Hash, c.Descending)
+
+					pkHash = true
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkNamespace && pkHash && true) {
+		collect(&filter.SortExpr{Column: "namespace", Descending: false}, &filter.SortExpr{Column: "hash", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkObjstoreBlobConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkObjstoreBlobConstraints(ctx context.Context, res *types.Blob) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
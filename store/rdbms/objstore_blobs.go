@@ -0,0 +1,91 @@
+package rdbms
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+)
+
+func (s Store) convertObjstoreBlobFilter(f types.BlobFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.objstoreBlobsSelectBuilder()
+
+	if f.Namespace != "" {
+		query = query.Where(squirrel.Eq{"osb.namespace": f.Namespace})
+	}
+
+	return
+}
+
+// AcquireObjstoreBlob adds a reference to the blob
+//
+// Existing blob is referenced with a single conditional update; when there is
+// none, blob is created with one reference. Inserting a blob that was created
+// in the meantime (by another node) fails on the primary key.
+//
+// Returns true when blob was created and its content needs to be stored
+func (s Store) AcquireObjstoreBlob(ctx context.Context, b *types.Blob) (bool, error) {
+	query, args, err := s.UpdateBuilder(s.objstoreBlobTable()).
+		Set("refs", squirrel.Expr("refs + 1")).
+		Where(squirrel.Eq{"namespace": b.Namespace, "hash": b.Hash}).
+		Where(squirrel.Gt{"refs": 0}).
+		ToSql()
+
+	if err != nil {
+		return false, err
+	}
+
+	res, err := s.DB().ExecContext(ctx, query, args...)
+	if err = store.HandleError(err, s.config.ErrorHandler); err != nil {
+		return false, err
+	}
+
+	if n, err := res.RowsAffected(); err != nil {
+		return false, err
+	} else if n > 0 {
+		return false, nil
+	}
+
+	b.Refs = 1
+	if err = s.execCreateObjstoreBlobs(ctx, s.internalObjstoreBlobEncoder(b)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseObjstoreBlob removes a reference from the blob
+//
+// Blob without references is removed.
+//
+// Returns true when blob was removed and its content needs to be removed
+func (s Store) ReleaseObjstoreBlob(ctx context.Context, namespace string, hash string) (bool, error) {
+	cnd := squirrel.Eq{"namespace": namespace, "hash": hash}
+
+	query, args, err := s.UpdateBuilder(s.objstoreBlobTable()).
+		Set("refs", squirrel.Expr("refs - 1")).
+		Where(cnd).
+		Where(squirrel.Gt{"refs": 0}).
+		ToSql()
+
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = s.DB().ExecContext(ctx, query, args...); err != nil {
+		return false, store.HandleError(err, s.config.ErrorHandler)
+	}
+
+	if query, args, err = s.DeleteBuilder(s.objstoreBlobTable()).Where(cnd).Where(squirrel.LtOrEq{"refs": 0}).ToSql(); err != nil {
+		return false, err
+	}
+
+	res, err := s.DB().ExecContext(ctx, query, args...)
+	if err = store.HandleError(err, s.config.ErrorHandler); err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
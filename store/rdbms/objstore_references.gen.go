@@ -0,0 +1,583 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/objstore_references.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+)
+
+var _ = errors.Is
+
+// SearchObjstoreReferences returns all matching rows
+//
+// This function calls convertObjstoreReferenceFilter with the given
+// types.ReferenceFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchObjstoreReferences(ctx context.Context, f types.ReferenceFilter) (types.ReferenceSet, types.ReferenceFilter, error) {
+	var (
+		err error
+		set []*types.Reference
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertObjstoreReferenceFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("namespace") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "namespace",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+		if f.Sort.Get("name") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "name",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableObjstoreReferenceColumns(), s.Config().SqlSortHandler); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfObjstoreReferences(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			nil,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfObjstoreReferences collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfObjstoreReferences(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.Reference) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.Reference, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.Reference
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.Reference, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryObjstoreReferences(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectObjstoreReferenceCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectObjstoreReferenceCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectObjstoreReferenceCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryObjstoreReferences queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryObjstoreReferences(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.Reference) (bool, error),
+) ([]*types.Reference, error) {
+	var (
+		tmp = make([]*types.Reference, 0, DefaultSliceCapacity)
+		set = make([]*types.Reference, 0, DefaultSliceCapacity)
+		res *types.Reference
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalObjstoreReferenceRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupObjstoreReferenceByNamespaceName searches for object store reference by namespace and name
+func (s Store) LookupObjstoreReferenceByNamespaceName(ctx context.Context, namespace string, name string) (*types.Reference, error) {
+	return s.execLookupObjstoreReference(ctx, squirrel.Eq{
+		s.preprocessColumn("osr.namespace", ""): store.PreprocessValue(namespace, ""),
+		s.preprocessColumn("osr.name", ""):      store.PreprocessValue(name, ""),
+	})
+}
+
+// CreateObjstoreReference creates one or more rows in objstore_references table
+func (s Store) CreateObjstoreReference(ctx context.Context, rr ...*types.Reference) (err error) {
+	for _, res := range rr {
+		err = s.checkObjstoreReferenceConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateObjstoreReferences(ctx, s.internalObjstoreReferenceEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateObjstoreReference updates one or more existing rows in objstore_references
+func (s Store) UpdateObjstoreReference(ctx context.Context, rr ...*types.Reference) error {
+	return s.partialObjstoreReferenceUpdate(ctx, nil, rr...)
+}
+
+// partialObjstoreReferenceUpdate updates one or more existing rows in objstore_references
+func (s Store) partialObjstoreReferenceUpdate(ctx context.Context, onlyColumns []string, rr ...*types.Reference) (err error) {
+	for _, res := range rr {
+		err = s.checkObjstoreReferenceConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateObjstoreReferences(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("osr.namespace", ""): store.PreprocessValue(res.Namespace, ""), s.preprocessColumn("osr.name", ""): store.PreprocessValue(res.Name, ""),
+			},
+			s.internalObjstoreReferenceEncoder(res).Skip("namespace", "name").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpsertObjstoreReference updates one or more existing rows in objstore_references
+func (s Store) UpsertObjstoreReference(ctx context.Context, rr ...*types.Reference) (err error) {
+	for _, res := range rr {
+		err = s.checkObjstoreReferenceConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpsertObjstoreReferences(ctx, s.internalObjstoreReferenceEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteObjstoreReference Deletes one or more rows from objstore_references table
+func (s Store) DeleteObjstoreReference(ctx context.Context, rr ...*types.Reference) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteObjstoreReferences(ctx, squirrel.Eq{
+			s.preprocessColumn("osr.namespace", ""): store.PreprocessValue(res.Namespace, ""), s.preprocessColumn("osr.name", ""): store.PreprocessValue(res.Name, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteObjstoreReferenceByNamespaceName Deletes row from the objstore_references table
+func (s Store) DeleteObjstoreReferenceByNamespaceName(ctx context.Context, namespace string, name string) error {
+	return s.execDeleteObjstoreReferences(ctx, squirrel.Eq{
+		s.preprocessColumn("osr.namespace", ""): store.PreprocessValue(namespace, ""),
+		s.preprocessColumn("osr.name", ""):      store.PreprocessValue(name, ""),
+	})
+}
+
+// TruncateObjstoreReferences Deletes all rows from the objstore_references table
+func (s Store) TruncateObjstoreReferences(ctx context.Context) error {
+	return s.Truncate(ctx, s.objstoreReferenceTable())
+}
+
+// execLookupObjstoreReference prepares ObjstoreReference query and executes it,
+// returning types.Reference (or error)
+func (s Store) execLookupObjstoreReference(ctx context.Context, cnd squirrel.Sqlizer) (res *types.Reference, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.objstoreReferencesSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalObjstoreReferenceRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateObjstoreReferences updates all matched (by cnd) rows in objstore_references with given data
+func (s Store) execCreateObjstoreReferences(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.objstoreReferenceTable()).SetMap(payload))
+}
+
+// execUpdateObjstoreReferences updates all matched (by cnd) rows in objstore_references with given data
+func (s Store) execUpdateObjstoreReferences(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.objstoreReferenceTable("osr")).Where(cnd).SetMap(set))
+}
+
+// execUpsertObjstoreReferences inserts new or updates matching (by-primary-key) rows in objstore_references with given data
+func (s Store) execUpsertObjstoreReferences(ctx context.Context, set store.Payload) error {
+	upsert, err := s.config.UpsertBuilder(
+		s.config,
+		s.objstoreReferenceTable(),
+		set,
+		s.preprocessColumn("namespace", ""),
+		s.preprocessColumn("name", ""),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Exec(ctx, upsert)
+}
+
+// execDeleteObjstoreReferences Deletes all matched (by cnd) rows in objstore_references with given data
+func (s Store) execDeleteObjstoreReferences(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.objstoreReferenceTable("osr")).Where(cnd))
+}
+
+func (s Store) internalObjstoreReferenceRowScanner(row rowScanner) (res *types.Reference, err error) {
+	res = &types.Reference{}
+
+	if _, has := s.config.RowScanners["objstoreReference"]; has {
+		scanner := s.config.RowScanners["objstoreReference"].(func(_ rowScanner, _ *types.Reference) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.Namespace,
+			&res.Name,
+			&res.Hash,
+			&res.CreatedAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan objstoreReference db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryObjstoreReferences returns squirrel.SelectBuilder with set table and all columns
+func (s Store) objstoreReferencesSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.objstoreReferenceTable("osr"), s.objstoreReferenceColumns("osr")...)
+}
+
+// objstoreReferenceTable name of the db table
+func (Store) objstoreReferenceTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "objstore_references" + alias
+}
+
+// ObjstoreReferenceColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) objstoreReferenceColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "namespace",
+		alias + "name",
+		alias + "hash",
+		alias + "created_at",
+	}
+}
+
+// {true true false true true false}
+
+// sortableObjstoreReferenceColumns returns all ObjstoreReference columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableObjstoreReferenceColumns() map[string]string {
+	return map[string]string{
+		"namespace": "namespace", "name": "name",
+	}
+}
+
+// internalObjstoreReferenceEncoder encodes fields from types.Reference to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeObjstoreReference
+// func when rdbms.customEncoder=true
+func (s Store) internalObjstoreReferenceEncoder(res *types.Reference) store.Payload {
+	return store.Payload{
+		"namespace":  res.Namespace,
+		"name":       res.Name,
+		"hash":       res.Hash,
+		"created_at": res.CreatedAt,
+	}
+}
+
+// collectObjstoreReferenceCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectObjstoreReferenceCursorValues(res *types.Reference, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkNamespace bool
+
+		pkName bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "namespace":
+					cursor.Set(c.Column, res.Namespace, c.Descending)
+
+					pkNamespace = true
+				case "name":
+					cursor.Set(c.Column, res.Name, c.Descending)
+
+					pkName = true
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkNamespace && pkName && true) {
+		collect(&filter.SortExpr{Column: "namespace", Descending: false}, &filter.SortExpr{Column: "name", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkObjstoreReferenceConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkObjstoreReferenceConstraints(ctx context.Context, res *types.Reference) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
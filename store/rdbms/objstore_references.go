@@ -0,0 +1,20 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+)
+
+func (s Store) convertObjstoreReferenceFilter(f types.ReferenceFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.objstoreReferencesSelectBuilder()
+
+	if f.Namespace != "" {
+		query = query.Where(squirrel.Eq{"osr.namespace": f.Namespace})
+	}
+
+	if f.Hash != "" {
+		query = query.Where(squirrel.Eq{"osr.hash": f.Hash})
+	}
+
+	return
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/Masterminds/squirrel"
@@ -74,6 +75,11 @@ const (
 	MaxLimit = 1000
 )
 
+var (
+	// savepoint names must be unique within the transaction
+	savepointSeq uint64
+)
+
 func Connect(ctx context.Context, cfg *Config) (s *Store, err error) {
 	return s, func() error {
 		if err = cfg.ParseExtra(); err != nil {
@@ -362,8 +368,10 @@ func tx(ctx context.Context, dbCandidate interface{}, cfg *Config, txOpt *sql.Tx
 		// we can make a transaction, yay
 		db = dbCandidate.(*sqlx.DB)
 	case dbLayer:
-		// Already in a transaction, run the given task and finish
-		return task(ctx, dbCandidate.(dbLayer))
+		// Already in a transaction, run the given task in a savepoint
+		// so that its changes can be rolled back without aborting
+		// the whole transaction
+		return savepoint(ctx, dbCandidate.(dbLayer), task)
 	default:
 		return fmt.Errorf("could not use the db connection for transaction")
 	}
@@ -415,6 +423,29 @@ func tx(ctx context.Context, dbCandidate interface{}, cfg *Config, txOpt *sql.Tx
 	}
 }
 
+// savepoint runs the task within the savepoint of the current transaction
+//
+// Changes done by the task are rolled back when it fails; the error is
+// returned and the caller can continue with the transaction.
+func savepoint(ctx context.Context, tx dbLayer, task func(context.Context, dbLayer) error) (err error) {
+	name := fmt.Sprintf("sp%d", atomic.AddUint64(&savepointSeq, 1))
+
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err = task(ctx, tx); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return fmt.Errorf("failed to rollback to savepoint on error %v: %w", err, rollbackErr)
+		}
+
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
 func BuildCursor(q squirrel.SelectBuilder, cursor *filter.PagingCursor) squirrel.SelectBuilder {
 	if cursor != nil && len(cursor.Keys()) > 0 {
 		return q.Where(builders.CursorCondition(cursor, nil))
@@ -71,6 +71,8 @@ func (s Schema) Tables() []*Table {
 		s.Applications(),
 		s.Reminders(),
 		s.Attachments(),
+		s.ObjstoreReferences(),
+		s.ObjstoreBlobs(),
 		s.ActionLog(),
 		s.RbacRules(),
 		s.Settings(),
@@ -87,11 +89,15 @@ func (s Schema) Tables() []*Table {
 		s.ComposePage(),
 		s.ComposeRecord(),
 		s.ComposeRecordValue(),
+		s.ComposeRecordUniqueValue(),
+		s.ComposeRecordRevisions(),
 		s.FederationModuleShared(),
 		s.FederationModuleExposed(),
 		s.FederationModuleMapping(),
 		s.FederationNodes(),
 		s.FederationNodesSync(),
+		s.FederationConflicts(),
+		s.FederationPendingChanges(),
 		s.AutomationWorkflows(),
 		s.AutomationTriggers(),
 		s.AutomationSessions(),
@@ -100,6 +106,12 @@ func (s Schema) Tables() []*Table {
 		s.MessagebusQueuemessage(),
 		s.ApigwRoute(),
 		s.ApigwFilter(),
+		s.ApigwSecrets(),
+		s.ApigwCacheEntries(),
+		s.Webhooks(),
+		s.WebhookDeliveries(),
+		s.ClusterLeases(),
+		s.InvalidationVersions(),
 	}
 }
 
@@ -277,6 +289,29 @@ func (Schema) Attachments() *Table {
 	)
 }
 
+func (Schema) ObjstoreReferences() *Table {
+	return TableDef("objstore_references",
+		ColumnDef("namespace", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("name", ColumnTypeVarchar, ColumnTypeLength(resourceLength)),
+		ColumnDef("hash", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+
+		AddIndex("unique_namespace_name", IColumn("namespace", "name")),
+		AddIndex("hash", IColumn("namespace", "hash")),
+	)
+}
+
+func (Schema) ObjstoreBlobs() *Table {
+	return TableDef("objstore_blobs",
+		ColumnDef("namespace", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("hash", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("refs", ColumnTypeInteger, DefaultValue("0")),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+
+		PrimaryKey(IColumn("namespace", "hash")),
+	)
+}
+
 func (Schema) ActionLog() *Table {
 	return TableDef("actionlog",
 		ID,
@@ -464,6 +499,7 @@ func (Schema) ComposeModule() *Table {
 		ColumnDef("handle", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
 		ColumnDef("name", ColumnTypeText),
 		ColumnDef("meta", ColumnTypeJson),
+		ColumnDef("config", ColumnTypeJson),
 		CUDTimestamps,
 
 		AddIndex("namespace", IColumn("rel_namespace")),
@@ -558,6 +594,34 @@ func (Schema) ComposeRecordValue() *Table {
 	)
 }
 
+func (Schema) ComposeRecordUniqueValue() *Table {
+	return TableDef("compose_record_unique_value",
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("name", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("hash", ColumnTypeVarchar, ColumnTypeLength(64)),
+		ColumnDef("rel_record", ColumnTypeIdentifier),
+
+		PrimaryKey(IColumn("rel_module", "name", "hash")),
+		AddIndex("record", IColumn("rel_record")),
+	)
+}
+
+func (Schema) ComposeRecordRevisions() *Table {
+	return TableDef("compose_record_revisions",
+		ID,
+		ColumnDef("rel_record", ColumnTypeIdentifier),
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("rel_namespace", ColumnTypeIdentifier),
+		ColumnDef("revision", ColumnTypeInteger),
+		ColumnDef("operation", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("changes", ColumnTypeJson),
+		ColumnDef("rel_user", ColumnTypeIdentifier),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+
+		AddIndex("unique_revision", IColumn("rel_record", "revision")),
+	)
+}
+
 func (Schema) FederationModuleShared() *Table {
 	return TableDef("federation_module_shared",
 		ID,
@@ -566,6 +630,7 @@ func (Schema) FederationModuleShared() *Table {
 		ColumnDef("rel_node", ColumnTypeIdentifier),
 		ColumnDef("xref_module", ColumnTypeIdentifier),
 		ColumnDef("fields", ColumnTypeJson),
+		ColumnDef("bidirectional", ColumnTypeBoolean, DefaultValue("false")),
 		CUDTimestamps,
 		CUDUsers,
 	)
@@ -580,6 +645,8 @@ func (Schema) FederationModuleExposed() *Table {
 		ColumnDef("rel_compose_module", ColumnTypeIdentifier),
 		ColumnDef("rel_compose_namespace", ColumnTypeIdentifier),
 		ColumnDef("fields", ColumnTypeJson),
+		ColumnDef("bidirectional", ColumnTypeBoolean, DefaultValue("false")),
+		ColumnDef("conflict_policy", ColumnTypeVarchar, ColumnTypeLength(32), DefaultValue("''")),
 		CUDTimestamps,
 		CUDUsers,
 
@@ -624,6 +691,45 @@ func (Schema) FederationNodesSync() *Table {
 	)
 }
 
+func (Schema) FederationConflicts() *Table {
+	return TableDef("federation_conflicts",
+		ID,
+		ColumnDef("rel_node", ColumnTypeIdentifier),
+		ColumnDef("rel_module", ColumnTypeIdentifier),
+		ColumnDef("rel_compose_namespace", ColumnTypeIdentifier),
+		ColumnDef("rel_compose_module", ColumnTypeIdentifier),
+		ColumnDef("rel_compose_record", ColumnTypeIdentifier),
+		ColumnDef("policy", ColumnTypeVarchar, ColumnTypeLength(32)),
+		ColumnDef("status", ColumnTypeVarchar, ColumnTypeLength(32)),
+		ColumnDef("resolution", ColumnTypeVarchar, ColumnTypeLength(32)),
+		ColumnDef("local_values", ColumnTypeJson),
+		ColumnDef("local_updated_at", ColumnTypeTimestamp, Null),
+		ColumnDef("remote_values", ColumnTypeJson),
+		ColumnDef("remote_deleted", ColumnTypeBoolean, DefaultValue("false")),
+		ColumnDef("remote_updated_at", ColumnTypeTimestamp, Null),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("resolved_at", ColumnTypeTimestamp, Null),
+		ColumnDef("resolved_by", ColumnTypeIdentifier, DefaultValue("0")),
+
+		AddIndex("node_module", IColumn("rel_node", "rel_module")),
+		AddIndex("status", IColumn("status")),
+	)
+}
+
+func (Schema) FederationPendingChanges() *Table {
+	return TableDef("federation_pending_changes",
+		ID,
+		ColumnDef("rel_compose_module", ColumnTypeIdentifier),
+		ColumnDef("rel_compose_record", ColumnTypeIdentifier),
+		ColumnDef("attempts", ColumnTypeInteger, DefaultValue("0")),
+		ColumnDef("error", ColumnTypeText),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("last_attempt_at", ColumnTypeTimestamp, Null),
+
+		AddIndex("compose_record", IColumn("rel_compose_record")),
+	)
+}
+
 func (Schema) AutomationWorkflows() *Table {
 	return TableDef("automation_workflows",
 		ID,
@@ -661,6 +767,7 @@ func (Schema) AutomationSessions() *Table {
 		ColumnDef("suspended_at", ColumnTypeTimestamp, Null),
 		ColumnDef("completed_at", ColumnTypeTimestamp, Null),
 		ColumnDef("error", ColumnTypeText),
+		ColumnDef("state", ColumnTypeJson, Null),
 
 		AddIndex("workflow", IColumn("rel_workflow")),
 		AddIndex("event_type", IFieldFull(&IField{Field: "event_type", Length: handleLength})),
@@ -740,3 +847,95 @@ func (Schema) ApigwFilter() *Table {
 		CUDUsers,
 	)
 }
+
+func (Schema) ApigwSecrets() *Table {
+	return TableDef("apigw_secrets",
+		ID,
+		ColumnDef("handle", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("meta", ColumnTypeJson),
+		ColumnDef("ciphertext", ColumnTypeBinary),
+		ColumnDef("data_key", ColumnTypeBinary),
+		ColumnDef("key_id", ColumnTypeVarchar, ColumnTypeLength(64)),
+		ColumnDef("rotated_at", ColumnTypeTimestamp, Null),
+		CUDTimestamps,
+		CUDUsers,
+
+		AddIndex("unique_handle", IExpr("LOWER(handle)"), IWhere("LENGTH(handle) > 0 AND deleted_at IS NULL")),
+	)
+}
+
+func (Schema) ApigwCacheEntries() *Table {
+	return TableDef("apigw_cache_entries",
+		ColumnDef("cache_key", ColumnTypeVarchar, ColumnTypeLength(128)),
+		ColumnDef("rel_route", ColumnTypeIdentifier),
+		ColumnDef("status", ColumnTypeInteger),
+		ColumnDef("header", ColumnTypeJson),
+		ColumnDef("body", ColumnTypeBinary),
+		ColumnDef("etag", ColumnTypeVarchar, ColumnTypeLength(128)),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("expires_at", ColumnTypeTimestamp),
+
+		AddIndex("route", IColumn("rel_route")),
+		AddIndex("expires_at", IColumn("expires_at")),
+		PrimaryKey(IColumn("cache_key")),
+	)
+}
+
+func (Schema) Webhooks() *Table {
+	return TableDef("webhooks",
+		ID,
+		ColumnDef("url", ColumnTypeText),
+		ColumnDef("secret", ColumnTypeText),
+		ColumnDef("resource_type", ColumnTypeText, ColumnTypeLength(handleLength)),
+		ColumnDef("event_types", ColumnTypeJson),
+		ColumnDef("constraints", ColumnTypeJson),
+		ColumnDef("enabled", ColumnTypeBoolean),
+		ColumnDef("meta", ColumnTypeJson),
+		ColumnDef("owned_by", ColumnTypeIdentifier),
+		CUDTimestamps,
+		CUDUsers,
+	)
+}
+
+func (Schema) WebhookDeliveries() *Table {
+	return TableDef("webhook_deliveries",
+		ID,
+		ColumnDef("rel_webhook", ColumnTypeIdentifier),
+		ColumnDef("resource_type", ColumnTypeText, ColumnTypeLength(handleLength)),
+		ColumnDef("event_type", ColumnTypeText, ColumnTypeLength(handleLength)),
+		ColumnDef("payload", ColumnTypeBinary),
+		ColumnDef("status", ColumnTypeVarchar, ColumnTypeLength(16)),
+		ColumnDef("attempts", ColumnTypeInteger),
+		ColumnDef("response_status", ColumnTypeInteger),
+		ColumnDef("error", ColumnTypeText),
+		ColumnDef("created_at", ColumnTypeTimestamp),
+		ColumnDef("last_attempt_at", ColumnTypeTimestamp, Null),
+		ColumnDef("next_attempt_at", ColumnTypeTimestamp, Null),
+		ColumnDef("delivered_at", ColumnTypeTimestamp, Null),
+
+		AddIndex("webhook", IColumn("rel_webhook")),
+		AddIndex("pending", IColumn("status", "next_attempt_at")),
+	)
+}
+
+func (Schema) ClusterLeases() *Table {
+	return TableDef("cluster_leases",
+		ColumnDef("name", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("holder", ColumnTypeVarchar, ColumnTypeLength(resourceLength)),
+		ColumnDef("acquired_at", ColumnTypeTimestamp),
+		ColumnDef("expires_at", ColumnTypeTimestamp),
+
+		PrimaryKey(IColumn("name")),
+	)
+}
+
+func (Schema) InvalidationVersions() *Table {
+	return TableDef("invalidation_versions",
+		ColumnDef("kind", ColumnTypeVarchar, ColumnTypeLength(handleLength)),
+		ColumnDef("version", ColumnTypeIdentifier),
+		ColumnDef("node", ColumnTypeVarchar, ColumnTypeLength(resourceLength)),
+		ColumnDef("updated_at", ColumnTypeTimestamp),
+
+		PrimaryKey(IColumn("kind")),
+	)
+}
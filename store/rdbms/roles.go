@@ -2,6 +2,7 @@ package rdbms
 
 import (
 	"context"
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -94,3 +95,11 @@ func (s Store) RoleMetrics(ctx context.Context) (*types.RoleMetrics, error) {
 
 	return rval, nil
 }
+
+func (s Store) CountRoles(ctx context.Context, f types.RoleFilter) (uint, error) {
+	if q, err := s.convertRoleFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count roles: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
@@ -1,6 +1,8 @@
 package rdbms
 
 import (
+	"context"
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -37,3 +39,11 @@ func (s Store) convertTemplateFilter(f types.TemplateFilter) (query squirrel.Sel
 
 	return
 }
+
+func (s Store) CountTemplates(ctx context.Context, f types.TemplateFilter) (uint, error) {
+	if q, err := s.convertTemplateFilter(f); err != nil {
+		return 0, fmt.Errorf("could not count templates: %w", err)
+	} else {
+		return Count(ctx, s.db, q)
+	}
+}
@@ -0,0 +1,588 @@
+package rdbms
+
+// This file is an auto-generated file
+//
+// Template:    pkg/codegen/assets/store_rdbms.gen.go.tpl
+// Definitions: store/webhook_deliveries.yaml
+//
+// Changes to this file may cause incorrect behavior
+// and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"database/sql"
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/rdbms/builders"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+var _ = errors.Is
+
+// SearchWebhookDeliveries returns all matching rows
+//
+// This function calls convertWebhookDeliveryFilter with the given
+// types.WebhookDeliveryFilter and expects to receive a working squirrel.SelectBuilder
+func (s Store) SearchWebhookDeliveries(ctx context.Context, f types.WebhookDeliveryFilter) (types.WebhookDeliverySet, types.WebhookDeliveryFilter, error) {
+	var (
+		err error
+		set []*types.WebhookDelivery
+		q   squirrel.SelectBuilder
+	)
+
+	return set, f, func() error {
+		q, err = s.convertWebhookDeliveryFilter(f)
+		if err != nil {
+			return err
+		}
+
+		// Paging enabled
+		// {search: {enablePaging:true}}
+		// Cleanup unwanted cursor values (only relevant is f.PageCursor, next&prev are reset and returned)
+		f.PrevPage, f.NextPage = nil, nil
+
+		if f.PageCursor != nil {
+			// Page cursor exists so we need to validate it against used sort
+			// To cover the case when paging cursor is set but sorting is empty, we collect the sorting instructions
+			// from the cursor.
+			// This (extracted sorting info) is then returned as part of response
+			if f.Sort, err = f.PageCursor.Sort(f.Sort); err != nil {
+				return err
+			}
+		}
+
+		// Make sure results are always sorted at least by primary keys
+		if f.Sort.Get("id") == nil {
+			f.Sort = append(f.Sort, &filter.SortExpr{
+				Column:     "id",
+				Descending: f.Sort.LastDescending(),
+			})
+		}
+
+		// Cloned sorting instructions for the actual sorting
+		// Original are passed to the fetchFullPageOfUsers fn used for cursor creation so it MUST keep the initial
+		// direction information
+		sort := f.Sort.Clone()
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		if f.PageCursor != nil && f.PageCursor.ROrder {
+			sort.Reverse()
+		}
+
+		// Apply sorting expr from filter to query
+		if q, err = setOrderBy(q, sort, s.sortableWebhookDeliveryColumns(), s.Config().SqlSortHandler); err != nil {
+			return err
+		}
+
+		set, f.PrevPage, f.NextPage, err = s.fetchFullPageOfWebhookDeliveries(
+			ctx,
+			q, f.Sort, f.PageCursor,
+			f.Limit,
+			nil,
+			func(cur *filter.PagingCursor) squirrel.Sqlizer {
+				return builders.CursorCondition(cur, nil)
+			},
+		)
+
+		if err != nil {
+			return err
+		}
+
+		f.PageCursor = nil
+		return nil
+	}()
+}
+
+// fetchFullPageOfWebhookDeliveries collects all requested results.
+//
+// Function applies:
+//  - cursor conditions (where ...)
+//  - limit
+//
+// Main responsibility of this function is to perform additional sequential queries in case when not enough results
+// are collected due to failed check on a specific row (by check fn).
+//
+// Function then moves cursor to the last item fetched
+func (s Store) fetchFullPageOfWebhookDeliveries(
+	ctx context.Context,
+	q squirrel.SelectBuilder,
+	sort filter.SortExprSet,
+	cursor *filter.PagingCursor,
+	reqItems uint,
+	check func(*types.WebhookDelivery) (bool, error),
+	cursorCond func(*filter.PagingCursor) squirrel.Sqlizer,
+) (set []*types.WebhookDelivery, prev, next *filter.PagingCursor, err error) {
+	var (
+		aux []*types.WebhookDelivery
+
+		// When cursor for a previous page is used it's marked as reversed
+		// This tells us to flip the descending flag on all used sort keys
+		reversedOrder = cursor != nil && cursor.ROrder
+
+		// copy of the select builder
+		tryQuery squirrel.SelectBuilder
+
+		// Copy no. of required items to limit
+		// Limit will change when doing subsequent queries to fill
+		// the set with all required items
+		limit = reqItems
+
+		// cursor to prev. page is only calculated when cursor is used
+		hasPrev = cursor != nil
+
+		// next cursor is calculated when there are more pages to come
+		hasNext bool
+	)
+
+	set = make([]*types.WebhookDelivery, 0, DefaultSliceCapacity)
+
+	for try := 0; try < MaxRefetches; try++ {
+		if cursor != nil {
+			tryQuery = q.Where(cursorCond(cursor))
+		} else {
+			tryQuery = q
+		}
+
+		if limit > 0 {
+			// fetching + 1 so we know if there are more items
+			// we can fetch (next-page cursor)
+			tryQuery = tryQuery.Limit(uint64(limit + 1))
+		}
+
+		if aux, err = s.QueryWebhookDeliveries(ctx, tryQuery, check); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if len(aux) == 0 {
+			// nothing fetched
+			break
+		}
+
+		// append fetched items
+		set = append(set, aux...)
+
+		if reqItems == 0 {
+			// no max requested items specified, break out
+			break
+		}
+
+		collected := uint(len(set))
+
+		if reqItems > collected {
+			// not enough items fetched, try again with adjusted limit
+			limit = reqItems - collected
+
+			if limit < MinEnsureFetchLimit {
+				// In case limit is set very low and we've missed records in the first fetch,
+				// make sure next fetch limit is a bit higher
+				limit = MinEnsureFetchLimit
+			}
+
+			// Update cursor so that it points to the last item fetched
+			cursor = s.collectWebhookDeliveryCursorValues(set[collected-1], sort...)
+
+			// Copy reverse flag from sorting
+			cursor.LThen = sort.Reversed()
+			continue
+		}
+
+		if reqItems < collected {
+			set = set[:reqItems]
+			hasNext = true
+		}
+
+		break
+	}
+
+	collected := len(set)
+
+	if collected == 0 {
+		return nil, nil, nil, nil
+	}
+
+	if reversedOrder {
+		// Fetched set needs to be reversed because we've forced a descending order to get the previous page
+		for i, j := 0, collected-1; i < j; i, j = i+1, j-1 {
+			set[i], set[j] = set[j], set[i]
+		}
+
+		// when in reverse-order rules on what cursor to return change
+		hasPrev, hasNext = hasNext, hasPrev
+	}
+
+	if hasPrev {
+		prev = s.collectWebhookDeliveryCursorValues(set[0], sort...)
+		prev.ROrder = true
+		prev.LThen = !sort.Reversed()
+	}
+
+	if hasNext {
+		next = s.collectWebhookDeliveryCursorValues(set[collected-1], sort...)
+		next.LThen = sort.Reversed()
+	}
+
+	return set, prev, next, nil
+}
+
+// QueryWebhookDeliveries queries the database, converts and checks each row and
+// returns collected set
+//
+// Fn also returns total number of fetched items and last fetched item so that the caller can construct cursor
+// for next page of results
+func (s Store) QueryWebhookDeliveries(
+	ctx context.Context,
+	q squirrel.Sqlizer,
+	check func(*types.WebhookDelivery) (bool, error),
+) ([]*types.WebhookDelivery, error) {
+	var (
+		tmp = make([]*types.WebhookDelivery, 0, DefaultSliceCapacity)
+		set = make([]*types.WebhookDelivery, 0, DefaultSliceCapacity)
+		res *types.WebhookDelivery
+
+		// Query rows with
+		rows, err = s.Query(ctx, q)
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		if err = rows.Err(); err == nil {
+			res, err = s.internalWebhookDeliveryRowScanner(rows)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		tmp = append(tmp, res)
+	}
+
+	for _, res = range tmp {
+
+		set = append(set, res)
+	}
+
+	return set, nil
+}
+
+// LookupWebhookDeliveryByID searches for webhook delivery by ID
+func (s Store) LookupWebhookDeliveryByID(ctx context.Context, id uint64) (*types.WebhookDelivery, error) {
+	return s.execLookupWebhookDelivery(ctx, squirrel.Eq{
+		s.preprocessColumn("whkd.id", ""): store.PreprocessValue(id, ""),
+	})
+}
+
+// CreateWebhookDelivery creates one or more rows in webhook_deliveries table
+func (s Store) CreateWebhookDelivery(ctx context.Context, rr ...*types.WebhookDelivery) (err error) {
+	for _, res := range rr {
+		err = s.checkWebhookDeliveryConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execCreateWebhookDeliveries(ctx, s.internalWebhookDeliveryEncoder(res))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// UpdateWebhookDelivery updates one or more existing rows in webhook_deliveries
+func (s Store) UpdateWebhookDelivery(ctx context.Context, rr ...*types.WebhookDelivery) error {
+	return s.partialWebhookDeliveryUpdate(ctx, nil, rr...)
+}
+
+// partialWebhookDeliveryUpdate updates one or more existing rows in webhook_deliveries
+func (s Store) partialWebhookDeliveryUpdate(ctx context.Context, onlyColumns []string, rr ...*types.WebhookDelivery) (err error) {
+	for _, res := range rr {
+		err = s.checkWebhookDeliveryConstraints(ctx, res)
+		if err != nil {
+			return err
+		}
+
+		err = s.execUpdateWebhookDeliveries(
+			ctx,
+			squirrel.Eq{
+				s.preprocessColumn("whkd.id", ""): store.PreprocessValue(res.ID, ""),
+			},
+			s.internalWebhookDeliveryEncoder(res).Skip("id").Only(onlyColumns...))
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+// DeleteWebhookDelivery Deletes one or more rows from webhook_deliveries table
+func (s Store) DeleteWebhookDelivery(ctx context.Context, rr ...*types.WebhookDelivery) (err error) {
+	for _, res := range rr {
+
+		err = s.execDeleteWebhookDeliveries(ctx, squirrel.Eq{
+			s.preprocessColumn("whkd.id", ""): store.PreprocessValue(res.ID, ""),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteWebhookDeliveryByID Deletes row from the webhook_deliveries table
+func (s Store) DeleteWebhookDeliveryByID(ctx context.Context, ID uint64) error {
+	return s.execDeleteWebhookDeliveries(ctx, squirrel.Eq{
+		s.preprocessColumn("whkd.id", ""): store.PreprocessValue(ID, ""),
+	})
+}
+
+// TruncateWebhookDeliveries Deletes all rows from the webhook_deliveries table
+func (s Store) TruncateWebhookDeliveries(ctx context.Context) error {
+	return s.Truncate(ctx, s.webhookDeliveryTable())
+}
+
+// execLookupWebhookDelivery prepares WebhookDelivery query and executes it,
+// returning types.WebhookDelivery (or error)
+func (s Store) execLookupWebhookDelivery(ctx context.Context, cnd squirrel.Sqlizer) (res *types.WebhookDelivery, err error) {
+	var (
+		row rowScanner
+	)
+
+	row, err = s.QueryRow(ctx, s.webhookDeliveriesSelectBuilder().Where(cnd))
+	if err != nil {
+		return
+	}
+
+	res, err = s.internalWebhookDeliveryRowScanner(row)
+	if err != nil {
+		return
+	}
+
+	return res, nil
+}
+
+// execCreateWebhookDeliveries updates all matched (by cnd) rows in webhook_deliveries with given data
+func (s Store) execCreateWebhookDeliveries(ctx context.Context, payload store.Payload) error {
+	return s.Exec(ctx, s.InsertBuilder(s.webhookDeliveryTable()).SetMap(payload))
+}
+
+// execUpdateWebhookDeliveries updates all matched (by cnd) rows in webhook_deliveries with given data
+func (s Store) execUpdateWebhookDeliveries(ctx context.Context, cnd squirrel.Sqlizer, set store.Payload) error {
+	return s.Exec(ctx, s.UpdateBuilder(s.webhookDeliveryTable("whkd")).Where(cnd).SetMap(set))
+}
+
+// execDeleteWebhookDeliveries Deletes all matched (by cnd) rows in webhook_deliveries with given data
+func (s Store) execDeleteWebhookDeliveries(ctx context.Context, cnd squirrel.Sqlizer) error {
+	return s.Exec(ctx, s.DeleteBuilder(s.webhookDeliveryTable("whkd")).Where(cnd))
+}
+
+func (s Store) internalWebhookDeliveryRowScanner(row rowScanner) (res *types.WebhookDelivery, err error) {
+	res = &types.WebhookDelivery{}
+
+	if _, has := s.config.RowScanners["webhookDelivery"]; has {
+		scanner := s.config.RowScanners["webhookDelivery"].(func(_ rowScanner, _ *types.WebhookDelivery) error)
+		err = scanner(row, res)
+	} else {
+		err = row.Scan(
+			&res.ID,
+			&res.WebhookID,
+			&res.ResourceType,
+			&res.EventType,
+			&res.Payload,
+			&res.Status,
+			&res.Attempts,
+			&res.ResponseStatus,
+			&res.Error,
+			&res.CreatedAt,
+			&res.LastAttemptAt,
+			&res.NextAttemptAt,
+			&res.DeliveredAt,
+		)
+	}
+
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound.Stack(1)
+	}
+
+	if err != nil {
+		return nil, errors.Store("could not scan webhookDelivery db row: %s", err).Wrap(err)
+	} else {
+		return res, nil
+	}
+}
+
+// QueryWebhookDeliveries returns squirrel.SelectBuilder with set table and all columns
+func (s Store) webhookDeliveriesSelectBuilder() squirrel.SelectBuilder {
+	return s.SelectBuilder(s.webhookDeliveryTable("whkd"), s.webhookDeliveryColumns("whkd")...)
+}
+
+// webhookDeliveryTable name of the db table
+func (Store) webhookDeliveryTable(aa ...string) string {
+	var alias string
+	if len(aa) > 0 {
+		alias = " AS " + aa[0]
+	}
+
+	return "webhook_deliveries" + alias
+}
+
+// WebhookDeliveryColumns returns all defined table columns
+//
+// With optional string arg, all columns are returned aliased
+func (Store) webhookDeliveryColumns(aa ...string) []string {
+	var alias string
+	if len(aa) > 0 {
+		alias = aa[0] + "."
+	}
+
+	return []string{
+		alias + "id",
+		alias + "rel_webhook",
+		alias + "resource_type",
+		alias + "event_type",
+		alias + "payload",
+		alias + "status",
+		alias + "attempts",
+		alias + "response_status",
+		alias + "error",
+		alias + "created_at",
+		alias + "last_attempt_at",
+		alias + "next_attempt_at",
+		alias + "delivered_at",
+	}
+}
+
+// {true true false true true false}
+
+// sortableWebhookDeliveryColumns returns all WebhookDelivery columns flagged as sortable
+//
+// With optional string arg, all columns are returned aliased
+func (Store) sortableWebhookDeliveryColumns() map[string]string {
+	return map[string]string{
+		"id": "id", "rel_webhook": "rel_webhook",
+		"webhookid": "rel_webhook",
+		"status":    "status", "created_at": "created_at",
+		"createdat":       "created_at",
+		"last_attempt_at": "last_attempt_at",
+		"lastattemptat":   "last_attempt_at",
+		"next_attempt_at": "next_attempt_at",
+		"nextattemptat":   "next_attempt_at",
+		"delivered_at":    "delivered_at",
+		"deliveredat":     "delivered_at",
+	}
+}
+
+// internalWebhookDeliveryEncoder encodes fields from types.WebhookDelivery to store.Payload (map)
+//
+// Encoding is done by using generic approach or by calling encodeWebhookDelivery
+// func when rdbms.customEncoder=true
+func (s Store) internalWebhookDeliveryEncoder(res *types.WebhookDelivery) store.Payload {
+	return store.Payload{
+		"id":              res.ID,
+		"rel_webhook":     res.WebhookID,
+		"resource_type":   res.ResourceType,
+		"event_type":      res.EventType,
+		"payload":         res.Payload,
+		"status":          res.Status,
+		"attempts":        res.Attempts,
+		"response_status": res.ResponseStatus,
+		"error":           res.Error,
+		"created_at":      res.CreatedAt,
+		"last_attempt_at": res.LastAttemptAt,
+		"next_attempt_at": res.NextAttemptAt,
+		"delivered_at":    res.DeliveredAt,
+	}
+}
+
+// collectWebhookDeliveryCursorValues collects values from the given resource that and sets them to the cursor
+// to be used for pagination
+//
+// Values that are collected must come from sortable, unique or primary columns/fields
+// At least one of the collected columns must be flagged as unique, otherwise fn appends primary keys at the end
+//
+// Known issue:
+//   when collecting cursor values for query that sorts by unique column with partial index (ie: unique handle on
+//   undeleted items)
+func (s Store) collectWebhookDeliveryCursorValues(res *types.WebhookDelivery, cc ...*filter.SortExpr) *filter.PagingCursor {
+	var (
+		cursor = &filter.PagingCursor{LThen: filter.SortExprSet(cc).Reversed()}
+
+		hasUnique bool
+
+		// All known primary key columns
+
+		pkId bool
+
+		collect = func(cc ...*filter.SortExpr) {
+			for _, c := range cc {
+				switch c.Column {
+				case "id":
+					cursor.Set(c.Column, res.ID, c.Descending)
+
+					pkId = true
+				case "rel_webhook":
+					cursor.Set(c.Column, res.WebhookID, c.Descending)
+
+				case "status":
+					cursor.Set(c.Column, res.Status, c.Descending)
+
+				case "created_at":
+					cursor.Set(c.Column, res.CreatedAt, c.Descending)
+
+				case "last_attempt_at":
+					cursor.Set(c.Column, res.LastAttemptAt, c.Descending)
+
+				case "next_attempt_at":
+					cursor.Set(c.Column, res.NextAttemptAt, c.Descending)
+
+				case "delivered_at":
+					cursor.Set(c.Column, res.DeliveredAt, c.Descending)
+
+				}
+			}
+		}
+	)
+
+	collect(cc...)
+	if !hasUnique || !(pkId && true) {
+		collect(&filter.SortExpr{Column: "id", Descending: false})
+	}
+
+	return cursor
+}
+
+// checkWebhookDeliveryConstraints performs lookups (on valid) resource to check if any of the values on unique fields
+// already exists in the store
+//
+// Using built-in constraint checking would be more performant but unfortunately we cannot rely
+// on the full support (MySQL does not support conditional indexes)
+func (s *Store) checkWebhookDeliveryConstraints(ctx context.Context, res *types.WebhookDelivery) error {
+	// Consider resource valid when all fields in unique constraint check lookups
+	// have valid (non-empty) value
+	//
+	// Only string and uint64 are supported for now
+	// feel free to add additional types if needed
+	var valid = true
+
+	if !valid {
+		return nil
+	}
+
+	var checks = make([]func() error, 0)
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+func (s Store) convertWebhookDeliveryFilter(f types.WebhookDeliveryFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.webhookDeliveriesSelectBuilder()
+
+	if f.WebhookID > 0 {
+		query = query.Where(squirrel.Eq{"whkd.rel_webhook": f.WebhookID})
+	}
+
+	if f.Status != "" {
+		query = query.Where(squirrel.Eq{"whkd.status": f.Status})
+	}
+
+	if f.NextAttemptBefore != nil {
+		query = query.Where(squirrel.LtOrEq{"whkd.next_attempt_at": f.NextAttemptBefore})
+	}
+
+	return
+}
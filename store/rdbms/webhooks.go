@@ -0,0 +1,23 @@
+package rdbms
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+func (s Store) convertWebhookFilter(f types.WebhookFilter) (query squirrel.SelectBuilder, err error) {
+	query = s.webhooksSelectBuilder()
+	query = filter.StateCondition(query, "whk.deleted_at", f.Deleted)
+	query = filter.StateConditionNegBool(query, "whk.enabled", f.Disabled)
+
+	if len(f.WebhookID) > 0 {
+		query = query.Where(squirrel.Eq{"whk.id": f.WebhookID})
+	}
+
+	if f.ResourceType != "" {
+		query = query.Where(squirrel.Eq{"whk.resource_type": f.ResourceType})
+	}
+
+	return
+}
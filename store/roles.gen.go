@@ -33,6 +33,9 @@ type (
 
 		// Additional custom functions
 
+		// CountRoles (custom function)
+		CountRoles(ctx context.Context, _f types.RoleFilter) (uint, error)
+
 		// RoleMetrics (custom function)
 		RoleMetrics(ctx context.Context) (*types.RoleMetrics, error)
 	}
@@ -97,6 +100,10 @@ func TruncateRoles(ctx context.Context, s Roles) error {
 	return s.TruncateRoles(ctx)
 }
 
+func CountRoles(ctx context.Context, s Roles, _f types.RoleFilter) (uint, error) {
+	return s.CountRoles(ctx, _f)
+}
+
 func RoleMetrics(ctx context.Context, s Roles) (*types.RoleMetrics, error) {
 	return s.RoleMetrics(ctx)
 }
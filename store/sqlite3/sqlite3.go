@@ -121,7 +121,7 @@ func errorHandler(err error) error {
 	if err != nil {
 		if implErr, ok := err.(sqlite3.Error); ok {
 			switch implErr.ExtendedCode {
-			case sqlite3.ErrConstraintUnique:
+			case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
 				return store.ErrNotUnique.Wrap(implErr)
 			}
 		}
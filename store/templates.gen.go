@@ -29,6 +29,11 @@ type (
 		DeleteTemplateByID(ctx context.Context, ID uint64) error
 
 		TruncateTemplates(ctx context.Context) error
+
+		// Additional custom functions
+
+		// CountTemplates (custom function)
+		CountTemplates(ctx context.Context, _f types.TemplateFilter) (uint, error)
 	}
 )
 
@@ -83,3 +88,7 @@ func DeleteTemplateByID(ctx context.Context, s Templates, ID uint64) error {
 func TruncateTemplates(ctx context.Context, s Templates) error {
 	return s.TruncateTemplates(ctx)
 }
+
+func CountTemplates(ctx context.Context, s Templates, _f types.TemplateFilter) (uint, error) {
+	return s.CountTemplates(ctx, _f)
+}
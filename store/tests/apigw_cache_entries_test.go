@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testApigwCacheEntries(t *testing.T, s store.ApigwCacheEntries) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(key string, routeID uint64, ttl time.Duration) *types.ApigwCacheEntry {
+			now := time.Now().Truncate(time.Second)
+
+			return &types.ApigwCacheEntry{
+				Key:       key,
+				RouteID:   routeID,
+				Status:    http.StatusOK,
+				Header:    types.ApigwCacheEntryHeader{"Content-Type": []string{"application/json"}},
+				Body:      []byte(`{"foo":"bar"}`),
+				ETag:      `"etag"`,
+				CreatedAt: now,
+				ExpiresAt: now.Add(ttl),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.ApigwCacheEntry) {
+			req := require.New(t)
+			req.NoError(s.TruncateApigwCacheEntries(ctx))
+			res := makeNew("1:key", 1, time.Minute)
+			req.NoError(s.CreateApigwCacheEntry(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateApigwCacheEntries(ctx))
+		req.NoError(s.CreateApigwCacheEntry(ctx, makeNew("1:key", 1, time.Minute)))
+	})
+
+	t.Run("lookup by key", func(t *testing.T) {
+		req, e := truncAndCreate(t)
+		fetched, err := s.LookupApigwCacheEntryByKey(ctx, e.Key)
+		req.NoError(err)
+		req.Equal(e.RouteID, fetched.RouteID)
+		req.Equal(e.Body, fetched.Body)
+		req.Equal(e.ETag, fetched.ETag)
+		req.Equal("application/json", http.Header(fetched.Header).Get("Content-Type"))
+	})
+
+	t.Run("upsert", func(t *testing.T) {
+		req, e := truncAndCreate(t)
+		e.Body = []byte(`{}`)
+		req.NoError(s.UpsertApigwCacheEntry(ctx, e))
+
+		fetched, err := s.LookupApigwCacheEntryByKey(ctx, e.Key)
+		req.NoError(err)
+		req.Equal([]byte(`{}`), fetched.Body)
+	})
+
+	t.Run("delete by route", func(t *testing.T) {
+		req, e := truncAndCreate(t)
+		req.NoError(s.CreateApigwCacheEntry(ctx, makeNew("2:key", 2, time.Minute)))
+
+		req.NoError(s.DeleteApigwCacheEntriesByRouteID(ctx, e.RouteID))
+
+		_, err := s.LookupApigwCacheEntryByKey(ctx, e.Key)
+		req.EqualError(err, store.ErrNotFound.Error())
+
+		_, err = s.LookupApigwCacheEntryByKey(ctx, "2:key")
+		req.NoError(err)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		req, e := truncAndCreate(t)
+		req.NoError(s.CreateApigwCacheEntry(ctx, makeNew("1:expired", 1, -time.Minute)))
+
+		req.NoError(s.DeleteExpiredApigwCacheEntries(ctx))
+
+		_, err := s.LookupApigwCacheEntryByKey(ctx, "1:expired")
+		req.EqualError(err, store.ErrNotFound.Error())
+
+		_, err = s.LookupApigwCacheEntryByKey(ctx, e.Key)
+		req.NoError(err)
+	})
+
+	t.Run("search by route", func(t *testing.T) {
+		req, _ := truncAndCreate(t)
+		req.NoError(s.CreateApigwCacheEntry(ctx, makeNew("2:key", 2, time.Minute)))
+
+		set, _, err := s.SearchApigwCacheEntries(ctx, types.ApigwCacheEntryFilter{RouteID: 2})
+		req.NoError(err)
+		req.Len(set, 1)
+	})
+}
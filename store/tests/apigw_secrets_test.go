@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testApigwSecrets(t *testing.T, s store.ApigwSecrets) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(handle string) *types.ApigwSecret {
+			return &types.ApigwSecret{
+				ID:         id.Next(),
+				Handle:     handle,
+				Ciphertext: []byte{0x00, 0x01, 0xff},
+				DataKey:    []byte{0xff, 0x01, 0x00},
+				KeyID:      "k1",
+				CreatedAt:  *now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.ApigwSecret) {
+			req := require.New(t)
+			req.NoError(s.TruncateApigwSecrets(ctx))
+			res := makeNew("secret")
+			req.NoError(s.CreateApigwSecret(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateApigwSecret(ctx, makeNew("create")))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req, sec := truncAndCreate(t)
+
+		fetched, err := s.LookupApigwSecretByID(ctx, sec.ID)
+		req.NoError(err)
+		req.Equal(sec.ID, fetched.ID)
+		req.Equal(sec.Handle, fetched.Handle)
+		req.Equal(sec.Ciphertext, fetched.Ciphertext)
+		req.Equal(sec.DataKey, fetched.DataKey)
+		req.Equal(sec.KeyID, fetched.KeyID)
+		req.Nil(fetched.UpdatedAt)
+		req.Nil(fetched.DeletedAt)
+	})
+
+	t.Run("lookup by handle", func(t *testing.T) {
+		req, sec := truncAndCreate(t)
+
+		fetched, err := s.LookupApigwSecretByHandle(ctx, sec.Handle)
+		req.NoError(err)
+		req.Equal(sec.ID, fetched.ID)
+
+		sec.DeletedAt = now()
+		req.NoError(s.UpdateApigwSecret(ctx, sec))
+
+		_, err = s.LookupApigwSecretByHandle(ctx, sec.Handle)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, sec := truncAndCreate(t)
+		sec.KeyID = "k2"
+		sec.DataKey = []byte{0x02}
+		sec.Meta.Description = "updated"
+		req.NoError(s.UpdateApigwSecret(ctx, sec))
+
+		fetched, err := s.LookupApigwSecretByID(ctx, sec.ID)
+		req.NoError(err)
+		req.Equal("k2", fetched.KeyID)
+		req.Equal([]byte{0x02}, fetched.DataKey)
+		req.Equal("updated", fetched.Meta.Description)
+	})
+
+	t.Run("unique handle", func(t *testing.T) {
+		req, sec := truncAndCreate(t)
+		req.Error(s.CreateApigwSecret(ctx, makeNew(sec.Handle)))
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateApigwSecrets(ctx))
+
+		var (
+			basic   = makeNew("basic")
+			oauth2  = makeNew("oauth2")
+			deleted = makeNew("deleted")
+		)
+
+		oauth2.KeyID = "k2"
+		deleted.DeletedAt = now()
+
+		req.NoError(s.CreateApigwSecret(ctx, basic, oauth2, deleted))
+
+		set, _, err := s.SearchApigwSecrets(ctx, types.ApigwSecretFilter{})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchApigwSecrets(ctx, types.ApigwSecretFilter{Deleted: filter.StateInclusive})
+		req.NoError(err)
+		req.Len(set, 3)
+
+		set, _, err = s.SearchApigwSecrets(ctx, types.ApigwSecretFilter{Query: "oau"})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(oauth2.ID, set[0].ID)
+
+		set, _, err = s.SearchApigwSecrets(ctx, types.ApigwSecretFilter{KeyIDNot: "k2"})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(basic.ID, set[0].ID)
+
+		set, _, err = s.SearchApigwSecrets(ctx, types.ApigwSecretFilter{SecretID: []uint64{basic.ID}})
+		req.NoError(err)
+		req.Len(set, 1)
+	})
+}
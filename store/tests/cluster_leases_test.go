@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/cluster/types"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testClusterLeases(t *testing.T, s store.ClusterLeases) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(name, holder string, at time.Time, ttl time.Duration) *types.Lease {
+			return &types.Lease{
+				Name:       name,
+				Holder:     holder,
+				AcquiredAt: at,
+				ExpiresAt:  at.Add(ttl),
+			}
+		}
+	)
+
+	t.Run("acquire", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateClusterLeases(ctx))
+
+		at := time.Now().Truncate(time.Second)
+
+		ok, err := s.AcquireClusterLease(ctx, makeNew("job", "node-1", at, time.Minute))
+		req.NoError(err)
+		req.True(ok)
+
+		// renewal by the same holder
+		ok, err = s.AcquireClusterLease(ctx, makeNew("job", "node-1", at.Add(time.Second), time.Minute))
+		req.NoError(err)
+		req.True(ok)
+
+		// other holders can not acquire valid lease
+		ok, err = s.AcquireClusterLease(ctx, makeNew("job", "node-2", at.Add(time.Second), time.Minute))
+		req.NoError(err)
+		req.False(ok)
+
+		l, err := s.LookupClusterLeaseByName(ctx, "job")
+		req.NoError(err)
+		req.Equal("node-1", l.Holder)
+		req.True(at.Add(time.Second + time.Minute).Equal(l.ExpiresAt))
+	})
+
+	t.Run("take over expired", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateClusterLeases(ctx))
+
+		at := time.Now().Truncate(time.Second)
+
+		ok, err := s.AcquireClusterLease(ctx, makeNew("job", "node-1", at.Add(-time.Minute), time.Second))
+		req.NoError(err)
+		req.True(ok)
+
+		ok, err = s.AcquireClusterLease(ctx, makeNew("job", "node-2", at, time.Minute))
+		req.NoError(err)
+		req.True(ok)
+
+		l, err := s.LookupClusterLeaseByName(ctx, "job")
+		req.NoError(err)
+		req.Equal("node-2", l.Holder)
+		req.True(at.Equal(l.AcquiredAt))
+	})
+
+	t.Run("release", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateClusterLeases(ctx))
+
+		at := time.Now().Truncate(time.Second)
+
+		ok, err := s.AcquireClusterLease(ctx, makeNew("job", "node-1", at, time.Minute))
+		req.NoError(err)
+		req.True(ok)
+
+		// only holder can release the lease
+		req.NoError(s.ReleaseClusterLease(ctx, "job", "node-2"))
+		_, err = s.LookupClusterLeaseByName(ctx, "job")
+		req.NoError(err)
+
+		req.NoError(s.ReleaseClusterLease(ctx, "job", "node-1"))
+		_, err = s.LookupClusterLeaseByName(ctx, "job")
+		req.EqualError(err, store.ErrNotFound.Error())
+
+		ok, err = s.AcquireClusterLease(ctx, makeNew("job", "node-2", at, time.Minute))
+		req.NoError(err)
+		req.True(ok)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateClusterLeases(ctx))
+
+		at := time.Now().Truncate(time.Second)
+
+		req.NoError(s.CreateClusterLease(ctx,
+			makeNew("job-1", "node-1", at, time.Minute),
+			makeNew("job-2", "node-2", at, time.Minute),
+			makeNew("job-3", "node-1", at.Add(-time.Hour), time.Minute),
+		))
+
+		set, _, err := s.SearchClusterLeases(ctx, types.LeaseFilter{Holder: "node-1"})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchClusterLeases(ctx, types.LeaseFilter{Holder: "node-1", Valid: true})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal("job-1", set[0].Name)
+	})
+}
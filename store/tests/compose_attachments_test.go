@@ -81,7 +81,27 @@ func testComposeAttachments(t *testing.T, s store.ComposeAttachments) {
 	})
 
 	t.Run("search", func(t *testing.T) {
-		t.Skip("not implemented")
+		req := require.New(t)
+		req.NoError(s.TruncateComposeAttachments(ctx))
+
+		nsAtt := makeNew("ns")
+		nsAtt.NamespaceID = 1
+		nsAtt.Kind = types.NamespaceAttachment
+
+		pageAtt := makeNew("page")
+		pageAtt.NamespaceID = 2
+		pageAtt.Kind = types.PageAttachment
+
+		req.NoError(s.CreateComposeAttachment(ctx, nsAtt, pageAtt))
+
+		set, _, err := s.SearchComposeAttachments(ctx, types.AttachmentFilter{})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchComposeAttachments(ctx, types.AttachmentFilter{NamespaceID: 2})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(pageAtt.ID, set[0].ID)
 	})
 
 	t.Run("search by *", func(t *testing.T) {
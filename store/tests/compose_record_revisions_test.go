@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testComposeRecordRevisions(t *testing.T, s store.ComposeRecordRevisions) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(recordID uint64, revision uint) *types.RecordRevision {
+			return &types.RecordRevision{
+				ID:        id.Next(),
+				RecordID:  recordID,
+				Revision:  revision,
+				Operation: types.RecordRevisionOperationUpdate,
+				Changes: types.RecordRevisionChangeSet{
+					{Name: "amount", Old: []string{"1"}, New: []string{"2"}},
+				},
+				CreatedAt: *now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.RecordRevision) {
+			req := require.New(t)
+			req.NoError(s.TruncateComposeRecordRevisions(ctx))
+			res := makeNew(id.Next(), 1)
+			req.NoError(s.CreateComposeRecordRevision(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateComposeRecordRevision(ctx, makeNew(id.Next(), 1)))
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		t.Run("by ID", func(t *testing.T) {
+			req, rev := truncAndCreate(t)
+			fetched, err := s.LookupComposeRecordRevisionByID(ctx, rev.ID)
+			req.NoError(err)
+			req.Equal(rev.ID, fetched.ID)
+			req.Equal(rev.Operation, fetched.Operation)
+			req.Len(fetched.Changes, 1)
+			req.Equal([]string{"2"}, fetched.Changes[0].New)
+		})
+
+		t.Run("by record ID and revision", func(t *testing.T) {
+			req, rev := truncAndCreate(t)
+			fetched, err := s.LookupComposeRecordRevisionByRecordIDRevision(ctx, rev.RecordID, rev.Revision)
+			req.NoError(err)
+			req.Equal(rev.ID, fetched.ID)
+		})
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, rev := truncAndCreate(t)
+		req.NoError(s.DeleteComposeRecordRevisionByID(ctx, rev.ID))
+		_, err := s.LookupComposeRecordRevisionByID(ctx, rev.ID)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("search", func(t *testing.T) {
+		var (
+			req      = require.New(t)
+			recordID = id.Next()
+		)
+
+		req.NoError(s.TruncateComposeRecordRevisions(ctx))
+		req.NoError(s.CreateComposeRecordRevision(ctx,
+			makeNew(recordID, 1),
+			makeNew(recordID, 2),
+			makeNew(recordID, 3),
+			makeNew(id.Next(), 1),
+		))
+
+		set, _, err := s.SearchComposeRecordRevisions(ctx, types.RecordRevisionFilter{RecordID: recordID})
+		req.NoError(err)
+		req.Len(set, 3)
+
+		f := types.RecordRevisionFilter{RecordID: recordID}
+		req.NoError(f.Sort.Set("revision DESC"))
+		f.Paging = filter.Paging{Limit: 1}
+		set, _, err = s.SearchComposeRecordRevisions(ctx, f)
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(uint(3), set[0].Revision)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
 	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/pkg/id"
 	"github.com/cortezaproject/corteza-server/store"
@@ -1635,4 +1636,55 @@ func testComposeRecords(t *testing.T, s store.ComposeRecords) {
 		req.Equal("1st,1;2nd,22;3rd,3", stringifyValues(set, "str1", "num1"))
 
 	})
+
+	t.Run("unique constraints", func(t *testing.T) {
+		var (
+			req = require.New(t)
+			rve *types.RecordValueErrorSet
+
+			umod = mod.Clone()
+
+			r1 = makeNew(&types.RecordValue{Name: "str1", Value: "a"}, &types.RecordValue{Name: "str2", Value: "x"})
+			r2 = makeNew(&types.RecordValue{Name: "str1", Value: "a"}, &types.RecordValue{Name: "str2", Value: "y"})
+			r3 = makeNew(&types.RecordValue{Name: "str1", Value: "a"}, &types.RecordValue{Name: "str2", Value: "x"})
+		)
+
+		umod.Config.UniqueConstraints = []*types.ModuleConfigUniqueConstraint{
+			{Name: "str1_str2", Fields: []string{"str1", "str2"}},
+		}
+
+		req.NoError(s.TruncateComposeRecords(ctx, umod))
+		req.NoError(s.CreateComposeRecord(ctx, umod, r1))
+		req.NoError(s.CreateComposeRecord(ctx, umod, r2))
+
+		rve = types.IsRecordValueErrorSet(s.CreateComposeRecord(ctx, umod, r3))
+		req.NotNil(rve)
+		req.True(rve.HasKind("duplicateValue"))
+		req.Equal(r1.ID, rve.Set[0].Meta["recordID"])
+
+		// not running in a transaction, remove what failed create left behind
+		req.NoError(s.DeleteComposeRecordByID(ctx, umod, r3.ID))
+
+		// record can be updated with its own values
+		req.NoError(s.UpdateComposeRecord(ctx, umod, r1))
+
+		// changing values to match other record
+		r2.Values = r2.Values.Replace("str2", "x")
+		req.NotNil(types.IsRecordValueErrorSet(s.UpdateComposeRecord(ctx, umod, r2)))
+		r2.Values = r2.Values.Replace("str2", "y")
+		req.NoError(s.UpdateComposeRecord(ctx, umod, r2))
+
+		// deleted records do not hold the values
+		r1.DeletedAt = &r1.CreatedAt
+		req.NoError(s.UpdateComposeRecord(ctx, umod, r1))
+		req.NoError(s.CreateComposeRecord(ctx, umod, r3))
+
+		// reindex fails when there are duplicates
+		r1.DeletedAt = nil
+		req.NoError(s.UpdateComposeRecord(ctx, mod, r1))
+		req.True(errors.IsDuplicateData(s.ReindexComposeRecordUniqueValues(ctx, umod)))
+
+		req.NoError(s.DeleteComposeRecordByID(ctx, umod, r1.ID))
+		req.NoError(s.ReindexComposeRecordUniqueValues(ctx, umod))
+	})
 }
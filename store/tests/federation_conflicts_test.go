@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testFederationConflicts(t *testing.T, s store.FederationConflicts) {
+	var (
+		ctx = context.Background()
+
+		nodeID   = id.Next()
+		moduleID = id.Next()
+
+		makeNew = func(status string) *types.Conflict {
+			return &types.Conflict{
+				ID:              id.Next(),
+				NodeID:          nodeID,
+				ModuleID:        moduleID,
+				ComposeRecordID: id.Next(),
+				Policy:          types.ConflictPolicyManual,
+				Status:          status,
+				LocalValues:     types.ConflictValueSet{{Name: "name", Value: "local"}},
+				LocalUpdatedAt:  now(),
+				RemoteValues:    types.ConflictValueSet{{Name: "name", Value: "remote"}},
+				RemoteUpdatedAt: now(),
+				CreatedAt:       *now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.Conflict) {
+			req := require.New(t)
+			req.NoError(s.TruncateFederationConflicts(ctx))
+			res := makeNew(types.ConflictStatusPending)
+			req.NoError(s.CreateFederationConflict(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateFederationConflict(ctx, makeNew(types.ConflictStatusPending)))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req, c := truncAndCreate(t)
+
+		fetched, err := s.LookupFederationConflictByID(ctx, c.ID)
+		req.NoError(err)
+		req.Equal(c.ID, fetched.ID)
+		req.Equal(c.ComposeRecordID, fetched.ComposeRecordID)
+		req.Equal(types.ConflictStatusPending, fetched.Status)
+		req.Equal(types.ConflictValueSet{{Name: "name", Value: "local"}}, fetched.LocalValues)
+		req.Equal(types.ConflictValueSet{{Name: "name", Value: "remote"}}, fetched.RemoteValues)
+		req.Nil(fetched.ResolvedAt)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, c := truncAndCreate(t)
+		c.Status = types.ConflictStatusResolved
+		c.Resolution = types.ConflictResolutionRemote
+		c.ResolvedAt = now()
+		c.ResolvedBy = id.Next()
+		req.NoError(s.UpdateFederationConflict(ctx, c))
+
+		fetched, err := s.LookupFederationConflictByID(ctx, c.ID)
+		req.NoError(err)
+		req.Equal(types.ConflictStatusResolved, fetched.Status)
+		req.Equal(types.ConflictResolutionRemote, fetched.Resolution)
+		req.Equal(c.ResolvedBy, fetched.ResolvedBy)
+		req.NotNil(fetched.ResolvedAt)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateFederationConflicts(ctx))
+
+		var (
+			pending  = makeNew(types.ConflictStatusPending)
+			resolved = makeNew(types.ConflictStatusResolved)
+			other    = makeNew(types.ConflictStatusPending)
+		)
+
+		other.NodeID = id.Next()
+
+		req.NoError(s.CreateFederationConflict(ctx, pending, resolved, other))
+
+		set, _, err := s.SearchFederationConflicts(ctx, types.ConflictFilter{NodeID: nodeID})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchFederationConflicts(ctx, types.ConflictFilter{NodeID: nodeID, Status: types.ConflictStatusPending})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(pending.ID, set[0].ID)
+
+		set, _, err = s.SearchFederationConflicts(ctx, types.ConflictFilter{ComposeRecordID: other.ComposeRecordID})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(other.ID, set[0].ID)
+	})
+}
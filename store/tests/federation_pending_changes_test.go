@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/federation/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testFederationPendingChanges(t *testing.T, s store.FederationPendingChanges) {
+	var (
+		ctx = context.Background()
+
+		moduleID = id.Next()
+
+		makeNew = func() *types.PendingChange {
+			return &types.PendingChange{
+				ID:              id.Next(),
+				ComposeModuleID: moduleID,
+				ComposeRecordID: id.Next(),
+				CreatedAt:       *now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.PendingChange) {
+			req := require.New(t)
+			req.NoError(s.TruncateFederationPendingChanges(ctx))
+			res := makeNew()
+			req.NoError(s.CreateFederationPendingChange(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateFederationPendingChange(ctx, makeNew()))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req, c := truncAndCreate(t)
+
+		fetched, err := s.LookupFederationPendingChangeByID(ctx, c.ID)
+		req.NoError(err)
+		req.Equal(c.ID, fetched.ID)
+		req.Equal(c.ComposeModuleID, fetched.ComposeModuleID)
+		req.Equal(c.ComposeRecordID, fetched.ComposeRecordID)
+		req.Zero(fetched.Attempts)
+		req.Nil(fetched.LastAttemptAt)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, c := truncAndCreate(t)
+		c.Attempts = 2
+		c.Error = "unreachable"
+		c.LastAttemptAt = now()
+		req.NoError(s.UpdateFederationPendingChange(ctx, c))
+
+		fetched, err := s.LookupFederationPendingChangeByID(ctx, c.ID)
+		req.NoError(err)
+		req.Equal(uint(2), fetched.Attempts)
+		req.Equal("unreachable", fetched.Error)
+		req.NotNil(fetched.LastAttemptAt)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, c := truncAndCreate(t)
+		req.NoError(s.DeleteFederationPendingChange(ctx, c))
+
+		_, err := s.LookupFederationPendingChangeByID(ctx, c.ID)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateFederationPendingChanges(ctx))
+
+		var (
+			first  = makeNew()
+			second = makeNew()
+			other  = makeNew()
+		)
+
+		other.ComposeModuleID = id.Next()
+
+		req.NoError(s.CreateFederationPendingChange(ctx, first, second, other))
+
+		set, _, err := s.SearchFederationPendingChanges(ctx, types.PendingChangeFilter{ComposeModuleID: moduleID})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchFederationPendingChanges(ctx, types.PendingChangeFilter{ComposeRecordID: other.ComposeRecordID})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(other.ID, set[0].ID)
+	})
+
+	t.Run("search by attempts", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateFederationPendingChanges(ctx))
+
+		var (
+			failing = makeNew()
+			failed  = makeNew()
+			fresh   = makeNew()
+		)
+
+		failing.Attempts = 2
+		failed.Attempts = 5
+
+		req.NoError(s.CreateFederationPendingChange(ctx, failing, failed, fresh))
+
+		f := types.PendingChangeFilter{MaxAttempts: 5}
+		f.Sort = filter.SortExprSet{&filter.SortExpr{Column: "attempts"}}
+
+		set, _, err := s.SearchFederationPendingChanges(ctx, f)
+		req.NoError(err)
+		req.Len(set, 2)
+		req.Equal(fresh.ID, set[0].ID)
+		req.Equal(failing.ID, set[1].ID)
+	})
+}
@@ -5,8 +5,10 @@ package tests
 // Template:	pkg/codegen/assets/store_test_all.gen.go.tpl
 // Definitions:
 //  - store/actionlog.yaml
+//  - store/apigw_cache_entries.yaml
 //  - store/apigw_filter.yaml
 //  - store/apigw_route.yaml
+//  - store/apigw_secrets.yaml
 //  - store/applications.yaml
 //  - store/attachments.yaml
 //  - store/auth_clients.yaml
@@ -16,20 +18,27 @@ package tests
 //  - store/automation_sessions.yaml
 //  - store/automation_triggers.yaml
 //  - store/automation_workflows.yaml
+//  - store/cluster_leases.yaml
 //  - store/compose_attachments.yaml
 //  - store/compose_charts.yaml
 //  - store/compose_module_fields.yaml
 //  - store/compose_modules.yaml
 //  - store/compose_namespaces.yaml
 //  - store/compose_pages.yaml
+//  - store/compose_record_revisions.yaml
 //  - store/credentials.yaml
+//  - store/federation_conflicts.yaml
 //  - store/federation_exposed_modules.yaml
 //  - store/federation_module_mappings.yaml
 //  - store/federation_nodes.yaml
 //  - store/federation_nodes_sync.yaml
+//  - store/federation_pending_changes.yaml
 //  - store/federation_shared_modules.yaml
 //  - store/flags.yaml
+//  - store/invalidation_versions.yaml
 //  - store/labels.yaml
+//  - store/objstore_blobs.yaml
+//  - store/objstore_references.yaml
 //  - store/queue.yaml
 //  - store/queue_message.yaml
 //  - store/rbac_rules.yaml
@@ -41,6 +50,8 @@ package tests
 //  - store/settings.yaml
 //  - store/templates.yaml
 //  - store/users.yaml
+//  - store/webhook_deliveries.yaml
+//  - store/webhooks.yaml
 
 //
 // Changes to this file may cause incorrect behavior and will be lost if
@@ -58,6 +69,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testActionlog(t, s)
 	})
 
+	// Run generated tests for ApigwCacheEntries
+	t.Run("ApigwCacheEntries", func(t *testing.T) {
+		testApigwCacheEntries(t, s)
+	})
+
 	// Run generated tests for ApigwFilter
 	t.Run("ApigwFilter", func(t *testing.T) {
 		testApigwFilter(t, s)
@@ -68,6 +84,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testApigwRoute(t, s)
 	})
 
+	// Run generated tests for ApigwSecrets
+	t.Run("ApigwSecrets", func(t *testing.T) {
+		testApigwSecrets(t, s)
+	})
+
 	// Run generated tests for Applications
 	t.Run("Applications", func(t *testing.T) {
 		testApplications(t, s)
@@ -113,6 +134,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testAutomationWorkflows(t, s)
 	})
 
+	// Run generated tests for ClusterLeases
+	t.Run("ClusterLeases", func(t *testing.T) {
+		testClusterLeases(t, s)
+	})
+
 	// Run generated tests for ComposeAttachments
 	t.Run("ComposeAttachments", func(t *testing.T) {
 		testComposeAttachments(t, s)
@@ -143,6 +169,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testComposePages(t, s)
 	})
 
+	// Run generated tests for ComposeRecordRevisions
+	t.Run("ComposeRecordRevisions", func(t *testing.T) {
+		testComposeRecordRevisions(t, s)
+	})
+
 	// Run generated tests for ComposeRecordValues
 	t.Run("ComposeRecordValues", func(t *testing.T) {
 		testComposeRecordValues(t, s)
@@ -158,6 +189,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testCredentials(t, s)
 	})
 
+	// Run generated tests for FederationConflicts
+	t.Run("FederationConflicts", func(t *testing.T) {
+		testFederationConflicts(t, s)
+	})
+
 	// Run generated tests for FederationExposedModules
 	t.Run("FederationExposedModules", func(t *testing.T) {
 		testFederationExposedModules(t, s)
@@ -178,6 +214,11 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testFederationNodesSync(t, s)
 	})
 
+	// Run generated tests for FederationPendingChanges
+	t.Run("FederationPendingChanges", func(t *testing.T) {
+		testFederationPendingChanges(t, s)
+	})
+
 	// Run generated tests for FederationSharedModules
 	t.Run("FederationSharedModules", func(t *testing.T) {
 		testFederationSharedModules(t, s)
@@ -188,11 +229,26 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 		testFlags(t, s)
 	})
 
+	// Run generated tests for InvalidationVersions
+	t.Run("InvalidationVersions", func(t *testing.T) {
+		testInvalidationVersions(t, s)
+	})
+
 	// Run generated tests for Labels
 	t.Run("Labels", func(t *testing.T) {
 		testLabels(t, s)
 	})
 
+	// Run generated tests for ObjstoreBlobs
+	t.Run("ObjstoreBlobs", func(t *testing.T) {
+		testObjstoreBlobs(t, s)
+	})
+
+	// Run generated tests for ObjstoreReferences
+	t.Run("ObjstoreReferences", func(t *testing.T) {
+		testObjstoreReferences(t, s)
+	})
+
 	// Run generated tests for Queue
 	t.Run("Queue", func(t *testing.T) {
 		testQueue(t, s)
@@ -247,4 +303,14 @@ func testAllGenerated(t *testing.T, s store.Storer) {
 	t.Run("Users", func(t *testing.T) {
 		testUsers(t, s)
 	})
+
+	// Run generated tests for WebhookDeliveries
+	t.Run("WebhookDeliveries", func(t *testing.T) {
+		testWebhookDeliveries(t, s)
+	})
+
+	// Run generated tests for Webhooks
+	t.Run("Webhooks", func(t *testing.T) {
+		testWebhooks(t, s)
+	})
 }
@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation/types"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testInvalidationVersions(t *testing.T, s store.InvalidationVersions) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(kind, node string) *types.Version {
+			return &types.Version{
+				Kind:      kind,
+				Version:   id.Next(),
+				Node:      node,
+				UpdatedAt: time.Now(),
+			}
+		}
+	)
+
+	t.Run("upsert", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateInvalidationVersions(ctx))
+
+		v := makeNew("rbac", "node-1")
+		req.NoError(s.UpsertInvalidationVersion(ctx, v))
+
+		v.Version = id.Next()
+		v.Node = "node-2"
+		req.NoError(s.UpsertInvalidationVersion(ctx, v))
+
+		fetched, err := s.LookupInvalidationVersionByKind(ctx, "rbac")
+		req.NoError(err)
+		req.Equal(v.Version, fetched.Version)
+		req.Equal("node-2", fetched.Node)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateInvalidationVersions(ctx))
+
+		req.NoError(s.CreateInvalidationVersion(ctx,
+			makeNew("rbac", "node-1"),
+			makeNew("settings", "node-1"),
+			makeNew("compose:module", "node-2"),
+		))
+
+		set, _, err := s.SearchInvalidationVersions(ctx, types.VersionFilter{})
+		req.NoError(err)
+		req.Len(set, 3)
+
+		set, _, err = s.SearchInvalidationVersions(ctx, types.VersionFilter{Kind: []string{"rbac", "settings"}})
+		req.NoError(err)
+		req.Len(set, 2)
+	})
+}
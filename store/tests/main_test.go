@@ -33,6 +33,9 @@ func Test_Store(t *testing.T) {
 			name      string
 			dsnEnvKey string
 			init      store.ConnectorFn
+
+			// transactions are disabled
+			noTx bool
 		}
 	)
 
@@ -61,6 +64,7 @@ func Test_Store(t *testing.T) {
 				name:      "SQLite",
 				dsnEnvKey: "RDBMS_SQLITE_DSN",
 				init:      sqlite3.Connect,
+				noTx:      true,
 			},
 			{
 				name:      "InMemory",
@@ -101,6 +105,14 @@ func Test_Store(t *testing.T) {
 			}
 
 			testAllGenerated(t, genericStore)
+
+			t.Run("Tx", func(t *testing.T) {
+				if s.noTx {
+					t.Skipf("transactions are disabled in %s store", s.name)
+				}
+
+				testTx(t, genericStore)
+			})
 		})
 	}
 
@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testObjstoreBlobs(t *testing.T, s store.ObjstoreBlobs) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(ns, hash string) *types.Blob {
+			return &types.Blob{
+				Namespace: ns,
+				Hash:      hash,
+				CreatedAt: time.Now(),
+			}
+		}
+
+		refs = func(req *require.Assertions, ns, hash string) uint {
+			b, err := s.LookupObjstoreBlobByNamespaceHash(ctx, ns, hash)
+			req.NoError(err)
+			return b.Refs
+		}
+	)
+
+	t.Run("acquire", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateObjstoreBlobs(ctx))
+
+		created, err := s.AcquireObjstoreBlob(ctx, makeNew("compose", "aaa"))
+		req.NoError(err)
+		req.True(created)
+		req.Equal(uint(1), refs(req, "compose", "aaa"))
+
+		created, err = s.AcquireObjstoreBlob(ctx, makeNew("compose", "aaa"))
+		req.NoError(err)
+		req.False(created)
+		req.Equal(uint(2), refs(req, "compose", "aaa"))
+
+		created, err = s.AcquireObjstoreBlob(ctx, makeNew("system", "aaa"))
+		req.NoError(err)
+		req.True(created)
+	})
+
+	t.Run("acquire blob created in the meantime", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateObjstoreBlobs(ctx))
+
+		// blob without references is in the middle of being removed
+		req.NoError(s.CreateObjstoreBlob(ctx, makeNew("compose", "aaa")))
+
+		_, err := s.AcquireObjstoreBlob(ctx, makeNew("compose", "aaa"))
+		req.Error(err)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateObjstoreBlobs(ctx))
+
+		_, err := s.AcquireObjstoreBlob(ctx, makeNew("compose", "aaa"))
+		req.NoError(err)
+		_, err = s.AcquireObjstoreBlob(ctx, makeNew("compose", "aaa"))
+		req.NoError(err)
+
+		removed, err := s.ReleaseObjstoreBlob(ctx, "compose", "aaa")
+		req.NoError(err)
+		req.False(removed)
+		req.Equal(uint(1), refs(req, "compose", "aaa"))
+
+		removed, err = s.ReleaseObjstoreBlob(ctx, "compose", "aaa")
+		req.NoError(err)
+		req.True(removed)
+
+		_, err = s.LookupObjstoreBlobByNamespaceHash(ctx, "compose", "aaa")
+		req.EqualError(err, store.ErrNotFound.Error())
+
+		removed, err = s.ReleaseObjstoreBlob(ctx, "compose", "aaa")
+		req.NoError(err)
+		req.False(removed)
+	})
+}
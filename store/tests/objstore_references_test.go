@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/objstore/types"
+	"github.com/cortezaproject/corteza-server/store"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testObjstoreReferences(t *testing.T, s store.ObjstoreReferences) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(ns, name, hash string) *types.Reference {
+			return &types.Reference{
+				Namespace: ns,
+				Name:      name,
+				Hash:      hash,
+				CreatedAt: time.Now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.Reference) {
+			req := require.New(t)
+			req.NoError(s.TruncateObjstoreReferences(ctx))
+			ref := makeNew("compose", "compose/1.pdf", "aaa")
+			req.NoError(s.CreateObjstoreReference(ctx, ref))
+			return req, ref
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateObjstoreReferences(ctx))
+		req.NoError(s.CreateObjstoreReference(ctx, makeNew("compose", "compose/1.pdf", "aaa")))
+	})
+
+	t.Run("create with duplicate name", func(t *testing.T) {
+		req, ref := truncAndCreate(t)
+		req.Error(s.CreateObjstoreReference(ctx, makeNew(ref.Namespace, ref.Name, "bbb")))
+		req.NoError(s.CreateObjstoreReference(ctx, makeNew("system", ref.Name, "bbb")))
+	})
+
+	t.Run("lookup by namespace and name", func(t *testing.T) {
+		req, ref := truncAndCreate(t)
+		fetched, err := s.LookupObjstoreReferenceByNamespaceName(ctx, ref.Namespace, ref.Name)
+		req.NoError(err)
+		req.Equal(ref.Hash, fetched.Hash)
+
+		_, err = s.LookupObjstoreReferenceByNamespaceName(ctx, "system", ref.Name)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, ref := truncAndCreate(t)
+		ref.Hash = "bbb"
+		req.NoError(s.UpdateObjstoreReference(ctx, ref))
+
+		fetched, err := s.LookupObjstoreReferenceByNamespaceName(ctx, ref.Namespace, ref.Name)
+		req.NoError(err)
+		req.Equal("bbb", fetched.Hash)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, ref := truncAndCreate(t)
+		req.NoError(s.DeleteObjstoreReference(ctx, ref))
+
+		_, err := s.LookupObjstoreReferenceByNamespaceName(ctx, ref.Namespace, ref.Name)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateObjstoreReferences(ctx))
+		req.NoError(s.CreateObjstoreReference(ctx,
+			makeNew("compose", "compose/1.pdf", "aaa"),
+			makeNew("compose", "compose/2.pdf", "aaa"),
+			makeNew("compose", "compose/3.pdf", "bbb"),
+			makeNew("system", "system/1.pdf", "aaa"),
+		))
+
+		set, _, err := s.SearchObjstoreReferences(ctx, types.ReferenceFilter{Namespace: "compose", Hash: "aaa"})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchObjstoreReferences(ctx, types.ReferenceFilter{Hash: "aaa"})
+		req.NoError(err)
+		req.Len(set, 3)
+	})
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testTx(t *testing.T, s store.Storer) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func(handle string) *types.Role {
+			return &types.Role{
+				ID:        id.Next(),
+				CreatedAt: time.Now(),
+				Name:      handle,
+				Handle:    handle,
+			}
+		}
+	)
+
+	t.Run("nested transaction is rolled back on its own", func(t *testing.T) {
+		var (
+			req = require.New(t)
+
+			kept   = makeNew("tx-kept")
+			failed = makeNew("tx-failed")
+			after  = makeNew("tx-after")
+		)
+
+		req.NoError(s.TruncateRoles(ctx))
+
+		err := store.Tx(ctx, s, func(ctx context.Context, s store.Storer) error {
+			if err := s.CreateRole(ctx, kept); err != nil {
+				return err
+			}
+
+			err := store.Tx(ctx, s, func(ctx context.Context, s store.Storer) error {
+				if err := s.CreateRole(ctx, failed); err != nil {
+					return err
+				}
+
+				return fmt.Errorf("failed")
+			})
+
+			req.EqualError(err, "failed")
+
+			// transaction can be used after the nested one fails
+			return s.CreateRole(ctx, after)
+		})
+
+		req.NoError(err)
+
+		_, err = s.LookupRoleByID(ctx, kept.ID)
+		req.NoError(err)
+
+		_, err = s.LookupRoleByID(ctx, after.ID)
+		req.NoError(err)
+
+		_, err = s.LookupRoleByID(ctx, failed.ID)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+}
@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhookDeliveries(t *testing.T, s store.WebhookDeliveries) {
+	var (
+		ctx = context.Background()
+
+		webhookID = id.Next()
+
+		makeNew = func(status types.WebhookDeliveryStatus, next *time.Time) *types.WebhookDelivery {
+			return &types.WebhookDelivery{
+				ID:            id.Next(),
+				WebhookID:     webhookID,
+				ResourceType:  "compose:record",
+				EventType:     "afterCreate",
+				Payload:       types.WebhookPayload(`{"foo":"bar"}`),
+				Status:        status,
+				NextAttemptAt: next,
+				CreatedAt:     *now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.WebhookDelivery) {
+			req := require.New(t)
+			req.NoError(s.TruncateWebhookDeliveries(ctx))
+			res := makeNew(types.WebhookDeliveryStatusPending, now())
+			req.NoError(s.CreateWebhookDelivery(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateWebhookDelivery(ctx, makeNew(types.WebhookDeliveryStatusPending, nil)))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req, d := truncAndCreate(t)
+
+		fetched, err := s.LookupWebhookDeliveryByID(ctx, d.ID)
+		req.NoError(err)
+		req.Equal(d.ID, fetched.ID)
+		req.Equal(d.WebhookID, fetched.WebhookID)
+		req.JSONEq(`{"foo":"bar"}`, string(fetched.Payload))
+		req.Equal(types.WebhookDeliveryStatusPending, fetched.Status)
+		req.NotNil(fetched.NextAttemptAt)
+		req.Nil(fetched.DeliveredAt)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, d := truncAndCreate(t)
+		d.Attempts = 1
+		d.Status = types.WebhookDeliveryStatusDelivered
+		d.ResponseStatus = 200
+		d.DeliveredAt = now()
+		d.NextAttemptAt = nil
+		req.NoError(s.UpdateWebhookDelivery(ctx, d))
+
+		fetched, err := s.LookupWebhookDeliveryByID(ctx, d.ID)
+		req.NoError(err)
+		req.Equal(uint(1), fetched.Attempts)
+		req.Equal(200, fetched.ResponseStatus)
+		req.Equal(types.WebhookDeliveryStatusDelivered, fetched.Status)
+		req.NotNil(fetched.DeliveredAt)
+		req.Nil(fetched.NextAttemptAt)
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateWebhookDeliveries(ctx))
+
+		var (
+			past   = now().Add(-time.Hour)
+			future = now().Add(time.Hour)
+
+			due       = makeNew(types.WebhookDeliveryStatusPending, &past)
+			scheduled = makeNew(types.WebhookDeliveryStatusPending, &future)
+			delivered = makeNew(types.WebhookDeliveryStatusDelivered, nil)
+			other     = makeNew(types.WebhookDeliveryStatusFailed, nil)
+		)
+
+		other.WebhookID = id.Next()
+
+		req.NoError(s.CreateWebhookDelivery(ctx, due, scheduled, delivered, other))
+
+		set, _, err := s.SearchWebhookDeliveries(ctx, types.WebhookDeliveryFilter{WebhookID: webhookID})
+		req.NoError(err)
+		req.Len(set, 3)
+
+		set, _, err = s.SearchWebhookDeliveries(ctx, types.WebhookDeliveryFilter{
+			Status:            types.WebhookDeliveryStatusPending,
+			NextAttemptBefore: now(),
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(due.ID, set[0].ID)
+	})
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/stretchr/testify/require"
+)
+
+func testWebhooks(t *testing.T, s store.Webhooks) {
+	var (
+		ctx = context.Background()
+
+		makeNew = func() *types.Webhook {
+			return &types.Webhook{
+				ID:           id.Next(),
+				URL:          "https://example.tld/hook",
+				Secret:       "s3cr3t",
+				ResourceType: "compose:record",
+				EventTypes:   types.WebhookEventTypeSet{"afterCreate", "afterUpdate"},
+				Constraints: types.WebhookConstraintSet{
+					{Name: "module", Values: []string{"contact"}},
+				},
+				Enabled:   true,
+				CreatedAt: *now(),
+			}
+		}
+
+		truncAndCreate = func(t *testing.T) (*require.Assertions, *types.Webhook) {
+			req := require.New(t)
+			req.NoError(s.TruncateWebhooks(ctx))
+			res := makeNew()
+			req.NoError(s.CreateWebhook(ctx, res))
+			return req, res
+		}
+	)
+
+	t.Run("create", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.CreateWebhook(ctx, makeNew()))
+	})
+
+	t.Run("lookup by ID", func(t *testing.T) {
+		req, wh := truncAndCreate(t)
+
+		fetched, err := s.LookupWebhookByID(ctx, wh.ID)
+		req.NoError(err)
+		req.Equal(wh.ID, fetched.ID)
+		req.Equal(wh.URL, fetched.URL)
+		req.Equal(wh.Secret, fetched.Secret)
+		req.Equal(wh.EventTypes, fetched.EventTypes)
+		req.Len(fetched.Constraints, 1)
+		req.Equal("module", fetched.Constraints[0].Name)
+		req.Nil(fetched.UpdatedAt)
+		req.Nil(fetched.DeletedAt)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		req, wh := truncAndCreate(t)
+		wh.Enabled = false
+		wh.Meta.Description = "updated"
+		req.NoError(s.UpdateWebhook(ctx, wh))
+
+		fetched, err := s.LookupWebhookByID(ctx, wh.ID)
+		req.NoError(err)
+		req.False(fetched.Enabled)
+		req.Equal("updated", fetched.Meta.Description)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req, wh := truncAndCreate(t)
+		req.NoError(s.DeleteWebhook(ctx, wh))
+		_, err := s.LookupWebhookByID(ctx, wh.ID)
+		req.EqualError(err, store.ErrNotFound.Error())
+	})
+
+	t.Run("search", func(t *testing.T) {
+		req := require.New(t)
+		req.NoError(s.TruncateWebhooks(ctx))
+
+		var (
+			enabled  = makeNew()
+			disabled = makeNew()
+			deleted  = makeNew()
+			system   = makeNew()
+		)
+
+		disabled.Enabled = false
+		deleted.DeletedAt = now()
+		system.ResourceType = "system:user"
+
+		req.NoError(s.CreateWebhook(ctx, enabled, disabled, deleted, system))
+
+		set, _, err := s.SearchWebhooks(ctx, types.WebhookFilter{})
+		req.NoError(err)
+		req.Len(set, 2)
+
+		set, _, err = s.SearchWebhooks(ctx, types.WebhookFilter{Disabled: filter.StateInclusive, Deleted: filter.StateInclusive})
+		req.NoError(err)
+		req.Len(set, 4)
+
+		set, _, err = s.SearchWebhooks(ctx, types.WebhookFilter{ResourceType: "compose:record"})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(enabled.ID, set[0].ID)
+
+		set, _, err = s.SearchWebhooks(ctx, types.WebhookFilter{WebhookID: []uint64{system.ID}})
+		req.NoError(err)
+		req.Len(set, 1)
+		req.Equal(system.ID, set[0].ID)
+	})
+}
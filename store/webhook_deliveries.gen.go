@@ -0,0 +1,68 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/webhook_deliveries.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	WebhookDeliveries interface {
+		SearchWebhookDeliveries(ctx context.Context, f types.WebhookDeliveryFilter) (types.WebhookDeliverySet, types.WebhookDeliveryFilter, error)
+		LookupWebhookDeliveryByID(ctx context.Context, id uint64) (*types.WebhookDelivery, error)
+
+		CreateWebhookDelivery(ctx context.Context, rr ...*types.WebhookDelivery) error
+
+		UpdateWebhookDelivery(ctx context.Context, rr ...*types.WebhookDelivery) error
+
+		DeleteWebhookDelivery(ctx context.Context, rr ...*types.WebhookDelivery) error
+		DeleteWebhookDeliveryByID(ctx context.Context, ID uint64) error
+
+		TruncateWebhookDeliveries(ctx context.Context) error
+	}
+)
+
+var _ *types.WebhookDelivery
+var _ context.Context
+
+// SearchWebhookDeliveries returns all matching WebhookDeliveries from store
+func SearchWebhookDeliveries(ctx context.Context, s WebhookDeliveries, f types.WebhookDeliveryFilter) (types.WebhookDeliverySet, types.WebhookDeliveryFilter, error) {
+	return s.SearchWebhookDeliveries(ctx, f)
+}
+
+// LookupWebhookDeliveryByID searches for webhook delivery by ID
+func LookupWebhookDeliveryByID(ctx context.Context, s WebhookDeliveries, id uint64) (*types.WebhookDelivery, error) {
+	return s.LookupWebhookDeliveryByID(ctx, id)
+}
+
+// CreateWebhookDelivery creates one or more WebhookDeliveries in store
+func CreateWebhookDelivery(ctx context.Context, s WebhookDeliveries, rr ...*types.WebhookDelivery) error {
+	return s.CreateWebhookDelivery(ctx, rr...)
+}
+
+// UpdateWebhookDelivery updates one or more (existing) WebhookDeliveries in store
+func UpdateWebhookDelivery(ctx context.Context, s WebhookDeliveries, rr ...*types.WebhookDelivery) error {
+	return s.UpdateWebhookDelivery(ctx, rr...)
+}
+
+// DeleteWebhookDelivery Deletes one or more WebhookDeliveries from store
+func DeleteWebhookDelivery(ctx context.Context, s WebhookDeliveries, rr ...*types.WebhookDelivery) error {
+	return s.DeleteWebhookDelivery(ctx, rr...)
+}
+
+// DeleteWebhookDeliveryByID Deletes WebhookDelivery from store
+func DeleteWebhookDeliveryByID(ctx context.Context, s WebhookDeliveries, ID uint64) error {
+	return s.DeleteWebhookDeliveryByID(ctx, ID)
+}
+
+// TruncateWebhookDeliveries Deletes all WebhookDeliveries from store
+func TruncateWebhookDeliveries(ctx context.Context, s WebhookDeliveries) error {
+	return s.TruncateWebhookDeliveries(ctx)
+}
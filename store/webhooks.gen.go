@@ -0,0 +1,70 @@
+package store
+
+// This file is auto-generated.
+//
+// Template:    pkg/codegen/assets/store_base.gen.go.tpl
+// Definitions: store/webhooks.yaml
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+
+import (
+	"context"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	Webhooks interface {
+		SearchWebhooks(ctx context.Context, f types.WebhookFilter) (types.WebhookSet, types.WebhookFilter, error)
+		LookupWebhookByID(ctx context.Context, id uint64) (*types.Webhook, error)
+
+		CreateWebhook(ctx context.Context, rr ...*types.Webhook) error
+
+		UpdateWebhook(ctx context.Context, rr ...*types.Webhook) error
+
+		DeleteWebhook(ctx context.Context, rr ...*types.Webhook) error
+		DeleteWebhookByID(ctx context.Context, ID uint64) error
+
+		TruncateWebhooks(ctx context.Context) error
+	}
+)
+
+var _ *types.Webhook
+var _ context.Context
+
+// SearchWebhooks returns all matching Webhooks from store
+func SearchWebhooks(ctx context.Context, s Webhooks, f types.WebhookFilter) (types.WebhookSet, types.WebhookFilter, error) {
+	return s.SearchWebhooks(ctx, f)
+}
+
+// LookupWebhookByID searches for webhook by ID
+//
+// It returns webhook even if deleted
+func LookupWebhookByID(ctx context.Context, s Webhooks, id uint64) (*types.Webhook, error) {
+	return s.LookupWebhookByID(ctx, id)
+}
+
+// CreateWebhook creates one or more Webhooks in store
+func CreateWebhook(ctx context.Context, s Webhooks, rr ...*types.Webhook) error {
+	return s.CreateWebhook(ctx, rr...)
+}
+
+// UpdateWebhook updates one or more (existing) Webhooks in store
+func UpdateWebhook(ctx context.Context, s Webhooks, rr ...*types.Webhook) error {
+	return s.UpdateWebhook(ctx, rr...)
+}
+
+// DeleteWebhook Deletes one or more Webhooks from store
+func DeleteWebhook(ctx context.Context, s Webhooks, rr ...*types.Webhook) error {
+	return s.DeleteWebhook(ctx, rr...)
+}
+
+// DeleteWebhookByID Deletes Webhook from store
+func DeleteWebhookByID(ctx context.Context, s Webhooks, ID uint64) error {
+	return s.DeleteWebhookByID(ctx, ID)
+}
+
+// TruncateWebhooks Deletes all Webhooks from store
+func TruncateWebhooks(ctx context.Context, s Webhooks) error {
+	return s.TruncateWebhooks(ctx)
+}
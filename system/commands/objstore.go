@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"errors"
+
+	cmpService "github.com/cortezaproject/corteza-server/compose/service"
+	cmpTypes "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/cli"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errContentAddressingDisabled = errors.New("content addressed storage is not enabled (set STORAGE_DEDUP=true)")
+)
+
+type (
+	// rehasher is implemented by content addressed object stores
+	rehasher interface {
+		Rehash(filename string) (bool, error)
+	}
+)
+
+func Objstore(ctx context.Context, app serviceInitializer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "objstore",
+		Short: "Object (file) storage management",
+	}
+
+	cmd.AddCommand(objstoreRehash(ctx, app))
+
+	return cmd
+}
+
+func objstoreRehash(ctx context.Context, app serviceInitializer) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rehash",
+		Short: "Move existing attachments to content addressed storage",
+		Long: "Stores all existing attachment files (originals and previews) by their content hash.\n" +
+			"Requires content addressed storage to be enabled (STORAGE_DEDUP=true).\n" +
+			"Files that are already content-addressed are skipped.",
+		PreRunE: commandPreRunInitService(app),
+		Run: func(cmd *cobra.Command, args []string) {
+			var (
+				urls []string
+				n    int
+			)
+
+			sysFiles, ok := service.DefaultObjectStore.(rehasher)
+			if !ok {
+				cli.HandleError(errContentAddressingDisabled)
+			}
+
+			cmpFiles, ok := cmpService.DefaultObjectStore.(rehasher)
+			if !ok {
+				cli.HandleError(errContentAddressingDisabled)
+			}
+
+			sysAtt, _, err := store.SearchAttachments(ctx, service.DefaultStore, types.AttachmentFilter{})
+			cli.HandleError(err)
+
+			for _, att := range sysAtt {
+				urls = append(urls, att.Url, att.PreviewUrl)
+			}
+
+			n += rehash(cmd, sysFiles, urls...)
+
+			cmpAtt, _, err := store.SearchComposeAttachments(ctx, cmpService.DefaultStore, cmpTypes.AttachmentFilter{})
+			cli.HandleError(err)
+
+			urls = urls[:0]
+			for _, att := range cmpAtt {
+				urls = append(urls, att.Url, att.PreviewUrl)
+			}
+
+			n += rehash(cmd, cmpFiles, urls...)
+
+			cmd.Printf("Rehashed %d file(s)\n", n)
+		},
+	}
+}
+
+// rehash moves files to content addressed storage and returns number of moved files
+//
+// Errors are reported but do not stop the migration
+func rehash(cmd *cobra.Command, s rehasher, urls ...string) (n int) {
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+
+		if ok, err := s.Rehash(u); err != nil {
+			cmd.Printf("Failed to rehash %q: %v\n", u, err)
+		} else if ok {
+			n++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,297 @@
+package renderer
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type (
+	// docxSpan is a position of an element in the document part
+	docxSpan struct {
+		start, end int
+	}
+)
+
+var (
+	// document parts that can contain template actions
+	docxPartRegex = regexp.MustCompile(`^word/(document|header\d*|footer\d*|footnotes|endnotes)\.xml$`)
+
+	// text node of a run; first group is the opening tag, second is the text
+	docxTextRegex = regexp.MustCompile(`(<w:t(?:\s[^>]*)?>)([^<]*)</w:t>`)
+
+	docxNewlineReplacer = strings.NewReplacer(
+		"\r\n", `</w:t><w:br/><w:t xml:space="preserve">`,
+		"\n", `</w:t><w:br/><w:t xml:space="preserve">`,
+		"\t", `</w:t><w:tab/><w:t xml:space="preserve">`,
+	)
+
+	docxXMLEscaper = strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+		"'", "&apos;",
+	)
+)
+
+// renderDocx renders all of the document parts of the docx template
+//
+// Other parts (styles, images, ...) are copied as they are.
+func renderDocx(tpl io.Reader, pp map[string]io.Reader, vars map[string]interface{}) ([]byte, error) {
+	bb, err := ioutil.ReadAll(tpl)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(bb), int64(len(bb)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open docx template: %w", err)
+	}
+
+	// partials are used by each of the document parts
+	partials := make(map[string][]byte)
+	for h, p := range pp {
+		if partials[h], err = ioutil.ReadAll(p); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		buf = &bytes.Buffer{}
+		zw  = zip.NewWriter(buf)
+	)
+
+	for _, f := range zr.File {
+		var (
+			w  io.Writer
+			rc io.ReadCloser
+		)
+
+		if rc, err = f.Open(); err != nil {
+			return nil, err
+		}
+
+		bb, err = ioutil.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if docxPartRegex.MatchString(f.Name) {
+			if bb, err = renderDocxPart(bb, partials, vars); err != nil {
+				return nil, fmt.Errorf("could not render %s: %w", f.Name, err)
+			}
+		}
+
+		if w, err = zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: f.Method, Modified: f.Modified}); err != nil {
+			return nil, err
+		}
+
+		if _, err = w.Write(bb); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderDocxPart(part []byte, partials map[string][]byte, vars map[string]interface{}) ([]byte, error) {
+	src := string(part)
+	if !strings.Contains(src, "{{") {
+		return part, nil
+	}
+
+	src = docxMergeActions(src)
+	src = docxLiftControls(src, "w:tr")
+	src = docxLiftControls(src, "w:p")
+
+	pp := make(map[string]io.Reader)
+	for h, p := range partials {
+		pp[h] = bytes.NewReader(p)
+	}
+
+	t, err := preprocOfficeTemplate(src, pp, docxEscape)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	if err = t.Execute(out, vars); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// docxEscape escapes values so they can be used inside text nodes
+//
+// New lines and tabs are converted to line breaks and tabs
+func docxEscape(s string) string {
+	return docxNewlineReplacer.Replace(docxXMLEscaper.Replace(s))
+}
+
+// docxMergeActions moves template actions that are split across multiple runs
+// into the first run
+//
+// Word processors split text into multiple runs (spell checking, formatting changes, ...)
+// which breaks the template actions, for example {{.na</w:t>...<w:t>me}}.
+func docxMergeActions(src string) string {
+	var (
+		mm = docxTextRegex.FindAllStringSubmatchIndex(src, -1)
+
+		full   = &strings.Builder{}
+		starts = make([]int, len(mm))
+	)
+
+	for i, m := range mm {
+		starts[i] = full.Len()
+		full.WriteString(src[m[4]:m[5]])
+	}
+
+	var (
+		text    = full.String()
+		actions = officeActionRegex.FindAllStringIndex(text, -1)
+		out     = &strings.Builder{}
+		last    = 0
+		a       = 0
+	)
+
+	for i, m := range mm {
+		var (
+			from = starts[i]
+			to   = from + (m[5] - m[4])
+			node = &strings.Builder{}
+		)
+
+		for p := from; p < to; {
+			// skip actions that already ended
+			for a < len(actions) && actions[a][1] <= p {
+				a++
+			}
+
+			switch {
+			case a < len(actions) && actions[a][0] < from && actions[a][1] > p:
+				// action started in one of the previous nodes
+				p = actions[a][1]
+
+			case a < len(actions) && actions[a][0] == p:
+				node.WriteString(officeActionSource(text[actions[a][0]:actions[a][1]]))
+				p = actions[a][1]
+
+			case a < len(actions) && actions[a][0] < to:
+				node.WriteString(text[p:actions[a][0]])
+				p = actions[a][0]
+
+			default:
+				node.WriteString(text[p:to])
+				p = to
+			}
+		}
+
+		tag := src[m[2]:m[3]]
+		if !strings.Contains(tag, "xml:space") {
+			tag = `<w:t xml:space="preserve">`
+		}
+
+		out.WriteString(src[last:m[0]])
+		out.WriteString(tag)
+		out.WriteString(node.String())
+		out.WriteString("</w:t>")
+		last = m[1]
+	}
+
+	out.WriteString(src[last:])
+	return out.String()
+}
+
+// docxLiftControls replaces elements that contain only control actions with the actions
+//
+// This allows control actions to repeat or omit the elements between them; for example
+// a table row with {{range .items}} and a table row with {{end}} repeat all of the
+// table rows between them.
+func docxLiftControls(src, tag string) string {
+	var (
+		spans = docxSpans(src, tag)
+		lift  = make([]docxSpan, 0, len(spans))
+	)
+
+	for _, s := range spans {
+		if officeControlOnly(docxText(src[s.start:s.end])) {
+			lift = append(lift, s)
+		}
+	}
+
+	// outer elements first so we can skip nested ones
+	sort.Slice(lift, func(i, j int) bool { return lift[i].start < lift[j].start })
+
+	var (
+		out  = &strings.Builder{}
+		last = 0
+	)
+
+	for _, s := range lift {
+		if s.start < last {
+			// nested in an element that was already replaced
+			continue
+		}
+
+		out.WriteString(src[last:s.start])
+		out.WriteString(strings.Join(officeActionRegex.FindAllString(docxText(src[s.start:s.end]), -1), ""))
+		last = s.end
+	}
+
+	out.WriteString(src[last:])
+	return out.String()
+}
+
+// docxSpans returns positions of all elements with the given tag
+func docxSpans(src, tag string) []docxSpan {
+	var (
+		rx    = regexp.MustCompile(`<(/?)` + regexp.QuoteMeta(tag) + `(?:\s[^>]*?)?(/?)>`)
+		open  = make([]int, 0, 8)
+		spans = make([]docxSpan, 0, 32)
+	)
+
+	for _, m := range rx.FindAllStringSubmatchIndex(src, -1) {
+		switch {
+		case m[5] > m[4]:
+			// self-closing element
+
+		case m[3] > m[2]:
+			if len(open) == 0 {
+				continue
+			}
+
+			spans = append(spans, docxSpan{start: open[len(open)-1], end: m[1]})
+			open = open[:len(open)-1]
+
+		default:
+			open = append(open, m[0])
+		}
+	}
+
+	return spans
+}
+
+// docxText returns text of all text nodes in the element
+func docxText(src string) string {
+	var (
+		out = &strings.Builder{}
+	)
+
+	for _, m := range docxTextRegex.FindAllStringSubmatch(src, -1) {
+		out.WriteString(m[2])
+	}
+
+	return out.String()
+}
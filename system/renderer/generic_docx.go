@@ -0,0 +1,65 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	genericDocx struct {
+		def DriverDefinition
+	}
+	genericDocxDriver struct{}
+)
+
+func newGenericDocx() driverFactory {
+	return &genericDocx{
+		def: DriverDefinition{
+			Name: "genericDOCX",
+			InputTypes: []types.DocumentType{
+				types.DocumentTypeDOCX,
+			},
+			OutputTypes: []types.DocumentType{
+				types.DocumentTypeDOCX,
+			},
+		},
+	}
+}
+
+func (d *genericDocx) Define() DriverDefinition {
+	return d.def
+}
+
+func (d *genericDocx) CanRender(t types.DocumentType) bool {
+	for _, i := range d.def.InputTypes {
+		if i == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *genericDocx) CanProduce(t types.DocumentType) bool {
+	for _, o := range d.def.OutputTypes {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *genericDocx) Driver() driver {
+	return &genericDocxDriver{}
+}
+
+func (d *genericDocxDriver) Render(ctx context.Context, pl *driverPayload) (io.ReadSeeker, error) {
+	bb, err := renderDocx(pl.Template, pl.Partials, pl.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(bb), nil
+}
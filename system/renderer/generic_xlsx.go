@@ -0,0 +1,65 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	genericXlsx struct {
+		def DriverDefinition
+	}
+	genericXlsxDriver struct{}
+)
+
+func newGenericXlsx() driverFactory {
+	return &genericXlsx{
+		def: DriverDefinition{
+			Name: "genericXLSX",
+			InputTypes: []types.DocumentType{
+				types.DocumentTypeXLSX,
+			},
+			OutputTypes: []types.DocumentType{
+				types.DocumentTypeXLSX,
+			},
+		},
+	}
+}
+
+func (d *genericXlsx) Define() DriverDefinition {
+	return d.def
+}
+
+func (d *genericXlsx) CanRender(t types.DocumentType) bool {
+	for _, i := range d.def.InputTypes {
+		if i == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *genericXlsx) CanProduce(t types.DocumentType) bool {
+	for _, o := range d.def.OutputTypes {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *genericXlsx) Driver() driver {
+	return &genericXlsxDriver{}
+}
+
+func (d *genericXlsxDriver) Render(ctx context.Context, pl *driverPayload) (io.ReadSeeker, error) {
+	bb, err := renderXlsx(pl.Template, pl.Partials, pl.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(bb), nil
+}
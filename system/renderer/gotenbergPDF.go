@@ -171,7 +171,7 @@ func (d *gotenbergPDFDriver) applyOptions(mw *multipart.Writer, opts map[string]
 			err = d.addFormField(mw, "marginRight", v)
 
 		case "documentSize":
-			w, h := d.documentDimensions(v)
+			w, h := documentDimensions(v)
 			if w+h != "" {
 				err = d.addFormField(mw, "paperWidth", w)
 				if err != nil {
@@ -215,7 +215,7 @@ func (d *gotenbergPDFDriver) addFormField(mw *multipart.Writer, k, v string) err
 }
 
 // documentDimensions returns the ISO216 standard document dimensions in inches (Gotenberg uses inches)
-func (d *gotenbergPDFDriver) documentDimensions(isoDoc string) (string, string) {
+func documentDimensions(isoDoc string) (string, string) {
 	switch strings.ToLower(isoDoc) {
 	// A series
 	case "a0":
@@ -0,0 +1,195 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/net/html"
+)
+
+type (
+	nativePDF struct {
+		def DriverDefinition
+	}
+	nativePDFDriver struct{}
+)
+
+const (
+	// default document margin in inches; same as Gotenberg uses
+	nativePDFDefaultMargin = 0.39
+
+	mmPerInch = 25.4
+)
+
+// newNativePDF initializes the HTML to PDF driver that does not use any external services
+//
+// Only a subset of HTML is supported (headings, paragraphs, lists, tables, images,
+// page headers & footers); CSS is limited to inline styles.
+func newNativePDF() driverFactory {
+	return &nativePDF{
+		def: DriverDefinition{
+			Name: "nativePDF",
+			InputTypes: []types.DocumentType{
+				types.DocumentTypePlain,
+				types.DocumentTypeHTML,
+			},
+			OutputTypes: []types.DocumentType{
+				types.DocumentTypePDF,
+			},
+		},
+	}
+}
+
+func (d *nativePDF) Define() DriverDefinition {
+	return d.def
+}
+
+func (d *nativePDF) CanRender(t types.DocumentType) bool {
+	for _, i := range d.def.InputTypes {
+		if i == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *nativePDF) CanProduce(t types.DocumentType) bool {
+	for _, o := range d.def.OutputTypes {
+		if o == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *nativePDF) Driver() driver {
+	return &nativePDFDriver{}
+}
+
+func (d *nativePDFDriver) Render(ctx context.Context, pl *driverPayload) (io.ReadSeeker, error) {
+	t, err := preprocHTMLTemplate(pl)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &bytes.Buffer{}
+	if err = t.Execute(src, pl.Variables); err != nil {
+		return nil, err
+	}
+
+	doc, err := html.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, err := d.newDocument(pl.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = newPdfWriter(pdf, pl.Attachments).write(doc); err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	if err = pdf.Output(out); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(out.Bytes()), nil
+}
+
+// newDocument prepares the PDF document based on the options
+//
+// Options are the same as the ones used by the Gotenberg driver;
+// all dimensions are in inches.
+func (d *nativePDFDriver) newDocument(opts map[string]string) (*fpdf.Fpdf, error) {
+	var (
+		err error
+
+		init = &fpdf.InitType{
+			OrientationStr: "P",
+			UnitStr:        "mm",
+			SizeStr:        "A4",
+		}
+
+		mTop, mBottom, mLeft, mRight = nativePDFDefaultMargin, nativePDFDefaultMargin, nativePDFDefaultMargin, nativePDFDefaultMargin
+	)
+
+	inches := func(k, v string) float64 {
+		if err != nil {
+			return 0
+		}
+
+		var f float64
+		if f, err = strconv.ParseFloat(v, 64); err != nil {
+			err = fmt.Errorf("invalid option %s: %w", k, err)
+		}
+
+		return f
+	}
+
+	for k, v := range opts {
+		switch k {
+		case "marginTop":
+			mTop = inches(k, v)
+		case "marginBottom":
+			mBottom = inches(k, v)
+		case "marginLeft":
+			mLeft = inches(k, v)
+		case "marginRight":
+			mRight = inches(k, v)
+
+		case "marginY":
+			mTop = inches(k, v)
+			mBottom = mTop
+
+		case "marginX":
+			mLeft = inches(k, v)
+			mRight = mLeft
+
+		case "margin":
+			mTop = inches(k, v)
+			mBottom, mLeft, mRight = mTop, mTop, mTop
+
+		case "documentSize":
+			w, h := documentDimensions(v)
+			if w+h != "" {
+				init.Size.Wd = inches(k, w) * mmPerInch
+				init.Size.Ht = inches(k, h) * mmPerInch
+			}
+
+		case "documentWidth":
+			init.Size.Wd = inches(k, v) * mmPerInch
+
+		case "documentHeight":
+			init.Size.Ht = inches(k, v) * mmPerInch
+
+		case "orientation":
+			if v == "landscape" {
+				init.OrientationStr = "L"
+			}
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if init.Size.Wd == 0 || init.Size.Ht == 0 {
+		// custom size is used only when both dimensions are known
+		init.Size = fpdf.SizeType{}
+	}
+
+	pdf := fpdf.NewCustom(init)
+	pdf.SetMargins(mLeft*mmPerInch, mTop*mmPerInch, mRight*mmPerInch)
+	pdf.SetAutoPageBreak(true, mBottom*mmPerInch)
+	pdf.AliasNbPages(pdfPageCountAlias)
+
+	return pdf, addPdfFonts(pdf)
+}
@@ -0,0 +1,149 @@
+package renderer
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig"
+)
+
+const (
+	// name of the function that escapes the output of template actions
+	officeEscapeFn = "_officeEscape"
+)
+
+var (
+	officeActionRegex = regexp.MustCompile(`(?s){{.*?}}`)
+
+	// control actions do not produce any output
+	officeControlActionRegex = regexp.MustCompile(`^{{-?\s*(if|else|end|range|with|break|continue)\b`)
+
+	// word processors like to use typographic quotes
+	officeQuoteReplacer = strings.NewReplacer("“", `"`, "”", `"`, "„", `"`, "‘", "'", "’", "'")
+)
+
+// preprocOfficeTemplate prepares the template for office documents (docx, xlsx)
+//
+// Output of all template actions (in the main template and in partials) is passed
+// through the escape function so that the values can not break the document structure.
+// Text of the partials is escaped the same way since partials are written as plain text.
+func preprocOfficeTemplate(src string, pp map[string]io.Reader, escape func(string) string) (*template.Template, error) {
+	const (
+		name = "office_render"
+	)
+
+	gtpl := template.New(name).
+		Funcs(sprig.TxtFuncMap()).
+		Funcs(template.FuncMap{
+			officeEscapeFn: func(args ...interface{}) string {
+				if len(args) == 0 || args[len(args)-1] == nil {
+					return ""
+				}
+
+				return escape(fmt.Sprint(args[len(args)-1]))
+			},
+		})
+
+	t, err := gtpl.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prep partials
+	for _, p := range pp {
+		bb, err := ioutil.ReadAll(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = gtpl.Parse(string(bb)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, at := range t.Templates() {
+		if at.Tree == nil || at.Tree.Root == nil {
+			continue
+		}
+
+		officeEscapeNode(at.Tree.Root, at.Name() != name, escape)
+	}
+
+	return t, nil
+}
+
+// officeEscapeNode walks the template tree and adds the escape function to all actions
+// that produce output
+func officeEscapeNode(n parse.Node, escapeText bool, escape func(string) string) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, c := range n.Nodes {
+			officeEscapeNode(c, escapeText, escape)
+		}
+
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			// variable declarations do not produce any output
+			return
+		}
+
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(officeEscapeFn).SetPos(n.Pos)},
+		})
+
+	case *parse.IfNode:
+		officeEscapeNode(n.List, escapeText, escape)
+		officeEscapeNode(n.ElseList, escapeText, escape)
+
+	case *parse.RangeNode:
+		officeEscapeNode(n.List, escapeText, escape)
+		officeEscapeNode(n.ElseList, escapeText, escape)
+
+	case *parse.WithNode:
+		officeEscapeNode(n.List, escapeText, escape)
+		officeEscapeNode(n.ElseList, escapeText, escape)
+
+	case *parse.TextNode:
+		if escapeText {
+			n.Text = []byte(escape(string(n.Text)))
+		}
+	}
+}
+
+// officeActionSource cleans up the action as written in the document
+//
+// Actions are stored as (XML escaped) text and can contain typographic quotes.
+func officeActionSource(a string) string {
+	return officeQuoteReplacer.Replace(html.UnescapeString(a))
+}
+
+// officeControlOnly checks if the text consists only of control actions (if, range, end, ...)
+//
+// Elements (paragraphs, table rows) with control actions only are replaced with the
+// actions themselves so that they can be used to repeat or omit the whole element.
+func officeControlOnly(text string) bool {
+	aa := officeActionRegex.FindAllString(text, -1)
+	if len(aa) == 0 {
+		return false
+	}
+
+	for _, a := range aa {
+		if !officeControlActionRegex.MatchString(officeActionSource(a)) {
+			return false
+		}
+	}
+
+	return strings.TrimSpace(officeActionRegex.ReplaceAllString(text, "")) == ""
+}
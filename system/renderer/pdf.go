@@ -0,0 +1,1184 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+type (
+	// pdfStyle is the text style of the node
+	pdfStyle struct {
+		family string
+		size   float64
+		align  string
+		color  [3]int
+
+		bold, italic, underline bool
+
+		// preserve whitespace
+		pre bool
+	}
+
+	// pdfRun is a piece of inline content with the same style
+	pdfRun struct {
+		style pdfStyle
+		text  string
+
+		// line break
+		br bool
+
+		// page number or page count placeholder
+		page string
+	}
+
+	pdfTableCell struct {
+		text    string
+		style   pdfStyle
+		colspan int
+		width   string
+		header  bool
+	}
+
+	pdfTableRow struct {
+		cells  []*pdfTableCell
+		header bool
+	}
+
+	// pdfWriter lays out the HTML document onto PDF pages
+	pdfWriter struct {
+		pdf *fpdf.Fpdf
+		att AttachmentIndex
+
+		// base left margin; lists indent the content by changing the left margin
+		left float64
+
+		// pending inline content of the current block
+		runs []pdfRun
+
+		// registered images by source
+		images map[string]*fpdf.ImageInfoType
+
+		err error
+	}
+)
+
+const (
+	// placeholder that is replaced with the number of pages
+	pdfPageCountAlias = "{nb}"
+
+	pdfBaseFontSize    = 11
+	pdfLineHeight      = 1.3
+	pdfListIndent      = 6.0
+	pdfCellPadding     = 1.5
+	pdfMillimeterPerPx = mmPerInch / 96
+)
+
+var (
+	pdfHeadingSizes = map[atom.Atom]float64{
+		atom.H1: 22,
+		atom.H2: 18,
+		atom.H3: 15,
+		atom.H4: 13,
+		atom.H5: 11,
+		atom.H6: 10,
+	}
+
+	// block elements flush the inline content before and after them
+	pdfBlockElements = map[atom.Atom]bool{
+		atom.P:          true,
+		atom.Div:        true,
+		atom.Section:    true,
+		atom.Article:    true,
+		atom.Main:       true,
+		atom.Nav:        true,
+		atom.Aside:      true,
+		atom.Header:     true,
+		atom.Footer:     true,
+		atom.Blockquote: true,
+		atom.Pre:        true,
+		atom.Address:    true,
+		atom.Center:     true,
+		atom.Figure:     true,
+		atom.Figcaption: true,
+		atom.Dl:         true,
+		atom.Dt:         true,
+		atom.Dd:         true,
+	}
+
+	pdfImageTypes = map[string]string{
+		"image/png":  "PNG",
+		"image/jpeg": "JPG",
+		"image/jpg":  "JPG",
+		"image/gif":  "GIF",
+		".png":       "PNG",
+		".jpg":       "JPG",
+		".jpeg":      "JPG",
+		".gif":       "GIF",
+	}
+)
+
+func newPdfWriter(pdf *fpdf.Fpdf, att AttachmentIndex) *pdfWriter {
+	left, _, _, _ := pdf.GetMargins()
+
+	return &pdfWriter{
+		pdf:    pdf,
+		att:    att,
+		left:   left,
+		images: make(map[string]*fpdf.ImageInfoType),
+	}
+}
+
+// write lays out the whole document
+//
+// The first header and footer elements are used as page header and footer
+// and are repeated on every page.
+func (w *pdfWriter) write(doc *html.Node) error {
+	var (
+		body = pdfFind(doc, atom.Body)
+		base = pdfStyle{family: pdfFontSans, size: pdfBaseFontSize, align: "L"}
+	)
+
+	if body == nil {
+		body = doc
+	}
+
+	if header := pdfFind(body, atom.Header); header != nil {
+		header.Parent.RemoveChild(header)
+		w.pdf.SetHeaderFunc(func() {
+			w.isolated(func() {
+				w.node(header, base)
+				w.pdf.Ln(w.lineHeight(base) / 2)
+			})
+		})
+	}
+
+	if footer := pdfFind(body, atom.Footer); footer != nil {
+		footer.Parent.RemoveChild(footer)
+		w.pdf.SetFooterFunc(func() {
+			_, _, _, bottom := w.pdf.GetMargins()
+			w.isolated(func() {
+				w.pdf.SetY(-bottom)
+				w.node(footer, base)
+			})
+		})
+	}
+
+	w.pdf.AddPage()
+	w.children(body, base)
+	w.flush()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	return w.pdf.Error()
+}
+
+// isolated renders the content (page header or footer) without
+// interfering with the pending inline content
+func (w *pdfWriter) isolated(fn func()) {
+	var (
+		runs          = w.runs
+		left, _, _, _ = w.pdf.GetMargins()
+	)
+
+	w.runs = nil
+	w.pdf.SetLeftMargin(w.left)
+	w.pdf.SetX(w.left)
+
+	fn()
+	w.flush()
+
+	w.runs = runs
+	w.pdf.SetLeftMargin(left)
+}
+
+func (w *pdfWriter) children(n *html.Node, st pdfStyle) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.node(c, st)
+	}
+}
+
+func (w *pdfWriter) node(n *html.Node, st pdfStyle) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data, st)
+		return
+	case html.ElementNode:
+		// handled below
+	default:
+		w.children(n, st)
+		return
+	}
+
+	st = pdfNodeStyle(n, st)
+
+	if pdfPageBreak(n, "before") {
+		w.flush()
+		w.pdf.AddPage()
+	}
+
+	switch a := n.DataAtom; {
+	case a == atom.Head, a == atom.Script, a == atom.Style, a == atom.Title:
+		return
+
+	case a == atom.Br:
+		w.runs = append(w.runs, pdfRun{style: st, br: true})
+
+	case a == atom.Span && pdfHasClass(n, "page-number"):
+		w.runs = append(w.runs, pdfRun{style: st, page: "number"})
+
+	case a == atom.Span && pdfHasClass(n, "page-count"):
+		w.runs = append(w.runs, pdfRun{style: st, page: "count"})
+
+	case a == atom.Img:
+		w.flush()
+		w.image(n, st)
+
+	case a == atom.Hr:
+		w.flush()
+		w.hr()
+
+	case a == atom.Table:
+		w.flush()
+		w.table(n, st)
+
+	case a == atom.Ul, a == atom.Ol:
+		w.flush()
+		w.list(n, st)
+
+	case pdfHeadingSizes[a] > 0:
+		w.flush()
+		w.pdf.Ln(w.lineHeight(st) / 3)
+		w.children(n, st)
+		if w.flush() {
+			w.pdf.Ln(w.lineHeight(st) / 3)
+		}
+
+	case a == atom.P:
+		w.flush()
+		w.children(n, st)
+		if w.flush() {
+			w.pdf.Ln(w.lineHeight(st) / 2)
+		}
+
+	case pdfBlockElements[a]:
+		w.flush()
+		w.children(n, st)
+		w.flush()
+
+	default:
+		w.children(n, st)
+	}
+
+	if pdfPageBreak(n, "after") {
+		w.flush()
+		w.pdf.AddPage()
+	}
+}
+
+// text adds the text to the pending inline content
+func (w *pdfWriter) text(s string, st pdfStyle) {
+	if !st.pre {
+		s = pdfCollapseSpace(s)
+	}
+
+	if s == "" {
+		return
+	}
+
+	w.runs = append(w.runs, pdfRun{style: st, text: s})
+}
+
+// flush writes the pending inline content as a block of text
+//
+// Returns true if anything was written
+func (w *pdfWriter) flush() bool {
+	runs := w.runs
+	w.runs = nil
+
+	// leading and trailing whitespace is not rendered
+	for len(runs) > 0 && pdfBlankRun(runs[0]) {
+		runs = runs[1:]
+	}
+
+	for len(runs) > 0 && pdfBlankRun(runs[len(runs)-1]) {
+		runs = runs[:len(runs)-1]
+	}
+
+	if len(runs) == 0 {
+		return false
+	}
+
+	if !runs[0].style.pre {
+		runs[0].text = strings.TrimLeft(runs[0].text, " ")
+	}
+
+	if l := len(runs) - 1; !runs[l].style.pre {
+		runs[l].text = strings.TrimRight(runs[l].text, " ")
+	}
+
+	var (
+		left, _, _, _ = w.pdf.GetMargins()
+		align         = runs[0].style.align
+		lh            = w.lineHeight(runs[0].style)
+	)
+
+	w.pdf.SetX(left)
+
+	if align == "C" || align == "R" || align == "J" {
+		// mixed styles are not supported with aligned text;
+		// block is written with the style of the first run
+		text := &strings.Builder{}
+		for _, r := range runs {
+			text.WriteString(w.runText(r))
+		}
+
+		w.setFont(runs[0].style)
+		w.pdf.MultiCell(0, lh, text.String(), "", align, false)
+		return true
+	}
+
+	for _, r := range runs {
+		if h := w.lineHeight(r.style); h > lh {
+			lh = h
+		}
+	}
+
+	for _, r := range runs {
+		if r.br {
+			w.pdf.Ln(lh)
+			continue
+		}
+
+		w.setFont(r.style)
+		w.pdf.Write(lh, w.runText(r))
+	}
+
+	w.pdf.Ln(lh)
+	return true
+}
+
+func (w *pdfWriter) runText(r pdfRun) string {
+	switch {
+	case r.br:
+		return "\n"
+	case r.page == "number":
+		return strconv.Itoa(w.pdf.PageNo())
+	case r.page == "count":
+		return pdfPageCountAlias
+	}
+
+	return r.text
+}
+
+func (w *pdfWriter) setFont(st pdfStyle) {
+	var style string
+	if st.bold {
+		style += "B"
+	}
+	if st.italic {
+		style += "I"
+	}
+	if st.underline {
+		style += "U"
+	}
+
+	w.pdf.SetFont(st.family, style, st.size)
+	w.pdf.SetTextColor(st.color[0], st.color[1], st.color[2])
+}
+
+func (w *pdfWriter) lineHeight(st pdfStyle) float64 {
+	return w.pdf.PointConvert(st.size) * pdfLineHeight
+}
+
+// contentWidth returns the width between the current margins
+func (w *pdfWriter) contentWidth() float64 {
+	var (
+		pw, _             = w.pdf.GetPageSize()
+		left, _, right, _ = w.pdf.GetMargins()
+	)
+
+	return pw - left - right
+}
+
+// fits checks if the content of the given height fits on the current page
+func (w *pdfWriter) fits(h float64) bool {
+	var (
+		_, ph        = w.pdf.GetPageSize()
+		_, top, _, _ = w.pdf.GetMargins()
+		_, bottom    = w.pdf.GetAutoPageBreak()
+		y            = w.pdf.GetY()
+	)
+
+	// content that is larger than the page does not fit anywhere
+	return y+h <= ph-bottom || y <= top
+}
+
+func (w *pdfWriter) hr() {
+	var (
+		left, _, _, _ = w.pdf.GetMargins()
+		y             = w.pdf.GetY() + 1
+	)
+
+	w.pdf.SetDrawColor(160, 160, 160)
+	w.pdf.Line(left, y, left+w.contentWidth(), y)
+	w.pdf.SetDrawColor(0, 0, 0)
+	w.pdf.SetY(y + 2)
+}
+
+// list writes list items with bullets or numbers
+func (w *pdfWriter) list(n *html.Node, st pdfStyle) {
+	var (
+		left, _, _, _ = w.pdf.GetMargins()
+		i             = 0
+		lh            = w.lineHeight(st)
+	)
+
+	if start, err := strconv.Atoi(pdfAttr(n, "start")); err == nil {
+		i = start - 1
+	}
+
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+
+		i++
+		marker := "•"
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(i) + "."
+		}
+
+		if !w.fits(lh) {
+			w.pdf.AddPage()
+		}
+
+		w.pdf.SetLeftMargin(left)
+		w.pdf.SetX(left)
+		w.setFont(st)
+		w.pdf.CellFormat(pdfListIndent, lh, marker, "", 0, "L", false, 0, "")
+
+		w.pdf.SetLeftMargin(left + pdfListIndent)
+		w.children(li, pdfNodeStyle(li, st))
+		if !w.flush() && w.pdf.GetX() > left+pdfListIndent-0.01 {
+			// empty item
+			w.pdf.Ln(lh)
+		}
+	}
+
+	w.pdf.SetLeftMargin(left)
+	w.pdf.SetX(left)
+
+	if n.Parent == nil || n.Parent.DataAtom != atom.Li {
+		w.pdf.Ln(lh / 2)
+	}
+}
+
+// image writes the image from the attachment index or from the data URI
+//
+// Images are scaled by the width and height attributes (in pixels) and
+// scaled down to fit the page width.
+func (w *pdfWriter) image(n *html.Node, st pdfStyle) {
+	src := pdfAttr(n, "src")
+	info, err := w.loadImage(src)
+	if err != nil {
+		if w.err == nil {
+			w.err = err
+		}
+		return
+	}
+
+	var (
+		wd, ht = info.Extent()
+		max    = w.contentWidth()
+
+		left, _, _, _ = w.pdf.GetMargins()
+		x             = left
+
+		aw, _ = strconv.ParseFloat(strings.TrimSuffix(pdfAttr(n, "width"), "px"), 64)
+		ah, _ = strconv.ParseFloat(strings.TrimSuffix(pdfAttr(n, "height"), "px"), 64)
+	)
+
+	switch {
+	case aw > 0 && ah > 0:
+		wd, ht = aw*pdfMillimeterPerPx, ah*pdfMillimeterPerPx
+	case aw > 0:
+		wd, ht = aw*pdfMillimeterPerPx, ht*aw*pdfMillimeterPerPx/wd
+	case ah > 0:
+		wd, ht = wd*ah*pdfMillimeterPerPx/ht, ah*pdfMillimeterPerPx
+	}
+
+	if wd > max {
+		wd, ht = max, ht*max/wd
+	}
+
+	switch st.align {
+	case "C":
+		x += (max - wd) / 2
+	case "R":
+		x += max - wd
+	}
+
+	if !w.fits(ht) {
+		w.pdf.AddPage()
+	}
+
+	y := w.pdf.GetY()
+	w.pdf.ImageOptions(src, x, y, wd, ht, false, fpdf.ImageOptions{}, 0, "")
+	w.pdf.SetY(y + ht + 1)
+}
+
+// loadImage registers the image with the document
+//
+// Image source can be a name of the attachment or a base64 encoded data URI
+func (w *pdfWriter) loadImage(src string) (*fpdf.ImageInfoType, error) {
+	if info, ok := w.images[src]; ok {
+		return info, nil
+	}
+
+	var (
+		bb  []byte
+		typ string
+		err error
+	)
+
+	if strings.HasPrefix(src, "data:") {
+		meta, data := src, ""
+		if p := strings.Index(src, ","); p > 0 {
+			meta, data = src[5:p], src[p+1:]
+		}
+
+		if !strings.HasSuffix(meta, ";base64") {
+			return nil, fmt.Errorf("unsupported image data URI")
+		}
+
+		typ = pdfImageTypes[strings.TrimSuffix(meta, ";base64")]
+		if bb, err = base64.StdEncoding.DecodeString(data); err != nil {
+			return nil, fmt.Errorf("invalid image data URI: %w", err)
+		}
+	} else {
+		a := w.att[strings.TrimPrefix(src, "attachment:")]
+		if a == nil || a.Source == nil {
+			return nil, fmt.Errorf("image attachment not found: %s", src)
+		}
+
+		if typ = pdfImageTypes[a.Mime]; typ == "" {
+			typ = pdfImageTypes[strings.ToLower(path.Ext(a.Name))]
+		}
+
+		if bb, err = ioutil.ReadAll(a.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	if typ == "" {
+		return nil, fmt.Errorf("unsupported image type: %s", pdfShorten(src))
+	}
+
+	info := w.pdf.RegisterImageOptionsReader(src, fpdf.ImageOptions{ImageType: typ}, bytes.NewReader(bb))
+	if err = w.pdf.Error(); err != nil {
+		return nil, fmt.Errorf("could not load image %s: %w", pdfShorten(src), err)
+	}
+
+	w.images[src] = info
+	return info, nil
+}
+
+// table writes the table; header rows are repeated on each page
+func (w *pdfWriter) table(n *html.Node, st pdfStyle) {
+	var (
+		rows   = pdfTableRows(n, st)
+		cols   = 0
+		border = pdfAttr(n, "border") != "0"
+
+		left, _, _, _ = w.pdf.GetMargins()
+		cw            = w.contentWidth()
+	)
+
+	for _, r := range rows {
+		c := 0
+		for _, cell := range r.cells {
+			c += cell.colspan
+		}
+
+		if c > cols {
+			cols = c
+		}
+	}
+
+	if cols == 0 {
+		return
+	}
+
+	if tw := pdfLength(pdfAttr(n, "width"), cw); tw > 0 && tw < cw {
+		cw = tw
+	}
+
+	var (
+		widths = pdfColumnWidths(rows, cols, cw)
+		header = make([]*pdfTableRow, 0, 1)
+
+		autoBreak, bottom = w.pdf.GetAutoPageBreak()
+	)
+
+	// rows are positioned manually
+	w.pdf.SetAutoPageBreak(false, bottom)
+	defer w.pdf.SetAutoPageBreak(autoBreak, bottom)
+
+	for i, r := range rows {
+		if r.header && len(header) == i {
+			header = append(header, r)
+		}
+
+		h := w.rowHeight(r, widths)
+		if !w.fits(h) {
+			w.pdf.AddPage()
+
+			if !r.header {
+				for _, hr := range header {
+					w.row(hr, widths, left, w.rowHeight(hr, widths), border)
+				}
+			}
+		}
+
+		w.row(r, widths, left, h, border)
+	}
+
+	w.pdf.SetX(left)
+	w.pdf.Ln(w.lineHeight(st) / 2)
+}
+
+func (w *pdfWriter) rowHeight(r *pdfTableRow, widths []float64) float64 {
+	var (
+		h   float64
+		col = 0
+	)
+
+	for _, c := range r.cells {
+		cw := pdfSpanWidth(widths, col, c.colspan)
+		col += c.colspan
+
+		w.setFont(c.style)
+		lines := w.lineCount(c.text, cw-2*pdfCellPadding)
+
+		if ch := float64(lines)*w.lineHeight(c.style) + 2*pdfCellPadding; ch > h {
+			h = ch
+		}
+	}
+
+	return h
+}
+
+// lineCount returns number of lines the text wraps into
+// when written with MultiCell of the given width
+//
+// Unlike SplitLines it measures runes and not bytes and works
+// with UTF-8 fonts.
+func (w *pdfWriter) lineCount(text string, width float64) (n int) {
+	width -= 2 * w.pdf.GetCellMargin()
+
+	for _, p := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		var line string
+
+		n++
+		for _, word := range strings.Fields(p) {
+			if line != "" && w.pdf.GetStringWidth(line+" "+word) <= width {
+				line += " " + word
+				continue
+			}
+
+			if line != "" {
+				n++
+			}
+
+			// words wider than the cell are broken
+			line = ""
+			for _, r := range word {
+				if line != "" && w.pdf.GetStringWidth(line+string(r)) > width {
+					n++
+					line = ""
+				}
+
+				line += string(r)
+			}
+		}
+	}
+
+	return
+}
+
+func (w *pdfWriter) row(r *pdfTableRow, widths []float64, x, h float64, border bool) {
+	var (
+		y   = w.pdf.GetY()
+		col = 0
+	)
+
+	w.pdf.SetDrawColor(160, 160, 160)
+	w.pdf.SetFillColor(235, 235, 235)
+
+	for _, c := range r.cells {
+		cw := pdfSpanWidth(widths, col, c.colspan)
+		col += c.colspan
+
+		switch {
+		case border && c.header:
+			w.pdf.Rect(x, y, cw, h, "FD")
+		case border:
+			w.pdf.Rect(x, y, cw, h, "D")
+		}
+
+		align := c.style.align
+		if align == "J" {
+			align = "L"
+		}
+
+		w.setFont(c.style)
+		w.pdf.SetXY(x+pdfCellPadding, y+pdfCellPadding)
+		w.pdf.MultiCell(cw-2*pdfCellPadding, w.lineHeight(c.style), c.text, "", align, false)
+
+		x += cw
+	}
+
+	w.pdf.SetDrawColor(0, 0, 0)
+	w.pdf.SetY(y + h)
+}
+
+// pdfTableRows collects rows of the table, skipping nested tables
+func pdfTableRows(n *html.Node, st pdfStyle) []*pdfTableRow {
+	rows := make([]*pdfTableRow, 0, 16)
+
+	var walk func(*html.Node, bool)
+	walk = func(n *html.Node, header bool) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+
+			switch c.DataAtom {
+			case atom.Thead:
+				walk(c, true)
+			case atom.Tbody, atom.Tfoot:
+				walk(c, false)
+
+			case atom.Tr:
+				r := &pdfTableRow{header: header}
+				rst := pdfNodeStyle(c, st)
+				all := true
+
+				for td := c.FirstChild; td != nil; td = td.NextSibling {
+					if td.Type != html.ElementNode || (td.DataAtom != atom.Td && td.DataAtom != atom.Th) {
+						continue
+					}
+
+					cell := &pdfTableCell{
+						style:  pdfNodeStyle(td, rst),
+						width:  pdfAttr(td, "width"),
+						header: td.DataAtom == atom.Th,
+					}
+
+					if cell.colspan, _ = strconv.Atoi(pdfAttr(td, "colspan")); cell.colspan < 1 {
+						cell.colspan = 1
+					}
+
+					if w := pdfStyleProp(td, "width"); w != "" {
+						cell.width = w
+					}
+
+					cell.text = pdfText(td, cell.style.pre)
+					all = all && cell.header
+					r.cells = append(r.cells, cell)
+				}
+
+				r.header = r.header || (all && len(r.cells) > 0)
+				rows = append(rows, r)
+			}
+		}
+	}
+
+	walk(n, false)
+	return rows
+}
+
+// pdfColumnWidths calculates column widths from the first row that defines them;
+// remaining width is split equally between the other columns
+func pdfColumnWidths(rows []*pdfTableRow, cols int, total float64) []float64 {
+	var (
+		widths = make([]float64, cols)
+		used   float64
+		free   = cols
+	)
+
+	for _, r := range rows {
+		col, found := 0, false
+		for _, c := range r.cells {
+			if c.colspan == 1 && c.width != "" {
+				if widths[col] = pdfLength(c.width, total); widths[col] > 0 {
+					found = true
+					used += widths[col]
+					free--
+				}
+			}
+
+			col += c.colspan
+		}
+
+		if found {
+			break
+		}
+	}
+
+	rest := 0.0
+	if free > 0 && used < total {
+		rest = (total - used) / float64(free)
+	}
+
+	for i := range widths {
+		if widths[i] == 0 {
+			widths[i] = rest
+		}
+	}
+
+	if used > total {
+		// scale down to fit
+		for i := range widths {
+			widths[i] = widths[i] * total / used
+		}
+	}
+
+	return widths
+}
+
+func pdfSpanWidth(widths []float64, col, span int) (w float64) {
+	for i := col; i < col+span && i < len(widths); i++ {
+		w += widths[i]
+	}
+
+	return
+}
+
+// pdfNodeStyle applies the element's default style and its inline style
+func pdfNodeStyle(n *html.Node, st pdfStyle) pdfStyle {
+	switch n.DataAtom {
+	case atom.B, atom.Strong, atom.Th:
+		st.bold = true
+	case atom.I, atom.Em, atom.Cite, atom.Var:
+		st.italic = true
+	case atom.U, atom.Ins:
+		st.underline = true
+	case atom.A:
+		st.underline = true
+		st.color = [3]int{0, 0, 238}
+	case atom.Code, atom.Kbd, atom.Samp, atom.Tt:
+		st.family = pdfFontMono
+	case atom.Pre:
+		st.family = pdfFontMono
+		st.pre = true
+	case atom.Small:
+		st.size *= 0.85
+	case atom.Center:
+		st.align = "C"
+	}
+
+	if s := pdfHeadingSizes[n.DataAtom]; s > 0 {
+		st.bold = true
+		st.size = s
+	}
+
+	if a := pdfAlign(pdfAttr(n, "align")); a != "" {
+		st.align = a
+	}
+
+	for _, d := range strings.Split(pdfAttr(n, "style"), ";") {
+		kv := strings.SplitN(d, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		var (
+			k = strings.ToLower(strings.TrimSpace(kv[0]))
+			v = strings.ToLower(strings.TrimSpace(kv[1]))
+		)
+
+		switch k {
+		case "text-align":
+			if a := pdfAlign(v); a != "" {
+				st.align = a
+			}
+
+		case "font-weight":
+			w, _ := strconv.Atoi(v)
+			st.bold = v == "bold" || v == "bolder" || w >= 600
+
+		case "font-style":
+			st.italic = v == "italic" || v == "oblique"
+
+		case "text-decoration":
+			st.underline = strings.Contains(v, "underline")
+
+		case "font-size":
+			if s := pdfFontSize(v, st.size); s > 0 {
+				st.size = s
+			}
+
+		case "font-family":
+			switch {
+			case strings.Contains(v, "mono"), strings.Contains(v, "courier"):
+				st.family = pdfFontMono
+			case strings.Contains(v, "sans"), strings.Contains(v, "helvetica"), strings.Contains(v, "arial"):
+				st.family = pdfFontSans
+			case strings.Contains(v, "serif"), strings.Contains(v, "times"):
+				st.family = pdfFontSerif
+			}
+
+		case "color":
+			if c, ok := pdfColor(v); ok {
+				st.color = c
+			}
+
+		case "white-space":
+			st.pre = strings.HasPrefix(v, "pre")
+		}
+	}
+
+	return st
+}
+
+func pdfAlign(v string) string {
+	switch strings.ToLower(v) {
+	case "left":
+		return "L"
+	case "center":
+		return "C"
+	case "right":
+		return "R"
+	case "justify":
+		return "J"
+	}
+
+	return ""
+}
+
+// pdfFontSize converts the CSS font size to points
+func pdfFontSize(v string, current float64) float64 {
+	var (
+		f   float64
+		err error
+	)
+
+	switch {
+	case strings.HasSuffix(v, "pt"):
+		f, err = strconv.ParseFloat(strings.TrimSuffix(v, "pt"), 64)
+	case strings.HasSuffix(v, "px"):
+		f, err = strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64)
+		f *= 0.75
+	case strings.HasSuffix(v, "rem"):
+		f, err = strconv.ParseFloat(strings.TrimSuffix(v, "rem"), 64)
+		f *= pdfBaseFontSize
+	case strings.HasSuffix(v, "em"):
+		f, err = strconv.ParseFloat(strings.TrimSuffix(v, "em"), 64)
+		f *= current
+	case strings.HasSuffix(v, "%"):
+		f, err = strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		f *= current / 100
+	}
+
+	if err != nil {
+		return 0
+	}
+
+	return f
+}
+
+// pdfLength converts the HTML length (pixels or percentage of the total) to millimeters
+func pdfLength(v string, total float64) float64 {
+	v = strings.TrimSpace(v)
+	if strings.HasSuffix(v, "%") {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		if err != nil {
+			return 0
+		}
+
+		return total * f / 100
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "px"), 64)
+	if err != nil {
+		return 0
+	}
+
+	return f * pdfMillimeterPerPx
+}
+
+// pdfColor parses #rgb and #rrggbb colors
+func pdfColor(v string) (c [3]int, ok bool) {
+	v = strings.TrimPrefix(v, "#")
+	if len(v) == 3 {
+		v = string([]byte{v[0], v[0], v[1], v[1], v[2], v[2]})
+	}
+
+	if len(v) != 6 {
+		return c, false
+	}
+
+	for i := range c {
+		n, err := strconv.ParseUint(v[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return c, false
+		}
+
+		c[i] = int(n)
+	}
+
+	return c, true
+}
+
+// pdfPageBreak checks for page-break-before/after (or break-before/after) in the inline style
+func pdfPageBreak(n *html.Node, where string) bool {
+	for _, k := range []string{"page-break-" + where, "break-" + where} {
+		switch pdfStyleProp(n, k) {
+		case "always", "page":
+			return true
+		}
+	}
+
+	return false
+}
+
+// pdfStyleProp returns the value of the property from the inline style
+func pdfStyleProp(n *html.Node, prop string) string {
+	for _, d := range strings.Split(pdfAttr(n, "style"), ";") {
+		kv := strings.SplitN(d, ":", 2)
+		if len(kv) == 2 && strings.EqualFold(strings.TrimSpace(kv[0]), prop) {
+			return strings.ToLower(strings.TrimSpace(kv[1]))
+		}
+	}
+
+	return ""
+}
+
+func pdfAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func pdfHasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(pdfAttr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pdfFind returns the first element with the given tag
+func pdfFind(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if f := pdfFind(c, a); f != nil {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// pdfText returns the text content of the node; line breaks and
+// block elements are converted to new lines
+func pdfText(n *html.Node, pre bool) string {
+	out := &strings.Builder{}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			switch {
+			case c.Type == html.TextNode && pre:
+				out.WriteString(c.Data)
+			case c.Type == html.TextNode:
+				out.WriteString(pdfCollapseSpace(c.Data))
+			case c.Type != html.ElementNode:
+				continue
+			case c.DataAtom == atom.Br:
+				out.WriteString("\n")
+			case c.DataAtom == atom.Script, c.DataAtom == atom.Style:
+				continue
+			case pdfBlockElements[c.DataAtom], c.DataAtom == atom.Li:
+				if out.Len() > 0 {
+					out.WriteString("\n")
+				}
+				walk(c)
+			default:
+				walk(c)
+			}
+		}
+	}
+
+	walk(n)
+
+	lines := strings.Split(out.String(), "\n")
+	if !pre {
+		for i := range lines {
+			lines[i] = strings.TrimSpace(lines[i])
+		}
+	}
+
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// pdfCollapseSpace replaces sequences of whitespace with a single space
+func pdfCollapseSpace(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	var (
+		out   = strings.Fields(s)
+		lead  = strings.TrimLeft(s, " \t\r\n\f") != s
+		trail = strings.TrimRight(s, " \t\r\n\f") != s
+	)
+
+	if len(out) == 0 {
+		return " "
+	}
+
+	r := strings.Join(out, " ")
+	if lead {
+		r = " " + r
+	}
+	if trail {
+		r += " "
+	}
+
+	return r
+}
+
+func pdfBlankRun(r pdfRun) bool {
+	return !r.br && r.page == "" && !r.style.pre && strings.TrimSpace(r.text) == ""
+}
+
+// pdfShorten shortens long image sources (data URIs) for error messages
+func pdfShorten(s string) string {
+	if len(s) > 40 {
+		return s[:40] + "..."
+	}
+
+	return s
+}
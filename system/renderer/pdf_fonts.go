@@ -0,0 +1,62 @@
+package renderer
+
+import (
+	"embed"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfFontSans  = "sans"
+	pdfFontMono  = "mono"
+	pdfFontSerif = "serif"
+)
+
+var (
+	//go:embed assets/fonts/*.ttf
+	pdfFonts embed.FS
+
+	// font files by family and style
+	//
+	// There are no italic variants; italic text is rendered
+	// with the upright font.
+	pdfFontFiles = map[string]map[string]string{
+		pdfFontSans: {
+			"":   "DejaVuSans.ttf",
+			"B":  "DejaVuSans-Bold.ttf",
+			"I":  "DejaVuSans.ttf",
+			"BI": "DejaVuSans-Bold.ttf",
+		},
+		pdfFontMono: {
+			"":   "DejaVuSansMono.ttf",
+			"B":  "DejaVuSansMono-Bold.ttf",
+			"I":  "DejaVuSansMono.ttf",
+			"BI": "DejaVuSansMono-Bold.ttf",
+		},
+		pdfFontSerif: {
+			"":   "DejaVuSerif.ttf",
+			"B":  "DejaVuSerif-Bold.ttf",
+			"I":  "DejaVuSerif.ttf",
+			"BI": "DejaVuSerif-Bold.ttf",
+		},
+	}
+)
+
+// addPdfFonts registers embedded UTF-8 fonts with the document
+//
+// Core PDF fonts support only cp1252 so any text outside of it
+// (Cyrillic, Greek, CJK...) would not be rendered.
+func addPdfFonts(pdf *fpdf.Fpdf) error {
+	for family, styles := range pdfFontFiles {
+		for style, file := range styles {
+			bb, err := pdfFonts.ReadFile("assets/fonts/" + file)
+			if err != nil {
+				return err
+			}
+
+			pdf.AddUTF8FontFromBytes(family, style, bb)
+		}
+	}
+
+	return pdf.Error()
+}
@@ -15,12 +15,15 @@ type (
 )
 
 func Renderer(cfg options.TemplateOpt) *renderer {
-	ff := make([]driverFactory, 0, 3)
-	ff = append(ff, newGenericText(), newGenericHTML())
+	ff := make([]driverFactory, 0, 6)
+	ff = append(ff, newGenericText(), newGenericHTML(), newGenericDocx(), newGenericXlsx())
 	if cfg.RendererGotenbergEnabled {
 		ff = append(ff, newGotenbergPDF(cfg.RendererGotenbergAddress))
 	}
 
+	// fallback for PDF rendering when Gotenberg is not available
+	ff = append(ff, newNativePDF())
+
 	return &renderer{
 		factories: ff,
 	}
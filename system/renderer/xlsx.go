@@ -0,0 +1,212 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type (
+	// xlsxRow is a rendered row
+	xlsxRow struct {
+		// template row the rendered row is based on
+		src int
+
+		// rendered cell values by column
+		cells map[int]string
+	}
+)
+
+const (
+	// separators used in the intermediate template output
+	xlsxRowSep   = "\x1e"
+	xlsxCellSep  = "\x1f"
+	xlsxValueSep = "\x1d"
+)
+
+var (
+	xlsxSepReplacer = strings.NewReplacer(xlsxRowSep, "", xlsxCellSep, "", xlsxValueSep, "")
+)
+
+// renderXlsx renders all of the sheets of the xlsx template
+//
+// Rows with control actions only (if, range, end, ...) are used to repeat or
+// omit the rows between them; styles of the repeated rows are preserved.
+// Cells that consist of a single action and render into a number are stored as numbers.
+func renderXlsx(tpl io.Reader, pp map[string]io.Reader, vars map[string]interface{}) ([]byte, error) {
+	f, err := excelize.OpenReader(tpl)
+	if err != nil {
+		return nil, fmt.Errorf("could not open xlsx template: %w", err)
+	}
+
+	// partials are used by each of the sheets
+	partials := make(map[string][]byte)
+	for h, p := range pp {
+		buf := &bytes.Buffer{}
+		if _, err = buf.ReadFrom(p); err != nil {
+			return nil, err
+		}
+		partials[h] = buf.Bytes()
+	}
+
+	for _, sheet := range f.GetSheetList() {
+		if err = renderXlsxSheet(f, sheet, partials, vars); err != nil {
+			return nil, fmt.Errorf("could not render sheet %s: %w", sheet, err)
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderXlsxSheet(f *excelize.File, sheet string, partials map[string][]byte, vars map[string]interface{}) error {
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return err
+	}
+
+	var (
+		src = &strings.Builder{}
+		has bool
+
+		// cells that are stored as numbers when possible
+		single = make(map[string]bool)
+	)
+
+	for _, row := range rows {
+		if has = strings.Contains(strings.Join(row, ""), "{{"); has {
+			break
+		}
+	}
+
+	if !has {
+		// nothing to render
+		return nil
+	}
+
+	// Build the template; each row is prefixed with its number
+	// and each cell with its column so we can reconstruct the sheet
+	for i, row := range rows {
+		text := strings.Join(row, "")
+		if officeControlOnly(text) {
+			for _, a := range officeActionRegex.FindAllString(text, -1) {
+				src.WriteString(officeActionSource(a))
+			}
+
+			continue
+		}
+
+		src.WriteString(xlsxRowSep + strconv.Itoa(i+1))
+		for c, v := range row {
+			if !strings.Contains(v, "{{") {
+				continue
+			}
+
+			v = officeActionRegex.ReplaceAllStringFunc(v, officeActionSource)
+			if aa := officeActionRegex.FindAllString(v, -1); len(aa) == 1 && aa[0] == strings.TrimSpace(v) {
+				single[fmt.Sprintf("%d:%d", i+1, c+1)] = true
+			}
+
+			src.WriteString(xlsxCellSep + strconv.Itoa(c+1) + xlsxValueSep + v)
+		}
+	}
+
+	pp := make(map[string]io.Reader)
+	for h, p := range partials {
+		pp[h] = bytes.NewReader(p)
+	}
+
+	t, err := preprocOfficeTemplate(src.String(), pp, xlsxSepReplacer.Replace)
+	if err != nil {
+		return err
+	}
+
+	out := &strings.Builder{}
+	if err = t.Execute(out, vars); err != nil {
+		return err
+	}
+
+	rr, err := xlsxParseRows(out.String())
+	if err != nil {
+		return err
+	}
+
+	// Rendered rows are placed after the template rows...
+	for i, r := range rr {
+		dst := len(rows) + i + 1
+		if err = f.DuplicateRowTo(sheet, r.src, dst); err != nil {
+			return err
+		}
+
+		for c, v := range r.cells {
+			axis, err := excelize.CoordinatesToCellName(c, dst)
+			if err != nil {
+				return err
+			}
+
+			if n, nErr := strconv.ParseFloat(v, 64); nErr == nil && single[fmt.Sprintf("%d:%d", r.src, c)] {
+				err = f.SetCellValue(sheet, axis, n)
+			} else {
+				err = f.SetCellStr(sheet, axis, v)
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// ... and template rows are removed
+	for range rows {
+		if err = f.RemoveRow(sheet, 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// xlsxParseRows parses the intermediate template output into rows
+func xlsxParseRows(out string) ([]*xlsxRow, error) {
+	var (
+		parts = strings.Split(out, xlsxRowSep)
+		rr    = make([]*xlsxRow, 0, len(parts))
+	)
+
+	// first part holds whatever was rendered before the first row
+	for _, p := range parts[1:] {
+		cc := strings.Split(p, xlsxCellSep)
+
+		src, err := strconv.Atoi(strings.TrimSpace(cc[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid row reference: %w", err)
+		}
+
+		r := &xlsxRow{src: src, cells: make(map[int]string)}
+		for _, c := range cc[1:] {
+			kv := strings.SplitN(c, xlsxValueSep, 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid cell in row %d", src)
+			}
+
+			col, err := strconv.Atoi(kv[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid cell reference in row %d: %w", src, err)
+			}
+
+			r.cells[col] = kv[1]
+		}
+
+		rr = append(rr, r)
+	}
+
+	return rr, nil
+}
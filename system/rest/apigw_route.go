@@ -31,6 +31,7 @@ type (
 		Update(ctx context.Context, upd *types.ApigwRoute) (*types.ApigwRoute, error)
 		DeleteByID(ctx context.Context, ID uint64) error
 		UndeleteByID(ctx context.Context, ID uint64) error
+		PurgeCache(ctx context.Context, ID uint64) error
 		Search(ctx context.Context, filter types.ApigwRouteFilter) (types.ApigwRouteSet, types.ApigwRouteFilter, error)
 	}
 )
@@ -111,6 +112,10 @@ func (ctrl *ApigwRoute) Undelete(ctx context.Context, r *request.ApigwRouteUndel
 	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.RouteID)
 }
 
+func (ctrl *ApigwRoute) PurgeCache(ctx context.Context, r *request.ApigwRoutePurgeCache) (interface{}, error) {
+	return api.OK(), ctrl.svc.PurgeCache(ctx, r.RouteID)
+}
+
 func (ctrl *ApigwRoute) makePayload(ctx context.Context, q *types.ApigwRoute, err error) (*routePayload, error) {
 	if err != nil || q == nil {
 		return nil, err
@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/api"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/system/rest/request"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	ApigwSecret struct {
+		svc apigwSecretService
+	}
+
+	// secret value (plain or encrypted) is never returned
+	apigwSecretSetPayload struct {
+		Filter types.ApigwSecretFilter `json:"filter"`
+		Set    types.ApigwSecretSet    `json:"set"`
+	}
+
+	apigwSecretRotateKeysPayload struct {
+		Rotated int `json:"rotated"`
+	}
+
+	apigwSecretService interface {
+		FindByID(ctx context.Context, ID uint64) (*types.ApigwSecret, error)
+		Search(ctx context.Context, filter types.ApigwSecretFilter) (types.ApigwSecretSet, types.ApigwSecretFilter, error)
+		Create(ctx context.Context, new *types.ApigwSecret) (*types.ApigwSecret, error)
+		Update(ctx context.Context, upd *types.ApigwSecret) (*types.ApigwSecret, error)
+		DeleteByID(ctx context.Context, ID uint64) error
+		UndeleteByID(ctx context.Context, ID uint64) error
+		RotateKeys(ctx context.Context) (int, error)
+	}
+)
+
+func (ApigwSecret) New() *ApigwSecret {
+	return &ApigwSecret{
+		svc: service.DefaultApigwSecret,
+	}
+}
+
+func (ctrl *ApigwSecret) List(ctx context.Context, r *request.ApigwSecretList) (interface{}, error) {
+	var (
+		err error
+		f   = types.ApigwSecretFilter{
+			Query:   r.Query,
+			Handle:  r.Handle,
+			Deleted: filter.State(r.Deleted),
+		}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, f, err := ctrl.svc.Search(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apigwSecretSetPayload{Filter: f, Set: set}, nil
+}
+
+func (ctrl *ApigwSecret) Create(ctx context.Context, r *request.ApigwSecretCreate) (interface{}, error) {
+	return ctrl.svc.Create(ctx, &types.ApigwSecret{
+		Handle: r.Handle,
+		Value:  r.Value,
+		Meta:   r.Meta,
+	})
+}
+
+func (ctrl *ApigwSecret) Update(ctx context.Context, r *request.ApigwSecretUpdate) (interface{}, error) {
+	return ctrl.svc.Update(ctx, &types.ApigwSecret{
+		ID:     r.SecretID,
+		Handle: r.Handle,
+		Value:  r.Value,
+		Meta:   r.Meta,
+	})
+}
+
+func (ctrl *ApigwSecret) Read(ctx context.Context, r *request.ApigwSecretRead) (interface{}, error) {
+	return ctrl.svc.FindByID(ctx, r.SecretID)
+}
+
+func (ctrl *ApigwSecret) Delete(ctx context.Context, r *request.ApigwSecretDelete) (interface{}, error) {
+	return api.OK(), ctrl.svc.DeleteByID(ctx, r.SecretID)
+}
+
+func (ctrl *ApigwSecret) Undelete(ctx context.Context, r *request.ApigwSecretUndelete) (interface{}, error) {
+	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.SecretID)
+}
+
+func (ctrl *ApigwSecret) RotateKeys(ctx context.Context, r *request.ApigwSecretRotateKeys) (interface{}, error) {
+	rotated, err := ctrl.svc.RotateKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &apigwSecretRotateKeysPayload{Rotated: rotated}, nil
+}
@@ -132,6 +132,10 @@ func (ctrl Attachment) serve(ctx context.Context, attachmentID uint64, preview,
 			w.Header().Add("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
 		}
 
+		if c, ok := fh.(io.Closer); ok {
+			defer c.Close()
+		}
+
 		http.ServeContent(w, req, name, att.CreatedAt, fh)
 	}, nil
 }
@@ -25,16 +25,18 @@ type (
 		Read(context.Context, *request.ApigwRouteRead) (interface{}, error)
 		Delete(context.Context, *request.ApigwRouteDelete) (interface{}, error)
 		Undelete(context.Context, *request.ApigwRouteUndelete) (interface{}, error)
+		PurgeCache(context.Context, *request.ApigwRoutePurgeCache) (interface{}, error)
 	}
 
 	// HTTP API interface
 	ApigwRoute struct {
-		List     func(http.ResponseWriter, *http.Request)
-		Create   func(http.ResponseWriter, *http.Request)
-		Update   func(http.ResponseWriter, *http.Request)
-		Read     func(http.ResponseWriter, *http.Request)
-		Delete   func(http.ResponseWriter, *http.Request)
-		Undelete func(http.ResponseWriter, *http.Request)
+		List       func(http.ResponseWriter, *http.Request)
+		Create     func(http.ResponseWriter, *http.Request)
+		Update     func(http.ResponseWriter, *http.Request)
+		Read       func(http.ResponseWriter, *http.Request)
+		Delete     func(http.ResponseWriter, *http.Request)
+		Undelete   func(http.ResponseWriter, *http.Request)
+		PurgeCache func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -134,6 +136,22 @@ func NewApigwRoute(h ApigwRouteAPI) *ApigwRoute {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		PurgeCache: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewApigwRoutePurgeCache()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.PurgeCache(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -148,5 +166,6 @@ func (h ApigwRoute) MountRoutes(r chi.Router, middlewares ...func(http.Handler)
 		r.Get("/apigw/route/{routeID}", h.Read)
 		r.Delete("/apigw/route/{routeID}", h.Delete)
 		r.Post("/apigw/route/{routeID}/undelete", h.Undelete)
+		r.Post("/apigw/route/{routeID}/cache/purge", h.PurgeCache)
 	})
 }
@@ -20,11 +20,13 @@ type (
 	// Internal API interface
 	StatsAPI interface {
 		List(context.Context, *request.StatsList) (interface{}, error)
+		Limits(context.Context, *request.StatsLimits) (interface{}, error)
 	}
 
 	// HTTP API interface
 	Stats struct {
-		List func(http.ResponseWriter, *http.Request)
+		List   func(http.ResponseWriter, *http.Request)
+		Limits func(http.ResponseWriter, *http.Request)
 	}
 )
 
@@ -44,6 +46,22 @@ func NewStats(h StatsAPI) *Stats {
 				return
 			}
 
+			api.Send(w, r, value)
+		},
+		Limits: func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			params := request.NewStatsLimits()
+			if err := params.Fill(r); err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
+			value, err := h.Limits(r.Context(), params)
+			if err != nil {
+				api.Send(w, r, err)
+				return
+			}
+
 			api.Send(w, r, value)
 		},
 	}
@@ -53,5 +71,6 @@ func (h Stats) MountRoutes(r chi.Router, middlewares ...func(http.Handler) http.
 	r.Group(func(r chi.Router) {
 		r.Use(middlewares...)
 		r.Get("/stats/", h.List)
+		r.Get("/stats/limits", h.Limits)
 	})
 }
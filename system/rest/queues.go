@@ -79,7 +79,8 @@ func (ctrl *Queue) Create(ctx context.Context, r *request.QueuesCreate) (interfa
 }
 
 func (ctrl *Queue) Read(ctx context.Context, r *request.QueuesRead) (interface{}, error) {
-	return ctrl.svc.FindByID(ctx, r.QueueID)
+	q, err := ctrl.svc.FindByID(ctx, r.QueueID)
+	return ctrl.makePayload(ctx, q, err)
 }
 
 func (ctrl *Queue) Update(ctx context.Context, r *request.QueuesUpdate) (interface{}, error) {
@@ -111,8 +112,12 @@ func (ctrl *Queue) makePayload(ctx context.Context, q *types.Queue, err error) (
 		return nil, err
 	}
 
+	// credentials are not exposed
+	c := *q
+	c.Meta = q.Meta.Masked()
+
 	qq := &queuePayload{
-		Queue: q,
+		Queue: &c,
 	}
 
 	return qq, nil
@@ -156,6 +156,13 @@ type (
 		// Route ID
 		RouteID uint64 `json:",string"`
 	}
+
+	ApigwRoutePurgeCache struct {
+		// RouteID PATH parameter
+		//
+		// Route ID
+		RouteID uint64 `json:",string"`
+	}
 )
 
 // NewApigwRouteList request
@@ -719,3 +726,38 @@ func (r *ApigwRouteUndelete) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewApigwRoutePurgeCache request
+func NewApigwRoutePurgeCache() *ApigwRoutePurgeCache {
+	return &ApigwRoutePurgeCache{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwRoutePurgeCache) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"routeID": r.RouteID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwRoutePurgeCache) GetRouteID() uint64 {
+	return r.RouteID
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwRoutePurgeCache) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "routeID")
+		r.RouteID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
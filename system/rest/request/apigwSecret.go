@@ -0,0 +1,593 @@
+package request
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/go-chi/chi"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// dummy vars to prevent
+// unused imports complain
+var (
+	_ = chi.URLParam
+	_ = multipart.ErrMessageTooLarge
+	_ = payload.ParseUint64s
+	_ = strings.ToLower
+	_ = io.EOF
+	_ = fmt.Errorf
+	_ = json.NewEncoder
+)
+
+type (
+	// Internal API interface
+	ApigwSecretList struct {
+		// Query GET parameter
+		//
+		// Filter secrets by handle
+		Query string
+
+		// Handle GET parameter
+		//
+		// Secret handle
+		Handle string
+
+		// Deleted GET parameter
+		//
+		// Exclude (0, default), include (1) or return only (2) deleted secrets
+		Deleted uint64 `json:",string"`
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	ApigwSecretCreate struct {
+		// Handle POST parameter
+		//
+		// Secret handle
+		Handle string
+
+		// Value POST parameter
+		//
+		// Secret value
+		Value string
+
+		// Meta POST parameter
+		//
+		// Secret meta
+		Meta types.ApigwSecretMeta
+	}
+
+	ApigwSecretUpdate struct {
+		// SecretID PATH parameter
+		//
+		// Secret ID
+		SecretID uint64 `json:",string"`
+
+		// Handle POST parameter
+		//
+		// Secret handle
+		Handle string
+
+		// Value POST parameter
+		//
+		// New secret value
+		Value string
+
+		// Meta POST parameter
+		//
+		// Secret meta
+		Meta types.ApigwSecretMeta
+	}
+
+	ApigwSecretRead struct {
+		// SecretID PATH parameter
+		//
+		// Secret ID
+		SecretID uint64 `json:",string"`
+	}
+
+	ApigwSecretDelete struct {
+		// SecretID PATH parameter
+		//
+		// Secret ID
+		SecretID uint64 `json:",string"`
+	}
+
+	ApigwSecretUndelete struct {
+		// SecretID PATH parameter
+		//
+		// Secret ID
+		SecretID uint64 `json:",string"`
+	}
+
+	ApigwSecretRotateKeys struct {
+	}
+)
+
+// NewApigwSecretList request
+func NewApigwSecretList() *ApigwSecretList {
+	return &ApigwSecretList{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"query":      r.Query,
+		"handle":     r.Handle,
+		"deleted":    r.Deleted,
+		"limit":      r.Limit,
+		"pageCursor": r.PageCursor,
+		"sort":       r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) GetQuery() string {
+	return r.Query
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) GetHandle() string {
+	return r.Handle
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) GetDeleted() uint64 {
+	return r.Deleted
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretList) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretList) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["query"]; ok && len(val) > 0 {
+			r.Query, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["handle"]; ok && len(val) > 0 {
+			r.Handle, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["deleted"]; ok && len(val) > 0 {
+			r.Deleted, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// NewApigwSecretCreate request
+func NewApigwSecretCreate() *ApigwSecretCreate {
+	return &ApigwSecretCreate{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretCreate) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"handle": r.Handle,
+		"meta":   r.Meta,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretCreate) GetHandle() string {
+	return r.Handle
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretCreate) GetValue() string {
+	return r.Value
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretCreate) GetMeta() types.ApigwSecretMeta {
+	return r.Meta
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretCreate) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+			if val, ok := req.MultipartForm.Value["handle"]; ok && len(val) > 0 {
+				r.Handle, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["value"]; ok && len(val) > 0 {
+				r.Value, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["meta[]"]; ok {
+				r.Meta, err = types.ParseApigwSecretMeta(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["meta"]; ok {
+				r.Meta, err = types.ParseApigwSecretMeta(val)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if val, ok := req.Form["handle"]; ok && len(val) > 0 {
+			r.Handle, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["value"]; ok && len(val) > 0 {
+			r.Value, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["meta[]"]; ok {
+			r.Meta, err = types.ParseApigwSecretMeta(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["meta"]; ok {
+			r.Meta, err = types.ParseApigwSecretMeta(val)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// NewApigwSecretUpdate request
+func NewApigwSecretUpdate() *ApigwSecretUpdate {
+	return &ApigwSecretUpdate{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUpdate) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"secretID": r.SecretID,
+		"handle":   r.Handle,
+		"meta":     r.Meta,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUpdate) GetSecretID() uint64 {
+	return r.SecretID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUpdate) GetHandle() string {
+	return r.Handle
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUpdate) GetValue() string {
+	return r.Value
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUpdate) GetMeta() types.ApigwSecretMeta {
+	return r.Meta
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretUpdate) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+			if val, ok := req.MultipartForm.Value["handle"]; ok && len(val) > 0 {
+				r.Handle, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["value"]; ok && len(val) > 0 {
+				r.Value, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["meta[]"]; ok {
+				r.Meta, err = types.ParseApigwSecretMeta(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["meta"]; ok {
+				r.Meta, err = types.ParseApigwSecretMeta(val)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if val, ok := req.Form["handle"]; ok && len(val) > 0 {
+			r.Handle, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["value"]; ok && len(val) > 0 {
+			r.Value, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["meta[]"]; ok {
+			r.Meta, err = types.ParseApigwSecretMeta(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["meta"]; ok {
+			r.Meta, err = types.ParseApigwSecretMeta(val)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "secretID")
+		r.SecretID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewApigwSecretRead request
+func NewApigwSecretRead() *ApigwSecretRead {
+	return &ApigwSecretRead{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretRead) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"secretID": r.SecretID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretRead) GetSecretID() uint64 {
+	return r.SecretID
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretRead) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "secretID")
+		r.SecretID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewApigwSecretDelete request
+func NewApigwSecretDelete() *ApigwSecretDelete {
+	return &ApigwSecretDelete{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretDelete) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"secretID": r.SecretID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretDelete) GetSecretID() uint64 {
+	return r.SecretID
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretDelete) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "secretID")
+		r.SecretID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewApigwSecretUndelete request
+func NewApigwSecretUndelete() *ApigwSecretUndelete {
+	return &ApigwSecretUndelete{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUndelete) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"secretID": r.SecretID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretUndelete) GetSecretID() uint64 {
+	return r.SecretID
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretUndelete) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "secretID")
+		r.SecretID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewApigwSecretRotateKeys request
+func NewApigwSecretRotateKeys() *ApigwSecretRotateKeys {
+	return &ApigwSecretRotateKeys{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r ApigwSecretRotateKeys) Auditable() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// Fill processes request and fills internal variables
+func (r *ApigwSecretRotateKeys) Fill(req *http.Request) (err error) {
+
+	return err
+}
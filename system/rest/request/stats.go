@@ -35,6 +35,9 @@ type (
 	// Internal API interface
 	StatsList struct {
 	}
+
+	StatsLimits struct {
+	}
 )
 
 // NewStatsList request
@@ -52,3 +55,19 @@ func (r *StatsList) Fill(req *http.Request) (err error) {
 
 	return err
 }
+
+// NewStatsLimits request
+func NewStatsLimits() *StatsLimits {
+	return &StatsLimits{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r StatsLimits) Auditable() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+// Fill processes request and fills internal variables
+func (r *StatsLimits) Fill(req *http.Request) (err error) {
+
+	return err
+}
@@ -0,0 +1,1041 @@
+package request
+
+// This file is auto-generated.
+//
+// Changes to this file may cause incorrect behavior and will be lost if
+// the code is regenerated.
+//
+// Definitions file that controls how this file is generated:
+//
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cortezaproject/corteza-server/pkg/payload"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/go-chi/chi"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// dummy vars to prevent
+// unused imports complain
+var (
+	_ = chi.URLParam
+	_ = multipart.ErrMessageTooLarge
+	_ = payload.ParseUint64s
+	_ = strings.ToLower
+	_ = io.EOF
+	_ = fmt.Errorf
+	_ = json.NewEncoder
+)
+
+type (
+	// Internal API interface
+	WebhooksList struct {
+		// ResourceType GET parameter
+		//
+		// Filter by resource type
+		ResourceType string
+
+		// Deleted GET parameter
+		//
+		// Exclude (0
+		Deleted uint
+
+		// Disabled GET parameter
+		//
+		// Exclude (0
+		Disabled uint
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	WebhooksCreate struct {
+		// Url POST parameter
+		//
+		// Webhook URL
+		Url string
+
+		// Secret POST parameter
+		//
+		// Secret for signing the payload
+		Secret string
+
+		// ResourceType POST parameter
+		//
+		// Resource type
+		ResourceType string
+
+		// EventTypes POST parameter
+		//
+		// Event types
+		EventTypes types.WebhookEventTypeSet
+
+		// Constraints POST parameter
+		//
+		// Constraints
+		Constraints types.WebhookConstraintSet
+
+		// Enabled POST parameter
+		//
+		// Is webhook enabled
+		Enabled bool
+
+		// Meta POST parameter
+		//
+		// Meta data
+		Meta types.WebhookMeta
+
+		// OwnedBy POST parameter
+		//
+		// Owner of the webhook
+		OwnedBy uint64 `json:",string"`
+	}
+
+	WebhooksRead struct {
+		// WebhookID PATH parameter
+		//
+		// Webhook ID
+		WebhookID uint64 `json:",string"`
+	}
+
+	WebhooksUpdate struct {
+		// WebhookID PATH parameter
+		//
+		// Webhook ID
+		WebhookID uint64 `json:",string"`
+
+		// Url POST parameter
+		//
+		// Webhook URL
+		Url string
+
+		// Secret POST parameter
+		//
+		// Secret for signing the payload (existing is kept when empty)
+		Secret string
+
+		// ResourceType POST parameter
+		//
+		// Resource type
+		ResourceType string
+
+		// EventTypes POST parameter
+		//
+		// Event types
+		EventTypes types.WebhookEventTypeSet
+
+		// Constraints POST parameter
+		//
+		// Constraints
+		Constraints types.WebhookConstraintSet
+
+		// Enabled POST parameter
+		//
+		// Is webhook enabled
+		Enabled bool
+
+		// Meta POST parameter
+		//
+		// Meta data
+		Meta types.WebhookMeta
+
+		// OwnedBy POST parameter
+		//
+		// Owner of the webhook
+		OwnedBy uint64 `json:",string"`
+	}
+
+	WebhooksDelete struct {
+		// WebhookID PATH parameter
+		//
+		// Webhook ID
+		WebhookID uint64 `json:",string"`
+	}
+
+	WebhooksUndelete struct {
+		// WebhookID PATH parameter
+		//
+		// Webhook ID
+		WebhookID uint64 `json:",string"`
+	}
+
+	WebhooksDeliveries struct {
+		// WebhookID PATH parameter
+		//
+		// Webhook ID
+		WebhookID uint64 `json:",string"`
+
+		// Status GET parameter
+		//
+		// Filter by delivery status (pending
+		Status string
+
+		// Limit GET parameter
+		//
+		// Limit
+		Limit uint
+
+		// PageCursor GET parameter
+		//
+		// Page cursor
+		PageCursor string
+
+		// Sort GET parameter
+		//
+		// Sort items
+		Sort string
+	}
+
+	WebhooksRedeliver struct {
+		// WebhookID PATH parameter
+		//
+		// Webhook ID
+		WebhookID uint64 `json:",string"`
+
+		// DeliveryID PATH parameter
+		//
+		// Delivery ID
+		DeliveryID uint64 `json:",string"`
+	}
+)
+
+// NewWebhooksList request
+func NewWebhooksList() *WebhooksList {
+	return &WebhooksList{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"resourceType": r.ResourceType,
+		"deleted":      r.Deleted,
+		"disabled":     r.Disabled,
+		"limit":        r.Limit,
+		"pageCursor":   r.PageCursor,
+		"sort":         r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) GetResourceType() string {
+	return r.ResourceType
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) GetDeleted() uint {
+	return r.Deleted
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) GetDisabled() uint {
+	return r.Disabled
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksList) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksList) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["resourceType"]; ok && len(val) > 0 {
+			r.ResourceType, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["deleted"]; ok && len(val) > 0 {
+			r.Deleted, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["disabled"]; ok && len(val) > 0 {
+			r.Disabled, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// NewWebhooksCreate request
+func NewWebhooksCreate() *WebhooksCreate {
+	return &WebhooksCreate{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"url":          r.Url,
+		"secret":       r.Secret,
+		"resourceType": r.ResourceType,
+		"eventTypes":   r.EventTypes,
+		"constraints":  r.Constraints,
+		"enabled":      r.Enabled,
+		"meta":         r.Meta,
+		"ownedBy":      r.OwnedBy,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetUrl() string {
+	return r.Url
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetSecret() string {
+	return r.Secret
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetResourceType() string {
+	return r.ResourceType
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetEventTypes() types.WebhookEventTypeSet {
+	return r.EventTypes
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetConstraints() types.WebhookConstraintSet {
+	return r.Constraints
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetEnabled() bool {
+	return r.Enabled
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetMeta() types.WebhookMeta {
+	return r.Meta
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksCreate) GetOwnedBy() uint64 {
+	return r.OwnedBy
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksCreate) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+			if val, ok := req.MultipartForm.Value["url"]; ok && len(val) > 0 {
+				r.Url, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["secret"]; ok && len(val) > 0 {
+				r.Secret, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["resourceType"]; ok && len(val) > 0 {
+				r.ResourceType, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["eventTypes[]"]; ok {
+				r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["eventTypes"]; ok {
+				r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["constraints[]"]; ok {
+				r.Constraints, err = types.ParseWebhookConstraintSet(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["constraints"]; ok {
+				r.Constraints, err = types.ParseWebhookConstraintSet(val)
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["enabled"]; ok && len(val) > 0 {
+				r.Enabled, err = payload.ParseBool(val[0]), nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["meta[]"]; ok {
+				r.Meta, err = types.ParseWebhookMeta(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["meta"]; ok {
+				r.Meta, err = types.ParseWebhookMeta(val)
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["ownedBy"]; ok && len(val) > 0 {
+				r.OwnedBy, err = payload.ParseUint64(val[0]), nil
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if val, ok := req.Form["url"]; ok && len(val) > 0 {
+			r.Url, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["secret"]; ok && len(val) > 0 {
+			r.Secret, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["resourceType"]; ok && len(val) > 0 {
+			r.ResourceType, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["eventTypes[]"]; ok {
+			r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["eventTypes"]; ok {
+			r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["constraints[]"]; ok {
+			r.Constraints, err = types.ParseWebhookConstraintSet(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["constraints"]; ok {
+			r.Constraints, err = types.ParseWebhookConstraintSet(val)
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["enabled"]; ok && len(val) > 0 {
+			r.Enabled, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["meta[]"]; ok {
+			r.Meta, err = types.ParseWebhookMeta(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["meta"]; ok {
+			r.Meta, err = types.ParseWebhookMeta(val)
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["ownedBy"]; ok && len(val) > 0 {
+			r.OwnedBy, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return err
+}
+
+// NewWebhooksRead request
+func NewWebhooksRead() *WebhooksRead {
+	return &WebhooksRead{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksRead) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"webhookID": r.WebhookID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksRead) GetWebhookID() uint64 {
+	return r.WebhookID
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksRead) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "webhookID")
+		r.WebhookID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWebhooksUpdate request
+func NewWebhooksUpdate() *WebhooksUpdate {
+	return &WebhooksUpdate{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"webhookID":    r.WebhookID,
+		"url":          r.Url,
+		"secret":       r.Secret,
+		"resourceType": r.ResourceType,
+		"eventTypes":   r.EventTypes,
+		"constraints":  r.Constraints,
+		"enabled":      r.Enabled,
+		"meta":         r.Meta,
+		"ownedBy":      r.OwnedBy,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetWebhookID() uint64 {
+	return r.WebhookID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetUrl() string {
+	return r.Url
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetSecret() string {
+	return r.Secret
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetResourceType() string {
+	return r.ResourceType
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetEventTypes() types.WebhookEventTypeSet {
+	return r.EventTypes
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetConstraints() types.WebhookConstraintSet {
+	return r.Constraints
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetEnabled() bool {
+	return r.Enabled
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetMeta() types.WebhookMeta {
+	return r.Meta
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUpdate) GetOwnedBy() uint64 {
+	return r.OwnedBy
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksUpdate) Fill(req *http.Request) (err error) {
+
+	if strings.HasPrefix(strings.ToLower(req.Header.Get("content-type")), "application/json") {
+		err = json.NewDecoder(req.Body).Decode(r)
+
+		switch {
+		case err == io.EOF:
+			err = nil
+		case err != nil:
+			return fmt.Errorf("error parsing http request body: %w", err)
+		}
+	}
+
+	{
+		// Caching 32MB to memory, the rest to disk
+		if err = req.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		} else if err == nil {
+			// Multipart params
+
+			if val, ok := req.MultipartForm.Value["url"]; ok && len(val) > 0 {
+				r.Url, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["secret"]; ok && len(val) > 0 {
+				r.Secret, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["resourceType"]; ok && len(val) > 0 {
+				r.ResourceType, err = val[0], nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["eventTypes[]"]; ok {
+				r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["eventTypes"]; ok {
+				r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["constraints[]"]; ok {
+				r.Constraints, err = types.ParseWebhookConstraintSet(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["constraints"]; ok {
+				r.Constraints, err = types.ParseWebhookConstraintSet(val)
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["enabled"]; ok && len(val) > 0 {
+				r.Enabled, err = payload.ParseBool(val[0]), nil
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["meta[]"]; ok {
+				r.Meta, err = types.ParseWebhookMeta(val)
+				if err != nil {
+					return err
+				}
+			} else if val, ok := req.MultipartForm.Value["meta"]; ok {
+				r.Meta, err = types.ParseWebhookMeta(val)
+				if err != nil {
+					return err
+				}
+			}
+
+			if val, ok := req.MultipartForm.Value["ownedBy"]; ok && len(val) > 0 {
+				r.OwnedBy, err = payload.ParseUint64(val[0]), nil
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	{
+		if err = req.ParseForm(); err != nil {
+			return err
+		}
+
+		// POST params
+
+		if val, ok := req.Form["url"]; ok && len(val) > 0 {
+			r.Url, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["secret"]; ok && len(val) > 0 {
+			r.Secret, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["resourceType"]; ok && len(val) > 0 {
+			r.ResourceType, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["eventTypes[]"]; ok {
+			r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["eventTypes"]; ok {
+			r.EventTypes, err = types.ParseWebhookEventTypeSet(val)
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["constraints[]"]; ok {
+			r.Constraints, err = types.ParseWebhookConstraintSet(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["constraints"]; ok {
+			r.Constraints, err = types.ParseWebhookConstraintSet(val)
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["enabled"]; ok && len(val) > 0 {
+			r.Enabled, err = payload.ParseBool(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["meta[]"]; ok {
+			r.Meta, err = types.ParseWebhookMeta(val)
+			if err != nil {
+				return err
+			}
+		} else if val, ok := req.Form["meta"]; ok {
+			r.Meta, err = types.ParseWebhookMeta(val)
+			if err != nil {
+				return err
+			}
+		}
+
+		if val, ok := req.Form["ownedBy"]; ok && len(val) > 0 {
+			r.OwnedBy, err = payload.ParseUint64(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "webhookID")
+		r.WebhookID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWebhooksDelete request
+func NewWebhooksDelete() *WebhooksDelete {
+	return &WebhooksDelete{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDelete) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"webhookID": r.WebhookID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDelete) GetWebhookID() uint64 {
+	return r.WebhookID
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksDelete) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "webhookID")
+		r.WebhookID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWebhooksUndelete request
+func NewWebhooksUndelete() *WebhooksUndelete {
+	return &WebhooksUndelete{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUndelete) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"webhookID": r.WebhookID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksUndelete) GetWebhookID() uint64 {
+	return r.WebhookID
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksUndelete) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "webhookID")
+		r.WebhookID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWebhooksDeliveries request
+func NewWebhooksDeliveries() *WebhooksDeliveries {
+	return &WebhooksDeliveries{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDeliveries) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"webhookID":  r.WebhookID,
+		"status":     r.Status,
+		"limit":      r.Limit,
+		"pageCursor": r.PageCursor,
+		"sort":       r.Sort,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDeliveries) GetWebhookID() uint64 {
+	return r.WebhookID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDeliveries) GetStatus() string {
+	return r.Status
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDeliveries) GetLimit() uint {
+	return r.Limit
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDeliveries) GetPageCursor() string {
+	return r.PageCursor
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksDeliveries) GetSort() string {
+	return r.Sort
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksDeliveries) Fill(req *http.Request) (err error) {
+
+	{
+		// GET params
+		tmp := req.URL.Query()
+
+		if val, ok := tmp["status"]; ok && len(val) > 0 {
+			r.Status, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["limit"]; ok && len(val) > 0 {
+			r.Limit, err = payload.ParseUint(val[0]), nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["pageCursor"]; ok && len(val) > 0 {
+			r.PageCursor, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+		if val, ok := tmp["sort"]; ok && len(val) > 0 {
+			r.Sort, err = val[0], nil
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "webhookID")
+		r.WebhookID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
+
+// NewWebhooksRedeliver request
+func NewWebhooksRedeliver() *WebhooksRedeliver {
+	return &WebhooksRedeliver{}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksRedeliver) Auditable() map[string]interface{} {
+	return map[string]interface{}{
+		"webhookID":  r.WebhookID,
+		"deliveryID": r.DeliveryID,
+	}
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksRedeliver) GetWebhookID() uint64 {
+	return r.WebhookID
+}
+
+// Auditable returns all auditable/loggable parameters
+func (r WebhooksRedeliver) GetDeliveryID() uint64 {
+	return r.DeliveryID
+}
+
+// Fill processes request and fills internal variables
+func (r *WebhooksRedeliver) Fill(req *http.Request) (err error) {
+
+	{
+		var val string
+		// path params
+
+		val = chi.URLParam(req, "webhookID")
+		r.WebhookID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+		val = chi.URLParam(req, "deliveryID")
+		r.DeliveryID, err = payload.ParseUint64(val), nil
+		if err != nil {
+			return err
+		}
+
+	}
+
+	return err
+}
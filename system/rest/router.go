@@ -42,5 +42,7 @@ func MountRoutes(r chi.Router) {
 		handlers.NewQueues(Queue{}.New()).MountRoutes(r)
 		handlers.NewApigwRoute(ApigwRoute{}.New()).MountRoutes(r)
 		handlers.NewApigwFilter(ApigwFilter{}.New()).MountRoutes(r)
+		handlers.NewApigwSecret(ApigwSecret{}.New()).MountRoutes(r)
+		handlers.NewWebhooks(Webhook{}.New()).MountRoutes(r)
 	})
 }
@@ -16,6 +16,7 @@ type (
 
 	statsService interface {
 		Metrics(context.Context) (*service.StatisticsMetricsPayload, error)
+		Limits(context.Context) (*service.StatisticsLimitsPayload, error)
 	}
 )
 
@@ -28,3 +29,7 @@ func (Stats) New() *Stats {
 func (ctrl *Stats) List(ctx context.Context, r *request.StatsList) (interface{}, error) {
 	return ctrl.svc.Metrics(ctx)
 }
+
+func (ctrl *Stats) Limits(ctx context.Context, r *request.StatsLimits) (interface{}, error) {
+	return ctrl.svc.Limits(ctx)
+}
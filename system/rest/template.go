@@ -219,7 +219,11 @@ func (ctrl *Template) serve(doc io.ReadSeeker, ct string, r *request.TemplateRen
 
 		name := url.QueryEscape(strings.TrimSpace(r.Filename) + "." + strings.TrimSpace(r.Ext))
 		w.Header().Add("Content-Disposition", "attachment; filename="+name)
-		w.Header().Add("Content-Type", ct+"; charset=utf-8")
+		if dt := types.DocumentType(ct); dt.Binary() || dt == types.DocumentTypePDF {
+			w.Header().Add("Content-Type", ct)
+		} else {
+			w.Header().Add("Content-Type", ct+"; charset=utf-8")
+		}
 
 		http.ServeContent(w, req, name, time.Now(), doc)
 	}, nil
@@ -233,6 +237,10 @@ func (ctrl *Template) getDestinationType(ext string) string {
 		return "text/html"
 	case "pdf":
 		return "application/pdf"
+	case "docx":
+		return string(types.DocumentTypeDOCX)
+	case "xlsx":
+		return string(types.DocumentTypeXLSX)
 	}
 
 	return "text/plain"
@@ -0,0 +1,182 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/api"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/system/rest/request"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	Webhook struct {
+		svc webhookService
+	}
+
+	webhookPayload struct {
+		*types.Webhook
+
+		// secret is never returned, only the information if it is set
+		HasSecret bool `json:"hasSecret"`
+	}
+
+	webhookSetPayload struct {
+		Filter types.WebhookFilter `json:"filter"`
+		Set    []*webhookPayload   `json:"set"`
+	}
+
+	webhookDeliverySetPayload struct {
+		Filter types.WebhookDeliveryFilter `json:"filter"`
+		Set    types.WebhookDeliverySet    `json:"set"`
+	}
+
+	webhookService interface {
+		FindByID(ctx context.Context, ID uint64) (*types.Webhook, error)
+		Search(ctx context.Context, filter types.WebhookFilter) (types.WebhookSet, types.WebhookFilter, error)
+		Create(ctx context.Context, new *types.Webhook) (*types.Webhook, error)
+		Update(ctx context.Context, upd *types.Webhook) (*types.Webhook, error)
+		DeleteByID(ctx context.Context, ID uint64) error
+		UndeleteByID(ctx context.Context, ID uint64) error
+		Deliveries(ctx context.Context, webhookID uint64, filter types.WebhookDeliveryFilter) (types.WebhookDeliverySet, types.WebhookDeliveryFilter, error)
+		Redeliver(ctx context.Context, webhookID, deliveryID uint64) (*types.WebhookDelivery, error)
+	}
+)
+
+func (Webhook) New() *Webhook {
+	return &Webhook{
+		svc: service.DefaultWebhook,
+	}
+}
+
+func (ctrl *Webhook) List(ctx context.Context, r *request.WebhooksList) (interface{}, error) {
+	var (
+		err error
+		f   = types.WebhookFilter{
+			ResourceType: r.ResourceType,
+			Deleted:      filter.State(r.Deleted),
+			Disabled:     filter.State(r.Disabled),
+		}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, filter, err := ctrl.svc.Search(ctx, f)
+
+	return ctrl.makeFilterPayload(ctx, set, filter, err)
+}
+
+func (ctrl *Webhook) Create(ctx context.Context, r *request.WebhooksCreate) (interface{}, error) {
+	var (
+		err error
+		wh  = &types.Webhook{
+			URL:          r.Url,
+			Secret:       r.Secret,
+			ResourceType: r.ResourceType,
+			EventTypes:   r.EventTypes,
+			Constraints:  r.Constraints,
+			Enabled:      r.Enabled,
+			Meta:         r.Meta,
+			OwnedBy:      r.OwnedBy,
+		}
+	)
+
+	wh, err = ctrl.svc.Create(ctx, wh)
+
+	return ctrl.makePayload(ctx, wh, err)
+}
+
+func (ctrl *Webhook) Read(ctx context.Context, r *request.WebhooksRead) (interface{}, error) {
+	wh, err := ctrl.svc.FindByID(ctx, r.WebhookID)
+
+	return ctrl.makePayload(ctx, wh, err)
+}
+
+func (ctrl *Webhook) Update(ctx context.Context, r *request.WebhooksUpdate) (interface{}, error) {
+	var (
+		err error
+		wh  = &types.Webhook{
+			ID:           r.WebhookID,
+			URL:          r.Url,
+			Secret:       r.Secret,
+			ResourceType: r.ResourceType,
+			EventTypes:   r.EventTypes,
+			Constraints:  r.Constraints,
+			Enabled:      r.Enabled,
+			Meta:         r.Meta,
+			OwnedBy:      r.OwnedBy,
+		}
+	)
+
+	wh, err = ctrl.svc.Update(ctx, wh)
+
+	return ctrl.makePayload(ctx, wh, err)
+}
+
+func (ctrl *Webhook) Delete(ctx context.Context, r *request.WebhooksDelete) (interface{}, error) {
+	return api.OK(), ctrl.svc.DeleteByID(ctx, r.WebhookID)
+}
+
+func (ctrl *Webhook) Undelete(ctx context.Context, r *request.WebhooksUndelete) (interface{}, error) {
+	return api.OK(), ctrl.svc.UndeleteByID(ctx, r.WebhookID)
+}
+
+func (ctrl *Webhook) Deliveries(ctx context.Context, r *request.WebhooksDeliveries) (interface{}, error) {
+	var (
+		err error
+		f   = types.WebhookDeliveryFilter{
+			Status: types.WebhookDeliveryStatus(r.Status),
+		}
+	)
+
+	if f.Paging, err = filter.NewPaging(r.Limit, r.PageCursor); err != nil {
+		return nil, err
+	}
+
+	if f.Sorting, err = filter.NewSorting(r.Sort); err != nil {
+		return nil, err
+	}
+
+	set, filter, err := ctrl.svc.Deliveries(ctx, r.WebhookID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookDeliverySetPayload{Filter: filter, Set: set}, nil
+}
+
+func (ctrl *Webhook) Redeliver(ctx context.Context, r *request.WebhooksRedeliver) (interface{}, error) {
+	return ctrl.svc.Redeliver(ctx, r.WebhookID, r.DeliveryID)
+}
+
+func (ctrl *Webhook) makePayload(ctx context.Context, wh *types.Webhook, err error) (*webhookPayload, error) {
+	if err != nil || wh == nil {
+		return nil, err
+	}
+
+	return &webhookPayload{
+		Webhook:   wh,
+		HasSecret: wh.Secret != "",
+	}, nil
+}
+
+func (ctrl *Webhook) makeFilterPayload(ctx context.Context, nn types.WebhookSet, f types.WebhookFilter, err error) (*webhookSetPayload, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	wsp := &webhookSetPayload{Filter: f, Set: make([]*webhookPayload, len(nn))}
+
+	for i := range nn {
+		wsp.Set[i], _ = ctrl.makePayload(ctx, nn[i], nil)
+	}
+
+	return wsp, nil
+}
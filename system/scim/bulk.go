@@ -0,0 +1,212 @@
+package scim
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+)
+
+const (
+	urnBulkResponse = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+)
+
+type (
+	bulkOperationRequest struct {
+		Method  string          `json:"method"`
+		BulkId  string          `json:"bulkId,omitempty"`
+		Version string          `json:"version,omitempty"`
+		Path    string          `json:"path"`
+		Data    json.RawMessage `json:"data,omitempty"`
+	}
+
+	bulkRequest struct {
+		Schemas      []string                `json:"schemas"`
+		FailOnErrors int                     `json:"failOnErrors"`
+		Operations   []*bulkOperationRequest `json:"Operations"`
+	}
+
+	bulkOperationResponse struct {
+		Method   string          `json:"method"`
+		BulkId   string          `json:"bulkId,omitempty"`
+		Version  string          `json:"version,omitempty"`
+		Location string          `json:"location,omitempty"`
+		Status   string          `json:"status"`
+		Response json.RawMessage `json:"response,omitempty"`
+	}
+
+	bulkResponse struct {
+		Schemas    []string                 `json:"schemas"`
+		Operations []*bulkOperationResponse `json:"Operations"`
+	}
+
+	bulkHandler struct {
+		// operations are dispatched through the SCIM router
+		router http.Handler
+	}
+
+	// bulkRecorder collects the response of a single bulk operation
+	bulkRecorder struct {
+		header http.Header
+		status int
+		body   bytes.Buffer
+	}
+)
+
+func (rec *bulkRecorder) Header() http.Header {
+	return rec.header
+}
+
+func (rec *bulkRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	return rec.body.Write(b)
+}
+
+func (rec *bulkRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+// bulk runs all operations from the request (RFC 7644, section 3.7)
+//
+// Operations are executed in order, each as a separate request to the SCIM
+// endpoints. Processing stops when number of errors reaches failOnErrors.
+func (h bulkHandler) bulk(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var (
+		req    = &bulkRequest{}
+		rsp    = &bulkResponse{Schemas: []string{urnBulkResponse}}
+		failed int
+
+		// resolved bulk IDs of created resources
+		resolved = make(map[string]string)
+	)
+
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, bulkMaxPayloadSize)).Decode(req); err != nil {
+		if strings.Contains(err.Error(), "request body too large") {
+			sendError(w, newErrorfResponse(http.StatusRequestEntityTooLarge, "bulk payload exceeds %d bytes", bulkMaxPayloadSize).withType(scimTypeTooMany))
+			return
+		}
+
+		sendError(w, newErrorfResponse(http.StatusBadRequest, "could not decode bulk payload: %v", err).withType(scimTypeInvalidSyntax))
+		return
+	}
+
+	if len(req.Operations) > bulkMaxOperations {
+		sendError(w, newErrorfResponse(http.StatusRequestEntityTooLarge, "number of operations exceeds %d", bulkMaxOperations).withType(scimTypeTooMany))
+		return
+	}
+
+	for _, op := range req.Operations {
+		if req.FailOnErrors > 0 && failed >= req.FailOnErrors {
+			break
+		}
+
+		opRsp := h.run(r, op, resolved)
+		rsp.Operations = append(rsp.Operations, opRsp)
+
+		if status, _ := strconv.Atoi(opRsp.Status); status >= 400 {
+			failed++
+		}
+	}
+
+	send(w, http.StatusOK, rsp)
+}
+
+// run dispatches a single bulk operation
+func (h bulkHandler) run(r *http.Request, op *bulkOperationRequest, resolved map[string]string) *bulkOperationResponse {
+	var (
+		method = strings.ToUpper(op.Method)
+		rec    = &bulkRecorder{header: http.Header{}}
+		opRsp  = &bulkOperationResponse{Method: method, BulkId: op.BulkId}
+	)
+
+	fail := func(status int, err error) *bulkOperationResponse {
+		opRsp.Status = strconv.Itoa(status)
+		opRsp.Response, _ = json.Marshal(newErrorResponse(status, err).withType(scimTypeInvalidValue))
+		return opRsp
+	}
+
+	switch method {
+	case http.MethodPost:
+		if op.BulkId == "" {
+			return fail(http.StatusBadRequest, fmt.Errorf("bulkId is required for POST operation"))
+		}
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fail(http.StatusBadRequest, fmt.Errorf("unsupported method: %q", op.Method))
+	}
+
+	if !strings.HasPrefix(op.Path, "/Users") && !strings.HasPrefix(op.Path, "/Groups") {
+		return fail(http.StatusBadRequest, fmt.Errorf("unsupported path: %q", op.Path))
+	}
+
+	var (
+		target = op.Path
+		data   = string(op.Data)
+	)
+
+	// replace references to resources created in the previous operations
+	for bulkId, id := range resolved {
+		target = strings.ReplaceAll(target, "bulkId:"+bulkId, id)
+		data = strings.ReplaceAll(data, "bulkId:"+bulkId, id)
+	}
+
+	if strings.Contains(target, "bulkId:") || strings.Contains(data, "bulkId:") {
+		return fail(http.StatusConflict, fmt.Errorf("unresolved bulkId reference"))
+	}
+
+	// sub-request gets a fresh routing context
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, nil)
+	sub, err := http.NewRequestWithContext(ctx, method, target, strings.NewReader(data))
+	if err != nil {
+		return fail(http.StatusBadRequest, err)
+	}
+
+	sub.Header.Set("Content-Type", contentType)
+	sub.Header.Set("Authorization", r.Header.Get("Authorization"))
+	if op.Version != "" {
+		sub.Header.Set("If-Match", op.Version)
+	}
+
+	h.router.ServeHTTP(rec, sub)
+
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	opRsp.Status = strconv.Itoa(rec.status)
+	opRsp.Version = rec.header.Get("ETag")
+
+	if rec.status >= 400 {
+		opRsp.Response = rec.body.Bytes()
+		return opRsp
+	}
+
+	if method == http.MethodDelete {
+		return opRsp
+	}
+
+	res := resource{}
+	if err = json.Unmarshal(rec.body.Bytes(), &res); err == nil {
+		opRsp.Location = res.location()
+	}
+
+	if op.BulkId != "" && opRsp.Location != "" {
+		// last segment of the location is the ID used in the endpoints
+		resolved[op.BulkId] = path.Base(opRsp.Location)
+	}
+
+	return opRsp
+}
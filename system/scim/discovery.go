@@ -0,0 +1,274 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+)
+
+const (
+	urnServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	urnResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	urnSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+
+	// bulk request limits
+	bulkMaxOperations  = 1000
+	bulkMaxPayloadSize = 1 << 20
+)
+
+type (
+	supportedResponse struct {
+		Supported bool `json:"supported"`
+	}
+
+	serviceProviderConfigResponse struct {
+		Schemas               []string          `json:"schemas"`
+		Meta                  *metaResponse     `json:"meta"`
+		DocumentationUri      string            `json:"documentationUri,omitempty"`
+		Patch                 supportedResponse `json:"patch"`
+		ChangePassword        supportedResponse `json:"changePassword"`
+		Sort                  supportedResponse `json:"sort"`
+		Etag                  supportedResponse `json:"etag"`
+		AuthenticationSchemes []interface{}     `json:"authenticationSchemes"`
+
+		Bulk struct {
+			Supported      bool `json:"supported"`
+			MaxOperations  int  `json:"maxOperations"`
+			MaxPayloadSize int  `json:"maxPayloadSize"`
+		} `json:"bulk"`
+
+		Filter struct {
+			Supported  bool `json:"supported"`
+			MaxResults int  `json:"maxResults"`
+		} `json:"filter"`
+	}
+
+	resourceTypeResponse struct {
+		Schemas     []string      `json:"schemas"`
+		Meta        *metaResponse `json:"meta"`
+		ID          string        `json:"id"`
+		Name        string        `json:"name"`
+		Endpoint    string        `json:"endpoint"`
+		Description string        `json:"description"`
+		Schema      string        `json:"schema"`
+	}
+
+	schemaAttribute struct {
+		Name          string             `json:"name"`
+		Type          string             `json:"type"`
+		MultiValued   bool               `json:"multiValued"`
+		Required      bool               `json:"required"`
+		CaseExact     bool               `json:"caseExact"`
+		Mutability    string             `json:"mutability"`
+		Returned      string             `json:"returned"`
+		Uniqueness    string             `json:"uniqueness"`
+		SubAttributes []*schemaAttribute `json:"subAttributes,omitempty"`
+	}
+
+	schemaResponse struct {
+		Schemas     []string           `json:"schemas"`
+		Meta        *metaResponse      `json:"meta"`
+		ID          string             `json:"id"`
+		Name        string             `json:"name"`
+		Description string             `json:"description"`
+		Attributes  []*schemaAttribute `json:"attributes"`
+	}
+
+	discoveryHandler struct {
+		baseURL string
+	}
+)
+
+// attr returns simple, single valued, read-write attribute definition
+func attr(name, typ string, sub ...*schemaAttribute) *schemaAttribute {
+	return &schemaAttribute{
+		Name:          name,
+		Type:          typ,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: sub,
+	}
+}
+
+func (h discoveryHandler) meta(resourceType, path string) *metaResponse {
+	return &metaResponse{
+		ResourceType: resourceType,
+		Location:     h.baseURL + path,
+	}
+}
+
+func (h discoveryHandler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	rsp := &serviceProviderConfigResponse{
+		Schemas:        []string{urnServiceProviderConfig},
+		Meta:           h.meta("ServiceProviderConfig", "/ServiceProviderConfig"),
+		Patch:          supportedResponse{true},
+		ChangePassword: supportedResponse{true},
+		Sort:           supportedResponse{true},
+		Etag:           supportedResponse{true},
+		AuthenticationSchemes: []interface{}{
+			map[string]interface{}{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": "Authentication scheme using the OAuth Bearer Token Standard",
+				"specUri":     "http://www.rfc-editor.org/info/rfc6750",
+				"primary":     true,
+			},
+		},
+	}
+
+	rsp.Bulk.Supported = true
+	rsp.Bulk.MaxOperations = bulkMaxOperations
+	rsp.Bulk.MaxPayloadSize = bulkMaxPayloadSize
+	rsp.Filter.Supported = true
+	rsp.Filter.MaxResults = listMaxCount
+
+	send(w, http.StatusOK, rsp)
+}
+
+func (h discoveryHandler) resourceTypes() []*resourceTypeResponse {
+	return []*resourceTypeResponse{
+		{
+			Schemas:     []string{urnResourceType},
+			Meta:        h.meta("ResourceType", "/ResourceTypes/User"),
+			ID:          "User",
+			Name:        "User",
+			Endpoint:    "/Users",
+			Description: "User Account",
+			Schema:      urnUser,
+		},
+		{
+			Schemas:     []string{urnResourceType},
+			Meta:        h.meta("ResourceType", "/ResourceTypes/Group"),
+			ID:          "Group",
+			Name:        "Group",
+			Endpoint:    "/Groups",
+			Description: "Group (role)",
+			Schema:      urnGroup,
+		},
+	}
+}
+
+func (h discoveryHandler) listResourceTypes(w http.ResponseWriter, r *http.Request) {
+	var (
+		rr  = h.resourceTypes()
+		out = make([]interface{}, len(rr))
+	)
+
+	for i := range rr {
+		out[i] = rr[i]
+	}
+
+	send(w, http.StatusOK, newListResponse(out...))
+}
+
+func (h discoveryHandler) getResourceType(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, rt := range h.resourceTypes() {
+		if rt.ID == id {
+			send(w, http.StatusOK, rt)
+			return
+		}
+	}
+
+	sendError(w, newErrorfResponse(http.StatusNotFound, "resource type %q not found", id))
+}
+
+func (h discoveryHandler) schemas() []*schemaResponse {
+	var (
+		value = attr("value", "string")
+		typ   = attr("type", "string")
+		prim  = attr("primary", "boolean")
+
+		id = &schemaAttribute{
+			Name:       "id",
+			Type:       "string",
+			CaseExact:  true,
+			Mutability: "readOnly",
+			Returned:   "always",
+			Uniqueness: "server",
+		}
+
+		userName = attr("userName", "string")
+		emails   = attr("emails", "complex", value, typ, prim)
+		members  = attr("members", "complex",
+			attr("value", "string"),
+			attr("display", "string"),
+			attr("$ref", "reference"),
+		)
+	)
+
+	userName.Required = true
+	userName.Uniqueness = "server"
+	emails.MultiValued = true
+	members.MultiValued = true
+
+	return []*schemaResponse{
+		{
+			Schemas:     []string{urnSchema},
+			Meta:        h.meta("Schema", "/Schemas/"+urnUser),
+			ID:          urnUser,
+			Name:        "User",
+			Description: "User Account",
+			Attributes: []*schemaAttribute{
+				id,
+				attr("externalId", "string"),
+				userName,
+				attr("name", "complex",
+					attr("formatted", "string"),
+					attr("givenName", "string"),
+					attr("familyName", "string"),
+				),
+				attr("displayName", "string"),
+				attr("nickName", "string"),
+				emails,
+				attr("active", "boolean"),
+				{
+					Name:       "password",
+					Type:       "string",
+					Mutability: "writeOnly",
+					Returned:   "never",
+					Uniqueness: "none",
+				},
+			},
+		},
+		{
+			Schemas:     []string{urnSchema},
+			Meta:        h.meta("Schema", "/Schemas/"+urnGroup),
+			ID:          urnGroup,
+			Name:        "Group",
+			Description: "Group (role)",
+			Attributes: []*schemaAttribute{
+				id,
+				attr("externalId", "string"),
+				attr("displayName", "string"),
+				members,
+			},
+		},
+	}
+}
+
+func (h discoveryHandler) listSchemas(w http.ResponseWriter, r *http.Request) {
+	var (
+		ss  = h.schemas()
+		out = make([]interface{}, len(ss))
+	)
+
+	for i := range ss {
+		out[i] = ss[i]
+	}
+
+	send(w, http.StatusOK, newListResponse(out...))
+}
+
+func (h discoveryHandler) getSchema(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	for _, s := range h.schemas() {
+		if s.ID == id {
+			send(w, http.StatusOK, s)
+			return
+		}
+	}
+
+	sendError(w, newErrorfResponse(http.StatusNotFound, "schema %q not found", id))
+}
@@ -0,0 +1,592 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SCIM filtering (RFC 7644, section 3.4.2.2)
+//
+// Filters are parsed into an expression tree that is evaluated against
+// the JSON representation of the resource.
+
+type (
+	filterExpr interface {
+		match(res map[string]interface{}) bool
+	}
+
+	// attribute path with optional sub-attribute (emails.value)
+	attrPath struct {
+		attr string
+		sub  string
+	}
+
+	// attrExpr compares attribute value: userName eq "john"
+	attrExpr struct {
+		path  attrPath
+		op    string
+		value interface{}
+	}
+
+	// logExpr joins two expressions with and/or
+	logExpr struct {
+		op          string
+		left, right filterExpr
+	}
+
+	// notExpr negates the expression
+	notExpr struct {
+		expr filterExpr
+	}
+
+	// valuePathExpr filters values of multi-valued attributes: emails[type eq "work"]
+	valuePathExpr struct {
+		attr   string
+		filter filterExpr
+	}
+
+	filterToken struct {
+		// one of (, ), [, ], str or word
+		kind string
+		val  string
+	}
+
+	filterParser struct {
+		tt  []filterToken
+		pos int
+	}
+)
+
+const (
+	filterOpEq = "eq"
+	filterOpNe = "ne"
+	filterOpCo = "co"
+	filterOpSw = "sw"
+	filterOpEw = "ew"
+	filterOpGt = "gt"
+	filterOpGe = "ge"
+	filterOpLt = "lt"
+	filterOpLe = "le"
+	filterOpPr = "pr"
+)
+
+var (
+	filterOperators = map[string]bool{
+		filterOpEq: true, filterOpNe: true, filterOpCo: true, filterOpSw: true, filterOpEw: true,
+		filterOpGt: true, filterOpGe: true, filterOpLt: true, filterOpLe: true, filterOpPr: true,
+	}
+
+	// attributes that are compared case sensitive
+	caseExactAttributes = map[string]bool{
+		"id":            true,
+		"externalid":    true,
+		"members.value": true,
+	}
+
+	// attribute paths can be prefixed with the schema URN of the core resources
+	coreSchemaPrefixes = []string{
+		strings.ToLower(urnUser) + ":",
+		strings.ToLower(urnGroup) + ":",
+	}
+)
+
+// parseFilter parses the SCIM filter expression
+func parseFilter(filter string) (filterExpr, error) {
+	tt, err := tokenizeFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tt) == 0 {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	p := &filterParser{tt: tt}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tt) {
+		return nil, fmt.Errorf("unexpected %q at the end of the filter", p.tt[p.pos].val)
+	}
+
+	return e, nil
+}
+
+func tokenizeFilter(s string) ([]filterToken, error) {
+	var (
+		tt = make([]filterToken, 0, 8)
+		rr = []rune(s)
+	)
+
+	for i := 0; i < len(rr); {
+		switch c := rr[i]; {
+		case unicode.IsSpace(c):
+			i++
+
+		case c == '(' || c == ')' || c == '[' || c == ']':
+			tt = append(tt, filterToken{kind: string(c), val: string(c)})
+			i++
+
+		case c == '"':
+			// find the closing quote, skipping escaped characters
+			j := i + 1
+			for ; j < len(rr) && rr[j] != '"'; j++ {
+				if rr[j] == '\\' {
+					j++
+				}
+			}
+
+			if j >= len(rr) {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+
+			var str string
+			if err := json.Unmarshal([]byte(string(rr[i:j+1])), &str); err != nil {
+				return nil, fmt.Errorf("invalid string in filter: %w", err)
+			}
+
+			tt = append(tt, filterToken{kind: "str", val: str})
+			i = j + 1
+
+		default:
+			j := i
+			for ; j < len(rr) && !unicode.IsSpace(rr[j]) && !strings.ContainsRune(`()[]"`, rr[j]); j++ {
+			}
+
+			tt = append(tt, filterToken{kind: "word", val: string(rr[i:j])})
+			i = j
+		}
+	}
+
+	return tt, nil
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.pos < len(p.tt) {
+		return &p.tt[p.pos]
+	}
+
+	return nil
+}
+
+// keyword checks (case insensitive) if the next token is the given keyword and consumes it
+func (p *filterParser) keyword(kw string) bool {
+	if t := p.peek(); t != nil && t.kind == "word" && strings.EqualFold(t.val, kw) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *filterParser) expect(kind string) error {
+	if t := p.peek(); t == nil || t.kind != kind {
+		return fmt.Errorf("expecting %q in filter", kind)
+	}
+
+	p.pos++
+	return nil
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logExpr{op: "or", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &logExpr{op: "and", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseNot() (filterExpr, error) {
+	if !p.keyword("not") {
+		return p.parseAtom()
+	}
+
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	return &notExpr{expr: e}, nil
+}
+
+func (p *filterParser) parseAtom() (filterExpr, error) {
+	t := p.peek()
+	if t == nil {
+		return nil, fmt.Errorf("unexpected end of the filter")
+	}
+
+	if t.kind == "(" {
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return e, p.expect(")")
+	}
+
+	if t.kind != "word" {
+		return nil, fmt.Errorf("expecting attribute in filter, got %q", t.val)
+	}
+
+	p.pos++
+	path, err := parseAttrPath(t.val)
+	if err != nil {
+		return nil, err
+	}
+
+	if n := p.peek(); n != nil && n.kind == "[" {
+		if path.sub != "" {
+			return nil, fmt.Errorf("unexpected value filter after %q", t.val)
+		}
+
+		p.pos++
+		f, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		return &valuePathExpr{attr: path.attr, filter: f}, p.expect("]")
+	}
+
+	op := p.peek()
+	if op == nil || op.kind != "word" || !filterOperators[strings.ToLower(op.val)] {
+		return nil, fmt.Errorf("expecting operator after %q", t.val)
+	}
+
+	p.pos++
+	e := &attrExpr{path: path, op: strings.ToLower(op.val)}
+	if e.op == filterOpPr {
+		return e, nil
+	}
+
+	v := p.peek()
+	switch {
+	case v == nil:
+		return nil, fmt.Errorf("expecting value after %q", op.val)
+
+	case v.kind == "str":
+		e.value = v.val
+
+	case v.kind == "word" && strings.EqualFold(v.val, "true"):
+		e.value = true
+
+	case v.kind == "word" && strings.EqualFold(v.val, "false"):
+		e.value = false
+
+	case v.kind == "word" && strings.EqualFold(v.val, "null"):
+		e.value = nil
+
+	default:
+		var n float64
+		if err = json.Unmarshal([]byte(v.val), &n); err != nil || v.kind != "word" {
+			return nil, fmt.Errorf("invalid value %q in filter", v.val)
+		}
+
+		e.value = n
+	}
+
+	p.pos++
+	return e, nil
+}
+
+// parseAttrPath parses attribute path with optional schema URN prefix and sub-attribute
+func parseAttrPath(path string) (attrPath, error) {
+	lc := strings.ToLower(path)
+	for _, prefix := range coreSchemaPrefixes {
+		if strings.HasPrefix(lc, prefix) {
+			path = path[len(prefix):]
+			break
+		}
+	}
+
+	if strings.HasPrefix(strings.ToLower(path), "urn:") {
+		// attribute from one of the extension schemas;
+		// there are no sub-attributes we could match
+		return attrPath{attr: path}, nil
+	}
+
+	pp := strings.Split(path, ".")
+	if len(pp) > 2 || pp[0] == "" || (len(pp) == 2 && pp[1] == "") {
+		return attrPath{}, fmt.Errorf("invalid attribute path %q", path)
+	}
+
+	if len(pp) == 2 {
+		return attrPath{attr: pp[0], sub: pp[1]}, nil
+	}
+
+	return attrPath{attr: pp[0]}, nil
+}
+
+func (p attrPath) String() string {
+	if p.sub != "" {
+		return p.attr + "." + p.sub
+	}
+
+	return p.attr
+}
+
+// values returns all of the values of the attribute
+//
+// Values of multi-valued complex attributes (emails, members) without
+// the sub-attribute are matched by the "value" sub-attribute
+func (p attrPath) values(res map[string]interface{}) []interface{} {
+	v, ok := lookupAttr(res, p.attr)
+	if !ok {
+		return nil
+	}
+
+	sub := func(v interface{}) (interface{}, bool) {
+		m, is := v.(map[string]interface{})
+		switch {
+		case !is:
+			return v, p.sub == ""
+		case p.sub == "":
+			return lookupAttr(m, "value")
+		default:
+			return lookupAttr(m, p.sub)
+		}
+	}
+
+	vv := make([]interface{}, 0, 1)
+	if aa, is := v.([]interface{}); is {
+		for _, a := range aa {
+			if s, ok := sub(a); ok {
+				vv = append(vv, s)
+			}
+		}
+	} else if s, ok := sub(v); ok {
+		vv = append(vv, s)
+	}
+
+	return vv
+}
+
+// lookupAttr finds the attribute by its name; attribute names are case insensitive
+func lookupAttr(res map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := res[name]; ok {
+		return v, true
+	}
+
+	for k, v := range res {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func (e *attrExpr) match(res map[string]interface{}) bool {
+	var (
+		vv        = e.path.values(res)
+		caseExact = caseExactAttributes[strings.ToLower(e.path.String())]
+	)
+
+	switch e.op {
+	case filterOpPr:
+		for _, v := range vv {
+			if !isEmptyValue(v) {
+				return true
+			}
+		}
+
+		return false
+
+	case filterOpNe:
+		return !(&attrExpr{path: e.path, op: filterOpEq, value: e.value}).match(res)
+
+	case filterOpEq:
+		if e.value == nil {
+			return len(vv) == 0
+		}
+	}
+
+	for _, v := range vv {
+		if compareValues(v, e.op, e.value, caseExact) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *logExpr) match(res map[string]interface{}) bool {
+	if e.op == "and" {
+		return e.left.match(res) && e.right.match(res)
+	}
+
+	return e.left.match(res) || e.right.match(res)
+}
+
+func (e *notExpr) match(res map[string]interface{}) bool {
+	return !e.expr.match(res)
+}
+
+func (e *valuePathExpr) match(res map[string]interface{}) bool {
+	v, _ := lookupAttr(res, e.attr)
+
+	aa, is := v.([]interface{})
+	if !is {
+		aa = []interface{}{v}
+	}
+
+	for _, a := range aa {
+		if m, is := a.(map[string]interface{}); is && e.filter.match(m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compareValues compares the attribute value (a) with the value from the filter (b)
+func compareValues(a interface{}, op string, b interface{}, caseExact bool) bool {
+	switch av := a.(type) {
+	case string:
+		bv, ok := b.(string)
+		if !ok {
+			return false
+		}
+
+		if at, err := time.Parse(time.RFC3339Nano, av); err == nil {
+			if bt, err := time.Parse(time.RFC3339Nano, bv); err == nil {
+				return compareOrdered(op, at.Before(bt), at.Equal(bt))
+			}
+		}
+
+		if !caseExact {
+			av, bv = strings.ToLower(av), strings.ToLower(bv)
+		}
+
+		switch op {
+		case filterOpCo:
+			return strings.Contains(av, bv)
+		case filterOpSw:
+			return strings.HasPrefix(av, bv)
+		case filterOpEw:
+			return strings.HasSuffix(av, bv)
+		}
+
+		return compareOrdered(op, av < bv, av == bv)
+
+	case float64:
+		bv, ok := b.(float64)
+		if !ok {
+			return false
+		}
+
+		return compareOrdered(op, av < bv, av == bv)
+
+	case bool:
+		bv, ok := b.(bool)
+		return ok && op == filterOpEq && av == bv
+	}
+
+	return false
+}
+
+func compareOrdered(op string, less, equal bool) bool {
+	switch op {
+	case filterOpEq:
+		return equal
+	case filterOpGt:
+		return !less && !equal
+	case filterOpGe:
+		return !less
+	case filterOpLt:
+		return less
+	case filterOpLe:
+		return less || equal
+	}
+
+	return false
+}
+
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// filterEquals collects attributes compared with eq operator that must all
+// match (joined with and) so that the search can be narrowed down in the store
+//
+// Keys are lowercased attribute paths
+func filterEquals(e filterExpr) map[string]string {
+	var (
+		eq   = make(map[string]string)
+		walk func(filterExpr, string)
+	)
+
+	walk = func(e filterExpr, prefix string) {
+		switch e := e.(type) {
+		case *logExpr:
+			if e.op == "and" {
+				walk(e.left, prefix)
+				walk(e.right, prefix)
+			}
+
+		case *valuePathExpr:
+			walk(e.filter, strings.ToLower(e.attr)+".")
+
+		case *attrExpr:
+			if s, ok := e.value.(string); ok && e.op == filterOpEq {
+				eq[prefix+strings.ToLower(e.path.String())] = s
+			}
+		}
+	}
+
+	walk(e, "")
+	return eq
+}
@@ -0,0 +1,84 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterMatch(t *testing.T) {
+	const user = `{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"id": "42",
+		"userName": "John.Doe",
+		"name": {"formatted": "John Doe"},
+		"active": true,
+		"emails": [
+			{"value": "john@example.com", "type": "work"},
+			{"value": "jd@home.org", "type": "home"}
+		],
+		"meta": {"created": "2021-03-01T10:00:00Z"}
+	}`
+
+	tests := []struct {
+		filter string
+		match  bool
+	}{
+		{`userName eq "john.doe"`, true},
+		{`USERNAME Eq "john.doe"`, true},
+		{`id eq "42"`, true},
+		{`userName ne "john.doe"`, false},
+		{`name.formatted co "Doe"`, true},
+		{`userName sw "J"`, true},
+		{`userName ew "x"`, false},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "john.doe"`, true},
+		{`emails co "example.com"`, true},
+		{`emails[type eq "work" and value ew ".com"]`, true},
+		{`emails[type eq "other"]`, false},
+		{`emails.type eq "home"`, true},
+		{`active eq true`, true},
+		{`active eq false`, false},
+		{`nickName pr`, false},
+		{`not (nickName pr)`, true},
+		{`meta.created gt "2021-01-01T00:00:00Z"`, true},
+		{`meta.created lt "2021-01-01T00:00:00Z"`, false},
+		{`userName eq "foo" or (active eq true and id eq "42")`, true},
+		{`userName eq "foo" or active eq true and id eq "1"`, false},
+	}
+
+	res := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(user), &res))
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			f, err := parseFilter(tt.filter)
+			require.NoError(t, err)
+			require.Equal(t, tt.match, f.match(res))
+		})
+	}
+}
+
+func TestFilterParseErrors(t *testing.T) {
+	for _, filter := range []string{
+		``,
+		`userName`,
+		`userName xx "foo"`,
+		`userName eq`,
+		`userName eq "foo`,
+		`(userName eq "foo"`,
+		`emails[type eq "work"`,
+		`userName eq "foo" and`,
+	} {
+		t.Run(filter, func(t *testing.T) {
+			_, err := parseFilter(filter)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFilterEquals(t *testing.T) {
+	f, err := parseFilter(`userName eq "foo" and emails[value eq "a@b.c"] and (active eq true or id eq "1")`)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"username": "foo", "emails.value": "a@b.c"}, filterEquals(f))
+}
@@ -2,9 +2,11 @@ package scim
 
 import (
 	"fmt"
-	"github.com/cortezaproject/corteza-server/system/types"
 	"net/http"
 	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/system/types"
 )
 
 type (
@@ -12,6 +14,8 @@ type (
 		ResourceType string     `json:"resourceType"`
 		Created      time.Time  `json:"created"`
 		LastModified *time.Time `json:"lastModified,omitempty"`
+		Location     string     `json:"location,omitempty"`
+		Version      string     `json:"version,omitempty"`
 	}
 
 	errorResponse struct {
@@ -24,6 +28,16 @@ type (
 
 const (
 	urnError = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// detail error types (RFC 7644, section 3.12)
+	scimTypeInvalidFilter = "invalidFilter"
+	scimTypeTooMany       = "tooMany"
+	scimTypeUniqueness    = "uniqueness"
+	scimTypeInvalidSyntax = "invalidSyntax"
+	scimTypeInvalidPath   = "invalidPath"
+	scimTypeNoTarget      = "noTarget"
+	scimTypeInvalidValue  = "invalidValue"
+	scimTypeInvalidVers   = "invalidVers"
 )
 
 func newUserMetaResponse(u *types.User) *metaResponse {
@@ -67,6 +81,24 @@ func newErrorResponse(httpStatus int, err error) *errorResponse {
 	return er
 }
 
+// errorStatus translates the service error into HTTP status
+func errorStatus(err error, def int) int {
+	switch {
+	case errors.IsNotFound(err):
+		return http.StatusNotFound
+	case errors.IsDuplicateData(err):
+		return http.StatusConflict
+	}
+
+	return def
+}
+
+// withType sets the SCIM detail error type
+func (e *errorResponse) withType(scimType string) *errorResponse {
+	e.SCIMType = scimType
+	return e
+}
+
 func (e *errorResponse) Error() string {
 	return e.Detail
 }
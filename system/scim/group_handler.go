@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -18,6 +19,7 @@ type (
 	groupsHandler struct {
 		externalIdAsPrimary bool
 		externalIdValidator *regexp.Regexp
+		baseURL             string
 
 		svc     service.RoleService
 		userSvc service.UserService
@@ -27,14 +29,104 @@ type (
 
 func (h groupsHandler) get(w http.ResponseWriter, r *http.Request) {
 	var (
-		res = h.lookup(h.sec(r), chi.URLParam(r, "id"), w)
+		ctx = h.sec(r)
+		res = h.lookup(ctx, chi.URLParam(r, "id"), w)
 	)
 
 	if res == nil {
 		return
 	}
 
-	send(w, http.StatusOK, newGroupResourceResponse(res))
+	h.send(ctx, w, r, http.StatusOK, res)
+}
+
+// list searches for groups
+//
+// Equality conditions on displayName, externalId, id and members
+// are used to narrow down the search.
+func (h groupsHandler) list(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = h.sec(r)
+		f   = types.RoleFilter{Archived: filter.StateInclusive}
+	)
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	// members are loaded only when needed
+	withMembers := req.wants("members") || req.uses("members")
+
+	rr := make([]resource, 0)
+	ok, err := true, nil
+	if req.filter != nil {
+		ok, err = h.narrow(ctx, &f, filterEquals(req.filter))
+		if err != nil {
+			sendError(w, err)
+			return
+		}
+	}
+
+	if ok {
+		roles, _, err := h.svc.Find(ctx, f)
+		if err != nil {
+			sendError(w, newErrorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		for _, role := range roles {
+			res, err := h.resource(ctx, role, withMembers)
+			if err != nil {
+				sendError(w, err)
+				return
+			}
+
+			rr = append(rr, res)
+		}
+	}
+
+	rsp, err := req.page(rr)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	send(w, http.StatusOK, rsp)
+}
+
+// narrow sets role filter from the equality conditions
+//
+// Returns false when no role can match
+func (h groupsHandler) narrow(ctx context.Context, f *types.RoleFilter, eq map[string]string) (bool, error) {
+	for attr, v := range eq {
+		switch attr {
+		case "id":
+			ID, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return false, nil
+			}
+
+			f.RoleID = append(f.RoleID, ID)
+
+		case "displayname":
+			f.Name = v
+
+		case "externalid":
+			f.Labels = map[string]string{groupLabel_SCIM_externalId: v}
+
+		case "members", "members.value":
+			u, err := h.lookupUser(ctx, v)
+			if err != nil || u == nil {
+				return false, nil
+			}
+
+			f.MemberID = u.ID
+		}
+	}
+
+	return true, nil
 }
 
 func (h groupsHandler) create(w http.ResponseWriter, r *http.Request) {
@@ -61,7 +153,7 @@ func (h groupsHandler) create(w http.ResponseWriter, r *http.Request) {
 				sendError(w, err)
 				return
 			}
-		} else if *payload.Name != "" {
+		} else if payload.Name != nil && *payload.Name != "" {
 			existing, err = svc.FindByName(ctx, *payload.Name)
 			if err != nil && !errors.Is(err, service.RoleErrNotFound()) {
 				sendError(w, err)
@@ -81,7 +173,7 @@ func (h groupsHandler) create(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusCreated
 	}
 
-	send(w, status, newGroupResourceResponse(res))
+	h.send(ctx, w, r, status, res)
 }
 
 func (h groupsHandler) replace(w http.ResponseWriter, r *http.Request) {
@@ -93,6 +185,10 @@ func (h groupsHandler) replace(w http.ResponseWriter, r *http.Request) {
 		payload  = &groupResourceRequest{}
 	)
 
+	if existing == nil || !h.checkVersion(ctx, w, r, existing) {
+		return
+	}
+
 	if err := payload.decodeJSON(r.Body); err != nil {
 		sendError(w, newErrorResponse(http.StatusBadRequest, err))
 		return
@@ -104,129 +200,79 @@ func (h groupsHandler) replace(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := http.StatusOK
-	if res.UpdatedAt == nil {
-		status = http.StatusCreated
-	}
-
-	send(w, status, newGroupResourceResponse(res))
+	h.send(ctx, w, r, http.StatusOK, res)
 }
 
 // patches group
 //
-// only supports adding and removing members
+// Operations are applied to the group resource (with members);
+// name and external ID are saved and memberships are synced.
+//
+// Responds with the patched resource only when attributes are requested
 func (h groupsHandler) patch(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	var (
 		ctx     = h.sec(r)
-		svc     = h.svc
-		res     = h.lookup(ctx, chi.URLParam(r, "id"), w)
+		role    = h.lookup(ctx, chi.URLParam(r, "id"), w)
 		payload = &operationsRequest{}
+		req     = &groupResourceRequest{}
 	)
 
-	if res == nil {
+	if role == nil || !h.checkVersion(ctx, w, r, role) {
 		return
 	}
 
 	if err := payload.decodeJSON(r.Body); err != nil {
-		sendError(w, newErrorResponse(http.StatusBadRequest, err))
+		sendError(w, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidSyntax))
 		return
 	}
 
-	var (
-		u           *types.User
-		ops         = make([]func() error, 0, len(payload.Operations))
-		err         error
-		memberships = make(map[uint64]bool)
-	)
-
-	{
-		// collect all existing memberships into a simple map
-		// to ensure we dont step on our feet (too much)
-		//
-		// this is not 100% bulletproof for concurrent modifications
-		mm, _, err := store.SearchRoleMembers(ctx, service.DefaultStore, types.RoleMemberFilter{RoleID: res.ID})
-		if err != nil {
-			sendError(w, err)
-			return
-		}
+	res, err := h.resource(ctx, role, true)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
 
-		for _, m := range mm {
-			memberships[m.UserID] = true
-		}
+	if err = payload.apply(res); err != nil {
+		sendError(w, err)
+		return
 	}
 
-	// validate and collect operations
-	for _, op := range payload.Operations {
-		// iterate through operation's values, load user and schedule op
-		for path, userExternalId := range op.Value {
-			if path != "members" {
-				// allow only "members" path
-				sendError(w, newErrorfResponse(http.StatusBadRequest, "unsupported path: %q", op.Path))
-				return
-			}
+	if err = res.decode(req); err != nil {
+		sendError(w, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidValue))
+		return
+	}
 
-			u, err = lookupUserByExternalId(ctx, h.userSvc, h.externalIdValidator, userExternalId)
-			if err != nil {
-				sendError(w, err)
-				return
-			}
+	if req.Members == nil {
+		// all members were removed
+		req.Members = []*groupMemberRequest{}
+	}
 
-			if u == nil {
-				sendError(w, newErrorfResponse(http.StatusBadRequest, "no such user: %q", userExternalId))
-				return
-			}
+	if req.changes(role) {
+		role, err = h.save(ctx, req, role)
+	} else {
+		err = h.syncMembers(ctx, role, req.memberValues())
+	}
 
-			// making sure u is not overwritten
-			// in the next iteration
-			memberId := u.ID
-
-			switch op.Operation {
-			case patchOpAdd:
-				// support for add operation,
-				// check if there members already exist
-				ops = append(ops, func() error {
-					if memberships[memberId] {
-						// already added
-						return nil
-					}
-
-					memberships[memberId] = true
-					return svc.MemberAdd(ctx, res.ID, memberId)
-				})
-
-			case patchOpRemove:
-				// support for remove operation,
-				// check if there members are missing
-				ops = append(ops, func() error {
-					if !memberships[memberId] {
-						// already removed
-						return nil
-					}
-
-					delete(memberships, memberId)
-					return svc.MemberRemove(ctx, res.ID, memberId)
-				})
-
-			default:
-				sendError(w, newErrorfResponse(http.StatusBadRequest, "unsupported operation: %q", op.Operation))
-				return
-			}
-		}
+	if err != nil {
+		sendError(w, err)
+		return
 	}
 
-	// run all scheduled ops
-	for _, op := range ops {
-		if err = op(); err != nil {
-			sendError(w, err)
-			return
+	if r.URL.Query().Get("attributes") == "" {
+		if res, err = h.resource(ctx, role, true); err == nil {
+			w.Header().Set("ETag", res.version())
 		}
+
+		send(w, http.StatusNoContent, nil)
+		return
 	}
 
-	send(w, http.StatusNoContent, nil)
+	h.send(ctx, w, r, http.StatusOK, role)
 }
 
+// save creates or updates the role and syncs memberships when members are set
 func (h groupsHandler) save(ctx context.Context, req *groupResourceRequest, existing *types.Role) (res *types.Role, err error) {
 	var (
 		svc = h.svc
@@ -251,9 +297,174 @@ func (h groupsHandler) save(ctx context.Context, req *groupResourceRequest, exis
 		return nil, newErrorResponse(http.StatusInternalServerError, err)
 	}
 
+	if req.Members != nil {
+		if err = h.syncMembers(ctx, res, req.memberValues()); err != nil {
+			return nil, err
+		}
+	}
+
 	return res, nil
 }
 
+// syncMembers adds and removes members so that they match the given values (user IDs)
+func (h groupsHandler) syncMembers(ctx context.Context, role *types.Role, values []string) error {
+	current, err := h.members(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		svc      = h.svc
+		existing = make(map[string]uint64, len(current))
+		wanted   = make(map[string]bool, len(values))
+	)
+
+	for _, m := range current {
+		existing[m.value] = m.userID
+	}
+
+	for _, v := range values {
+		wanted[v] = true
+		if _, ok := existing[v]; ok {
+			continue
+		}
+
+		u, err := h.lookupUser(ctx, v)
+		if err != nil {
+			return err
+		}
+
+		if u == nil {
+			return newErrorfResponse(http.StatusBadRequest, "no such user: %q", v).withType(scimTypeInvalidValue)
+		}
+
+		if err = svc.MemberAdd(ctx, role.ID, u.ID); err != nil {
+			return err
+		}
+
+		existing[v] = u.ID
+	}
+
+	for v, userID := range existing {
+		if wanted[v] {
+			continue
+		}
+
+		if err = svc.MemberRemove(ctx, role.ID, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type (
+	groupMember struct {
+		userID uint64
+		value  string
+		user   *types.User
+	}
+)
+
+// members loads all members of the role
+func (h groupsHandler) members(ctx context.Context, roleID uint64) ([]*groupMember, error) {
+	mm, _, err := store.SearchRoleMembers(ctx, service.DefaultStore, types.RoleMemberFilter{RoleID: roleID})
+	if err != nil || len(mm) == 0 {
+		return nil, err
+	}
+
+	var (
+		ids = make([]uint64, len(mm))
+		out = make([]*groupMember, 0, len(mm))
+	)
+
+	for i, m := range mm {
+		ids[i] = m.UserID
+	}
+
+	uu, _, err := h.userSvc.Find(ctx, types.UserFilter{UserID: ids, Suspended: filter.StateInclusive})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range uu {
+		out = append(out, &groupMember{
+			userID: u.ID,
+			value:  userResourceID(u, h.externalIdAsPrimary),
+			user:   u,
+		})
+	}
+
+	return out, nil
+}
+
+// resource converts role into SCIM resource
+func (h groupsHandler) resource(ctx context.Context, role *types.Role, withMembers bool) (resource, error) {
+	rsp := newGroupResourceResponse(role)
+
+	if withMembers {
+		mm, err := h.members(ctx, role.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range mm {
+			display := m.user.Name
+			if display == "" {
+				display = m.user.Email
+			}
+
+			rsp.Members = append(rsp.Members, &groupMemberResponse{
+				Value:   m.value,
+				Display: display,
+				Ref:     h.baseURL + "/Users/" + m.value,
+			})
+		}
+	}
+
+	res, err := newResource(rsp)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.stamp(h.baseURL + "/Groups/" + groupResourceID(role, h.externalIdAsPrimary)), nil
+}
+
+func (h groupsHandler) send(ctx context.Context, w http.ResponseWriter, r *http.Request, status int, role *types.Role) {
+	q := r.URL.Query()
+	req := &listRequest{
+		Attributes:         splitAttributes(q.Get("attributes")),
+		ExcludedAttributes: splitAttributes(q.Get("excludedAttributes")),
+	}
+
+	res, err := h.resource(ctx, role, req.wants("members"))
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	sendResource(w, r, status, res)
+}
+
+// checkVersion checks the If-Match header; handles errors by writing them to response
+func (h groupsHandler) checkVersion(ctx context.Context, w http.ResponseWriter, r *http.Request, role *types.Role) bool {
+	if r.Header.Get("If-Match") == "" {
+		return true
+	}
+
+	res, err := h.resource(ctx, role, true)
+	if err == nil {
+		err = checkVersion(r, res)
+	}
+
+	if err != nil {
+		sendError(w, err)
+		return false
+	}
+
+	return true
+}
+
 func (h groupsHandler) delete(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx = h.sec(r)
@@ -261,7 +472,7 @@ func (h groupsHandler) delete(w http.ResponseWriter, r *http.Request) {
 		res = h.lookup(ctx, chi.URLParam(r, "id"), w)
 	)
 
-	if res == nil {
+	if res == nil || !h.checkVersion(ctx, w, r, res) {
 		return
 	}
 
@@ -301,7 +512,7 @@ func (h groupsHandler) lookup(ctx context.Context, id string, w http.ResponseWri
 
 		role, err := svc.FindByID(ctx, id)
 		if err != nil {
-			sendError(w, newErrorResponse(http.StatusBadRequest, err))
+			sendError(w, newErrorResponse(errorStatus(err, http.StatusBadRequest), err))
 			return nil
 		}
 
@@ -309,6 +520,25 @@ func (h groupsHandler) lookup(ctx context.Context, id string, w http.ResponseWri
 	}
 }
 
+// lookupUser finds the member by the ID used in the SCIM API endpoints
+func (h groupsHandler) lookupUser(ctx context.Context, id string) (*types.User, error) {
+	if h.externalIdAsPrimary {
+		return lookupUserByExternalId(ctx, h.userSvc, h.externalIdValidator, id)
+	}
+
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return nil, newErrorfResponse(http.StatusBadRequest, "invalid user ID: %q", id).withType(scimTypeInvalidValue)
+	}
+
+	u, err := h.userSvc.FindByID(ctx, userID)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return u, err
+}
+
 func (h groupsHandler) lookupByExternalId(ctx context.Context, id string) (r *types.Role, err error) {
 	if h.externalIdValidator != nil && !h.externalIdValidator.MatchString(id) {
 		return nil, newErrorfResponse(http.StatusBadRequest, "invalid external ID")
@@ -328,3 +558,12 @@ func (h groupsHandler) lookupByExternalId(ctx context.Context, id string) (r *ty
 		return nil, newErrorfResponse(http.StatusPreconditionFailed, "more than one group matches this externalId")
 	}
 }
+
+// groupResourceID returns ID that is used to reference the group in the SCIM API endpoints
+func groupResourceID(r *types.Role, externalIdAsPrimary bool) string {
+	if ID := r.Labels[groupLabel_SCIM_externalId]; externalIdAsPrimary && ID != "" {
+		return ID
+	}
+
+	return strconv.FormatUint(r.ID, 10)
+}
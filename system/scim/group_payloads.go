@@ -3,9 +3,10 @@ package scim
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/cortezaproject/corteza-server/system/types"
 	"io"
 	"strconv"
+
+	"github.com/cortezaproject/corteza-server/system/types"
 )
 
 const (
@@ -14,12 +15,23 @@ const (
 )
 
 type (
+	groupMemberResponse struct {
+		Value   string `json:"value"`
+		Display string `json:"display,omitempty"`
+		Ref     string `json:"$ref,omitempty"`
+	}
+
+	groupMemberRequest struct {
+		Value string `json:"value"`
+	}
+
 	groupResourceResponse struct {
-		Schemas    []string      `json:"schemas"`
-		Meta       *metaResponse `json:"meta,omitempty"`
-		ID         string        `json:"id,omitempty"`
-		ExternalId string        `json:"externalId,omitempty"`
-		Name       string        `json:"displayName"`
+		Schemas    []string               `json:"schemas"`
+		Meta       *metaResponse          `json:"meta,omitempty"`
+		ID         string                 `json:"id,omitempty"`
+		ExternalId string                 `json:"externalId,omitempty"`
+		Name       string                 `json:"displayName"`
+		Members    []*groupMemberResponse `json:"members,omitempty"`
 	}
 
 	groupResourceRequest struct {
@@ -27,6 +39,9 @@ type (
 		Meta       *metaResponse `json:"meta,omitempty"`
 		ExternalId *string       `json:"externalId,omitempty"`
 		Name       *string       `json:"displayName"`
+
+		// nil when members are not set
+		Members []*groupMemberRequest `json:"members"`
 	}
 )
 
@@ -59,3 +74,21 @@ func (req *groupResourceRequest) applyTo(u *types.Role) {
 		u.SetLabel("SCIM_externalId", *req.ExternalId)
 	}
 }
+
+// changes checks if the request modifies the role itself (not just members)
+func (req *groupResourceRequest) changes(r *types.Role) bool {
+	return (req.Name != nil && *req.Name != r.Name) ||
+		(req.ExternalId != nil && *req.ExternalId != r.Labels[groupLabel_SCIM_externalId])
+}
+
+// memberValues returns values (user IDs) of all members
+func (req *groupResourceRequest) memberValues() []string {
+	vv := make([]string, 0, len(req.Members))
+	for _, m := range req.Members {
+		if m != nil && m.Value != "" {
+			vv = append(vv, m.Value)
+		}
+	}
+
+	return vv
+}
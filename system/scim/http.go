@@ -2,12 +2,18 @@ package scim
 
 import (
 	"encoding/json"
-	"go.uber.org/zap"
 	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	contentType = "application/scim+json"
 )
 
 func send(w http.ResponseWriter, status int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(status)
 
 	if status == http.StatusNoContent || payload == nil {
@@ -20,13 +26,75 @@ func send(w http.ResponseWriter, status int, payload interface{}) {
 }
 
 func sendError(w http.ResponseWriter, err error) {
-	var (
-		status = http.StatusInternalServerError
-	)
+	er, ok := err.(*errorResponse)
+	if !ok {
+		er = newErrorResponse(errorStatus(err, http.StatusInternalServerError), err)
+		if er.Status == http.StatusConflict {
+			er.SCIMType = scimTypeUniqueness
+		}
+	}
+
+	send(w, er.Status, er)
+}
+
+// sendResource sends single resource with its version in the ETag header
+//
+// Handles conditional GET requests (If-None-Match) and attributes and
+// excludedAttributes query parameters
+func sendResource(w http.ResponseWriter, r *http.Request, status int, res resource) {
+	version := res.version()
+	w.Header().Set("ETag", version)
+
+	if status == http.StatusCreated {
+		w.Header().Set("Location", res.location())
+	}
+
+	if r.Method == http.MethodGet && matchVersion(r.Header.Get("If-None-Match"), version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	q := r.URL.Query()
+	send(w, status, res.project(splitAttributes(q.Get("attributes")), splitAttributes(q.Get("excludedAttributes"))))
+}
+
+// checkVersion compares current version of the resource with the If-Match header
+func checkVersion(r *http.Request, res resource) error {
+	im := r.Header.Get("If-Match")
+	if im == "" || matchVersion(im, res.version()) {
+		return nil
+	}
+
+	return newErrorfResponse(http.StatusPreconditionFailed, "resource version mismatch").withType(scimTypeInvalidVers)
+}
+
+// matchVersion checks if any of the (comma separated) entity tags from the header match the version
+//
+// Weak comparison is used since all versions are weak.
+func matchVersion(header, version string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(version, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func splitAttributes(s string) []string {
+	if s == "" {
+		return nil
+	}
 
-	if er, ok := err.(*errorResponse); ok {
-		status = er.Status
+	aa := strings.Split(s, ",")
+	for i := range aa {
+		aa[i] = strings.TrimSpace(aa[i])
 	}
 
-	send(w, status, err)
+	return aa
 }
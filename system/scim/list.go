@@ -0,0 +1,234 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	urnListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+
+	// default and max number of resources on one page
+	listDefaultCount = 100
+	listMaxCount     = 1000
+
+	// number of resources loaded from the store at once
+	listBatchSize = 500
+)
+
+type (
+	// listRequest holds query parameters or body of the search request
+	listRequest struct {
+		Schemas            []string `json:"schemas"`
+		Filter             string   `json:"filter"`
+		SortBy             string   `json:"sortBy"`
+		SortOrder          string   `json:"sortOrder"`
+		StartIndex         int      `json:"startIndex"`
+		Count              *int     `json:"count"`
+		Attributes         []string `json:"attributes"`
+		ExcludedAttributes []string `json:"excludedAttributes"`
+
+		filter filterExpr
+	}
+
+	// listPager collects resources on the requested page
+	// while (sorted) resources are read in batches from the store
+	listPager struct {
+		req   *listRequest
+		rsp   *listResponse
+		count int
+	}
+
+	listResponse struct {
+		Schemas      []string      `json:"schemas"`
+		TotalResults int           `json:"totalResults"`
+		StartIndex   int           `json:"startIndex"`
+		ItemsPerPage int           `json:"itemsPerPage"`
+		Resources    []interface{} `json:"Resources"`
+	}
+)
+
+// parseListRequest reads list parameters from the query string (GET)
+// or from the request body (POST /.search)
+func parseListRequest(r *http.Request) (req *listRequest, err error) {
+	req = &listRequest{}
+
+	if r.Method == http.MethodPost {
+		defer r.Body.Close()
+		if err = json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, newErrorfResponse(http.StatusBadRequest, "could not decode search request: %v", err).withType(scimTypeInvalidSyntax)
+		}
+	} else {
+		q := r.URL.Query()
+		req.Filter = q.Get("filter")
+		req.SortBy = q.Get("sortBy")
+		req.SortOrder = q.Get("sortOrder")
+		req.Attributes = splitAttributes(q.Get("attributes"))
+		req.ExcludedAttributes = splitAttributes(q.Get("excludedAttributes"))
+
+		if v := q.Get("startIndex"); v != "" {
+			if req.StartIndex, err = strconv.Atoi(v); err != nil {
+				return nil, newErrorfResponse(http.StatusBadRequest, "invalid startIndex").withType(scimTypeInvalidValue)
+			}
+		}
+
+		if v := q.Get("count"); v != "" {
+			c, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, newErrorfResponse(http.StatusBadRequest, "invalid count").withType(scimTypeInvalidValue)
+			}
+
+			req.Count = &c
+		}
+	}
+
+	if req.Filter != "" {
+		if req.filter, err = parseFilter(req.Filter); err != nil {
+			return nil, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidFilter)
+		}
+	}
+
+	if req.SortBy != "" {
+		if _, err = parseAttrPath(req.SortBy); err != nil {
+			return nil, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidValue)
+		}
+	}
+
+	return req, nil
+}
+
+// wants checks if the attribute is returned
+func (req *listRequest) wants(attr string) bool {
+	has := func(aa []string) bool {
+		for _, a := range aa {
+			if p, err := parseAttrPath(a); err == nil && strings.EqualFold(p.attr, attr) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(req.Attributes) > 0 {
+		return has(req.Attributes)
+	}
+
+	return !has(req.ExcludedAttributes)
+}
+
+// uses checks if the filter or sorting uses the attribute
+func (req *listRequest) uses(attr string) bool {
+	attr = strings.ToLower(attr)
+	return strings.Contains(strings.ToLower(req.Filter), attr) || strings.Contains(strings.ToLower(req.SortBy), attr)
+}
+
+// page filters, sorts and slices the resources
+//
+// Store search is narrowed down with equality conditions from the filter;
+// the whole filter is evaluated here since SCIM filters are richer than what
+// the store supports and SCIM pagination is index based.
+func (req *listRequest) page(rr []resource) (*listResponse, error) {
+	var (
+		out = make([]resource, 0, len(rr))
+	)
+
+	for _, res := range rr {
+		if req.filter == nil || req.filter.match(res) {
+			out = append(out, res)
+		}
+	}
+
+	if req.SortBy != "" {
+		if err := sortResources(out, req.SortBy, strings.EqualFold(req.SortOrder, "descending")); err != nil {
+			return nil, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidValue)
+		}
+	}
+
+	var (
+		start, count = req.window()
+	)
+
+	rsp := &listResponse{
+		Schemas:      []string{urnListResponse},
+		TotalResults: len(out),
+		StartIndex:   start,
+		Resources:    make([]interface{}, 0, count),
+	}
+
+	for i := start - 1; i < len(out) && len(rsp.Resources) < count; i++ {
+		rsp.Resources = append(rsp.Resources, out[i].project(req.Attributes, req.ExcludedAttributes))
+	}
+
+	rsp.ItemsPerPage = len(rsp.Resources)
+	return rsp, nil
+}
+
+// window returns 1-based index of the first resource and max number of resources on the page
+func (req *listRequest) window() (start, count int) {
+	start, count = req.StartIndex, listDefaultCount
+
+	// startIndex is 1-based
+	if start < 1 {
+		start = 1
+	}
+
+	if req.Count != nil {
+		count = *req.Count
+	}
+
+	if count < 0 {
+		count = 0
+	}
+
+	if count > listMaxCount {
+		count = listMaxCount
+	}
+
+	return
+}
+
+// pager returns list pager for resources that are already sorted
+func (req *listRequest) pager() *listPager {
+	start, count := req.window()
+	return &listPager{
+		req: req,
+		rsp: &listResponse{
+			Schemas:    []string{urnListResponse},
+			StartIndex: start,
+			Resources:  make([]interface{}, 0, count),
+		},
+		count: count,
+	}
+}
+
+// add filters the resource and adds it to the page when it falls into the requested window
+//
+// All matching resources are counted as they make the totalResults
+func (p *listPager) add(res resource) {
+	if p.req.filter != nil && !p.req.filter.match(res) {
+		return
+	}
+
+	p.rsp.TotalResults++
+	if p.rsp.TotalResults >= p.rsp.StartIndex && len(p.rsp.Resources) < p.count {
+		p.rsp.Resources = append(p.rsp.Resources, res.project(p.req.Attributes, p.req.ExcludedAttributes))
+	}
+}
+
+func (p *listPager) response() *listResponse {
+	p.rsp.ItemsPerPage = len(p.rsp.Resources)
+	return p.rsp
+}
+
+// newListResponse wraps all of the items into the list response
+func newListResponse(ii ...interface{}) *listResponse {
+	return &listResponse{
+		Schemas:      []string{urnListResponse},
+		TotalResults: len(ii),
+		StartIndex:   1,
+		ItemsPerPage: len(ii),
+		Resources:    ii,
+	}
+}
@@ -1,6 +1,7 @@
 package scim
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"strings"
@@ -14,7 +15,7 @@ func TestOperationsRequestDecodeJSON(t *testing.T) {
 		payload operationsRequest
 
 		s = strings.NewReader(fmt.Sprintf(
-			`{"Operations":[{"op":"add","path":"members[value eq \"%s\"]"}],"schemas":["urn:ietf:params:scim:schemas:core:2.0:PatchOp"]}`,
+			`{"Operations":[{"op":"Add","path":"members[value eq \"%s\"]"}],"schemas":["urn:ietf:params:scim:schemas:core:2.0:PatchOp"]}`,
 			user1Id,
 		))
 
@@ -24,9 +25,6 @@ func TestOperationsRequestDecodeJSON(t *testing.T) {
 				{
 					Operation: "add",
 					Path:      fmt.Sprintf("members[value eq \"%s\"]", user1Id),
-					Value: map[string]string{
-						"members": user1Id,
-					},
 				},
 			},
 		}
@@ -35,4 +33,95 @@ func TestOperationsRequestDecodeJSON(t *testing.T) {
 	err := payload.decodeJSON(s)
 	req.NoError(err)
 	req.Equal(expectedPayload, payload)
+
+	req.Error(payload.decodeJSON(strings.NewReader(`{"Operations":[{"op":"move","path":"userName"}]}`)))
+}
+
+func TestOperationsRequestApply(t *testing.T) {
+	tests := []struct {
+		name string
+		res  string
+		ops  string
+		want string
+		err  bool
+	}{
+		{
+			name: "replace simple attribute",
+			res:  `{"userName":"foo","active":true}`,
+			ops:  `[{"op":"replace","path":"active","value":false}]`,
+			want: `{"userName":"foo","active":false}`,
+		},
+		{
+			name: "replace without path",
+			res:  `{"userName":"foo","name":{"formatted":"Foo"}}`,
+			ops:  `[{"op":"replace","value":{"userName":"bar","name.formatted":"Bar"}}]`,
+			want: `{"userName":"bar","name":{"formatted":"Bar"}}`,
+		},
+		{
+			name: "add member",
+			res:  `{"members":[{"value":"1"}]}`,
+			ops:  `[{"op":"add","path":"members","value":[{"value":"1"},{"value":"2"}]}]`,
+			want: `{"members":[{"value":"1"},{"value":"2"}]}`,
+		},
+		{
+			name: "add member with value filter",
+			res:  `{"members":[{"value":"1"}]}`,
+			ops:  `[{"op":"add","path":"members[value eq \"2\"]"}]`,
+			want: `{"members":[{"value":"1"},{"value":"2"}]}`,
+		},
+		{
+			name: "remove member with value filter",
+			res:  `{"members":[{"value":"1"},{"value":"2"}]}`,
+			ops:  `[{"op":"remove","path":"members[value eq \"1\"]"}]`,
+			want: `{"members":[{"value":"2"}]}`,
+		},
+		{
+			name: "remove listed members",
+			res:  `{"members":[{"value":"1"},{"value":"2"}]}`,
+			ops:  `[{"op":"remove","path":"members","value":[{"value":"2"}]}]`,
+			want: `{"members":[{"value":"1"}]}`,
+		},
+		{
+			name: "replace filtered sub-attribute",
+			res:  `{"emails":[{"type":"work","value":"a@b.c"},{"type":"home","value":"d@e.f"}]}`,
+			ops:  `[{"op":"replace","path":"emails[type eq \"work\"].value","value":"x@y.z"}]`,
+			want: `{"emails":[{"type":"work","value":"x@y.z"},{"type":"home","value":"d@e.f"}]}`,
+		},
+		{
+			name: "remove without path",
+			res:  `{"userName":"foo"}`,
+			ops:  `[{"op":"remove"}]`,
+			err:  true,
+		},
+		{
+			name: "invalid path",
+			res:  `{"userName":"foo"}`,
+			ops:  `[{"op":"replace","path":"emails[type eq","value":"x"}]`,
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				req     = require.New(t)
+				res     = resource{}
+				want    = resource{}
+				payload = &operationsRequest{}
+			)
+
+			req.NoError(payload.decodeJSON(strings.NewReader(`{"Operations":` + tt.ops + `}`)))
+			req.NoError(json.Unmarshal([]byte(tt.res), &res))
+
+			err := payload.apply(res)
+			if tt.err {
+				req.Error(err)
+				return
+			}
+
+			req.NoError(err)
+			req.NoError(json.Unmarshal([]byte(tt.want), &want))
+			req.Equal(want, res)
+		})
+	}
 }
@@ -4,26 +4,37 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"regexp"
+	"net/http"
+	"reflect"
+	"strings"
 )
 
 const (
-	urnPatchOp    = "urn:ietf:params:scim:schemas:core:2.0:PatchOp"
-	patchOpAdd    = "add"
-	patchOpRemove = "remove"
+	urnPatchOp     = "urn:ietf:params:scim:schemas:core:2.0:PatchOp"
+	patchOpAdd     = "add"
+	patchOpRemove  = "remove"
+	patchOpReplace = "replace"
 )
 
 type (
-	// very crud operations support
 	operationsRequest struct {
 		Schemas    []string           `json:"schemas"`
 		Operations []operationRequest `json:"Operations"`
 	}
 
 	operationRequest struct {
-		Operation string `json:"op"`
-		Path      string `json:"path"`
-		Value     map[string]string
+		Operation string          `json:"op"`
+		Path      string          `json:"path"`
+		Value     json.RawMessage `json:"value,omitempty"`
+	}
+
+	// patchPath is a parsed path of the patch operation;
+	// attribute with optional value filter and sub-attribute:
+	// emails[type eq "work"].value
+	patchPath struct {
+		attr   string
+		filter filterExpr
+		sub    string
 	}
 )
 
@@ -32,19 +43,304 @@ func (req *operationsRequest) decodeJSON(r io.Reader) error {
 		return fmt.Errorf("could not decode operations payload: %w", err)
 	}
 
-	// Compiles req.Path and updates req.Value
 	for i, op := range req.Operations {
-		r := regexp.MustCompile(`([a-z]\w+)\[value eq \"([\w]{8}-[\w]{4}-[\w]{4}-[\w]{4}-[\w]{12})\"\]`)
-		strings := r.FindStringSubmatch(op.Path)
-		if len(strings) == 3 {
-			key := strings[1]
-			if op.Value == nil {
-				op.Value = make(map[string]string)
+		// some providers use capitalized operation names
+		req.Operations[i].Operation = strings.ToLower(op.Operation)
+
+		switch req.Operations[i].Operation {
+		case patchOpAdd, patchOpReplace, patchOpRemove:
+		default:
+			return fmt.Errorf("unsupported operation: %q", op.Operation)
+		}
+	}
+
+	return nil
+}
+
+// apply applies all operations to the resource
+func (req *operationsRequest) apply(res resource) error {
+	for _, op := range req.Operations {
+		if err := op.apply(res); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply applies the operation to the resource (RFC 7644, section 3.5.2)
+func (op operationRequest) apply(res resource) error {
+	var (
+		value interface{}
+	)
+
+	if len(op.Value) > 0 {
+		if err := json.Unmarshal(op.Value, &value); err != nil {
+			return newErrorfResponse(http.StatusBadRequest, "invalid value: %v", err).withType(scimTypeInvalidValue)
+		}
+	}
+
+	if op.Path == "" {
+		if op.Operation == patchOpRemove {
+			return newErrorfResponse(http.StatusBadRequest, "path is required for remove operation").withType(scimTypeNoTarget)
+		}
+
+		// without the path, value holds attributes that are modified
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return newErrorfResponse(http.StatusBadRequest, "value must be an object when path is not set").withType(scimTypeInvalidValue)
+		}
+
+		for k, v := range obj {
+			p, err := parsePatchPath(k)
+			if err != nil {
+				return newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidPath)
+			}
+
+			if err = patchValue(res, op.Operation, p, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	p, err := parsePatchPath(op.Path)
+	if err != nil {
+		return newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidPath)
+	}
+
+	return patchValue(res, op.Operation, p, value)
+}
+
+// parsePatchPath parses the path of the patch operation
+func parsePatchPath(path string) (p *patchPath, err error) {
+	p = &patchPath{}
+
+	if s := strings.Index(path, "["); s >= 0 {
+		e := strings.LastIndex(path, "]")
+		if e < s {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+
+		if p.filter, err = parseFilter(path[s+1 : e]); err != nil {
+			return nil, fmt.Errorf("invalid path %q: %w", path, err)
+		}
+
+		rest := path[e+1:]
+		if rest != "" && (!strings.HasPrefix(rest, ".") || len(rest) == 1) {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+
+		p.sub = strings.TrimPrefix(rest, ".")
+		path = path[:s]
+	}
+
+	ap, err := parseAttrPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if ap.sub != "" && p.filter != nil {
+		return nil, fmt.Errorf("invalid path %q", path)
+	}
+
+	p.attr = ap.attr
+	if ap.sub != "" {
+		p.sub = ap.sub
+	}
+
+	return p, nil
+}
+
+// patchValue modifies the attribute on the path
+func patchValue(res map[string]interface{}, op string, p *patchPath, value interface{}) error {
+	var (
+		key      = attrKey(res, p.attr)
+		cur, has = res[key]
+	)
+
+	switch {
+	case p.filter != nil:
+		return patchFiltered(res, key, op, p, value)
+
+	case p.sub != "":
+		if aa, ok := cur.([]interface{}); ok {
+			// sub-attribute of all values
+			for _, a := range aa {
+				if m, ok := a.(map[string]interface{}); ok {
+					patchSub(m, op, p.sub, value)
+				}
+			}
+
+			return nil
+		}
+
+		m, _ := cur.(map[string]interface{})
+		if m == nil {
+			if op == patchOpRemove {
+				return nil
+			}
+
+			m = make(map[string]interface{})
+		}
+
+		patchSub(m, op, p.sub, value)
+		res[key] = m
+
+	case op == patchOpRemove:
+		aa, isArray := cur.([]interface{})
+		if value == nil || !isArray {
+			delete(res, key)
+			return nil
+		}
+
+		// remove only values that are listed in the operation
+		out := make([]interface{}, 0, len(aa))
+		for _, a := range aa {
+			if !containsValue(value, a) {
+				out = append(out, a)
+			}
+		}
+
+		res[key] = out
+
+	case op == patchOpAdd && has:
+		switch c := cur.(type) {
+		case []interface{}:
+			vv, ok := value.([]interface{})
+			if !ok {
+				vv = []interface{}{value}
+			}
+
+			// values that already exist are not added again
+			for _, v := range vv {
+				if !containsValue(c, v) {
+					c = append(c, v)
+				}
+			}
+
+			res[key] = c
+
+		case map[string]interface{}:
+			obj, ok := value.(map[string]interface{})
+			if !ok {
+				return newErrorfResponse(http.StatusBadRequest, "value of %q must be an object", p.attr).withType(scimTypeInvalidValue)
+			}
+
+			for k, v := range obj {
+				c[attrKey(c, k)] = v
+			}
+
+		default:
+			res[key] = value
+		}
+
+	default:
+		res[key] = value
+	}
+
+	return nil
+}
+
+// patchFiltered modifies values of multi-valued attribute that match the filter
+//
+// When nothing matches, add and replace operations add a new value
+// constructed from the filter: emails[type eq "work"].value
+func patchFiltered(res map[string]interface{}, key, op string, p *patchPath, value interface{}) error {
+	var (
+		aa, _   = res[key].([]interface{})
+		out     = make([]interface{}, 0, len(aa)+1)
+		matched bool
+		obj     map[string]interface{}
+	)
+
+	if p.sub == "" && value != nil {
+		var ok bool
+		if obj, ok = value.(map[string]interface{}); !ok {
+			return newErrorfResponse(http.StatusBadRequest, "value of %q must be an object", p.attr).withType(scimTypeInvalidValue)
+		}
+	}
+
+	for _, a := range aa {
+		m, ok := a.(map[string]interface{})
+		if !ok || !p.filter.match(m) {
+			out = append(out, a)
+			continue
+		}
+
+		matched = true
+		switch {
+		case op == patchOpRemove && p.sub == "":
+			continue
+		case p.sub != "":
+			patchSub(m, op, p.sub, value)
+		default:
+			for k, v := range obj {
+				m[attrKey(m, k)] = v
 			}
-			op.Value[key] = strings[2]
-			req.Operations[i].Value = op.Value
 		}
+
+		out = append(out, m)
 	}
 
+	if !matched && op != patchOpRemove {
+		m := make(map[string]interface{})
+		for k, v := range filterEquals(p.filter) {
+			m[k] = v
+		}
+
+		if len(m) == 0 {
+			return newErrorfResponse(http.StatusBadRequest, "no values of %q match the filter", p.attr).withType(scimTypeNoTarget)
+		}
+
+		if p.sub != "" {
+			m[p.sub] = value
+		}
+
+		for k, v := range obj {
+			m[attrKey(m, k)] = v
+		}
+
+		out = append(out, m)
+	}
+
+	res[key] = out
 	return nil
 }
+
+func patchSub(m map[string]interface{}, op, sub string, value interface{}) {
+	if op == patchOpRemove {
+		delete(m, attrKey(m, sub))
+	} else {
+		m[attrKey(m, sub)] = value
+	}
+}
+
+// containsValue checks if the value is in the list
+//
+// Complex values are compared by their "value" sub-attribute
+func containsValue(list interface{}, v interface{}) bool {
+	aa, ok := list.([]interface{})
+	if !ok {
+		aa = []interface{}{list}
+	}
+
+	key := func(v interface{}) interface{} {
+		if m, ok := v.(map[string]interface{}); ok {
+			if val, has := lookupAttr(m, "value"); has {
+				return val
+			}
+		}
+
+		return v
+	}
+
+	for _, a := range aa {
+		if reflect.DeepEqual(key(a), key(v)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,238 @@
+package scim
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// resource is a JSON representation of SCIM resource (user, group)
+	//
+	// Filtering, sorting, patching and attribute projection are all done
+	// on this representation.
+	resource map[string]interface{}
+)
+
+var (
+	// attributes that are always returned
+	alwaysReturned = map[string]bool{
+		"id":      true,
+		"schemas": true,
+	}
+)
+
+// newResource converts resource response payload into a resource
+func newResource(payload interface{}) (resource, error) {
+	bb, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource{}
+	return res, json.Unmarshal(bb, &res)
+}
+
+// stamp sets the location and version of the resource
+func (res resource) stamp(location string) resource {
+	meta, _ := res["meta"].(map[string]interface{})
+	if meta == nil {
+		meta = make(map[string]interface{})
+		res["meta"] = meta
+	}
+
+	meta["location"] = location
+	meta["version"] = res.version()
+	return res
+}
+
+// version calculates (weak) version of the resource from its content
+//
+// Meta attributes are not used, timestamps stored in the database
+// can have different precision than the ones that were just set.
+func (res resource) version() string {
+	cp := make(map[string]interface{}, len(res))
+	for k, v := range res {
+		if k != "meta" {
+			cp[k] = v
+		}
+	}
+
+	// maps are encoded with sorted keys so the output is stable
+	bb, _ := json.Marshal(cp)
+	return fmt.Sprintf(`W/"%x"`, sha1.Sum(bb))
+}
+
+func (res resource) location() string {
+	meta, _ := res["meta"].(map[string]interface{})
+	location, _ := meta["location"].(string)
+	return location
+}
+
+// decode converts the (patched) resource into the request payload
+func (res resource) decode(dst interface{}) error {
+	bb, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bb, dst)
+}
+
+// project returns a copy of the resource with only requested attributes
+//
+// Attributes can be sub-attributes (name.formatted); if attributes are
+// set, excluded attributes are ignored
+func (res resource) project(attributes, excluded []string) resource {
+	if len(attributes) == 0 && len(excluded) == 0 {
+		return res
+	}
+
+	var (
+		out = resource{}
+	)
+
+	if len(attributes) > 0 {
+		for k, v := range res {
+			if alwaysReturned[k] {
+				out[k] = v
+			}
+		}
+
+		for _, a := range attributes {
+			p, err := parseAttrPath(a)
+			if err != nil {
+				continue
+			}
+
+			key := attrKey(res, p.attr)
+			v, ok := res[key]
+			switch {
+			case !ok:
+				continue
+
+			case p.sub == "":
+				out[key] = v
+
+			default:
+				out[key] = projectSub(out[key], v, p.sub)
+			}
+		}
+
+		return out
+	}
+
+	for k, v := range res {
+		out[k] = v
+	}
+
+	for _, a := range excluded {
+		p, err := parseAttrPath(a)
+		if err != nil || alwaysReturned[p.attr] {
+			continue
+		}
+
+		key := attrKey(res, p.attr)
+		if p.sub == "" {
+			delete(out, key)
+			continue
+		}
+
+		if m, ok := out[key].(map[string]interface{}); ok {
+			cp := make(map[string]interface{}, len(m))
+			for k, v := range m {
+				if !strings.EqualFold(k, p.sub) {
+					cp[k] = v
+				}
+			}
+
+			out[key] = cp
+		}
+	}
+
+	return out
+}
+
+// projectSub adds the sub-attribute of a complex attribute to the projection
+func projectSub(dst, src interface{}, sub string) interface{} {
+	switch src := src.(type) {
+	case map[string]interface{}:
+		m, _ := dst.(map[string]interface{})
+		if m == nil {
+			m = make(map[string]interface{})
+		}
+
+		k := attrKey(src, sub)
+		if v, ok := src[k]; ok {
+			m[k] = v
+		}
+
+		return m
+
+	case []interface{}:
+		aa, _ := dst.([]interface{})
+		out := make([]interface{}, len(src))
+		for i := range src {
+			var d interface{}
+			if i < len(aa) {
+				d = aa[i]
+			}
+
+			out[i] = projectSub(d, src[i], sub)
+		}
+
+		return out
+	}
+
+	return dst
+}
+
+// attrKey returns the key of the existing attribute (names are case insensitive)
+// or the name itself if attribute does not exist
+func attrKey(res map[string]interface{}, name string) string {
+	if _, ok := res[name]; ok {
+		return name
+	}
+
+	for k := range res {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+
+	return name
+}
+
+// sortResources sorts resources by the value of the attribute
+//
+// Resources without the value are always last
+func sortResources(rr []resource, sortBy string, descending bool) error {
+	p, err := parseAttrPath(sortBy)
+	if err != nil {
+		return err
+	}
+
+	first := func(res resource) interface{} {
+		if vv := p.values(res); len(vv) > 0 {
+			return vv[0]
+		}
+
+		return nil
+	}
+
+	sort.SliceStable(rr, func(i, j int) bool {
+		a, b := first(rr[i]), first(rr[j])
+		switch {
+		case a == nil || b == nil:
+			return a != nil
+		case descending:
+			return compareValues(b, filterOpLt, a, false)
+		default:
+			return compareValues(a, filterOpLt, b, false)
+		}
+	})
+
+	return nil
+}
@@ -14,6 +14,9 @@ type (
 	Config struct {
 		ExternalIdAsPrimary bool
 		ExternalIdValidator *regexp.Regexp
+
+		// base URL of the SCIM endpoints, used for resource locations
+		BaseURL string
 	}
 )
 
@@ -45,15 +48,19 @@ func Routes(r chi.Router, cfg Config) {
 		uh := &usersHandler{
 			externalIdAsPrimary: cfg.ExternalIdAsPrimary,
 			externalIdValidator: cfg.ExternalIdValidator,
+			baseURL:             cfg.BaseURL,
 
 			svc:     service.DefaultUser,
 			passSvc: service.DefaultAuth,
 			sec:     getSecurityContext,
 		}
 
+		r.Get("/", uh.list)
+		r.Post("/.search", uh.list)
 		r.Get("/{id}", uh.get)
 		r.Post("/", uh.create)
 		r.Put("/{id}", uh.replace)
+		r.Patch("/{id}", uh.patch)
 		r.Delete("/{id}", uh.delete)
 	})
 
@@ -61,16 +68,29 @@ func Routes(r chi.Router, cfg Config) {
 		gh := &groupsHandler{
 			externalIdAsPrimary: cfg.ExternalIdAsPrimary,
 			externalIdValidator: cfg.ExternalIdValidator,
+			baseURL:             cfg.BaseURL,
 
 			svc:     service.DefaultRole,
 			userSvc: service.DefaultUser,
 			sec:     getSecurityContext,
 		}
 
+		r.Get("/", gh.list)
+		r.Post("/.search", gh.list)
 		r.Get("/{id}", gh.get)
 		r.Post("/", gh.create)
 		r.Put("/{id}", gh.replace)
 		r.Patch("/{id}", gh.patch)
 		r.Delete("/{id}", gh.delete)
 	})
+
+	dh := &discoveryHandler{baseURL: cfg.BaseURL}
+	r.Get("/ServiceProviderConfig", dh.serviceProviderConfig)
+	r.Get("/ResourceTypes", dh.listResourceTypes)
+	r.Get("/ResourceTypes/{id}", dh.getResourceType)
+	r.Get("/Schemas", dh.listSchemas)
+	r.Get("/Schemas/{id}", dh.getSchema)
+
+	bh := &bulkHandler{router: r}
+	r.Post("/Bulk", bh.bulk)
 }
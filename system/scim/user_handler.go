@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 	"github.com/cortezaproject/corteza-server/system/service"
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/go-chi/chi"
@@ -21,6 +23,7 @@ type (
 	usersHandler struct {
 		externalIdAsPrimary bool
 		externalIdValidator *regexp.Regexp
+		baseURL             string
 
 		svc     service.UserService
 		passSvc passwordSetter
@@ -37,7 +40,188 @@ func (h usersHandler) get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	send(w, http.StatusOK, newUserResourceResponse(res))
+	h.send(w, r, http.StatusOK, res)
+}
+
+// list searches for users
+//
+// Equality conditions on userName, emails, nickName, externalId and id
+// are used to narrow down the search. When results can be sorted by the
+// store, users are read in batches and only the requested page is kept.
+func (h usersHandler) list(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx = h.sec(r)
+		f   = types.UserFilter{Suspended: filter.StateInclusive}
+	)
+
+	req, err := parseListRequest(r)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	if req.filter != nil && !h.narrow(&f, filterEquals(req.filter)) {
+		send(w, http.StatusOK, req.pager().response())
+		return
+	}
+
+	if req.SortBy != "" {
+		col, ok := h.sortColumn(req.SortBy)
+		if !ok {
+			// sorting by attributes that are not stored
+			// in columns needs all users
+			h.listAll(ctx, w, req, f)
+			return
+		}
+
+		f.Sort = filter.SortExprSet{{Column: col, Descending: strings.EqualFold(req.SortOrder, "descending")}}
+	}
+
+	var (
+		p  = req.pager()
+		uu types.UserSet
+	)
+
+	f.Limit = listBatchSize
+	for {
+		if uu, f, err = h.svc.Find(ctx, f); err != nil {
+			sendError(w, newErrorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		for _, u := range uu {
+			res, err := h.resource(u)
+			if err != nil {
+				sendError(w, err)
+				return
+			}
+
+			p.add(res)
+		}
+
+		if f.NextPage == nil {
+			break
+		}
+
+		f.PageCursor = f.NextPage
+		f.NextPage = nil
+	}
+
+	send(w, http.StatusOK, p.response())
+}
+
+// listAll loads all users and sorts them in memory
+func (h usersHandler) listAll(ctx context.Context, w http.ResponseWriter, req *listRequest, f types.UserFilter) {
+	uu, _, err := h.svc.Find(ctx, f)
+	if err != nil {
+		sendError(w, newErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	rr := make([]resource, 0, len(uu))
+	for _, u := range uu {
+		res, err := h.resource(u)
+		if err != nil {
+			sendError(w, err)
+			return
+		}
+
+		rr = append(rr, res)
+	}
+
+	rsp, err := req.page(rr)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	send(w, http.StatusOK, rsp)
+}
+
+// sortColumn returns user column that matches the SCIM attribute
+func (h usersHandler) sortColumn(sortBy string) (string, bool) {
+	p, err := parseAttrPath(sortBy)
+	if err != nil {
+		return "", false
+	}
+
+	switch strings.ToLower(p.String()) {
+	case "id":
+		// with externalIdAsPrimary, id holds external ID (label)
+		return "id", !h.externalIdAsPrimary
+	case "username":
+		return "username", true
+	case "emails", "emails.value":
+		return "email", true
+	case "nickname":
+		return "handle", true
+	case "displayname", "name.formatted":
+		return "name", true
+	case "meta.created":
+		return "created_at", true
+	case "meta.lastmodified":
+		return "updated_at", true
+	}
+
+	return "", false
+}
+
+// narrow sets user filter from the equality conditions
+//
+// Returns false when no user can match
+func (h usersHandler) narrow(f *types.UserFilter, eq map[string]string) bool {
+	for attr, v := range eq {
+		switch attr {
+		case "id":
+			if h.externalIdAsPrimary {
+				// id is external ID, resolved the same way as on single user lookup
+				if !h.narrowByExternalId(f, v) {
+					return false
+				}
+
+				continue
+			}
+
+			ID, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return false
+			}
+
+			f.UserID = append(f.UserID, ID)
+
+		case "username":
+			f.Username = v
+
+		case "emails", "emails.value":
+			f.Email = v
+
+		case "nickname":
+			f.Handle = v
+
+		case "externalid":
+			if !h.narrowByExternalId(f, v) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// narrowByExternalId sets external ID label on user filter
+//
+// Returns false when no user can match
+func (h usersHandler) narrowByExternalId(f *types.UserFilter, v string) bool {
+	if h.externalIdValidator != nil && !h.externalIdValidator.MatchString(v) {
+		return false
+	}
+
+	if ID, has := f.Labels[userLabel_SCIM_externalId]; has {
+		return ID == v
+	}
+
+	f.Labels = map[string]string{userLabel_SCIM_externalId: v}
+	return true
 }
 
 func (h usersHandler) create(w http.ResponseWriter, r *http.Request) {
@@ -85,7 +269,7 @@ func (h usersHandler) create(w http.ResponseWriter, r *http.Request) {
 		status = http.StatusCreated
 	}
 
-	send(w, status, newUserResourceResponse(res))
+	h.send(w, r, status, res)
 }
 
 func (h usersHandler) replace(w http.ResponseWriter, r *http.Request) {
@@ -97,6 +281,10 @@ func (h usersHandler) replace(w http.ResponseWriter, r *http.Request) {
 		payload  = &userResourceRequest{}
 	)
 
+	if existing == nil || !h.checkVersion(w, r, existing) {
+		return
+	}
+
 	if err := payload.decodeJSON(r.Body); err != nil {
 		sendError(w, newErrorResponse(http.StatusBadRequest, err))
 		return
@@ -108,12 +296,65 @@ func (h usersHandler) replace(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	status := http.StatusOK
-	if res.UpdatedAt == nil {
-		status = http.StatusCreated
+	h.send(w, r, http.StatusOK, res)
+}
+
+// patches user
+//
+// Operations are applied to the user resource that is then saved
+// the same way as with replace
+func (h usersHandler) patch(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var (
+		ctx      = h.sec(r)
+		existing = h.lookup(ctx, chi.URLParam(r, "id"), w)
+		payload  = &operationsRequest{}
+		req      = &userResourceRequest{}
+	)
+
+	if existing == nil || !h.checkVersion(w, r, existing) {
+		return
+	}
+
+	if err := payload.decodeJSON(r.Body); err != nil {
+		sendError(w, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidSyntax))
+		return
+	}
+
+	res, err := h.resource(existing)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	displayName := res["displayName"]
+	if err = payload.apply(res); err != nil {
+		sendError(w, err)
+		return
+	}
+
+	if v, ok := res["displayName"]; ok && v != displayName {
+		// displayName and name.formatted are both stored as user's name
+		res["name"] = map[string]interface{}{"formatted": v}
+	}
+
+	if v, ok := res["active"].(string); ok {
+		// some providers send booleans as strings
+		res["active"], _ = strconv.ParseBool(v)
+	}
+
+	if err = res.decode(req); err != nil {
+		sendError(w, newErrorResponse(http.StatusBadRequest, err).withType(scimTypeInvalidValue))
+		return
+	}
+
+	if existing, err = h.save(ctx, req, existing); err != nil {
+		sendError(w, err)
+		return
 	}
 
-	send(w, status, newUserResourceResponse(res))
+	h.send(w, r, http.StatusOK, existing)
 }
 
 func (h usersHandler) save(ctx context.Context, req *userResourceRequest, existing *types.User) (res *types.User, err error) {
@@ -147,6 +388,20 @@ func (h usersHandler) save(ctx context.Context, req *userResourceRequest, existi
 		}
 	}
 
+	if req.Active != nil && *req.Active != (res.SuspendedAt == nil) {
+		if *req.Active {
+			err = svc.Unsuspend(ctx, res.ID)
+		} else {
+			err = svc.Suspend(ctx, res.ID)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		return svc.FindByID(ctx, res.ID)
+	}
+
 	return res, nil
 }
 
@@ -157,7 +412,7 @@ func (h usersHandler) delete(w http.ResponseWriter, r *http.Request) {
 		res = h.lookup(ctx, chi.URLParam(r, "id"), w)
 	)
 
-	if res == nil {
+	if res == nil || !h.checkVersion(w, r, res) {
 		return
 	}
 
@@ -168,7 +423,7 @@ func (h usersHandler) delete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// loads role from request path params
+// loads user from request path params
 //
 // handles errors by writing them to response
 func (h usersHandler) lookup(ctx context.Context, id string, w http.ResponseWriter) *types.User {
@@ -189,20 +444,69 @@ func (h usersHandler) lookup(ctx context.Context, id string, w http.ResponseWrit
 
 		return res
 	} else {
-		groupId, err := strconv.ParseUint(id, 10, 64)
-		if err != nil || groupId == 0 {
+		userId, err := strconv.ParseUint(id, 10, 64)
+		if err != nil || userId == 0 {
 			sendError(w, newErrorResponse(http.StatusBadRequest, err))
 			return nil
 		}
 
-		role, err := svc.FindByID(ctx, groupId)
+		user, err := svc.FindByID(ctx, userId)
 		if err != nil {
-			sendError(w, newErrorResponse(http.StatusBadRequest, err))
+			sendError(w, newErrorResponse(errorStatus(err, http.StatusBadRequest), err))
 			return nil
 		}
 
-		return role
+		return user
+	}
+}
+
+// resource converts user into SCIM resource
+func (h usersHandler) resource(u *types.User) (resource, error) {
+	res, err := newResource(newUserResourceResponse(u))
+	if err != nil {
+		return nil, err
+	}
+
+	// id is the same as the one used in the location (and in group members)
+	// so that filtering by id works with external IDs as well
+	ID := userResourceID(u, h.externalIdAsPrimary)
+	res["id"] = ID
+
+	return res.stamp(h.baseURL + "/Users/" + ID), nil
+}
+
+func (h usersHandler) send(w http.ResponseWriter, r *http.Request, status int, u *types.User) {
+	res, err := h.resource(u)
+	if err != nil {
+		sendError(w, err)
+		return
+	}
+
+	sendResource(w, r, status, res)
+}
+
+// checkVersion checks the If-Match header; handles errors by writing them to response
+func (h usersHandler) checkVersion(w http.ResponseWriter, r *http.Request, u *types.User) bool {
+	res, err := h.resource(u)
+	if err == nil {
+		err = checkVersion(r, res)
+	}
+
+	if err != nil {
+		sendError(w, err)
+		return false
+	}
+
+	return true
+}
+
+// userResourceID returns ID that is used to reference the user in the SCIM API endpoints
+func userResourceID(u *types.User, externalIdAsPrimary bool) string {
+	if ID := u.Labels[userLabel_SCIM_externalId]; externalIdAsPrimary && ID != "" {
+		return ID
 	}
+
+	return strconv.FormatUint(u.ID, 10)
 }
 
 func (h usersHandler) lookupByExternalId(ctx context.Context, id string) (r *types.User, err error) {
@@ -3,10 +3,12 @@ package scim
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/cortezaproject/corteza-server/pkg/handle"
-	"github.com/cortezaproject/corteza-server/system/types"
 	"io"
 	"strconv"
+	"strings"
+
+	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/system/types"
 )
 
 const (
@@ -17,13 +19,16 @@ const (
 type (
 	emailResponse struct {
 		Value   string `json:"value"`
+		Type    string `json:"type,omitempty"`
 		Primary bool   `json:"primary,omitempty"`
 	}
 
 	emailsResponse []*emailResponse
 
 	userNameResponse struct {
-		Formatted string `json:"formatted"`
+		Formatted  string `json:"formatted"`
+		GivenName  string `json:"givenName,omitempty"`
+		FamilyName string `json:"familyName,omitempty"`
 	}
 
 	userGroupMembershipRequest struct {
@@ -31,25 +36,29 @@ type (
 	}
 
 	userResourceResponse struct {
-		Schemas    []string          `json:"schemas"`
-		Meta       *metaResponse     `json:"meta,omitempty"`
-		ID         string            `json:"id,omitempty"`
-		ExternalId string            `json:"externalId,omitempty"`
-		UserName   string            `json:"userName,omitempty"`
-		NickName   string            `json:"nickName,omitempty"`
-		Name       *userNameResponse `json:"displayName"`
-		Emails     emailsResponse    `json:"emails,omitempty"`
+		Schemas     []string          `json:"schemas"`
+		Meta        *metaResponse     `json:"meta,omitempty"`
+		ID          string            `json:"id,omitempty"`
+		ExternalId  string            `json:"externalId,omitempty"`
+		UserName    string            `json:"userName,omitempty"`
+		NickName    string            `json:"nickName,omitempty"`
+		Name        *userNameResponse `json:"name,omitempty"`
+		DisplayName string            `json:"displayName,omitempty"`
+		Emails      emailsResponse    `json:"emails,omitempty"`
+		Active      bool              `json:"active"`
 	}
 
 	userResourceRequest struct {
-		Schemas    []string          `json:"schemas"`
-		Meta       *metaResponse     `json:"meta,omitempty"`
-		ExternalId *string           `json:"externalId,omitempty"`
-		UserName   *string           `json:"userName,omitempty"`
-		NickName   *string           `json:"nickName,omitempty"`
-		Password   *string           `json:"password,omitempty"`
-		Name       *userNameResponse `json:"name"`
-		Emails     emailsResponse    `json:"emails,omitempty"`
+		Schemas     []string          `json:"schemas"`
+		Meta        *metaResponse     `json:"meta,omitempty"`
+		ExternalId  *string           `json:"externalId,omitempty"`
+		UserName    *string           `json:"userName,omitempty"`
+		NickName    *string           `json:"nickName,omitempty"`
+		Password    *string           `json:"password,omitempty"`
+		Name        *userNameResponse `json:"name"`
+		DisplayName *string           `json:"displayName,omitempty"`
+		Emails      emailsResponse    `json:"emails,omitempty"`
+		Active      *bool             `json:"active,omitempty"`
 
 		Groups []*userGroupMembershipRequest `json:"groups,omitempty"`
 	}
@@ -63,11 +72,16 @@ func newUserResourceResponse(u *types.User) *userResourceResponse {
 		ExternalId: u.Labels[userLabel_SCIM_externalId],
 		UserName:   u.Username,
 		NickName:   u.Handle,
-		Emails:     emailsResponse{{u.Email, true}},
+		Active:     u.SuspendedAt == nil,
+	}
+
+	if u.Email != "" {
+		rsp.Emails = emailsResponse{{Value: u.Email, Type: "work", Primary: true}}
 	}
 
 	if u.Name != "" {
 		rsp.Name = &userNameResponse{Formatted: u.Name}
+		rsp.DisplayName = u.Name
 	}
 
 	return rsp
@@ -105,6 +119,13 @@ func (req *userResourceRequest) applyTo(u *types.User) {
 
 	if req.Name != nil {
 		u.Name = req.Name.Formatted
+		if u.Name == "" {
+			u.Name = strings.TrimSpace(req.Name.GivenName + " " + req.Name.FamilyName)
+		}
+	}
+
+	if req.DisplayName != nil && (req.Name == nil || u.Name == "") {
+		u.Name = *req.DisplayName
 	}
 
 	if req.UserName != nil {
@@ -15,6 +15,7 @@ package service
 // - system.role.yaml
 // - system.template.yaml
 // - system.user.yaml
+// - system.webhook.yaml
 // - system.yaml
 
 import (
@@ -240,6 +241,21 @@ func (svc accessControl) List() (out []map[string]string) {
 			"any":  types.UserRbacResource(0),
 			"op":   "impersonate",
 		},
+		{
+			"type": types.WebhookResourceType,
+			"any":  types.WebhookRbacResource(0),
+			"op":   "read",
+		},
+		{
+			"type": types.WebhookResourceType,
+			"any":  types.WebhookRbacResource(0),
+			"op":   "update",
+		},
+		{
+			"type": types.WebhookResourceType,
+			"any":  types.WebhookRbacResource(0),
+			"op":   "delete",
+		},
 		{
 			"type": types.ComponentResourceType,
 			"any":  types.ComponentRbacResource(),
@@ -260,6 +276,11 @@ func (svc accessControl) List() (out []map[string]string) {
 			"any":  types.ComponentRbacResource(),
 			"op":   "settings.manage",
 		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "limits.read",
+		},
 		{
 			"type": types.ComponentResourceType,
 			"any":  types.ComponentRbacResource(),
@@ -355,6 +376,21 @@ func (svc accessControl) List() (out []map[string]string) {
 			"any":  types.ComponentRbacResource(),
 			"op":   "apigw-routes.search",
 		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "apigw-secrets.manage",
+		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "webhook.create",
+		},
+		{
+			"type": types.ComponentResourceType,
+			"any":  types.ComponentRbacResource(),
+			"op":   "webhooks.search",
+		},
 		{
 			"type": types.ComponentResourceType,
 			"any":  types.ComponentRbacResource(),
@@ -678,6 +714,27 @@ func (svc accessControl) CanImpersonateUser(ctx context.Context, r *types.User)
 	return svc.can(ctx, "impersonate", r)
 }
 
+// CanReadWebhook checks if current user can read webhook
+//
+// This function is auto-generated
+func (svc accessControl) CanReadWebhook(ctx context.Context, r *types.Webhook) bool {
+	return svc.can(ctx, "read", r)
+}
+
+// CanUpdateWebhook checks if current user can update webhook
+//
+// This function is auto-generated
+func (svc accessControl) CanUpdateWebhook(ctx context.Context, r *types.Webhook) bool {
+	return svc.can(ctx, "update", r)
+}
+
+// CanDeleteWebhook checks if current user can delete webhook
+//
+// This function is auto-generated
+func (svc accessControl) CanDeleteWebhook(ctx context.Context, r *types.Webhook) bool {
+	return svc.can(ctx, "delete", r)
+}
+
 // CanGrant checks if current user can manage system permissions
 //
 // This function is auto-generated
@@ -706,6 +763,13 @@ func (svc accessControl) CanManageSettings(ctx context.Context) bool {
 	return svc.can(ctx, "settings.manage", &types.Component{})
 }
 
+// CanReadLimits checks if current user can read resource limits and their usage
+//
+// This function is auto-generated
+func (svc accessControl) CanReadLimits(ctx context.Context) bool {
+	return svc.can(ctx, "limits.read", &types.Component{})
+}
+
 // CanCreateAuthClient checks if current user can create auth clients
 //
 // This function is auto-generated
@@ -839,6 +903,27 @@ func (svc accessControl) CanSearchApigwRoutes(ctx context.Context) bool {
 	return svc.can(ctx, "apigw-routes.search", &types.Component{})
 }
 
+// CanManageApigwSecrets checks if current user can list, create, update or delete api gateway secrets
+//
+// This function is auto-generated
+func (svc accessControl) CanManageApigwSecrets(ctx context.Context) bool {
+	return svc.can(ctx, "apigw-secrets.manage", &types.Component{})
+}
+
+// CanCreateWebhook checks if current user can create webhooks
+//
+// This function is auto-generated
+func (svc accessControl) CanCreateWebhook(ctx context.Context) bool {
+	return svc.can(ctx, "webhook.create", &types.Component{})
+}
+
+// CanSearchWebhooks checks if current user can list, search or filter webhooks
+//
+// This function is auto-generated
+func (svc accessControl) CanSearchWebhooks(ctx context.Context) bool {
+	return svc.can(ctx, "webhooks.search", &types.Component{})
+}
+
 // CanManageResourceTranslations checks if current user can list, search, create, or update resource translations
 //
 // This function is auto-generated
@@ -867,6 +952,8 @@ func rbacResourceValidator(r string, oo ...string) error {
 		return rbacTemplateResourceValidator(r, oo...)
 	case types.UserResourceType:
 		return rbacUserResourceValidator(r, oo...)
+	case types.WebhookResourceType:
+		return rbacWebhookResourceValidator(r, oo...)
 	case types.ComponentResourceType:
 		return rbacComponentResourceValidator(r, oo...)
 	}
@@ -938,12 +1025,19 @@ func rbacResourceOperations(r string) map[string]bool {
 			"name.unmask":  true,
 			"impersonate":  true,
 		}
+	case types.WebhookResourceType:
+		return map[string]bool{
+			"read":   true,
+			"update": true,
+			"delete": true,
+		}
 	case types.ComponentResourceType:
 		return map[string]bool{
 			"grant":                        true,
 			"action-log.read":              true,
 			"settings.read":                true,
 			"settings.manage":              true,
+			"limits.read":                  true,
 			"auth-client.create":           true,
 			"auth-clients.search":          true,
 			"role.create":                  true,
@@ -963,6 +1057,9 @@ func rbacResourceOperations(r string) map[string]bool {
 			"queues.search":                true,
 			"apigw-route.create":           true,
 			"apigw-routes.search":          true,
+			"apigw-secrets.manage":         true,
+			"webhook.create":               true,
+			"webhooks.search":              true,
 			"resource-translations.manage": true,
 		}
 	}
@@ -1322,6 +1419,50 @@ func rbacUserResourceValidator(r string, oo ...string) error {
 	return nil
 }
 
+// rbacWebhookResourceValidator checks validity of rbac resource and operations
+//
+// Can be called without operations to check for validity of resource string only
+//
+// This function is auto-generated
+func rbacWebhookResourceValidator(r string, oo ...string) error {
+	defOps := rbacResourceOperations(r)
+	for _, o := range oo {
+		if !defOps[o] {
+			return fmt.Errorf("invalid operation '%s' for system Webhook resource", o)
+		}
+	}
+
+	if !strings.HasPrefix(r, types.WebhookResourceType) {
+		// expecting resource to always include path
+		return fmt.Errorf("invalid resource type")
+	}
+
+	const sep = "/"
+	var (
+		pp  = strings.Split(strings.Trim(r[len(types.WebhookResourceType):], sep), sep)
+		prc = []string{
+			"ID",
+		}
+	)
+
+	if len(pp) != len(prc) {
+		return fmt.Errorf("invalid resource path structure")
+	}
+
+	for i := 0; i < len(pp); i++ {
+		if pp[i] != "*" {
+			if i > 0 && pp[i-1] == "*" {
+				return fmt.Errorf("invalid resource path wildcard level (%d) for Webhook", i)
+			}
+
+			if _, err := cast.ToUint64E(pp[i]); err != nil {
+				return fmt.Errorf("invalid reference for %s: '%s'", prc[i], pp[i])
+			}
+		}
+	}
+	return nil
+}
+
 // rbacComponentResourceValidator checks validity of rbac resource and operations
 //
 // Can be called without operations to check for validity of resource string only
@@ -6,6 +6,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
 	"github.com/cortezaproject/corteza-server/pkg/apigw"
 	a "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
 
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -16,6 +17,11 @@ type (
 		actionlog actionlog.Recorder
 		store     store.Storer
 		ac        routeAccessController
+		opt       ApigwRouteOptions
+	}
+
+	ApigwRouteOptions struct {
+		LimitRoutes int
 	}
 
 	routeAccessController interface {
@@ -29,11 +35,12 @@ type (
 	}
 )
 
-func Route() *apigwRoute {
+func Route(opt ApigwRouteOptions) *apigwRoute {
 	return &apigwRoute{
 		ac:        DefaultAccessControl,
 		actionlog: DefaultActionlog,
 		store:     DefaultStore,
+		opt:       opt,
 	}
 }
 
@@ -73,6 +80,10 @@ func (svc *apigwRoute) Create(ctx context.Context, new *types.ApigwRoute) (q *ty
 			return ApigwRouteErrNotAllowedToCreate(qProps)
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return
+		}
+
 		new.ID = nextID()
 		new.CreatedAt = *now()
 		new.CreatedBy = a.GetIdentityFromContext(ctx).Identity()
@@ -205,6 +216,10 @@ func (svc *apigwRoute) UndeleteByID(ctx context.Context, ID uint64) (err error)
 
 		qProps.setRoute(q)
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return
+		}
+
 		q.DeletedAt = nil
 		q.UpdatedBy = a.GetIdentityFromContext(ctx).Identity()
 
@@ -225,6 +240,54 @@ func (svc *apigwRoute) UndeleteByID(ctx context.Context, ID uint64) (err error)
 	return svc.recordAction(ctx, qProps, ApigwRouteActionDelete, err)
 }
 
+// PurgeCache removes all cached responses of the route
+func (svc *apigwRoute) PurgeCache(ctx context.Context, ID uint64) (err error) {
+	var (
+		qProps = &apigwRouteActionProps{}
+		q      *types.ApigwRoute
+	)
+
+	err = func() (err error) {
+		if ID == 0 {
+			return ApigwRouteErrInvalidID()
+		}
+
+		if q, err = store.LookupApigwRouteByID(ctx, svc.store, ID); err != nil {
+			return
+		}
+
+		qProps.setRoute(q)
+
+		if !svc.ac.CanUpdateApigwRoute(ctx, q) {
+			return ApigwRouteErrNotAllowedToPurgeCache(qProps)
+		}
+
+		return apigw.Service().PurgeCache(ctx, q.ID)
+	}()
+
+	return svc.recordAction(ctx, qProps, ApigwRouteActionPurgeCache, err)
+}
+
+func (svc *apigwRoute) checkLimits(ctx context.Context) error {
+	if svc.opt.LimitRoutes == 0 {
+		return nil
+	}
+
+	if c, err := countValidApigwRoutes(ctx, svc.store); err != nil {
+		return err
+	} else if c >= uint(svc.opt.LimitRoutes) {
+		return ApigwRouteErrMaxRouteLimitReached()
+	}
+
+	return nil
+}
+
+// countValidApigwRoutes counts routes that are not deleted;
+// disabled routes are included
+func countValidApigwRoutes(ctx context.Context, s store.ApigwRoutes) (c uint, err error) {
+	return store.CountApigwRoutes(ctx, s, types.ApigwRouteFilter{Disabled: filter.StateInclusive})
+}
+
 func (svc *apigwRoute) Search(ctx context.Context, filter types.ApigwRouteFilter) (r types.ApigwRouteSet, f types.ApigwRouteFilter, err error) {
 	var (
 		aProps = &apigwRouteActionProps{search: &filter}
@@ -353,6 +353,26 @@ func ApigwRouteActionUndelete(props ...*apigwRouteActionProps) *apigwRouteAction
 	return a
 }
 
+// ApigwRouteActionPurgeCache returns "system:apigw-route.purgeCache" action
+//
+// This function is auto-generated.
+//
+func ApigwRouteActionPurgeCache(props ...*apigwRouteActionProps) *apigwRouteAction {
+	a := &apigwRouteAction{
+		timestamp: time.Now(),
+		resource:  "system:apigw-route",
+		action:    "purgeCache",
+		log:       "purged cached responses of {{route}}",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -779,6 +799,42 @@ func ApigwRouteErrNotAllowedToUndelete(mm ...*apigwRouteActionProps) *errors.Err
 	return e
 }
 
+// ApigwRouteErrNotAllowedToPurgeCache returns "system:apigw-route.notAllowedToPurgeCache" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwRouteErrNotAllowedToPurgeCache(mm ...*apigwRouteActionProps) *errors.Error {
+	var p = &apigwRouteActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to purge cached responses of this route", nil),
+
+		errors.Meta("type", "notAllowedToPurgeCache"),
+		errors.Meta("resource", "system:apigw-route"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(apigwRouteLogMetaKey{}, "failed to purge cached responses of {{route.endpoint}}; insufficient permissions"),
+		errors.Meta(apigwRoutePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwRoute.errors.notAllowedToPurgeCache"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // ApigwRouteErrNotAllowedToExec returns "system:apigw-route.notAllowedToExec" as *errors.Error
 //
 //
@@ -815,6 +871,40 @@ func ApigwRouteErrNotAllowedToExec(mm ...*apigwRouteActionProps) *errors.Error {
 	return e
 }
 
+// ApigwRouteErrMaxRouteLimitReached returns "system:apigw-route.maxRouteLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func ApigwRouteErrMaxRouteLimitReached(mm ...*apigwRouteActionProps) *errors.Error {
+	var p = &apigwRouteActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your gateway route limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxRouteLimitReached"),
+		errors.Meta("resource", "system:apigw-route"),
+
+		errors.Meta(apigwRoutePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "apigwRoute.errors.maxRouteLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/secure"
+	a "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+type (
+	apigwSecret struct {
+		actionlog actionlog.Recorder
+		store     store.Storer
+		ac        apigwSecretAccessController
+		keys      *secure.Keyring
+	}
+
+	apigwSecretAccessController interface {
+		CanManageApigwSecrets(ctx context.Context) bool
+	}
+)
+
+func ApigwSecret(kr *secure.Keyring) *apigwSecret {
+	return &apigwSecret{
+		ac:        DefaultAccessControl,
+		actionlog: DefaultActionlog,
+		store:     DefaultStore,
+		keys:      kr,
+	}
+}
+
+func (svc *apigwSecret) FindByID(ctx context.Context, secretID uint64) (s *types.ApigwSecret, err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+	)
+
+	err = func() error {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if secretID == 0 {
+			return ApigwSecretErrInvalidID()
+		}
+
+		if s, err = store.LookupApigwSecretByID(ctx, svc.store, secretID); err != nil {
+			return ApigwSecretErrNotFound().Wrap(err)
+		}
+
+		sProps.setSecret(s)
+
+		return nil
+	}()
+
+	return s, svc.recordAction(ctx, sProps, ApigwSecretActionLookup, err)
+}
+
+func (svc *apigwSecret) Search(ctx context.Context, filter types.ApigwSecretFilter) (set types.ApigwSecretSet, f types.ApigwSecretFilter, err error) {
+	var (
+		sProps = &apigwSecretActionProps{filter: &filter}
+	)
+
+	err = func() error {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if set, f, err = store.SearchApigwSecrets(ctx, svc.store, filter); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return set, f, svc.recordAction(ctx, sProps, ApigwSecretActionSearch, err)
+}
+
+// Create encrypts and stores a new secret
+func (svc *apigwSecret) Create(ctx context.Context, new *types.ApigwSecret) (s *types.ApigwSecret, err error) {
+	var (
+		sProps = &apigwSecretActionProps{new: new}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if err = svc.validate(ctx, new); err != nil {
+			return
+		}
+
+		if new.Value == "" {
+			return ApigwSecretErrValueEmpty()
+		}
+
+		if err = svc.keys.SealSecret(new); err != nil {
+			return ApigwSecretErrEncryptionFailed().Wrap(err)
+		}
+
+		new.ID = nextID()
+		new.CreatedAt = *now()
+		new.CreatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.CreateApigwSecret(ctx, svc.store, new); err != nil {
+			return
+		}
+
+		s = new
+		return svc.reloadRoutes(ctx)
+	}()
+
+	return s, svc.recordAction(ctx, sProps, ApigwSecretActionCreate, err)
+}
+
+// Update changes secret's handle and meta
+//
+// When value is set, secret is re-encrypted with a new data key
+func (svc *apigwSecret) Update(ctx context.Context, upd *types.ApigwSecret) (s *types.ApigwSecret, err error) {
+	var (
+		sProps = &apigwSecretActionProps{update: upd}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if upd.ID == 0 {
+			return ApigwSecretErrInvalidID()
+		}
+
+		if s, err = store.LookupApigwSecretByID(ctx, svc.store, upd.ID); err != nil {
+			return ApigwSecretErrNotFound().Wrap(err)
+		}
+
+		sProps.setSecret(s)
+
+		if err = svc.validate(ctx, upd); err != nil {
+			return
+		}
+
+		s.Handle = upd.Handle
+		s.Meta = upd.Meta
+		s.UpdatedAt = now()
+		s.UpdatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if upd.Value != "" {
+			s.Value = upd.Value
+			if err = svc.keys.SealSecret(s); err != nil {
+				return ApigwSecretErrEncryptionFailed().Wrap(err)
+			}
+
+			s.RotatedAt = now()
+		}
+
+		if err = store.UpdateApigwSecret(ctx, svc.store, s); err != nil {
+			return
+		}
+
+		return svc.reloadRoutes(ctx)
+	}()
+
+	return s, svc.recordAction(ctx, sProps, ApigwSecretActionUpdate, err)
+}
+
+func (svc *apigwSecret) DeleteByID(ctx context.Context, ID uint64) (err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+		s      *types.ApigwSecret
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if s, err = store.LookupApigwSecretByID(ctx, svc.store, ID); err != nil {
+			return ApigwSecretErrNotFound().Wrap(err)
+		}
+
+		sProps.setSecret(s)
+
+		s.DeletedAt = now()
+		s.DeletedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.UpdateApigwSecret(ctx, svc.store, s); err != nil {
+			return
+		}
+
+		return svc.reloadRoutes(ctx)
+	}()
+
+	return svc.recordAction(ctx, sProps, ApigwSecretActionDelete, err)
+}
+
+func (svc *apigwSecret) UndeleteByID(ctx context.Context, ID uint64) (err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+		s      *types.ApigwSecret
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if s, err = store.LookupApigwSecretByID(ctx, svc.store, ID); err != nil {
+			return ApigwSecretErrNotFound().Wrap(err)
+		}
+
+		sProps.setSecret(s)
+
+		if err = svc.uniqueCheck(ctx, s); err != nil {
+			return
+		}
+
+		s.DeletedAt = nil
+		s.UpdatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.UpdateApigwSecret(ctx, svc.store, s); err != nil {
+			return
+		}
+
+		return svc.reloadRoutes(ctx)
+	}()
+
+	return svc.recordAction(ctx, sProps, ApigwSecretActionUndelete, err)
+}
+
+// RotateKeys re-encrypts data keys of all secrets that are
+// not encrypted with the current key
+//
+// Secret values and their data keys do not change; after rotation
+// previous keys can be removed from the configuration
+func (svc *apigwSecret) RotateKeys(ctx context.Context) (rotated int, err error) {
+	var (
+		sProps = &apigwSecretActionProps{}
+		set    types.ApigwSecretSet
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanManageApigwSecrets(ctx) {
+			return ApigwSecretErrNotAllowedToManage()
+		}
+
+		if svc.keys.CurrentKeyID() == "" {
+			return ApigwSecretErrEncryptionFailed().Wrap(secure.ErrKeyNotConfigured)
+		}
+
+		set, _, err = store.SearchApigwSecrets(ctx, svc.store, types.ApigwSecretFilter{
+			KeyIDNot: svc.keys.CurrentKeyID(),
+			Deleted:  filter.StateInclusive,
+		})
+
+		if err != nil {
+			return
+		}
+
+		return store.Tx(ctx, svc.store, func(ctx context.Context, s store.Storer) (err error) {
+			for _, sec := range set {
+				if _, err = svc.keys.RewrapSecret(sec); err != nil {
+					return ApigwSecretErrEncryptionFailed().Wrap(err)
+				}
+
+				sec.RotatedAt = now()
+				if err = store.UpdateApigwSecret(ctx, s, sec); err != nil {
+					return
+				}
+
+				rotated++
+			}
+
+			return nil
+		})
+	}()
+
+	sProps.setRotated(rotated)
+	return rotated, svc.recordAction(ctx, sProps, ApigwSecretActionRotateKeys, err)
+}
+
+func (svc *apigwSecret) validate(ctx context.Context, s *types.ApigwSecret) error {
+	if s.Handle == "" || !handle.IsValid(s.Handle) {
+		return ApigwSecretErrInvalidHandle()
+	}
+
+	return svc.uniqueCheck(ctx, s)
+}
+
+func (svc *apigwSecret) uniqueCheck(ctx context.Context, s *types.ApigwSecret) error {
+	if ex, _ := store.LookupApigwSecretByHandle(ctx, svc.store, s.Handle); ex != nil && ex.ID > 0 && ex.ID != s.ID {
+		return ApigwSecretErrHandleNotUnique(&apigwSecretActionProps{secret: s})
+	}
+
+	return nil
+}
+
+// reloadRoutes makes sure API Gateway filters are using current secret values
+func (svc *apigwSecret) reloadRoutes(ctx context.Context) error {
+	if apigw.Service() == nil {
+		return nil
+	}
+
+	return apigw.Service().Reload(ctx)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/secure"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/store/sqlite3"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type (
+	apigwSecretAllowAll struct{}
+)
+
+func (apigwSecretAllowAll) CanManageApigwSecrets(context.Context) bool { return true }
+
+func TestApigwSecret_RotateKeys(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+
+		s   store.Storer
+		err error
+
+		old = &apigwSecret{ac: apigwSecretAllowAll{}, keys: secure.NewKeyring("old", "")}
+		svc = &apigwSecret{ac: apigwSecretAllowAll{}, keys: secure.NewKeyring("new", "old")}
+	)
+
+	if s, err = sqlite3.ConnectInMemory(ctx); err != nil {
+		req.NoError(err)
+	} else if err = store.Upgrade(ctx, zap.NewNop(), s); err != nil {
+		req.NoError(err)
+	}
+
+	old.store, svc.store = s, s
+	req.NoError(store.TruncateApigwSecrets(ctx, s))
+
+	for _, h := range []string{"one", "two"} {
+		_, err = old.Create(ctx, &types.ApigwSecret{Handle: h, Value: "s3cr3t-" + h})
+		req.NoError(err)
+	}
+
+	// created with the current key, does not need to be rotated
+	_, err = svc.Create(ctx, &types.ApigwSecret{Handle: "three", Value: "s3cr3t-three"})
+	req.NoError(err)
+
+	rotated, err := svc.RotateKeys(ctx)
+	req.NoError(err)
+	req.Equal(2, rotated)
+
+	rotated, err = svc.RotateKeys(ctx)
+	req.NoError(err)
+	req.Equal(0, rotated)
+
+	// previous key is not needed anymore
+	ss := secure.NewStorage(s, secure.NewKeyring("new", ""))
+	for _, h := range []string{"one", "two", "three"} {
+		v, err := ss.Secret(ctx, h)
+		req.NoError(err)
+		req.Equal("s3cr3t-"+h, v)
+	}
+}
+
+func TestApigwSecret_Update(t *testing.T) {
+	var (
+		req = require.New(t)
+		ctx = context.Background()
+
+		s   store.Storer
+		err error
+
+		kr  = secure.NewKeyring("key", "")
+		svc = &apigwSecret{ac: apigwSecretAllowAll{}, keys: kr}
+	)
+
+	if s, err = sqlite3.ConnectInMemory(ctx); err != nil {
+		req.NoError(err)
+	} else if err = store.Upgrade(ctx, zap.NewNop(), s); err != nil {
+		req.NoError(err)
+	}
+
+	svc.store = s
+	req.NoError(store.TruncateApigwSecrets(ctx, s))
+
+	sec, err := svc.Create(ctx, &types.ApigwSecret{Handle: "api-key", Value: "v1"})
+	req.NoError(err)
+	req.Empty(sec.Value)
+	req.Nil(sec.RotatedAt)
+
+	ciphertext := sec.Ciphertext
+
+	// update w/o value keeps the secret
+	sec, err = svc.Update(ctx, &types.ApigwSecret{ID: sec.ID, Handle: "api-key", Meta: types.ApigwSecretMeta{Description: "key"}})
+	req.NoError(err)
+	req.Equal(ciphertext, sec.Ciphertext)
+	req.Nil(sec.RotatedAt)
+
+	sec, err = svc.Update(ctx, &types.ApigwSecret{ID: sec.ID, Handle: "api-key", Value: "v2"})
+	req.NoError(err)
+	req.NotEqual(ciphertext, sec.Ciphertext)
+	req.NotNil(sec.RotatedAt)
+
+	v, err := secure.NewStorage(s, kr).Secret(ctx, "api-key")
+	req.NoError(err)
+	req.Equal("v2", v)
+
+	_, err = svc.Create(ctx, &types.ApigwSecret{Handle: "api-key", Value: "v3"})
+	req.Error(err)
+	req.Equal("secret handle not unique", err.Error())
+
+	_, err = svc.Create(ctx, &types.ApigwSecret{Handle: "empty"})
+	req.Error(err)
+	req.Equal("secret value can not be empty", err.Error())
+}
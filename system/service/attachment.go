@@ -121,7 +121,7 @@ func (svc attachment) OpenOriginal(att *types.Attachment) (io.ReadSeeker, error)
 		return nil, nil
 	}
 
-	return svc.files.Open(att.Url)
+	return files.OpenSeeker(svc.files, att.Url)
 }
 
 func (svc attachment) OpenPreview(att *types.Attachment) (io.ReadSeeker, error) {
@@ -129,7 +129,7 @@ func (svc attachment) OpenPreview(att *types.Attachment) (io.ReadSeeker, error)
 		return nil, nil
 	}
 
-	return svc.files.Open(att.PreviewUrl)
+	return files.OpenSeeker(svc.files, att.PreviewUrl)
 }
 
 func (svc attachment) CreateSettingsAttachment(ctx context.Context, name string, size int64, fh io.ReadSeeker, labels map[string]string) (att *types.Attachment, err error) {
@@ -17,6 +17,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/handle"
 	"github.com/cortezaproject/corteza-server/pkg/payload"
 	"github.com/cortezaproject/corteza-server/pkg/rand"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/service/event"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -42,6 +43,9 @@ type (
 
 	AuthOptions struct {
 		LimitUsers int
+
+		// WebAuthn relying party
+		WebAuthn webauthn.RelyingParty
 	}
 
 	authAccessController interface {
@@ -59,6 +63,7 @@ const (
 	credentialsTypeCreatePasswordToken         = "password-create-token"
 	credentialsTypeMfaTotpSecret               = "mfa-totp-secret"
 	credentialsTypeMFAEmailOTP                 = "mfa-email-otp"
+	credentialsTypeMfaWebAuthn                 = "mfa-webauthn"
 
 	credentialsTokenLength = 32
 
@@ -983,8 +988,8 @@ func (svc auth) procLogin(ctx context.Context, s store.Storer, u *types.User, c
 		var eapSec *types.ExternalAuthProviderSecurity
 
 		switch p.Provider {
-		case credentialsTypePassword:
-			// nothing to do with password provider
+		case credentialsTypePassword, credentialsTypeMfaWebAuthn:
+			// nothing to do with password & passkey providers
 
 		case "saml":
 			// we need to fetch SAML security settings from different part of settings
@@ -616,6 +616,66 @@ func AuthActionTotpValidate(props ...*authActionProps) *authAction {
 	return a
 }
 
+// AuthActionWebauthnConfigure returns "system:auth.webauthnConfigure" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebauthnConfigure(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webauthnConfigure",
+		log:       "WebAuthn credentials {{credentials.label}} for {{user}} configured",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebauthnRemove returns "system:auth.webauthnRemove" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebauthnRemove(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webauthnRemove",
+		log:       "WebAuthn credentials {{credentials.label}} for {{user}} removed",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
+// AuthActionWebauthnValidate returns "system:auth.webauthnValidate" action
+//
+// This function is auto-generated.
+//
+func AuthActionWebauthnValidate(props ...*authActionProps) *authAction {
+	a := &authAction{
+		timestamp: time.Now(),
+		resource:  "system:auth",
+		action:    "webauthnValidate",
+		log:       "WebAuthn credentials {{credentials.label}} for {{user}} validated",
+		severity:  actionlog.Notice,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // AuthActionEmailOtpVerify returns "system:auth.emailOtpVerify" action
 //
 // This function is auto-generated.
@@ -1646,6 +1706,212 @@ func AuthErrDisabledMFAWithTOTP(mm ...*authActionProps) *errors.Error {
 	return e
 }
 
+// AuthErrNotAllowedToRemoveWebAuthn returns "system:auth.notAllowedToRemoveWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrNotAllowedToRemoveWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to remove WebAuthn credentials", nil),
+
+		errors.Meta("type", "notAllowedToRemoveWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.notAllowedToRemoveWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrUnconfiguredWebAuthn returns "system:auth.unconfiguredWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrUnconfiguredWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("WebAuthn not configured", nil),
+
+		errors.Meta("type", "unconfiguredWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.unconfiguredWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrEnforcedMFAWithWebAuthn returns "system:auth.enforcedMFAWithWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrEnforcedMFAWithWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("WebAuthn is enforced and last credentials cannot be removed", nil),
+
+		errors.Meta("type", "enforcedMFAWithWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.enforcedMFAWithWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrInvalidWebAuthn returns "system:auth.invalidWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrInvalidWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid security key or passkey", nil),
+
+		errors.Meta("type", "invalidWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		// action log entry; no formatting, it will be applied inside recordAction fn.
+		errors.Meta(authLogMetaKey{}, "failed to verify WebAuthn credentials {{credentials.label}}"),
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.invalidWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrDisabledMFAWithWebAuthn returns "system:auth.disabledMFAWithWebAuthn" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrDisabledMFAWithWebAuthn(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("multi factor authentication with WebAuthn is disabled", nil),
+
+		errors.Meta("type", "disabledMFAWithWebAuthn"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.disabledMFAWithWebAuthn"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
+// AuthErrPasswordlessLoginDisabledByConfig returns "system:auth.passwordlessLoginDisabledByConfig" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func AuthErrPasswordlessLoginDisabledByConfig(mm ...*authActionProps) *errors.Error {
+	var p = &authActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("sign-in with passkey is disabled", nil),
+
+		errors.Meta("type", "passwordlessLoginDisabledByConfig"),
+		errors.Meta("resource", "system:auth"),
+
+		errors.Meta(authPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "auth.errors.passwordlessLoginDisabledByConfig"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // AuthErrDisabledMFAWithEmailOTP returns "system:auth.disabledMFAWithEmailOTP" as *errors.Error
 //
 //
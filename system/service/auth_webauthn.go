@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	internalAuth "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+)
+
+// WebAuthnCredentials returns all valid WebAuthn credentials of the user
+func (svc auth) WebAuthnCredentials(ctx context.Context, userID uint64) (types.CredentialsSet, error) {
+	return svc.getWebAuthnCredentials(ctx, svc.store, userID)
+}
+
+// BeginWebAuthnRegistration generates options for registering new
+// WebAuthn credentials of the current user
+//
+// When passwordless login is enabled, registered credentials are discoverable
+// (passkeys) so that they can be used without entering the email.
+func (svc auth) BeginWebAuthnRegistration(ctx context.Context) (cc *webauthn.CredentialCreation, ws *webauthn.Session, err error) {
+	var (
+		u        *types.User
+		existing types.CredentialsSet
+		exclude  [][]byte
+		i        = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+		return nil, nil, AuthErrDisabledMFAWithWebAuthn()
+	}
+
+	if u, err = store.LookupUserByID(ctx, svc.store, i.Identity()); err != nil {
+		return
+	}
+
+	if existing, err = svc.getWebAuthnCredentials(ctx, svc.store, u.ID); err != nil {
+		return
+	}
+
+	for _, c := range existing {
+		if id, err := webauthn.DecodeID(c.Credentials); err == nil {
+			exclude = append(exclude, id)
+		}
+	}
+
+	return svc.opt.WebAuthn.BeginRegistration(
+		webauthn.User{
+			ID:          webAuthnUserHandle(u.ID),
+			Name:        u.Email,
+			DisplayName: u.Name,
+		},
+		exclude,
+		svc.settings.Auth.MultiFactor.WebAuthn.Passwordless,
+	)
+}
+
+// ConfigureWebAuthn verifies the registration response and stores
+// new WebAuthn credentials in user's credentials
+//
+// It returns the user with security policy changes
+func (svc auth) ConfigureWebAuthn(ctx context.Context, ws *webauthn.Session, label string, response []byte) (u *types.User, err error) {
+	var (
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind, Label: label}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if ws == nil || !webAuthnUserMatch(ws.UserID, u.ID) {
+			return AuthErrInvalidWebAuthn(aam)
+		}
+
+		wc, err := svc.opt.WebAuthn.FinishRegistration(ws, response)
+		if err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		meta, err := json.Marshal(wc)
+		if err != nil {
+			return err
+		}
+
+		if label = strings.TrimSpace(label); label == "" {
+			label = "Passkey"
+		}
+
+		cred := &types.Credentials{
+			ID:          nextID(),
+			CreatedAt:   *now(),
+			OwnerID:     u.ID,
+			Label:       label,
+			Kind:        kind,
+			Credentials: webauthn.EncodeID(wc.ID),
+			Meta:        meta,
+		}
+
+		aam.setCredentials(cred)
+
+		if err = store.CreateCredentials(ctx, s, cred); err != nil {
+			return err
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = true
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionWebauthnConfigure, err)
+}
+
+// RemoveWebAuthn removes WebAuthn credentials of the user
+//
+// When removing credentials of another user, remover should have
+// permissions to update that user. Last credentials can not be removed
+// when WebAuthn is enforced.
+//
+// It returns the user with security policy changes
+func (svc auth) RemoveWebAuthn(ctx context.Context, userID, credentialsID uint64) (u *types.User, err error) {
+	var (
+		cc   types.CredentialsSet
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind, ID: credentialsID}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+		self = i != nil && i.Identity() == userID
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, userID)
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if !self && !svc.ac.CanUpdateUser(ctx, u) {
+			return AuthErrNotAllowedToRemoveWebAuthn()
+		}
+
+		if cc, err = svc.getWebAuthnCredentials(ctx, s, u.ID); err != nil {
+			return err
+		}
+
+		c := cc.FindByID(credentialsID)
+		if c == nil {
+			return AuthErrNotAllowedToRemoveWebAuthn()
+		}
+
+		aam.setCredentials(c)
+
+		if len(cc) == 1 && svc.settings.Auth.MultiFactor.WebAuthn.Enforced {
+			return AuthErrEnforcedMFAWithWebAuthn()
+		}
+
+		c.DeletedAt = now()
+		if err = store.UpdateCredentials(ctx, s, c); err != nil {
+			return err
+		}
+
+		if len(cc) > 1 {
+			return nil
+		}
+
+		u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn = false
+		return store.UpdateUser(ctx, s, u)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionWebauthnRemove, err)
+}
+
+// BeginWebAuthnLogin generates options for verifying one of the
+// current user's WebAuthn credentials
+func (svc auth) BeginWebAuthnLogin(ctx context.Context) (ca *webauthn.CredentialAssertion, ws *webauthn.Session, err error) {
+	var (
+		cc      types.CredentialsSet
+		allowed [][]byte
+		i       = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+		return nil, nil, AuthErrDisabledMFAWithWebAuthn()
+	}
+
+	if cc, err = svc.getWebAuthnCredentials(ctx, svc.store, i.Identity()); err != nil {
+		return
+	}
+
+	for _, c := range cc {
+		if id, err := webauthn.DecodeID(c.Credentials); err == nil {
+			allowed = append(allowed, id)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil, nil, AuthErrUnconfiguredWebAuthn()
+	}
+
+	return svc.opt.WebAuthn.BeginLogin(allowed, webauthn.VerificationPreferred)
+}
+
+// ValidateWebAuthn verifies the assertion with current user's credentials
+func (svc auth) ValidateWebAuthn(ctx context.Context, ws *webauthn.Session, response []byte) (err error) {
+	var (
+		u    *types.User
+		c    *types.Credentials
+		kind = credentialsTypeMfaWebAuthn
+		aam  = &authActionProps{credentials: &types.Credentials{Kind: kind}}
+		i    = internalAuth.GetIdentityFromContext(ctx)
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled {
+			return AuthErrDisabledMFAWithWebAuthn()
+		}
+
+		u, err = store.LookupUserByID(ctx, s, i.Identity())
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		if !u.Meta.SecurityPolicy.MFA.EnforcedWebAuthn {
+			return AuthErrUnconfiguredWebAuthn()
+		}
+
+		if c, err = svc.verifyWebAuthn(ctx, s, ws, response); err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		aam.setCredentials(c)
+
+		if c.OwnerID != u.ID {
+			return AuthErrInvalidWebAuthn(aam)
+		}
+
+		c.LastUsedAt = now()
+		return store.UpdateCredentials(ctx, s, c)
+	})
+
+	return svc.recordAction(ctx, aam, AuthActionWebauthnValidate, err)
+}
+
+// BeginPasswordlessLogin generates options for signing-in with any
+// discoverable credentials (passkey) registered for this site
+func (svc auth) BeginPasswordlessLogin(_ context.Context) (*webauthn.CredentialAssertion, *webauthn.Session, error) {
+	if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled || !svc.settings.Auth.MultiFactor.WebAuthn.Passwordless {
+		return nil, nil, AuthErrPasswordlessLoginDisabledByConfig()
+	}
+
+	return svc.opt.WebAuthn.BeginLogin(nil, webauthn.VerificationRequired)
+}
+
+// PasswordlessLogin verifies the assertion and signs-in the owner of the credentials
+//
+// User verification is required by the authenticator so the passkey
+// satisfies the WebAuthn MFA as well.
+func (svc auth) PasswordlessLogin(ctx context.Context, ws *webauthn.Session, response []byte) (u *types.User, err error) {
+	var (
+		c            *types.Credentials
+		kind         = credentialsTypeMfaWebAuthn
+		authProvider = &types.AuthProvider{Provider: kind}
+		aam          = &authActionProps{
+			provider:    authProvider.Provider,
+			credentials: &types.Credentials{Kind: kind},
+		}
+	)
+
+	err = svc.store.Tx(ctx, func(ctx context.Context, s store.Storer) error {
+		if !svc.settings.Auth.MultiFactor.WebAuthn.Enabled || !svc.settings.Auth.MultiFactor.WebAuthn.Passwordless {
+			return AuthErrPasswordlessLoginDisabledByConfig()
+		}
+
+		if c, err = svc.verifyWebAuthn(ctx, s, ws, response); err != nil {
+			return AuthErrInvalidWebAuthn(aam).Wrap(err)
+		}
+
+		aam.setCredentials(c)
+
+		u, err = store.LookupUserByID(ctx, s, c.OwnerID)
+		if errors.IsNotFound(err) {
+			return AuthErrFailedForUnknownUser(aam)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		aam.setUser(u)
+
+		return svc.procLogin(ctx, s, u, c, authProvider)
+	})
+
+	return u, svc.recordAction(ctx, aam, AuthActionAuthenticate, err)
+}
+
+// verifyWebAuthn finds credentials used in the assertion and verifies them
+//
+// It updates the signature counter of the credentials; caller is responsible
+// for storing the changes
+func (svc auth) verifyWebAuthn(ctx context.Context, s store.Credentials, ws *webauthn.Session, response []byte) (*types.Credentials, error) {
+	var (
+		wc webauthn.Credential
+	)
+
+	a, err := webauthn.ParseAssertion(response)
+	if err != nil {
+		return nil, err
+	}
+
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		Kind:        credentialsTypeMfaWebAuthn,
+		Credentials: webauthn.EncodeID(a.CredentialID),
+		Deleted:     filter.StateExcluded,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cc) != 1 {
+		return nil, AuthErrInvalidWebAuthn()
+	}
+
+	c := cc[0]
+
+	if len(a.UserHandle) > 0 && !webAuthnUserMatch(a.UserHandle, c.OwnerID) {
+		return nil, AuthErrInvalidWebAuthn()
+	}
+
+	if err = json.Unmarshal(c.Meta, &wc); err != nil {
+		return nil, err
+	}
+
+	wc.ID = a.CredentialID
+	if wc.SignCount, err = svc.opt.WebAuthn.FinishLogin(ws, a, &wc); err != nil {
+		return nil, err
+	}
+
+	if c.Meta, err = json.Marshal(wc); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Searches for all valid WebAuthn credentials
+func (auth) getWebAuthnCredentials(ctx context.Context, s store.Credentials, userID uint64) (types.CredentialsSet, error) {
+	cc, _, err := store.SearchCredentials(ctx, s, types.CredentialsFilter{
+		OwnerID: userID,
+		Kind:    credentialsTypeMfaWebAuthn,
+		Deleted: filter.StateExcluded,
+	})
+
+	return cc, err
+}
+
+// user handle is an opaque value, ID of the user is used
+func webAuthnUserHandle(userID uint64) []byte {
+	return []byte(strconv.FormatUint(userID, 10))
+}
+
+func webAuthnUserMatch(handle []byte, userID uint64) bool {
+	return userID > 0 && string(handle) == strconv.FormatUint(userID, 10)
+}
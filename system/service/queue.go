@@ -182,6 +182,9 @@ func (svc *queue) Update(ctx context.Context, upd *types.Queue) (q *types.Queue,
 		upd.UpdatedAt = now()
 		upd.CreatedAt = qq.CreatedAt
 
+		// masked credentials sent back by the client are kept
+		upd.Meta.KeepMasked(qq.Meta)
+
 		if err = store.UpdateQueue(ctx, svc.store, upd); err != nil {
 			return
 		}
@@ -12,6 +12,7 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/expr"
 	"github.com/cortezaproject/corteza-server/pkg/handle"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/label"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
@@ -34,6 +35,8 @@ type (
 
 		store store.Storer
 
+		opt RoleOptions
+
 		// list of all system roles
 		system map[string]bool
 
@@ -41,6 +44,10 @@ type (
 		closed map[string]bool
 	}
 
+	RoleOptions struct {
+		LimitRoles int
+	}
+
 	roleAccessController interface {
 		CanSearchRoles(context.Context) bool
 		CanCreateRole(context.Context) bool
@@ -87,8 +94,10 @@ type (
 	}
 )
 
-func Role() *role {
+func Role(opt RoleOptions) *role {
 	return &role{
+		opt: opt,
+
 		ac:       DefaultAccessControl,
 		eventbus: eventbus.Service(),
 
@@ -320,6 +329,10 @@ func (svc role) Create(ctx context.Context, new *types.Role) (r *types.Role, err
 			return
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return
+		}
+
 		new.ID = nextID()
 		new.CreatedAt = *now()
 
@@ -516,6 +529,12 @@ func (svc role) Undelete(ctx context.Context, roleID uint64) (err error) {
 			return RoleErrNotAllowedToDelete()
 		}
 
+		if r.ArchivedAt == nil {
+			if err = svc.checkLimits(ctx); err != nil {
+				return
+			}
+		}
+
 		r.DeletedAt = nil
 
 		if err = store.UpdateRole(ctx, svc.store, r); err != nil {
@@ -581,6 +600,12 @@ func (svc role) Unarchive(ctx context.Context, roleID uint64) (err error) {
 			return RoleErrNotAllowedToUndelete()
 		}
 
+		if r.DeletedAt == nil {
+			if err = svc.checkLimits(ctx); err != nil {
+				return
+			}
+		}
+
 		r.ArchivedAt = nil
 		if err = store.UpdateRole(ctx, svc.store, r); err != nil {
 			return
@@ -592,6 +617,24 @@ func (svc role) Unarchive(ctx context.Context, roleID uint64) (err error) {
 	return svc.recordAction(ctx, raProps, RoleActionUnarchive, err)
 }
 
+func (svc role) checkLimits(ctx context.Context) error {
+	if svc.opt.LimitRoles == 0 {
+		return nil
+	}
+
+	if c, err := countValidRoles(ctx, svc.store); err != nil {
+		return err
+	} else if c >= uint(svc.opt.LimitRoles) {
+		return RoleErrMaxRoleLimitReached()
+	}
+
+	return nil
+}
+
+func countValidRoles(ctx context.Context, s store.Roles) (c uint, err error) {
+	return store.CountRoles(ctx, s, types.RoleFilter{})
+}
+
 func (svc role) Membership(ctx context.Context, userID uint64) (types.RoleMemberSet, error) {
 	mm, _, err := store.SearchRoleMembers(ctx, svc.store, types.RoleMemberFilter{UserID: userID})
 	return mm, err
@@ -759,6 +802,11 @@ func toLabeledRoles(set []*types.Role) []label.LabeledResource {
 	return ll
 }
 
+const (
+	// RoleInvalidationKind is used to notify other nodes about changed roles
+	RoleInvalidationKind = "system:role"
+)
+
 // Initializes roles to RBAC and default role service
 //
 // Sets all closed & system roles
@@ -848,14 +896,20 @@ func initRoles(ctx context.Context, log *zap.Logger, opt options.RBACOpt, eb eve
 	// Hook to role create, update & delete events and
 	// re-apply all roles to RBAC
 	eb.Register(
-		func(_ context.Context, ev eventbus.Event) error {
+		func(evCtx context.Context, ev eventbus.Event) error {
 			log.Debug("role changed, updating RBAC")
+			invalidation.Notify(evCtx, RoleInvalidationKind)
 			return UpdateRbacRoles(ctx, log, ru, bypass, authenticated, anonymous)
 		},
 		eventbus.For("system:role"),
 		eventbus.On("afterUpdate", "afterCreate", "afterDelete"),
 	)
 
+	// Re-apply all roles to RBAC when roles are changed on other nodes
+	invalidation.Register(RoleInvalidationKind, func(ctx context.Context) error {
+		return UpdateRbacRoles(ctx, log, ru, bypass, authenticated, anonymous)
+	})
+
 	return nil
 }
 
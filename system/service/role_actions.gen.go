@@ -1147,6 +1147,40 @@ func RoleErrNameNotUnique(mm ...*roleActionProps) *errors.Error {
 	return e
 }
 
+// RoleErrMaxRoleLimitReached returns "system:role.maxRoleLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func RoleErrMaxRoleLimitReached(mm ...*roleActionProps) *errors.Error {
+	var p = &roleActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your role limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxRoleLimitReached"),
+		errors.Meta("resource", "system:role"),
+
+		errors.Meta(rolePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "role.errors.maxRoleLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -7,15 +7,18 @@ import (
 
 	automationService "github.com/cortezaproject/corteza-server/automation/service"
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/secure"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/healthcheck"
 	"github.com/cortezaproject/corteza-server/pkg/id"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/pkg/objstore"
+	"github.com/cortezaproject/corteza-server/pkg/objstore/cas"
 	"github.com/cortezaproject/corteza-server/pkg/objstore/minio"
 	"github.com/cortezaproject/corteza-server/pkg/objstore/plain"
 	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/pkg/rbac"
+	"github.com/cortezaproject/corteza-server/pkg/webauthn"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/automation"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -34,6 +37,8 @@ type (
 		Auth      options.AuthOpt
 		RBAC      options.RBACOpt
 		Limit     options.LimitOpt
+		Webhook   options.WebhookOpt
+		Apigw     options.ApigwOpt
 	}
 
 	eventDispatcher interface {
@@ -79,7 +84,9 @@ var (
 	DefaultQueue               *queue
 	DefaultApigwRoute          *apigwRoute
 	DefaultApigwFilter         *apigwFilter
+	DefaultApigwSecret         *apigwSecret
 	DefaultReport              *report
+	DefaultWebhook             *webhook
 
 	DefaultStatistics *statistics
 
@@ -130,6 +137,10 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 			bucket string
 		)
 		const svcPath = "system"
+
+		// prefix for content addressed blobs
+		blobPrefix := ""
+
 		if opt.MinioEndpoint != "" {
 			bucket = minio.GetBucket(opt.MinioBucket, svcPath)
 
@@ -149,12 +160,19 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 				zap.Error(err))
 		} else {
 			path := opt.Path + "/" + svcPath
+			blobPrefix = path
 			DefaultObjectStore, err = plain.New(path)
 			log.Info("initializing store",
 				zap.String("path", path),
 				zap.Error(err))
 		}
 
+		if err == nil && opt.Dedup {
+			DefaultObjectStore, err = cas.New(DefaultObjectStore, DefaultStore, svcPath, blobPrefix)
+			log.Info("initializing content addressed storage",
+				zap.Error(err))
+		}
+
 		if err != nil {
 			return err
 		}
@@ -162,22 +180,36 @@ func Initialize(ctx context.Context, log *zap.Logger, s store.Storer, ws websock
 
 	hcd.Add(objstore.Healthcheck(DefaultObjectStore), "ObjectStore/System")
 
-	DefaultRenderer = Renderer(c.Template)
+	DefaultRenderer = Renderer(c.Template, TemplateOptions{LimitTemplates: c.Limit.SystemTemplates})
 	DefaultResourceTranslation = ResourceTranslation()
 	DefaultReport = Report(DefaultStore, DefaultAccessControl, DefaultActionlog, eventbus.Service())
 	DefaultAuthNotification = AuthNotification(CurrentSettings, DefaultRenderer, c.Auth)
-	DefaultAuth = Auth(AuthOptions{LimitUsers: c.Limit.SystemUsers})
+
+	{
+		// WebAuthn credentials are bound to the domain of the auth frontend
+		rp, rpErr := webauthn.NewRelyingParty("", c.Auth.BaseURL)
+		if rpErr != nil {
+			DefaultLogger.Warn("could not configure WebAuthn relying party", zap.Error(rpErr))
+		}
+
+		DefaultAuth = Auth(AuthOptions{LimitUsers: c.Limit.SystemUsers, WebAuthn: rp})
+	}
+
 	DefaultAuthClient = AuthClient(DefaultStore, DefaultAccessControl, DefaultActionlog, eventbus.Service(), c.Auth)
 	DefaultUser = User(UserOptions{LimitUsers: c.Limit.SystemUsers})
-	DefaultRole = Role()
+	DefaultRole = Role(RoleOptions{LimitRoles: c.Limit.SystemRoles})
 	DefaultApplication = Application(DefaultStore, DefaultAccessControl, DefaultActionlog, eventbus.Service())
 	DefaultReminder = Reminder(ctx, DefaultLogger.Named("reminder"), ws)
 	DefaultSink = Sink()
-	DefaultStatistics = Statistics()
+	DefaultStatistics = Statistics(c.Limit)
 	DefaultAttachment = Attachment(DefaultObjectStore)
 	DefaultQueue = Queue()
-	DefaultApigwRoute = Route()
+	DefaultApigwRoute = Route(ApigwRouteOptions{LimitRoutes: c.Limit.SystemGatewayRoutes})
 	DefaultApigwFilter = Filter()
+	DefaultApigwSecret = ApigwSecret(secure.NewKeyring(c.Apigw.SecretsKey, c.Apigw.SecretsPreviousKeys))
+	DefaultWebhook = Webhook(DefaultLogger.Named("webhook"), c.Webhook)
+	DefaultWebhook.RegisterArgsGuard("system:user", webhookUserGuard(DefaultAccessControl))
+	DefaultWebhook.RegisterArgsGuard("system:role", webhookRoleGuard(DefaultAccessControl))
 
 	if err = initRoles(ctx, log.Named("rbac.roles"), c.RBAC, eventbus.Service(), rbac.Global()); err != nil {
 		return err
@@ -240,11 +272,18 @@ func Activate(ctx context.Context) (err error) {
 		return
 	}
 
+	// Register webhooks on the eventbus
+	err = DefaultWebhook.Load(ctx)
+	if err != nil {
+		return
+	}
+
 	return
 }
 
 func Watchers(ctx context.Context) {
 	DefaultReminder.Watch(ctx)
+	DefaultWebhook.Watch(ctx)
 	return
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/errors"
+	"github.com/cortezaproject/corteza-server/pkg/invalidation"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/system/types"
@@ -44,6 +45,11 @@ type (
 	}
 )
 
+const (
+	// SettingsInvalidationKind is used to notify other nodes about changed settings
+	SettingsInvalidationKind = "system:settings"
+)
+
 var (
 	ErrNoReadPermission   = fmt.Errorf("not allowed to read settings")
 	ErrNoManagePermission = fmt.Errorf("not allowed to manage settings")
@@ -61,6 +67,9 @@ func Settings(ctx context.Context, s store.Settings, log *zap.Logger, ac accessC
 
 	svc.watch(ctx)
 
+	// reload settings when they are changed on other nodes
+	invalidation.Register(SettingsInvalidationKind, svc.UpdateCurrent)
+
 	return svc
 }
 
@@ -201,7 +210,7 @@ func (svc *settings) Set(ctx context.Context, v *types.SettingValue) (err error)
 	}
 
 	svc.logChange(ctx, types.SettingValueSet{v})
-	return svc.updateCurrent(ctx, types.SettingValueSet{v})
+	return svc.changed(ctx, types.SettingValueSet{v})
 }
 
 func (svc *settings) BulkSet(ctx context.Context, vv types.SettingValueSet) (err error) {
@@ -238,7 +247,7 @@ func (svc *settings) BulkSet(ctx context.Context, vv types.SettingValueSet) (err
 
 	svc.logChange(ctx, vv)
 
-	return svc.updateCurrent(ctx, vv)
+	return svc.changed(ctx, vv)
 }
 
 func (svc *settings) logChange(ctx context.Context, vv types.SettingValueSet) {
@@ -275,5 +284,16 @@ func (svc *settings) Delete(ctx context.Context, name string, ownedBy uint64) er
 		zap.String("name", name),
 		zap.Uint64("owned-by", ownedBy)).Info("setting value removed")
 
-	return svc.updateCurrent(ctx, vv)
+	return svc.changed(ctx, vv)
+}
+
+// changed updates current settings with changed values
+// and notifies other nodes about the change
+func (svc *settings) changed(ctx context.Context, vv types.SettingValueSet) error {
+	if err := svc.updateCurrent(ctx, vv); err != nil {
+		return err
+	}
+
+	invalidation.Notify(ctx, SettingsInvalidationKind)
+	return nil
 }
@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 
+	automationTypes "github.com/cortezaproject/corteza-server/automation/types"
+	composeTypes "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/store"
 
 	"github.com/cortezaproject/corteza-server/pkg/actionlog"
@@ -14,12 +18,14 @@ type (
 		ac        statsAccessControl
 		actionlog actionlog.Recorder
 		store     store.Storer
+		limit     options.LimitOpt
 	}
 
 	statsAccessControl interface {
 		CanSearchUsers(context.Context) bool
 		CanSearchRoles(context.Context) bool
 		CanSearchApplications(context.Context) bool
+		CanReadLimits(context.Context) bool
 	}
 
 	StatisticsMetricsPayload struct {
@@ -27,13 +33,33 @@ type (
 		Roles        *types.RoleMetrics        `json:"roles"`
 		Applications *types.ApplicationMetrics `json:"applications"`
 	}
+
+	// StatisticsLimit holds configured limit and current usage
+	//
+	// Limit 0 means there is no limit
+	StatisticsLimit struct {
+		Limit int  `json:"limit"`
+		Used  uint `json:"used"`
+	}
+
+	StatisticsLimitsPayload struct {
+		Users         StatisticsLimit `json:"users"`
+		Roles         StatisticsLimit `json:"roles"`
+		GatewayRoutes StatisticsLimit `json:"gatewayRoutes"`
+		Templates     StatisticsLimit `json:"templates"`
+		Namespaces    StatisticsLimit `json:"namespaces"`
+		Modules       StatisticsLimit `json:"modules"`
+		Records       StatisticsLimit `json:"records"`
+		Workflows     StatisticsLimit `json:"workflows"`
+	}
 )
 
-func Statistics() *statistics {
+func Statistics(limit options.LimitOpt) *statistics {
 	return &statistics{
 		ac:        DefaultAccessControl,
 		actionlog: DefaultActionlog,
 		store:     DefaultStore,
+		limit:     limit,
 	}
 }
 
@@ -67,3 +93,62 @@ func (svc statistics) Metrics(ctx context.Context) (rval *StatisticsMetricsPaylo
 
 	return rval, svc.recordAction(ctx, &statisticsActionProps{}, StatisticsActionServe, err)
 }
+
+// Limits returns configured resource limits with their current usage
+//
+// Usage is counted the same way as when limits are enforced
+// by the services (deleted resources are not counted)
+func (svc statistics) Limits(ctx context.Context) (rval *StatisticsLimitsPayload, err error) {
+	err = func() error {
+		if !svc.ac.CanReadLimits(ctx) {
+			return StatisticsErrNotAllowedToReadLimits()
+		}
+
+		rval = &StatisticsLimitsPayload{
+			Users:         StatisticsLimit{Limit: svc.limit.SystemUsers},
+			Roles:         StatisticsLimit{Limit: svc.limit.SystemRoles},
+			GatewayRoutes: StatisticsLimit{Limit: svc.limit.SystemGatewayRoutes},
+			Templates:     StatisticsLimit{Limit: svc.limit.SystemTemplates},
+			Namespaces:    StatisticsLimit{Limit: svc.limit.ComposeNamespaces},
+			Modules:       StatisticsLimit{Limit: svc.limit.ComposeModules},
+			Records:       StatisticsLimit{Limit: svc.limit.ComposeRecords},
+			Workflows:     StatisticsLimit{Limit: svc.limit.AutomationWorkflows},
+		}
+
+		if rval.Users.Used, err = countValidUsers(ctx, svc.store); err != nil {
+			return err
+		}
+
+		if rval.Roles.Used, err = countValidRoles(ctx, svc.store); err != nil {
+			return err
+		}
+
+		if rval.GatewayRoutes.Used, err = countValidApigwRoutes(ctx, svc.store); err != nil {
+			return err
+		}
+
+		if rval.Templates.Used, err = countValidTemplates(ctx, svc.store); err != nil {
+			return err
+		}
+
+		if rval.Namespaces.Used, err = store.CountComposeNamespaces(ctx, svc.store, composeTypes.NamespaceFilter{}); err != nil {
+			return err
+		}
+
+		if rval.Modules.Used, err = store.CountComposeModules(ctx, svc.store, composeTypes.ModuleFilter{}); err != nil {
+			return err
+		}
+
+		if rval.Records.Used, err = store.CountComposeRecords(ctx, svc.store); err != nil {
+			return err
+		}
+
+		if rval.Workflows.Used, err = store.CountAutomationWorkflows(ctx, svc.store, automationTypes.WorkflowFilter{Disabled: filter.StateInclusive}); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return rval, svc.recordAction(ctx, &statisticsActionProps{}, StatisticsActionLimits, err)
+}
@@ -129,6 +129,26 @@ func StatisticsActionServe(props ...*statisticsActionProps) *statisticsAction {
 	return a
 }
 
+// StatisticsActionLimits returns "system:statistics.limits" action
+//
+// This function is auto-generated.
+//
+func StatisticsActionLimits(props ...*statisticsActionProps) *statisticsAction {
+	a := &statisticsAction{
+		timestamp: time.Now(),
+		resource:  "system:statistics",
+		action:    "limits",
+		log:       "limits served",
+		severity:  actionlog.Debug,
+	}
+
+	if len(props) > 0 {
+		a.props = props[0]
+	}
+
+	return a
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 // Error constructors
@@ -203,6 +223,40 @@ func StatisticsErrNotAllowedToReadStatistics(mm ...*statisticsActionProps) *erro
 	return e
 }
 
+// StatisticsErrNotAllowedToReadLimits returns "system:statistics.notAllowedToReadLimits" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func StatisticsErrNotAllowedToReadLimits(mm ...*statisticsActionProps) *errors.Error {
+	var p = &statisticsActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("not allowed to read limits", nil),
+
+		errors.Meta("type", "notAllowedToReadLimits"),
+		errors.Meta("resource", "system:statistics"),
+
+		errors.Meta(statisticsPropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "statistics.errors.notAllowedToReadLimits"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -3,6 +3,7 @@ package service
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"strconv"
@@ -24,6 +25,12 @@ type (
 		ac        templateAccessController
 
 		renderer rendererService
+
+		opt TemplateOptions
+	}
+
+	TemplateOptions struct {
+		LimitTemplates int
 	}
 
 	templateAccessController interface {
@@ -57,8 +64,10 @@ type (
 	}
 )
 
-func Renderer(cfg options.TemplateOpt) *template {
+func Renderer(cfg options.TemplateOpt, opt TemplateOptions) *template {
 	return &template{
+		opt: opt,
+
 		actionlog: DefaultActionlog,
 		store:     DefaultStore,
 		ac:        DefaultAccessControl,
@@ -204,6 +213,14 @@ func (svc template) Create(ctx context.Context, new *types.Template) (tpl *types
 			return TemplateErrNotAllowedToCreate()
 		}
 
+		if !validTemplateEncoding(new) {
+			return TemplateErrInvalidEncoding()
+		}
+
+		if err = svc.checkLimits(ctx); err != nil {
+			return
+		}
+
 		// @todo corredor?
 
 		// Set new values after beforeCreate events are emitted
@@ -240,6 +257,10 @@ func (svc template) Update(ctx context.Context, upd *types.Template) (tpl *types
 			return TemplateErrInvalidHandle()
 		}
 
+		if !validTemplateEncoding(upd) {
+			return TemplateErrInvalidEncoding()
+		}
+
 		if tpl, err = store.LookupTemplateByID(ctx, svc.store, upd.ID); err != nil {
 			return
 		}
@@ -333,6 +354,10 @@ func (svc template) UndeleteByID(ctx context.Context, ID uint64) (err error) {
 			return TemplateErrNotAllowedToUndelete()
 		}
 
+		if err = svc.checkLimits(ctx); err != nil {
+			return
+		}
+
 		// @todo corredor?
 		tpl.DeletedAt = nil
 		if err = store.UpdateTemplate(ctx, svc.store, tpl); err != nil {
@@ -345,6 +370,24 @@ func (svc template) UndeleteByID(ctx context.Context, ID uint64) (err error) {
 	return svc.recordAction(ctx, tplProps, TemplateActionUndelete, err)
 }
 
+func (svc template) checkLimits(ctx context.Context) error {
+	if svc.opt.LimitTemplates == 0 {
+		return nil
+	}
+
+	if c, err := countValidTemplates(ctx, svc.store); err != nil {
+		return err
+	} else if c >= uint(svc.opt.LimitTemplates) {
+		return TemplateErrMaxTemplateLimitReached()
+	}
+
+	return nil
+}
+
+func countValidTemplates(ctx context.Context, s store.Templates) (c uint, err error) {
+	return store.CountTemplates(ctx, s, types.TemplateFilter{})
+}
+
 func (svc template) Drivers() []renderer.DriverDefinition {
 	return svc.renderer.Drivers()
 }
@@ -387,9 +430,14 @@ func (svc template) Render(ctx context.Context, templateID uint64, dstType strin
 			return err
 		}
 
+		src, err := svc.getSource(tpl)
+		if err != nil {
+			return err
+		}
+
 		// Prepare payload
 		p := &renderer.RendererPayload{
-			Template:     svc.getSource(tpl),
+			Template:     src,
 			TemplateType: tpl.Type,
 			TargetType:   types.DocumentType(dstType),
 			Variables:    variables,
@@ -411,8 +459,27 @@ func (svc template) Render(ctx context.Context, templateID uint64, dstType strin
 
 // Util things
 
-func (svc template) getSource(tpl *types.Template) io.Reader {
-	return bytes.NewBuffer([]byte(tpl.Template))
+func (svc template) getSource(tpl *types.Template) (io.Reader, error) {
+	if tpl.Type.Binary() {
+		bb, err := base64.StdEncoding.DecodeString(tpl.Template)
+		if err != nil {
+			return nil, TemplateErrInvalidEncoding().Wrap(err)
+		}
+
+		return bytes.NewReader(bb), nil
+	}
+
+	return bytes.NewBuffer([]byte(tpl.Template)), nil
+}
+
+// validTemplateEncoding checks if binary templates are base64 encoded
+func validTemplateEncoding(tpl *types.Template) bool {
+	if !tpl.Type.Binary() {
+		return true
+	}
+
+	_, err := base64.StdEncoding.DecodeString(tpl.Template)
+	return err == nil
 }
 
 func (svc template) getPartials(ctx context.Context, tpl *types.Template) ([]*renderer.TemplatePartial, error) {
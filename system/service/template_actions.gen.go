@@ -577,6 +577,40 @@ func TemplateErrCannotRenderPartial(mm ...*templateActionProps) *errors.Error {
 	return e
 }
 
+// TemplateErrInvalidEncoding returns "system:template.invalidEncoding" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func TemplateErrInvalidEncoding(mm ...*templateActionProps) *errors.Error {
+	var p = &templateActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("invalid template encoding; binary templates must be base64 encoded", nil),
+
+		errors.Meta("type", "invalidEncoding"),
+		errors.Meta("resource", "system:template"),
+
+		errors.Meta(templatePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "template.errors.invalidEncoding"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // TemplateErrNotAllowedToRead returns "system:template.notAllowedToRead" as *errors.Error
 //
 //
@@ -829,6 +863,40 @@ func TemplateErrNotAllowedToRender(mm ...*templateActionProps) *errors.Error {
 	return e
 }
 
+// TemplateErrMaxTemplateLimitReached returns "system:template.maxTemplateLimitReached" as *errors.Error
+//
+//
+// This function is auto-generated.
+//
+func TemplateErrMaxTemplateLimitReached(mm ...*templateActionProps) *errors.Error {
+	var p = &templateActionProps{}
+	if len(mm) > 0 {
+		p = mm[0]
+	}
+
+	var e = errors.New(
+		errors.KindInternal,
+
+		p.Format("you have reached your template limit, contact your Corteza administrator", nil),
+
+		errors.Meta("type", "maxTemplateLimitReached"),
+		errors.Meta("resource", "system:template"),
+
+		errors.Meta(templatePropsMetaKey{}, p),
+
+		// translation namespace & key
+		errors.Meta(locale.ErrorMetaNamespace{}, "system"),
+		errors.Meta(locale.ErrorMetaKey{}, "template.errors.maxTemplateLimitReached"),
+
+		errors.StackSkip(1),
+	)
+
+	if len(mm) > 0 {
+	}
+
+	return e
+}
+
 // *********************************************************************************************************************
 // *********************************************************************************************************************
 
@@ -0,0 +1,873 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/actionlog"
+	a "github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/cluster"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/pkg/sentry"
+	"github.com/cortezaproject/corteza-server/pkg/version"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"go.uber.org/zap"
+)
+
+type (
+	webhook struct {
+		actionlog actionlog.Recorder
+		store     store.Storer
+		ac        webhookAccessController
+		eventbus  webhookEventRegistry
+		client    *http.Client
+
+		opt options.WebhookOpt
+		log *zap.Logger
+
+		// maps registered event handlers (value, uintptr) to webhook ID (key, uint64)
+		reg map[uint64]uintptr
+		mux *sync.Mutex
+
+		// argument guards per resource type
+		guards map[string]WebhookArgsGuard
+	}
+
+	// WebhookArgsGuard checks if the webhook owner (identity in the context)
+	// can read the resource that fired the event and trims encoded event
+	// arguments to what the owner is allowed to read
+	//
+	// Returns false when owner can not read the resource
+	WebhookArgsGuard func(ctx context.Context, ev eventbus.Event, args map[string][]byte) (bool, error)
+
+	webhookAccessController interface {
+		CanCreateWebhook(ctx context.Context) bool
+		CanSearchWebhooks(ctx context.Context) bool
+		CanReadWebhook(ctx context.Context, r *types.Webhook) bool
+		CanUpdateWebhook(ctx context.Context, r *types.Webhook) bool
+		CanDeleteWebhook(ctx context.Context, r *types.Webhook) bool
+	}
+
+	webhookEventRegistry interface {
+		Register(h eventbus.HandlerFn, ops ...eventbus.HandlerRegOp) uintptr
+		Unregister(ptrs ...uintptr)
+	}
+
+	// Events from all components that can encode their
+	// arguments (same as for corredor scripts)
+	webhookEncodableEvent interface {
+		Encode() (map[string][]byte, error)
+	}
+
+	webhookPayload struct {
+		DeliveryID   uint64                     `json:"deliveryID,string"`
+		WebhookID    uint64                     `json:"webhookID,string"`
+		ResourceType string                     `json:"resourceType"`
+		EventType    string                     `json:"eventType"`
+		Timestamp    time.Time                  `json:"timestamp"`
+		Args         map[string]json.RawMessage `json:"args,omitempty"`
+	}
+)
+
+const (
+	WebhookHeaderWebhookID = "X-Corteza-Webhook"
+	WebhookHeaderDelivery  = "X-Corteza-Delivery"
+	WebhookHeaderResource  = "X-Corteza-Resource"
+	WebhookHeaderEvent     = "X-Corteza-Event"
+	WebhookHeaderSignature = "X-Corteza-Signature"
+
+	// limits the amount of response body that is read
+	// and stored with the delivery error
+	webhookMaxErrorBody = 1024
+
+	// cluster lease that guards retries of pending deliveries
+	webhookRetryLease = "system.webhook.retry"
+)
+
+func Webhook(log *zap.Logger, opt options.WebhookOpt) *webhook {
+	return &webhook{
+		ac:        DefaultAccessControl,
+		actionlog: DefaultActionlog,
+		store:     DefaultStore,
+		eventbus:  eventbus.Service(),
+		client:    &http.Client{},
+		opt:       opt,
+		log:       log,
+		reg:       make(map[uint64]uintptr),
+		mux:       &sync.Mutex{},
+		guards:    make(map[string]WebhookArgsGuard),
+	}
+}
+
+// RegisterArgsGuard registers guard for events of the resource type
+//
+// Events of resource types without a guard are delivered
+// without arguments.
+func (svc *webhook) RegisterArgsGuard(resourceType string, g WebhookArgsGuard) {
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+	svc.guards[resourceType] = g
+}
+
+func (svc *webhook) FindByID(ctx context.Context, ID uint64) (wh *types.Webhook, err error) {
+	var (
+		whProps = &webhookActionProps{}
+	)
+
+	err = func() error {
+		if ID == 0 {
+			return WebhookErrInvalidID()
+		}
+
+		if wh, err = store.LookupWebhookByID(ctx, svc.store, ID); err != nil {
+			return WebhookErrNotFound().Wrap(err)
+		}
+
+		whProps.setWebhook(wh)
+
+		if !svc.ac.CanReadWebhook(ctx, wh) {
+			return WebhookErrNotAllowedToRead(whProps)
+		}
+
+		return nil
+	}()
+
+	return wh, svc.recordAction(ctx, whProps, WebhookActionLookup, err)
+}
+
+func (svc *webhook) Search(ctx context.Context, filter types.WebhookFilter) (set types.WebhookSet, f types.WebhookFilter, err error) {
+	var (
+		whProps = &webhookActionProps{filter: &filter}
+	)
+
+	// For each fetched item, store backend will check if it is valid or not
+	filter.Check = func(res *types.Webhook) (bool, error) {
+		if !svc.ac.CanReadWebhook(ctx, res) {
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	err = func() error {
+		if !svc.ac.CanSearchWebhooks(ctx) {
+			return WebhookErrNotAllowedToSearch()
+		}
+
+		if set, f, err = store.SearchWebhooks(ctx, svc.store, filter); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return set, f, svc.recordAction(ctx, whProps, WebhookActionSearch, err)
+}
+
+func (svc *webhook) Create(ctx context.Context, new *types.Webhook) (wh *types.Webhook, err error) {
+	var (
+		whProps = &webhookActionProps{new: new}
+	)
+
+	err = func() (err error) {
+		if !svc.ac.CanCreateWebhook(ctx) {
+			return WebhookErrNotAllowedToCreate(whProps)
+		}
+
+		if err = svc.validate(new, whProps); err != nil {
+			return
+		}
+
+		new.ID = nextID()
+		new.CreatedAt = *now()
+		new.CreatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if new.OwnedBy == 0 {
+			new.OwnedBy = new.CreatedBy
+		}
+
+		// payloads are checked against owner's permissions;
+		// prevent creating webhooks on behalf of other users
+		if new.OwnedBy != new.CreatedBy {
+			return WebhookErrInvalidOwner(whProps)
+		}
+
+		if err = store.CreateWebhook(ctx, svc.store, new); err != nil {
+			return
+		}
+
+		wh = new
+		svc.register(wh)
+
+		return nil
+	}()
+
+	return wh, svc.recordAction(ctx, whProps, WebhookActionCreate, err)
+}
+
+// Update modifies existing webhook
+//
+// When secret is not set, the existing one is kept
+func (svc *webhook) Update(ctx context.Context, upd *types.Webhook) (wh *types.Webhook, err error) {
+	var (
+		whProps = &webhookActionProps{update: upd}
+		old     *types.Webhook
+	)
+
+	err = func() (err error) {
+		if upd.ID == 0 {
+			return WebhookErrInvalidID()
+		}
+
+		if old, err = store.LookupWebhookByID(ctx, svc.store, upd.ID); err != nil {
+			return WebhookErrNotFound(whProps).Wrap(err)
+		}
+
+		whProps.setWebhook(old)
+
+		if !svc.ac.CanUpdateWebhook(ctx, old) {
+			return WebhookErrNotAllowedToUpdate(whProps)
+		}
+
+		if err = svc.validate(upd, whProps); err != nil {
+			return
+		}
+
+		if upd.Secret == "" {
+			upd.Secret = old.Secret
+		}
+
+		if upd.OwnedBy == 0 {
+			upd.OwnedBy = old.OwnedBy
+		}
+
+		// ownership can only be taken over, never handed to another user
+		if upd.OwnedBy != old.OwnedBy && upd.OwnedBy != a.GetIdentityFromContext(ctx).Identity() {
+			return WebhookErrInvalidOwner(whProps)
+		}
+
+		upd.CreatedAt = old.CreatedAt
+		upd.CreatedBy = old.CreatedBy
+		upd.DeletedAt = old.DeletedAt
+		upd.DeletedBy = old.DeletedBy
+		upd.UpdatedAt = now()
+		upd.UpdatedBy = a.GetIdentityFromContext(ctx).Identity()
+
+		if err = store.UpdateWebhook(ctx, svc.store, upd); err != nil {
+			return
+		}
+
+		wh = upd
+		svc.register(wh)
+
+		return nil
+	}()
+
+	return wh, svc.recordAction(ctx, whProps, WebhookActionUpdate, err)
+}
+
+func (svc *webhook) DeleteByID(ctx context.Context, ID uint64) (err error) {
+	var (
+		whProps = &webhookActionProps{}
+		wh      *types.Webhook
+	)
+
+	err = func() (err error) {
+		if ID == 0 {
+			return WebhookErrInvalidID()
+		}
+
+		if wh, err = store.LookupWebhookByID(ctx, svc.store, ID); err != nil {
+			return WebhookErrNotFound().Wrap(err)
+		}
+
+		whProps.setWebhook(wh)
+
+		if !svc.ac.CanDeleteWebhook(ctx, wh) {
+			return WebhookErrNotAllowedToDelete(whProps)
+		}
+
+		wh.DeletedAt = now()
+		wh.DeletedBy = a.GetIdentityFromContext(ctx).Identity()
+		if err = store.UpdateWebhook(ctx, svc.store, wh); err != nil {
+			return
+		}
+
+		svc.register(wh)
+
+		return nil
+	}()
+
+	return svc.recordAction(ctx, whProps, WebhookActionDelete, err)
+}
+
+func (svc *webhook) UndeleteByID(ctx context.Context, ID uint64) (err error) {
+	var (
+		whProps = &webhookActionProps{}
+		wh      *types.Webhook
+	)
+
+	err = func() (err error) {
+		if ID == 0 {
+			return WebhookErrInvalidID()
+		}
+
+		if wh, err = store.LookupWebhookByID(ctx, svc.store, ID); err != nil {
+			return WebhookErrNotFound().Wrap(err)
+		}
+
+		whProps.setWebhook(wh)
+
+		if !svc.ac.CanDeleteWebhook(ctx, wh) {
+			return WebhookErrNotAllowedToUndelete(whProps)
+		}
+
+		wh.DeletedAt = nil
+		wh.DeletedBy = 0
+		if err = store.UpdateWebhook(ctx, svc.store, wh); err != nil {
+			return
+		}
+
+		svc.register(wh)
+
+		return nil
+	}()
+
+	return svc.recordAction(ctx, whProps, WebhookActionUndelete, err)
+}
+
+// Deliveries returns delivery log of a webhook
+func (svc *webhook) Deliveries(ctx context.Context, webhookID uint64, filter types.WebhookDeliveryFilter) (set types.WebhookDeliverySet, f types.WebhookDeliveryFilter, err error) {
+	var (
+		whProps = &webhookActionProps{}
+		wh      *types.Webhook
+	)
+
+	err = func() error {
+		if wh, err = svc.FindByID(ctx, webhookID); err != nil {
+			return err
+		}
+
+		whProps.setWebhook(wh)
+
+		filter.WebhookID = wh.ID
+		if set, f, err = store.SearchWebhookDeliveries(ctx, svc.store, filter); err != nil {
+			return err
+		}
+
+		return nil
+	}()
+
+	return set, f, svc.recordAction(ctx, whProps, WebhookActionDeliveries, err)
+}
+
+// Redeliver resets delivery attempts and delivers stored payload again
+//
+// Redelivery is done immediately; when it fails, delivery is retried
+// in the same way as the original delivery
+func (svc *webhook) Redeliver(ctx context.Context, webhookID, deliveryID uint64) (d *types.WebhookDelivery, err error) {
+	var (
+		whProps = &webhookActionProps{}
+		wh      *types.Webhook
+	)
+
+	err = func() error {
+		if webhookID == 0 || deliveryID == 0 {
+			return WebhookErrInvalidID()
+		}
+
+		if wh, err = store.LookupWebhookByID(ctx, svc.store, webhookID); err != nil {
+			return WebhookErrNotFound().Wrap(err)
+		}
+
+		whProps.setWebhook(wh)
+
+		if !svc.ac.CanUpdateWebhook(ctx, wh) {
+			return WebhookErrNotAllowedToUpdate(whProps)
+		}
+
+		if d, err = store.LookupWebhookDeliveryByID(ctx, svc.store, deliveryID); err != nil || d.WebhookID != wh.ID {
+			return WebhookErrDeliveryNotFound(whProps)
+		}
+
+		whProps.setDelivery(d)
+
+		d.Status = types.WebhookDeliveryStatusPending
+		d.Attempts = 0
+		d.DeliveredAt = nil
+		d.NextAttemptAt = now()
+
+		// Delivery errors are logged with the delivery;
+		// only store errors are returned
+		return svc.attempt(ctx, wh, d)
+	}()
+
+	return d, svc.recordAction(ctx, whProps, WebhookActionRedeliver, err)
+}
+
+// Load registers all enabled webhooks on the eventbus
+func (svc *webhook) Load(ctx context.Context) error {
+	if !svc.opt.Enabled {
+		return nil
+	}
+
+	set, _, err := store.SearchWebhooks(ctx, svc.store, types.WebhookFilter{})
+	if err != nil {
+		return err
+	}
+
+	for _, wh := range set {
+		svc.register(wh)
+	}
+
+	svc.log.Debug("webhooks loaded", zap.Int("count", len(set)))
+
+	return nil
+}
+
+// Watch periodically retries pending deliveries
+func (svc *webhook) Watch(ctx context.Context) {
+	if !svc.opt.Enabled || svc.opt.RetryInterval == 0 {
+		return
+	}
+
+	var (
+		rTicker = time.NewTicker(svc.opt.RetryInterval)
+	)
+
+	// only one node in the cluster retries pending deliveries
+	cluster.Register(ctx, webhookRetryLease)
+
+	go func() {
+		defer sentry.Recover()
+		defer rTicker.Stop()
+		defer svc.log.Info("stopped")
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-rTicker.C:
+				if !cluster.Holds(webhookRetryLease) {
+					continue
+				}
+
+				if err := svc.retryPending(ctx); err != nil {
+					svc.log.Error("failed to retry pending webhook deliveries", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func (svc *webhook) validate(wh *types.Webhook, whProps *webhookActionProps) error {
+	if u, err := url.Parse(wh.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return WebhookErrInvalidURL(whProps)
+	}
+
+	if wh.ResourceType == "" {
+		return WebhookErrInvalidResourceType(whProps)
+	}
+
+	if len(wh.EventTypes) == 0 {
+		return WebhookErrInvalidEventTypes(whProps)
+	}
+
+	for _, c := range wh.Constraints {
+		if _, err := eventbus.ConstraintMaker(c.Name, c.Op, c.Values...); err != nil {
+			return WebhookErrInvalidConstraint(whProps).Wrap(err)
+		}
+	}
+
+	return nil
+}
+
+// register (re)registers webhook on the eventbus
+//
+// Deleted and disabled webhooks are only unregistered
+func (svc *webhook) register(wh *types.Webhook) {
+	svc.mux.Lock()
+	defer svc.mux.Unlock()
+
+	if ptr, has := svc.reg[wh.ID]; has {
+		svc.eventbus.Unregister(ptr)
+		delete(svc.reg, wh.ID)
+	}
+
+	if !svc.opt.Enabled || !wh.Enabled || wh.DeletedAt != nil {
+		return
+	}
+
+	ops := []eventbus.HandlerRegOp{
+		eventbus.On(wh.EventTypes...),
+		eventbus.For(wh.ResourceType),
+	}
+
+	for _, c := range wh.Constraints {
+		if cnstr, err := eventbus.ConstraintMaker(c.Name, c.Op, c.Values...); err != nil {
+			svc.log.Debug(
+				"failed to make constraint for webhook",
+				zap.Uint64("webhookID", wh.ID),
+				zap.Any("constraint", c),
+				zap.Error(err),
+			)
+		} else {
+			ops = append(ops, eventbus.Constraint(cnstr))
+		}
+	}
+
+	svc.reg[wh.ID] = svc.eventbus.Register(svc.makeHandler(wh.ID, wh.OwnedBy), ops...)
+}
+
+// makeHandler creates eventbus handler that logs and delivers the event
+//
+// Events are only delivered when webhook owner can read the resource that
+// fired the event. Delivery is done in the background, handler never fails
+func (svc *webhook) makeHandler(webhookID, ownerID uint64) eventbus.HandlerFn {
+	return func(_ context.Context, ev eventbus.Event) error {
+		// request context can be canceled before delivery is done
+		ctx := a.SetIdentityToContext(context.Background(), a.ServiceUser())
+
+		args, ok, err := svc.ownerArgs(ctx, ownerID, ev)
+		if err != nil {
+			svc.log.Error("failed to check webhook owner's access", zap.Uint64("webhookID", webhookID), zap.Error(err))
+			return nil
+		}
+
+		if !ok {
+			svc.log.Debug(
+				"webhook owner can not read the resource, skipping delivery",
+				zap.Uint64("webhookID", webhookID),
+				zap.Uint64("ownerID", ownerID),
+			)
+			return nil
+		}
+
+		d, err := svc.makeDelivery(webhookID, ev, args)
+		if err != nil {
+			svc.log.Error("failed to prepare webhook delivery", zap.Uint64("webhookID", webhookID), zap.Error(err))
+			return nil
+		}
+
+		if err = store.CreateWebhookDelivery(ctx, svc.store, d); err != nil {
+			svc.log.Error("failed to store webhook delivery", zap.Uint64("webhookID", webhookID), zap.Error(err))
+			return nil
+		}
+
+		go func() {
+			defer sentry.Recover()
+
+			wh, err := store.LookupWebhookByID(ctx, svc.store, webhookID)
+			if err != nil {
+				svc.log.Error("failed to load webhook", zap.Uint64("webhookID", webhookID), zap.Error(err))
+				return
+			}
+
+			if err = svc.attempt(ctx, wh, d); err != nil {
+				svc.log.Error("failed to update webhook delivery", zap.Uint64("deliveryID", d.ID), zap.Error(err))
+			}
+		}()
+
+		return nil
+	}
+}
+
+// ownerArgs checks if webhook owner can read the resource that fired the event
+// and returns encoded event arguments trimmed to what the owner can read
+//
+// Deleted or suspended owners do not receive anything, events of resources
+// without a registered guard are delivered without arguments
+func (svc *webhook) ownerArgs(ctx context.Context, ownerID uint64, ev eventbus.Event) (args map[string][]byte, ok bool, err error) {
+	owner, err := svc.ownerIdentity(ctx, ownerID)
+	if err != nil || owner == nil {
+		return nil, false, err
+	}
+
+	svc.mux.Lock()
+	guard := svc.guards[ev.ResourceType()]
+	svc.mux.Unlock()
+
+	if guard == nil {
+		return nil, true, nil
+	}
+
+	enc, is := ev.(webhookEncodableEvent)
+	if !is {
+		return nil, true, nil
+	}
+
+	if args, err = enc.Encode(); err != nil {
+		return nil, false, err
+	}
+
+	if ok, err = guard(a.SetIdentityToContext(ctx, owner), ev, args); err != nil || !ok {
+		return nil, false, err
+	}
+
+	return args, true, nil
+}
+
+// ownerIdentity loads webhook owner with all roles
+//
+// Owner is loaded on every event so that changes
+// in owner's roles are respected right away
+func (svc *webhook) ownerIdentity(ctx context.Context, ownerID uint64) (a.Identifiable, error) {
+	if ownerID == 0 {
+		return nil, nil
+	}
+
+	u, err := store.LookupUserByID(ctx, svc.store, ownerID)
+	if err == store.ErrNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if !u.Valid() {
+		return nil, nil
+	}
+
+	mm, _, err := store.SearchRoleMembers(ctx, svc.store, types.RoleMemberFilter{UserID: u.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	rr := make([]uint64, 0, len(mm))
+	for _, m := range mm {
+		rr = append(rr, m.RoleID)
+	}
+
+	return a.Authenticated(u.ID, rr...), nil
+}
+
+func (svc *webhook) makeDelivery(webhookID uint64, ev eventbus.Event, args map[string][]byte) (d *types.WebhookDelivery, err error) {
+	d = &types.WebhookDelivery{
+		ID:           nextID(),
+		WebhookID:    webhookID,
+		ResourceType: ev.ResourceType(),
+		EventType:    ev.EventType(),
+		Status:       types.WebhookDeliveryStatusPending,
+		CreatedAt:    *now(),
+	}
+
+	// next-attempt is left empty until the initial attempt (made right away,
+	// in the background) fails; that way retries of pending deliveries
+	// can not pick up delivery that is still being sent
+	//
+	// Deliveries whose initial attempt never finished can be redelivered manually
+
+	p := webhookPayload{
+		DeliveryID:   d.ID,
+		WebhookID:    webhookID,
+		ResourceType: d.ResourceType,
+		EventType:    d.EventType,
+		Timestamp:    d.CreatedAt,
+	}
+
+	if len(args) > 0 {
+		p.Args = make(map[string]json.RawMessage)
+		for k, v := range args {
+			p.Args[k] = v
+		}
+	}
+
+	if d.Payload, err = json.Marshal(p); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// retryPending loads all pending deliveries that are due and tries to deliver them
+func (svc *webhook) retryPending(ctx context.Context) error {
+	ctx = a.SetIdentityToContext(ctx, a.ServiceUser())
+
+	dd, _, err := store.SearchWebhookDeliveries(ctx, svc.store, types.WebhookDeliveryFilter{
+		Status:            types.WebhookDeliveryStatusPending,
+		NextAttemptBefore: now(),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// cache webhooks; one webhook can have multiple pending deliveries
+	ww := make(map[uint64]*types.Webhook)
+
+	for _, d := range dd {
+		wh, has := ww[d.WebhookID]
+		if !has {
+			if wh, err = store.LookupWebhookByID(ctx, svc.store, d.WebhookID); err != nil && err != store.ErrNotFound {
+				return err
+			}
+
+			ww[d.WebhookID] = wh
+		}
+
+		if wh == nil || !wh.Enabled || wh.DeletedAt != nil {
+			// webhook was removed or disabled in the meantime
+			d.Status = types.WebhookDeliveryStatusFailed
+			d.Error = "webhook removed or disabled"
+			d.NextAttemptAt = nil
+			if err = store.UpdateWebhookDelivery(ctx, svc.store, d); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err = svc.attempt(ctx, wh, d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attempt sends the payload to the webhook URL and updates delivery
+//
+// On failure, next attempt is scheduled with exponential backoff
+// until max number of attempts is reached.
+//
+// Only errors from the store are returned
+func (svc *webhook) attempt(ctx context.Context, wh *types.Webhook, d *types.WebhookDelivery) error {
+	var (
+		whProps = &webhookActionProps{webhook: wh, delivery: d}
+
+		status, err = svc.send(ctx, wh, d)
+	)
+
+	d.Attempts++
+	d.LastAttemptAt = now()
+	d.ResponseStatus = status
+
+	if err == nil {
+		d.Status = types.WebhookDeliveryStatusDelivered
+		d.DeliveredAt = d.LastAttemptAt
+		d.NextAttemptAt = nil
+		d.Error = ""
+	} else {
+		d.Error = err.Error()
+
+		if int(d.Attempts) >= svc.opt.MaxAttempts {
+			d.Status = types.WebhookDeliveryStatusFailed
+			d.NextAttemptAt = nil
+		} else {
+			d.Status = types.WebhookDeliveryStatusPending
+			d.NextAttemptAt = svc.nextAttempt(*d.LastAttemptAt, d.Attempts)
+		}
+
+		err = WebhookErrDeliveryFailed(whProps).Wrap(err)
+	}
+
+	_ = svc.recordAction(ctx, whProps, WebhookActionDeliver, err)
+
+	return store.UpdateWebhookDelivery(ctx, svc.store, d)
+}
+
+// nextAttempt calculates time of the next attempt
+//
+// Delay doubles with each attempt
+func (svc *webhook) nextAttempt(from time.Time, attempts uint) *time.Time {
+	var (
+		delay = svc.opt.RetryDelay
+	)
+
+	for i := uint(1); i < attempts; i++ {
+		delay *= 2
+	}
+
+	next := from.Add(delay)
+	return &next
+}
+
+// send makes HTTP request to the webhook URL
+//
+// Any non-2xx status is considered as failed delivery
+func (svc *webhook) send(ctx context.Context, wh *types.Webhook, d *types.WebhookDelivery) (int, error) {
+	if svc.opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, svc.opt.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Corteza-Webhook/"+version.Version)
+	req.Header.Set(WebhookHeaderWebhookID, strconv.FormatUint(wh.ID, 10))
+	req.Header.Set(WebhookHeaderDelivery, strconv.FormatUint(d.ID, 10))
+	req.Header.Set(WebhookHeaderResource, d.ResourceType)
+	req.Header.Set(WebhookHeaderEvent, d.EventType)
+
+	if wh.Secret != "" {
+		req.Header.Set(WebhookHeaderSignature, WebhookSignature(wh.Secret, d.Payload))
+	}
+
+	rsp, err := svc.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, webhookMaxErrorBody))
+		return rsp.StatusCode, fmt.Errorf("unexpected response status %d: %s", rsp.StatusCode, body)
+	}
+
+	return rsp.StatusCode, nil
+}
+
+// WebhookSignature returns HMAC-SHA256 signature of the payload
+//
+// Format of the signature (sha256=<hex encoded HMAC>) allows receivers
+// to verify the payload with the shared secret
+func WebhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookUserGuard lets through only events of users that webhook owner can read
+func webhookUserGuard(ac interface {
+	CanReadUser(context.Context, *types.User) bool
+}) WebhookArgsGuard {
+	return func(ctx context.Context, ev eventbus.Event, _ map[string][]byte) (bool, error) {
+		if e, is := ev.(interface{ User() *types.User }); is && e.User() != nil {
+			return ac.CanReadUser(ctx, e.User()), nil
+		}
+
+		return false, nil
+	}
+}
+
+// webhookRoleGuard lets through only events of roles that webhook owner can read
+func webhookRoleGuard(ac interface {
+	CanReadRole(context.Context, *types.Role) bool
+}) WebhookArgsGuard {
+	return func(ctx context.Context, ev eventbus.Event, _ map[string][]byte) (bool, error) {
+		if e, is := ev.(interface{ Role() *types.Role }); is && e.Role() != nil {
+			return ac.CanReadRole(ctx, e.Role()), nil
+		}
+
+		return false, nil
+	}
+}
@@ -0,0 +1,57 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type (
+	// ApigwCacheEntry holds cached API Gateway route response
+	//
+	// Key is prefixed with route ID, entries are purged by route.
+	ApigwCacheEntry struct {
+		Key     string                `json:"key"`
+		RouteID uint64                `json:"routeID,string"`
+		Status  int                   `json:"status"`
+		Header  ApigwCacheEntryHeader `json:"header"`
+		Body    []byte                `json:"-"`
+		ETag    string                `json:"etag"`
+
+		CreatedAt time.Time `json:"createdAt"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+
+	ApigwCacheEntryHeader http.Header
+
+	ApigwCacheEntryFilter struct {
+		RouteID uint64 `json:"routeID,string"`
+	}
+)
+
+// Expired checks if entry is expired at the given time
+func (e ApigwCacheEntry) Expired(at time.Time) bool {
+	return !at.Before(e.ExpiresAt)
+}
+
+func (hh *ApigwCacheEntryHeader) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*hh = ApigwCacheEntryHeader{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, hh); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into ApigwCacheEntryHeader", string(b))
+		}
+	}
+
+	return nil
+}
+
+func (hh ApigwCacheEntryHeader) Value() (driver.Value, error) {
+	return json.Marshal(hh)
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/pkg/errors"
+)
+
+type (
+	// ApigwSecret holds encrypted credentials used by API Gateway filters
+	//
+	// Secrets are encrypted with envelope encryption; value is encrypted
+	// with a random data key and data key is encrypted with the key from options.
+	// Filters reference secrets by handle, plaintext value is never returned.
+	ApigwSecret struct {
+		ID     uint64          `json:"secretID,string"`
+		Handle string          `json:"handle"`
+		Meta   ApigwSecretMeta `json:"meta"`
+
+		// Plaintext value, set only when secret is created or updated;
+		// it is never stored or serialized
+		Value string `json:"-"`
+
+		// Encrypted value
+		Ciphertext []byte `json:"-"`
+
+		// Data key, encrypted with the key identified by KeyID
+		DataKey []byte `json:"-"`
+		KeyID   string `json:"keyID"`
+
+		RotatedAt *time.Time `json:"rotatedAt,omitempty"`
+
+		CreatedAt time.Time  `json:"createdAt,omitempty"`
+		CreatedBy uint64     `json:"createdBy,string" `
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+		UpdatedBy uint64     `json:"updatedBy,string,omitempty" `
+		DeletedAt *time.Time `json:"deletedAt,omitempty"`
+		DeletedBy uint64     `json:"deletedBy,string,omitempty" `
+	}
+
+	ApigwSecretMeta struct {
+		Description string `json:"description,omitempty"`
+	}
+
+	ApigwSecretFilter struct {
+		SecretID []uint64 `json:"secretID"`
+		Handle   string   `json:"handle"`
+		Query    string   `json:"query"`
+
+		// Only secrets that are not encrypted with this key
+		KeyIDNot string `json:"-"`
+
+		Deleted filter.State `json:"deleted"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*ApigwSecret) (bool, error) `json:"-"`
+
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+func (cc *ApigwSecretMeta) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*cc = ApigwSecretMeta{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, cc); err != nil {
+			return errors.Wrapf(err, "cannot scan '%v' into ApigwSecretMeta", string(b))
+		}
+	}
+
+	return nil
+}
+
+func (cc ApigwSecretMeta) Value() (driver.Value, error) {
+	return json.Marshal(cc)
+}
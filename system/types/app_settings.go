@@ -154,6 +154,17 @@ type (
 					// TOTP issuer, defaults to "Corteza"
 					Issuer string
 				} `kv:"totp"`
+
+				WebAuthn struct {
+					// Can users use WebAuthn (passkeys, security keys) for MFA
+					Enabled bool
+
+					// Is MFA with WebAuthn enforced?
+					Enforced bool
+
+					// Can users sign-in with a passkey instead of the password
+					Passwordless bool
+				} `kv:"webauthn"`
 			} `json:"-" kv:"multi-factor"`
 
 			Mail struct {
@@ -14,6 +14,11 @@ func ParseApigwfFilterParams(ss []string) (p ApigwFilterParams, err error) {
 	return p, parseStringsInput(ss, p)
 }
 
+func ParseApigwSecretMeta(ss []string) (p ApigwSecretMeta, err error) {
+	err = parseStringsInput(ss, &p)
+	return
+}
+
 func ParseTemplateMeta(ss []string) (p TemplateMeta, err error) {
 	p = TemplateMeta{}
 	return p, parseStringsInput(ss, p)
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/cortezaproject/corteza-server/pkg/filter"
@@ -62,6 +63,14 @@ type (
 	QueueMeta struct {
 		PollDelay      *time.Duration `json:"poll_delay"`
 		DispatchEvents bool           `json:"dispatch_events"`
+
+		// Redis consumer settings; stream defaults to queue name
+		RedisURL       string         `json:"redis_url"`
+		RedisStream    string         `json:"redis_stream"`
+		RedisGroup     string         `json:"redis_group"`
+		RedisConsumer  string         `json:"redis_consumer"`
+		RedisMaxLen    int64          `json:"redis_max_len"`
+		RedisClaimIdle *time.Duration `json:"redis_claim_idle"`
 	}
 )
 
@@ -69,7 +78,8 @@ func (h *QueueMeta) UnmarshalJSON(s []byte) error {
 	type Alias QueueMeta
 
 	aux := &struct {
-		PollDelay string `json:"poll_delay"`
+		PollDelay      string `json:"poll_delay"`
+		RedisClaimIdle string `json:"redis_claim_idle"`
 		*Alias
 	}{
 		Alias: (*Alias)(h),
@@ -86,6 +96,10 @@ func (h *QueueMeta) UnmarshalJSON(s []byte) error {
 		h.PollDelay = &d
 	}
 
+	if d, err := cast.ToDurationE(aux.RedisClaimIdle); err == nil && aux.RedisClaimIdle != "" {
+		h.RedisClaimIdle = &d
+	}
+
 	return nil
 }
 
@@ -100,15 +114,65 @@ func (m QueueMeta) MarshalJSON() ([]byte, error) {
 		pollDelay = m.PollDelay.String()
 	}
 
+	redisClaimIdle := ""
+	if m.RedisClaimIdle != nil {
+		redisClaimIdle = m.RedisClaimIdle.String()
+	}
+
 	return json.Marshal(struct {
 		PollDelay      string `json:"poll_delay"`
 		DispatchEvents bool   `json:"dispatch_events"`
+
+		RedisURL       string `json:"redis_url,omitempty"`
+		RedisStream    string `json:"redis_stream,omitempty"`
+		RedisGroup     string `json:"redis_group,omitempty"`
+		RedisConsumer  string `json:"redis_consumer,omitempty"`
+		RedisMaxLen    int64  `json:"redis_max_len,omitempty"`
+		RedisClaimIdle string `json:"redis_claim_idle,omitempty"`
 	}{
 		PollDelay:      pollDelay,
 		DispatchEvents: m.DispatchEvents,
+
+		RedisURL:       m.RedisURL,
+		RedisStream:    m.RedisStream,
+		RedisGroup:     m.RedisGroup,
+		RedisConsumer:  m.RedisConsumer,
+		RedisMaxLen:    m.RedisMaxLen,
+		RedisClaimIdle: redisClaimIdle,
 	})
 }
 
+// Masked returns copy of the meta with password in the redis URL masked
+func (m QueueMeta) Masked() QueueMeta {
+	m.RedisURL = maskURL(m.RedisURL)
+	return m
+}
+
+// KeepMasked replaces masked redis URL with the one from the stored meta
+//
+// Clients receive masked URLs and can send them back unchanged on update
+func (m *QueueMeta) KeepMasked(stored QueueMeta) {
+	if m.RedisURL != "" && m.RedisURL != stored.RedisURL && m.RedisURL == maskURL(stored.RedisURL) {
+		m.RedisURL = stored.RedisURL
+	}
+}
+
+// maskURL masks password in the URL
+//
+// Values that can not be parsed as URL are masked completely
+func maskURL(s string) string {
+	if s == "" {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return "xxxxx"
+	}
+
+	return u.Redacted()
+}
+
 func (m *QueueMeta) Scan(value interface{}) error {
 	switch value.(type) {
 	case nil:
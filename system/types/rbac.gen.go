@@ -15,6 +15,7 @@ package types
 // - system.role.yaml
 // - system.template.yaml
 // - system.user.yaml
+// - system.webhook.yaml
 // - system.yaml
 
 import (
@@ -38,6 +39,7 @@ const (
 	RoleResourceType        = "corteza::system:role"
 	TemplateResourceType    = "corteza::system:template"
 	UserResourceType        = "corteza::system:user"
+	WebhookResourceType     = "corteza::system:webhook"
 	ComponentResourceType   = "corteza::system"
 )
 
@@ -289,6 +291,37 @@ func UserRbacResourceTpl() string {
 	return "%s/%s"
 }
 
+// RbacResource returns string representation of RBAC resource for Webhook by calling WebhookRbacResource fn
+//
+// RBAC resource is in the corteza::system:webhook/... format
+//
+// This function is auto-generated
+func (r Webhook) RbacResource() string {
+	return WebhookRbacResource(r.ID)
+}
+
+// WebhookRbacResource returns string representation of RBAC resource for Webhook
+//
+// RBAC resource is in the corteza::system:webhook/... format
+//
+// This function is auto-generated
+func WebhookRbacResource(id uint64) string {
+	cpts := []interface{}{WebhookResourceType}
+	if id != 0 {
+		cpts = append(cpts, strconv.FormatUint(id, 10))
+	} else {
+		cpts = append(cpts, "*")
+	}
+
+	return fmt.Sprintf(WebhookRbacResourceTpl(), cpts...)
+
+}
+
+// @todo template
+func WebhookRbacResourceTpl() string {
+	return "%s/%s"
+}
+
 // RbacResource returns string representation of RBAC resource for Component by calling ComponentRbacResource fn
 //
 // RBAC resource is in the corteza::system/... format
@@ -71,8 +71,15 @@ const (
 	DocumentTypePlain DocumentType = "text/plain"
 	DocumentTypeHTML  DocumentType = "text/html"
 	DocumentTypePDF   DocumentType = "application/pdf"
+	DocumentTypeDOCX  DocumentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	DocumentTypeXLSX  DocumentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 )
 
+// Binary document templates (DOCX, XLSX) are stored base64 encoded
+func (t DocumentType) Binary() bool {
+	return t == DocumentTypeDOCX || t == DocumentTypeXLSX
+}
+
 func (t *TemplateMeta) Scan(value interface{}) error {
 	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
 	switch value.(type) {
@@ -10,6 +10,11 @@ package types
 
 type (
 
+	// ApigwCacheEntrySet slice of ApigwCacheEntry
+	//
+	// This type is auto-generated.
+	ApigwCacheEntrySet []*ApigwCacheEntry
+
 	// ApigwFilterSet slice of ApigwFilter
 	//
 	// This type is auto-generated.
@@ -20,6 +25,11 @@ type (
 	// This type is auto-generated.
 	ApigwRouteSet []*ApigwRoute
 
+	// ApigwSecretSet slice of ApigwSecret
+	//
+	// This type is auto-generated.
+	ApigwSecretSet []*ApigwSecret
+
 	// ApplicationSet slice of Application
 	//
 	// This type is auto-generated.
@@ -104,8 +114,53 @@ type (
 	//
 	// This type is auto-generated.
 	UserSet []*User
+
+	// WebhookSet slice of Webhook
+	//
+	// This type is auto-generated.
+	WebhookSet []*Webhook
+
+	// WebhookConstraintSet slice of WebhookConstraint
+	//
+	// This type is auto-generated.
+	WebhookConstraintSet []*WebhookConstraint
+
+	// WebhookDeliverySet slice of WebhookDelivery
+	//
+	// This type is auto-generated.
+	WebhookDeliverySet []*WebhookDelivery
 )
 
+// Walk iterates through every slice item and calls w(ApigwCacheEntry) err
+//
+// This function is auto-generated.
+func (set ApigwCacheEntrySet) Walk(w func(*ApigwCacheEntry) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(ApigwCacheEntry) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set ApigwCacheEntrySet) Filter(f func(*ApigwCacheEntry) (bool, error)) (out ApigwCacheEntrySet, err error) {
+	var ok bool
+	out = ApigwCacheEntrySet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(ApigwFilter) err
 //
 // This function is auto-generated.
@@ -218,6 +273,62 @@ func (set ApigwRouteSet) IDs() (IDs []uint64) {
 	return
 }
 
+// Walk iterates through every slice item and calls w(ApigwSecret) err
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) Walk(w func(*ApigwSecret) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(ApigwSecret) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) Filter(f func(*ApigwSecret) (bool, error)) (out ApigwSecretSet, err error) {
+	var ok bool
+	out = ApigwSecretSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) FindByID(ID uint64) *ApigwSecret {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set ApigwSecretSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
 // Walk iterates through every slice item and calls w(Application) err
 //
 // This function is auto-generated.
@@ -1039,3 +1150,145 @@ func (set UserSet) IDs() (IDs []uint64) {
 
 	return
 }
+
+// Walk iterates through every slice item and calls w(Webhook) err
+//
+// This function is auto-generated.
+func (set WebhookSet) Walk(w func(*Webhook) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(Webhook) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set WebhookSet) Filter(f func(*Webhook) (bool, error)) (out WebhookSet, err error) {
+	var ok bool
+	out = WebhookSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set WebhookSet) FindByID(ID uint64) *Webhook {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set WebhookSet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
+
+// Walk iterates through every slice item and calls w(WebhookConstraint) err
+//
+// This function is auto-generated.
+func (set WebhookConstraintSet) Walk(w func(*WebhookConstraint) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(WebhookConstraint) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set WebhookConstraintSet) Filter(f func(*WebhookConstraint) (bool, error)) (out WebhookConstraintSet, err error) {
+	var ok bool
+	out = WebhookConstraintSet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// Walk iterates through every slice item and calls w(WebhookDelivery) err
+//
+// This function is auto-generated.
+func (set WebhookDeliverySet) Walk(w func(*WebhookDelivery) error) (err error) {
+	for i := range set {
+		if err = w(set[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Filter iterates through every slice item, calls f(WebhookDelivery) (bool, err) and return filtered slice
+//
+// This function is auto-generated.
+func (set WebhookDeliverySet) Filter(f func(*WebhookDelivery) (bool, error)) (out WebhookDeliverySet, err error) {
+	var ok bool
+	out = WebhookDeliverySet{}
+	for i := range set {
+		if ok, err = f(set[i]); err != nil {
+			return
+		} else if ok {
+			out = append(out, set[i])
+		}
+	}
+
+	return
+}
+
+// FindByID finds items from slice by its ID property
+//
+// This function is auto-generated.
+func (set WebhookDeliverySet) FindByID(ID uint64) *WebhookDelivery {
+	for i := range set {
+		if set[i].ID == ID {
+			return set[i]
+		}
+	}
+
+	return nil
+}
+
+// IDs returns a slice of uint64s from all items in the set
+//
+// This function is auto-generated.
+func (set WebhookDeliverySet) IDs() (IDs []uint64) {
+	IDs = make([]uint64, len(set))
+
+	for i := range set {
+		IDs[i] = set[i].ID
+	}
+
+	return
+}
@@ -14,6 +14,62 @@ import (
 	"testing"
 )
 
+func TestApigwCacheEntrySetWalk(t *testing.T) {
+	var (
+		value = make(ApigwCacheEntrySet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*ApigwCacheEntry) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*ApigwCacheEntry) error { return fmt.Errorf("walk error") }))
+}
+
+func TestApigwCacheEntrySetFilter(t *testing.T) {
+	var (
+		value = make(ApigwCacheEntrySet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*ApigwCacheEntry) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*ApigwCacheEntry) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*ApigwCacheEntry) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
 func TestApigwFilterSetWalk(t *testing.T) {
 	var (
 		value = make(ApigwFilterSet, 3)
@@ -194,6 +250,96 @@ func TestApigwRouteSetIDs(t *testing.T) {
 	}
 }
 
+func TestApigwSecretSetWalk(t *testing.T) {
+	var (
+		value = make(ApigwSecretSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*ApigwSecret) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*ApigwSecret) error { return fmt.Errorf("walk error") }))
+}
+
+func TestApigwSecretSetFilter(t *testing.T) {
+	var (
+		value = make(ApigwSecretSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*ApigwSecret) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*ApigwSecret) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*ApigwSecret) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestApigwSecretSetIDs(t *testing.T) {
+	var (
+		value = make(ApigwSecretSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(ApigwSecret)
+	value[1] = new(ApigwSecret)
+	value[2] = new(ApigwSecret)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
 func TestApplicationSetWalk(t *testing.T) {
 	var (
 		value = make(ApplicationSet, 3)
@@ -1553,3 +1699,239 @@ func TestUserSetIDs(t *testing.T) {
 		req.Equal(len(val), len(value))
 	}
 }
+
+func TestWebhookSetWalk(t *testing.T) {
+	var (
+		value = make(WebhookSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*Webhook) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*Webhook) error { return fmt.Errorf("walk error") }))
+}
+
+func TestWebhookSetFilter(t *testing.T) {
+	var (
+		value = make(WebhookSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*Webhook) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*Webhook) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*Webhook) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestWebhookSetIDs(t *testing.T) {
+	var (
+		value = make(WebhookSet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(Webhook)
+	value[1] = new(Webhook)
+	value[2] = new(Webhook)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
+
+func TestWebhookConstraintSetWalk(t *testing.T) {
+	var (
+		value = make(WebhookConstraintSet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*WebhookConstraint) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*WebhookConstraint) error { return fmt.Errorf("walk error") }))
+}
+
+func TestWebhookConstraintSetFilter(t *testing.T) {
+	var (
+		value = make(WebhookConstraintSet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*WebhookConstraint) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*WebhookConstraint) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*WebhookConstraint) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestWebhookDeliverySetWalk(t *testing.T) {
+	var (
+		value = make(WebhookDeliverySet, 3)
+		req   = require.New(t)
+	)
+
+	// check walk with no errors
+	{
+		err := value.Walk(func(*WebhookDelivery) error {
+			return nil
+		})
+		req.NoError(err)
+	}
+
+	// check walk with error
+	req.Error(value.Walk(func(*WebhookDelivery) error { return fmt.Errorf("walk error") }))
+}
+
+func TestWebhookDeliverySetFilter(t *testing.T) {
+	var (
+		value = make(WebhookDeliverySet, 3)
+		req   = require.New(t)
+	)
+
+	// filter nothing
+	{
+		set, err := value.Filter(func(*WebhookDelivery) (bool, error) {
+			return true, nil
+		})
+		req.NoError(err)
+		req.Equal(len(set), len(value))
+	}
+
+	// filter one item
+	{
+		found := false
+		set, err := value.Filter(func(*WebhookDelivery) (bool, error) {
+			if !found {
+				found = true
+				return found, nil
+			}
+			return false, nil
+		})
+		req.NoError(err)
+		req.Len(set, 1)
+	}
+
+	// filter error
+	{
+		_, err := value.Filter(func(*WebhookDelivery) (bool, error) {
+			return false, fmt.Errorf("filter error")
+		})
+		req.Error(err)
+	}
+}
+
+func TestWebhookDeliverySetIDs(t *testing.T) {
+	var (
+		value = make(WebhookDeliverySet, 3)
+		req   = require.New(t)
+	)
+
+	// construct objects
+	value[0] = new(WebhookDelivery)
+	value[1] = new(WebhookDelivery)
+	value[2] = new(WebhookDelivery)
+	// set ids
+	value[0].ID = 1
+	value[1].ID = 2
+	value[2].ID = 3
+
+	// Find existing
+	{
+		val := value.FindByID(2)
+		req.Equal(uint64(2), val.ID)
+	}
+
+	// Find non-existing
+	{
+		val := value.FindByID(4)
+		req.Nil(val)
+	}
+
+	// List IDs from set
+	{
+		val := value.IDs()
+		req.Equal(len(val), len(value))
+	}
+}
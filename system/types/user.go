@@ -56,6 +56,9 @@ type (
 				// Is TOTP configured & enforced?
 				EnforcedTOTP bool `json:"enforcedTOTP"`
 
+				// Are WebAuthn credentials configured & enforced?
+				EnforcedWebAuthn bool `json:"enforcedWebAuthn"`
+
 				// Require OTP to be entered every time client is authorized
 				//StrictTOTP bool `json:"strictTOTP"`
 			} `json:"mfa"`
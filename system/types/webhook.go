@@ -0,0 +1,251 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+)
+
+type (
+	Webhook struct {
+		ID  uint64 `json:"webhookID,string"`
+		URL string `json:"url"`
+
+		// Secret is used for signing the payload (HMAC-SHA256)
+		// and is never exposed
+		Secret string `json:"-"`
+
+		// Resource type that fires the webhook
+		ResourceType string `json:"resourceType"`
+
+		// Event types that fire the webhook
+		EventTypes WebhookEventTypeSet `json:"eventTypes"`
+
+		// Webhook constraints
+		//
+		// Same as workflow triggers, constraints are matched
+		// against the resource that fired the event
+		Constraints WebhookConstraintSet `json:"constraints"`
+
+		Enabled bool        `json:"enabled"`
+		Meta    WebhookMeta `json:"meta"`
+
+		OwnedBy   uint64     `json:"ownedBy,string"`
+		CreatedAt time.Time  `json:"createdAt,omitempty"`
+		CreatedBy uint64     `json:"createdBy,string" `
+		UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+		UpdatedBy uint64     `json:"updatedBy,string,omitempty" `
+		DeletedAt *time.Time `json:"deletedAt,omitempty"`
+		DeletedBy uint64     `json:"deletedBy,string,omitempty" `
+	}
+
+	WebhookEventTypeSet []string
+
+	WebhookConstraint struct {
+		Name   string   `json:"name"`
+		Op     string   `json:"op,omitempty"`
+		Values []string `json:"values,omitempty"`
+	}
+
+	WebhookMeta struct {
+		Description string `json:"description,omitempty"`
+	}
+
+	WebhookFilter struct {
+		WebhookID    []uint64 `json:"webhookID"`
+		ResourceType string   `json:"resourceType"`
+
+		Deleted  filter.State `json:"deleted"`
+		Disabled filter.State `json:"disabled"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*Webhook) (bool, error) `json:"-"`
+
+		filter.Sorting
+		filter.Paging
+	}
+
+	// WebhookDelivery is a log entry for each event that
+	// was (or will be) delivered to the webhook URL
+	WebhookDelivery struct {
+		ID        uint64 `json:"deliveryID,string"`
+		WebhookID uint64 `json:"webhookID,string"`
+
+		ResourceType string `json:"resourceType"`
+		EventType    string `json:"eventType"`
+
+		// Payload as it is sent to the webhook URL
+		Payload WebhookPayload `json:"payload"`
+
+		Status WebhookDeliveryStatus `json:"status"`
+
+		// Number of delivery attempts
+		Attempts uint `json:"attempts"`
+
+		// Status code & error from the last delivery attempt
+		ResponseStatus int    `json:"responseStatus,omitempty"`
+		Error          string `json:"error,omitempty"`
+
+		CreatedAt     time.Time  `json:"createdAt"`
+		LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty"`
+		NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+		DeliveredAt   *time.Time `json:"deliveredAt,omitempty"`
+	}
+
+	// WebhookPayload holds JSON encoded payload
+	WebhookPayload []byte
+
+	WebhookDeliveryStatus string
+
+	WebhookDeliveryFilter struct {
+		WebhookID uint64                `json:"webhookID,string"`
+		Status    WebhookDeliveryStatus `json:"status"`
+
+		// Return only deliveries with next attempt scheduled before given time
+		NextAttemptBefore *time.Time `json:"nextAttemptBefore,omitempty"`
+
+		// Check fn is called by store backend for each resource found function can
+		// modify the resource and return false if store should not return it
+		//
+		// Store then loads additional resources to satisfy the paging parameters
+		Check func(*WebhookDelivery) (bool, error) `json:"-"`
+
+		filter.Sorting
+		filter.Paging
+	}
+)
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// HasEventType returns true if event type is one of webhook's event types
+func (w Webhook) HasEventType(eventType string) bool {
+	for _, et := range w.EventTypes {
+		if et == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ParseWebhookEventTypeSet(ss []string) (p WebhookEventTypeSet, err error) {
+	p = WebhookEventTypeSet{}
+	return p, parseWebhookInput(ss, &p)
+}
+
+func ParseWebhookConstraintSet(ss []string) (p WebhookConstraintSet, err error) {
+	p = WebhookConstraintSet{}
+	return p, parseWebhookInput(ss, &p)
+}
+
+func ParseWebhookMeta(ss []string) (p WebhookMeta, err error) {
+	p = WebhookMeta{}
+	return p, parseWebhookInput(ss, &p)
+}
+
+func parseWebhookInput(ss []string, p interface{}) (err error) {
+	if len(ss) == 0 {
+		return
+	}
+
+	return json.Unmarshal([]byte(ss[0]), p)
+}
+
+func (vv *WebhookEventTypeSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*vv = WebhookEventTypeSet{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, vv); err != nil {
+			return fmt.Errorf("cannot scan '%v' into WebhookEventTypeSet: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (vv WebhookEventTypeSet) Value() (driver.Value, error) {
+	return json.Marshal(vv)
+}
+
+func (vv *WebhookConstraintSet) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*vv = WebhookConstraintSet{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, vv); err != nil {
+			return fmt.Errorf("cannot scan '%v' into WebhookConstraintSet: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (vv WebhookConstraintSet) Value() (driver.Value, error) {
+	return json.Marshal(vv)
+}
+
+func (vv *WebhookMeta) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*vv = WebhookMeta{}
+	case []uint8:
+		b := value.([]byte)
+		if err := json.Unmarshal(b, vv); err != nil {
+			return fmt.Errorf("cannot scan '%v' into WebhookMeta: %w", string(b), err)
+		}
+	}
+
+	return nil
+}
+
+func (vv WebhookMeta) Value() (driver.Value, error) {
+	return json.Marshal(vv)
+}
+
+func (p *WebhookPayload) Scan(value interface{}) error {
+	//lint:ignore S1034 This typecast is intentional, we need to get []byte out of a []uint8
+	switch value.(type) {
+	case nil:
+		*p = nil
+	case []uint8:
+		*p = append(WebhookPayload{}, value.([]byte)...)
+	case string:
+		*p = WebhookPayload(value.(string))
+	}
+
+	return nil
+}
+
+func (p WebhookPayload) Value() (driver.Value, error) {
+	return []byte(p), nil
+}
+
+// MarshalJSON outputs payload as-is
+func (p WebhookPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+
+	return p, nil
+}
+
+func (p *WebhookPayload) UnmarshalJSON(b []byte) error {
+	*p = append(WebhookPayload{}, b...)
+	return nil
+}
@@ -0,0 +1,50 @@
+package apigw
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func Test_filter_def_prefilters(t *testing.T) {
+	var (
+		h = newHelper(t)
+	)
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-routes.search")
+
+	h.apiInit().
+		Get("/apigw/filter/def").
+		Query("kind", "prefilter").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response`, 6)).
+		Assert(jsonpath.Contains(`$.response[*].name`, "rateLimit")).
+		Assert(jsonpath.Contains(`$.response[*].name`, "cors")).
+		Assert(jsonpath.Contains(`$.response[*].name`, "ipFilter")).
+		Assert(jsonpath.Contains(`$.response[*].name`, "auth")).
+		End()
+}
+
+func Test_filter_def_postfilters(t *testing.T) {
+	var (
+		h = newHelper(t)
+	)
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-routes.search")
+
+	h.apiInit().
+		Get("/apigw/filter/def").
+		Query("kind", "postfilter").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Contains(`$.response[*].name`, "responseCache")).
+		End()
+}
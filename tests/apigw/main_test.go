@@ -23,7 +23,6 @@ import (
 	"github.com/cortezaproject/corteza-server/pkg/label"
 	ltype "github.com/cortezaproject/corteza-server/pkg/label/types"
 	"github.com/cortezaproject/corteza-server/pkg/logger"
-	"github.com/cortezaproject/corteza-server/pkg/options"
 	"github.com/cortezaproject/corteza-server/store"
 	"github.com/cortezaproject/corteza-server/store/sqlite3"
 	"github.com/cortezaproject/corteza-server/system/rest"
@@ -75,14 +74,17 @@ func InitTestApp() {
 
 	if r == nil {
 		r = chi.NewRouter()
+		server.Passthrough("/")
 		r.Use(server.BaseMiddleware(false, logger.Default())...)
-		helpers.BindAuthMiddleware(r)
 
 		// Sys routes for route management tests
-		rest.MountRoutes(r)
+		r.Group(func(r chi.Router) {
+			helpers.BindAuthMiddleware(r)
+			rest.MountRoutes(r)
+		})
 
 		// API gw routes
-		apigw.Setup(options.Apigw(), service.DefaultLogger, service.DefaultStore)
+		apigw.Setup(&testApp.Opt.Apigw, service.DefaultLogger, service.DefaultStore)
 		err := apigw.Service().Reload(ctx)
 		if err != nil {
 			panic(err)
@@ -0,0 +1,151 @@
+package apigw
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	"github.com/steinfletcher/apitest"
+)
+
+func Test_postfilter_cache(t *testing.T) {
+	for _, storage := range []string{"memory", "store"} {
+		t.Run(storage, func(t *testing.T) {
+			var (
+				ctx, h, s = setup(t)
+
+				hits int32
+
+				srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(fmt.Sprintf("hit %d", atomic.AddInt32(&hits, 1))))
+				}))
+
+				route = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/cached-" + storage, Method: "GET", Enabled: true, CreatedAt: time.Now()}
+			)
+
+			defer srv.Close()
+
+			cleanup(ctx, h, s)
+			h.noError(s.TruncateApigwCacheEntries(ctx))
+
+			h.noError(s.CreateApigwRoute(ctx, route))
+			h.noError(s.CreateApigwFilter(ctx,
+				&sysTypes.ApigwFilter{
+					ID:        id.Next(),
+					Route:     route.ID,
+					Ref:       "proxy",
+					Kind:      "processer",
+					Enabled:   true,
+					CreatedAt: time.Now(),
+					Params:    sysTypes.ApigwFilterParams{"location": srv.URL},
+				},
+				&sysTypes.ApigwFilter{
+					ID:        id.Next(),
+					Route:     route.ID,
+					Ref:       "responseCache",
+					Kind:      "postfilter",
+					Enabled:   true,
+					CreatedAt: time.Now(),
+					Params:    sysTypes.ApigwFilterParams{"ttl": "1h", "storage": storage, "query": []string{"page"}},
+				},
+			))
+
+			h.noError(apigw.Service().Reload(ctx))
+
+			// gateway requests are anonymous; route does not authenticate them
+			anonymous := func() *apitest.APITest {
+				InitTestApp()
+				return apitest.New().Handler(r)
+			}
+
+			rsp := anonymous().
+				Get(route.Endpoint).
+				Expect(t).
+				Status(http.StatusOK).
+				Header("X-Cache", "MISS").
+				Body("hit 1").
+				End()
+
+			etag := rsp.Response.Header.Get("ETag")
+			h.a.NotEmpty(etag)
+
+			anonymous().
+				Get(route.Endpoint).
+				Query("foo", "ignored").
+				Expect(t).
+				Status(http.StatusOK).
+				Header("X-Cache", "HIT").
+				Body("hit 1").
+				End()
+
+			anonymous().
+				Get(route.Endpoint).
+				Header("If-None-Match", etag).
+				Expect(t).
+				Status(http.StatusNotModified).
+				End()
+
+			anonymous().
+				Get(route.Endpoint).
+				Query("page", "2").
+				Expect(t).
+				Status(http.StatusOK).
+				Header("X-Cache", "MISS").
+				Body("hit 2").
+				End()
+
+			// credentials that are not resolved by the route bypass the cache
+			h.apiInit().
+				Get(route.Endpoint).
+				Expect(t).
+				Status(http.StatusOK).
+				HeaderNotPresent("X-Cache").
+				Body("hit 3").
+				End()
+
+			helpers.AllowMe(h, route.RbacResource(), "update")
+
+			h.apiInit().
+				Post(fmt.Sprintf("/apigw/route/%d/cache/purge", route.ID)).
+				Header("Accept", "application/json").
+				Expect(t).
+				Status(http.StatusOK).
+				Assert(helpers.AssertNoErrors).
+				End()
+
+			anonymous().
+				Get(route.Endpoint).
+				Expect(t).
+				Status(http.StatusOK).
+				Header("X-Cache", "MISS").
+				Body("hit 4").
+				End()
+		})
+	}
+}
+
+func Test_postfilter_cache_purge_forbidden(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+
+		route = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/cached", Method: "GET", Enabled: true, CreatedAt: time.Now()}
+	)
+
+	cleanup(ctx, h, s)
+	h.noError(s.CreateApigwRoute(ctx, route))
+
+	h.apiInit().
+		Post(fmt.Sprintf("/apigw/route/%d/cache/purge", route.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("apigwRoute.errors.notAllowedToPurgeCache")).
+		End()
+}
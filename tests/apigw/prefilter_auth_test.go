@@ -0,0 +1,130 @@
+package apigw
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/steinfletcher/apitest"
+	"gopkg.in/square/go-jose.v2"
+)
+
+func Test_prefilter_auth_corteza(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+
+		route = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/auth-corteza", Method: "GET", Enabled: true, CreatedAt: time.Now()}
+	)
+
+	cleanup(ctx, h, s)
+
+	h.noError(s.CreateApigwRoute(ctx, route))
+	h.noError(s.CreateApigwFilter(ctx, authTestFilter(route.ID, sysTypes.ApigwFilterParams{"scope": "api"}), authTestPayload(route.ID)))
+	h.noError(apigw.Service().Reload(ctx))
+
+	// token of the current user is added by the helper
+	h.apiInit().
+		Get("/auth-corteza").
+		Expect(t).
+		Status(http.StatusOK).
+		Body("42").
+		End()
+
+	apitest.New().
+		Handler(r).
+		Get("/auth-corteza").
+		Expect(t).
+		Status(http.StatusUnauthorized).
+		Header("WWW-Authenticate", `Bearer realm="corteza"`).
+		End()
+}
+
+func Test_prefilter_auth_jwks(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+
+		key, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: "k1", Algorithm: "RS256", Use: "sig"},
+			}})
+		}))
+
+		user  = &sysTypes.User{ID: id.Next(), Email: "auth-jwks@example.com", CreatedAt: time.Now()}
+		route = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/auth-jwks", Method: "GET", Enabled: true, CreatedAt: time.Now()}
+
+		sign = func(email string) string {
+			tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+				"exp":   time.Now().Add(time.Hour).Unix(),
+				"email": email,
+			})
+
+			tkn.Header["kid"] = "k1"
+			str, err := tkn.SignedString(key)
+			h.noError(err)
+			return str
+		}
+	)
+
+	defer srv.Close()
+
+	cleanup(ctx, h, s)
+	h.noError(store.DeleteUserByID(context.Background(), s, user.ID))
+	h.noError(s.CreateUser(ctx, user))
+
+	h.noError(s.CreateApigwRoute(ctx, route))
+	h.noError(s.CreateApigwFilter(ctx, authTestFilter(route.ID, sysTypes.ApigwFilterParams{"type": "jwks", "jwksUrl": srv.URL}), authTestPayload(route.ID)))
+	h.noError(apigw.Service().Reload(ctx))
+
+	apitest.New().
+		Handler(r).
+		Get("/auth-jwks").
+		Header("Authorization", "Bearer "+sign(user.Email)).
+		Expect(t).
+		Status(http.StatusOK).
+		Body("42").
+		End()
+
+	apitest.New().
+		Handler(r).
+		Get("/auth-jwks").
+		Header("Authorization", "Bearer "+sign("unknown@example.com")).
+		Expect(t).
+		Status(http.StatusForbidden).
+		End()
+}
+
+func authTestFilter(routeID uint64, params sysTypes.ApigwFilterParams) *sysTypes.ApigwFilter {
+	return &sysTypes.ApigwFilter{
+		ID:        id.Next(),
+		Route:     routeID,
+		Ref:       "auth",
+		Kind:      "prefilter",
+		Enabled:   true,
+		CreatedAt: time.Now(),
+		Params:    params,
+	}
+}
+
+func authTestPayload(routeID uint64) *sysTypes.ApigwFilter {
+	return &sysTypes.ApigwFilter{
+		ID:        id.Next(),
+		Route:     routeID,
+		Ref:       "payload",
+		Kind:      "processer",
+		Enabled:   true,
+		CreatedAt: time.Now(),
+		Params:    sysTypes.ApigwFilterParams{"jsfunc": `return 42`},
+	}
+}
@@ -0,0 +1,70 @@
+package apigw
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
+)
+
+func Test_prefilter_cors(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+
+		route = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/cors", Method: "POST", Enabled: true, CreatedAt: time.Now()}
+	)
+
+	cleanup(ctx, h, s)
+
+	h.noError(s.CreateApigwRoute(ctx, route))
+	h.noError(s.CreateApigwFilter(ctx, &sysTypes.ApigwFilter{
+		ID:        id.Next(),
+		Route:     route.ID,
+		Ref:       "cors",
+		Kind:      "prefilter",
+		Enabled:   true,
+		CreatedAt: time.Now(),
+		Params: sysTypes.ApigwFilterParams{
+			"allowedOrigins": []string{"https://app.example.com"},
+			"allowedMethods": []string{"POST"},
+			"maxAge":         600,
+		},
+	}))
+
+	h.noError(apigw.Service().Reload(ctx))
+
+	h.apiInit().
+		Method(http.MethodOptions).
+		URL("/cors").
+		Header("Origin", "https://app.example.com").
+		Header("Access-Control-Request-Method", "POST").
+		Expect(t).
+		Status(http.StatusNoContent).
+		Header("Access-Control-Allow-Origin", "https://app.example.com").
+		Header("Access-Control-Max-Age", "600").
+		End()
+
+	h.apiInit().
+		Method(http.MethodOptions).
+		URL("/cors").
+		Header("Origin", "https://other.com").
+		Header("Access-Control-Request-Method", "POST").
+		Expect(t).
+		Status(http.StatusNoContent).
+		HeaderNotPresent("Access-Control-Allow-Origin").
+		End()
+
+	// routes without CORS prefilter use default rules
+	h.apiInit().
+		Method(http.MethodOptions).
+		URL("/no-cors").
+		Header("Origin", "https://other.com").
+		Header("Access-Control-Request-Method", "GET").
+		Expect(t).
+		Status(http.StatusOK).
+		Header("Access-Control-Allow-Origin", "https://other.com").
+		End()
+}
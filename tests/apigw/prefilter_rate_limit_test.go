@@ -0,0 +1,61 @@
+package apigw
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
+)
+
+func Test_prefilter_rate_limit(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+
+		route = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/rate-limit", Method: "GET", Enabled: true, CreatedAt: time.Now()}
+	)
+
+	cleanup(ctx, h, s)
+
+	h.noError(s.CreateApigwRoute(ctx, route))
+	h.noError(s.CreateApigwFilter(ctx, &sysTypes.ApigwFilter{
+		ID:        id.Next(),
+		Route:     route.ID,
+		Ref:       "rateLimit",
+		Kind:      "prefilter",
+		Enabled:   true,
+		CreatedAt: time.Now(),
+		Params: sysTypes.ApigwFilterParams{
+			"rate":   1,
+			"period": "1h",
+			"key":    "header",
+			"header": "X-Api-Key",
+		},
+	}))
+
+	h.noError(apigw.Service().Reload(ctx))
+
+	h.apiInit().
+		Get("/rate-limit").
+		Header("X-Api-Key", "foo").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	h.apiInit().
+		Get("/rate-limit").
+		Header("X-Api-Key", "foo").
+		Expect(t).
+		Status(http.StatusTooManyRequests).
+		Header("Retry-After", "3600").
+		End()
+
+	h.apiInit().
+		Get("/rate-limit").
+		Header("X-Api-Key", "bar").
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+}
@@ -0,0 +1,67 @@
+package apigw
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw"
+	"github.com/cortezaproject/corteza-server/pkg/apigw/secure"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	sysTypes "github.com/cortezaproject/corteza-server/system/types"
+)
+
+func Test_processor_proxy_secret(t *testing.T) {
+	var (
+		ctx, h, s = setup(t)
+
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if u, p, ok := r.BasicAuth(); !ok || u != "user" || p != "s3cr3t" {
+				_, _ = w.Write([]byte("unauthorized"))
+				return
+			}
+
+			_, _ = w.Write([]byte("authorized"))
+		}))
+
+		secret = &sysTypes.ApigwSecret{ID: id.Next(), Handle: "proxy-pass", Value: "s3cr3t", CreatedAt: time.Now()}
+		route  = &sysTypes.ApigwRoute{ID: id.Next(), Endpoint: "/proxy-secret", Method: "GET", Enabled: true, CreatedAt: time.Now()}
+	)
+
+	defer srv.Close()
+
+	cleanup(ctx, h, s)
+	h.noError(s.TruncateApigwSecrets(ctx))
+
+	h.noError(secure.NewKeyring(testApp.Opt.Apigw.SecretsKey, "").SealSecret(secret))
+	h.noError(s.CreateApigwSecret(ctx, secret))
+	h.noError(s.CreateApigwRoute(ctx, route))
+	h.noError(s.CreateApigwFilter(ctx, &sysTypes.ApigwFilter{
+		ID:        id.Next(),
+		Route:     route.ID,
+		Ref:       "proxy",
+		Kind:      "processer",
+		Enabled:   true,
+		CreatedAt: time.Now(),
+		Params: sysTypes.ApigwFilterParams{
+			"location": srv.URL,
+			"auth": map[string]interface{}{
+				"type": "basic",
+				"params": map[string]interface{}{
+					"username": "user",
+					"password": map[string]interface{}{"secret": "proxy-pass"},
+				},
+			},
+		},
+	}))
+
+	h.noError(apigw.Service().Reload(ctx))
+
+	h.apiInit().
+		Get("/proxy-secret").
+		Expect(t).
+		Status(http.StatusOK).
+		Body("authorized").
+		End()
+}
@@ -0,0 +1,182 @@
+package compose
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+// makes namespace with author & book modules where book references author
+func (h helper) makeGraphQLModules() (*types.Namespace, *types.Module, *types.Module) {
+	ns := h.makeNamespace("graphql testing namespace")
+
+	author := h.createModule(&types.Module{
+		Handle:      "author",
+		Name:        "Author",
+		NamespaceID: ns.ID,
+		Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "name", Kind: "String"},
+			&types.ModuleField{Name: "active", Kind: "Bool"},
+		},
+	})
+
+	book := h.createModule(&types.Module{
+		Handle:      "book",
+		Name:        "Book",
+		NamespaceID: ns.ID,
+		Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "title", Kind: "String"},
+			&types.ModuleField{Name: "pages", Kind: "Number"},
+			&types.ModuleField{Name: "tags", Kind: "String", Multi: true},
+			&types.ModuleField{Name: "author", Kind: "Record", Options: types.ModuleFieldOptions{"moduleID": fmt.Sprintf("%d", author.ID)}},
+		},
+	})
+
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read", "modules.search")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "read", "records.search")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read", "record.value.update")
+
+	return ns, author, book
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	ns, author, book := h.makeGraphQLModules()
+
+	a := h.makeRecord(author,
+		&types.RecordValue{Name: "name", Value: "Jane"},
+		&types.RecordValue{Name: "active", Value: "1"},
+	)
+
+	h.makeRecord(book,
+		&types.RecordValue{Name: "title", Value: "First"},
+		&types.RecordValue{Name: "pages", Value: "120"},
+		&types.RecordValue{Name: "tags", Value: "a", Place: 0},
+		&types.RecordValue{Name: "tags", Value: "b", Place: 1},
+		&types.RecordValue{Name: "author", Value: fmt.Sprintf("%d", a.ID)},
+	)
+
+	h.makeRecord(book,
+		&types.RecordValue{Name: "title", Value: "Second"},
+		&types.RecordValue{Name: "author", Value: fmt.Sprintf("%d", a.ID)},
+	)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(`{"query": "{ bookList(sort: \"title\") { records { title pages tags author { name active } } } }"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.errors`)).
+		Assert(jsonpath.Len(`$.data.bookList.records`, 2)).
+		Assert(jsonpath.Equal(`$.data.bookList.records[0].title`, "First")).
+		Assert(jsonpath.Equal(`$.data.bookList.records[0].pages`, float64(120))).
+		Assert(jsonpath.Equal(`$.data.bookList.records[0].tags`, []interface{}{"a", "b"})).
+		Assert(jsonpath.Equal(`$.data.bookList.records[0].author.name`, "Jane")).
+		Assert(jsonpath.Equal(`$.data.bookList.records[0].author.active`, true)).
+		Assert(jsonpath.Equal(`$.data.bookList.records[1].author.name`, "Jane")).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(fmt.Sprintf(`{"query": "query ($id: ID!) { author(recordID: $id) { recordID name } }", "variables": {"id": "%d"}}`, a.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.errors`)).
+		Assert(jsonpath.Equal(`$.data.author.recordID`, fmt.Sprintf("%d", a.ID))).
+		Assert(jsonpath.Equal(`$.data.author.name`, "Jane")).
+		End()
+}
+
+func TestGraphQLQueryFieldPermissions(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	ns, author, _ := h.makeGraphQLModules()
+	helpers.DenyMe(h, types.ModuleFieldRbacResource(0, 0, author.Fields[0].ID), "record.value.read")
+
+	h.makeRecord(author,
+		&types.RecordValue{Name: "name", Value: "Jane"},
+		&types.RecordValue{Name: "active", Value: "1"},
+	)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(`{"query": "{ authorList { records { name active } } }"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.errors`)).
+		Assert(jsonpath.Len(`$.data.authorList.records`, 1)).
+		Assert(jsonpath.Equal(`$.data.authorList.records[0].name`, nil)).
+		Assert(jsonpath.Equal(`$.data.authorList.records[0].active`, true)).
+		End()
+}
+
+func TestGraphQLQueryForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	ns, _, _ := h.makeGraphQLModules()
+	helpers.DenyMe(h, types.ModuleRbacResource(0, 0), "records.search")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(`{"query": "{ authorList { records { name } } }"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Len(`$.errors`, 1)).
+		Assert(jsonpath.Contains(`$.errors[0].message`, "not allowed to search")).
+		End()
+}
+
+func TestGraphQLMutations(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	ns, author, _ := h.makeGraphQLModules()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update", "delete")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(`{"query": "mutation { createAuthor(values: {name: \"Jane\", active: true}) { name active } }"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.errors`)).
+		Assert(jsonpath.Equal(`$.data.createAuthor.name`, "Jane")).
+		Assert(jsonpath.Equal(`$.data.createAuthor.active`, true)).
+		End()
+
+	a := h.makeRecord(author,
+		&types.RecordValue{Name: "name", Value: "John"},
+		&types.RecordValue{Name: "active", Value: "1"},
+	)
+
+	// fields that are not part of the input are left unchanged
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(fmt.Sprintf(`{"query": "mutation { updateAuthor(recordID: \"%d\", values: {name: \"Johnny\"}) { name active } }"}`, a.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.errors`)).
+		Assert(jsonpath.Equal(`$.data.updateAuthor.name`, "Johnny")).
+		Assert(jsonpath.Equal(`$.data.updateAuthor.active`, true)).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/graphql", ns.ID)).
+		JSON(fmt.Sprintf(`{"query": "mutation { deleteAuthor(recordID: \"%d\") }"}`, a.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.NotPresent(`$.errors`)).
+		Assert(jsonpath.Equal(`$.data.deleteAuthor`, true)).
+		End()
+
+	h.a.NotNil(h.lookupRecordByID(author, a.ID).DeletedAt)
+}
@@ -209,3 +209,68 @@ func TestRecordExecOrganize(t *testing.T) {
 	h.a.Len(lRec.Values.FilterByName("category"), 1)
 	h.a.Equal("CAT2", lRec.Values.FilterByName("category")[0].Value)
 }
+
+func TestRecordExecOrganize_revisions(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.noError(store.TruncateComposeRecordRevisions(context.Background(), service.DefaultStore))
+
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update")
+
+	module := h.repoMakeRecordModuleWithFields(
+		"record testing module",
+		&types.ModuleField{Name: "position", Kind: "Number"},
+		&types.ModuleField{Name: "category"},
+	)
+
+	module.Config.RecordRevisions.Enabled = true
+	h.noError(store.UpdateComposeModule(context.Background(), service.DefaultStore, module))
+
+	var (
+		aRec = h.makeRecord(module,
+			&types.RecordValue{Name: "position", Value: "1"},
+			&types.RecordValue{Name: "category", Value: "CAT1"},
+		)
+
+		bRec = h.makeRecord(module,
+			&types.RecordValue{Name: "position", Value: "2"},
+			&types.RecordValue{Name: "category", Value: "CAT1"},
+		)
+
+		revisions = func(recordID uint64) types.RecordRevisionSet {
+			rr, _, err := store.SearchComposeRecordRevisions(context.Background(), service.DefaultStore, types.RecordRevisionFilter{RecordID: recordID})
+			h.noError(err)
+			return rr
+		}
+	)
+
+	h.apiSendRecordExec(module.NamespaceID, module.ID, "organize", request.ProcedureArgs{
+		{Name: "recordID", Value: strconv.FormatUint(bRec.ID, 10)},
+		{Name: "groupField", Value: "category"},
+		{Name: "group", Value: "CAT2"}}).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	rr := revisions(bRec.ID)
+	h.a.Len(rr, 1)
+	h.a.Equal(types.RecordRevisionOperationUpdate, rr[0].Operation)
+	h.a.Len(rr[0].Changes, 1)
+	h.a.Equal("category", rr[0].Changes[0].Name)
+	h.a.Equal([]string{"CAT1"}, rr[0].Changes[0].Old)
+	h.a.Equal([]string{"CAT2"}, rr[0].Changes[0].New)
+
+	// records placed after the moved one are renumbered
+	h.apiSendRecordExec(module.NamespaceID, module.ID, "organize", request.ProcedureArgs{
+		{Name: "recordID", Value: strconv.FormatUint(bRec.ID, 10)},
+		{Name: "positionField", Value: "position"},
+		{Name: "position", Value: "1"}}).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	rr = revisions(aRec.ID)
+	h.a.NotEmpty(rr)
+	h.a.Equal("position", rr[0].Changes[0].Name)
+	h.a.Equal([]string{"1"}, rr[0].Changes[0].Old)
+}
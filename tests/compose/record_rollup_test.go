@@ -0,0 +1,237 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+)
+
+// makes invoice module with total & count rollups over line module
+func (h helper) makeRollupModules() (invoice, line *types.Module) {
+	ns := h.makeNamespace("rollup testing namespace")
+
+	line = h.createModule(&types.Module{
+		Handle:      "line",
+		NamespaceID: ns.ID,
+		Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "invoice", Kind: "Record"},
+			&types.ModuleField{Name: "amount", Kind: "Number", Options: types.ModuleFieldOptions{"precision": 2}},
+			&types.ModuleField{Name: "kind", Kind: "String"},
+		},
+	})
+
+	invoice = h.createModule(&types.Module{
+		Handle:      "invoice",
+		NamespaceID: ns.ID,
+		Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "total", Kind: "Number", Expressions: types.ModuleFieldExpr{
+				Rollup: &types.ModuleFieldRollup{ModuleID: line.ID, RelationField: "invoice", ValueField: "amount", Aggregate: types.RollupSum},
+			}},
+			&types.ModuleField{Name: "items", Kind: "Number", Expressions: types.ModuleFieldExpr{
+				Rollup: &types.ModuleFieldRollup{ModuleID: line.ID, RelationField: "invoice", Aggregate: types.RollupCount, Filter: "kind = 'item'"},
+			}},
+		},
+	})
+
+	line.Fields[0].Options = types.ModuleFieldOptions{"moduleID": fmt.Sprintf("%d", invoice.ID)}
+	h.noError(store.UpdateComposeModuleField(context.Background(), service.DefaultStore, line.Fields[0]))
+
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "read", "record.create", "record.undelete")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update", "delete")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read", "record.value.update")
+
+	return
+}
+
+func (h helper) createRollupLine(line *types.Module, invoiceID uint64, amount, kind string) uint64 {
+	rec, err := service.DefaultRecord.Create(h.secCtx(), &types.Record{
+		NamespaceID: line.NamespaceID,
+		ModuleID:    line.ID,
+		Values: types.RecordValueSet{
+			{Name: "invoice", Value: fmt.Sprintf("%d", invoiceID)},
+			{Name: "amount", Value: amount},
+			{Name: "kind", Value: kind},
+		},
+	})
+
+	h.noError(err)
+	return rec.ID
+}
+
+func (h helper) assertRollupValue(m *types.Module, recordID uint64, field, expected string) {
+	rec := h.lookupRecordByID(m, recordID)
+	v := rec.Values.Get(field, 0)
+	if expected == "" {
+		h.a.True(v == nil || v.IsDeleted(), "expecting no value for %s", field)
+		return
+	}
+
+	h.a.NotNil(v, "expecting value for %s", field)
+	h.a.Equal(expected, v.Value, field)
+}
+
+func TestRecordRollup(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		ctx           = h.secCtx()
+		invoice, line = h.makeRollupModules()
+	)
+
+	// rollup values sent by the client are ignored
+	inv, err := service.DefaultRecord.Create(ctx, &types.Record{
+		NamespaceID: invoice.NamespaceID,
+		ModuleID:    invoice.ID,
+		Values:      types.RecordValueSet{{Name: "total", Value: "42"}},
+	})
+	h.noError(err)
+	h.assertRollupValue(invoice, inv.ID, "total", "0")
+	h.assertRollupValue(invoice, inv.ID, "items", "0")
+
+	l1 := h.createRollupLine(line, inv.ID, "10", "item")
+	h.createRollupLine(line, inv.ID, "2.5", "item")
+	h.createRollupLine(line, inv.ID, "5", "shipping")
+
+	h.assertRollupValue(invoice, inv.ID, "total", "17.5")
+	h.assertRollupValue(invoice, inv.ID, "items", "2")
+
+	// update
+	rec := h.lookupRecordByID(line, l1)
+	rec.Values = rec.Values.Replace("amount", "20")
+	_, err = service.DefaultRecord.Update(ctx, rec)
+	h.noError(err)
+	h.assertRollupValue(invoice, inv.ID, "total", "27.5")
+
+	// move line to another invoice
+	inv2, err := service.DefaultRecord.Create(ctx, &types.Record{NamespaceID: invoice.NamespaceID, ModuleID: invoice.ID})
+	h.noError(err)
+
+	rec = h.lookupRecordByID(line, l1)
+	rec.Values = rec.Values.Replace("invoice", fmt.Sprintf("%d", inv2.ID))
+	_, err = service.DefaultRecord.Update(ctx, rec)
+	h.noError(err)
+	h.assertRollupValue(invoice, inv.ID, "total", "7.5")
+	h.assertRollupValue(invoice, inv.ID, "items", "1")
+	h.assertRollupValue(invoice, inv2.ID, "total", "20")
+	h.assertRollupValue(invoice, inv2.ID, "items", "1")
+
+	// delete & undelete
+	h.noError(service.DefaultRecord.DeleteByID(ctx, line.NamespaceID, line.ID, l1))
+	h.assertRollupValue(invoice, inv2.ID, "total", "0")
+	h.assertRollupValue(invoice, inv2.ID, "items", "0")
+
+	h.noError(service.DefaultRecord.UndeleteByID(ctx, line.NamespaceID, line.ID, l1))
+	h.assertRollupValue(invoice, inv2.ID, "total", "20")
+}
+
+func TestRecordRollupRecompute(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		invoice, line = h.makeRollupModules()
+		inv           = h.makeRecord(invoice)
+	)
+
+	// records stored directly do not trigger rollup update
+	h.makeRecord(line,
+		&types.RecordValue{Name: "invoice", Value: fmt.Sprintf("%d", inv.ID), Ref: inv.ID},
+		&types.RecordValue{Name: "amount", Value: "3"},
+		&types.RecordValue{Name: "kind", Value: "item"},
+	)
+
+	h.assertRollupValue(invoice, inv.ID, "total", "")
+
+	n, err := service.DefaultRecord.RecomputeRollups(context.Background(), invoice.NamespaceID, invoice.ID)
+	h.noError(err)
+	h.a.Equal(uint(1), n)
+	h.assertRollupValue(invoice, inv.ID, "total", "3")
+	h.assertRollupValue(invoice, inv.ID, "items", "1")
+}
+
+func TestRecordRollupPropagation(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.noError(store.TruncateComposeRecordRevisions(context.Background(), service.DefaultStore))
+
+	var (
+		ctx           = h.secCtx()
+		invoice, line = h.makeRollupModules()
+	)
+
+	invoice.Config.RecordRevisions.Enabled = true
+	invoice.Fields = append(invoice.Fields, &types.ModuleField{
+		ID:          id.Next(),
+		ModuleID:    invoice.ID,
+		NamespaceID: invoice.NamespaceID,
+		Name:        "customer",
+		Kind:        "Record",
+	})
+	h.noError(store.UpdateComposeModule(context.Background(), service.DefaultStore, invoice))
+	h.noError(store.CreateComposeModuleField(context.Background(), service.DefaultStore, invoice.Fields[2]))
+
+	// customer revenue is a rollup of invoice totals that are rollups of lines
+	customer := h.createModule(&types.Module{
+		Handle:      "customer",
+		NamespaceID: invoice.NamespaceID,
+		Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "revenue", Kind: "Number", Expressions: types.ModuleFieldExpr{
+				Rollup: &types.ModuleFieldRollup{ModuleID: invoice.ID, RelationField: "customer", ValueField: "total", Aggregate: types.RollupSum},
+			}},
+		},
+	})
+
+	cst, err := service.DefaultRecord.Create(ctx, &types.Record{NamespaceID: customer.NamespaceID, ModuleID: customer.ID})
+	h.noError(err)
+
+	inv, err := service.DefaultRecord.Create(ctx, &types.Record{
+		NamespaceID: invoice.NamespaceID,
+		ModuleID:    invoice.ID,
+		Values:      types.RecordValueSet{{Name: "customer", Value: fmt.Sprintf("%d", cst.ID)}},
+	})
+	h.noError(err)
+
+	h.createRollupLine(line, inv.ID, "10", "item")
+	h.createRollupLine(line, inv.ID, "5", "item")
+
+	h.assertRollupValue(invoice, inv.ID, "total", "15")
+	h.assertRollupValue(customer, cst.ID, "revenue", "15")
+
+	// rollup change updates the record and stores a revision
+	rec := h.lookupRecordByID(invoice, inv.ID)
+	h.a.NotNil(rec.UpdatedAt)
+
+	rr, _, err := store.SearchComposeRecordRevisions(context.Background(), service.DefaultStore, types.RecordRevisionFilter{RecordID: inv.ID})
+	h.noError(err)
+	h.a.Len(rr, 3)
+	h.a.Equal(types.RecordRevisionOperationUpdate, rr[2].Operation)
+	h.a.Equal("total", rr[2].Changes[0].Name)
+}
+
+func TestModuleCreateWithInvalidRollup(t *testing.T) {
+	h := newHelper(t)
+	h.clearModules()
+
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read", "module.create")
+	ns := h.makeNamespace("some-namespace")
+	rel := h.makeModule(ns, "related", &types.ModuleField{Name: "title", Kind: "String"})
+
+	fjs := fmt.Sprintf(`{ "name": "foo", "fields": [{ "name": "total", "kind": "Number", "expressions": { "rollup": { "moduleID": "%d", "relationField": "title", "aggregate": "sum" } } }]}`, rel.ID)
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/", ns.ID)).
+		Header("Accept", "application/json").
+		JSON(fjs).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("module.errors.invalidRollup")).
+		End()
+}
@@ -3,6 +3,7 @@ package compose
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"mime/multipart"
@@ -16,6 +17,9 @@ import (
 	"github.com/cortezaproject/corteza-server/compose/service"
 	"github.com/cortezaproject/corteza-server/compose/service/values"
 	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/resource"
+	"github.com/cortezaproject/corteza-server/pkg/envoy/spreadsheet"
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/id"
 	"github.com/cortezaproject/corteza-server/store"
 	systemService "github.com/cortezaproject/corteza-server/system/service"
@@ -765,6 +769,266 @@ func TestRecordDelete(t *testing.T) {
 	h.a.NotNil(r.DeletedAt)
 }
 
+func TestRecordRevisions(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.noError(store.TruncateComposeRecordRevisions(context.Background(), service.DefaultStore))
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	module.Config.RecordRevisions.Enabled = true
+	h.noError(store.UpdateComposeModule(context.Background(), service.DefaultStore, module))
+
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+
+	rsp := struct {
+		Response *types.Record `json:"response"`
+	}{}
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", module.NamespaceID, module.ID)).
+		JSON(`{"values": [{"name": "name", "value": "v1"}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End().
+		JSON(&rsp)
+
+	h.a.NotNil(rsp.Response)
+	recordID := rsp.Response.ID
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, recordID)).
+		JSON(`{"values": [{"name": "name", "value": "v2"}]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions", module.NamespaceID, module.ID, recordID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response`, 2)).
+		Assert(jsonpath.Equal(`$.response[1].operation`, "update")).
+		Assert(jsonpath.Equal(`$.response[1].changes[0].old[0]`, "v1")).
+		Assert(jsonpath.Equal(`$.response[1].changes[0].new[0]`, "v2")).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/1/restore", module.NamespaceID, module.ID, recordID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	r := h.lookupRecordByID(module, recordID)
+	h.a.NotNil(r)
+	h.a.Equal("v1", r.Values.FilterByName("name")[0].Value)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/revisions/42/restore", module.NamespaceID, module.ID, recordID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record.errors.revisionNotFound")).
+		End()
+}
+
+func TestRecordTrash(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "trashed"})
+	h.makeRecord(module)
+
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "delete")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "records.search")
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, record.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/trash", module.NamespaceID, module.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record.errors.notAllowedToSearchTrash")).
+		End()
+
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.undelete", "record.purge")
+
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/trash", module.NamespaceID, module.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		Assert(jsonpath.Equal(`$.response.set[0].recordID`, fmt.Sprintf("%d", record.ID))).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/undelete", module.NamespaceID, module.ID, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	r := h.lookupRecordByID(module, record.ID)
+	h.a.Nil(r.DeletedAt)
+	h.a.Equal("trashed", r.Values.FilterByName("name")[0].Value)
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d/purge", module.NamespaceID, module.ID, record.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record.errors.notDeleted")).
+		End()
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/trash", module.NamespaceID, module.ID)).
+		JSON(fmt.Sprintf(`{"recordIDs": ["%d"]}`, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	_, err := store.LookupComposeRecordByID(context.Background(), service.DefaultStore, module, record.ID)
+	h.a.Equal(store.ErrNotFound, err)
+}
+
+func TestRecordUndeleteValidation(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFieldsRequired("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "email", Value: "required name is missing"})
+
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "delete")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.undelete")
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/undelete", module.NamespaceID, module.ID, record.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record.errors.valueInput")).
+		End()
+
+	r, err := store.LookupComposeRecordByID(context.Background(), service.DefaultStore, module, record.ID)
+	h.noError(err)
+	h.a.NotNil(r.DeletedAt)
+}
+
+func TestRecordUndeleteEvents(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record testing module")
+	record := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "trashed"})
+
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "delete")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.undelete")
+
+	var (
+		fired []string
+		ptr   = eventbus.Service().Register(
+			func(ctx context.Context, ev eventbus.Event) error {
+				fired = append(fired, ev.EventType())
+				return nil
+			},
+			eventbus.For("compose:record"),
+			eventbus.On("beforeUndelete", "afterUndelete"),
+		)
+	)
+
+	defer eventbus.Service().Unregister(ptr)
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Empty(fired)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/%d/undelete", module.NamespaceID, module.ID, record.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.a.Equal([]string{"beforeUndelete", "afterUndelete"}, fired)
+}
+
+func TestRecordPurgeAttachments(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	h.noError(store.TruncateAttachments(context.Background(), service.DefaultStore))
+
+	module := h.repoMakeRecordModuleWithFields(
+		"record testing module",
+		&types.ModuleField{Name: "file", Kind: "File", Multi: true},
+	)
+
+	var (
+		owned  = h.repoMakeAttachment()
+		shared = h.repoMakeAttachment()
+		purged = h.makeRecord(module,
+			&types.RecordValue{Name: "file", Value: strconv.FormatUint(owned.ID, 10), Ref: owned.ID, Place: 0},
+			&types.RecordValue{Name: "file", Value: strconv.FormatUint(shared.ID, 10), Ref: shared.ID, Place: 1},
+		)
+	)
+
+	h.makeRecord(module, &types.RecordValue{Name: "file", Value: strconv.FormatUint(shared.ID, 10), Ref: shared.ID})
+
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "delete")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.purge")
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d", module.NamespaceID, module.ID, purged.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/namespace/%d/module/%d/record/%d/purge", module.NamespaceID, module.ID, purged.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	_, err := store.LookupComposeAttachmentByID(context.Background(), service.DefaultStore, owned.ID)
+	h.a.Equal(store.ErrNotFound, err)
+
+	h.lookupAttachmentByID(shared.ID)
+}
+
 func TestRecordAttachment(t *testing.T) {
 	h := newHelper(t)
 	h.clearRecords()
@@ -957,6 +1221,366 @@ func TestRecordExport(t *testing.T) {
 	h.a.Equal(expected, string(b))
 }
 
+func TestRecordExportSpreadsheet(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record export module")
+	other := h.makeRecordModuleWithFieldsOnNs("other record export module", h.lookupNamespaceByID(module.NamespaceID))
+	for i := 0; i < 3; i++ {
+		h.makeRecord(module, &types.RecordValue{Name: "name", Value: fmt.Sprintf("d%d", i)})
+	}
+	h.makeRecord(other, &types.RecordValue{Name: "name", Value: "o0"})
+
+	for _, ext := range []string{"xlsx", "ods"} {
+		t.Run(ext, func(t *testing.T) {
+			r := h.apiInit().
+				Get(fmt.Sprintf("/namespace/%d/module/%d/record/export.%s", module.NamespaceID, module.ID, ext)).
+				Query("fields", "name").
+				Query("modules", strconv.FormatUint(other.ID, 10)).
+				Header("Accept", "application/json").
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+
+			b, err := ioutil.ReadAll(r.Response.Body)
+			h.noError(err)
+
+			rr, err := spreadsheet.Decoder().Decode(context.Background(), bytes.NewReader(b), nil)
+			h.noError(err)
+			h.a.Len(rr, 2)
+
+			for i, count := range []uint64{3, 1} {
+				ds, ok := rr[i].(*resource.ResourceDataset)
+				h.a.True(ok)
+				h.a.Equal([]string{"id", "name"}, ds.P.Fields())
+				h.a.Equal(count, ds.P.Count())
+			}
+
+			// exported document can be used for import
+			url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+			h.apiInitRecordImport(h.apiInit(), url, "export."+ext, b).
+				Assert(helpers.AssertNoErrors).
+				Assert(jsonpath.Equal("$.response.progress.entryCount", float64(3))).
+				Assert(jsonpath.Equal("$.response.key", "id")).
+				End()
+		})
+	}
+}
+
+func TestRecordExportSpreadsheet_multiValueRoundTrip(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+
+	module := h.repoMakeRecordModuleWithFields("record export module")
+
+	for _, ext := range []string{"xlsx", "ods"} {
+		t.Run(ext, func(t *testing.T) {
+			h.noError(store.TruncateComposeRecords(context.Background(), service.DefaultStore, nil))
+			h.makeRecord(module,
+				&types.RecordValue{Name: "options", Value: "a", Place: 0},
+				&types.RecordValue{Name: "options", Value: "b", Place: 1},
+				&types.RecordValue{Name: "description", Value: "line 1\nline 2"},
+			)
+
+			r := h.apiInit().
+				Get(fmt.Sprintf("/namespace/%d/module/%d/record/export.%s", module.NamespaceID, module.ID, ext)).
+				Query("fields", "options").
+				Query("fields", "description").
+				Header("Accept", "application/json").
+				Expect(t).
+				Status(http.StatusOK).
+				End()
+
+			b, err := ioutil.ReadAll(r.Response.Body)
+			h.noError(err)
+
+			h.noError(store.TruncateComposeRecords(context.Background(), service.DefaultStore, nil))
+
+			url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+			rsp := &rImportSession{}
+			api := h.apiInit()
+
+			h.apiInitRecordImport(api, url, "export."+ext, b).End().JSON(rsp)
+			h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"options":"options","description":"description"},"onError":"fail"}`).
+				Assert(helpers.AssertNoErrors).
+				End()
+
+			rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID, NamespaceID: module.NamespaceID})
+			h.noError(err)
+			h.a.Len(rr, 1)
+
+			vv := rr[0].Values.FilterByName("options")
+			h.a.Len(vv, 2)
+			h.a.Equal("a", vv[0].Value)
+			h.a.Equal("b", vv[1].Value)
+
+			// multi-line values of single-value fields are kept intact
+			h.a.Equal("line 1\nline 2", rr[0].Values.Get("description", 0).Value)
+		})
+	}
+}
+
+func TestRecordImport_multiValueNotSplitForCsv(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+
+	module := h.repoMakeRecordModuleWithFields("record import module")
+
+	url := fmt.Sprintf("/namespace/%d/module/%d/record/import", module.NamespaceID, module.ID)
+	rsp := &rImportSession{}
+	api := h.apiInit()
+
+	h.apiInitRecordImport(api, url, "f1.csv", []byte("name,options\nfoo,\"a\nb\"\n")).End().JSON(rsp)
+	h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"name":"name","options":"options"},"onError":"fail"}`).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	rr, _, err := store.SearchComposeRecords(context.Background(), service.DefaultStore, module, types.RecordFilter{ModuleID: module.ID, NamespaceID: module.NamespaceID})
+	h.noError(err)
+	h.a.Len(rr, 1)
+
+	vv := rr[0].Values.FilterByName("options")
+	h.a.Len(vv, 1)
+	h.a.Equal("a\nb", vv[0].Value)
+}
+
+func TestRecordExportSpreadsheet_multiModuleNotSupported(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record export module")
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/export.csv", module.NamespaceID, module.ID)).
+		Query("fields", "name").
+		Query("modules", "42").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("multi-module export not supported by format (csv)")).
+		End()
+}
+
+func TestRecordStreamExport(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record stream export module")
+	helpers.AllowMe(h, module.RbacResource(), "records.search")
+	for i := 0; i < 5; i++ {
+		h.makeRecord(module,
+			&types.RecordValue{Name: "name", Value: fmt.Sprintf("d%d", i)},
+			&types.RecordValue{Name: "email", Value: fmt.Sprintf("d%d@example.tld", i)},
+		)
+	}
+
+	r := h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/stream/export", module.NamespaceID, module.ID)).
+		Query("fields", "name").
+		Query("sort", "name DESC").
+		Query("batchSize", "2").
+		Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/x-ndjson").
+		End()
+
+	b, err := ioutil.ReadAll(r.Response.Body)
+	h.noError(err)
+
+	lines := bytes.Split(bytes.TrimSpace(b), []byte("\n"))
+	h.a.Len(lines, 5)
+
+	for i, l := range lines {
+		rec := &types.Record{}
+		h.noError(json.Unmarshal(l, rec))
+		h.a.NotZero(rec.ID)
+		h.a.Len(rec.Values, 1)
+		h.a.Equal(fmt.Sprintf("d%d", 4-i), rec.Values.Get("name", 0).Value)
+	}
+}
+
+func TestRecordStreamExport_forbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	module := h.repoMakeRecordModuleWithFields("record stream export module")
+	h.apiInit().
+		Get(fmt.Sprintf("/namespace/%d/module/%d/record/stream/export", module.NamespaceID, module.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("record.errors.notAllowedToSearch")).
+		End()
+}
+
+func TestRecordStreamImport(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "update")
+
+	module := h.repoMakeRecordModuleWithFields("record stream import module")
+	existing := h.makeRecord(module, &types.RecordValue{Name: "name", Value: "old"})
+
+	body := fmt.Sprintf(`{"values":[{"name":"name","value":"r1"}]}
+{"recordID":"%d","values":[{"name":"name","value":"new"}]}
+
+{"values":[{"name":"unknown","value":"r3"}]}
+{"values":[{"name":"name","value":"r4"}]}
+`, existing.ID)
+
+	type (
+		result struct {
+			Line     uint   `json:"line"`
+			RecordID string `json:"recordID"`
+			Error    string `json:"error"`
+			Summary  *struct {
+				Processed uint `json:"processed"`
+				Created   uint `json:"created"`
+				Updated   uint `json:"updated"`
+				Failed    uint `json:"failed"`
+				Aborted   bool `json:"aborted"`
+			} `json:"summary"`
+		}
+	)
+
+	streamImport := func(onError string, body string) []*result {
+		r := h.apiInit().
+			Post(fmt.Sprintf("/namespace/%d/module/%d/record/stream/import", module.NamespaceID, module.ID)).
+			Query("batchSize", "2").
+			Query("onError", onError).
+			Header("Content-Type", "application/x-ndjson").
+			Body(body).
+			Expect(t).
+			Status(http.StatusOK).
+			End()
+
+		b, err := ioutil.ReadAll(r.Response.Body)
+		h.noError(err)
+
+		rr := make([]*result, 0)
+		for _, l := range bytes.Split(bytes.TrimSpace(b), []byte("\n")) {
+			res := &result{}
+			h.noError(json.Unmarshal(l, res))
+			rr = append(rr, res)
+		}
+
+		return rr
+	}
+
+	t.Run("fail", func(t *testing.T) {
+		rr := streamImport("fail", body)
+		h.a.Len(rr, 5)
+
+		// first batch is applied
+		h.a.Equal(uint(1), rr[0].Line)
+		h.a.NotEmpty(rr[0].RecordID)
+		h.a.Equal(uint(2), rr[1].Line)
+		h.a.Equal(strconv.FormatUint(existing.ID, 10), rr[1].RecordID)
+
+		// second batch is rolled back
+		h.a.Equal(uint(4), rr[2].Line)
+		h.a.NotEmpty(rr[2].Error)
+		h.a.Equal(uint(5), rr[3].Line)
+		h.a.Equal("batch rolled back", rr[3].Error)
+
+		h.a.NotNil(rr[4].Summary)
+		h.a.True(rr[4].Summary.Aborted)
+		h.a.Equal(uint(4), rr[4].Summary.Processed)
+		h.a.Equal(uint(2), rr[4].Summary.Failed)
+
+		h.a.Equal("new", h.lookupRecordByID(module, existing.ID).Values.Get("name", 0).Value)
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		rr := streamImport("skip", body)
+		h.a.Len(rr, 5)
+
+		h.a.NotEmpty(rr[2].Error)
+		h.a.NotEmpty(rr[3].RecordID)
+
+		h.a.NotNil(rr[4].Summary)
+		h.a.False(rr[4].Summary.Aborted)
+		h.a.Equal(uint(2), rr[4].Summary.Created)
+		h.a.Equal(uint(1), rr[4].Summary.Updated)
+		h.a.Equal(uint(1), rr[4].Summary.Failed)
+	})
+
+	t.Run("fail on invalid line", func(t *testing.T) {
+		rr := streamImport("fail", `{"values":[{"name":"name","value":"r1"}]}
+{"values":
+{"values":[{"name":"name","value":"r3"}]}
+`)
+		h.a.Len(rr, 3)
+
+		// line before the invalid one is applied
+		h.a.Equal(uint(1), rr[0].Line)
+		h.a.NotEmpty(rr[0].RecordID)
+
+		h.a.Equal(uint(2), rr[1].Line)
+		h.a.NotEmpty(rr[1].Error)
+
+		h.a.NotNil(rr[2].Summary)
+		h.a.True(rr[2].Summary.Aborted)
+		h.a.Equal(uint(2), rr[2].Summary.Processed)
+		h.a.Equal(uint(1), rr[2].Summary.Created)
+		h.a.Equal(uint(1), rr[2].Summary.Failed)
+	})
+
+	t.Run("after events of rolled back batch", func(t *testing.T) {
+		var (
+			created = 0
+			ptr     = eventbus.Service().Register(
+				func(ctx context.Context, ev eventbus.Event) error {
+					created++
+					return nil
+				},
+				eventbus.For("compose:record"),
+				eventbus.On("afterCreate"),
+			)
+		)
+
+		defer eventbus.Service().Unregister(ptr)
+
+		streamImport("fail", `{"values":[{"name":"name","value":"r1"}]}
+{"values":[{"name":"unknown","value":"r2"}]}
+`)
+		h.a.Zero(created)
+
+		streamImport("skip", `{"values":[{"name":"name","value":"r1"}]}
+{"values":[{"name":"unknown","value":"r2"}]}
+`)
+		h.a.Equal(1, created)
+	})
+
+	t.Run("before events of skipped line's batch", func(t *testing.T) {
+		var (
+			creating = 0
+			ptr      = eventbus.Service().Register(
+				func(ctx context.Context, ev eventbus.Event) error {
+					creating++
+					return nil
+				},
+				eventbus.For("compose:record"),
+				eventbus.On("beforeCreate"),
+			)
+		)
+
+		defer eventbus.Service().Unregister(ptr)
+
+		// failed line is rolled back on its own;
+		// the rest of its batch is not applied again
+		streamImport("skip", `{"values":[{"name":"name","value":"r1"}]}
+{"values":[{"name":"unknown","value":"r2"}]}
+{"values":[{"name":"name","value":"r3"}]}
+`)
+		h.a.Equal(2, creating)
+	})
+}
+
 func (h helper) apiInitRecordImport(api *apitest.APITest, url, f string, file []byte) *apitest.Response {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -0,0 +1,180 @@
+package compose
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/compose/service"
+	"github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	"github.com/steinfletcher/apitest"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+// makes contact module with email & tenantCode unique constraint
+func (h helper) makeUniqueConstraintModule() *types.Module {
+	ns := h.makeNamespace("unique constraint testing namespace")
+
+	m := h.createModule(&types.Module{
+		Handle:      "contact",
+		NamespaceID: ns.ID,
+		Config: types.ModuleConfig{UniqueConstraints: []*types.ModuleConfigUniqueConstraint{
+			{Name: "email_tenant", Fields: []string{"email", "tenantCode"}},
+		}},
+		Fields: types.ModuleFieldSet{
+			&types.ModuleField{Name: "email", Kind: "Email"},
+			&types.ModuleField{Name: "tenantCode", Kind: "String"},
+		},
+	})
+
+	helpers.AllowMe(h, types.NamespaceRbacResource(0), "read")
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "read", "record.create")
+	helpers.AllowMe(h, types.RecordRbacResource(0, 0, 0), "read", "update", "delete")
+	helpers.AllowMe(h, types.ModuleFieldRbacResource(0, 0, 0), "record.value.read", "record.value.update")
+
+	return m
+}
+
+func (h helper) createContact(m *types.Module, email, tenantCode string) *apitest.Response {
+	return h.apiInit().
+		Post(fmt.Sprintf("/namespace/%d/module/%d/record/", m.NamespaceID, m.ID)).
+		JSON(fmt.Sprintf(`{"values": [{"name": "email", "value": %q}, {"name": "tenantCode", "value": %q}]}`, email, tenantCode)).
+		Header("Accept", "application/json").
+		Expect(h.t)
+}
+
+func TestRecordUniqueConstraint(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	m := h.makeUniqueConstraintModule()
+
+	h.createContact(m, "jane@example.tld", "t1").
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.createContact(m, "jane@example.tld", "t2").
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	// records with missing values are not checked
+	h.createContact(m, "jane@example.tld", "").
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.createContact(m, "jane@example.tld", "").
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	h.createContact(m, "jane@example.tld", "t1").
+		Status(http.StatusOK).
+		Assert(helpers.AssertRecordValueError(
+			&types.RecordValueError{Kind: "duplicateValue", Meta: map[string]interface{}{"field": "email"}},
+			&types.RecordValueError{Kind: "duplicateValue", Meta: map[string]interface{}{"field": "tenantCode"}},
+		)).
+		End()
+}
+
+func TestRecordUniqueConstraintDeleted(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	m := h.makeUniqueConstraintModule()
+
+	r := h.makeRecord(m,
+		&types.RecordValue{Name: "email", Value: "jane@example.tld"},
+		&types.RecordValue{Name: "tenantCode", Value: "t1"},
+	)
+
+	h.createContact(m, "jane@example.tld", "t1").
+		Status(http.StatusOK).
+		Assert(helpers.AssertRecordValueError(
+			&types.RecordValueError{Kind: "duplicateValue", Meta: map[string]interface{}{"field": "email"}},
+		)).
+		End()
+
+	h.noError(service.DefaultRecord.DeleteByID(h.secCtx(), m.NamespaceID, m.ID, r.ID))
+
+	h.createContact(m, "jane@example.tld", "t1").
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+}
+
+func TestModuleUpdateUniqueConstraint(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		m = h.makeUniqueConstraintModule()
+
+		// records stored before constraint was added
+		unconstrained = m.Clone()
+	)
+
+	helpers.AllowMe(h, types.ModuleRbacResource(0, 0), "update")
+	unconstrained.Config = types.ModuleConfig{}
+
+	for i := 0; i < 2; i++ {
+		h.makeRecord(unconstrained,
+			&types.RecordValue{Name: "email", Value: "jane@example.tld"},
+			&types.RecordValue{Name: "tenantCode", Value: "t1"},
+		)
+	}
+
+	update := func(constraints string) *apitest.Response {
+		return h.apiInit().
+			Post(fmt.Sprintf("/namespace/%d/module/%d", m.NamespaceID, m.ID)).
+			JSON(fmt.Sprintf(`{"handle": "contact", "config": {"uniqueConstraints": %s}, "fields": [{"fieldID": "%d", "name": "email", "kind": "Email"}, {"fieldID": "%d", "name": "tenantCode", "kind": "String"}]}`, constraints, m.Fields[0].ID, m.Fields[1].ID)).
+			Header("Accept", "application/json").
+			Expect(t)
+	}
+
+	update(`[{"name": "email", "fields": ["email"]}]`).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("module.errors.uniqueConstraintViolated")).
+		End()
+
+	update(`[{"name": "email", "fields": ["nope"]}]`).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("module.errors.invalidUniqueConstraint")).
+		End()
+
+	update(`[]`).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Present(`$.response.moduleID`)).
+		End()
+}
+
+func TestRecordUniqueConstraintImport(t *testing.T) {
+	h := newHelper(t)
+	h.clearRecords()
+
+	var (
+		m   = h.makeUniqueConstraintModule()
+		url = fmt.Sprintf("/namespace/%d/module/%d/record/import", m.NamespaceID, m.ID)
+		rsp = &rImportSession{}
+		api = h.apiInit()
+	)
+
+	r := h.apiInitRecordImport(api, url, "f1.csv", []byte("email,tenant\njane@example.tld,t1\njane@example.tld,t2\njane@example.tld,t1\n")).End()
+	r.JSON(rsp)
+
+	h.apiRunRecordImport(api, fmt.Sprintf("%s/%s", url, rsp.Response.SessionID), `{"fields":{"email":"email","tenant":"tenantCode"},"onError":"skip"}`).
+		End()
+
+	api.Get(fmt.Sprintf("%s/%s", url, rsp.Response.SessionID)).
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal("$.response.progress.completed", float64(2))).
+		Assert(jsonpath.Equal("$.response.progress.failed", float64(1))).
+		Assert(jsonpath.Present("$.response.progress.failLog.errors[\"duplicateValue field email\"]")).
+		End()
+}
@@ -6,11 +6,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/cortezaproject/corteza-server/pkg/eventbus"
 	"github.com/cortezaproject/corteza-server/pkg/messagebus"
 	mtypes "github.com/cortezaproject/corteza-server/pkg/messagebus/types"
 	"github.com/cortezaproject/corteza-server/system/service/event"
 	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/go-redis/redis/v8"
 )
 
 type (
@@ -96,6 +98,58 @@ func TestMessageWrite(t *testing.T) {
 	w.Wait()
 }
 
+func TestMessageWriteRedis(t *testing.T) {
+	var (
+		h   = newHelper(t)
+		ctx = context.Background()
+		srv = miniredis.RunT(t)
+
+		messageProcessed = make(chan []byte, 1)
+
+		q = &types.Queue{
+			ID:       1,
+			Queue:    "test_redis",
+			Consumer: string(mtypes.ConsumerRedis),
+			Meta: types.QueueMeta{
+				DispatchEvents: true,
+				RedisURL:       "redis://" + srv.Addr(),
+			},
+		}
+	)
+
+	h.prepareQueues(ctx, q)
+
+	// reinit the messagebus
+	h.initMessagebus(ctx)
+
+	ptr := registerTestEvent(func(ctx context.Context, ev eventbus.Event) error {
+		if m, ok := ev.(interface{ Payload() *types.QueueMessage }); ok {
+			messageProcessed <- m.Payload().Payload
+		}
+
+		return nil
+	})
+
+	defer eventbus.Service().Unregister(ptr)
+
+	messagebus.Service().Push(q.Queue, []byte("this is a test"))
+
+	select {
+	case <-time.After(time.Second * 3):
+		t.Fatal("message not processed")
+
+	case p := <-messageProcessed:
+		h.a.Equal([]byte("this is a test"), p)
+	}
+
+	// processed message is acknowledged
+	rc := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	h.a.Eventually(func() bool {
+		pending, err := rc.XPending(ctx, q.Queue, "corteza").Result()
+		return err == nil && pending.Count == 0
+	}, time.Second, time.Millisecond*10)
+}
+
 func TestMessageWriteEventbus(t *testing.T) {
 	var (
 		h   = newHelper(t)
@@ -123,7 +177,7 @@ func TestMessageWriteEventbus(t *testing.T) {
 
 	// eventbus is the consumer, update temporary channel
 	// so we get the value back lower
-	registerTestEvent(messageProcessedFn(messageProcessed))
+	defer eventbus.Service().Unregister(registerTestEvent(messageProcessedFn(messageProcessed)))
 
 	go func() {
 		messagebus.Service().Push(testQueueEb.Queue, []byte("this is a test"))
@@ -145,10 +199,10 @@ func TestMessageWriteEventbus(t *testing.T) {
 	w.Wait()
 }
 
-func registerTestEvent(fn eventbus.HandlerFn) {
+func registerTestEvent(fn eventbus.HandlerFn) uintptr {
 	e := event.QueueOnMessage(&types.QueueMessage{})
 
-	eventbus.
+	return eventbus.
 		Service().
 		Register(fn,
 			eventbus.On(e.EventType()),
@@ -0,0 +1,200 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cortezaproject/corteza-server/pkg/apigw/secure"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) clearApigwSecrets() {
+	h.noError(store.TruncateApigwSecrets(context.Background(), service.DefaultStore))
+}
+
+// secure storage, as used by API Gateway filters
+func (h helper) apigwSecureStorage() *secure.Storage {
+	return secure.NewStorage(service.DefaultStore, secure.NewKeyring(testApp.Opt.Apigw.SecretsKey, ""))
+}
+
+func (h helper) apiCreateApigwSecret(handle, value string) *types.ApigwSecret {
+	rsp := &struct {
+		Response *types.ApigwSecret `json:"response"`
+	}{}
+
+	h.apiInit().
+		Post("/apigw/secret").
+		Header("Accept", "application/json").
+		FormData("handle", handle).
+		FormData("value", value).
+		Expect(h.t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End().
+		JSON(rsp)
+
+	return rsp.Response
+}
+
+func TestApigwSecretCreateForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	h.apiInit().
+		Post("/apigw/secret").
+		Header("Accept", "application/json").
+		FormData("handle", "api-key").
+		FormData("value", "s3cr3t").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("apigwSecret.errors.notAllowedToManage")).
+		End()
+}
+
+func TestApigwSecretCreate(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	h.apiInit().
+		Post("/apigw/secret").
+		Header("Accept", "application/json").
+		FormData("handle", "api-key").
+		FormData("value", "s3cr3t").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.handle`, "api-key")).
+		Assert(jsonpath.NotPresent(`$.response.value`)).
+		Assert(jsonpath.NotPresent(`$.response.ciphertext`)).
+		Assert(jsonpath.NotPresent(`$.response.dataKey`)).
+		End()
+
+	s, err := store.LookupApigwSecretByHandle(context.Background(), service.DefaultStore, "api-key")
+	h.noError(err)
+	h.a.NotContains(string(s.Ciphertext), "s3cr3t")
+
+	v, err := h.apigwSecureStorage().Secret(context.Background(), "api-key")
+	h.noError(err)
+	h.a.Equal("s3cr3t", v)
+
+	h.apiInit().
+		Post("/apigw/secret").
+		Header("Accept", "application/json").
+		FormData("handle", "api-key").
+		FormData("value", "s3cr3t").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("apigwSecret.errors.handleNotUnique")).
+		End()
+}
+
+func TestApigwSecretListAndRead(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	s := h.apiCreateApigwSecret("basic-pass", "s3cr3t")
+	h.apiCreateApigwSecret("oauth2-secret", "s3cr3t")
+
+	h.apiInit().
+		Get("/apigw/secret/").
+		Query("query", "oauth2").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		Assert(jsonpath.Equal(`$.response.set[0].handle`, "oauth2-secret")).
+		End()
+
+	h.apiInit().
+		Get(fmt.Sprintf("/apigw/secret/%d", s.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.handle`, "basic-pass")).
+		Assert(jsonpath.NotPresent(`$.response.value`)).
+		End()
+}
+
+func TestApigwSecretUpdate(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	s := h.apiCreateApigwSecret("api-key", "s3cr3t")
+
+	h.apiInit().
+		Put(fmt.Sprintf("/apigw/secret/%d", s.ID)).
+		Header("Accept", "application/json").
+		FormData("handle", "api-key").
+		FormData("value", "n3w-s3cr3t").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Present(`$.response.rotatedAt`)).
+		End()
+
+	v, err := h.apigwSecureStorage().Secret(context.Background(), "api-key")
+	h.noError(err)
+	h.a.Equal("n3w-s3cr3t", v)
+}
+
+func TestApigwSecretDelete(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	s := h.apiCreateApigwSecret("api-key", "s3cr3t")
+
+	h.apiInit().
+		Delete(fmt.Sprintf("/apigw/secret/%d", s.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	_, err := h.apigwSecureStorage().Secret(context.Background(), "api-key")
+	h.a.Error(err)
+
+	h.apiInit().
+		Post(fmt.Sprintf("/apigw/secret/%d/undelete", s.ID)).
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	_, err = h.apigwSecureStorage().Secret(context.Background(), "api-key")
+	h.noError(err)
+}
+
+func TestApigwSecretRotateKeys(t *testing.T) {
+	h := newHelper(t)
+	h.clearApigwSecrets()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "apigw-secrets.manage")
+
+	h.apiCreateApigwSecret("api-key", "s3cr3t")
+
+	// all secrets are already encrypted with the current key
+	h.apiInit().
+		Post("/apigw/secret/rotate-keys").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.rotated`, float64(0))).
+		End()
+}
@@ -377,6 +377,302 @@ func TestScimPatchingGroupMembership(t *testing.T) {
 
 }
 
+func TestScimUserList(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	h.createUser(&types.User{Email: "scim-a@example.tld", Username: "scim-a", Name: "Alpha"})
+	h.createUser(&types.User{Email: "scim-b@example.tld", Username: "scim-b", Name: "Beta"})
+	h.createUser(&types.User{Email: "scim-c@example.tld", Username: "scim-c", Name: "Gamma"})
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `userName eq "scim-b"`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Contains(`$.schemas`, "urn:ietf:params:scim:api:messages:2.0:ListResponse")).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].userName`, "scim-b")).
+		End()
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `userName sw "scim-" and not (name.formatted eq "Beta")`).
+		Query("sortBy", "userName").
+		Query("sortOrder", "descending").
+		Query("startIndex", "2").
+		Query("count", "1").
+		Query("attributes", "userName").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(2))).
+		Assert(jsonpath.Equal(`$.startIndex`, float64(2))).
+		Assert(jsonpath.Equal(`$.itemsPerPage`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].userName`, "scim-a")).
+		Assert(jsonpath.NotPresent(`$.Resources[0].emails`)).
+		End()
+
+	h.scimApiInit().
+		Post("/Users/.search").
+		JSON(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:SearchRequest"],"filter":"emails[value eq \"scim-c@example.tld\"]"}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].userName`, "scim-c")).
+		End()
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `userName eq`).
+		Expect(t).
+		Status(http.StatusBadRequest).
+		Assert(jsonpath.Equal(`$.scimType`, "invalidFilter")).
+		End()
+}
+
+func TestScimUserListPaging(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	for i := 0; i < 5; i++ {
+		h.createUser(&types.User{Email: fmt.Sprintf("scim-%d@example.tld", i), Username: fmt.Sprintf("scim-%d", i), Name: "Paging"})
+	}
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("filter", `name.formatted eq "Paging" and userName ne "scim-0"`).
+		Query("sortBy", "emails.value").
+		Query("sortOrder", "descending").
+		Query("startIndex", "2").
+		Query("count", "2").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(4))).
+		Assert(jsonpath.Equal(`$.itemsPerPage`, float64(2))).
+		Assert(jsonpath.Equal(`$.Resources[0].userName`, "scim-3")).
+		Assert(jsonpath.Equal(`$.Resources[1].userName`, "scim-2")).
+		End()
+
+	h.scimApiInit().
+		Get("/Users").
+		Query("startIndex", "5").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(5))).
+		Assert(jsonpath.Equal(`$.itemsPerPage`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].userName`, "scim-4")).
+		End()
+}
+
+func TestScimUserListByExternalIdAsPrimary(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	h.scimApiInit(scimSetWithExternalId).
+		Post("/Users").
+		JSON(`{"userName":"foo","emails":[{"value":"foo@bar.com"}],"externalId":"foo42","schemas":["urn:ietf:params:scim:schemas:core:2.0:User"]}`).
+		Expect(t).
+		Status(http.StatusCreated).
+		End()
+
+	h.scimApiInit(scimSetWithExternalId).
+		Get("/Users").
+		Query("filter", `id eq "foo42"`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].id`, "foo42")).
+		Assert(jsonpath.Equal(`$.Resources[0].userName`, "foo")).
+		End()
+
+	h.scimApiInit(scimSetWithExternalId).
+		Get("/Users").
+		Query("filter", `id eq "foo42" and externalId eq "bar"`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(0))).
+		End()
+}
+
+func TestScimUserPatch(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	u := h.createUser(&types.User{Email: "scim-patch@example.tld", Username: "scim-patch", Name: "Patch"})
+
+	h.scimApiInit().
+		Patch(fmt.Sprintf("/Users/%d", u.ID)).
+		JSON(`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[
+			{"op":"Replace","path":"active","value":false},
+			{"op":"replace","value":{"name.formatted":"Patched"}}
+		]}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.active`, false)).
+		Assert(jsonpath.Equal(`$.name.formatted`, "Patched")).
+		End()
+
+	u, err := store.LookupUserByID(context.Background(), service.DefaultStore, u.ID)
+	h.a.NoError(err)
+	h.a.NotNil(u.SuspendedAt)
+	h.a.Equal("Patched", u.Name)
+}
+
+func TestScimUserVersion(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+
+	u := h.createUserWithEmail(h.randEmail())
+
+	rsp := h.scimApiInit().
+		Get(fmt.Sprintf("/Users/%d", u.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	version := rsp.Response.Header.Get("ETag")
+	h.a.NotEmpty(version)
+
+	h.scimApiInit().
+		Get(fmt.Sprintf("/Users/%d", u.ID)).
+		Header("If-None-Match", version).
+		Expect(t).
+		Status(http.StatusNotModified).
+		End()
+
+	h.scimApiInit().
+		Delete(fmt.Sprintf("/Users/%d", u.ID)).
+		Header("If-Match", `W/"outdated"`).
+		Expect(t).
+		Status(http.StatusPreconditionFailed).
+		Assert(jsonpath.Equal(`$.scimType`, "invalidVers")).
+		End()
+
+	h.scimApiInit().
+		Delete(fmt.Sprintf("/Users/%d", u.ID)).
+		Header("If-Match", version).
+		Expect(t).
+		Status(http.StatusNoContent).
+		End()
+}
+
+func TestScimGroupList(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+	h.clearRoles()
+	h.clearRoleMembers()
+
+	u := h.createUserWithEmail(h.randEmail())
+	r1 := h.createRole(&types.Role{Name: "scim-group-1", Handle: "scim_group_1"})
+	h.createRole(&types.Role{Name: "scim-group-2", Handle: "scim_group_2"})
+	h.createRoleMember(u.ID, r1.ID)
+
+	h.scimApiInit().
+		Get("/Groups").
+		Query("filter", fmt.Sprintf(`members[value eq "%d"]`, u.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(1))).
+		Assert(jsonpath.Equal(`$.Resources[0].displayName`, "scim-group-1")).
+		Assert(jsonpath.Equal(`$.Resources[0].members[0].value`, fmt.Sprintf("%d", u.ID))).
+		End()
+
+	h.scimApiInit().
+		Get("/Groups").
+		Query("filter", `displayName sw "scim-group"`).
+		Query("excludedAttributes", "members").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(2))).
+		Assert(jsonpath.NotPresent(`$.Resources[0].members`)).
+		End()
+}
+
+func TestScimDiscovery(t *testing.T) {
+	h := newHelper(t)
+
+	h.scimApiInit().
+		Get("/ServiceProviderConfig").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.patch.supported`, true)).
+		Assert(jsonpath.Equal(`$.bulk.supported`, true)).
+		Assert(jsonpath.Equal(`$.etag.supported`, true)).
+		End()
+
+	h.scimApiInit().
+		Get("/ResourceTypes").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.totalResults`, float64(2))).
+		End()
+
+	h.scimApiInit().
+		Get("/Schemas/urn:ietf:params:scim:schemas:core:2.0:Group").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Equal(`$.name`, "Group")).
+		End()
+
+	h.scimApiInit().
+		Get("/ResourceTypes/Foo").
+		Expect(t).
+		Status(http.StatusNotFound).
+		End()
+}
+
+func TestScimBulk(t *testing.T) {
+	h := newHelper(t)
+	h.clearUsers()
+	h.clearRoles()
+	h.clearRoleMembers()
+
+	h.scimApiInit().
+		Post("/Bulk").
+		JSON(`{
+  "schemas": ["urn:ietf:params:scim:api:messages:2.0:BulkRequest"],
+  "failOnErrors": 1,
+  "Operations": [
+    {
+      "method": "POST",
+      "path": "/Users",
+      "bulkId": "u1",
+      "data": {"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"], "userName": "bulk", "emails": [{"value": "bulk@example.tld"}]}
+    },
+    {
+      "method": "POST",
+      "path": "/Groups",
+      "bulkId": "g1",
+      "data": {"schemas": ["urn:ietf:params:scim:schemas:core:2.0:Group"], "displayName": "bulk-group", "members": [{"value": "bulkId:u1"}]}
+    },
+    {
+      "method": "DELETE",
+      "path": "/Users/0"
+    },
+    {
+      "method": "DELETE",
+      "path": "/Groups/bulkId:g1"
+    }
+  ]
+}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(jsonpath.Contains(`$.schemas`, "urn:ietf:params:scim:api:messages:2.0:BulkResponse")).
+		Assert(jsonpath.Len(`$.Operations`, 3)).
+		Assert(jsonpath.Equal(`$.Operations[0].status`, "201")).
+		Assert(jsonpath.Equal(`$.Operations[1].status`, "201")).
+		Assert(jsonpath.Equal(`$.Operations[2].status`, "400")).
+		End()
+
+	rr, _, err := store.SearchRoles(context.Background(), service.DefaultStore, types.RoleFilter{Name: "bulk-group"})
+	h.a.NoError(err)
+	h.a.Len(rr, 1)
+
+	mm, _, err := store.SearchRoleMembers(context.Background(), service.DefaultStore, types.RoleMemberFilter{RoleID: rr[0].ID})
+	h.a.NoError(err)
+	h.a.Len(mm, 1)
+}
+
 func scimSetWithExternalId(c *scim.Config) {
 	c.ExternalIdAsPrimary = true
 }
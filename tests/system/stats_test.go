@@ -0,0 +1,173 @@
+package system
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	composeTypes "github.com/cortezaproject/corteza-server/compose/types"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/pkg/options"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+func (h helper) clearCompose() {
+	var (
+		ctx = context.Background()
+		s   = service.DefaultStore
+	)
+
+	h.noError(store.TruncateComposeNamespaces(ctx, s))
+	h.noError(store.TruncateComposeModules(ctx, s))
+	h.noError(store.TruncateComposeRecords(ctx, s, &composeTypes.Module{}))
+}
+
+func (h helper) repoMakeComposeRecords(deleted bool, n int) {
+	var (
+		ctx = context.Background()
+		now = time.Now()
+		ns  = &composeTypes.Namespace{ID: id.Next(), Slug: "ns_" + rs(), CreatedAt: now}
+		m   = &composeTypes.Module{ID: id.Next(), NamespaceID: ns.ID, Handle: "m_" + rs(), CreatedAt: now}
+	)
+
+	h.noError(store.CreateComposeNamespace(ctx, service.DefaultStore, ns))
+	h.noError(store.CreateComposeModule(ctx, service.DefaultStore, m))
+
+	for i := 0; i < n; i++ {
+		r := &composeTypes.Record{ID: id.Next(), NamespaceID: ns.ID, ModuleID: m.ID, CreatedAt: now}
+		if deleted {
+			r.DeletedAt = &now
+		}
+
+		h.noError(store.CreateComposeRecord(ctx, service.DefaultStore, m, r))
+	}
+}
+
+func TestStatsLimitsForbidden(t *testing.T) {
+	h := newHelper(t)
+
+	h.apiInit().
+		Get("/stats/limits").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("statistics.errors.notAllowedToReadLimits")).
+		End()
+}
+
+func TestStatsLimits(t *testing.T) {
+	h := newHelper(t)
+	h.clearRoles()
+	h.clearTemplates()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "limits.read")
+
+	h.repoMakeRole()
+	h.repoMakeRole()
+	h.repoMakeTemplate()
+
+	h.apiInit().
+		Get("/stats/limits").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.roles.used`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.roles.limit`, float64(0))).
+		Assert(jsonpath.Equal(`$.response.templates.used`, float64(1))).
+		Assert(jsonpath.Present(`$.response.users`)).
+		Assert(jsonpath.Present(`$.response.gatewayRoutes`)).
+		Assert(jsonpath.Present(`$.response.namespaces`)).
+		Assert(jsonpath.Present(`$.response.modules`)).
+		Assert(jsonpath.Present(`$.response.records`)).
+		Assert(jsonpath.Present(`$.response.workflows`)).
+		End()
+}
+
+func TestStatsLimitsSkipDeleted(t *testing.T) {
+	h := newHelper(t)
+	h.clearRoles()
+	h.clearTemplates()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "limits.read")
+
+	now := time.Now()
+	h.repoMakeRole()
+	h.createRole(&types.Role{Handle: "deleted_" + rs(), DeletedAt: &now})
+	h.createRole(&types.Role{Handle: "archived_" + rs(), ArchivedAt: &now})
+
+	tpl := h.repoMakeTemplate()
+	tpl.DeletedAt = &now
+	h.noError(store.UpdateTemplate(context.Background(), service.DefaultStore, tpl))
+
+	h.apiInit().
+		Get("/stats/limits").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.roles.used`, float64(1))).
+		Assert(jsonpath.Equal(`$.response.templates.used`, float64(0))).
+		End()
+}
+
+func TestStatsLimitsCompose(t *testing.T) {
+	h := newHelper(t)
+	h.clearCompose()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "limits.read")
+
+	h.repoMakeComposeRecords(false, 3)
+	h.repoMakeComposeRecords(true, 2)
+
+	h.apiInit().
+		Get("/stats/limits").
+		Header("Accept", "application/json").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.namespaces.used`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.modules.used`, float64(2))).
+		Assert(jsonpath.Equal(`$.response.records.used`, float64(3))).
+		End()
+}
+
+func TestStatsLimitsConfigured(t *testing.T) {
+	h := newHelper(t)
+	h.clearRoles()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "limits.read")
+
+	h.repoMakeRole()
+
+	svc := service.Statistics(options.LimitOpt{SystemRoles: 5, ComposeRecords: 100})
+
+	rval, err := svc.Limits(h.secCtx())
+	h.noError(err)
+	h.a.Equal(5, rval.Roles.Limit)
+	h.a.Equal(uint(1), rval.Roles.Used)
+	h.a.Equal(100, rval.Records.Limit)
+	h.a.Equal(0, rval.Users.Limit)
+}
+
+func TestStatsLimitsRoleLimitReached(t *testing.T) {
+	h := newHelper(t)
+	h.clearRoles()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "role.create")
+
+	h.repoMakeRole()
+
+	svc := service.Role(service.RoleOptions{LimitRoles: 2})
+
+	_, err := svc.Create(h.secCtx(), &types.Role{Handle: "first_" + rs()})
+	h.noError(err)
+
+	_, err = svc.Create(h.secCtx(), &types.Role{Handle: "second_" + rs()})
+	h.a.True(service.RoleErrMaxRoleLimitReached().Is(err))
+}
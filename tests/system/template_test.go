@@ -1,8 +1,12 @@
 package system
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"testing"
 	"time"
@@ -13,6 +17,7 @@ import (
 	"github.com/cortezaproject/corteza-server/system/types"
 	"github.com/cortezaproject/corteza-server/tests/helpers"
 	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+	"github.com/xuri/excelize/v2"
 )
 
 func (h helper) repoMakeTemplate(ss ...string) *types.Template {
@@ -306,3 +311,196 @@ func TestTemplateRenderHTML(t *testing.T) {
 		Assert(helpers.AssertBody("<h1>Hello, world!</h1>")).
 		End()
 }
+
+func TestTemplateCreate_invalidEncoding(t *testing.T) {
+	h := newHelper(t)
+	h.clearTemplates()
+	helpers.AllowMe(h, types.ComponentRbacResource(), "template.create")
+
+	h.apiInit().
+		Post("/template/").
+		Header("Accept", "application/json").
+		FormData("handle", "handle").
+		FormData("type", string(types.DocumentTypeDOCX)).
+		FormData("template", "not base64 encoded").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("template.errors.invalidEncoding")).
+		End()
+}
+
+func TestTemplateRenderDOCX(t *testing.T) {
+	h := newHelper(t)
+	h.clearTemplates()
+	helpers.AllowMe(h, types.ComponentRbacResource(), "templates.search")
+	helpers.AllowMe(h, types.TemplateRbacResource(0), "read")
+	helpers.AllowMe(h, types.TemplateRbacResource(0), "render")
+
+	// actions are split across runs the same way word processors do it
+	document := `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>Dear {{.na</w:t></w:r><w:r><w:t>me}},</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>{{template “signature” .}}</w:t></w:r></w:p>` +
+		`<w:tbl>` +
+		`<w:tr><w:tc><w:p><w:r><w:t>{{range .items}}</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:tc><w:p><w:r><w:t>{{.}}</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:tc><w:p><w:r><w:t>{{end}}</w:t></w:r></w:p></w:tc></w:tr>` +
+		`</w:tbl>` +
+		`</w:body></w:document>`
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="UTF-8"?><Types/>`,
+		"word/document.xml":   document,
+	} {
+		w, err := zw.Create(name)
+		h.noError(err)
+		_, err = w.Write([]byte(content))
+		h.noError(err)
+	}
+	h.noError(zw.Close())
+
+	h.noError(store.CreateTemplate(context.Background(), service.DefaultStore, &types.Template{
+		ID:        id.Next(),
+		CreatedAt: time.Now(),
+		Handle:    "signature",
+		Type:      types.DocumentTypePlain,
+		Partial:   true,
+		Template:  "Acme & Co",
+	}))
+	res := h.repoMakeTemplate("rendering", base64.StdEncoding.EncodeToString(buf.Bytes()), string(types.DocumentTypeDOCX))
+
+	r := h.apiInit().
+		Post(fmt.Sprintf("/template/%d/render/testing.docx", res.ID)).
+		JSON(`{"variables": {"name": "<Jane>", "items": ["a", "b\nc"]}}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", string(types.DocumentTypeDOCX)).
+		End()
+
+	bb, err := ioutil.ReadAll(r.Response.Body)
+	h.noError(err)
+
+	zr, err := zip.NewReader(bytes.NewReader(bb), int64(len(bb)))
+	h.noError(err)
+
+	var out []byte
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			rc, err := f.Open()
+			h.noError(err)
+			out, err = ioutil.ReadAll(rc)
+			h.noError(err)
+		}
+	}
+
+	h.a.Equal(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`+
+		`<w:p><w:r><w:t xml:space="preserve">Dear &lt;Jane&gt;</w:t></w:r><w:r><w:t xml:space="preserve">,</w:t></w:r></w:p>`+
+		`<w:p><w:r><w:t xml:space="preserve">Acme &amp; Co</w:t></w:r></w:p>`+
+		`<w:tbl>`+
+		`<w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">a</w:t></w:r></w:p></w:tc></w:tr>`+
+		`<w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">b</w:t><w:br/><w:t xml:space="preserve">c</w:t></w:r></w:p></w:tc></w:tr>`+
+		`</w:tbl>`+
+		`</w:body></w:document>`, string(out))
+}
+
+func TestTemplateRenderXLSX(t *testing.T) {
+	h := newHelper(t)
+	h.clearTemplates()
+	helpers.AllowMe(h, types.ComponentRbacResource(), "templates.search")
+	helpers.AllowMe(h, types.TemplateRbacResource(0), "read")
+	helpers.AllowMe(h, types.TemplateRbacResource(0), "render")
+
+	f := excelize.NewFile()
+	sheet := f.GetSheetName(0)
+	for axis, v := range map[string]string{
+		"A1": "Quote for {{.customer}}",
+		"A2": "{{range .items}}",
+		"A3": "{{.name}}", "B3": "{{.qty}}",
+		"A4": "{{end}}",
+		"A5": "Total", "B5": "{{.total}}",
+	} {
+		h.noError(f.SetCellStr(sheet, axis, v))
+	}
+
+	buf, err := f.WriteToBuffer()
+	h.noError(err)
+
+	res := h.repoMakeTemplate("rendering", base64.StdEncoding.EncodeToString(buf.Bytes()), string(types.DocumentTypeXLSX))
+
+	r := h.apiInit().
+		Post(fmt.Sprintf("/template/%d/render/testing.xlsx", res.ID)).
+		JSON(`{"variables": {"customer": "Acme", "items": [{"name": "foo", "qty": 2}, {"name": "bar", "qty": 3}], "total": 5}}`).
+		Expect(t).
+		Status(http.StatusOK).
+		End()
+
+	bb, err := ioutil.ReadAll(r.Response.Body)
+	h.noError(err)
+
+	out, err := excelize.OpenReader(bytes.NewReader(bb))
+	h.noError(err)
+
+	rows, err := out.GetRows(sheet)
+	h.noError(err)
+	h.a.Equal([][]string{
+		{"Quote for Acme"},
+		{"foo", "2"},
+		{"bar", "3"},
+		{"Total", "5"},
+	}, rows)
+
+	// numbers are stored as numbers
+	zr, err := zip.NewReader(bytes.NewReader(bb), int64(len(bb)))
+	h.noError(err)
+
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			h.noError(err)
+			xml, err := ioutil.ReadAll(rc)
+			h.noError(err)
+			h.a.Regexp(`<c r="B4"( s="\d+")?><v>5</v></c>`, string(xml))
+		}
+	}
+}
+
+func TestTemplateRenderPDF(t *testing.T) {
+	h := newHelper(t)
+	h.clearTemplates()
+	helpers.AllowMe(h, types.ComponentRbacResource(), "templates.search")
+	helpers.AllowMe(h, types.TemplateRbacResource(0), "read")
+	helpers.AllowMe(h, types.TemplateRbacResource(0), "render")
+
+	const (
+		// 1x1 transparent PNG
+		img = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+	)
+
+	res := h.repoMakeTemplate("rendering", `<html><body>
+		<header>Quote for {{.customer}}</header>
+		<footer>Page <span class="page-number"></span> of <span class="page-count"></span></footer>
+		<h1>Hello, {{.customer}}</h1>
+		<p>Thank you for <strong>your</strong> order.</p>
+		<img src="`+img+`" width="32" height="32">
+		<table>
+			<thead><tr><th width="70%">Item</th><th>Qty</th></tr></thead>
+			<tbody>{{range .items}}<tr><td>{{.name}}</td><td>{{.qty}}</td></tr>{{end}}</tbody>
+		</table>
+		<ul><li>first</li><li>second</li></ul>
+		<p style="page-break-before: always; text-align: right">Total: {{.total}}</p>
+	</body></html>`, "text/html")
+
+	r := h.apiInit().
+		Post(fmt.Sprintf("/template/%d/render/testing.pdf", res.ID)).
+		JSON(`{"variables": {"customer": "Acme", "items": [{"name": "foo", "qty": 2}, {"name": "bar", "qty": 3}], "total": 5}}`).
+		Expect(t).
+		Status(http.StatusOK).
+		Header("Content-Type", "application/pdf").
+		End()
+
+	bb, err := ioutil.ReadAll(r.Response.Body)
+	h.noError(err)
+	h.a.True(bytes.HasPrefix(bb, []byte("%PDF-")))
+	h.a.Contains(string(bb), "/Count 2")
+}
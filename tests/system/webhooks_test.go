@@ -0,0 +1,322 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/pkg/eventbus"
+	"github.com/cortezaproject/corteza-server/pkg/id"
+	"github.com/cortezaproject/corteza-server/store"
+	"github.com/cortezaproject/corteza-server/system/service"
+	"github.com/cortezaproject/corteza-server/system/service/event"
+	"github.com/cortezaproject/corteza-server/system/types"
+	"github.com/cortezaproject/corteza-server/tests/helpers"
+	jsonpath "github.com/steinfletcher/apitest-jsonpath"
+)
+
+type (
+	webhookTestRequest struct {
+		header http.Header
+		body   []byte
+	}
+)
+
+func (h helper) clearWebhooks() {
+	h.noError(store.TruncateWebhookDeliveries(context.Background(), service.DefaultStore))
+	h.noError(store.TruncateWebhooks(context.Background(), service.DefaultStore))
+}
+
+func (h helper) repoMakeWebhook(url string) *types.Webhook {
+	res := &types.Webhook{
+		ID:           id.Next(),
+		URL:          url,
+		ResourceType: "system:user",
+		EventTypes:   types.WebhookEventTypeSet{"afterCreate"},
+		Enabled:      true,
+		CreatedAt:    time.Now(),
+	}
+
+	h.noError(store.CreateWebhook(context.Background(), service.DefaultStore, res))
+
+	return res
+}
+
+func (h helper) repoMakeWebhookDelivery(wh *types.Webhook, status types.WebhookDeliveryStatus) *types.WebhookDelivery {
+	res := &types.WebhookDelivery{
+		ID:           id.Next(),
+		WebhookID:    wh.ID,
+		ResourceType: wh.ResourceType,
+		EventType:    "afterCreate",
+		Payload:      types.WebhookPayload(`{"test":true}`),
+		Status:       status,
+		Attempts:     3,
+		CreatedAt:    time.Now(),
+	}
+
+	h.noError(store.CreateWebhookDelivery(context.Background(), service.DefaultStore, res))
+
+	return res
+}
+
+// storeWebhookOwner stores current user and its membership
+// so that webhook deliveries can check the owner's permissions
+func (h helper) storeWebhookOwner() {
+	h.noError(store.CreateUser(context.Background(), service.DefaultStore, &types.User{
+		ID:        h.cUser.ID,
+		Email:     fmt.Sprintf("%d@webhook.test", h.cUser.ID),
+		CreatedAt: time.Now(),
+	}))
+
+	h.noError(store.CreateRoleMember(context.Background(), service.DefaultStore, &types.RoleMember{
+		UserID: h.cUser.ID,
+		RoleID: h.roleID,
+	}))
+}
+
+// webhookTestServer starts HTTP server that captures all incoming requests
+func webhookTestServer(status int) (*httptest.Server, chan webhookTestRequest) {
+	var (
+		reqs = make(chan webhookTestRequest, 10)
+	)
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		reqs <- webhookTestRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	})), reqs
+}
+
+func (h helper) waitWebhookRequest(reqs chan webhookTestRequest) webhookTestRequest {
+	select {
+	case req := <-reqs:
+		return req
+	case <-time.After(5 * time.Second):
+		h.t.Fatal("webhook request not received")
+	}
+
+	return webhookTestRequest{}
+}
+
+func TestWebhookList(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+
+	h.repoMakeWebhook("https://example.tld/hook")
+	h.repoMakeWebhook("https://example.tld/hook")
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "webhooks.search")
+	helpers.AllowMe(h, types.WebhookRbacResource(0), "read")
+
+	h.apiInit().
+		Get("/webhooks/").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 2)).
+		End()
+}
+
+func TestWebhookCreateForbidden(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+
+	h.apiInit().
+		Post("/webhooks").
+		Header("Accept", "application/json").
+		FormData("url", "https://example.tld/hook").
+		FormData("resourceType", "system:user").
+		FormData("eventTypes", `["afterCreate"]`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("webhook.errors.notAllowedToCreate")).
+		End()
+}
+
+func TestWebhookCreateInvalidURL(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "webhook.create")
+
+	h.apiInit().
+		Post("/webhooks").
+		Header("Accept", "application/json").
+		FormData("url", "ftp://example.tld/hook").
+		FormData("resourceType", "system:user").
+		FormData("eventTypes", `["afterCreate"]`).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("webhook.errors.invalidURL")).
+		End()
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+	h.storeWebhookOwner()
+
+	srv, reqs := webhookTestServer(http.StatusOK)
+	defer srv.Close()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "webhook.create")
+	helpers.AllowMe(h, types.WebhookRbacResource(0), "read", "update", "delete")
+	helpers.AllowMe(h, types.UserRbacResource(0), "read")
+
+	const secret = "s3cr3t"
+
+	h.apiInit().
+		Post("/webhooks").
+		FormData("url", srv.URL).
+		FormData("secret", secret).
+		FormData("resourceType", "system:user").
+		FormData("eventTypes", `["afterCreate"]`).
+		FormData("enabled", "true").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.hasSecret`, true)).
+		Assert(jsonpath.NotPresent(`$.response.secret`)).
+		End()
+
+	set, _, err := store.SearchWebhooks(context.Background(), service.DefaultStore, types.WebhookFilter{})
+	h.noError(err)
+	h.a.Len(set, 1)
+	wh := set[0]
+
+	defer func() {
+		// make sure webhook does not linger on the eventbus
+		h.noError(service.DefaultWebhook.DeleteByID(h.secCtx(), wh.ID))
+	}()
+
+	u := &types.User{ID: id.Next(), Handle: "webhook-test"}
+	h.noError(eventbus.Service().WaitFor(context.Background(), event.UserAfterCreate(u, nil)))
+
+	req := h.waitWebhookRequest(reqs)
+	h.a.Equal(service.WebhookSignature(secret, req.body), req.header.Get(service.WebhookHeaderSignature))
+	h.a.Equal(fmt.Sprintf("%d", wh.ID), req.header.Get(service.WebhookHeaderWebhookID))
+	h.a.Equal("afterCreate", req.header.Get(service.WebhookHeaderEvent))
+	h.a.Contains(string(req.body), "webhook-test")
+
+	var d *types.WebhookDelivery
+	h.a.Eventually(func() bool {
+		dd, _, err := store.SearchWebhookDeliveries(context.Background(), service.DefaultStore, types.WebhookDeliveryFilter{WebhookID: wh.ID})
+		h.noError(err)
+		if len(dd) != 1 {
+			return false
+		}
+
+		d = dd[0]
+		return d.Status == types.WebhookDeliveryStatusDelivered
+	}, 5*time.Second, 10*time.Millisecond)
+
+	h.a.Equal(uint(1), d.Attempts)
+	h.a.Equal(http.StatusOK, d.ResponseStatus)
+	h.a.Nil(d.NextAttemptAt)
+
+	h.apiInit().
+		Get(fmt.Sprintf("/webhooks/%d/deliveries", wh.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Len(`$.response.set`, 1)).
+		Assert(jsonpath.Equal(`$.response.set[0].status`, "delivered")).
+		End()
+}
+
+func TestWebhookCreateForeignOwner(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "webhook.create")
+
+	h.apiInit().
+		Post("/webhooks").
+		Header("Accept", "application/json").
+		FormData("url", "https://example.tld/hook").
+		FormData("resourceType", "system:user").
+		FormData("eventTypes", `["afterCreate"]`).
+		FormData("ownedBy", fmt.Sprintf("%d", id.Next())).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertError("webhook.errors.invalidOwner")).
+		End()
+}
+
+func TestWebhookDeliveryOwnerNotAllowedToRead(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+	h.storeWebhookOwner()
+
+	srv, reqs := webhookTestServer(http.StatusOK)
+	defer srv.Close()
+
+	helpers.AllowMe(h, types.ComponentRbacResource(), "webhook.create")
+	helpers.AllowMe(h, types.WebhookRbacResource(0), "read", "update", "delete")
+	helpers.DenyMe(h, types.UserRbacResource(0), "read")
+
+	h.apiInit().
+		Post("/webhooks").
+		FormData("url", srv.URL).
+		FormData("resourceType", "system:user").
+		FormData("eventTypes", `["afterCreate"]`).
+		FormData("enabled", "true").
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		End()
+
+	set, _, err := store.SearchWebhooks(context.Background(), service.DefaultStore, types.WebhookFilter{})
+	h.noError(err)
+	h.a.Len(set, 1)
+	wh := set[0]
+
+	defer func() {
+		h.noError(service.DefaultWebhook.DeleteByID(h.secCtx(), wh.ID))
+	}()
+
+	u := &types.User{ID: id.Next(), Handle: "webhook-test"}
+	h.noError(eventbus.Service().WaitFor(context.Background(), event.UserAfterCreate(u, nil)))
+
+	select {
+	case <-reqs:
+		h.t.Fatal("webhook request should not be sent")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	dd, _, err := store.SearchWebhookDeliveries(context.Background(), service.DefaultStore, types.WebhookDeliveryFilter{WebhookID: wh.ID})
+	h.noError(err)
+	h.a.Empty(dd)
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	h := newHelper(t)
+	h.clearWebhooks()
+
+	srv, reqs := webhookTestServer(http.StatusInternalServerError)
+	defer srv.Close()
+
+	wh := h.repoMakeWebhook(srv.URL)
+	d := h.repoMakeWebhookDelivery(wh, types.WebhookDeliveryStatusFailed)
+
+	helpers.AllowMe(h, types.WebhookRbacResource(0), "read", "update")
+
+	h.apiInit().
+		Post(fmt.Sprintf("/webhooks/%d/deliveries/%d/redeliver", wh.ID, d.ID)).
+		Expect(t).
+		Status(http.StatusOK).
+		Assert(helpers.AssertNoErrors).
+		Assert(jsonpath.Equal(`$.response.status`, "pending")).
+		Assert(jsonpath.Equal(`$.response.attempts`, float64(1))).
+		Assert(jsonpath.Equal(`$.response.responseStatus`, float64(http.StatusInternalServerError))).
+		Assert(jsonpath.Present(`$.response.nextAttemptAt`)).
+		End()
+
+	req := h.waitWebhookRequest(reqs)
+	h.a.Equal(`{"test":true}`, string(req.body))
+	h.a.Empty(req.header.Get(service.WebhookHeaderSignature))
+}
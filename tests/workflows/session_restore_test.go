@@ -0,0 +1,164 @@
+package workflows
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cortezaproject/corteza-server/automation/service"
+	"github.com/cortezaproject/corteza-server/automation/types"
+	"github.com/cortezaproject/corteza-server/pkg/auth"
+	"github.com/cortezaproject/corteza-server/pkg/expr"
+	"github.com/cortezaproject/corteza-server/pkg/filter"
+	"github.com/cortezaproject/corteza-server/pkg/wfexec"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_session_restore(t *testing.T) {
+	var (
+		ctx = bypassRBAC(context.Background())
+		req = require.New(t)
+
+		ses *types.Session
+	)
+
+	req.NoError(defStore.TruncateAutomationSessions(ctx))
+
+	loadNewScenario(ctx, t)
+
+	wf, err := defStore.LookupAutomationWorkflowByHandle(ctx, "prompted")
+	req.NoError(err)
+
+	// deferred workflow, returns right away
+	_, _, err = service.DefaultWorkflow.Exec(ctx, wf.ID, types.WorkflowExecParams{})
+	req.NoError(err)
+
+	lookup := func(status types.SessionStatus) func() bool {
+		return func() bool {
+			set, _, err := defStore.SearchAutomationSessions(ctx, types.SessionFilter{
+				WorkflowID: []uint64{wf.ID},
+				Completed:  filter.StateInclusive,
+			})
+			req.NoError(err)
+			if len(set) != 1 {
+				return false
+			}
+
+			ses = set[0]
+			return ses.Status == status
+		}
+	}
+
+	req.Eventually(lookup(types.SessionPrompted), time.Second*5, time.Millisecond*10)
+
+	req.NotNil(ses.State)
+	req.NotNil(ses.State.Session)
+	req.Len(ses.State.Session.Prompted, 1)
+	req.Equal(auth.GetIdentityFromContext(ctx).Identity(), ses.State.Invoker.ID)
+
+	stateID := ses.State.Session.Prompted[0].State.StateID
+
+	// simulate restart; sessions are restored from the store
+	req.NoError(service.Activate(ctx))
+
+	input, err := expr.NewVars(map[string]interface{}{"answer": true})
+	req.NoError(err)
+
+	req.NoError(service.DefaultSession.Resume(ses.ID, stateID, auth.GetIdentityFromContext(ctx), input))
+
+	req.Eventually(lookup(types.SessionCompleted), time.Second*5, time.Millisecond*10)
+	req.Empty(ses.Error)
+	req.Nil(ses.State)
+}
+
+func Test_session_restore_in_loop(t *testing.T) {
+	var (
+		ctx = bypassRBAC(context.Background())
+		req = require.New(t)
+
+		ses *types.Session
+	)
+
+	req.NoError(defStore.TruncateAutomationSessions(ctx))
+
+	loadNewScenario(ctx, t)
+
+	wf, err := defStore.LookupAutomationWorkflowByHandle(ctx, "prompted-in-loop")
+	req.NoError(err)
+
+	_, _, err = service.DefaultWorkflow.Exec(ctx, wf.ID, types.WorkflowExecParams{})
+	req.NoError(err)
+
+	req.Eventually(func() bool {
+		set, _, err := defStore.SearchAutomationSessions(ctx, types.SessionFilter{WorkflowID: []uint64{wf.ID}})
+		req.NoError(err)
+		if len(set) != 1 {
+			return false
+		}
+
+		ses = set[0]
+		return ses.Status == types.SessionPrompted
+	}, time.Second*5, time.Millisecond*10)
+
+	// prompt inside a loop can not be persisted,
+	// stored state explains why
+	req.NotNil(ses.State)
+	req.Nil(ses.State.Session)
+	req.Contains(ses.State.Error, wfexec.ErrSuspendedInLoop.Error())
+}
+
+func Test_session_resume_on_other_node(t *testing.T) {
+	var (
+		ctx = bypassRBAC(context.Background())
+		req = require.New(t)
+
+		ses *types.Session
+	)
+
+	req.NoError(defStore.TruncateAutomationSessions(ctx))
+
+	loadScenarioWithName(ctx, t, "session_restore")
+
+	wf, err := defStore.LookupAutomationWorkflowByHandle(ctx, "prompted")
+	req.NoError(err)
+
+	_, _, err = service.DefaultWorkflow.Exec(ctx, wf.ID, types.WorkflowExecParams{})
+	req.NoError(err)
+
+	lookup := func(status types.SessionStatus) func() bool {
+		return func() bool {
+			set, _, err := defStore.SearchAutomationSessions(ctx, types.SessionFilter{
+				WorkflowID: []uint64{wf.ID},
+				Completed:  filter.StateInclusive,
+			})
+			req.NoError(err)
+			if len(set) != 1 {
+				return false
+			}
+
+			ses = set[0]
+			return ses.Status == status
+		}
+	}
+
+	req.Eventually(lookup(types.SessionPrompted), time.Second*5, time.Millisecond*10)
+
+	// session was taken over by another node that moved on to a new
+	// state; copy in memory is stale and session is loaded from the store
+	stateID := ses.State.Session.Prompted[0].State.StateID + 1
+	ses.State.Session.Prompted[0].State.StateID = stateID
+	ses.State.Node = "other-node"
+	req.NoError(defStore.UpsertAutomationSession(ctx, ses))
+
+	input, err := expr.NewVars(map[string]interface{}{"answer": true})
+	req.NoError(err)
+
+	req.NoError(service.DefaultSession.Resume(ses.ID, stateID, auth.GetIdentityFromContext(ctx), input))
+
+	req.Eventually(lookup(types.SessionCompleted), time.Second*5, time.Millisecond*10)
+	req.Empty(ses.Error)
+	req.Nil(ses.State)
+
+	// resuming session that is no longer waiting for input
+	req.Error(service.DefaultSession.Resume(ses.ID, stateID, auth.GetIdentityFromContext(ctx), input))
+}